@@ -0,0 +1,85 @@
+// Beads import command for migrating repositories that still carry a
+// .beads/issues.jsonl export.
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sentiolabs/arc/internal/beads"
+	"github.com/spf13/cobra"
+)
+
+// importBeadsCmd imports a beads export into the current project.
+var importBeadsCmd = &cobra.Command{
+	Use:   "beads [path]",
+	Short: "Import issues from a beads repository",
+	Long: `Import issues from a beads export into the current project (or --project).
+
+path is a repository containing ` + beads.DefaultPath + ` or the JSONL file
+itself; it defaults to the current directory.
+
+Statuses, priorities, issue types, labels, comments, and dependencies
+(blocks, parent-child, related, discovered-from) are carried over. Each
+imported issue keeps its beads ID as its external ref, and issues imported
+on an earlier run are skipped, so the import can be re-run safely as the
+beads repository changes.
+
+Examples:
+  arc import beads
+  arc import beads ~/src/legacy-repo --project arc-k3x9f2`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := "."
+		if len(args) == 1 {
+			path = args[0]
+		}
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			path = filepath.Join(path, beads.DefaultPath)
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		projID, err := getProjectID()
+		if err != nil {
+			return err
+		}
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+
+		result, err := c.ImportBeads(projID, f)
+		if err != nil {
+			return err
+		}
+
+		if outputJSON {
+			outputResult(result)
+			return nil
+		}
+
+		fmt.Printf("Imported %d issues from %s\n", result.Created, path)
+		if result.Skipped > 0 {
+			fmt.Printf("  Skipped:      %d (already imported)\n", result.Skipped)
+		}
+		if result.Ignored > 0 {
+			fmt.Printf("  Ignored:      %d (deleted in beads)\n", result.Ignored)
+		}
+		fmt.Printf("  Dependencies: %d\n", result.Dependencies)
+		fmt.Printf("  Comments:     %d\n", result.Comments)
+		for _, w := range result.Warnings {
+			fmt.Printf("  warning: %s\n", w)
+		}
+		return nil
+	},
+}
+
+func init() {
+	importCmd.AddCommand(importBeadsCmd)
+}
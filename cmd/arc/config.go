@@ -13,6 +13,7 @@ import (
 	"strings"
 
 	cfgpkg "github.com/sentiolabs/arc/internal/config"
+	"github.com/sentiolabs/arc/internal/storage/postgres"
 	"github.com/spf13/cobra"
 )
 
@@ -25,12 +26,16 @@ const dottedKeyParts = 2
 // Config key names, used as the canonical dotted identifiers throughout the
 // config commands.
 const (
-	cliServerKey      = "cli.server"
-	updatesChannelKey = "updates.channel"
-	plansDirKey       = "plans.dir"
-	plansTypeKey      = "plans.type"
-	serverPortKey     = "server.port"
-	serverDBPathKey   = "server.db_path"
+	cliServerKey         = "cli.server"
+	cliTokenKey          = "cli.token"
+	updatesChannelKey    = "updates.channel"
+	plansDirKey          = "plans.dir"
+	plansTypeKey         = "plans.type"
+	serverPortKey        = "server.port"
+	serverDBPathKey      = "server.db_path"
+	serverRequireAuthKey = "server.require_auth"
+	serverDBDriverKey    = "server.db_driver"
+	serverDSNKey         = "server.dsn"
 )
 
 // cmdEdit is the cobra Use string for the "config edit" sub-command.
@@ -47,10 +52,14 @@ var legacyAliases = map[string]string{
 // recognizedKeys is the canonical list of all valid config key names.
 var recognizedKeys = []string{
 	cliServerKey,
+	cliTokenKey,
 	plansDirKey,
 	plansTypeKey,
 	serverPortKey,
 	serverDBPathKey,
+	serverDBDriverKey,
+	serverDSNKey,
+	serverRequireAuthKey,
 	updatesChannelKey,
 }
 
@@ -148,14 +157,18 @@ func runConfigList(cmd *cobra.Command, args []string) error {
 		if restartSet[key] {
 			tag = "   (requires restart)"
 		}
-		fmt.Printf("  %-10s = %s%s\n", label, value, tag)
+		fmt.Printf("  %-12s = %s%s\n", label, value, tag)
 	}
 	fmt.Println("[cli]")
 	printRow(cliServerKey, cfg.CLI.Server)
+	printRow(cliTokenKey, redactToken(cfg.CLI.Token))
 	fmt.Println()
 	fmt.Println("[server]")
 	printRow(serverPortKey, strconv.Itoa(cfg.Server.Port))
 	printRow(serverDBPathKey, cfg.Server.DBPath)
+	printRow(serverDBDriverKey, cfg.Server.Driver())
+	printRow(serverDSNKey, postgres.RedactDSN(cfg.Server.DSN))
+	printRow(serverRequireAuthKey, strconv.FormatBool(cfg.Server.RequireAuth))
 	fmt.Println()
 	fmt.Println("[updates]")
 	printRow(updatesChannelKey, cfg.Updates.Channel)
@@ -239,7 +252,11 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 	if err := saveConfig(cfg); err != nil {
 		return fmt.Errorf("save config: %w", err)
 	}
-	fmt.Printf("Set %s = %s\n", key, args[1])
+	value := args[1]
+	if key == serverDSNKey {
+		value = postgres.RedactDSN(value)
+	}
+	fmt.Printf("Set %s = %s\n", key, value)
 	return nil
 }
 
@@ -368,11 +385,25 @@ func levenshtein(a, b string) int {
 	return prev[lb]
 }
 
+// redactToken hides all but the last few characters of an API token.
+func redactToken(token string) string {
+	const visible = 4
+	if token == "" {
+		return ""
+	}
+	if len(token) <= visible {
+		return "****"
+	}
+	return "****" + token[len(token)-visible:]
+}
+
 // getKey returns the string form of the config field for key.
 func getKey(cfg *cfgpkg.Config, key string) string {
 	switch key {
 	case cliServerKey:
 		return cfg.CLI.Server
+	case cliTokenKey:
+		return cfg.CLI.Token
 	case plansDirKey:
 		return cfg.Plans.Dir
 	case plansTypeKey:
@@ -381,6 +412,12 @@ func getKey(cfg *cfgpkg.Config, key string) string {
 		return strconv.Itoa(cfg.Server.Port)
 	case serverDBPathKey:
 		return cfg.Server.DBPath
+	case serverDBDriverKey:
+		return cfg.Server.Driver()
+	case serverDSNKey:
+		return cfg.Server.DSN
+	case serverRequireAuthKey:
+		return strconv.FormatBool(cfg.Server.RequireAuth)
 	case updatesChannelKey:
 		return cfg.Updates.Channel
 	}
@@ -393,6 +430,8 @@ func setKey(cfg *cfgpkg.Config, key, value string) error {
 	switch key {
 	case cliServerKey:
 		cfg.CLI.Server = value
+	case cliTokenKey:
+		cfg.CLI.Token = value
 	case plansDirKey:
 		cfg.Plans.Dir = value
 	case plansTypeKey:
@@ -405,6 +444,16 @@ func setKey(cfg *cfgpkg.Config, key, value string) error {
 		cfg.Server.Port = n
 	case serverDBPathKey:
 		cfg.Server.DBPath = value
+	case serverDBDriverKey:
+		cfg.Server.DBDriver = value
+	case serverDSNKey:
+		cfg.Server.DSN = value
+	case serverRequireAuthKey:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.New("server.require_auth: must be true or false")
+		}
+		cfg.Server.RequireAuth = b
 	case updatesChannelKey:
 		cfg.Updates.Channel = value
 	}
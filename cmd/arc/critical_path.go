@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/sentiolabs/arc/internal/types"
+	"github.com/spf13/cobra"
+)
+
+// pathCmd shows the chain of blockers gating an issue.
+var pathCmd = &cobra.Command{
+	Use:   "path <epic-id>",
+	Short: "Show the critical path to finishing an issue",
+	Long: `Show the longest chain of open issues that has to be finished, in order,
+before the given issue (usually an epic) can be. An issue waits on what
+blocks it, and a parent waits on its children.
+
+Also names the bottleneck: the issue gating this one that the most open
+work waits on.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+
+		cp, err := c.GetCriticalPath(args[0])
+		if err != nil {
+			return err
+		}
+
+		if outputJSON {
+			outputResult(cp)
+			return nil
+		}
+
+		if len(cp.Path) == 0 {
+			fmt.Printf("%s is closed; nothing gates it\n", cp.RootID)
+			return nil
+		}
+		fmt.Printf("Critical path to %s (%d issues):\n", cp.RootID, len(cp.Path))
+		for i, issue := range cp.Path {
+			fmt.Printf("%3d. %s\n", i+1, formatAnalysisIssue(issue))
+		}
+		if b := cp.Bottleneck; b != nil {
+			fmt.Printf("\nBottleneck: %s (%d waiting, %d ready when it closes)\n",
+				b.IssueID, len(b.Waiting), len(b.Ready))
+		}
+		return nil
+	},
+}
+
+// unblocksCmd shows the work waiting on an issue.
+var unblocksCmd = &cobra.Command{
+	Use:   "unblocks <issue-id>",
+	Short: "Show what closing an issue would unblock",
+	Long: `List the open issues that become ready as soon as the given issue is
+closed, followed by every issue that transitively waits on it through
+blocks dependencies and parent-child links.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+
+		u, err := c.GetUnblocks(args[0])
+		if err != nil {
+			return err
+		}
+
+		if outputJSON {
+			outputResult(u)
+			return nil
+		}
+
+		if len(u.Waiting) == 0 {
+			fmt.Printf("Nothing waits on %s\n", u.IssueID)
+			return nil
+		}
+		fmt.Printf("Closing %s makes %d issue(s) ready:\n", u.IssueID, len(u.Ready))
+		for _, issue := range u.Ready {
+			fmt.Println("  " + formatAnalysisIssue(issue))
+		}
+		fmt.Printf("\n%d issue(s) wait on it in total:\n", len(u.Waiting))
+		for _, issue := range u.Waiting {
+			fmt.Println("  " + formatAnalysisIssue(issue))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pathCmd)
+	rootCmd.AddCommand(unblocksCmd)
+}
+
+// formatAnalysisIssue returns the issue line used by path and unblocks.
+func formatAnalysisIssue(issue *types.Issue) string {
+	return formatIssue(issue.ID, string(issue.Status), string(issue.IssueType), issue.Priority, issue.Title, nil)
+}
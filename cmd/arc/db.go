@@ -1,12 +1,17 @@
 // Database management commands for the arc CLI.
-// Provides backup and maintenance operations for the SQLite database.
+// Provides backup and maintenance operations for the SQLite database, and
+// migration of its contents to PostgreSQL.
 package main
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 
+	"github.com/sentiolabs/arc/internal/config"
 	"github.com/sentiolabs/arc/internal/project"
+	"github.com/sentiolabs/arc/internal/storage/postgres"
 	"github.com/sentiolabs/arc/internal/storage/sqlite"
 	"github.com/spf13/cobra"
 )
@@ -52,10 +57,140 @@ Example:
 	},
 }
 
+// dbMigrateToPostgresCmd copies a SQLite database into an empty PostgreSQL database.
+var dbMigrateToPostgresCmd = &cobra.Command{
+	Use:   "migrate-to-postgres",
+	Short: "Copy the SQLite database into PostgreSQL",
+	Long: `Copy every project, issue, plan, AI session and event from the SQLite
+database into an empty PostgreSQL database, keeping all IDs and timestamps.
+The copy runs in a single transaction, so a failure leaves the target empty.
+
+The target DSN defaults to server.dsn. Stop the server first so nothing is
+written to SQLite during the copy, then switch the server over:
+
+  arc server stop
+  arc config set server.dsn postgres://arc@localhost/arc
+  arc db migrate-to-postgres
+  arc config set server.db_driver postgres
+  arc server start`,
+	RunE: runDBMigrateToPostgres,
+}
+
+func runDBMigrateToPostgres(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	dbPath, _ := cmd.Flags().GetString("db")
+	if dbPath == "" {
+		dbPath = cfg.Server.ResolvedDBPath()
+	}
+	dsn, _ := cmd.Flags().GetString("dsn")
+	if dsn == "" {
+		dsn = cfg.Server.DSN
+	}
+	if dsn == "" {
+		return errors.New("no PostgreSQL DSN: pass --dsn or set server.dsn")
+	}
+	if _, err := os.Stat(dbPath); err != nil {
+		return fmt.Errorf("open SQLite database: %w", err)
+	}
+
+	src, err := sqlite.New(dbPath)
+	if err != nil {
+		return fmt.Errorf("open SQLite database: %w", err)
+	}
+	defer src.Close()
+
+	dst, err := postgres.New(dsn)
+	if err != nil {
+		return fmt.Errorf("open PostgreSQL database: %w", err)
+	}
+	defer dst.Close()
+
+	counts, err := dst.CopyFrom(cmd.Context(), src.DB())
+	if err != nil {
+		return fmt.Errorf("migrate failed: %w", err)
+	}
+
+	if outputJSON {
+		outputResult(counts)
+		return nil
+	}
+	fmt.Printf("Copied %s to %s\n", dbPath, dst.Path())
+	for _, c := range counts {
+		fmt.Printf("  %-20s %d\n", c.Table, c.Rows)
+	}
+	return nil
+}
+
+// dbReindexCmd rebuilds the SQLite full-text search index.
+var dbReindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "Rebuild the full-text search index",
+	Long: `Rebuild the SQLite full-text search index from the issues, comments and
+labels tables. The index is kept current on every write and rebuilt
+automatically when its format changes, so this is only needed to recover
+from a corrupted index or one edited outside arc.
+
+The rebuild runs in a single transaction; writes from a running server wait
+for it to finish. PostgreSQL maintains its search index itself and needs
+no rebuild.`,
+	RunE: runDBReindex,
+}
+
+func runDBReindex(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	dbPath, _ := cmd.Flags().GetString("db")
+	if dbPath == "" {
+		if cfg.Server.DBDriver == config.DBDriverPostgres {
+			fmt.Println("PostgreSQL keeps its search index current; nothing to rebuild.")
+			return nil
+		}
+		dbPath = cfg.Server.ResolvedDBPath()
+	}
+	if _, err := os.Stat(dbPath); err != nil {
+		return fmt.Errorf("open SQLite database: %w", err)
+	}
+
+	store, err := sqlite.New(dbPath)
+	if err != nil {
+		return fmt.Errorf("open SQLite database: %w", err)
+	}
+	defer store.Close()
+
+	progress := func(done, total int) {
+		fmt.Printf("\rIndexed %d/%d issues", done, total)
+	}
+	if outputJSON {
+		progress = nil
+	}
+	indexed, err := store.RebuildSearchIndex(cmd.Context(), progress)
+	if err != nil {
+		return fmt.Errorf("reindex failed: %w", err)
+	}
+
+	if outputJSON {
+		outputResult(map[string]any{"path": dbPath, "issues": indexed})
+		return nil
+	}
+	if indexed > 0 {
+		fmt.Println()
+	}
+	fmt.Printf("Rebuilt search index for %d issues in %s\n", indexed, dbPath)
+	return nil
+}
+
 // init registers the db command tree with the root command.
 func init() {
 	dbBackupCmd.Flags().String("db", "", "Database path (default: ~/.arc/data.db)")
-	dbCmd.AddCommand(dbBackupCmd)
+	dbMigrateToPostgresCmd.Flags().String("db", "", "SQLite database path (default: server.db_path)")
+	dbMigrateToPostgresCmd.Flags().String("dsn", "", "PostgreSQL connection string (default: server.dsn)")
+	dbReindexCmd.Flags().String("db", "", "SQLite database path (default: server.db_path)")
+	dbCmd.AddCommand(dbBackupCmd, dbMigrateToPostgresCmd, dbReindexCmd)
 	rootCmd.AddCommand(dbCmd)
 }
 
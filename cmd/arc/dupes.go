@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/fatih/color"
+	"github.com/sentiolabs/arc/internal/dupes"
+	"github.com/sentiolabs/arc/internal/types"
+	"github.com/spf13/cobra"
+)
+
+// dupesCmd lists groups of open issues that look like duplicates.
+var dupesCmd = &cobra.Command{
+	Use:   "dupes",
+	Short: "Find open issues that look like duplicates",
+	Long: `Group the project's open issues whose titles look alike, most similar
+group first, to help clean up issues filed more than once. Within each group
+the oldest issue is listed first.
+
+Similarity runs from 0 to 1, where 1 is an identical title. Raise
+--threshold to see only the closest matches:
+
+  arc dupes
+  arc dupes --threshold 0.4`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		threshold, _ := cmd.Flags().GetFloat64("threshold")
+		if threshold <= 0 || threshold > 1 {
+			return fmt.Errorf("--threshold must be above 0 and at most 1, got %v", threshold)
+		}
+
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+		projID, err := getProjectID()
+		if err != nil {
+			return err
+		}
+
+		clusters, err := c.Duplicates(projID, threshold)
+		if err != nil {
+			return err
+		}
+
+		if outputJSON {
+			outputResult(clusters)
+			return nil
+		}
+
+		if len(clusters) == 0 {
+			fmt.Println("No likely duplicates")
+			return nil
+		}
+		for i, cluster := range clusters {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Println(color.New(color.Bold).Sprintf("%d issues, %.0f%% similar", len(cluster.Issues), cluster.Score*100))
+			for _, issue := range cluster.Issues {
+				fmt.Println("  " + formatIssue(issue.ID, string(issue.Status), string(issue.IssueType),
+					issue.Priority, issue.Title, issue.Labels))
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	dupesCmd.Flags().Float64("threshold", dupes.DefaultThreshold, "Minimum similarity (0-1) to group issues")
+	rootCmd.AddCommand(dupesCmd)
+}
+
+// printDuplicateMatches lists likely duplicates of an issue with their
+// similarity, one per line.
+func printDuplicateMatches(w io.Writer, matches []*types.DuplicateMatch) {
+	for _, m := range matches {
+		issue := m.Issue
+		_, _ = fmt.Fprintf(w, "  %s  %s\n", formatIssue(issue.ID, string(issue.Status), string(issue.IssueType),
+			issue.Priority, issue.Title, issue.Labels), color.New(color.Faint).Sprintf("(%.0f%% similar)", m.Score*100))
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/sentiolabs/arc/internal/client"
+	"github.com/sentiolabs/arc/internal/depgraph"
+	"github.com/spf13/cobra"
+)
+
+// graphCmd exports the dependency graph.
+var graphCmd = &cobra.Command{
+	Use:   "graph [epic-id]",
+	Short: "Export the dependency graph",
+	Long: `Export the current project's dependency graph, or with an issue ID the
+part of it reachable from that issue, such as an epic with its children and
+their blockers.
+
+Formats:
+  dot       Graphviz DOT, e.g. arc graph | dot -Tsvg > graph.svg
+  mermaid   A fenced Mermaid flowchart to paste into markdown
+  json      Nodes and edges
+
+Nodes are colored by status and shaped by type: epics as 3D boxes (DOT) or
+subroutines (Mermaid), features as ellipses or stadiums, bugs as octagons or
+hexagons, chores as notes or flags. Arrows point from an issue to what it
+depends on: blocks in red or plain, parent-child bold or thick, related
+dotted without a head, discovered-from and duplicates dashed.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		if outputJSON {
+			format = depgraph.FormatJSON
+		}
+		switch format {
+		case depgraph.FormatDOT, depgraph.FormatMermaid, depgraph.FormatJSON:
+		default:
+			return fmt.Errorf("invalid format %q: use dot, mermaid or json", format)
+		}
+
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+
+		wsID, err := getProjectID()
+		if err != nil {
+			return err
+		}
+
+		opts := client.GraphOptions{}
+		if len(args) == 1 {
+			opts.Root = args[0]
+		}
+		opts.HideClosed, _ = cmd.Flags().GetBool("hide-closed")
+		opts.Depth, _ = cmd.Flags().GetInt("depth")
+
+		g, err := c.GetGraph(wsID, opts)
+		if err != nil {
+			return err
+		}
+
+		switch format {
+		case depgraph.FormatJSON:
+			outputResult(g)
+		case depgraph.FormatDOT:
+			fmt.Print(depgraph.DOT(g))
+		case depgraph.FormatMermaid:
+			fmt.Print("```mermaid\n" + depgraph.Mermaid(g) + "```\n")
+		}
+		return nil
+	},
+}
+
+func init() {
+	graphCmd.Flags().StringP("format", "f", depgraph.FormatDOT, "Output format (dot, mermaid, json)")
+	graphCmd.Flags().Bool("hide-closed", false, "Leave out closed issues")
+	graphCmd.Flags().Int("depth", 0, "With an issue ID, the most dependency hops to follow (0 = no limit)")
+	rootCmd.AddCommand(graphCmd)
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/sentiolabs/arc/internal/history"
+	"github.com/sentiolabs/arc/internal/types"
+	"github.com/spf13/cobra"
+)
+
+// historyValueMaxRunes caps single-line values in the timeline.
+const historyValueMaxRunes = 60
+
+// historyTimeLayouts are the --at formats accepted besides RFC 3339,
+// interpreted in local time.
+var historyTimeLayouts = []string{time.DateTime, "2006-01-02 15:04", time.DateOnly}
+
+// historyCmd prints an issue's change timeline.
+var historyCmd = &cobra.Command{
+	Use:   "history <id>",
+	Short: "Show how an issue changed over time",
+	Long: `Print an issue's audit trail as a timeline, one entry per change, with
+the value before and after. Description changes are shown as a line diff.
+
+With --at the issue is first shown as it was at that moment, followed by
+the changes that led up to it. --at takes an RFC 3339 timestamp or a local
+"YYYY-MM-DD[ HH:MM[:SS]]".`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+
+		var at time.Time
+		if raw, _ := cmd.Flags().GetString("at"); raw != "" {
+			if at, err = parseHistoryTime(raw); err != nil {
+				return err
+			}
+		}
+
+		h, err := c.GetIssueHistory(args[0], at)
+		if err != nil {
+			return err
+		}
+
+		if outputJSON {
+			outputResult(h)
+			return nil
+		}
+
+		if !at.IsZero() {
+			printIssueSnapshot(os.Stdout, h.Issue, h.At)
+		}
+		for _, e := range h.Events {
+			fmt.Println(formatHistoryEvent(e))
+		}
+		return nil
+	},
+}
+
+func init() {
+	historyCmd.Flags().String("at", "", "Show the issue as it was at this time")
+	rootCmd.AddCommand(historyCmd)
+}
+
+// parseHistoryTime parses an --at value.
+func parseHistoryTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range historyTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			if layout == time.DateOnly {
+				// A bare date means the end of that day.
+				t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use RFC 3339 or YYYY-MM-DD[ HH:MM[:SS]]", s)
+}
+
+// printIssueSnapshot writes the header shown for an issue rebuilt at a
+// point in time.
+func printIssueSnapshot(w io.Writer, issue *types.Issue, at time.Time) {
+	fmt.Fprintf(w, "%s as of %s\n\n", issue.ID, at.Local().Format(time.DateTime))
+	fmt.Fprintf(w, "Title:    %s\n", issue.Title)
+	fmt.Fprintf(w, "Status:   %s\n", issue.Status)
+	fmt.Fprintf(w, "Priority: P%d\n", issue.Priority)
+	fmt.Fprintf(w, "Type:     %s\n", issue.IssueType)
+	if issue.Rank > 0 {
+		fmt.Fprintf(w, "Rank:     %d\n", issue.Rank)
+	}
+	if issue.CloseReason != "" {
+		fmt.Fprintf(w, "Closed:   %s\n", issue.CloseReason)
+	}
+	if len(issue.Labels) > 0 {
+		fmt.Fprintf(w, "Labels:   %s\n", strings.Join(issue.Labels, ", "))
+	}
+	if issue.Description != "" {
+		fmt.Fprintf(w, "\nDescription:\n%s\n", issue.Description)
+	}
+	fmt.Fprintln(w)
+}
+
+// formatHistoryEvent renders one audit trail entry. Most entries take one
+// line; description changes are followed by a line diff.
+func formatHistoryEvent(e *types.Event) string {
+	what, detail, diff := string(e.EventType), "", ""
+	switch e.EventType {
+	case types.EventUpdated, types.EventFieldChanged:
+		field, before, hadOld := history.ParseValue(e.OldValue)
+		newField, after, hasNew := history.ParseValue(e.NewValue)
+		if !hadOld && !hasNew {
+			break // recorded before changes carried their values
+		}
+		if !hadOld {
+			field = newField
+		}
+		what = field
+		if e.EventType == types.EventUpdated && field == "description" {
+			diff = formatDescriptionDiff(before, after)
+			break
+		}
+		detail = historyChange(before, hadOld, after, hasNew)
+	case types.EventStatusChanged:
+		detail = historyChange(deref(e.OldValue), e.OldValue != nil, deref(e.NewValue), e.NewValue != nil)
+	case types.EventMoved:
+		from, _ := history.ParseMoveValue(e.OldValue)
+		to, _ := history.ParseMoveValue(e.NewValue)
+		detail = historyChange(from, from != "", to, to != "")
+	case types.EventCreated, types.EventClosed, types.EventCommented:
+		detail = quoteHistoryValue(deref(e.NewValue))
+	case types.EventReopened:
+		if e.OldValue != nil {
+			detail = "was closed: " + quoteHistoryValue(*e.OldValue)
+		}
+	case types.EventUndone:
+		detail = "reversed event #" + deref(e.OldValue)
+	default:
+		detail = deref(e.NewValue)
+		if detail == "" {
+			detail = deref(e.OldValue)
+		}
+	}
+
+	parts := []string{
+		e.CreatedAt.Local().Format("2006-01-02 15:04"),
+		color.New(color.FgCyan).Sprintf("%-16s", what),
+		color.New(color.Faint).Sprintf("%-12s", e.Actor),
+	}
+	if detail != "" {
+		parts = append(parts, detail)
+	}
+	line := strings.TrimRight(strings.Join(parts, "  "), " ")
+	if diff != "" {
+		line += "\n" + diff
+	}
+	return line
+}
+
+// historyChange renders a value changing, marking missing sides as unset.
+func historyChange(before string, hadOld bool, after string, hasNew bool) string {
+	from, to := "(unset)", "(unset)"
+	if hadOld {
+		from = quoteHistoryValue(before)
+	}
+	if hasNew {
+		to = quoteHistoryValue(after)
+	}
+	return from + " → " + to
+}
+
+// quoteHistoryValue shortens a value to one line for the timeline.
+func quoteHistoryValue(s string) string {
+	if s == "" {
+		return `""`
+	}
+	return truncateQuote(s, historyValueMaxRunes)
+}
+
+// formatDescriptionDiff renders a description change as indented diff
+// lines, removed lines in red and added lines in green.
+func formatDescriptionDiff(before, after string) string {
+	removed, added := color.New(color.FgRed), color.New(color.FgGreen)
+	var lines []string
+	for _, l := range history.DiffLines(before, after) {
+		text := strings.TrimRight("    "+string(l.Op)+" "+l.Text, " ")
+		switch l.Op {
+		case history.LineRemoved:
+			text = removed.Sprint(text)
+		case history.LineAdded:
+			text = added.Sprint(text)
+		}
+		lines = append(lines, text)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// deref returns the value of s, or "" when it is nil.
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func TestFormatHistoryEvent(t *testing.T) {
+	origNoColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = origNoColor }()
+
+	ts := time.Date(2026, 3, 14, 9, 30, 0, 0, time.Local)
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		name  string
+		event types.Event
+		want  string
+	}{
+		{
+			name:  "title",
+			event: types.Event{EventType: types.EventUpdated, OldValue: str("title=Draft"), NewValue: str("title=Final")},
+			want:  "2026-03-14 09:30  title             alice         Draft → Final",
+		},
+		{
+			name: "description",
+			event: types.Event{
+				EventType: types.EventUpdated,
+				OldValue:  str("description=Steps:\nrun it"),
+				NewValue:  str("description=Steps:\nbuild it\nrun it"),
+			},
+			want: "2026-03-14 09:30  description       alice\n" +
+				"      Steps:\n" +
+				"    + build it\n" +
+				"      run it",
+		},
+		{
+			name:  "field set",
+			event: types.Event{EventType: types.EventFieldChanged, NewValue: str("team=core")},
+			want:  "2026-03-14 09:30  team              alice         (unset) → core",
+		},
+		{
+			name:  "status",
+			event: types.Event{EventType: types.EventStatusChanged, OldValue: str("open"), NewValue: str("in_progress")},
+			want:  "2026-03-14 09:30  status_changed    alice         open → in_progress",
+		},
+		{
+			name:  "undone",
+			event: types.Event{EventType: types.EventUndone, OldValue: str("42"), NewValue: str("57")},
+			want:  "2026-03-14 09:30  undone            alice         reversed event #42",
+		},
+		{
+			name:  "legacy update",
+			event: types.Event{EventType: types.EventUpdated},
+			want:  "2026-03-14 09:30  updated           alice",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.event.Actor = "alice"
+			tt.event.CreatedAt = ts
+			if got := formatHistoryEvent(&tt.event); got != tt.want {
+				t.Errorf("formatHistoryEvent:\n got %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHistoryTime(t *testing.T) {
+	got, err := parseHistoryTime("2026-03-14")
+	if err != nil {
+		t.Fatalf("parseHistoryTime: %v", err)
+	}
+	if want := time.Date(2026, 3, 14, 23, 59, 59, 999999999, time.Local); !got.Equal(want) {
+		t.Errorf("date = %v, want end of day %v", got, want)
+	}
+	if _, err := parseHistoryTime("2026-03-14T09:30:00Z"); err != nil {
+		t.Errorf("RFC 3339: %v", err)
+	}
+	if _, err := parseHistoryTime("last week"); err == nil {
+		t.Error("expected an error for an unparseable time")
+	}
+}
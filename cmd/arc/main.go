@@ -43,6 +43,10 @@ const (
 	// depPairArgCount is the number of arguments for commands that take a pair of issue IDs.
 	depPairArgCount = 2
 
+	// takeAttempts bounds how often --take re-reads an issue that changed
+	// between reading and updating it.
+	takeAttempts = 3
+
 	// statusIconOpen is the icon displayed for open issues.
 	statusIconOpen = "\u25cb" // ○
 
@@ -123,6 +127,7 @@ func saveConfig(cfg *cfgpkg.Config) error {
 }
 
 // getClient returns an HTTP client configured for the current server URL.
+// The API token comes from ARC_TOKEN, falling back to cli.token in config.
 func getClient() (*client.Client, error) {
 	cfg, err := loadConfig()
 	if err != nil {
@@ -139,7 +144,15 @@ func getClient() (*client.Client, error) {
 		url = cfg.CLI.Server
 	}
 
-	return client.New(url), nil
+	c := client.New(url)
+	token := os.Getenv("ARC_TOKEN")
+	if token == "" {
+		token = cfg.CLI.Token
+	}
+	if token != "" {
+		c.SetToken(token)
+	}
+	return c, nil
 }
 
 // getProjectID resolves the project ID using the following priority:
@@ -535,6 +548,24 @@ var projectDeleteCmd = &cobra.Command{
 var listCmd = &cobra.Command{
 	Use:   cmdList,
 	Short: "List issues",
+	Long: `List issues in the current project.
+
+--where takes a query in a compact syntax; all terms must match, and OR
+splits it into alternatives:
+
+  status:open,in_progress   -status:closed     type:bug,feature
+  label:area:api            -label:wontfix     parent:mp-abc123
+  priority:0,1  priority<=1 created>7d  updated<2026-03-01
+  field.component:ui        sort:-priority,updated
+  other words               must appear in the title or description
+
+Relative times (30m, 24h, 7d, 2w) count back from now, so updated>7d means
+updated within the last week. @name runs a query saved with
+"arc project queries save", optionally followed by more terms.
+
+  arc list --where "type:bug priority<=1 -label:wontfix sort:-updated"
+  arc list --where "label:urgent OR priority:0"
+  arc list --where "@triage label:area:api"`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		c, err := getClient()
 		if err != nil {
@@ -549,15 +580,26 @@ var listCmd = &cobra.Command{
 		status, _ := cmd.Flags().GetString("status")
 		issueType, _ := cmd.Flags().GetString("type")
 		query, _ := cmd.Flags().GetString("query")
+		where, _ := cmd.Flags().GetString("where")
+		if query != "" && where != "" {
+			return errors.New("use either --query or --where, not both")
+		}
 		limit, _ := cmd.Flags().GetInt("limit")
 		parentID, _ := cmd.Flags().GetString("parent")
+		fieldPairs, _ := cmd.Flags().GetStringArray("field")
+		fields, err := parseAssignments("field", fieldPairs)
+		if err != nil {
+			return err
+		}
 
 		issues, err := c.ListIssues(wsID, client.ListIssuesOptions{
 			Status: status,
 			Type:   issueType,
 			Query:  query,
+			Where:  where,
 			Limit:  limit,
 			Parent: parentID,
+			Fields: fields,
 		})
 		if err != nil {
 			return err
@@ -580,8 +622,10 @@ func init() {
 	listCmd.Flags().String("status", "", "Filter by status")
 	listCmd.Flags().String("type", "", "Filter by type")
 	listCmd.Flags().StringP("query", "q", "", "Search query")
+	listCmd.Flags().StringP("where", "w", "", "Filter with a query, e.g. \"status:open label:api priority<=1\"")
 	listCmd.Flags().IntP("limit", "l", defaultListLimit, "Max results")
 	listCmd.Flags().String("parent", "", "Filter by parent issue ID")
+	listCmd.Flags().StringArray("field", nil, "Filter by custom field as key=value (repeatable)")
 }
 
 // createCmd creates a new issue in the active project.
@@ -600,6 +644,13 @@ var createCmd = &cobra.Command{
 			return err
 		}
 
+		if tmpl, _ := cmd.Flags().GetString("template"); tmpl != "" {
+			if len(args) > 0 || cmd.Flags().Changed("title") {
+				return errors.New("a title cannot be combined with --template")
+			}
+			return createFromTemplate(cmd, c, wsID, tmpl)
+		}
+
 		priority, _ := cmd.Flags().GetInt("priority")
 		issueType, _ := cmd.Flags().GetString("type")
 		description, _ := cmd.Flags().GetString("description")
@@ -621,15 +672,28 @@ var createCmd = &cobra.Command{
 		if title == "" {
 			return errors.New("title is required (positional arg or --title flag)")
 		}
+		fieldPairs, _ := cmd.Flags().GetStringArray("field")
+		fields, err := parseAssignments("field", fieldPairs)
+		if err != nil {
+			return err
+		}
+
+		noDuplicates, _ := cmd.Flags().GetBool("no-duplicates")
 
 		issue, err := c.CreateIssue(wsID, client.CreateIssueRequest{
-			Title:       title,
-			Description: description,
-			Priority:    priority,
-			IssueType:   issueType,
-			ParentID:    parentID,
+			Title:        title,
+			Description:  description,
+			Priority:     priority,
+			IssueType:    issueType,
+			ParentID:     parentID,
+			Fields:       fields,
+			NoDuplicates: noDuplicates,
 		})
 		if err != nil {
+			var dupErr *types.DuplicateIssueError
+			if errors.As(err, &dupErr) && !outputJSON {
+				printDuplicateMatches(os.Stderr, dupErr.Matches)
+			}
 			return err
 		}
 
@@ -656,6 +720,10 @@ var createCmd = &cobra.Command{
 		}
 
 		fmt.Printf("Created: %s\n", issue.ID)
+		if len(issue.PossibleDuplicates) > 0 {
+			_, _ = fmt.Fprintln(os.Stderr, "Warning: this may duplicate an open issue:")
+			printDuplicateMatches(os.Stderr, issue.PossibleDuplicates)
+		}
 		return nil
 	},
 }
@@ -668,6 +736,10 @@ func init() {
 	createCmd.Flags().Bool("stdin", false, "Read description from stdin")
 	createCmd.Flags().String("parent", "", "Parent issue ID (creates child with .N suffix)")
 	createCmd.Flags().StringSlice("label", nil, "Label to apply (repeatable)")
+	createCmd.Flags().String("template", "", "Create issues from a template in .arc/templates or on the server")
+	createCmd.Flags().StringArray("var", nil, "Template variable as name=value (repeatable)")
+	createCmd.Flags().StringArray("field", nil, "Custom field value as key=value (repeatable)")
+	createCmd.Flags().Bool("no-duplicates", false, "Refuse to create the issue if it likely duplicates an open one")
 }
 
 // showCmd displays full details for a single issue.
@@ -705,6 +777,7 @@ var showCmd = &cobra.Command{
 		if len(details.Labels) > 0 {
 			fmt.Printf("\nLabels: %s\n", strings.Join(details.Labels, ", "))
 		}
+		printFields(details.Fields)
 		if len(details.Dependencies) > 0 {
 			fmt.Printf("\nDepends on:\n")
 			for _, dep := range details.Dependencies {
@@ -753,6 +826,10 @@ var updateCmd = &cobra.Command{
 			val, _ := cmd.Flags().GetInt("priority")
 			updates["priority"] = val
 		}
+		if cmd.Flags().Changed("rank") {
+			val, _ := cmd.Flags().GetInt("rank")
+			updates["rank"] = val
+		}
 		if val, _ := cmd.Flags().GetString("type"); val != "" {
 			updates["issue_type"] = val
 		}
@@ -768,6 +845,14 @@ var updateCmd = &cobra.Command{
 		if description != "" {
 			updates["description"] = description
 		}
+		fieldPairs, _ := cmd.Flags().GetStringArray("field")
+		fields, err := parseAssignments("field", fieldPairs)
+		if err != nil {
+			return err
+		}
+		if len(fields) > 0 {
+			updates["fields"] = fields
+		}
 
 		// Handle --take flag
 		take, _ := cmd.Flags().GetBool("take")
@@ -800,9 +885,19 @@ var updateCmd = &cobra.Command{
 		}
 
 		// Apply field updates first (if any)
+		ifVersion, _ := cmd.Flags().GetInt64("if-version")
 		var issue *types.Issue
 		if len(updates) > 0 {
-			issue, err = c.UpdateIssueByID(args[0], updates)
+			if take && ifVersion == 0 {
+				issue, err = takeIssue(c, args[0], updates, sessionID)
+			} else {
+				issue, err = c.UpdateIssueByIDAtVersion(args[0], updates, ifVersion)
+			}
+			var conflictErr *types.VersionConflictError
+			if errors.As(err, &conflictErr) {
+				return fmt.Errorf("%s has changed since version %d (now at version %d); review it and retry",
+					args[0], conflictErr.Expected, conflictErr.Current)
+			}
 			if err != nil {
 				return err
 			}
@@ -843,10 +938,36 @@ var updateCmd = &cobra.Command{
 	},
 }
 
+// takeIssue applies a --take update as a compare-and-swap: it reads the
+// issue, refuses if another session is already working on it, and writes
+// only if the issue is unchanged since that read. A concurrent change is
+// retried with a fresh read.
+func takeIssue(c *client.Client, id string, updates map[string]any, sessionID string) (*types.Issue, error) {
+	var err error
+	for range takeAttempts {
+		current, getErr := c.GetIssueByID(id)
+		if getErr != nil {
+			return nil, getErr
+		}
+		if current.Status == types.StatusInProgress && current.AISessionID != "" && current.AISessionID != sessionID {
+			return nil, fmt.Errorf("%s is already in progress in session %s", id, current.AISessionID)
+		}
+
+		var issue *types.Issue
+		issue, err = c.UpdateIssueByIDAtVersion(id, updates, current.Version)
+		var conflictErr *types.VersionConflictError
+		if !errors.As(err, &conflictErr) {
+			return issue, err
+		}
+	}
+	return nil, err
+}
+
 func init() {
 	updateCmd.Flags().String("status", "", "New status")
 	updateCmd.Flags().String("title", "", "New title")
 	updateCmd.Flags().IntP("priority", "p", 0, "New priority")
+	updateCmd.Flags().Int("rank", 0, "New rank (1+ is worked on first, 0 clears it)")
 	updateCmd.Flags().StringP("type", "t", "", "New type")
 	updateCmd.Flags().StringP("description", "d", "", "New description")
 	updateCmd.Flags().Bool("stdin", false, "Read description from stdin")
@@ -855,6 +976,9 @@ func init() {
 	updateCmd.Flags().String("session-id", "", "Explicit AI session ID (used with --take)")
 	updateCmd.Flags().StringSlice("label-add", nil, "Label to add (repeatable)")
 	updateCmd.Flags().StringSlice("label-remove", nil, "Label to remove (repeatable)")
+	updateCmd.Flags().StringArray("field", nil, "Set a custom field as key=value; key= clears it (repeatable)")
+	updateCmd.Flags().Int64("if-version", 0,
+		"Only update if the issue is still at this version (see the version field of arc show --json)")
 }
 
 // closeCmd marks one or more issues as closed.
@@ -950,10 +1074,10 @@ var readyCmd = &cobra.Command{
 			return err
 		}
 
-		limit, _ := cmd.Flags().GetInt("limit")
-		sortPolicy, _ := cmd.Flags().GetString("sort")
+		opts := workOptions(cmd)
+		opts.Sort, _ = cmd.Flags().GetString("sort")
 
-		issues, err := c.GetReadyWork(wsID, limit, sortPolicy)
+		issues, err := c.GetReadyWork(wsID, opts)
 		if err != nil {
 			return err
 		}
@@ -978,7 +1102,7 @@ var readyCmd = &cobra.Command{
 }
 
 func init() {
-	readyCmd.Flags().IntP("limit", "l", defaultReadyLimit, "Max results")
+	addWorkFlags(readyCmd, defaultReadyLimit)
 	readyCmd.Flags().String("sort", "hybrid",
 		"Sort policy: hybrid (recent by priority, old by age), "+
 			"priority (always by priority), oldest (oldest first)")
@@ -999,9 +1123,7 @@ var blockedCmd = &cobra.Command{
 			return err
 		}
 
-		limit, _ := cmd.Flags().GetInt("limit")
-
-		issues, err := c.GetBlockedIssues(wsID, limit)
+		issues, err := c.GetBlockedIssues(wsID, workOptions(cmd))
 		if err != nil {
 			return err
 		}
@@ -1025,7 +1147,38 @@ var blockedCmd = &cobra.Command{
 }
 
 func init() {
-	blockedCmd.Flags().IntP("limit", "l", defaultBlockedLimit, "Max results")
+	addWorkFlags(blockedCmd, defaultBlockedLimit)
+}
+
+// addWorkFlags registers the filter and paging flags shared by ready and blocked.
+func addWorkFlags(cmd *cobra.Command, limit int) {
+	cmd.Flags().String("status", "", "Filter by status")
+	cmd.Flags().String("type", "", "Filter by type")
+	cmd.Flags().IntP("priority", "p", 0, "Filter by priority (0-4)")
+	cmd.Flags().StringArray("label", nil, "Filter by label (repeatable, all must match)")
+	cmd.Flags().String("parent", "", "Only children of this issue (e.g. an epic)")
+	cmd.Flags().String("teammate", "", "Only issues for this teammate role (teammate:<role> label)")
+	cmd.Flags().IntP("limit", "l", limit, "Max results")
+	cmd.Flags().Int("offset", 0, "Skip this many results")
+	cmd.Flags().String("after", "", "Start after this issue ID (the last one of the previous page)")
+}
+
+// workOptions reads the flags registered by addWorkFlags.
+func workOptions(cmd *cobra.Command) client.WorkOptions {
+	var opts client.WorkOptions
+	opts.Status, _ = cmd.Flags().GetString("status")
+	opts.Type, _ = cmd.Flags().GetString("type")
+	if cmd.Flags().Changed("priority") {
+		priority, _ := cmd.Flags().GetInt("priority")
+		opts.Priority = &priority
+	}
+	opts.Labels, _ = cmd.Flags().GetStringArray("label")
+	opts.Parent, _ = cmd.Flags().GetString("parent")
+	opts.Teammate, _ = cmd.Flags().GetString("teammate")
+	opts.Limit, _ = cmd.Flags().GetInt("limit")
+	opts.Offset, _ = cmd.Flags().GetInt("offset")
+	opts.After, _ = cmd.Flags().GetString("after")
+	return opts
 }
 
 // ============ Dependency Commands ============
@@ -1039,6 +1192,7 @@ var depCmd = &cobra.Command{
 func init() {
 	depCmd.AddCommand(depAddCmd)
 	depCmd.AddCommand(depRemoveCmd)
+	depCmd.AddCommand(depCheckCmd)
 }
 
 // depAddCmd creates a dependency between two issues.
@@ -1067,7 +1221,7 @@ var depAddCmd = &cobra.Command{
 }
 
 func init() {
-	depAddCmd.Flags().StringP("type", "t", "blocks", "Dependency type (blocks, parent-child, related, discovered-from)")
+	depAddCmd.Flags().StringP("type", "t", "blocks", "Dependency type (blocks, parent-child, related, discovered-from, duplicates)")
 }
 
 // depRemoveCmd removes a dependency between two issues.
@@ -1090,6 +1244,49 @@ var depRemoveCmd = &cobra.Command{
 	},
 }
 
+// depCheckCmd audits the project's dependency graph.
+var depCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check the dependency graph for problems",
+	Long: `Audit the current project's dependencies for cycles of blocking
+dependencies, dependencies on missing issues or issues in other projects,
+and parent-child dependencies that disagree with hierarchical child IDs.
+Exits non-zero when problems are found.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+
+		wsID, err := getProjectID()
+		if err != nil {
+			return err
+		}
+
+		report, err := c.ValidateDependencies(wsID)
+		if err != nil {
+			return err
+		}
+
+		if outputJSON {
+			outputResult(report)
+		} else {
+			fmt.Printf("Checked %d issues, %d dependencies\n", report.Issues, report.Dependencies)
+			for _, p := range report.Problems {
+				fmt.Printf("  %s  %s\n", color.New(color.FgRed).Sprintf("%-16s", p.Kind), p.Detail)
+			}
+		}
+		if len(report.Problems) > 0 {
+			return fmt.Errorf("%d dependency problem(s) found", len(report.Problems))
+		}
+		if !outputJSON {
+			fmt.Println("No problems found")
+		}
+		return nil
+	},
+}
+
 // ============ Stats Command ============
 
 // statsCmd displays aggregate statistics for the active project.
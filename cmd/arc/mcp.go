@@ -0,0 +1,43 @@
+package main
+
+import (
+	"os"
+
+	"github.com/sentiolabs/arc/internal/mcp"
+	"github.com/spf13/cobra"
+)
+
+// mcpCmd runs an MCP server over stdio.
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Run an MCP server over stdio",
+	Long: `Run a Model Context Protocol server on stdin/stdout.
+
+MCP-capable agents can register arc as a tool server instead of shelling
+out to the CLI. The tools (create_issue, show_issue, update_issue,
+close_issue, ready_work, add_dependency, remove_dependency, team_context,
+create_plan, wait_plan) call the arc server configured for this CLI.
+
+Project-scoped tools default to the project resolved for the current
+directory (or --project); callers may override it per call.
+
+To expose the same tools over streamable HTTP instead, start the server
+with 'arc server start --mcp' and point the agent at http://<host>:<port>/mcp.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+
+		// A missing project is not fatal: tools accept an explicit project.
+		defaultProject, _, _, _ := resolveProject()
+
+		srv := mcp.NewServer(c, mcp.Options{DefaultProject: defaultProject})
+		return srv.ServeStdio(cmd.Context(), os.Stdin, os.Stdout)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// mergeIssueCmd merges a duplicate issue into its canonical issue.
+var mergeIssueCmd = &cobra.Command{
+	Use:   "merge-issue <duplicate>",
+	Short: "Merge a duplicate issue into its canonical issue",
+	Long: `Merge a duplicate issue into the issue it duplicates.
+
+Comments, labels and dependencies move to the canonical issue, and issues
+that depended on the duplicate depend on the canonical issue instead. The
+duplicate is closed as a duplicate. Afterwards its ID stands for the
+canonical issue: arc show, arc update and the API read and change the
+canonical issue when given it.
+
+Both issues must be in the same project.
+
+Examples:
+  arc merge-issue arc-x7k2 --into arc-a1b2`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		into, _ := cmd.Flags().GetString("into")
+		if into == "" {
+			return errors.New("--into flag is required")
+		}
+
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+
+		result, err := c.MergeIssueByID(args[0], into)
+		if err != nil {
+			return err
+		}
+
+		if outputJSON {
+			outputResult(result)
+			return nil
+		}
+
+		fmt.Printf("Merged %s into %s\n", result.Duplicate.ID, result.Canonical.ID)
+		fmt.Printf("Moved %d comments, %d labels and %d dependencies\n",
+			result.CommentsMoved, result.LabelsMoved, result.DependenciesMoved)
+		return nil
+	},
+}
+
+func init() {
+	mergeIssueCmd.Flags().String("into", "", "Canonical issue ID (required)")
+	rootCmd.AddCommand(mergeIssueCmd)
+}
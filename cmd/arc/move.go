@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// moveCmd moves an issue and its descendants to another project.
+var moveCmd = &cobra.Command{
+	Use:   "move <issue-id>",
+	Short: "Move an issue and its children to another project",
+	Long: `Move an issue, together with its children and their children, to another
+project. The moved issues get new IDs under the target project's prefix and
+keep their comments, labels, dependencies and history. Custom field values
+the target project does not define, or whose values it does not accept,
+are dropped and listed.
+
+The old IDs keep working: arc show, arc update and the API resolve them to
+the moved issues, so IDs already pasted into commit messages and comments
+still lead to the right place.
+
+The project can be specified by name or ID.
+
+Examples:
+  arc move arc.x7k2ab --to web
+  arc move arc.x7k2ab --to proj-a1b2c3`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		to, _ := cmd.Flags().GetString("to")
+		if to == "" {
+			return errors.New("--to flag is required")
+		}
+
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+
+		projID, err := resolveProjectNameOrID(c, to)
+		if err != nil {
+			return fmt.Errorf("resolve target project %q: %w", to, err)
+		}
+
+		result, err := c.MoveIssueByID(args[0], projID)
+		if err != nil {
+			return err
+		}
+
+		if outputJSON {
+			outputResult(result)
+			return nil
+		}
+
+		fmt.Printf("Moved %d issues to %s\n", len(result.IDMap), result.TargetProject.Name)
+		for _, oldID := range slices.Sorted(maps.Keys(result.IDMap)) {
+			fmt.Printf("  %s → %s\n", oldID, result.IDMap[oldID])
+		}
+		for _, id := range slices.Sorted(maps.Keys(result.DroppedFields)) {
+			fmt.Printf("Dropped fields on %s: %s\n", id, strings.Join(result.DroppedFields[id], ", "))
+		}
+		return nil
+	},
+}
+
+func init() {
+	moveCmd.Flags().String("to", "", "Target project name or ID (required)")
+	rootCmd.AddCommand(moveCmd)
+}
@@ -155,13 +155,13 @@ func runOnboard(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get ready work (use default hybrid sort)
-	readyIssues, err := c.GetReadyWork(wsID, onboardLimit, "")
+	readyIssues, err := c.GetReadyWork(wsID, client.WorkOptions{Limit: onboardLimit})
 	if err != nil {
 		return fmt.Errorf("get ready work: %w", err)
 	}
 
 	// Get blocked issues
-	blockedIssues, err := c.GetBlockedIssues(wsID, onboardLimit)
+	blockedIssues, err := c.GetBlockedIssues(wsID, client.WorkOptions{Limit: onboardLimit})
 	if err != nil {
 		return fmt.Errorf("get blocked issues: %w", err)
 	}
@@ -12,6 +12,7 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -20,6 +21,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/sentiolabs/arc/internal/client"
 	"github.com/sentiolabs/arc/internal/plans"
 	"github.com/sentiolabs/arc/internal/types"
 	"github.com/spf13/cobra"
@@ -70,7 +72,9 @@ Commands:
   create <file-path>       Register a plan from a markdown file
   show <plan-id>           Show plan metadata and content
   approve <plan-id>        Approve a plan
-  reject <plan-id>         Reject a plan`,
+  reject <plan-id>         Reject a plan
+  link <plan-id> <issue>   Link a plan to an issue, making it searchable
+  unlink <plan-id> <issue> Remove a plan's link to an issue`,
 }
 
 // planWaitTimeout is the --timeout flag for planWaitCmd.
@@ -79,7 +83,8 @@ var planWaitTimeout time.Duration
 const (
 	// planWaitDefaultTimeout is the default value for planWaitCmd's --timeout flag.
 	planWaitDefaultTimeout = 30 * time.Minute
-	// planWaitPollInterval is how often planWaitCmd polls the plan status.
+	// planWaitPollInterval is how often planWaitCmd polls the plan status
+	// when the event stream is unavailable.
 	planWaitPollInterval = 2 * time.Second
 	// planWaitMaxConsecutiveErrors is how many consecutive GetPlan failures
 	// planWaitCmd tolerates before giving up. A single transient error (e.g. a
@@ -99,6 +104,8 @@ func init() {
 	planCmd.AddCommand(planRejectCmd)
 	planCmd.AddCommand(planCommentsCmd)
 	planCmd.AddCommand(planWaitCmd)
+	planCmd.AddCommand(planLinkCmd)
+	planCmd.AddCommand(planUnlinkCmd)
 
 	planCreateCmd.Flags().StringVar(&titleFlag, "title", "", "Override the plan title written to frontmatter")
 	planCreateCmd.Flags().BoolVar(&noFrontmatter, "no-frontmatter", false,
@@ -246,6 +253,48 @@ var planRejectCmd = &cobra.Command{
 	},
 }
 
+// planLinkCmd links a plan to an issue.
+var planLinkCmd = &cobra.Command{
+	Use:   "link <plan-id> <issue-id>",
+	Short: "Link a plan to an issue",
+	Long: `Link a plan to an issue. The plan's content is indexed with the issue,
+so arc search finds the issue by words in its plans.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+
+		if err := c.LinkIssuePlan(args[1], args[0]); err != nil {
+			return err
+		}
+
+		_, _ = fmt.Printf("Plan %s linked to %s\n", args[0], args[1])
+		return nil
+	},
+}
+
+// planUnlinkCmd removes a plan's link to an issue.
+var planUnlinkCmd = &cobra.Command{
+	Use:   "unlink <plan-id> <issue-id>",
+	Short: "Remove a plan's link to an issue",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+
+		if err := c.UnlinkIssuePlan(args[1], args[0]); err != nil {
+			return err
+		}
+
+		_, _ = fmt.Printf("Plan %s unlinked from %s\n", args[0], args[1])
+		return nil
+	},
+}
+
 // planCommentsCmd lists review comments for a plan in a structured format.
 var planCommentsCmd = &cobra.Command{
 	Use:   "comments <plan-id>",
@@ -321,8 +370,10 @@ type planWaitResult struct {
 }
 
 // planWaitCmd blocks until a review decision is made in the planner web UI.
-// It polls the plan status every 2s until it leaves draft/in_review, then
-// prints the decision and the full comment thread (the same formats used by
+// It listens on the server's event stream and re-checks the plan whenever its
+// status changes, falling back to polling every 2s if the stream cannot be
+// opened or drops. Once the plan leaves draft/in_review it prints the
+// decision and the full comment thread (the same formats used by
 // `arc plan comments`). Exits non-zero on timeout so callers can distinguish
 // "no decision yet" from a decision.
 var planWaitCmd = &cobra.Command{
@@ -336,42 +387,121 @@ var planWaitCmd = &cobra.Command{
 		}
 		planID := args[0]
 
-		deadline := time.Now().Add(planWaitTimeout)
-		var consecutiveErrors int
-		for {
-			plan, err := c.GetPlan(planID)
-			if err != nil {
-				consecutiveErrors++
-				if consecutiveErrors >= planWaitMaxConsecutiveErrors {
-					return fmt.Errorf("plan wait aborted after %d consecutive errors: %w",
-						planWaitMaxConsecutiveErrors, err)
-				}
-				if time.Now().After(deadline) {
-					return fmt.Errorf("timed out after %s waiting for a decision on %s: %w",
-						planWaitTimeout, planID, err)
-				}
-				time.Sleep(planWaitPollInterval)
-				continue
-			}
-			consecutiveErrors = 0
-			if plan.Status != types.PlanStatusDraft && plan.Status != types.PlanStatusInReview {
-				comments, err := c.ListPlanComments(planID)
-				if err != nil {
-					return err
-				}
-				if outputJSON {
-					outputResult(planWaitResult{Status: plan.Status, Comments: comments})
-					return nil
-				}
-				fmt.Printf("Decision: %s\n", plan.Status)
-				printPlanComments(comments)
-				return nil
-			}
-			if time.Now().After(deadline) {
-				return fmt.Errorf("timed out after %s waiting for a decision on %s (status: %s)",
-					planWaitTimeout, planID, plan.Status)
-			}
-			time.Sleep(planWaitPollInterval)
+		w := &planWaiter{c: c, planID: planID, deadline: time.Now().Add(planWaitTimeout)}
+		status, err := w.wait()
+		if err != nil {
+			return err
+		}
+
+		comments, err := c.ListPlanComments(planID)
+		if err != nil {
+			return err
+		}
+		if outputJSON {
+			outputResult(planWaitResult{Status: status, Comments: comments})
+			return nil
 		}
+		fmt.Printf("Decision: %s\n", status)
+		printPlanComments(comments)
+		return nil
 	},
 }
+
+// planWaiter holds the state shared by the streaming and polling phases of
+// planWaitCmd, so consecutive GetPlan failures are counted across both.
+type planWaiter struct {
+	c                 *client.Client
+	planID            string
+	deadline          time.Time
+	consecutiveErrors int
+}
+
+// wait blocks until the plan has a decision and returns its status.
+func (w *planWaiter) wait() (string, error) {
+	status, done, err := w.waitStream()
+	if done || err != nil {
+		return status, err
+	}
+	return w.poll()
+}
+
+// waitStream waits for plan status changes on the event stream. It returns
+// done=false with a nil error when the caller should fall back to polling:
+// the stream could not be opened, dropped, or a GetPlan call failed.
+func (w *planWaiter) waitStream() (string, bool, error) {
+	ctx, cancel := context.WithDeadline(context.Background(), w.deadline)
+	defer cancel()
+
+	stream, err := w.c.OpenEventStream(ctx, client.EventStreamOptions{
+		Types: []string{string(types.StreamPlanStatusChanged)},
+	})
+	if err != nil {
+		return "", false, nil
+	}
+	defer stream.Close()
+
+	for {
+		// Check after subscribing (and after every change) so a decision
+		// made before the stream opened is not missed.
+		status, done, err := w.check()
+		if done || err != nil || w.consecutiveErrors > 0 {
+			return status, done, err
+		}
+		if err := w.nextPlanChange(stream); err != nil {
+			return "", false, nil
+		}
+	}
+}
+
+// nextPlanChange blocks until the stream reports a change to this plan.
+func (w *planWaiter) nextPlanChange(stream *client.EventStream) error {
+	for {
+		ev, err := stream.Next()
+		if err != nil {
+			return err
+		}
+		if ev.PlanID == w.planID {
+			return nil
+		}
+	}
+}
+
+// poll re-checks the plan every planWaitPollInterval until it is decided.
+func (w *planWaiter) poll() (string, error) {
+	for {
+		status, done, err := w.check()
+		if done || err != nil {
+			return status, err
+		}
+		time.Sleep(planWaitPollInterval)
+	}
+}
+
+// check fetches the plan once. It reports done when a decision has been
+// made, and returns an error once the deadline passes or GetPlan has failed
+// planWaitMaxConsecutiveErrors times in a row. A single transient error (e.g.
+// a server restart) is tolerated.
+func (w *planWaiter) check() (string, bool, error) {
+	plan, err := w.c.GetPlan(w.planID)
+	if err != nil {
+		w.consecutiveErrors++
+		if w.consecutiveErrors >= planWaitMaxConsecutiveErrors {
+			return "", false, fmt.Errorf("plan wait aborted after %d consecutive errors: %w",
+				planWaitMaxConsecutiveErrors, err)
+		}
+		if time.Now().After(w.deadline) {
+			return "", false, fmt.Errorf("timed out after %s waiting for a decision on %s: %w",
+				planWaitTimeout, w.planID, err)
+		}
+		return "", false, nil
+	}
+	w.consecutiveErrors = 0
+	if plan.Status != types.PlanStatusDraft && plan.Status != types.PlanStatusInReview {
+		return plan.Status, true, nil
+	}
+	if time.Now().After(w.deadline) {
+		return "", false, fmt.Errorf("timed out after %s waiting for a decision on %s (status: %s)",
+			planWaitTimeout, w.planID, plan.Status)
+	}
+	return plan.Status, false, nil
+}
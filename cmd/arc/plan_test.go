@@ -459,3 +459,62 @@ func TestPlanWaitAbortsAfterConsecutiveErrors(t *testing.T) {
 		t.Errorf("expected %d GetPlan calls, got %d", planWaitMaxConsecutiveErrors, getPlanCalls)
 	}
 }
+
+func TestPlanWaitWakesOnStreamEvent(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+	store, err := sqlite.New(dbPath)
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	defer store.Close()
+
+	server := api.New(api.ServerOptions{Address: ":0", Store: store})
+	ts := httptest.NewServer(server.Echo())
+	defer ts.Close()
+
+	c := client.New(ts.URL)
+	c.SetActor("test-user")
+
+	filePath := filepath.Join(t.TempDir(), "plan.md")
+	if err := os.WriteFile(filePath, []byte("# Plan\n"), 0o600); err != nil {
+		t.Fatalf("write plan file: %v", err)
+	}
+	plan, err := c.CreatePlan(filePath)
+	if err != nil {
+		t.Fatalf("create plan: %v", err)
+	}
+
+	origServerURL := serverURL
+	origTimeout := planWaitTimeout
+	origOutputJSON := outputJSON
+	serverURL = ts.URL
+	planWaitTimeout = time.Minute
+	outputJSON = false
+	defer func() {
+		serverURL = origServerURL
+		planWaitTimeout = origTimeout
+		outputJSON = origOutputJSON
+	}()
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_ = c.UpdatePlanStatus(plan.ID, "rejected")
+	}()
+
+	start := time.Now()
+	out := captureStdout(t, func() {
+		if err := planWaitCmd.RunE(planWaitCmd, []string{plan.ID}); err != nil {
+			t.Fatalf("planWaitCmd.RunE: %v", err)
+		}
+	})
+
+	// Polling would not notice the change until planWaitPollInterval elapsed.
+	if elapsed := time.Since(start); elapsed >= planWaitPollInterval {
+		t.Errorf("plan wait took %s; expected the event stream to wake it before the %s poll",
+			elapsed, planWaitPollInterval)
+	}
+	if want := "Decision: rejected\nNo comments\n"; out != want {
+		t.Errorf("planWaitCmd output: got %q, want %q", out, want)
+	}
+}
@@ -0,0 +1,122 @@
+// Project export and import commands for moving a project between servers.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// exportCmd writes the current project as a JSONL archive.
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export a project as a portable JSONL archive",
+	Long: `Export the current project (or --project) as a JSONL archive.
+
+The archive holds the project, its config, workspaces, labels, issues,
+dependencies, comments, events, and plans with their comments, one typed
+record per line. Plan file contents are not included.
+
+Examples:
+  arc export --project arc-k3x9f2 > arc.jsonl
+  arc export -o arc.jsonl`,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		projID, err := getProjectID()
+		if err != nil {
+			return err
+		}
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+
+		var w io.Writer = os.Stdout
+		if out, _ := cmd.Flags().GetString("output"); out != "" && out != "-" {
+			f, err := os.Create(out)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if cerr := f.Close(); err == nil {
+					err = cerr
+				}
+			}()
+			w = f
+		}
+
+		return c.ExportProject(projID, w)
+	},
+}
+
+// importCmd loads a JSONL archive produced by 'arc export'.
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import a project from a JSONL archive",
+	Long: `Import a project archive produced by 'arc export'. Use "-" to read stdin.
+
+By default the archived project is recreated with all of its IDs preserved;
+the import fails without changes if the project, any issue, workspace, or
+plan already exists on the server.
+
+With --into, the archive is added to an existing project instead. Issue IDs
+are re-prefixed with that project's prefix, and dependencies, comments,
+events, and ID mentions in descriptions are rewritten to match. Workspaces
+the project already has are skipped, and the archived config is ignored.
+
+Examples:
+  arc import arc.jsonl
+  arc import arc.jsonl --into team-a8c2d1`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var r io.Reader = os.Stdin
+		if args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+
+		into, _ := cmd.Flags().GetString("into")
+		result, err := c.ImportProject(r, into)
+		if err != nil {
+			return err
+		}
+
+		if outputJSON {
+			outputResult(result)
+			return nil
+		}
+
+		fmt.Printf("Imported into %s (%s)\n", result.Project.Name, result.Project.ID)
+		fmt.Printf("  Issues:       %d\n", result.Issues)
+		fmt.Printf("  Dependencies: %d\n", result.Dependencies)
+		fmt.Printf("  Comments:     %d\n", result.Comments)
+		fmt.Printf("  Events:       %d\n", result.Events)
+		fmt.Printf("  Plans:        %d\n", result.Plans)
+		fmt.Printf("  Workspaces:   %d", result.Workspaces)
+		if result.SkippedWorkspaces > 0 {
+			fmt.Printf(" (%d already registered)", result.SkippedWorkspaces)
+		}
+		fmt.Println()
+		if len(result.IDMap) > 0 {
+			fmt.Printf("  Renamed %d issue IDs (use --json for the mapping)\n", len(result.IDMap))
+		}
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringP("output", "o", "", "Write the archive to a file instead of stdout")
+	importCmd.Flags().String("into", "", "Add the archive to this existing project, re-prefixing issue IDs")
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+}
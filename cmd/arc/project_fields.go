@@ -0,0 +1,155 @@
+// Per-project custom field commands for the arc CLI.
+// Field definitions are stored in the project config under field.<key>;
+// values are set with `arc create/update --field key=value`.
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// projectFieldsCmd lists the project's custom field definitions.
+var projectFieldsCmd = &cobra.Command{
+	Use:   "fields",
+	Short: "Manage the project's custom issue fields",
+	Long: `List the project's custom issue fields.
+
+Field types are string, number, date (YYYY-MM-DD), user, and
+enum:<option>,<option>,... Values are set on issues with --field:
+
+  arc project fields set component enum:ui,api,storage
+  arc create "Fix login" --field component=ui
+  arc list --field component=ui`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projID, err := getProjectID()
+		if err != nil {
+			return err
+		}
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+		config, err := c.GetProjectConfig(projID)
+		if err != nil {
+			return err
+		}
+
+		defs := types.FieldDefs(config)
+		list := make([]*types.FieldDef, 0, len(defs))
+		for _, def := range defs {
+			list = append(list, def)
+		}
+		slices.SortFunc(list, func(a, b *types.FieldDef) int { return strings.Compare(a.Key, b.Key) })
+
+		if outputJSON {
+			outputResult(list)
+			return nil
+		}
+		if len(list) == 0 {
+			fmt.Println("No custom fields defined")
+			return nil
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, tabwriterPadding, ' ', 0)
+		_, _ = fmt.Fprintln(w, "FIELD\tTYPE")
+		for _, def := range list {
+			_, _ = fmt.Fprintf(w, "%s\t%s\n", def.Key, def.Spec())
+		}
+		return w.Flush()
+	},
+}
+
+// projectFieldsSetCmd defines or redefines a custom field.
+var projectFieldsSetCmd = &cobra.Command{
+	Use:   "set <key> <type>",
+	Short: "Define a custom field",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		def, err := types.ParseFieldDef(args[0], args[1])
+		if err != nil {
+			return err
+		}
+		projID, err := getProjectID()
+		if err != nil {
+			return err
+		}
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+		if err := c.SetProjectConfig(projID, types.FieldConfigPrefix+def.Key, def.Spec()); err != nil {
+			return err
+		}
+
+		if outputJSON {
+			outputResult(def)
+			return nil
+		}
+		fmt.Printf("Defined field %s (%s)\n", def.Key, def.Spec())
+		return nil
+	},
+}
+
+// projectFieldsUnsetCmd removes a custom field definition. Values already
+// set on issues are kept but can no longer be changed or filtered on.
+var projectFieldsUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a custom field definition",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projID, err := getProjectID()
+		if err != nil {
+			return err
+		}
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+		if err := c.DeleteProjectConfig(projID, types.FieldConfigPrefix+args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("Removed field %s\n", args[0])
+		return nil
+	},
+}
+
+// parseAssignments parses repeated name=value flag values into a map.
+func parseAssignments(flag string, pairs []string) (map[string]string, error) {
+	values := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --%s %q (want name=value)", flag, pair)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// printFields prints an issue's custom field values in key order.
+func printFields(fields map[string]string) {
+	if len(fields) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	fmt.Printf("\nFields:\n")
+	for _, key := range keys {
+		fmt.Printf("  %s: %s\n", key, fields[key])
+	}
+}
+
+func init() {
+	projectFieldsCmd.AddCommand(projectFieldsSetCmd, projectFieldsUnsetCmd)
+	projectCmd.AddCommand(projectFieldsCmd)
+}
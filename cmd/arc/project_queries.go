@@ -0,0 +1,130 @@
+// Saved issue query commands for the arc CLI.
+// Queries are stored in the project config under query.<name> and run with
+// `arc list --where @name`.
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sentiolabs/arc/internal/issuequery"
+)
+
+// savedQuery is a named query as listed by `arc project queries`.
+type savedQuery struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+}
+
+// projectQueriesCmd lists the project's saved queries.
+var projectQueriesCmd = &cobra.Command{
+	Use:   "queries",
+	Short: "Manage the project's saved issue queries",
+	Long: `List the project's saved issue queries. A saved query is shared by
+everyone on the project and runs with @name wherever --where is accepted:
+
+  arc project queries save triage "status:open -label:wontfix priority<=1 sort:-updated"
+  arc list --where @triage
+  arc list --where "@triage label:area:api"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projID, err := getProjectID()
+		if err != nil {
+			return err
+		}
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+		config, err := c.GetProjectConfig(projID)
+		if err != nil {
+			return err
+		}
+
+		list := []savedQuery{}
+		for key, value := range config {
+			if name, ok := strings.CutPrefix(key, issuequery.ConfigPrefix); ok {
+				list = append(list, savedQuery{Name: name, Query: value})
+			}
+		}
+		slices.SortFunc(list, func(a, b savedQuery) int { return strings.Compare(a.Name, b.Name) })
+
+		if outputJSON {
+			outputResult(list)
+			return nil
+		}
+		if len(list) == 0 {
+			fmt.Println("No saved queries")
+			return nil
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, tabwriterPadding, ' ', 0)
+		_, _ = fmt.Fprintln(w, "NAME\tQUERY")
+		for _, q := range list {
+			_, _ = fmt.Fprintf(w, "%s\t%s\n", q.Name, q.Query)
+		}
+		return w.Flush()
+	},
+}
+
+// projectQueriesSaveCmd saves or replaces a named query.
+var projectQueriesSaveCmd = &cobra.Command{
+	Use:   "save <name> <query>",
+	Short: "Save a named issue query",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, query := args[0], args[1]
+		if !issuequery.ValidName(name) {
+			return fmt.Errorf("invalid query name %q (use letters, digits, - and _)", name)
+		}
+		projID, err := getProjectID()
+		if err != nil {
+			return err
+		}
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+		if err := c.SetProjectConfig(projID, issuequery.ConfigPrefix+name, query); err != nil {
+			return err
+		}
+
+		if outputJSON {
+			outputResult(savedQuery{Name: name, Query: query})
+			return nil
+		}
+		fmt.Printf("Saved query @%s\n", name)
+		return nil
+	},
+}
+
+// projectQueriesRmCmd removes a saved query.
+var projectQueriesRmCmd = &cobra.Command{
+	Use:   "rm <name>",
+	Short: "Remove a saved issue query",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projID, err := getProjectID()
+		if err != nil {
+			return err
+		}
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+		if err := c.DeleteProjectConfig(projID, issuequery.ConfigPrefix+args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("Removed query @%s\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	projectQueriesCmd.AddCommand(projectQueriesSaveCmd, projectQueriesRmCmd)
+	projectCmd.AddCommand(projectQueriesCmd)
+}
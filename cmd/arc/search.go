@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/sentiolabs/arc/internal/client"
+	"github.com/sentiolabs/arc/internal/types"
+	"github.com/spf13/cobra"
+)
+
+// searchCmd runs a full-text search over issues.
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search issues, comments, labels and linked plans",
+	Long: `Search issue titles, descriptions, comments, labels and linked plans,
+best match first. Each result shows the field that matched with the
+matching terms highlighted.
+
+Terms match any word they prefix and must all match; quote a phrase to
+match its words in sequence:
+
+  arc search flaky login
+  arc search '"payment stub"'
+  arc search --all timeout        # every project you can see`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		limit, _ := cmd.Flags().GetInt("limit")
+		offset, _ := cmd.Flags().GetInt("offset")
+
+		opts := client.SearchOptions{Limit: limit, Offset: offset}
+		if !all {
+			projID, err := getProjectID()
+			if err != nil {
+				return err
+			}
+			opts.Project = projID
+		}
+
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+		hits, err := c.Search(strings.Join(args, " "), opts)
+		if err != nil {
+			return err
+		}
+
+		if outputJSON {
+			outputResult(hits)
+			return nil
+		}
+
+		if len(hits) == 0 {
+			fmt.Println("No matches")
+			return nil
+		}
+		for _, hit := range hits {
+			issue := hit.Issue
+			fmt.Println(formatIssue(issue.ID, string(issue.Status), string(issue.IssueType),
+				issue.Priority, issue.Title, issue.Labels))
+			if hit.Field != types.SearchFieldTitle {
+				fmt.Printf("    %s: %s\n", color.New(color.Faint).Sprint(hit.Field), renderSnippet(hit.Snippet))
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	searchCmd.Flags().Bool("all", false, "Search every project instead of the current one")
+	searchCmd.Flags().IntP("limit", "l", defaultListLimit, "Max results")
+	searchCmd.Flags().Int("offset", 0, "Skip this many results")
+	rootCmd.AddCommand(searchCmd)
+}
+
+// renderSnippet prints a search snippet on one line with its highlighted
+// terms in bold.
+func renderSnippet(snippet string) string {
+	bold := color.New(color.Bold, color.FgYellow)
+	var b strings.Builder
+	rest := strings.Join(strings.Fields(snippet), " ")
+	for {
+		before, match, found := strings.Cut(rest, types.HighlightStart)
+		b.WriteString(before)
+		if !found {
+			return b.String()
+		}
+		match, rest, _ = strings.Cut(match, types.HighlightEnd)
+		b.WriteString(bold.Sprint(match))
+	}
+}
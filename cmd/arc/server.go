@@ -81,10 +81,12 @@ func init() {
 	serverStartCmd.Flags().BoolP("foreground", "f", false, "Run in foreground (don't daemonize)")
 	serverStartCmd.Flags().Int("port", defaultServerPort, "Server port")
 	serverStartCmd.Flags().String("db", "", "Database path (default: ~/.arc/data.db)")
+	serverStartCmd.Flags().Bool("mcp", false, "Serve the MCP streamable-HTTP endpoint at /mcp")
 }
 
 func runServerStart(cmd *cobra.Command, args []string) error {
 	foreground, _ := cmd.Flags().GetBool("foreground")
+	enableMCP, _ := cmd.Flags().GetBool("mcp")
 
 	cfg, err := loadConfig()
 	if err != nil {
@@ -105,8 +107,12 @@ func runServerStart(cmd *cobra.Command, args []string) error {
 	if foreground {
 		// Run server directly (blocking)
 		return server.Run(server.Config{
-			Address: addr,
-			DBPath:  dbPath,
+			Address:     addr,
+			DBPath:      dbPath,
+			DBDriver:    cfg.Server.Driver(),
+			DSN:         cfg.Server.DSN,
+			MCP:         enableMCP,
+			RequireAuth: cfg.Server.RequireAuth,
 		})
 	}
 
@@ -130,6 +136,9 @@ func runServerStart(cmd *cobra.Command, args []string) error {
 	if dbPath != "" {
 		cmdArgs = append(cmdArgs, "--db", dbPath)
 	}
+	if enableMCP {
+		cmdArgs = append(cmdArgs, "--mcp")
+	}
 
 	daemonCmd := exec.Command(execPath, cmdArgs...)
 	daemonCmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
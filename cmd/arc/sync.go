@@ -0,0 +1,100 @@
+// Sync commands for keeping a project in step with an external issue tracker.
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/sentiolabs/arc/internal/client"
+	"github.com/spf13/cobra"
+)
+
+// syncCmd is the parent command for issue tracker sync.
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync the current project with an external issue tracker",
+}
+
+// syncGitHubCmd runs a two-way sync with a GitHub repository.
+var syncGitHubCmd = &cobra.Command{
+	Use:   "github",
+	Short: "Two-way sync with GitHub Issues",
+	Long: `Sync the current project with a GitHub repository's issues.
+
+Issues, labels, and comments are pulled from GitHub; status changes and
+comments made in arc are pushed back. Each synced issue keeps its GitHub
+reference (owner/name#N) as its external ref. Only changes since the last
+run are considered; an issue changed on both sides since then is reported
+as a conflict and left alone until you reconcile it by hand.
+
+The first run only pulls. --repo is remembered, so later runs can omit it.
+The token comes from --token or GITHUB_TOKEN, falling back to the server's
+GITHUB_TOKEN.
+
+Examples:
+  arc sync github --repo sentiolabs/arc --dry-run
+  arc sync github --repo sentiolabs/arc
+  arc sync github --api-url https://github.example.com/api/v3 --repo team/app`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projID, err := getProjectID()
+		if err != nil {
+			return err
+		}
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+
+		req := client.GitHubSyncRequest{}
+		req.Repo, _ = cmd.Flags().GetString("repo")
+		req.Token, _ = cmd.Flags().GetString("token")
+		req.APIURL, _ = cmd.Flags().GetString("api-url")
+		req.DryRun, _ = cmd.Flags().GetBool("dry-run")
+		if req.Token == "" {
+			req.Token = os.Getenv("GITHUB_TOKEN")
+		}
+
+		report, err := c.SyncGitHub(projID, req)
+		if err != nil {
+			return err
+		}
+
+		if outputJSON {
+			outputResult(report)
+			return nil
+		}
+
+		verb := "Synced"
+		if report.DryRun {
+			verb = "Dry run:"
+		}
+		fmt.Printf("%s %s — %d pulled, %d pushed\n", verb, report.Repo, report.Pulled, report.Pushed)
+		if len(report.Actions) > 0 {
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, tabwriterPadding, ' ', 0)
+			for _, a := range report.Actions {
+				_, _ = fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", a.Direction, a.Kind, a.ExternalRef, a.IssueID, a.Detail)
+			}
+			_ = w.Flush()
+		}
+		for _, conflict := range report.Conflicts {
+			fmt.Printf("  conflict: %s (%s) changed on both sides; arc %s, GitHub %s\n",
+				conflict.IssueID, conflict.ExternalRef,
+				conflict.LocalUpdatedAt.Local().Format("2006-01-02 15:04"),
+				conflict.RemoteUpdatedAt.Local().Format("2006-01-02 15:04"))
+		}
+		for _, w := range report.Warnings {
+			fmt.Printf("  warning: %s\n", w)
+		}
+		return nil
+	},
+}
+
+func init() {
+	syncGitHubCmd.Flags().String("repo", "", "GitHub repository as owner/name (remembered after the first sync)")
+	syncGitHubCmd.Flags().String("token", "", "GitHub token (env: GITHUB_TOKEN)")
+	syncGitHubCmd.Flags().String("api-url", "", "GitHub-compatible REST API base URL (default: https://api.github.com)")
+	syncGitHubCmd.Flags().Bool("dry-run", false, "Show what would change without writing to arc or GitHub")
+	syncCmd.AddCommand(syncGitHubCmd)
+	rootCmd.AddCommand(syncCmd)
+}
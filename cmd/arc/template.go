@@ -0,0 +1,292 @@
+// Issue template commands for storing templates on the server and listing
+// the ones available to `arc create --template`.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/client"
+	"github.com/sentiolabs/arc/internal/issuetmpl"
+	"github.com/spf13/cobra"
+)
+
+// templateExtensions are the file extensions of local templates, in lookup
+// order.
+var templateExtensions = []string{".yaml", ".yml"}
+
+// templateCmd is the parent command for issue templates.
+// Subcommands: list, show, save, delete.
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Manage issue templates",
+	Long: `Manage issue templates: YAML descriptions of a tree of issues that
+` + "`arc create --template <name>`" + ` instantiates.
+
+Templates are read from .arc/templates/<name>.yaml in the current directory,
+or from the server when no local file exists. Stored templates with a
+recurrence (a cron expression) are instantiated by the server on schedule.
+
+  description: Release checklist
+  vars:
+    version: "0.0"
+  recurrence: "0 9 * * 1"     # optional
+  issue:
+    title: Release {{version}}
+    type: epic
+    priority: 1
+    labels: [release]
+    children:
+      - key: freeze
+        title: Code freeze for {{version}}
+      - key: tag
+        title: Tag v{{version}}
+        depends_on: [freeze]  # blocked by the freeze sibling
+
+{{date}} expands to the date the template is instantiated.`,
+}
+
+func init() {
+	templateCmd.AddCommand(templateListCmd)
+	templateCmd.AddCommand(templateShowCmd)
+	templateCmd.AddCommand(templateSaveCmd)
+	templateCmd.AddCommand(templateDeleteCmd)
+	rootCmd.AddCommand(templateCmd)
+
+	templateSaveCmd.Flags().String("name", "", "Template name (default: file name without extension)")
+}
+
+// templateListCmd lists local and stored templates.
+var templateListCmd = &cobra.Command{
+	Use:   cmdList,
+	Short: "List issue templates",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projID, err := getProjectID()
+		if err != nil {
+			return err
+		}
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+
+		stored, err := c.ListIssueTemplates(projID)
+		if err != nil {
+			return err
+		}
+		local, err := localTemplateNames()
+		if err != nil {
+			return err
+		}
+
+		if outputJSON {
+			outputResult(map[string]any{"local": local, "server": stored})
+			return nil
+		}
+
+		if len(stored) == 0 && len(local) == 0 {
+			fmt.Println("No templates found.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, tabwriterPadding, ' ', 0)
+		_, _ = fmt.Fprintln(w, "NAME\tSOURCE\tRECURRENCE\tNEXT RUN")
+		for _, name := range local {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t-\t-\n", name, issuetmpl.Dir)
+		}
+		for _, t := range stored {
+			recurrence, next := "-", "-"
+			if t.Recurrence != "" {
+				recurrence = t.Recurrence
+			}
+			if t.NextRunAt != nil {
+				next = t.NextRunAt.Local().Format(time.DateTime)
+			}
+			_, _ = fmt.Fprintf(w, "%s\tserver\t%s\t%s\n", t.Name, recurrence, next)
+		}
+		return w.Flush()
+	},
+}
+
+// templateShowCmd prints a template's YAML.
+var templateShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show a template",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if content, ok, err := readLocalTemplate(args[0]); err != nil || ok {
+			if err == nil {
+				fmt.Print(content)
+			}
+			return err
+		}
+
+		projID, err := getProjectID()
+		if err != nil {
+			return err
+		}
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+		t, err := c.GetIssueTemplate(projID, args[0])
+		if err != nil {
+			return err
+		}
+
+		if outputJSON {
+			outputResult(t)
+			return nil
+		}
+		fmt.Print(t.Content)
+		return nil
+	},
+}
+
+// templateSaveCmd stores a template file on the server.
+var templateSaveCmd = &cobra.Command{
+	Use:   "save <file>",
+	Short: "Store a template on the server",
+	Long: `Store a template file on the server so every client can instantiate it.
+A template with a recurrence is instantiated by the server on schedule.
+Saving a template with an existing name replaces it.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		content, err := os.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		name, _ := cmd.Flags().GetString("name")
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(args[0]), filepath.Ext(args[0]))
+		}
+
+		projID, err := getProjectID()
+		if err != nil {
+			return err
+		}
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+		t, err := c.SaveIssueTemplate(projID, name, string(content))
+		if err != nil {
+			return err
+		}
+
+		if outputJSON {
+			outputResult(t)
+			return nil
+		}
+		fmt.Printf("Saved template: %s\n", t.Name)
+		if t.NextRunAt != nil {
+			fmt.Printf("Next run: %s\n", t.NextRunAt.Local().Format(time.DateTime))
+		}
+		return nil
+	},
+}
+
+// templateDeleteCmd removes a stored template.
+var templateDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a stored template",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projID, err := getProjectID()
+		if err != nil {
+			return err
+		}
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+		if err := c.DeleteIssueTemplate(projID, args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("Deleted template: %s\n", args[0])
+		return nil
+	},
+}
+
+// createFromTemplate implements `arc create --template`. A template in
+// .arc/templates is sent to the server with the request; otherwise the
+// server uses the template stored under that name.
+func createFromTemplate(cmd *cobra.Command, c *client.Client, projID, name string) error {
+	content, _, err := readLocalTemplate(name)
+	if err != nil {
+		return err
+	}
+
+	pairs, _ := cmd.Flags().GetStringArray("var")
+	vars, err := parseAssignments("var", pairs)
+	if err != nil {
+		return err
+	}
+	parentID, _ := cmd.Flags().GetString("parent")
+
+	inst, err := c.InstantiateTemplate(projID, name, client.InstantiateTemplateRequest{
+		Content:  content,
+		Vars:     vars,
+		ParentID: parentID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if outputJSON {
+		outputResult(inst)
+		return nil
+	}
+	fmt.Printf("Created %d issues from template %s:\n", len(inst.IssueIDs), name)
+	for _, id := range inst.IssueIDs {
+		fmt.Printf("  %s\n", id)
+	}
+	return nil
+}
+
+// readLocalTemplate reads .arc/templates/<name>.yaml (or .yml). ok is false
+// when neither exists.
+func readLocalTemplate(name string) (content string, ok bool, err error) {
+	if !issuetmpl.ValidName(name) {
+		return "", false, fmt.Errorf("invalid template name: %s", name)
+	}
+	for _, ext := range templateExtensions {
+		data, err := os.ReadFile(filepath.Join(issuetmpl.Dir, name+ext))
+		if err == nil {
+			return string(data), true, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", false, err
+		}
+	}
+	return "", false, nil
+}
+
+// localTemplateNames lists the templates in .arc/templates.
+func localTemplateNames() ([]string, error) {
+	entries, err := os.ReadDir(issuetmpl.Dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+
+	names := []string{}
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		name := strings.TrimSuffix(e.Name(), ext)
+		if e.IsDir() || !slices.Contains(templateExtensions, ext) || slices.Contains(names, name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
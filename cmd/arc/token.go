@@ -0,0 +1,160 @@
+// API token commands for issuing, listing, and revoking the bearer tokens
+// that identify callers to the arc server.
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/types"
+	"github.com/spf13/cobra"
+)
+
+// tokenCmd is the parent command for API token management.
+// Subcommands: list, create, revoke.
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Manage API tokens",
+	Long: `Manage API tokens used to authenticate with the arc server.
+
+A token identifies its holder as an actor; every change made with it is
+recorded under that actor in the audit trail. The CLI sends the token from
+the ARC_TOKEN environment variable, or cli.token in the config file.
+
+To require a token on every request, create tokens first and then run:
+
+  arc config set server.require_auth true
+  arc server restart`,
+}
+
+func init() {
+	tokenCmd.AddCommand(tokenListCmd)
+	tokenCmd.AddCommand(tokenCreateCmd)
+	tokenCmd.AddCommand(tokenRevokeCmd)
+	rootCmd.AddCommand(tokenCmd)
+}
+
+// tokenListCmd lists API tokens visible to the caller.
+var tokenListCmd = &cobra.Command{
+	Use:   cmdList,
+	Short: "List API tokens",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+
+		tokens, err := c.ListTokens()
+		if err != nil {
+			return err
+		}
+
+		if outputJSON {
+			outputResult(tokens)
+			return nil
+		}
+
+		if len(tokens) == 0 {
+			fmt.Println("No API tokens found.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, tabwriterPadding, ' ', 0)
+		_, _ = fmt.Fprintln(w, "ID\tNAME\tACTOR\tPREFIX\tCREATED\tLAST USED\tSTATUS")
+		for _, tok := range tokens {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				tok.ID, tok.Name, tok.Actor, tok.Prefix, tok.CreatedAt.Local().Format(time.DateTime),
+				lastUsedLabel(tok.LastUsedAt), tokenStatusLabel(tok))
+		}
+		return w.Flush()
+	},
+}
+
+// tokenCreateCmd issues a token and prints it once.
+var tokenCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create an API token",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+
+		actor, _ := cmd.Flags().GetString("actor")
+		save, _ := cmd.Flags().GetBool("save")
+
+		tok, err := c.CreateToken(args[0], actor)
+		if err != nil {
+			return err
+		}
+
+		if save {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			cfg.CLI.Token = tok.Token
+			if err := saveConfig(cfg); err != nil {
+				return fmt.Errorf("save token: %w", err)
+			}
+		}
+
+		if outputJSON {
+			outputResult(tok)
+			return nil
+		}
+
+		fmt.Printf("Created token %s for actor %s\n", tok.ID, tok.Actor)
+		fmt.Printf("Token: %s\n", tok.Token)
+		if save {
+			fmt.Println("Saved as cli.token in the config file.")
+		} else {
+			fmt.Println("Store the token now; it will not be shown again.")
+		}
+		return nil
+	},
+}
+
+func init() {
+	tokenCreateCmd.Flags().String("actor", "", "Actor the token acts as (default: the caller's identity)")
+	tokenCreateCmd.Flags().Bool("save", false, "Save the token as cli.token so this CLI uses it")
+}
+
+// tokenRevokeCmd revokes a token; requests using it are rejected afterwards.
+var tokenRevokeCmd = &cobra.Command{
+	Use:   "revoke <token-id>",
+	Short: "Revoke an API token",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+
+		if err := c.RevokeToken(args[0]); err != nil {
+			return err
+		}
+
+		fmt.Printf("Revoked token: %s\n", args[0])
+		return nil
+	},
+}
+
+// lastUsedLabel formats a token's last use time for the list table.
+func lastUsedLabel(t *time.Time) string {
+	if t == nil {
+		return "never"
+	}
+	return t.Local().Format(time.DateTime)
+}
+
+// tokenStatusLabel reports whether a token is still usable.
+func tokenStatusLabel(tok *types.APIToken) string {
+	if tok.RevokedAt != nil {
+		return "revoked"
+	}
+	return "active"
+}
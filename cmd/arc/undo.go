@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/sentiolabs/arc/internal/client"
+	"github.com/sentiolabs/arc/internal/types"
+	"github.com/spf13/cobra"
+)
+
+var undoYes bool
+
+// undoCmd reverses recent changes in the current project.
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Undo recent changes",
+	Long: `Reverse the most recent changes in the current project using the audit
+trail: status changes, closes and reopens, label and dependency changes,
+and field updates. Comments, creations and deletions are not undone.
+
+The reversal is previewed and confirmed before anything is changed, unless
+--yes is given; only the previewed changes are then undone. Changes to an
+issue that has been modified again since are skipped. Each reversal is recorded as an "undone" event, so running undo
+again moves further back instead of undoing the undo.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+
+		wsID, err := getProjectID()
+		if err != nil {
+			return err
+		}
+
+		actor, _ := cmd.Flags().GetString("actor")
+		last, _ := cmd.Flags().GetInt("last")
+		req := client.UndoRequest{Actor: actor, Last: last, DryRun: !undoYes}
+
+		steps, err := c.Undo(wsID, req)
+		if err != nil {
+			return err
+		}
+		if outputJSON {
+			outputResult(steps)
+			return undoError(steps)
+		}
+		if len(steps) == 0 {
+			fmt.Println("Nothing to undo")
+			return nil
+		}
+		for _, step := range steps {
+			fmt.Println(formatUndoStep(step))
+		}
+		if undoYes || !hasUndoWork(steps) {
+			return undoError(steps)
+		}
+
+		_, _ = fmt.Fprint(os.Stderr, "\nApply? [y/N] ")
+		var response string
+		_, _ = fmt.Scanln(&response)
+		if response != "y" && response != "Y" {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+
+		// Apply exactly the previewed steps. The server checks them again,
+		// so anything changed since the preview is skipped rather than
+		// overwritten, and nothing the preview did not show is undone.
+		apply := client.UndoRequest{EventIDs: previewedEventIDs(steps)}
+		if steps, err = c.Undo(wsID, apply); err != nil {
+			return err
+		}
+		fmt.Println()
+		for _, step := range steps {
+			fmt.Println(formatUndoStep(step))
+		}
+		return undoError(steps)
+	},
+}
+
+func init() {
+	undoCmd.Flags().String("actor", "", "Only undo changes made by this actor")
+	undoCmd.Flags().IntP("last", "n", 1, "Number of changes to undo")
+	undoCmd.Flags().BoolVarP(&undoYes, "yes", "y", false, "Apply without asking for confirmation")
+	rootCmd.AddCommand(undoCmd)
+}
+
+// hasUndoWork reports whether any step would change something.
+func hasUndoWork(steps []*types.UndoStep) bool {
+	for _, step := range steps {
+		if step.Skipped == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// previewedEventIDs returns the events of the steps that were not skipped.
+func previewedEventIDs(steps []*types.UndoStep) []int64 {
+	var ids []int64
+	for _, step := range steps {
+		if step.Skipped == "" {
+			ids = append(ids, step.Event.ID)
+		}
+	}
+	return ids
+}
+
+// undoError returns an error when a step failed to apply.
+func undoError(steps []*types.UndoStep) error {
+	for _, step := range steps {
+		if step.Error != "" {
+			return errors.New("undo stopped at a failed step")
+		}
+	}
+	return nil
+}
+
+// formatUndoStep renders one undo step: the event it reverses, who made
+// that change, what will be or was done, and the outcome.
+func formatUndoStep(step *types.UndoStep) string {
+	line := fmt.Sprintf("#%-6d %s  %s",
+		step.Event.ID,
+		color.New(color.Faint).Sprintf("%-12s", step.Event.Actor),
+		step.Action)
+	switch {
+	case step.Error != "":
+		return color.New(color.FgRed).Sprint("✗ ") + line + color.New(color.FgRed).Sprintf("  (failed: %s)", step.Error)
+	case step.Applied:
+		return color.New(color.FgGreen).Sprint("✓ ") + line
+	case step.Skipped != "":
+		return "  " + line + color.New(color.FgYellow).Sprintf("  (skipped: %s)", step.Skipped)
+	}
+	return "  " + line
+}
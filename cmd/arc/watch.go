@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/sentiolabs/arc/internal/client"
+	"github.com/sentiolabs/arc/internal/events"
+	"github.com/sentiolabs/arc/internal/types"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// watchPollInterval is how often watchCmd polls for issue changes (and
+	// retries the event stream) after the stream drops.
+	watchPollInterval = 2 * time.Second
+	// watchPollLimit is the max number of issues fetched per project per poll.
+	watchPollLimit = 500
+	// watchDetailMaxRunes caps the detail column so long comments stay on one line.
+	watchDetailMaxRunes = 60
+)
+
+// Flags for watchCmd.
+var (
+	watchIssueID     string
+	watchTypes       []string
+	watchAllProjects bool
+)
+
+// watchCmd prints live changes from the server's event stream.
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Stream live changes to issues, plans and AI sessions",
+	Long: `Print changes as they happen, one line per event.
+
+By default only events for the current project are shown. Use --issue to
+follow a single issue, --type to select event types (repeatable or
+comma-separated; "issue.*" matches every issue event), and --all-projects to
+see everything. With --json each event is printed as one JSON object per line.
+
+If the event stream is unavailable, watch falls back to polling the issue
+list for changes and reconnects to the stream as soon as it can.`,
+	Args: cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		c, err := getClient()
+		if err != nil {
+			return err
+		}
+
+		opts := client.EventStreamOptions{IssueID: watchIssueID, Types: watchTypes}
+		if !watchAllProjects {
+			opts.ProjectID, err = getProjectID()
+			if err != nil {
+				return err
+			}
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+
+		w := &watcher{c: c, opts: opts, out: os.Stdout, errOut: os.Stderr, pollInterval: watchPollInterval}
+		return w.run(ctx)
+	},
+}
+
+func init() {
+	watchCmd.Flags().StringVar(&watchIssueID, "issue", "", "Only show events for this issue")
+	watchCmd.Flags().StringSliceVar(&watchTypes, "type", nil, "Only show these event types (e.g. issue.closed, plan.*)")
+	watchCmd.Flags().BoolVar(&watchAllProjects, "all-projects", false, "Show events from every project")
+	rootCmd.AddCommand(watchCmd)
+}
+
+// watcher streams events to out, falling back to polling when the stream
+// is unavailable.
+type watcher struct {
+	c            *client.Client
+	opts         client.EventStreamOptions
+	out          io.Writer
+	errOut       io.Writer
+	pollInterval time.Duration
+
+	// seen maps issue ID to the last observed updated_at while polling.
+	// nil means the next poll only records a baseline.
+	seen map[string]time.Time
+}
+
+// run watches until ctx is cancelled.
+func (w *watcher) run(ctx context.Context) error {
+	polling := false
+	for ctx.Err() == nil {
+		stream, err := w.c.OpenEventStream(ctx, w.opts)
+		if err == nil {
+			if polling {
+				fmt.Fprintln(w.errOut, "reconnected to event stream")
+				polling = false
+			}
+			err = w.consume(stream)
+			_ = stream.Close()
+			if ctx.Err() != nil {
+				break
+			}
+		}
+
+		if !polling {
+			fmt.Fprintf(w.errOut, "event stream unavailable (%v); polling every %s\n", err, w.pollInterval)
+			polling = true
+			w.seen = nil
+		}
+		if err := w.poll(); err != nil {
+			fmt.Fprintf(w.errOut, "poll failed: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(w.pollInterval):
+		}
+	}
+	return nil
+}
+
+// consume prints events until the stream ends.
+func (w *watcher) consume(stream *client.EventStream) error {
+	for {
+		ev, err := stream.Next()
+		if err != nil {
+			return err
+		}
+		w.print(*ev)
+	}
+}
+
+// poll lists issues in the watched projects and prints a synthesized
+// issue.created or issue.updated event for each one that changed since the
+// previous poll.
+func (w *watcher) poll() error {
+	projectIDs := []string{w.opts.ProjectID}
+	if w.opts.ProjectID == "" {
+		projects, err := w.c.ListProjects()
+		if err != nil {
+			return err
+		}
+		projectIDs = projectIDs[:0]
+		for _, p := range projects {
+			projectIDs = append(projectIDs, p.ID)
+		}
+	}
+
+	baseline := w.seen == nil
+	if baseline {
+		w.seen = make(map[string]time.Time)
+	}
+	filter := events.Filter{IssueID: w.opts.IssueID, Types: w.opts.Types}
+
+	for _, projID := range projectIDs {
+		issues, err := w.c.ListIssues(projID, client.ListIssuesOptions{Limit: watchPollLimit})
+		if err != nil {
+			return err
+		}
+		for _, issue := range issues {
+			prev, known := w.seen[issue.ID]
+			w.seen[issue.ID] = issue.UpdatedAt
+			if baseline || (known && !issue.UpdatedAt.After(prev)) {
+				continue
+			}
+			ev := types.StreamEvent{
+				Type:      types.StreamIssueUpdated,
+				ProjectID: issue.ProjectID,
+				IssueID:   issue.ID,
+				Data:      map[string]any{"status": string(issue.Status), "title": issue.Title},
+				Timestamp: issue.UpdatedAt,
+			}
+			if !known {
+				ev.Type = types.StreamIssueCreated
+			}
+			if filter.Match(ev) {
+				w.print(ev)
+			}
+		}
+	}
+	return nil
+}
+
+// print writes one event in the configured output format.
+func (w *watcher) print(ev types.StreamEvent) {
+	if outputJSON {
+		_ = json.NewEncoder(w.out).Encode(ev)
+		return
+	}
+	fmt.Fprintln(w.out, formatStreamEvent(ev))
+}
+
+// formatStreamEvent renders an event as a single human-readable line.
+func formatStreamEvent(ev types.StreamEvent) string {
+	var subject string
+	switch {
+	case ev.IssueID != "":
+		subject = ev.IssueID
+	case ev.PlanID != "":
+		subject = ev.PlanID
+	case ev.SessionID != "":
+		subject = ev.SessionID
+	}
+
+	parts := []string{
+		ev.Timestamp.Local().Format(time.TimeOnly),
+		color.New(color.FgCyan).Sprintf("%-20s", ev.Type),
+		subject,
+	}
+	if detail := streamEventDetail(ev); detail != "" {
+		parts = append(parts, detail)
+	}
+	if ev.Actor != "" {
+		parts = append(parts, color.New(color.Faint).Sprint("by "+ev.Actor))
+	}
+	return strings.Join(parts, "  ")
+}
+
+// streamEventDetail picks the most informative payload field for display.
+func streamEventDetail(ev types.StreamEvent) string {
+	for _, key := range []string{"new_value", "status", "old_value", "agent_id"} {
+		if v, ok := ev.Data[key]; ok {
+			if s := strings.TrimSpace(fmt.Sprint(v)); s != "" {
+				return truncateQuote(s, watchDetailMaxRunes)
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/sentiolabs/arc/internal/api"
+	"github.com/sentiolabs/arc/internal/client"
+	"github.com/sentiolabs/arc/internal/storage/sqlite"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func TestFormatStreamEvent(t *testing.T) {
+	origNoColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = origNoColor }()
+
+	ts := time.Date(2026, 3, 14, 9, 30, 0, 0, time.Local)
+	got := formatStreamEvent(types.StreamEvent{
+		Type:      types.StreamIssueClosed,
+		IssueID:   "arc-abc1",
+		Actor:     "alice",
+		Data:      map[string]any{"new_value": "shipped"},
+		Timestamp: ts,
+	})
+	want := "09:30:00  issue.closed          arc-abc1  shipped  by alice"
+	if got != want {
+		t.Errorf("formatStreamEvent:\n got %q\nwant %q", got, want)
+	}
+}
+
+// newWatchTestServer starts an API server whose event stream endpoint can be
+// disabled, to exercise the polling fallback.
+func newWatchTestServer(t *testing.T, streamDisabled bool) (*client.Client, func()) {
+	t.Helper()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	server := api.New(api.ServerOptions{Address: ":0", Store: store})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if streamDisabled && r.URL.Path == "/api/v1/events/stream" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		server.Echo().ServeHTTP(w, r)
+	}))
+	c := client.New(ts.URL)
+	c.SetActor("test-user")
+	return c, func() {
+		ts.Close()
+		_ = store.Close()
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes and reads.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func newSyncBuffer() *syncBuffer {
+	return &syncBuffer{}
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// waitForOutput polls buf until it contains want or the deadline passes.
+func waitForOutput(t *testing.T, buf *syncBuffer, want string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if strings.Contains(buf.String(), want) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %q in output:\n%s", want, buf.String())
+}
+
+func TestWatcherStreamsEvents(t *testing.T) {
+	c, cleanup := newWatchTestServer(t, false)
+	defer cleanup()
+
+	proj, err := c.CreateProject("watch", "wat", "")
+	if err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+
+	out, errOut := newSyncBuffer(), newSyncBuffer()
+	w := &watcher{
+		c:            c,
+		opts:         client.EventStreamOptions{ProjectID: proj.ID},
+		out:          out,
+		errOut:       errOut,
+		pollInterval: 10 * time.Millisecond,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.run(ctx) }()
+
+	// Retry creation until the watcher's stream has picked it up.
+	deadline := time.Now().Add(5 * time.Second)
+	var issue *types.Issue
+	for issue == nil || !strings.Contains(out.String(), issue.ID) {
+		if time.Now().After(deadline) {
+			t.Fatalf("no issue event printed; stdout:\n%s\nstderr:\n%s", out.String(), errOut.String())
+		}
+		if issue, err = c.CreateIssue(proj.ID, client.CreateIssueRequest{Title: "streamed"}); err != nil {
+			t.Fatalf("create issue: %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if !strings.Contains(out.String(), string(types.StreamIssueCreated)) {
+		t.Errorf("expected issue.created in output:\n%s", out.String())
+	}
+	if errOut.String() != "" {
+		t.Errorf("unexpected stderr: %s", errOut.String())
+	}
+}
+
+func TestWatcherFallsBackToPolling(t *testing.T) {
+	c, cleanup := newWatchTestServer(t, true)
+	defer cleanup()
+
+	proj, err := c.CreateProject("watch", "wat", "")
+	if err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	existing, err := c.CreateIssue(proj.ID, client.CreateIssueRequest{Title: "existing"})
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+
+	out, errOut := newSyncBuffer(), newSyncBuffer()
+	w := &watcher{
+		c:            c,
+		opts:         client.EventStreamOptions{ProjectID: proj.ID},
+		out:          out,
+		errOut:       errOut,
+		pollInterval: 10 * time.Millisecond,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- w.run(ctx) }()
+
+	waitForOutput(t, errOut, "polling every")
+	created, err := c.CreateIssue(proj.ID, client.CreateIssueRequest{Title: "new while polling"})
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	waitForOutput(t, out, created.ID)
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if strings.Contains(out.String(), existing.ID) {
+		t.Errorf("baseline issue %s should not be reported:\n%s", existing.ID, out.String())
+	}
+}
@@ -0,0 +1,245 @@
+// Webhook management commands for registering outbound webhooks on the
+// current project, sending test pings, and inspecting the delivery log.
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/client"
+	"github.com/sentiolabs/arc/internal/types"
+	"github.com/spf13/cobra"
+)
+
+// webhookErrorMaxRunes caps the error column in the deliveries table.
+const webhookErrorMaxRunes = 50
+
+// webhookCmd is the parent command for webhook management.
+// Subcommands: list, create, delete, test, deliveries.
+var webhookCmd = &cobra.Command{
+	Use:   "webhook",
+	Short: "Manage outbound webhooks",
+	Long: `Manage webhooks that receive an HTTP POST for every matching change in
+the current project.
+
+Each request carries a JSON payload with the event and a snapshot of the
+affected issue or plan, signed with HMAC-SHA256 of the raw body using the
+webhook secret:
+
+  X-Arc-Signature-256: sha256=<hex digest>
+  X-Arc-Event:         issue.closed
+  X-Arc-Delivery:      <delivery id>
+
+Failed deliveries are retried with exponential backoff.`,
+}
+
+func init() {
+	webhookCmd.AddCommand(webhookListCmd)
+	webhookCmd.AddCommand(webhookCreateCmd)
+	webhookCmd.AddCommand(webhookDeleteCmd)
+	webhookCmd.AddCommand(webhookTestCmd)
+	webhookCmd.AddCommand(webhookDeliveriesCmd)
+	rootCmd.AddCommand(webhookCmd)
+}
+
+// webhookListCmd lists the current project's webhooks.
+var webhookListCmd = &cobra.Command{
+	Use:   cmdList,
+	Short: "List webhooks",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, projID, err := webhookContext()
+		if err != nil {
+			return err
+		}
+
+		hooks, err := c.ListWebhooks(projID)
+		if err != nil {
+			return err
+		}
+
+		if outputJSON {
+			outputResult(hooks)
+			return nil
+		}
+
+		if len(hooks) == 0 {
+			fmt.Println("No webhooks found.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, tabwriterPadding, ' ', 0)
+		_, _ = fmt.Fprintln(w, "ID\tURL\tEVENTS\tACTIVE")
+		for _, wh := range hooks {
+			eventTypes := "*"
+			if len(wh.EventTypes) > 0 {
+				eventTypes = strings.Join(wh.EventTypes, ",")
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%t\n", wh.ID, wh.URL, eventTypes, wh.Active)
+		}
+		return w.Flush()
+	},
+}
+
+// webhookCreateCmd registers a webhook and prints its secret once.
+var webhookCreateCmd = &cobra.Command{
+	Use:   "create <url>",
+	Short: "Register a webhook",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, projID, err := webhookContext()
+		if err != nil {
+			return err
+		}
+
+		secret, _ := cmd.Flags().GetString("secret")
+		eventTypes, _ := cmd.Flags().GetStringSlice("event")
+
+		wh, err := c.CreateWebhook(projID, client.CreateWebhookOptions{
+			URL:        args[0],
+			Secret:     secret,
+			EventTypes: eventTypes,
+		})
+		if err != nil {
+			return err
+		}
+
+		if outputJSON {
+			outputResult(wh)
+			return nil
+		}
+
+		fmt.Printf("Created webhook: %s\n", wh.ID)
+		fmt.Printf("Secret: %s\n", wh.Secret)
+		fmt.Println("Store the secret now; it will not be shown again.")
+		return nil
+	},
+}
+
+func init() {
+	webhookCreateCmd.Flags().String("secret", "", "Signing secret (generated if omitted)")
+	webhookCreateCmd.Flags().StringSlice("event", nil,
+		"Only deliver these event types (repeatable or comma-separated, e.g. issue.closed, plan.*)")
+}
+
+// webhookDeleteCmd removes a webhook and its delivery log.
+var webhookDeleteCmd = &cobra.Command{
+	Use:   "delete <webhook-id>",
+	Short: "Delete a webhook",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, projID, err := webhookContext()
+		if err != nil {
+			return err
+		}
+
+		if err := c.DeleteWebhook(projID, args[0]); err != nil {
+			return err
+		}
+
+		fmt.Printf("Deleted webhook: %s\n", args[0])
+		return nil
+	},
+}
+
+// webhookTestCmd sends a webhook.ping delivery and reports the outcome.
+var webhookTestCmd = &cobra.Command{
+	Use:   "test <webhook-id>",
+	Short: "Send a test ping to a webhook",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, projID, err := webhookContext()
+		if err != nil {
+			return err
+		}
+
+		delivery, err := c.TestWebhook(projID, args[0])
+		if err != nil {
+			return err
+		}
+
+		if outputJSON {
+			outputResult(delivery)
+			return nil
+		}
+
+		if delivery.Status != types.DeliverySucceeded {
+			return fmt.Errorf("test delivery %d failed: %s", delivery.ID, delivery.LastError)
+		}
+		fmt.Printf("Test delivery %d succeeded (HTTP %d)\n", delivery.ID, *delivery.ResponseStatus)
+		return nil
+	},
+}
+
+// webhookDeliveriesCmd shows a webhook's delivery log, newest first.
+var webhookDeliveriesCmd = &cobra.Command{
+	Use:   "deliveries <webhook-id>",
+	Short: "Show a webhook's delivery log",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, projID, err := webhookContext()
+		if err != nil {
+			return err
+		}
+
+		limit, _ := cmd.Flags().GetInt("limit")
+		deliveries, err := c.ListWebhookDeliveries(projID, args[0], limit)
+		if err != nil {
+			return err
+		}
+
+		if outputJSON {
+			outputResult(deliveries)
+			return nil
+		}
+
+		if len(deliveries) == 0 {
+			fmt.Println("No deliveries found.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, tabwriterPadding, ' ', 0)
+		_, _ = fmt.Fprintln(w, "ID\tEVENT\tSTATUS\tATTEMPTS\tHTTP\tCREATED\tERROR")
+		for _, d := range deliveries {
+			_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%s\t%s\t%s\n",
+				d.ID, d.EventType, deliveryStatusLabel(d), d.Attempts, responseStatusLabel(d.ResponseStatus),
+				d.CreatedAt.Local().Format(time.DateTime), truncateQuote(d.LastError, webhookErrorMaxRunes))
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	webhookDeliveriesCmd.Flags().Int("limit", 0, "Maximum number of deliveries to show (default: server default)")
+}
+
+// webhookContext returns a client and the current project ID.
+func webhookContext() (*client.Client, string, error) {
+	c, err := getClient()
+	if err != nil {
+		return nil, "", err
+	}
+	projID, err := getProjectID()
+	if err != nil {
+		return nil, "", err
+	}
+	return c, projID, nil
+}
+
+// deliveryStatusLabel describes a delivery's status, including when a
+// pending delivery will be retried.
+func deliveryStatusLabel(d *types.WebhookDelivery) string {
+	if d.Status == types.DeliveryPending && d.Attempts > 0 {
+		return "retry at " + d.NextAttemptAt.Local().Format(time.TimeOnly)
+	}
+	return d.Status
+}
+
+func responseStatusLabel(status *int) string {
+	if status == nil {
+		return "-"
+	}
+	return fmt.Sprint(*status)
+}
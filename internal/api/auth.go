@@ -0,0 +1,145 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sentiolabs/arc/internal/auth"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// Context keys set by the authenticate middleware.
+const (
+	actorContextKey = "arc.actor"
+	tokenContextKey = "arc.token"
+)
+
+// touchInterval limits how often a token's last_used_at is written.
+const touchInterval = time.Minute
+
+// authenticator resolves bearer tokens to API tokens.
+type authenticator struct {
+	requireAuth bool
+
+	mu      sync.Mutex
+	touched map[string]time.Time
+	// loopback maps the hashes of in-memory tokens handed to MCP loopback
+	// clients to the callers they act for. They are never persisted.
+	loopback map[string]loopbackGrant
+}
+
+// loopbackGrant is the identity a loopback token authenticates as: the
+// actor of the MCP request that minted it, and the API token that request
+// carried.
+type loopbackGrant struct {
+	actor string
+	token *types.APIToken
+}
+
+// authenticate is the middleware guarding /api/v1 and /mcp. A valid bearer
+// token sets the request's actor. Requests without a token are rejected when
+// the server requires auth, and otherwise fall back to the X-Actor header.
+func (s *Server) authenticate(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		raw, ok := bearerToken(c.Request())
+		if !ok {
+			if s.auth.requireAuth {
+				return unauthorized(c, "authentication required")
+			}
+			return next(c)
+		}
+
+		hash := auth.HashToken(raw)
+		if grant, ok := s.auth.loopbackGrant(hash); ok {
+			c.Set(actorContextKey, grant.actor)
+			if grant.token != nil {
+				c.Set(tokenContextKey, grant.token)
+			}
+			return next(c)
+		}
+
+		ctx := c.Request().Context()
+		tok, err := s.store.GetAPITokenByHash(ctx, hash)
+		if err != nil || tok.RevokedAt != nil {
+			return unauthorized(c, "invalid or revoked token")
+		}
+
+		if s.auth.shouldTouch(tok.ID, time.Now()) {
+			if err := s.store.TouchAPIToken(ctx, tok.ID, time.Now()); err != nil {
+				log.Printf("auth: record token use: %v", err)
+			}
+		}
+
+		c.Set(actorContextKey, tok.Actor)
+		c.Set(tokenContextKey, tok)
+		return next(c)
+	}
+}
+
+// shouldTouch reports whether last_used_at for id is stale enough to rewrite.
+func (a *authenticator) shouldTouch(id string, now time.Time) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if last, ok := a.touched[id]; ok && now.Sub(last) < touchInterval {
+		return false
+	}
+	a.touched[id] = now
+	return true
+}
+
+// grantLoopback mints an in-memory token that authenticates as actor and
+// tok, for a loopback client acting on behalf of an authenticated caller.
+// The returned revoke func invalidates the token.
+func (a *authenticator) grantLoopback(actor string, tok *types.APIToken) (token string, revoke func(), err error) {
+	token, err = auth.GenerateToken()
+	if err != nil {
+		return "", nil, err
+	}
+	hash := auth.HashToken(token)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.loopback == nil {
+		a.loopback = make(map[string]loopbackGrant)
+	}
+	a.loopback[hash] = loopbackGrant{actor: actor, token: tok}
+	return token, func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		delete(a.loopback, hash)
+	}, nil
+}
+
+// loopbackGrant returns the identity of a live loopback token.
+func (a *authenticator) loopbackGrant(hash string) (loopbackGrant, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	grant, ok := a.loopback[hash]
+	return grant, ok
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer" header.
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get(echo.HeaderAuthorization)
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func unauthorized(c echo.Context, message string) error {
+	c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="arc"`)
+	return errorJSON(c, http.StatusUnauthorized, message)
+}
+
+// authToken returns the API token that authenticated the request, if any.
+func authToken(c echo.Context) *types.APIToken {
+	tok, _ := c.Get(tokenContextKey).(*types.APIToken)
+	return tok
+}
@@ -0,0 +1,142 @@
+package api //nolint:testpackage // tests use internal helpers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// doAuthRequest sends a JSON request with an optional bearer token.
+func doAuthRequest(e *echo.Echo, method, path, body, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if token != "" {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+// createTestToken issues a token through the API and returns it with its plaintext.
+func createTestToken(t *testing.T, e *echo.Echo, body, bearer string) *types.APIToken {
+	t.Helper()
+	rec := doAuthRequest(e, http.MethodPost, "/api/v1/tokens", body, bearer)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create token returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var tok types.APIToken
+	if err := json.Unmarshal(rec.Body.Bytes(), &tok); err != nil {
+		t.Fatalf("decode token: %v", err)
+	}
+	return &tok
+}
+
+func TestTokenSetsActor(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	e := server.echo
+	pID := createTestProject(t, e)
+
+	tok := createTestToken(t, e, `{"name": "laptop", "actor": "alice"}`, "")
+	if !strings.HasPrefix(tok.Token, "arc_") || tok.Prefix != tok.Token[:12] {
+		t.Fatalf("created token = %+v", tok)
+	}
+
+	// The token's actor wins over a spoofed X-Actor header.
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/projects/"+pID+"/issues",
+		strings.NewReader(`{"title": "Audited"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+tok.Token)
+	req.Header.Set("X-Actor", "mallory")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create issue returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var issue types.Issue
+	if err := json.Unmarshal(rec.Body.Bytes(), &issue); err != nil {
+		t.Fatalf("decode issue: %v", err)
+	}
+
+	evts, err := server.store.GetEvents(req.Context(), issue.ID, 0)
+	if err != nil || len(evts) == 0 {
+		t.Fatalf("GetEvents = %d, %v", len(evts), err)
+	}
+	if evts[0].Actor != "alice" {
+		t.Errorf("event actor = %q, want alice", evts[0].Actor)
+	}
+
+	stored, err := server.store.GetAPIToken(req.Context(), tok.ID)
+	if err != nil || stored.LastUsedAt == nil {
+		t.Errorf("token last_used_at not recorded: %+v, %v", stored, err)
+	}
+}
+
+func TestRequireAuth(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	e := server.echo
+
+	tok := createTestToken(t, e, `{"name": "ci", "actor": "ci-bot"}`, "")
+	server.auth.requireAuth = true
+
+	rec := doAuthRequest(e, http.MethodGet, "/api/v1/projects", "", "")
+	if rec.Code != http.StatusUnauthorized || rec.Header().Get(echo.HeaderWWWAuthenticate) == "" {
+		t.Errorf("unauthenticated request returned %d, want 401 with WWW-Authenticate", rec.Code)
+	}
+	if rec = doAuthRequest(e, http.MethodGet, "/health", "", ""); rec.Code != http.StatusOK {
+		t.Errorf("health returned %d, want 200 without a token", rec.Code)
+	}
+	if rec = doAuthRequest(e, http.MethodGet, "/api/v1/projects", "", "arc_bogus"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("bogus token returned %d, want 401", rec.Code)
+	}
+	if rec = doAuthRequest(e, http.MethodGet, "/api/v1/projects", "", tok.Token); rec.Code != http.StatusOK {
+		t.Errorf("valid token returned %d, want 200", rec.Code)
+	}
+
+	if rec = doAuthRequest(e, http.MethodDelete, "/api/v1/tokens/"+tok.ID, "", tok.Token); rec.Code != http.StatusNoContent {
+		t.Fatalf("revoke returned %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec = doAuthRequest(e, http.MethodGet, "/api/v1/projects", "", tok.Token); rec.Code != http.StatusUnauthorized {
+		t.Errorf("revoked token returned %d, want 401", rec.Code)
+	}
+}
+
+func TestTokenScoping(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	e := server.echo
+
+	alice := createTestToken(t, e, `{"name": "a", "actor": "alice"}`, "")
+	bob := createTestToken(t, e, `{"name": "b", "actor": "bob"}`, "")
+
+	// Authenticated callers see and manage only their own tokens.
+	rec := doAuthRequest(e, http.MethodGet, "/api/v1/tokens", "", alice.Token)
+	var tokens []types.APIToken
+	if err := json.Unmarshal(rec.Body.Bytes(), &tokens); err != nil {
+		t.Fatalf("decode tokens: %v", err)
+	}
+	if len(tokens) != 1 || tokens[0].ID != alice.ID || tokens[0].Token != "" {
+		t.Errorf("alice's tokens = %+v", tokens)
+	}
+
+	if rec = doAuthRequest(e, http.MethodDelete, "/api/v1/tokens/"+bob.ID, "", alice.Token); rec.Code != http.StatusForbidden {
+		t.Errorf("revoking another actor's token returned %d, want 403", rec.Code)
+	}
+	rec = doAuthRequest(e, http.MethodPost, "/api/v1/tokens", `{"name": "x", "actor": "bob"}`, alice.Token)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("creating a token for another actor returned %d, want 403", rec.Code)
+	}
+
+	// Omitting the actor issues a token for the caller.
+	second := createTestToken(t, e, `{"name": "second"}`, alice.Token)
+	if second.Actor != "alice" {
+		t.Errorf("token created without actor belongs to %q, want alice", second.Actor)
+	}
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sentiolabs/arc/internal/beads"
+)
+
+// importBeads adds the issues of a beads issues.jsonl export, sent as the
+// request body, to a project. Re-running with the same export only adds
+// what is new.
+func (s *Server) importBeads(c echo.Context) error {
+	ctx := c.Request().Context()
+	pID := projectID(c)
+	if _, err := s.store.GetProject(ctx, pID); err != nil {
+		return errorJSON(c, http.StatusNotFound, err.Error())
+	}
+
+	issues, err := beads.Read(c.Request().Body)
+	if err != nil {
+		return errorJSON(c, http.StatusBadRequest, "invalid beads export: "+err.Error())
+	}
+
+	result, err := beads.Import(ctx, s.store, pID, issues, getActor(c))
+	if err != nil {
+		return errorJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	return successJSON(c, result)
+}
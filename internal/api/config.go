@@ -24,9 +24,9 @@ type configValidationErrorBody struct {
 	Errors map[string]string `json:"errors"`
 }
 
-// TODO(security): require auth before binding non-loopback. The config
-// surface is currently safe only because the server is localhost-bound by
-// default.
+// The config surface is covered by the API token middleware; set
+// server.require_auth before binding non-loopback. cli.token is never
+// serialized, so PUT keeps the token already on disk.
 
 func (s *Server) getConfig(c echo.Context) error {
 	path := cfgpkg.DefaultPath()
@@ -53,6 +53,11 @@ func (s *Server) putConfig(c echo.Context) error {
 		return errorJSON(c, http.StatusBadRequest, err.Error())
 	}
 	path := cfgpkg.DefaultPath()
+	if current, err := cfgpkg.Load(path); err == nil {
+		incoming.CLI.Token = current.CLI.Token
+		incoming.Server.DBDriver = current.Server.DBDriver
+		incoming.Server.DSN = current.Server.DSN
+	}
 	if err := cfgpkg.Save(path, &incoming); err != nil {
 		return errorJSON(c, http.StatusInternalServerError, err.Error())
 	}
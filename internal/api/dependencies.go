@@ -5,13 +5,17 @@ import (
 	"net/http"
 
 	"github.com/labstack/echo/v4"
+	"github.com/sentiolabs/arc/internal/depgraph"
 	"github.com/sentiolabs/arc/internal/types"
 )
 
+// codeDependencyCycle is the error code returned when a dependency would close a cycle.
+const codeDependencyCycle = "dependency_cycle"
+
 // addDependencyRequest is the request body for adding a dependency.
 type addDependencyRequest struct {
 	DependsOnID string `json:"depends_on_id"`
-	Type        string `json:"type"` // blocks, parent-child, related, discovered-from
+	Type        string `json:"type"` // blocks, parent-child, related, discovered-from, duplicates
 }
 
 // getDependencies returns an issue's dependencies.
@@ -59,14 +63,26 @@ func (s *Server) addDependency(c echo.Context) error {
 	if err := c.Bind(&req); err != nil {
 		return errorJSON(c, http.StatusBadRequest, "invalid request body")
 	}
+	dependsOnID, err := s.store.ResolveIssueAlias(c.Request().Context(), req.DependsOnID)
+	if err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
 
 	dep := &types.Dependency{
 		IssueID:     id,
-		DependsOnID: req.DependsOnID,
+		DependsOnID: dependsOnID,
 		Type:        types.DependencyType(req.Type),
 	}
 
 	if err := s.store.AddDependency(c.Request().Context(), dep, actor); err != nil {
+		var cycleErr *types.DependencyCycleError
+		if errors.As(err, &cycleErr) {
+			return c.JSON(http.StatusConflict, map[string]any{
+				"error": cycleErr.Error(),
+				"code":  codeDependencyCycle,
+				"cycle": cycleErr.Path,
+			})
+		}
 		return errorJSON(c, http.StatusBadRequest, err.Error())
 	}
 
@@ -93,3 +109,111 @@ func (s *Server) removeDependency(c echo.Context) error {
 
 	return c.NoContent(http.StatusNoContent)
 }
+
+// validateDependencies audits the project's dependency graph for cycles,
+// dependencies reaching outside the project, and parent-child
+// dependencies that disagree with hierarchical IDs.
+func (s *Server) validateDependencies(c echo.Context) error {
+	ctx := c.Request().Context()
+	pID := projectID(c)
+
+	if _, err := s.store.GetProject(ctx, pID); err != nil {
+		return errorJSON(c, http.StatusNotFound, err.Error())
+	}
+
+	report, err := depgraph.Validate(ctx, s.store, pID)
+	if err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+	return successJSON(c, report)
+}
+
+// getGraph returns the project's dependency graph, or the part reachable
+// from the root query parameter. hide_closed leaves out closed issues and
+// depth limits how far from the root to go. format selects JSON (default),
+// Graphviz DOT or a Mermaid flowchart.
+func (s *Server) getGraph(c echo.Context) error {
+	ctx := c.Request().Context()
+	pID := projectID(c)
+
+	format := c.QueryParam("format")
+	switch format {
+	case "", depgraph.FormatJSON, depgraph.FormatDOT, depgraph.FormatMermaid:
+	default:
+		return errorJSON(c, http.StatusBadRequest, "format must be json, dot or mermaid")
+	}
+
+	if _, err := s.store.GetProject(ctx, pID); err != nil {
+		return errorJSON(c, http.StatusNotFound, err.Error())
+	}
+
+	opts := depgraph.GraphOptions{
+		RootID:     c.QueryParam("root"),
+		HideClosed: c.QueryParam("hide_closed") == queryTrue,
+		MaxDepth:   queryInt(c, "depth", 0),
+	}
+	if opts.RootID != "" {
+		if err := s.validateIssueProject(c, opts.RootID); err != nil {
+			if errors.Is(err, errProjectMismatch) {
+				return errorJSON(c, http.StatusForbidden, "access denied")
+			}
+			return errorJSON(c, http.StatusNotFound, err.Error())
+		}
+	}
+
+	g, err := depgraph.Build(ctx, s.store, pID, opts)
+	if err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+
+	switch format {
+	case depgraph.FormatDOT:
+		return c.String(http.StatusOK, depgraph.DOT(g))
+	case depgraph.FormatMermaid:
+		return c.String(http.StatusOK, depgraph.Mermaid(g))
+	}
+	return successJSON(c, g)
+}
+
+// getCriticalPath returns the longest chain of open issues gating an
+// issue's completion, through blocks dependencies and, for a parent, its
+// children.
+func (s *Server) getCriticalPath(c echo.Context) error {
+	id := c.Param("id")
+
+	// Validate issue belongs to project (security: prevents cross-project access)
+	issue, err := s.getIssueInProject(c, id)
+	if err != nil {
+		if errors.Is(err, errProjectMismatch) {
+			return errorJSON(c, http.StatusForbidden, "access denied")
+		}
+		return errorJSON(c, http.StatusNotFound, err.Error())
+	}
+
+	cp, err := depgraph.CriticalPath(c.Request().Context(), s.store, issue.ProjectID, id)
+	if err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+	return successJSON(c, cp)
+}
+
+// getUnblocks returns the open work waiting on an issue: what becomes
+// ready when it closes and everything that transitively waits on it.
+func (s *Server) getUnblocks(c echo.Context) error {
+	id := c.Param("id")
+
+	// Validate issue belongs to project (security: prevents cross-project access)
+	issue, err := s.getIssueInProject(c, id)
+	if err != nil {
+		if errors.Is(err, errProjectMismatch) {
+			return errorJSON(c, http.StatusForbidden, "access denied")
+		}
+		return errorJSON(c, http.StatusNotFound, err.Error())
+	}
+
+	u, err := depgraph.Unblocks(c.Request().Context(), s.store, issue.ProjectID, id)
+	if err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+	return successJSON(c, u)
+}
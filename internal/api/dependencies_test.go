@@ -0,0 +1,152 @@
+package api //nolint:testpackage // tests use internal helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func TestAddDependencyCycle409(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	e := server.echo
+
+	pID := createTestProject(t, e)
+	a := createTestIssue(t, e, pID, "A")
+	b := createTestIssue(t, e, pID, "B")
+	addTestDependency(t, e, pID, testDep{IssueID: b, DependsOnID: a, DepType: "blocks"})
+
+	body := fmt.Sprintf(`{"depends_on_id": %q, "type": "blocks"}`, b)
+	req := httptest.NewRequest(http.MethodPost,
+		fmt.Sprintf("/api/v1/projects/%s/issues/%s/deps", pID, a), bytes.NewBufferString(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Code  string   `json:"code"`
+		Cycle []string `json:"cycle"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.Code != codeDependencyCycle || !slices.Equal(resp.Cycle, []string{a, b, a}) {
+		t.Errorf("response = %+v, want %s with cycle %s -> %s -> %s", resp, codeDependencyCycle, a, b, a)
+	}
+}
+
+func TestValidateDependencies(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	e := server.echo
+
+	pID := createTestProject(t, e)
+	a := createTestIssue(t, e, pID, "A")
+	b := createTestIssue(t, e, pID, "B")
+	addTestDependency(t, e, pID, testDep{IssueID: b, DependsOnID: a, DepType: "blocks"})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/projects/"+pID+"/deps/validate", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var report types.DependencyReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("failed to parse report: %v", err)
+	}
+	if report.Issues != 2 || report.Dependencies != 1 || len(report.Problems) != 0 {
+		t.Errorf("report = %+v, want 2 issues, 1 dependency and no problems", report)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/projects/proj-missing/deps/validate", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown project: expected 404, got %d", rec.Code)
+	}
+}
+
+func TestGetGraph(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	e := server.echo
+
+	pID := createTestProject(t, e)
+	a := createTestIssue(t, e, pID, "A")
+	b := createTestIssue(t, e, pID, "B")
+	addTestDependency(t, e, pID, testDep{IssueID: b, DependsOnID: a, DepType: "blocks"})
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/projects/"+pID+"/graph"+query, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("?root=" + b)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var g types.Graph
+	if err := json.Unmarshal(rec.Body.Bytes(), &g); err != nil {
+		t.Fatalf("failed to parse graph: %v", err)
+	}
+	if len(g.Nodes) != 2 || len(g.Edges) != 1 || g.Edges[0].From != b || g.Edges[0].To != a {
+		t.Errorf("graph = %+v, want %s -> %s", g, b, a)
+	}
+
+	if rec := get("?format=mermaid"); rec.Code != http.StatusOK || !bytes.HasPrefix(rec.Body.Bytes(), []byte("flowchart LR\n")) {
+		t.Errorf("mermaid: got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := get("?format=png"); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown format: expected 400, got %d", rec.Code)
+	}
+}
+
+func TestCriticalPathAndUnblocks(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	e := server.echo
+
+	pID := createTestProject(t, e)
+	a := createTestIssue(t, e, pID, "A")
+	b := createTestIssue(t, e, pID, "B")
+	addTestDependency(t, e, pID, testDep{IssueID: b, DependsOnID: a, DepType: "blocks"})
+
+	get := func(path string, v any) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s returned %d: %s", path, rec.Code, rec.Body.String())
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+	}
+
+	var cp types.CriticalPath
+	get("/api/v1/issues/"+b+"/critical-path", &cp)
+	if len(cp.Path) != 2 || cp.Path[0].ID != a || cp.Path[1].ID != b {
+		t.Errorf("critical path = %+v, want %s then %s", cp.Path, a, b)
+	}
+
+	var u types.Unblocks
+	get(fmt.Sprintf("/api/v1/projects/%s/issues/%s/unblocks", pID, a), &u)
+	if len(u.Ready) != 1 || u.Ready[0].ID != b {
+		t.Errorf("unblocks = %+v, want %s ready", u, b)
+	}
+}
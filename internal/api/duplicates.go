@@ -0,0 +1,140 @@
+// Package api provides HTTP handlers for the arc REST API.
+// This file implements duplicate detection among a project's open issues.
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sentiolabs/arc/internal/dupes"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+const (
+	// duplicatePageSize is how many open issues are read per ListIssues call
+	// when building a duplicate index.
+	duplicatePageSize = 500
+
+	// candidateTermLimit caps how many issues one title term contributes as
+	// duplicate candidates, and candidateTerms how many terms are searched.
+	candidateTermLimit = 50
+	candidateTerms     = 10
+
+	// minCandidateTermLen skips short words such as "a" or "to", which match
+	// most issues without making any of them more likely duplicates.
+	minCandidateTermLen = 3
+)
+
+// openIssueIndex builds a duplicate index over every open issue in the
+// project. The caller must close it.
+func (s *Server) openIssueIndex(ctx context.Context, projectID string) (*dupes.Index, error) {
+	var open []*types.Issue
+	for offset := 0; ; offset += duplicatePageSize {
+		page, err := s.store.ListIssues(ctx, types.IssueFilter{
+			ProjectID:       projectID,
+			ExcludeStatuses: []types.Status{types.StatusClosed},
+			Limit:           duplicatePageSize,
+			Offset:          offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+		open = append(open, page...)
+		if len(page) < duplicatePageSize {
+			break
+		}
+	}
+	return dupes.NewIndex(open)
+}
+
+// duplicateCandidates returns the project's open issues sharing at least one
+// title term with title. The terms are looked up in the full-text index, so
+// checking a new issue scores a few candidates instead of the whole project.
+func (s *Server) duplicateCandidates(ctx context.Context, projectID, title string) ([]*types.Issue, error) {
+	seenTerms := make(map[string]bool)
+	seen := make(map[string]bool)
+	var candidates []*types.Issue
+	for _, word := range strings.Fields(strings.ToLower(title)) {
+		term := strings.TrimFunc(word, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+		if utf8.RuneCountInString(term) < minCandidateTermLen || seenTerms[term] {
+			continue
+		}
+		if len(seenTerms) == candidateTerms {
+			break
+		}
+		seenTerms[term] = true
+
+		matches, err := s.store.ListIssues(ctx, types.IssueFilter{
+			ProjectID:       projectID,
+			Query:           term,
+			ExcludeStatuses: []types.Status{types.StatusClosed},
+			Limit:           candidateTermLimit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range matches {
+			// Full-text queries may not apply the other filters.
+			if issue.Status == types.StatusClosed || seen[issue.ID] {
+				continue
+			}
+			seen[issue.ID] = true
+			candidates = append(candidates, issue)
+		}
+	}
+	return candidates, nil
+}
+
+// findDuplicates returns the project's open issues that an issue titled
+// title would likely duplicate, best match first.
+func (s *Server) findDuplicates(ctx context.Context, projectID, title string) ([]*types.DuplicateMatch, error) {
+	candidates, err := s.duplicateCandidates(ctx, projectID, title)
+	if err != nil || len(candidates) == 0 {
+		return nil, err
+	}
+
+	index, err := dupes.NewIndex(candidates)
+	if err != nil {
+		return nil, err
+	}
+	defer index.Close()
+	return index.Find(title, dupes.DefaultThreshold)
+}
+
+// listDuplicates groups the project's open issues that look like duplicates
+// of one another, most similar group first. The threshold query parameter,
+// between 0 and 1, overrides how similar two issues must be to be grouped.
+func (s *Server) listDuplicates(c echo.Context) error {
+	ctx := c.Request().Context()
+	pID := projectID(c)
+
+	threshold := dupes.DefaultThreshold
+	if raw := c.QueryParam("threshold"); raw != "" {
+		t, err := strconv.ParseFloat(raw, 64)
+		if err != nil || t <= 0 || t > 1 {
+			return errorJSON(c, http.StatusBadRequest, "threshold must be a number above 0 and at most 1")
+		}
+		threshold = t
+	}
+
+	if _, err := s.store.GetProject(ctx, pID); err != nil {
+		return errorJSON(c, http.StatusNotFound, err.Error())
+	}
+
+	index, err := s.openIssueIndex(ctx, pID)
+	if err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+	defer index.Close()
+
+	clusters, err := index.Clusters(threshold)
+	if err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+	return successJSON(c, clusters)
+}
@@ -0,0 +1,104 @@
+package api //nolint:testpackage // tests use internal helpers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func TestCreateIssueDuplicates(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	e := server.echo
+
+	pID := createTestProject(t, e)
+	original := createTestIssue(t, e, pID, "Login page crashes on Safari")
+	createTestIssue(t, e, pID, "Upgrade the database driver")
+	closed := createTestIssue(t, e, pID, "Add CSV export to reports")
+	if rec := doWebhookRequest(e, http.MethodPost, "/api/v1/projects/"+pID+"/issues/"+closed+"/close",
+		`{"reason": "done"}`); rec.Code != http.StatusOK {
+		t.Fatalf("close returned %d: %s", rec.Code, rec.Body.String())
+	}
+
+	create := func(body string) (int, *types.Issue, map[string]json.RawMessage) {
+		t.Helper()
+		rec := doWebhookRequest(e, http.MethodPost, "/api/v1/projects/"+pID+"/issues", body)
+		var raw map[string]json.RawMessage
+		if err := json.Unmarshal(rec.Body.Bytes(), &raw); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		var issue types.Issue
+		if rec.Code == http.StatusCreated {
+			if err := json.Unmarshal(rec.Body.Bytes(), &issue); err != nil {
+				t.Fatalf("decode issue: %v", err)
+			}
+		}
+		return rec.Code, &issue, raw
+	}
+
+	code, issue, _ := create(`{"title": "Loggin page crashes on safari"}`)
+	if code != http.StatusCreated {
+		t.Fatalf("create returned %d", code)
+	}
+	if len(issue.PossibleDuplicates) != 1 || issue.PossibleDuplicates[0].Issue.ID != original {
+		t.Fatalf("possible_duplicates = %+v, want only %s", issue.PossibleDuplicates, original)
+	}
+	if score := issue.PossibleDuplicates[0].Score; score <= 0 || score > 1 {
+		t.Errorf("score = %v, want a score in (0, 1]", score)
+	}
+
+	// Closed issues are not duplicates, and unrelated issues are created cleanly.
+	if code, issue, raw := create(`{"title": "Reports should export CSV", "no_duplicates": true}`); code != http.StatusCreated {
+		t.Errorf("create matching a closed issue returned %d", code)
+	} else if _, ok := raw["possible_duplicates"]; ok {
+		t.Errorf("unexpected possible_duplicates for %s: %+v", issue.ID, issue.PossibleDuplicates)
+	}
+
+	code, _, raw := create(`{"title": "Safari login page crashes", "no_duplicates": true}`)
+	if code != http.StatusConflict {
+		t.Fatalf("create with no_duplicates returned %d, want 409", code)
+	}
+	var refused []*types.DuplicateMatch
+	if err := json.Unmarshal(raw["possible_duplicates"], &refused); err != nil || len(refused) != 2 {
+		t.Fatalf("409 possible_duplicates = %s, want both login issues", raw["possible_duplicates"])
+	}
+	if string(raw["code"]) != `"`+codePossibleDuplicates+`"` {
+		t.Errorf("409 code = %s", raw["code"])
+	}
+}
+
+func TestListDuplicates(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	e := server.echo
+
+	pID := createTestProject(t, e)
+	first := createTestIssue(t, e, pID, "Fix flaky login test")
+	second := createTestIssue(t, e, pID, "Login test is flaky")
+	createTestIssue(t, e, pID, "Upgrade the database driver")
+
+	rec := doWebhookRequest(e, http.MethodGet, "/api/v1/projects/"+pID+"/duplicates", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("list duplicates returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var clusters []*types.DuplicateCluster
+	if err := json.Unmarshal(rec.Body.Bytes(), &clusters); err != nil {
+		t.Fatalf("decode clusters: %v", err)
+	}
+	if len(clusters) != 1 || len(clusters[0].Issues) != 2 ||
+		clusters[0].Issues[0].ID != first || clusters[0].Issues[1].ID != second {
+		t.Fatalf("clusters = %+v, want [%s %s]", clusters, first, second)
+	}
+
+	for _, threshold := range []string{"0", "1.5", "high"} {
+		rec := doWebhookRequest(e, http.MethodGet, "/api/v1/projects/"+pID+"/duplicates?threshold="+threshold, "")
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("threshold=%s returned %d, want 400", threshold, rec.Code)
+		}
+	}
+	if rec := doWebhookRequest(e, http.MethodGet, "/api/v1/projects/"+pID+"/duplicates?threshold=0.99", ""); rec.Body.String() != "[]\n" {
+		t.Errorf("threshold=0.99 returned %s, want no clusters", rec.Body.String())
+	}
+}
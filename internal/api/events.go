@@ -0,0 +1,112 @@
+// events.go implements the Server-Sent Events endpoint that streams live
+// change notifications (issues, dependencies, labels, comments, plans and
+// AI sessions) from the in-process event bus.
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sentiolabs/arc/internal/events"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// sseHeartbeatInterval is how often an idle stream sends a comment line so
+// proxies and clients can tell a quiet stream from a dead connection.
+const sseHeartbeatInterval = 15 * time.Second
+
+// sseRetryMillis is the reconnection delay suggested to EventSource clients.
+const sseRetryMillis = 2000
+
+// streamEvents streams change notifications as Server-Sent Events.
+// Query parameters (all optional): project_id, issue_id and type. type may be
+// repeated or comma-separated; entries ending in ".*" match by prefix.
+//
+// Each event is written with its sequence number as the SSE id, its type as
+// the SSE event name, and the JSON-encoded types.StreamEvent as data.
+func (s *Server) streamEvents(c echo.Context) error {
+	filter := events.Filter{
+		ProjectID: c.QueryParam("project_id"),
+		IssueID:   c.QueryParam("issue_id"),
+		Types:     splitQueryList(c.QueryParams()["type"]),
+	}
+
+	ctx := c.Request().Context()
+	actor := getActor(c)
+	if filter.ProjectID != "" {
+		if err := s.checkProjectRole(ctx, filter.ProjectID, actor, types.RoleViewer); err != nil {
+			return errorJSON(c, http.StatusForbidden, err.Error())
+		}
+	}
+
+	// Subscribe before sending headers so a client that has seen the
+	// response start cannot miss events published afterwards.
+	sub := s.events.Subscribe(filter, 0)
+	defer sub.Close()
+
+	w := c.Response()
+	w.Header().Set(echo.HeaderContentType, "text/event-stream")
+	w.Header().Set(echo.HeaderCacheControl, "no-cache")
+	w.Header().Set(echo.HeaderConnection, "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if _, err := fmt.Fprintf(w, "retry: %d\n: connected\n\n", sseRetryMillis); err != nil {
+		return nil //nolint:nilerr // client went away; nothing to report
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return nil //nolint:nilerr // client went away; nothing to report
+			}
+			w.Flush()
+		case ev, ok := <-sub.C:
+			if !ok {
+				// Dropped for falling behind; the client reconnects and resyncs.
+				return nil
+			}
+			if ev.ProjectID != "" && !s.canViewProject(ctx, ev.ProjectID, actor) {
+				continue
+			}
+			if err := writeSSE(w, ev); err != nil {
+				return nil //nolint:nilerr // client went away; nothing to report
+			}
+			w.Flush()
+		}
+	}
+}
+
+// writeSSE writes one event in text/event-stream framing.
+func writeSSE(w *echo.Response, ev types.StreamEvent) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.Seq, ev.Type, data)
+	return err
+}
+
+// splitQueryList flattens repeated and comma-separated query values,
+// dropping empty entries.
+func splitQueryList(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for part := range strings.SplitSeq(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
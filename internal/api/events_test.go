@@ -0,0 +1,90 @@
+package api //nolint:testpackage // tests use internal helpers that access unexported fields
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// readSSEEvent reads lines until a complete event with data arrives and
+// returns its event name and decoded payload.
+func readSSEEvent(t *testing.T, r *bufio.Reader) (string, types.StreamEvent) {
+	t.Helper()
+	var name, data string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read stream: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		case line == "" && data != "":
+			var ev types.StreamEvent
+			if err := json.Unmarshal([]byte(data), &ev); err != nil {
+				t.Fatalf("decode event data %q: %v", data, err)
+			}
+			return name, ev
+		}
+	}
+}
+
+func TestStreamEventsFiltersByProjectAndType(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	ts := httptest.NewServer(server.echo)
+	defer ts.Close()
+
+	pID := createTestProject(t, server.echo)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	url := ts.URL + "/api/v1/events/stream?project_id=" + pID + "&type=issue.created,label.*"
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("stream returned %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	// A plan change (filtered out by type) followed by an issue creation.
+	createTestPlan(t, server.echo)
+	issueID := createTestIssue(t, server.echo, pID, "Streamed")
+
+	name, ev := readSSEEvent(t, bufio.NewReader(resp.Body))
+	if name != string(types.StreamIssueCreated) {
+		t.Errorf("event name = %q, want %q", name, types.StreamIssueCreated)
+	}
+	if ev.IssueID != issueID || ev.ProjectID != pID {
+		t.Errorf("event = %+v, want issue %s in project %s", ev, issueID, pID)
+	}
+	if ev.Seq == 0 {
+		t.Error("expected non-zero sequence number")
+	}
+}
+
+func TestSplitQueryList(t *testing.T) {
+	got := splitQueryList([]string{"issue.created, plan.*", "", "label.added"})
+	want := []string{"issue.created", "plan.*", "label.added"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("splitQueryList = %v, want %v", got, want)
+	}
+}
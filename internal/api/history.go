@@ -0,0 +1,71 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sentiolabs/arc/internal/history"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// historyEventLimit asks for an issue's whole audit trail, which
+// reconstruction needs in full.
+const historyEventLimit = math.MaxInt32
+
+// getIssueHistory returns an issue as it was at the RFC 3339 timestamp in
+// the at query parameter (default now), together with the events recorded
+// up to that moment, oldest first.
+func (s *Server) getIssueHistory(c echo.Context) error {
+	id := c.Param("id")
+	ctx := c.Request().Context()
+
+	at := time.Now()
+	if raw := c.QueryParam("at"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			return errorJSON(c, http.StatusBadRequest, "at must be an RFC 3339 timestamp")
+		}
+		at = parsed
+	}
+
+	// Validate issue belongs to project (security: prevents cross-project access)
+	issue, err := s.getIssueInProject(c, id)
+	if err != nil {
+		if errors.Is(err, errProjectMismatch) {
+			return errorJSON(c, http.StatusForbidden, "access denied")
+		}
+		return errorJSON(c, http.StatusNotFound, err.Error())
+	}
+	if issue.Labels, err = s.store.GetIssueLabels(ctx, id); err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+	fields, err := s.store.GetFieldsForIssues(ctx, []string{id})
+	if err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+	issue.Fields = fields[id]
+
+	events, err := s.store.GetEvents(ctx, id, historyEventLimit)
+	if err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+	history.Sort(events)
+
+	past, err := history.At(issue, events, at)
+	if errors.Is(err, history.ErrNotCreated) {
+		return errorJSON(c, http.StatusNotFound, fmt.Sprintf("%s: %s", err, id))
+	}
+	if err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+
+	if n := slices.IndexFunc(events, func(e *types.Event) bool { return e.CreatedAt.After(at) }); n >= 0 {
+		events = events[:n]
+	}
+	return successJSON(c, &types.IssueHistory{Issue: past, At: at, Events: events})
+}
@@ -0,0 +1,67 @@
+package api //nolint:testpackage // tests use internal helpers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func TestIssueCustomFields(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	e := server.echo
+	pID := createTestProject(t, e)
+	config := "/api/v1/projects/" + pID + "/config"
+	issues := "/api/v1/projects/" + pID + "/issues"
+
+	rec := doWebhookRequest(e, http.MethodPut, config, `{"key": "field.component", "value": "enum:ui,api"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("define field returned %d: %s", rec.Code, rec.Body.String())
+	}
+	rec = doWebhookRequest(e, http.MethodPut, config, `{"key": "field.estimate", "value": "decimal"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid definition returned %d", rec.Code)
+	}
+
+	rec = doWebhookRequest(e, http.MethodPost, issues, `{"title": "Button", "fields": {"component": "ui"}}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var issue types.Issue
+	if err := json.Unmarshal(rec.Body.Bytes(), &issue); err != nil {
+		t.Fatalf("decode issue: %v", err)
+	}
+	rec = doWebhookRequest(e, http.MethodPost, issues, `{"title": "Other"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create returned %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = doWebhookRequest(e, http.MethodGet, issues+"?field.component=ui", "")
+	var page struct {
+		Data []*types.Issue `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if len(page.Data) != 1 || page.Data[0].ID != issue.ID || page.Data[0].Fields["component"] != "ui" {
+		t.Errorf("filtered list = %s", rec.Body.String())
+	}
+	rec = doWebhookRequest(e, http.MethodGet, issues+"?field.component=db", "")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid filter returned %d", rec.Code)
+	}
+
+	rec = doWebhookRequest(e, http.MethodPut, issues+"/"+issue.ID, `{"fields": {"component": "db"}}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid update returned %d: %s", rec.Code, rec.Body.String())
+	}
+	rec = doWebhookRequest(e, http.MethodPut, issues+"/"+issue.ID, `{"fields": {"component": "api"}}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("update returned %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &issue); err != nil || issue.Fields["component"] != "api" {
+		t.Errorf("updated issue = %+v, %v", issue, err)
+	}
+}
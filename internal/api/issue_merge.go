@@ -0,0 +1,63 @@
+// Package api provides HTTP handlers for the arc REST API.
+// This file implements merging a duplicate issue into its canonical issue.
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// mergeIssueRequest is the request body for merging an issue.
+type mergeIssueRequest struct {
+	Into string `json:"into"`
+}
+
+// mergeIssue merges the issue into the canonical issue named in the body.
+func (s *Server) mergeIssue(c echo.Context) error {
+	id := c.Param("id")
+	actor := getActor(c)
+
+	// Validate issue belongs to project (security: prevents cross-project access)
+	if err := s.validateIssueProject(c, id); err != nil {
+		if errors.Is(err, errProjectMismatch) {
+			return errorJSON(c, http.StatusForbidden, "access denied")
+		}
+		return errorJSON(c, http.StatusNotFound, err.Error())
+	}
+
+	var req mergeIssueRequest
+	if err := c.Bind(&req); err != nil {
+		return errorJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+	if req.Into == "" {
+		return errorJSON(c, http.StatusBadRequest, "into is required")
+	}
+
+	into, err := s.store.ResolveIssueAlias(c.Request().Context(), req.Into)
+	if err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+
+	result, err := s.store.MergeIssue(c.Request().Context(), id, into, actor)
+	if err != nil {
+		var cycleErr *types.DependencyCycleError
+		if errors.As(err, &cycleErr) {
+			return c.JSON(http.StatusConflict, map[string]any{
+				"error": cycleErr.Error(),
+				"code":  codeDependencyCycle,
+				"cycle": cycleErr.Path,
+			})
+		}
+		errMsg := err.Error()
+		if strings.Contains(errMsg, "not found") {
+			return errorJSON(c, http.StatusNotFound, errMsg)
+		}
+		return errorJSON(c, http.StatusBadRequest, errMsg)
+	}
+
+	return successJSON(c, result)
+}
@@ -0,0 +1,86 @@
+package api //nolint:testpackage // tests use internal helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func TestMergeIssue(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	e := server.echo
+
+	pID := createTestProject(t, e)
+	canonical := createTestIssue(t, e, pID, "Login crashes on Safari")
+	dup := createTestIssue(t, e, pID, "Safari login crash")
+	base := "/api/v1/projects/" + pID + "/issues/"
+
+	if rec := doWebhookRequest(e, http.MethodPost, base+dup+"/merge", `{}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("merge without into returned %d, want 400", rec.Code)
+	}
+	if rec := doWebhookRequest(e, http.MethodPost, base+dup+"/merge", `{"into": "`+dup+`"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("merge into itself returned %d, want 400", rec.Code)
+	}
+	if rec := doWebhookRequest(e, http.MethodPost, base+dup+"/merge", `{"into": "missing"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("merge into a missing issue returned %d, want 404", rec.Code)
+	}
+
+	rec := doWebhookRequest(e, http.MethodPost, base+dup+"/merge", `{"into": "`+canonical+`"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("merge returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var result types.IssueMergeResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatalf("decode result: %v", err)
+	}
+	if result.Canonical.ID != canonical || result.Duplicate.ID != dup ||
+		result.Duplicate.CloseReason != types.CloseReasonDuplicate {
+		t.Errorf("result = %+v, want %s merged into %s", result, dup, canonical)
+	}
+
+	// Undo passes over the duplicate's close instead of failing the plan.
+	rec = doWebhookRequest(e, http.MethodPost, "/api/v1/projects/"+pID+"/undo", `{"dry_run": true}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("undo returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var steps []*types.UndoStep
+	if err := json.Unmarshal(rec.Body.Bytes(), &steps); err != nil {
+		t.Fatalf("decode steps: %v", err)
+	}
+	if want := "issue " + dup + " has been merged into " + canonical; len(steps) != 1 || steps[0].Skipped != want {
+		t.Errorf("undo steps = %+v, want the close of %s skipped as %q", steps, dup, want)
+	}
+
+	// The duplicate's ID now resolves to the canonical issue.
+	rec = doWebhookRequest(e, http.MethodGet, "/api/v1/issues/"+dup, "")
+	var issue types.Issue
+	if err := json.Unmarshal(rec.Body.Bytes(), &issue); err != nil {
+		t.Fatalf("decode issue: %v", err)
+	}
+	if issue.ID != canonical {
+		t.Errorf("GET %s returned %s, want %s", dup, issue.ID, canonical)
+	}
+
+	// Writes through the duplicate's ID reach the canonical issue too, so
+	// the ETag read above is the one the update is checked against.
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/issues/"+dup, bytes.NewBufferString(`{"priority": 0}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("If-Match", rec.Header().Get("ETag"))
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PUT %s with the ETag from GET returned %d: %s", dup, rec.Code, rec.Body.String())
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &issue); err != nil {
+		t.Fatalf("decode issue: %v", err)
+	}
+	if issue.ID != canonical || issue.Priority != 0 {
+		t.Errorf("PUT %s returned %s with priority %d, want %s with priority 0", dup, issue.ID, issue.Priority, canonical)
+	}
+}
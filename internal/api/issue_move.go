@@ -0,0 +1,87 @@
+// Package api provides HTTP handlers for the arc REST API.
+// This file implements moving issues between projects and resolving the
+// IDs they left behind.
+package api
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// aliasParams lists the path parameters of issue routes that name issues.
+var aliasParams = []string{"id", "dep"}
+
+// moveIssueRequest is the request body for moving an issue.
+type moveIssueRequest struct {
+	ProjectID string `json:"project_id"`
+}
+
+// resolveIssueAlias is route middleware that rewrites issue IDs in the path
+// of issue routes to their current IDs, so an ID an issue had before it was
+// moved keeps working everywhere the current one does, and reads and writes
+// of a merged duplicate both reach the issue it was merged into.
+func (s *Server) resolveIssueAlias(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if !strings.Contains(c.Path(), "/issues/:id") {
+			return next(c)
+		}
+		values := append([]string(nil), c.ParamValues()...)
+		for i, name := range c.ParamNames() {
+			if i >= len(values) || values[i] == "" || !slices.Contains(aliasParams, name) {
+				continue
+			}
+			current, err := s.store.ResolveIssueAlias(c.Request().Context(), values[i])
+			if err != nil {
+				return errorJSON(c, http.StatusInternalServerError, err.Error())
+			}
+			values[i] = current
+		}
+		c.SetParamValues(values...)
+		return next(c)
+	}
+}
+
+// moveIssue moves the issue and its descendants to the project named in
+// the body.
+func (s *Server) moveIssue(c echo.Context) error {
+	id := c.Param("id")
+	actor := getActor(c)
+
+	// Validate issue belongs to project (security: prevents cross-project access)
+	if err := s.validateIssueProject(c, id); err != nil {
+		if errors.Is(err, errProjectMismatch) {
+			return errorJSON(c, http.StatusForbidden, "access denied")
+		}
+		return errorJSON(c, http.StatusNotFound, err.Error())
+	}
+
+	var req moveIssueRequest
+	if err := c.Bind(&req); err != nil {
+		return errorJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+	if req.ProjectID == "" {
+		return errorJSON(c, http.StatusBadRequest, "project_id is required")
+	}
+
+	// The issues land in the target project, so the caller must be able to
+	// contribute there as well.
+	if err := s.checkProjectRole(c.Request().Context(), req.ProjectID, actor, types.RoleContributor); err != nil {
+		return errorJSON(c, http.StatusForbidden, err.Error())
+	}
+
+	result, err := s.store.MoveIssue(c.Request().Context(), id, req.ProjectID, actor)
+	if err != nil {
+		errMsg := err.Error()
+		if strings.Contains(errMsg, "not found") {
+			return errorJSON(c, http.StatusNotFound, errMsg)
+		}
+		return errorJSON(c, http.StatusBadRequest, errMsg)
+	}
+
+	return successJSON(c, result)
+}
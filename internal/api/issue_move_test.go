@@ -0,0 +1,70 @@
+package api //nolint:testpackage // tests use internal helpers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func TestMoveIssue(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	e := server.echo
+
+	src := createTestProject(t, e)
+	rec := doWebhookRequest(e, http.MethodPost, "/api/v1/projects", `{"name": "Web", "prefix": "web"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create project returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var dst types.Project
+	if err := json.Unmarshal(rec.Body.Bytes(), &dst); err != nil {
+		t.Fatalf("decode project: %v", err)
+	}
+	issue := createTestIssue(t, e, src, "Fix the landing page")
+	other := createTestIssue(t, e, src, "Write release notes")
+
+	if rec := doWebhookRequest(e, http.MethodPost, "/api/v1/issues/"+issue+"/move", `{}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("move without project_id returned %d, want 400", rec.Code)
+	}
+	if rec := doWebhookRequest(e, http.MethodPost, "/api/v1/issues/"+issue+"/move",
+		`{"project_id": "`+src+`"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("move into the same project returned %d, want 400", rec.Code)
+	}
+
+	rec = doWebhookRequest(e, http.MethodPost, "/api/v1/issues/"+issue+"/move", `{"project_id": "`+dst.ID+`"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("move returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var result types.IssueMoveResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatalf("decode result: %v", err)
+	}
+	moved := result.Issue.ID
+	if !strings.HasPrefix(moved, "web.") || result.IDMap[issue] != moved || result.Issue.ProjectID != dst.ID {
+		t.Fatalf("result = %+v, want %s moved under the web prefix", result, issue)
+	}
+
+	// The old ID keeps working, in paths and in request bodies.
+	rec = doWebhookRequest(e, http.MethodGet, "/api/v1/issues/"+issue, "")
+	var got types.Issue
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil || got.ID != moved {
+		t.Errorf("GET %s returned %d %s, want %s", issue, rec.Code, rec.Body.String(), moved)
+	}
+	if rec := doWebhookRequest(e, http.MethodPut, "/api/v1/issues/"+issue, `{"priority": 1}`); rec.Code != http.StatusOK {
+		t.Errorf("update by old ID returned %d: %s", rec.Code, rec.Body.String())
+	}
+	rec = doWebhookRequest(e, http.MethodPost, "/api/v1/issues/"+other+"/deps",
+		`{"depends_on_id": "`+issue+`", "type": "related"}`)
+	var dep types.Dependency
+	if err := json.Unmarshal(rec.Body.Bytes(), &dep); err != nil || dep.DependsOnID != moved {
+		t.Errorf("dependency on old ID returned %d %s, want it on %s", rec.Code, rec.Body.String(), moved)
+	}
+
+	// Through the old project the moved issue is out of reach.
+	if rec := doWebhookRequest(e, http.MethodGet, "/api/v1/projects/"+src+"/issues/"+issue, ""); rec.Code != http.StatusForbidden {
+		t.Errorf("GET through the old project returned %d, want 403", rec.Code)
+	}
+}
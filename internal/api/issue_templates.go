@@ -0,0 +1,150 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sentiolabs/arc/internal/issuetmpl"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// saveTemplateRequest is the request body for storing a template.
+type saveTemplateRequest struct {
+	Content string `json:"content"`
+}
+
+// instantiateTemplateRequest is the request body for creating issues from
+// a template. Content, when set, is used instead of the stored template,
+// which lets clients instantiate templates kept in their repository.
+type instantiateTemplateRequest struct {
+	Content  string            `json:"content,omitempty"`
+	Vars     map[string]string `json:"vars,omitempty"`
+	ParentID string            `json:"parent_id,omitempty"`
+}
+
+// listIssueTemplates returns a project's stored templates.
+func (s *Server) listIssueTemplates(c echo.Context) error {
+	pID := projectID(c)
+	if _, err := s.store.GetProject(c.Request().Context(), pID); err != nil {
+		return errorJSON(c, http.StatusNotFound, err.Error())
+	}
+
+	templates, err := s.store.ListIssueTemplates(c.Request().Context(), pID)
+	if err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return successJSON(c, templates)
+}
+
+// getIssueTemplate returns a stored template.
+func (s *Server) getIssueTemplate(c echo.Context) error {
+	t, err := s.store.GetIssueTemplate(c.Request().Context(), projectID(c), c.Param("name"))
+	if err != nil {
+		return errorJSON(c, http.StatusNotFound, err.Error())
+	}
+
+	return successJSON(c, t)
+}
+
+// saveIssueTemplate creates or replaces a stored template. A recurring
+// template's next run is scheduled from now.
+func (s *Server) saveIssueTemplate(c echo.Context) error {
+	ctx := c.Request().Context()
+	pID := projectID(c)
+	if _, err := s.store.GetProject(ctx, pID); err != nil {
+		return errorJSON(c, http.StatusNotFound, err.Error())
+	}
+
+	name := c.Param("name")
+	if !issuetmpl.ValidName(name) {
+		return errorJSON(c, http.StatusBadRequest, "invalid template name: "+name)
+	}
+	var req saveTemplateRequest
+	if err := c.Bind(&req); err != nil {
+		return errorJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+	parsed, err := issuetmpl.Parse([]byte(req.Content))
+	if err != nil {
+		return errorJSON(c, http.StatusBadRequest, err.Error())
+	}
+	next, err := issuetmpl.NextRun(parsed.Recurrence, time.Now())
+	if err != nil {
+		return errorJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	t := &types.IssueTemplate{
+		ProjectID:  pID,
+		Name:       name,
+		Content:    req.Content,
+		Recurrence: parsed.Recurrence,
+		NextRunAt:  next,
+	}
+	if err := s.store.SaveIssueTemplate(ctx, t); err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return successJSON(c, t)
+}
+
+// deleteIssueTemplate removes a stored template.
+func (s *Server) deleteIssueTemplate(c echo.Context) error {
+	if err := s.store.DeleteIssueTemplate(c.Request().Context(), projectID(c), c.Param("name")); err != nil {
+		return errorJSON(c, http.StatusNotFound, err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// instantiateIssueTemplate creates the issues described by a template.
+func (s *Server) instantiateIssueTemplate(c echo.Context) error {
+	ctx := c.Request().Context()
+	pID := projectID(c)
+	name := c.Param("name")
+	if _, err := s.store.GetProject(ctx, pID); err != nil {
+		return errorJSON(c, http.StatusNotFound, err.Error())
+	}
+
+	var req instantiateTemplateRequest
+	if err := c.Bind(&req); err != nil {
+		return errorJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	content := req.Content
+	if content == "" {
+		stored, err := s.store.GetIssueTemplate(ctx, pID, name)
+		if err != nil {
+			return errorJSON(c, http.StatusNotFound, err.Error())
+		}
+		content = stored.Content
+	}
+	t, err := issuetmpl.Parse([]byte(content))
+	if err != nil {
+		return errorJSON(c, http.StatusBadRequest, err.Error())
+	}
+	if req.ParentID != "" {
+		parent, err := s.store.GetIssue(ctx, req.ParentID)
+		if err != nil || parent.ProjectID != pID {
+			return errorJSON(c, http.StatusBadRequest, "parent issue not found in project: "+req.ParentID)
+		}
+	}
+
+	inst, err := issuetmpl.Instantiate(ctx, s.store, t, issuetmpl.Options{
+		ProjectID: pID,
+		Name:      name,
+		Vars:      req.Vars,
+		ParentID:  req.ParentID,
+		Actor:     getActor(c),
+	})
+	if err != nil {
+		// Issues already created make this a server-side failure rather
+		// than a bad template.
+		if inst != nil && len(inst.IssueIDs) > 0 {
+			return errorJSON(c, http.StatusInternalServerError, err.Error())
+		}
+		return errorJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	return createdJSON(c, inst)
+}
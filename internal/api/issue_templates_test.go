@@ -0,0 +1,77 @@
+package api //nolint:testpackage // tests use internal helpers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func TestIssueTemplateLifecycle(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	e := server.echo
+	pID := createTestProject(t, e)
+	base := "/api/v1/projects/" + pID + "/templates"
+
+	content := "recurrence: '@weekly'\\nissue:\\n  title: Release {{version}}\\n" +
+		"  children:\\n    - {key: a, title: Build}\\n    - {key: b, title: Ship, depends_on: [a]}\\n"
+	rec := doWebhookRequest(e, http.MethodPut, base+"/release", `{"content": "`+content+`"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("save template returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var saved types.IssueTemplate
+	if err := json.Unmarshal(rec.Body.Bytes(), &saved); err != nil {
+		t.Fatalf("decode template: %v", err)
+	}
+	if saved.Recurrence != "@weekly" || saved.NextRunAt == nil {
+		t.Errorf("saved template = %+v", saved)
+	}
+
+	rec = doWebhookRequest(e, http.MethodPut, base+"/broken", `{"content": "issue: {}"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid template returned %d", rec.Code)
+	}
+
+	rec = doWebhookRequest(e, http.MethodPost, base+"/release/instantiate", `{}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("instantiate without vars returned %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = doWebhookRequest(e, http.MethodPost, base+"/release/instantiate", `{"vars": {"version": "1.4"}}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("instantiate returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var inst types.TemplateInstance
+	if err := json.Unmarshal(rec.Body.Bytes(), &inst); err != nil {
+		t.Fatalf("decode instance: %v", err)
+	}
+	if len(inst.IssueIDs) != 3 || inst.IssueIDs[2] != inst.RootID+".2" {
+		t.Errorf("instance = %+v", inst)
+	}
+	root, _ := server.store.GetIssue(t.Context(), inst.RootID)
+	if root == nil || root.Title != "Release 1.4" {
+		t.Errorf("root = %+v", root)
+	}
+
+	// Inline content is used instead of a stored template.
+	rec = doWebhookRequest(e, http.MethodPost, base+"/local/instantiate", `{"content": "issue:\n  title: Local"}`)
+	if rec.Code != http.StatusCreated {
+		t.Errorf("inline instantiate returned %d: %s", rec.Code, rec.Body.String())
+	}
+	rec = doWebhookRequest(e, http.MethodPost, base+"/missing/instantiate", `{}`)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("missing template returned %d", rec.Code)
+	}
+
+	rec = doWebhookRequest(e, http.MethodDelete, base+"/release", "")
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("delete returned %d", rec.Code)
+	}
+	rec = doWebhookRequest(e, http.MethodGet, base, "")
+	var list []types.IssueTemplate
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil || len(list) != 0 {
+		t.Errorf("templates after delete = %+v, %v", list, err)
+	}
+}
@@ -2,10 +2,14 @@ package api
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/sentiolabs/arc/internal/issuequery"
 	"github.com/sentiolabs/arc/internal/types"
 )
 
@@ -18,6 +22,12 @@ const (
 	queryTrue = "true"
 	// codeOpenChildren is the error code returned when an issue has open children.
 	codeOpenChildren = "open_children"
+	// codePossibleDuplicates is the error code returned when no_duplicates refuses a create.
+	codePossibleDuplicates = "possible_duplicates"
+	// codeVersionConflict is the error code returned when If-Match names a stale version.
+	codeVersionConflict = "version_conflict"
+	// fieldQueryPrefix prefixes custom field filters in list queries (?field.component=ui).
+	fieldQueryPrefix = "field."
 )
 
 // createIssueRequest is the request body for creating an issue.
@@ -30,6 +40,10 @@ type createIssueRequest struct {
 	AISessionID string `json:"ai_session_id,omitempty"`
 	ExternalRef string `json:"external_ref,omitempty"`
 	ParentID    string `json:"parent_id,omitempty"` // For hierarchical child IDs
+
+	Fields map[string]string `json:"fields,omitempty"` // Custom field values
+
+	NoDuplicates bool `json:"no_duplicates,omitempty"` // Refuse to create a likely duplicate
 }
 
 // updateIssueRequest is the request body for updating an issue.
@@ -38,9 +52,13 @@ type updateIssueRequest struct {
 	Description *string `json:"description,omitempty"`
 	Status      *string `json:"status,omitempty"`
 	Priority    *int    `json:"priority,omitempty"`
+	Rank        *int    `json:"rank,omitempty"`
 	IssueType   *string `json:"issue_type,omitempty"`
 	AISessionID *string `json:"ai_session_id,omitempty"`
 	ExternalRef *string `json:"external_ref,omitempty"`
+
+	// Fields sets custom field values; an empty value removes the field.
+	Fields map[string]string `json:"fields,omitempty"`
 }
 
 // closeIssueRequest is the request body for closing an issue.
@@ -50,8 +68,9 @@ type closeIssueRequest struct {
 }
 
 // listIssues returns issues for a project with optional filtering and pagination.
-// Supports filtering by status, type, priority, parent_id, and free-text query.
-// Results include batch-fetched labels for each issue.
+// Supports filtering by status, type, priority, parent_id, custom fields
+// (field.<key>=value), and free-text query.
+// Results include batch-fetched labels and custom fields for each issue.
 func (s *Server) listIssues(c echo.Context) error {
 	pID := projectID(c)
 
@@ -59,17 +78,25 @@ func (s *Server) listIssues(c echo.Context) error {
 		ProjectID: pID,
 		Limit:     queryInt(c, "limit", defaultListLimit),
 		Offset:    queryInt(c, "offset", 0),
-		Query:     c.QueryParam("q"),
 	}
 
 	parseIssueFilterParams(c, &filter)
+	if q := c.QueryParam("q"); q != "" {
+		var err error
+		if filter, err = s.applyIssueQuery(c, filter, q); err != nil {
+			return errorJSON(c, http.StatusBadRequest, err.Error())
+		}
+	}
 
 	issues, err := s.store.ListIssues(c.Request().Context(), filter)
 	if err != nil {
+		if errors.Is(err, types.ErrInvalidField) {
+			return errorJSON(c, http.StatusBadRequest, err.Error())
+		}
 		return errorJSON(c, http.StatusInternalServerError, err.Error())
 	}
 
-	// Fetch labels for all issues in batch
+	// Fetch labels and custom fields for all issues in batch
 	if len(issues) > 0 {
 		issueIDs := make([]string, len(issues))
 		for i, issue := range issues {
@@ -82,11 +109,42 @@ func (s *Server) listIssues(c echo.Context) error {
 				issue.Labels = labelsMap[issue.ID]
 			}
 		}
+		fieldsMap, err := s.store.GetFieldsForIssues(c.Request().Context(), issueIDs)
+		if err == nil {
+			for _, issue := range issues {
+				issue.Fields = fieldsMap[issue.ID]
+			}
+		}
 	}
 
 	return paginatedJSON(c, issues, len(issues), filter.Limit, filter.Offset)
 }
 
+// applyIssueQuery narrows filter by a query in the issuequery syntax,
+// expanding a saved @name query from the project config. A query of plain
+// words stays a full-text search.
+func (s *Server) applyIssueQuery(c echo.Context, filter types.IssueFilter, q string) (types.IssueFilter, error) {
+	if strings.HasPrefix(strings.TrimSpace(q), "@") {
+		saved, err := s.store.GetProjectConfig(c.Request().Context(), filter.ProjectID)
+		if err != nil {
+			return filter, err
+		}
+		if q, err = issuequery.Expand(q, saved); err != nil {
+			return filter, err
+		}
+	}
+
+	parsed, err := issuequery.Parse(q, time.Now())
+	if err != nil {
+		return filter, fmt.Errorf("invalid query: %w", err)
+	}
+	if issuequery.IsText(parsed) {
+		filter.Query = q
+		return filter, nil
+	}
+	return issuequery.Merge(filter, parsed), nil
+}
+
 // parseIssueFilterParams extracts optional filter params from query string into the filter.
 // Supports repeated params for multi-select (e.g. ?status=open&status=blocked).
 func parseIssueFilterParams(c echo.Context, filter *types.IssueFilter) {
@@ -108,10 +166,21 @@ func parseIssueFilterParams(c echo.Context, filter *types.IssueFilter) {
 	if parentID := c.QueryParam("parent_id"); parentID != "" {
 		filter.ParentID = parentID
 	}
+	filter.Labels = append(filter.Labels, c.QueryParams()["label"]...)
+	for name, values := range c.QueryParams() {
+		if key, ok := strings.CutPrefix(name, fieldQueryPrefix); ok && len(values) > 0 {
+			if filter.Fields == nil {
+				filter.Fields = make(map[string]string)
+			}
+			filter.Fields[key] = values[0]
+		}
+	}
 }
 
 // createIssue creates a new issue in the specified project.
 // The issue type, priority, and other fields are set from the request body.
+// Open issues with a similar title are returned in possible_duplicates; with
+// no_duplicates set, finding any refuses the create with 409 instead.
 func (s *Server) createIssue(c echo.Context) error {
 	pID := projectID(c)
 	actor := getActor(c)
@@ -121,6 +190,23 @@ func (s *Server) createIssue(c echo.Context) error {
 		return errorJSON(c, http.StatusBadRequest, "invalid request body")
 	}
 
+	var duplicates []*types.DuplicateMatch
+	if strings.TrimSpace(req.Title) != "" {
+		var err error
+		duplicates, err = s.findDuplicates(c.Request().Context(), pID, req.Title)
+		if err != nil {
+			return errorJSON(c, http.StatusInternalServerError, err.Error())
+		}
+		if req.NoDuplicates && len(duplicates) > 0 {
+			dupErr := &types.DuplicateIssueError{Title: req.Title, Matches: duplicates}
+			return c.JSON(http.StatusConflict, map[string]any{
+				"error":                dupErr.Error(),
+				"code":                 codePossibleDuplicates,
+				codePossibleDuplicates: duplicates,
+			})
+		}
+	}
+
 	issue := &types.Issue{
 		ProjectID:   pID,
 		ParentID:    req.ParentID,
@@ -131,11 +217,13 @@ func (s *Server) createIssue(c echo.Context) error {
 		IssueType:   types.IssueType(req.IssueType),
 		AISessionID: req.AISessionID,
 		ExternalRef: req.ExternalRef,
+		Fields:      req.Fields,
 	}
 
 	if err := s.store.CreateIssue(c.Request().Context(), issue, actor); err != nil {
 		return errorJSON(c, http.StatusBadRequest, err.Error())
 	}
+	issue.PossibleDuplicates = duplicates
 
 	return createdJSON(c, issue)
 }
@@ -151,6 +239,7 @@ func (s *Server) getIssueByID(c echo.Context) error {
 		if err != nil {
 			return errorJSON(c, http.StatusNotFound, err.Error())
 		}
+		setIssueETag(c, details.Version)
 		return successJSON(c, details)
 	}
 
@@ -158,6 +247,7 @@ func (s *Server) getIssueByID(c echo.Context) error {
 	if err != nil {
 		return errorJSON(c, http.StatusNotFound, err.Error())
 	}
+	setIssueETag(c, issue.Version)
 	return successJSON(c, issue)
 }
 
@@ -181,18 +271,27 @@ func (s *Server) getIssue(c echo.Context) error {
 		if err != nil {
 			return errorJSON(c, http.StatusNotFound, err.Error())
 		}
+		setIssueETag(c, details.Version)
 		return successJSON(c, details)
 	}
 
+	setIssueETag(c, issue.Version)
 	return successJSON(c, issue)
 }
 
 // updateIssue applies partial updates to an issue.
 // Only provided fields are updated; omitted fields remain unchanged.
+// An If-Match header holding the issue's ETag makes the update conditional:
+// if the issue has changed since, nothing is written and 412 is returned.
 func (s *Server) updateIssue(c echo.Context) error {
 	id := c.Param("id")
 	actor := getActor(c)
 
+	ifVersion, ok := parseIfMatch(c.Request().Header.Get("If-Match"))
+	if !ok {
+		return errorJSON(c, http.StatusPreconditionFailed, "If-Match must be an issue ETag or *")
+	}
+
 	// Validate issue belongs to project (security: prevents cross-project access)
 	if err := s.validateIssueProject(c, id); err != nil {
 		if errors.Is(err, errProjectMismatch) {
@@ -220,6 +319,12 @@ func (s *Server) updateIssue(c echo.Context) error {
 	if req.Priority != nil {
 		updates["priority"] = *req.Priority
 	}
+	if req.Rank != nil {
+		if *req.Rank < 0 {
+			return errorJSON(c, http.StatusBadRequest, "rank must not be negative")
+		}
+		updates["rank"] = *req.Rank
+	}
 	if req.IssueType != nil {
 		updates["issue_type"] = *req.IssueType
 	}
@@ -229,12 +334,27 @@ func (s *Server) updateIssue(c echo.Context) error {
 	if req.ExternalRef != nil {
 		updates["external_ref"] = *req.ExternalRef
 	}
+	if len(req.Fields) > 0 {
+		updates["fields"] = req.Fields
+	}
 
 	if len(updates) == 0 {
 		return errorJSON(c, http.StatusBadRequest, "no updates provided")
 	}
 
-	if err := s.store.UpdateIssue(c.Request().Context(), id, updates, actor); err != nil {
+	if err := s.store.UpdateIssue(c.Request().Context(), id, updates, ifVersion, actor); err != nil {
+		if errors.Is(err, types.ErrInvalidField) {
+			return errorJSON(c, http.StatusBadRequest, err.Error())
+		}
+		var conflictErr *types.VersionConflictError
+		if errors.As(err, &conflictErr) {
+			setIssueETag(c, conflictErr.Current)
+			return c.JSON(http.StatusPreconditionFailed, map[string]any{
+				"error":           conflictErr.Error(),
+				"code":            codeVersionConflict,
+				"current_version": conflictErr.Current,
+			})
+		}
 		return errorJSON(c, http.StatusInternalServerError, err.Error())
 	}
 
@@ -243,10 +363,38 @@ func (s *Server) updateIssue(c echo.Context) error {
 	if err != nil {
 		return errorJSON(c, http.StatusInternalServerError, err.Error())
 	}
+	if fields, err := s.store.GetFieldsForIssues(c.Request().Context(), []string{id}); err == nil {
+		issue.Fields = fields[id]
+	}
 
+	setIssueETag(c, issue.Version)
 	return successJSON(c, issue)
 }
 
+// setIssueETag sets the ETag response header to an issue version.
+func setIssueETag(c echo.Context, version int64) {
+	c.Response().Header().Set("ETag", `"`+strconv.FormatInt(version, 10)+`"`)
+}
+
+// parseIfMatch returns the version named by an If-Match header, or 0 when
+// the header is absent or "*". ok is false for anything that is not a
+// single issue ETag.
+func parseIfMatch(header string) (version int64, ok bool) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return 0, true
+	}
+	unquoted, found := strings.CutPrefix(header, `"`)
+	if unquoted, found = strings.CutSuffix(unquoted, `"`); !found {
+		return 0, false
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, false
+	}
+	return version, true
+}
+
 // deleteIssue deletes an issue.
 func (s *Server) deleteIssue(c echo.Context) error {
 	id := c.Param("id")
@@ -331,17 +479,26 @@ func (s *Server) reopenIssue(c echo.Context) error {
 	return successJSON(c, issue)
 }
 
-// getReadyWork returns issues that are ready to work on (no unresolved blockers).
-// Supports filtering by type and priority.
-func (s *Server) getReadyWork(c echo.Context) error {
-	pID := projectID(c)
-
+// parseWorkFilter reads the ready and blocked work filters from the query
+// string: status, type, priority, repeated label, parent (an epic whose
+// children to list), teammate (shorthand for the teammate:<role> label),
+// sort, limit, offset and after, the ID of the last issue of the previous
+// page. It fails when the cursor is not an issue of the project.
+func (s *Server) parseWorkFilter(c echo.Context) (types.WorkFilter, error) {
 	filter := types.WorkFilter{
-		ProjectID: pID,
-		Limit:     queryInt(c, "limit", defaultListLimit),
+		ProjectID:  projectID(c),
+		SortPolicy: types.SortPolicy(c.QueryParam("sort")),
+		Labels:     c.QueryParams()["label"],
+		ParentID:   c.QueryParam("parent"),
+		After:      c.QueryParam("after"),
+		Limit:      queryInt(c, "limit", defaultListLimit),
+		Offset:     queryInt(c, "offset", 0),
 	}
 
-	// Parse optional filters
+	if status := c.QueryParam("status"); status != "" {
+		st := types.Status(status)
+		filter.Status = &st
+	}
 	if issueType := c.QueryParam("type"); issueType != "" {
 		t := types.IssueType(issueType)
 		filter.IssueType = &t
@@ -350,6 +507,25 @@ func (s *Server) getReadyWork(c echo.Context) error {
 		p := queryInt(c, "priority", defaultPriority)
 		filter.Priority = &p
 	}
+	if role := c.QueryParam("teammate"); role != "" {
+		filter.Labels = append(filter.Labels, teammatePrefix+role)
+	}
+
+	if filter.After != "" {
+		if err := s.validateIssueProject(c, filter.After); err != nil {
+			return filter, fmt.Errorf("invalid cursor: %w", err)
+		}
+	}
+	return filter, nil
+}
+
+// getReadyWork returns issues that are ready to work on (no unresolved blockers).
+// Supports the filters and pagination of parseWorkFilter.
+func (s *Server) getReadyWork(c echo.Context) error {
+	filter, err := s.parseWorkFilter(c)
+	if err != nil {
+		return errorJSON(c, http.StatusBadRequest, err.Error())
+	}
 
 	issues, err := s.store.GetReadyWork(c.Request().Context(), filter)
 	if err != nil {
@@ -376,11 +552,9 @@ func (s *Server) getReadyWork(c echo.Context) error {
 
 // getBlockedIssues returns issues that are blocked by unresolved dependencies.
 func (s *Server) getBlockedIssues(c echo.Context) error {
-	pID := projectID(c)
-
-	filter := types.WorkFilter{
-		ProjectID: pID,
-		Limit:     queryInt(c, "limit", defaultListLimit),
+	filter, err := s.parseWorkFilter(c)
+	if err != nil {
+		return errorJSON(c, http.StatusBadRequest, err.Error())
 	}
 
 	issues, err := s.store.GetBlockedIssues(c.Request().Context(), filter)
@@ -2,11 +2,15 @@ package api //nolint:testpackage // tests use internal helpers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/sentiolabs/arc/internal/types"
@@ -328,3 +332,361 @@ func TestCloseIssueNoChildrenNoCascade(t *testing.T) {
 		t.Errorf("status = %q, want %q", issue.Status, types.StatusClosed)
 	}
 }
+
+func TestUpdateIssueIfMatch(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	e := server.echo
+
+	pID := createTestProject(t, e)
+	issueID := createTestIssue(t, e, pID, "Contended")
+	url := fmt.Sprintf("/api/v1/projects/%s/issues/%s", pID, issueID)
+
+	put := func(ifMatch, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, url, bytes.NewBufferString(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if etag := rec.Header().Get("ETag"); etag != `"1"` {
+		t.Fatalf("GET ETag = %q, want \"1\"", etag)
+	}
+
+	rec = put(`"1"`, `{"title": "First"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PUT with current If-Match returned %d: %s", rec.Code, rec.Body.String())
+	}
+	if etag := rec.Header().Get("ETag"); etag != `"2"` {
+		t.Errorf("PUT ETag = %q, want \"2\"", etag)
+	}
+
+	rec = put(`"1"`, `{"title": "Stale"}`)
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("PUT with stale If-Match returned %d, want 412: %s", rec.Code, rec.Body.String())
+	}
+	var conflict struct {
+		Code           string `json:"code"`
+		CurrentVersion int64  `json:"current_version"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &conflict); err != nil {
+		t.Fatalf("failed to parse conflict: %v", err)
+	}
+	if conflict.Code != codeVersionConflict || conflict.CurrentVersion != 2 {
+		t.Errorf("conflict = %+v, want version_conflict at 2", conflict)
+	}
+
+	if rec = put("W/\"2\"", `{"title": "Weak"}`); rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("PUT with weak If-Match returned %d, want 412", rec.Code)
+	}
+	if rec = put("*", `{"title": "Any"}`); rec.Code != http.StatusOK {
+		t.Errorf("PUT with If-Match * returned %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestGetIssueHistory(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	e := server.echo
+
+	pID := createTestProject(t, e)
+	issueID := createTestIssue(t, e, pID, "Draft")
+
+	time.Sleep(2 * time.Millisecond)
+	before := time.Now()
+	time.Sleep(2 * time.Millisecond)
+
+	body := `{"title": "Final", "description": "first\nsecond", "rank": 2}`
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/issues/"+issueID, bytes.NewBufferString(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("update returned %d: %s", rec.Code, rec.Body.String())
+	}
+
+	get := func(path string) (*httptest.ResponseRecorder, types.IssueHistory) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		var h types.IssueHistory
+		if rec.Code == http.StatusOK {
+			if err := json.Unmarshal(rec.Body.Bytes(), &h); err != nil {
+				t.Fatalf("failed to parse history: %v", err)
+			}
+		}
+		return rec, h
+	}
+
+	rec, h := get(fmt.Sprintf("/api/v1/projects/%s/issues/%s/history?at=%s",
+		pID, issueID, url.QueryEscape(before.Format(time.RFC3339Nano))))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("history at returned %d: %s", rec.Code, rec.Body.String())
+	}
+	if h.Issue.Title != "Draft" || h.Issue.Description != "" || h.Issue.Rank != 0 {
+		t.Errorf("issue at %v = %q/%q/rank %d, want Draft with no description or rank",
+			before, h.Issue.Title, h.Issue.Description, h.Issue.Rank)
+	}
+	if len(h.Events) != 1 || h.Events[0].EventType != types.EventCreated {
+		t.Errorf("events at %v = %+v, want only the created event", before, h.Events)
+	}
+
+	rec, h = get("/api/v1/issues/" + issueID + "/history")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("history returned %d: %s", rec.Code, rec.Body.String())
+	}
+	if h.Issue.Title != "Final" || h.Issue.Rank != 2 {
+		t.Errorf("current issue = %q/rank %d, want Final/rank 2", h.Issue.Title, h.Issue.Rank)
+	}
+	var changed []string
+	for _, ev := range h.Events {
+		if ev.EventType == types.EventUpdated {
+			changed = append(changed, *ev.OldValue+" -> "+*ev.NewValue)
+		}
+	}
+	want := []string{"title=Draft -> title=Final", "description= -> description=first\nsecond", "rank=0 -> rank=2"}
+	if !slices.Equal(changed, want) {
+		t.Errorf("updated events = %q, want %q", changed, want)
+	}
+
+	if rec, _ = get("/api/v1/issues/" + issueID + "/history?at=2000-01-01T00:00:00Z"); rec.Code != http.StatusNotFound {
+		t.Errorf("history before creation returned %d, want 404", rec.Code)
+	}
+	if rec, _ = get("/api/v1/issues/" + issueID + "/history?at=yesterday"); rec.Code != http.StatusBadRequest {
+		t.Errorf("history with bad at returned %d, want 400", rec.Code)
+	}
+}
+
+func TestUndoChanges(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	e := server.echo
+
+	pID := createTestProject(t, e)
+	issueID := createTestIssue(t, e, pID, "Draft")
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/issues/"+issueID, bytes.NewBufferString(`{"title": "Final"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("X-Actor", "alice")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("update returned %d: %s", rec.Code, rec.Body.String())
+	}
+
+	post := func(body string) []*types.UndoStep {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/projects/"+pID+"/undo", bytes.NewBufferString(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("X-Actor", "bob")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("undo returned %d: %s", rec.Code, rec.Body.String())
+		}
+		var steps []*types.UndoStep
+		if err := json.Unmarshal(rec.Body.Bytes(), &steps); err != nil {
+			t.Fatalf("failed to parse steps: %v", err)
+		}
+		return steps
+	}
+
+	steps := post(`{"actor": "alice", "dry_run": true}`)
+	if len(steps) != 1 || steps[0].Applied || steps[0].Action != `set title of `+issueID+` back to "Draft"` {
+		t.Fatalf("dry run steps = %+v, want one unapplied title reversal", steps)
+	}
+	if issue, _ := server.store.GetIssue(context.Background(), issueID); issue.Title != "Final" {
+		t.Fatalf("dry run changed the title to %q", issue.Title)
+	}
+
+	steps = post(`{"actor": "alice"}`)
+	if len(steps) != 1 || !steps[0].Applied {
+		t.Fatalf("steps = %+v, want one applied step", steps)
+	}
+	if issue, _ := server.store.GetIssue(context.Background(), issueID); issue.Title != "Draft" {
+		t.Errorf("title after undo = %q, want Draft", issue.Title)
+	}
+	events, err := server.store.GetEvents(context.Background(), issueID, 1)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	if events[0].EventType != types.EventUndone || events[0].Actor != "bob" {
+		t.Errorf("latest event = %s by %s, want undone by bob", events[0].EventType, events[0].Actor)
+	}
+
+	if steps := post(`{"actor": "alice"}`); len(steps) != 0 {
+		t.Errorf("steps = %+v, want nothing left to undo", steps)
+	}
+}
+
+func TestUndoPreviewedEvents(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	e := server.echo
+
+	pID := createTestProject(t, e)
+	issueID := createTestIssue(t, e, pID, "Draft")
+	put := func(body string) {
+		t.Helper()
+		if rec := doWebhookRequest(e, http.MethodPut, "/api/v1/issues/"+issueID, body); rec.Code != http.StatusOK {
+			t.Fatalf("update returned %d: %s", rec.Code, rec.Body.String())
+		}
+	}
+	undo := func(body string) []*types.UndoStep {
+		t.Helper()
+		rec := doWebhookRequest(e, http.MethodPost, "/api/v1/projects/"+pID+"/undo", body)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("undo returned %d: %s", rec.Code, rec.Body.String())
+		}
+		var steps []*types.UndoStep
+		if err := json.Unmarshal(rec.Body.Bytes(), &steps); err != nil {
+			t.Fatalf("failed to parse steps: %v", err)
+		}
+		return steps
+	}
+
+	put(`{"title": "Final"}`)
+	put(`{"description": "First pass"}`)
+	preview := undo(`{"last": 2, "dry_run": true}`)
+	if len(preview) != 2 {
+		t.Fatalf("preview = %+v, want two steps", preview)
+	}
+
+	// Changes made between the preview and the confirmation are neither
+	// undone nor overwritten.
+	put(`{"priority": 0}`)
+	put(`{"description": "Second pass"}`)
+	body := fmt.Sprintf(`{"event_ids": [%d, %d]}`, preview[0].Event.ID, preview[1].Event.ID)
+	steps := undo(body)
+	if len(steps) != 2 || steps[0].Event.ID != preview[0].Event.ID || steps[0].Skipped == "" ||
+		steps[1].Event.ID != preview[1].Event.ID || !steps[1].Applied {
+		t.Fatalf("steps = %+v, want the description reversal skipped and the title reversal applied", steps)
+	}
+	issue, err := server.store.GetIssue(context.Background(), issueID)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if issue.Title != "Draft" || issue.Priority != 0 || issue.Description != "Second pass" {
+		t.Errorf("issue = %q, priority %d, %q; want Draft, priority 0, Second pass",
+			issue.Title, issue.Priority, issue.Description)
+	}
+
+	if steps := undo(body); len(steps) != 2 || steps[1].Skipped != "already undone" {
+		t.Errorf("steps = %+v, want the title reversal skipped as already undone", steps)
+	}
+}
+
+func TestGetReadyWorkFilters(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	e := server.echo
+
+	pID := createTestProject(t, e)
+	createTestIssue(t, e, pID, "One")
+	two := createTestIssue(t, e, pID, "Two")
+	createTestIssue(t, e, pID, "Three")
+	createTestLabel(t, e, "teammate:frontend", "#3b82f6")
+	addTestLabelToIssue(t, e, pID, two, "teammate:frontend")
+
+	ids := func(query string) []string {
+		t.Helper()
+		var out []string
+		for _, issue := range fetchReadyWork(t, e, "/api/v1/projects/"+pID+"/ready"+query) {
+			out = append(out, issue["id"].(string))
+		}
+		return out
+	}
+
+	if got := ids("?teammate=frontend"); !slices.Equal(got, []string{two}) {
+		t.Errorf("teammate=frontend = %v, want [%s]", got, two)
+	}
+	if got := ids("?label=teammate:frontend"); !slices.Equal(got, []string{two}) {
+		t.Errorf("label=teammate:frontend = %v, want [%s]", got, two)
+	}
+
+	all := ids("?sort=oldest")
+	if len(all) != 3 {
+		t.Fatalf("ready work = %v, want 3 issues", all)
+	}
+	if got := ids("?sort=oldest&limit=1&offset=1"); !slices.Equal(got, all[1:2]) {
+		t.Errorf("offset=1 = %v, want %v", got, all[1:2])
+	}
+	if got := ids("?sort=oldest&after=" + all[0]); !slices.Equal(got, all[1:]) {
+		t.Errorf("after=%s = %v, want %v", all[0], got, all[1:])
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/projects/"+pID+"/blocked?after=missing", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown cursor: expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestListIssuesQuery(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	e := server.echo
+
+	pID := createTestProject(t, e)
+	login := createTestIssue(t, e, pID, "Login redirect")
+	createTestIssue(t, e, pID, "Logout button")
+	bug := createTestIssueWithType(t, e, pID, "Crash on save", "bug")
+	createTestLabel(t, e, "area:api", "#3b82f6")
+	addTestLabelToIssue(t, e, pID, login, "area:api")
+	addTestLabelToIssue(t, e, pID, bug, "area:api")
+
+	issues := "/api/v1/projects/" + pID + "/issues"
+	ids := func(q string) []string {
+		t.Helper()
+		var out []string
+		for _, issue := range fetchIssues(t, e, issues+"?q="+url.QueryEscape(q)) {
+			out = append(out, issue.ID)
+		}
+		return out
+	}
+
+	if got := ids("label:area:api -type:bug"); !slices.Equal(got, []string{login}) {
+		t.Errorf("label:area:api -type:bug = %v, want [%s]", got, login)
+	}
+	if got := ids("type:bug OR login"); !sameIssueIDs(got, []string{login, bug}) {
+		t.Errorf("type:bug OR login = %v, want %s and %s", got, login, bug)
+	}
+
+	config := "/api/v1/projects/" + pID + "/config"
+	rec := doWebhookRequest(e, http.MethodPut, config, `{"key": "query.api", "value": "label:area:api sort:title"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("save query returned %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := ids("@api"); !slices.Equal(got, []string{bug, login}) {
+		t.Errorf("@api = %v, want [%s %s]", got, bug, login)
+	}
+	if got := ids("@api type:bug"); !slices.Equal(got, []string{bug}) {
+		t.Errorf("@api type:bug = %v, want [%s]", got, bug)
+	}
+
+	rec = doWebhookRequest(e, http.MethodPut, config, `{"key": "query.broken", "value": "colour:red"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("saving an invalid query returned %d, want 400", rec.Code)
+	}
+	for _, q := range []string{"status:bogus", "@missing"} {
+		req := httptest.NewRequest(http.MethodGet, issues+"?q="+url.QueryEscape(q), nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("q=%s returned %d, want 400", q, rec.Code)
+		}
+	}
+}
+
+// sameIssueIDs reports whether a and b hold the same IDs in any order.
+func sameIssueIDs(a, b []string) bool {
+	return slices.Equal(slices.Sorted(slices.Values(a)), slices.Sorted(slices.Values(b)))
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// addMemberRequest is the request body for adding a project member.
+type addMemberRequest struct {
+	Actor string            `json:"actor"`
+	Role  types.ProjectRole `json:"role"`
+}
+
+// errLastAdmin is returned when a change would leave members without an admin.
+var errLastAdmin = errors.New("project must keep at least one admin")
+
+// listMembers returns a project's members.
+func (s *Server) listMembers(c echo.Context) error {
+	pID := c.Param("id")
+	if _, err := s.store.GetProject(c.Request().Context(), pID); err != nil {
+		return errorJSON(c, http.StatusNotFound, err.Error())
+	}
+
+	members, err := s.store.ListProjectMembers(c.Request().Context(), pID)
+	if err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return successJSON(c, members)
+}
+
+// addMember adds a member or changes an existing member's role. The first
+// member of an open project must be an admin, so that adding members never
+// locks everyone out of managing the project. Members can only be added
+// while the server requires authentication.
+func (s *Server) addMember(c echo.Context) error {
+	ctx := c.Request().Context()
+	pID := c.Param("id")
+	if _, err := s.store.GetProject(ctx, pID); err != nil {
+		return errorJSON(c, http.StatusNotFound, err.Error())
+	}
+	if !s.auth.requireAuth {
+		return errorJSON(c, http.StatusBadRequest, errMembersNeedAuth.Error())
+	}
+
+	var req addMemberRequest
+	if err := c.Bind(&req); err != nil {
+		return errorJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+	if req.Actor == "" {
+		return errorJSON(c, http.StatusBadRequest, "actor is required")
+	}
+	if req.Role == "" {
+		req.Role = types.RoleContributor
+	}
+	if !req.Role.IsValid() {
+		return errorJSON(c, http.StatusBadRequest, "invalid role: "+string(req.Role))
+	}
+
+	members, err := s.store.ListProjectMembers(ctx, pID)
+	if err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+	if len(members) == 0 && req.Role != types.RoleAdmin {
+		return errorJSON(c, http.StatusBadRequest, "the first member of a project must be an admin")
+	}
+	if req.Role != types.RoleAdmin && isLastAdmin(members, req.Actor) {
+		return errorJSON(c, http.StatusConflict, errLastAdmin.Error())
+	}
+
+	m := &types.ProjectMember{ProjectID: pID, Actor: req.Actor, Role: req.Role}
+	if err := s.store.SetProjectMember(ctx, m); err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return successJSON(c, m)
+}
+
+// removeMember removes a member. The last admin can only be removed once
+// no other members remain, which reopens the project.
+func (s *Server) removeMember(c echo.Context) error {
+	ctx := c.Request().Context()
+	pID := c.Param("id")
+	actor := c.Param("actor")
+
+	members, err := s.store.ListProjectMembers(ctx, pID)
+	if err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+	if len(members) > 1 && isLastAdmin(members, actor) {
+		return errorJSON(c, http.StatusConflict, errLastAdmin.Error())
+	}
+
+	if err := s.store.RemoveProjectMember(ctx, pID, actor); err != nil {
+		return errorJSON(c, http.StatusNotFound, err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// isLastAdmin reports whether actor is the only admin among members.
+func isLastAdmin(members []*types.ProjectMember, actor string) bool {
+	admins := 0
+	actorIsAdmin := false
+	for _, m := range members {
+		if m.Role == types.RoleAdmin {
+			admins++
+			actorIsAdmin = actorIsAdmin || m.Actor == actor
+		}
+	}
+	return actorIsAdmin && admins == 1
+}
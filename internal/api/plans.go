@@ -4,6 +4,7 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -38,6 +39,11 @@ type updatePlanStatusRequest struct {
 	Status string `json:"status" validate:"required"`
 }
 
+// linkPlanRequest is the body for POST /issues/:id/plans.
+type linkPlanRequest struct {
+	PlanID string `json:"plan_id" validate:"required"`
+}
+
 // createPlanCommentRequest is the body for POST /plans/:planId/comments.
 // LineNumber is nil for overall feedback, or a specific line for anchored comments.
 type createPlanCommentRequest struct {
@@ -101,7 +107,11 @@ func (s *Server) createPlan(c echo.Context) error {
 		UpdatedAt: now,
 	}
 
-	if err := s.store.CreatePlan(c.Request().Context(), plan); err != nil {
+	ctx := c.Request().Context()
+	if err := s.store.CreatePlan(ctx, plan); err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+	if err := s.refreshPlanText(ctx, plan); err != nil {
 		return errorJSON(c, http.StatusInternalServerError, err.Error())
 	}
 
@@ -166,6 +176,9 @@ func (s *Server) updatePlanContent(c echo.Context) error {
 	if err := os.WriteFile(plan.FilePath, []byte(req.Content), planFilePerms); err != nil {
 		return errorJSON(c, http.StatusInternalServerError, fmt.Sprintf("writing plan file: %v", err))
 	}
+	if err := s.store.SetPlanText(ctx, plan.ID, req.Content); err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
 
 	result := types.PlanWithContent{
 		Plan:    *plan,
@@ -219,6 +232,79 @@ func (s *Server) deletePlan(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// refreshPlanText copies the content of a plan's file into the store, where
+// search indexes it for the issues linked to the plan. A file that does not
+// exist yet is indexed as empty.
+func (s *Server) refreshPlanText(ctx context.Context, plan *types.Plan) error {
+	content, err := os.ReadFile(plan.FilePath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading plan file: %w", err)
+	}
+	return s.store.SetPlanText(ctx, plan.ID, string(content))
+}
+
+// --- Plan Link Handlers ---
+
+// listIssuePlans returns the plans linked to an issue.
+func (s *Server) listIssuePlans(c echo.Context) error {
+	id := c.Param("id")
+	if err := s.validateIssueProject(c, id); err != nil {
+		return errorJSON(c, http.StatusNotFound, err.Error())
+	}
+
+	plans, err := s.store.ListIssuePlans(c.Request().Context(), id)
+	if err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+	return successJSON(c, plans)
+}
+
+// linkIssuePlan links a plan to an issue. The plan's file is read again so
+// search covers its content as it stands, even if it was edited on disk
+// since it was last written through the API.
+func (s *Server) linkIssuePlan(c echo.Context) error {
+	id := c.Param("id")
+	ctx := c.Request().Context()
+
+	if err := s.validateIssueProject(c, id); err != nil {
+		return errorJSON(c, http.StatusNotFound, err.Error())
+	}
+
+	var req linkPlanRequest
+	if err := c.Bind(&req); err != nil {
+		return errorJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+	if req.PlanID == "" {
+		return errorJSON(c, http.StatusBadRequest, "plan_id is required")
+	}
+
+	plan, err := s.store.GetPlan(ctx, req.PlanID)
+	if err != nil {
+		return errorJSON(c, http.StatusNotFound, err.Error())
+	}
+	if err := s.refreshPlanText(ctx, plan); err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+	if err := s.store.LinkIssuePlan(ctx, id, plan.ID); err != nil {
+		return errorJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// unlinkIssuePlan removes the link between an issue and a plan.
+func (s *Server) unlinkIssuePlan(c echo.Context) error {
+	id := c.Param("id")
+	if err := s.validateIssueProject(c, id); err != nil {
+		return errorJSON(c, http.StatusNotFound, err.Error())
+	}
+
+	if err := s.store.UnlinkIssuePlan(c.Request().Context(), id, c.Param("planId")); err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
 // listPlanComments returns all comments for a plan.
 func (s *Server) listPlanComments(c echo.Context) error {
 	planID := c.Param("planId")
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -425,3 +426,81 @@ func TestUpdatePlanStatus_ChangesRequestedAccepted(t *testing.T) {
 		t.Errorf("expected status changes_requested, got %q", plan.Status)
 	}
 }
+
+func TestLinkIssuePlan_IndexesPlanContent(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	e := server.Echo()
+
+	pID := createTestProject(t, e)
+	issueID := createTestIssue(t, e, pID, "Auth rework")
+	planID := createTestPlan(t, e)
+
+	if rec := doWebhookRequest(e, http.MethodPut, "/api/v1/plans/"+planID,
+		`{"content": "Rotate the signing keys weekly"}`); rec.Code != http.StatusOK {
+		t.Fatalf("update plan content returned %d: %s", rec.Code, rec.Body.String())
+	}
+	link := func() {
+		t.Helper()
+		rec := doWebhookRequest(e, http.MethodPost, "/api/v1/issues/"+issueID+"/plans",
+			`{"plan_id": "`+planID+`"}`)
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("link plan returned %d: %s", rec.Code, rec.Body.String())
+		}
+	}
+	search := func(q string) []*types.SearchHit {
+		t.Helper()
+		rec := doWebhookRequest(e, http.MethodGet, "/api/v1/search?q="+q, "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("search returned %d: %s", rec.Code, rec.Body.String())
+		}
+		var resp struct {
+			Data []*types.SearchHit `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode search response: %v", err)
+		}
+		return resp.Data
+	}
+
+	link()
+	hits := search("signing")
+	if len(hits) != 1 || hits[0].Issue.ID != issueID || hits[0].Field != types.SearchFieldPlans {
+		t.Fatalf("search = %+v, want %s matched on its plans", hits, issueID)
+	}
+
+	rec := doWebhookRequest(e, http.MethodGet, "/api/v1/issues/"+issueID+"/plans", "")
+	var plans []*types.Plan
+	if err := json.Unmarshal(rec.Body.Bytes(), &plans); err != nil {
+		t.Fatalf("decode issue plans: %v", err)
+	}
+	if len(plans) != 1 || plans[0].ID != planID {
+		t.Errorf("issue plans = %+v, want only %s", plans, planID)
+	}
+
+	// Linking again picks up edits made to the file on disk.
+	plan, err := server.store.GetPlan(t.Context(), planID)
+	if err != nil {
+		t.Fatalf("GetPlan failed: %v", err)
+	}
+	if err := os.WriteFile(plan.FilePath, []byte("Rotate the session cookies"), 0o600); err != nil {
+		t.Fatalf("write plan file: %v", err)
+	}
+	link()
+	if hits := search("cookies"); len(hits) != 1 {
+		t.Errorf("search after editing the file = %d hits, want 1", len(hits))
+	}
+
+	rec = doWebhookRequest(e, http.MethodDelete, "/api/v1/issues/"+issueID+"/plans/"+planID, "")
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("unlink plan returned %d: %s", rec.Code, rec.Body.String())
+	}
+	if hits := search("cookies"); len(hits) != 0 {
+		t.Errorf("search after unlinking = %d hits, want none", len(hits))
+	}
+
+	if rec := doWebhookRequest(e, http.MethodPost, "/api/v1/issues/"+issueID+"/plans",
+		`{"plan_id": "plan.missing"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("linking an unknown plan returned %d, want 404", rec.Code)
+	}
+}
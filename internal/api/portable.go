@@ -0,0 +1,74 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sentiolabs/arc/internal/portable"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// mimeNDJSON is the content type of project archives.
+const mimeNDJSON = "application/x-ndjson"
+
+// exportProject streams a project archive as JSONL.
+func (s *Server) exportProject(c echo.Context) error {
+	id := c.Param("id")
+
+	archive, err := s.store.ExportProject(c.Request().Context(), id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return errorJSON(c, http.StatusNotFound, err.Error())
+		}
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, mimeNDJSON)
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", id+".jsonl"))
+	res.WriteHeader(http.StatusOK)
+	return portable.Write(res, archive)
+}
+
+// importProject reads a JSONL archive from the request body. With ?into=
+// the archive is added to an existing project, which the caller must
+// maintain; otherwise the project is recreated with its original IDs.
+func (s *Server) importProject(c echo.Context) error {
+	ctx := c.Request().Context()
+	into := c.QueryParam("into")
+	if into != "" {
+		if _, err := s.store.GetProject(ctx, into); err != nil {
+			return errorJSON(c, http.StatusNotFound, err.Error())
+		}
+		if err := s.checkProjectRole(ctx, into, getActor(c), types.RoleMaintainer); err != nil {
+			return errorJSON(c, http.StatusForbidden, err.Error())
+		}
+	}
+
+	archive, err := portable.Read(c.Request().Body)
+	if err != nil {
+		return errorJSON(c, http.StatusBadRequest, "invalid archive: "+err.Error())
+	}
+
+	result, err := portable.Import(ctx, s.store, archive, portable.ImportOptions{TargetProjectID: into})
+	if err != nil {
+		if errors.Is(err, portable.ErrConflict) {
+			return errorJSON(c, http.StatusConflict, err.Error())
+		}
+		return errorJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	// As with createProject, a token-authenticated importer administers a
+	// project the import created.
+	if tok := authToken(c); tok != nil && into == "" {
+		admin := &types.ProjectMember{ProjectID: result.Project.ID, Actor: tok.Actor, Role: types.RoleAdmin}
+		if err := s.store.SetProjectMember(ctx, admin); err != nil {
+			return errorJSON(c, http.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return createdJSON(c, result)
+}
@@ -0,0 +1,71 @@
+package api //nolint:testpackage // tests use internal helpers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func TestExportImportProject(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	e := server.echo
+	pID := createTestProject(t, e)
+	issueID := createTestIssue(t, e, pID, "Portable")
+	as := actorTokens(t, server, "alice", "bob")
+
+	rec := as("alice", http.MethodGet, "/api/v1/projects/"+pID+"/export", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("export returned %d: %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != mimeNDJSON {
+		t.Errorf("Content-Type = %q, want %q", ct, mimeNDJSON)
+	}
+	archive := rec.Body.String()
+	if !strings.Contains(archive, `"type":"issue"`) || !strings.Contains(archive, issueID) {
+		t.Fatalf("archive missing issue record:\n%s", archive)
+	}
+
+	// The project already exists here, so a preserving import conflicts.
+	if rec := as("alice", http.MethodPost, "/api/v1/projects/import", archive); rec.Code != http.StatusConflict {
+		t.Errorf("preserving import returned %d, want 409: %s", rec.Code, rec.Body.String())
+	}
+	if rec := as("alice", http.MethodPost, "/api/v1/projects/import", "garbage"); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid archive returned %d, want 400", rec.Code)
+	}
+
+	// Import into a second project re-prefixes the issue.
+	rec = as("alice", http.MethodPost, "/api/v1/projects",
+		`{"name": "Target", "prefix": "tgt"}`)
+	var target types.Project
+	if err := json.Unmarshal(rec.Body.Bytes(), &target); err != nil {
+		t.Fatalf("decode project: %v", err)
+	}
+
+	rec = as("alice", http.MethodPost, "/api/v1/projects/import?into="+target.ID, archive)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("import into target returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var result types.ImportResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatalf("decode result: %v", err)
+	}
+	newID := result.IDMap[issueID]
+	if result.Issues != 1 || !strings.HasPrefix(newID, "tgt.") {
+		t.Fatalf("result = %+v", result)
+	}
+	if rec := as("alice", http.MethodGet, "/api/v1/issues/"+newID, ""); rec.Code != http.StatusOK {
+		t.Errorf("imported issue returned %d", rec.Code)
+	}
+
+	// Importing into a project needs maintainer there.
+	as("alice", http.MethodPost, "/api/v1/projects/"+target.ID+"/members", `{"actor": "alice", "role": "admin"}`)
+	as("alice", http.MethodPost, "/api/v1/projects/"+target.ID+"/members", `{"actor": "bob", "role": "contributor"}`)
+	rec = as("bob", http.MethodPost, "/api/v1/projects/import?into="+target.ID, archive)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("contributor import returned %d, want 403", rec.Code)
+	}
+}
@@ -3,15 +3,20 @@ package api
 import (
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 
 	"github.com/sentiolabs/arc/internal/config"
+	"github.com/sentiolabs/arc/internal/issuequery"
+	"github.com/sentiolabs/arc/internal/types"
 )
 
 // Per-project config endpoints expose a generic key/value store scoped to a
 // project. The API stores keys verbatim; semantic validation (for example of
-// plans.* keys) belongs to the callers that own those namespaces.
+// plans.* keys) belongs to the callers that own those namespaces. The
+// server-side namespaces are field.*, which defines custom issue fields, and
+// query.*, which holds saved issue queries.
 
 // setProjectConfigRequest is the request body for upserting a config key.
 type setProjectConfigRequest struct {
@@ -48,7 +53,7 @@ func (s *Server) putProjectConfig(c echo.Context) error {
 		return errorJSON(c, http.StatusBadRequest, "key is required")
 	}
 
-	// Validate plans.* namespace values server-side; other keys are stored verbatim.
+	// Validate plans.*, field.* and query.* namespace values server-side; other keys are stored verbatim.
 	switch req.Key {
 	case config.ProjectPlansTypeKey:
 		if !config.ValidPlansType(req.Value) {
@@ -58,6 +63,22 @@ func (s *Server) putProjectConfig(c echo.Context) error {
 		if strings.Contains(req.Value, "..") {
 			return errorJSON(c, http.StatusBadRequest, "plans dir must not contain '..'")
 		}
+	default:
+		if name, ok := strings.CutPrefix(req.Key, issuequery.ConfigPrefix); ok {
+			if !issuequery.ValidName(name) {
+				return errorJSON(c, http.StatusBadRequest, "invalid query name (use letters, digits, - and _)")
+			}
+			if _, err := issuequery.Parse(req.Value, time.Now()); err != nil {
+				return errorJSON(c, http.StatusBadRequest, "invalid query: "+err.Error())
+			}
+		}
+		if key, ok := strings.CutPrefix(req.Key, types.FieldConfigPrefix); ok {
+			def, err := types.ParseFieldDef(key, req.Value)
+			if err != nil {
+				return errorJSON(c, http.StatusBadRequest, err.Error())
+			}
+			req.Value = def.Spec()
+		}
 	}
 
 	if _, err := s.store.GetProject(c.Request().Context(), id); err != nil {
@@ -26,12 +26,22 @@ type updateProjectRequest struct {
 // listProjects returns all projects registered in the system.
 // Projects are the top-level containers for organizing issues.
 func (s *Server) listProjects(c echo.Context) error {
-	projects, err := s.store.ListProjects(c.Request().Context())
+	ctx := c.Request().Context()
+	projects, err := s.store.ListProjects(ctx)
 	if err != nil {
 		return errorJSON(c, http.StatusInternalServerError, err.Error())
 	}
 
-	return successJSON(c, projects)
+	// Hide projects the caller is not a member of.
+	actor := getActor(c)
+	visible := make([]*types.Project, 0, len(projects))
+	for _, p := range projects {
+		if s.canViewProject(ctx, p.ID, actor) {
+			visible = append(visible, p)
+		}
+	}
+
+	return successJSON(c, visible)
 }
 
 // createProject creates a new project with the specified name and prefix.
@@ -52,6 +62,16 @@ func (s *Server) createProject(c echo.Context) error {
 		return errorJSON(c, http.StatusBadRequest, err.Error())
 	}
 
+	// A token-authenticated creator administers the new project, which
+	// makes it private to its members from the start. Membership needs
+	// authentication, so this only applies when it is required.
+	if tok := authToken(c); tok != nil && s.auth.requireAuth {
+		admin := &types.ProjectMember{ProjectID: p.ID, Actor: tok.Actor, Role: types.RoleAdmin}
+		if err := s.store.SetProjectMember(c.Request().Context(), admin); err != nil {
+			return errorJSON(c, http.StatusInternalServerError, err.Error())
+		}
+	}
+
 	return createdJSON(c, p)
 }
 
@@ -137,7 +157,16 @@ func (s *Server) mergeProjects(c echo.Context) error {
 		return errorJSON(c, http.StatusBadRequest, "at least one source_id is required")
 	}
 
-	result, err := s.store.MergeProjects(c.Request().Context(), req.TargetID, req.SourceIDs, getActor(c))
+	// Merging deletes the sources and rewrites the target, so the caller
+	// must administer all of them.
+	actor := getActor(c)
+	for _, id := range append([]string{req.TargetID}, req.SourceIDs...) {
+		if err := s.checkProjectRole(c.Request().Context(), id, actor, types.RoleAdmin); err != nil {
+			return errorJSON(c, http.StatusForbidden, err.Error())
+		}
+	}
+
+	result, err := s.store.MergeProjects(c.Request().Context(), req.TargetID, req.SourceIDs, actor)
 	if err != nil {
 		errMsg := err.Error()
 		if strings.Contains(errMsg, "not found") {
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// routeRoles lists project routes that need more than the default role for
+// their method (viewer for reads, contributor for writes). Keys are
+// "METHOD route-path" as reported by echo.Context.Path.
+var routeRoles = map[string]types.ProjectRole{
+	"PUT /api/v1/projects/:id":                           types.RoleMaintainer,
+	"DELETE /api/v1/projects/:id":                        types.RoleAdmin,
+	"PUT /api/v1/projects/:id/config":                    types.RoleMaintainer,
+	"DELETE /api/v1/projects/:id/config/:key":            types.RoleMaintainer,
+	"POST /api/v1/projects/:id/workspaces":               types.RoleMaintainer,
+	"PATCH /api/v1/projects/:id/workspaces/:pathId":      types.RoleMaintainer,
+	"DELETE /api/v1/projects/:id/workspaces/:pathId":     types.RoleMaintainer,
+	"POST /api/v1/projects/:id/members":                  types.RoleAdmin,
+	"DELETE /api/v1/projects/:id/members/:actor":         types.RoleAdmin,
+	"DELETE /api/v1/projects/:pid/issues/:id":            types.RoleMaintainer,
+	"GET /api/v1/projects/:pid/webhooks":                 types.RoleMaintainer,
+	"POST /api/v1/projects/:pid/webhooks":                types.RoleMaintainer,
+	"DELETE /api/v1/projects/:pid/webhooks/:wid":         types.RoleMaintainer,
+	"POST /api/v1/projects/:pid/webhooks/:wid/test":      types.RoleMaintainer,
+	"GET /api/v1/projects/:pid/webhooks/:wid/deliveries": types.RoleMaintainer,
+	"POST /api/v1/projects/:pid/sync/github":             types.RoleMaintainer,
+	"PUT /api/v1/projects/:pid/templates/:name":          types.RoleMaintainer,
+	"DELETE /api/v1/projects/:pid/templates/:name":       types.RoleMaintainer,
+}
+
+// errMembersNeedAuth is returned for projects with members while the server
+// does not require authentication: without it the caller's identity is only
+// a claim in the X-Actor header, which cannot be checked against a role.
+var errMembersNeedAuth = errors.New("project members require server.require_auth")
+
+// requiredRole returns the project role needed to call a route.
+func requiredRole(method, path string) types.ProjectRole {
+	if role, ok := routeRoles[method+" "+path]; ok {
+		return role
+	}
+	if method == http.MethodGet || method == http.MethodHead {
+		return types.RoleViewer
+	}
+	return types.RoleContributor
+}
+
+// authorize is the middleware enforcing project roles on /api/v1. Routes
+// that do not address a project (labels, plans, config) pass through;
+// projects without members are open to every caller.
+func (s *Server) authorize(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		pID := s.routeProjectID(c)
+		if pID == "" {
+			return next(c)
+		}
+
+		role := requiredRole(c.Request().Method, c.Path())
+		if err := s.checkProjectRole(c.Request().Context(), pID, getActor(c), role); err != nil {
+			return errorJSON(c, http.StatusForbidden, err.Error())
+		}
+		return next(c)
+	}
+}
+
+// routeProjectID returns the project a request addresses. Global issue
+// routes are resolved through the issue; an unknown issue yields "" so the
+// handler can report it as not found.
+func (s *Server) routeProjectID(c echo.Context) string {
+	if pID := c.Param("pid"); pID != "" {
+		return pID
+	}
+	if pID := c.Param("projectId"); pID != "" {
+		return pID
+	}
+
+	path := c.Path()
+	switch {
+	case strings.HasPrefix(path, "/api/v1/projects/:id"):
+		return c.Param("id")
+	case strings.HasPrefix(path, "/api/v1/issues/:id"):
+		issue, err := s.store.GetIssue(c.Request().Context(), c.Param("id"))
+		if err != nil {
+			return ""
+		}
+		return issue.ProjectID
+	}
+	return ""
+}
+
+// checkProjectRole returns an error naming the missing role when actor may
+// not act on the project with at least role. Projects with members fail
+// closed while authentication is not required.
+func (s *Server) checkProjectRole(ctx context.Context, projectID, actor string, role types.ProjectRole) error {
+	members, err := s.store.ListProjectMembers(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if len(members) == 0 {
+		return nil
+	}
+	if !s.auth.requireAuth {
+		return fmt.Errorf("project %s is restricted: %w", projectID, errMembersNeedAuth)
+	}
+
+	for _, m := range members {
+		if m.Actor != actor {
+			continue
+		}
+		if m.Role.Includes(role) {
+			return nil
+		}
+		return fmt.Errorf("requires %s role on project %s (%s has %s)", role, projectID, actor, m.Role)
+	}
+	return fmt.Errorf("requires %s role on project %s (%s is not a member)", role, projectID, actor)
+}
+
+// canViewProject reports whether actor may read the project.
+func (s *Server) canViewProject(ctx context.Context, projectID, actor string) bool {
+	return s.checkProjectRole(ctx, projectID, actor, types.RoleViewer) == nil
+}
@@ -0,0 +1,219 @@
+package api //nolint:testpackage // tests use internal helpers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// doAsActor sends a JSON request attributed to actor via X-Actor.
+func doAsActor(e *echo.Echo, actor, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("X-Actor", actor)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+// actorTokens mints a token for each actor, then makes the server require
+// authentication, as project members do. It returns a function sending a
+// JSON request as one of those actors.
+func actorTokens(t *testing.T, server *Server, actors ...string) func(actor, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	tokens := make(map[string]string, len(actors))
+	for _, actor := range actors {
+		tokens[actor] = createTestToken(t, server.echo, `{"name": "test", "actor": "`+actor+`"}`, "").Token
+	}
+	server.auth.requireAuth = true
+
+	return func(actor, method, path, body string) *httptest.ResponseRecorder {
+		return doAuthRequest(server.echo, method, path, body, tokens[actor])
+	}
+}
+
+func TestRequiredRole(t *testing.T) {
+	tests := []struct {
+		method, path string
+		want         types.ProjectRole
+	}{
+		{http.MethodGet, "/api/v1/projects/:pid/issues", types.RoleViewer},
+		{http.MethodPost, "/api/v1/projects/:pid/issues", types.RoleContributor},
+		{http.MethodPut, "/api/v1/issues/:id", types.RoleContributor},
+		{http.MethodDelete, "/api/v1/projects/:pid/issues/:id", types.RoleMaintainer},
+		{http.MethodGet, "/api/v1/projects/:pid/webhooks", types.RoleMaintainer},
+		{http.MethodDelete, "/api/v1/projects/:id", types.RoleAdmin},
+	}
+	for _, tt := range tests {
+		if got := requiredRole(tt.method, tt.path); got != tt.want {
+			t.Errorf("requiredRole(%s %s) = %s, want %s", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestProjectRoles(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	e := server.echo
+	pID := createTestProject(t, e)
+	issueID := createTestIssue(t, e, pID, "Guarded")
+	members := "/api/v1/projects/" + pID + "/members"
+	as := actorTokens(t, server, "alice", "bob", "carol", "mallory")
+
+	// The first member must be an admin.
+	if rec := as("alice", http.MethodPost, members, `{"actor": "bob", "role": "viewer"}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("non-admin first member returned %d, want 400", rec.Code)
+	}
+	for _, body := range []string{
+		`{"actor": "alice", "role": "admin"}`,
+		`{"actor": "bob", "role": "viewer"}`,
+		`{"actor": "carol", "role": "contributor"}`,
+	} {
+		if rec := as("alice", http.MethodPost, members, body); rec.Code != http.StatusOK {
+			t.Fatalf("add member %s returned %d: %s", body, rec.Code, rec.Body.String())
+		}
+	}
+
+	tests := []struct {
+		name, actor, method, path, body string
+		want                            int
+	}{
+		{"outsider cannot read", "mallory", http.MethodGet, "/api/v1/projects/" + pID + "/issues", "", http.StatusForbidden},
+		{"viewer reads", "bob", http.MethodGet, "/api/v1/projects/" + pID + "/issues", "", http.StatusOK},
+		{"viewer reads via global route", "bob", http.MethodGet, "/api/v1/issues/" + issueID, "", http.StatusOK},
+		{"outsider blocked on global route", "mallory", http.MethodGet, "/api/v1/issues/" + issueID, "", http.StatusForbidden},
+		{"viewer cannot write", "bob", http.MethodPut, "/api/v1/issues/" + issueID, `{"title": "x"}`, http.StatusForbidden},
+		{"contributor writes", "carol", http.MethodPut, "/api/v1/issues/" + issueID, `{"title": "Edited"}`, http.StatusOK},
+		{"contributor cannot delete issues", "carol", http.MethodDelete, "/api/v1/projects/" + pID + "/issues/" + issueID, "", http.StatusForbidden},
+		{"contributor cannot add members", "carol", http.MethodPost, members, `{"actor": "dave"}`, http.StatusForbidden},
+		{"contributor cannot delete project", "carol", http.MethodDelete, "/api/v1/projects/" + pID, "", http.StatusForbidden},
+		{"outsider cannot merge", "mallory", http.MethodPost, "/api/v1/projects/merge", `{"target_id": "` + pID + `", "source_ids": ["x"]}`, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := as(tt.actor, tt.method, tt.path, tt.body)
+			if rec.Code != tt.want {
+				t.Errorf("%s %s as %s returned %d, want %d: %s", tt.method, tt.path, tt.actor, rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+
+	// The 403 names the missing role.
+	rec := as("bob", http.MethodPut, "/api/v1/issues/"+issueID, `{"title": "x"}`)
+	if !strings.Contains(rec.Body.String(), "requires contributor role") || !strings.Contains(rec.Body.String(), "bob has viewer") {
+		t.Errorf("403 body = %s", rec.Body.String())
+	}
+
+	// Restricted projects are hidden from non-members.
+	rec = as("mallory", http.MethodGet, "/api/v1/projects", "")
+	var projects []types.Project
+	if err := json.Unmarshal(rec.Body.Bytes(), &projects); err != nil {
+		t.Fatalf("decode projects: %v", err)
+	}
+	for _, p := range projects {
+		if p.ID == pID {
+			t.Error("restricted project listed for a non-member")
+		}
+	}
+
+	// The last admin cannot leave while others remain.
+	if rec = as("alice", http.MethodDelete, members+"/alice", ""); rec.Code != http.StatusConflict {
+		t.Errorf("removing the last admin returned %d, want 409", rec.Code)
+	}
+	if rec = as("alice", http.MethodDelete, members+"/bob", ""); rec.Code != http.StatusNoContent {
+		t.Errorf("removing a viewer returned %d, want 204", rec.Code)
+	}
+}
+
+func TestTokenCreatorAdministersProject(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	as := actorTokens(t, server, "alice", "bob")
+	rec := as("alice", http.MethodPost, "/api/v1/projects", `{"name": "private", "prefix": "prv"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create project returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var proj types.Project
+	if err := json.Unmarshal(rec.Body.Bytes(), &proj); err != nil {
+		t.Fatalf("decode project: %v", err)
+	}
+
+	m, err := server.store.GetProjectMember(context.Background(), proj.ID, "alice")
+	if err != nil || m.Role != types.RoleAdmin {
+		t.Fatalf("creator membership = %+v, %v; want admin", m, err)
+	}
+	if rec = as("bob", http.MethodGet, "/api/v1/projects/"+proj.ID, ""); rec.Code != http.StatusForbidden {
+		t.Errorf("non-member read returned %d, want 403", rec.Code)
+	}
+}
+
+func TestMembersRequireAuth(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	e := server.echo
+	pID := createTestProject(t, e)
+
+	// Without required auth, X-Actor is only a claim: members cannot be added.
+	rec := doAsActor(e, "alice", http.MethodPost, "/api/v1/projects/"+pID+"/members", `{"actor": "alice", "role": "admin"}`)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "require_auth") {
+		t.Fatalf("add member without auth returned %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// A project that already has members fails closed, even for a claimed admin.
+	admin := &types.ProjectMember{ProjectID: pID, Actor: "alice", Role: types.RoleAdmin}
+	if err := server.store.SetProjectMember(context.Background(), admin); err != nil {
+		t.Fatalf("set member: %v", err)
+	}
+	rec = doAsActor(e, "alice", http.MethodGet, "/api/v1/projects/"+pID+"/issues", "")
+	if rec.Code != http.StatusForbidden || !strings.Contains(rec.Body.String(), "require_auth") {
+		t.Errorf("claimed admin read returned %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// Token creators only administer new projects when auth is required.
+	tok := createTestToken(t, e, `{"name": "a", "actor": "alice"}`, "")
+	rec = doAuthRequest(e, http.MethodPost, "/api/v1/projects", `{"name": "open", "prefix": "opn"}`, tok.Token)
+	var proj types.Project
+	if err := json.Unmarshal(rec.Body.Bytes(), &proj); err != nil {
+		t.Fatalf("decode project: %v", err)
+	}
+	members, err := server.store.ListProjectMembers(context.Background(), proj.ID)
+	if err != nil || len(members) != 0 {
+		t.Errorf("members of a project created without required auth = %+v, %v", members, err)
+	}
+}
+
+func TestMCPEnforcesCallerRole(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	e := server.echo
+
+	listener := httptest.NewServer(e)
+	defer listener.Close()
+	server.address = listener.Listener.Addr().String()
+	server.registerMCP()
+
+	pID := createTestProject(t, e)
+	alice := createTestToken(t, e, `{"name": "agent", "actor": "alice"}`, "").Token
+	mallory := createTestToken(t, e, `{"name": "agent", "actor": "mallory"}`, "").Token
+	server.auth.requireAuth = true
+	members := "/api/v1/projects/" + pID + "/members"
+	if rec := doAuthRequest(e, http.MethodPost, members, `{"actor": "alice", "role": "admin"}`, alice); rec.Code != http.StatusOK {
+		t.Fatalf("add member returned %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// Tool calls are authorized as the caller: members pass, others get 403.
+	args := `{"project": "` + pID + `", "title": "From MCP"}`
+	if issue := callMCPTool(t, server, alice, "create_issue", args); issue["id"] == nil {
+		t.Errorf("member create_issue = %+v", issue)
+	}
+	if _, errText := tryMCPTool(t, server, mallory, "create_issue", args); !strings.Contains(errText, "mallory is not a member") {
+		t.Errorf("non-member create_issue error = %q", errText)
+	}
+}
@@ -0,0 +1,75 @@
+// Package api provides HTTP handlers for the arc REST API.
+// This file implements full-text search over issues, within one project or
+// across every project the caller can view.
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// searchIssues returns issues matching the full-text query q, best match
+// first. Each hit names the field that matched (title, description,
+// comments, labels or linked plans) with a highlighted snippet of it. The
+// project parameter limits the search to one project; without it every
+// project the caller can view is searched.
+func (s *Server) searchIssues(c echo.Context) error {
+	ctx := c.Request().Context()
+	q := strings.TrimSpace(c.QueryParam("q"))
+	if q == "" {
+		return errorJSON(c, http.StatusBadRequest, "q is required")
+	}
+
+	filter := types.SearchFilter{
+		Query:  q,
+		Limit:  queryInt(c, "limit", defaultListLimit),
+		Offset: queryInt(c, "offset", 0),
+	}
+
+	actor := getActor(c)
+	if pID := c.QueryParam("project"); pID != "" {
+		if _, err := s.store.GetProject(ctx, pID); err != nil {
+			return errorJSON(c, http.StatusNotFound, err.Error())
+		}
+		if err := s.checkProjectRole(ctx, pID, actor, types.RoleViewer); err != nil {
+			return errorJSON(c, http.StatusForbidden, err.Error())
+		}
+		filter.ProjectIDs = []string{pID}
+	} else {
+		projects, err := s.store.ListProjects(ctx)
+		if err != nil {
+			return errorJSON(c, http.StatusInternalServerError, err.Error())
+		}
+		for _, p := range projects {
+			if s.canViewProject(ctx, p.ID, actor) {
+				filter.ProjectIDs = append(filter.ProjectIDs, p.ID)
+			}
+		}
+		if len(filter.ProjectIDs) == 0 {
+			return paginatedJSON(c, []*types.SearchHit{}, 0, filter.Limit, filter.Offset)
+		}
+	}
+
+	hits, err := s.store.SearchIssues(ctx, filter)
+	if err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+
+	// Fetch labels for all hits in batch
+	if len(hits) > 0 {
+		issueIDs := make([]string, len(hits))
+		for i, hit := range hits {
+			issueIDs[i] = hit.Issue.ID
+		}
+		if labelsMap, err := s.store.GetLabelsForIssues(ctx, issueIDs); err == nil {
+			for _, hit := range hits {
+				hit.Issue.Labels = labelsMap[hit.Issue.ID]
+			}
+		}
+	}
+
+	return paginatedJSON(c, hits, len(hits), filter.Limit, filter.Offset)
+}
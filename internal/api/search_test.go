@@ -0,0 +1,83 @@
+package api //nolint:testpackage // tests use internal helpers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func TestSearchIssues(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	e := server.echo
+
+	pID := createTestProject(t, e)
+	rec := doWebhookRequest(e, http.MethodPost, "/api/v1/projects", `{"name": "Private", "prefix": "priv"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create project returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var private types.Project
+	if err := json.Unmarshal(rec.Body.Bytes(), &private); err != nil {
+		t.Fatalf("decode project: %v", err)
+	}
+
+	commented := createTestIssue(t, e, pID, "Checkout retries")
+	rec = doWebhookRequest(e, http.MethodPost, "/api/v1/projects/"+pID+"/issues/"+commented+"/comments",
+		`{"author": "alice", "text": "The payment stub is flaky under load"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("add comment returned %d: %s", rec.Code, rec.Body.String())
+	}
+	hidden := createTestIssue(t, e, private.ID, "Flaky deploy")
+	as := actorTokens(t, server, "alice", "bob")
+	if rec := as("alice", http.MethodPost, "/api/v1/projects/"+private.ID+"/members",
+		`{"actor": "alice", "role": "admin"}`); rec.Code != http.StatusOK {
+		t.Fatalf("add member returned %d: %s", rec.Code, rec.Body.String())
+	}
+
+	search := func(actor, query string) (int, []*types.SearchHit) {
+		t.Helper()
+		rec := as(actor, http.MethodGet, "/api/v1/search?"+query, "")
+		if rec.Code != http.StatusOK {
+			return rec.Code, nil
+		}
+		var resp struct {
+			Data []*types.SearchHit `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode search response: %v", err)
+		}
+		return rec.Code, resp.Data
+	}
+
+	// bob is not a member of the private project, so only sees the comment hit.
+	_, hits := search("bob", "q=flaky")
+	if len(hits) != 1 || hits[0].Issue.ID != commented || hits[0].Field != types.SearchFieldComments {
+		t.Fatalf("bob's search = %+v, want %s matched on comments", hits, commented)
+	}
+	if !strings.Contains(hits[0].Snippet, types.HighlightStart+"flaky"+types.HighlightEnd) {
+		t.Errorf("snippet %q does not highlight the match", hits[0].Snippet)
+	}
+	if _, hits := search("alice", "q=flaky"); len(hits) != 2 {
+		t.Errorf("alice's search = %d hits, want 2", len(hits))
+	}
+	if _, hits := search("alice", "q=flaky&project="+private.ID); len(hits) != 1 || hits[0].Issue.ID != hidden {
+		t.Errorf("search in %s = %+v, want only %s", private.ID, hits, hidden)
+	}
+
+	for _, tt := range []struct {
+		actor, query string
+		want         int
+	}{
+		{"bob", "q=flaky&project=" + private.ID, http.StatusForbidden},
+		{"bob", "q=flaky&project=proj-missing", http.StatusNotFound},
+		{"bob", "q=" + url.QueryEscape("  "), http.StatusBadRequest},
+	} {
+		if code, _ := search(tt.actor, tt.query); code != tt.want {
+			t.Errorf("search %s as %s returned %d, want %d", tt.query, tt.actor, code, tt.want)
+		}
+	}
+}
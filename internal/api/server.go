@@ -15,9 +15,13 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/sentiolabs/arc/internal/client"
+	"github.com/sentiolabs/arc/internal/events"
+	"github.com/sentiolabs/arc/internal/mcp"
 	"github.com/sentiolabs/arc/internal/storage"
 	"github.com/sentiolabs/arc/internal/types"
 	"github.com/sentiolabs/arc/internal/version"
+	"github.com/sentiolabs/arc/internal/webhooks"
 	"github.com/sentiolabs/arc/web"
 )
 
@@ -25,6 +29,9 @@ import (
 type Server struct {
 	echo      *echo.Echo
 	store     storage.Storage
+	events    *events.Bus
+	webhooks  *webhooks.Dispatcher
+	auth      *authenticator
 	address   string
 	startTime time.Time
 }
@@ -33,6 +40,17 @@ type Server struct {
 type ServerOptions struct {
 	Address string // e.g., ":7432" or "localhost:7432"
 	Store   storage.Storage
+	MCP     bool // Mount the MCP streamable-HTTP endpoint at /mcp
+	// Events is the bus feeding /api/v1/events/stream. When nil, a new bus
+	// is created and attached to Store if it implements storage.Notifier.
+	Events *events.Bus
+	// Webhooks sends test deliveries for /webhooks/:wid/test. When nil, a
+	// dispatcher is created for that purpose; queued deliveries are only
+	// processed by a dispatcher whose Run loop has been started.
+	Webhooks *webhooks.Dispatcher
+	// RequireAuth rejects /api/v1 and /mcp requests that do not carry a
+	// valid bearer token. When false, tokens are honored but optional.
+	RequireAuth bool
 }
 
 // New creates a new API server.
@@ -55,15 +73,34 @@ func New(cfg ServerOptions) *Server {
 	e.Use(middleware.Recover())
 	e.Use(middleware.CORS())
 
+	bus := cfg.Events
+	if bus == nil {
+		bus = events.NewBus()
+		if n, ok := cfg.Store.(storage.Notifier); ok {
+			n.SetPublisher(bus)
+		}
+	}
+
+	dispatcher := cfg.Webhooks
+	if dispatcher == nil {
+		dispatcher = webhooks.New(cfg.Store, webhooks.Options{})
+	}
+
 	s := &Server{
 		echo:      e,
 		store:     cfg.Store,
+		events:    bus,
+		webhooks:  dispatcher,
+		auth:      &authenticator{requireAuth: cfg.RequireAuth, touched: make(map[string]time.Time)},
 		address:   cfg.Address,
 		startTime: time.Now(),
 	}
 
 	// Register routes
 	s.registerRoutes()
+	if cfg.MCP {
+		s.registerMCP()
+	}
 
 	// Serve embedded SPA for non-API routes
 	web.RegisterSPA(e)
@@ -92,10 +129,11 @@ func (s *Server) registerRoutes() {
 	s.echo.GET("/health", s.healthCheck)
 
 	// API v1 routes
-	v1 := s.echo.Group("/api/v1")
+	v1 := s.echo.Group("/api/v1", s.authenticate, s.authorize)
 
 	// Projects (top-level containers)
 	v1.POST("/projects/merge", s.mergeProjects)
+	v1.POST("/projects/import", s.importProject)
 	// Resolve must be registered before :id to avoid "resolve" being captured as an ID
 	v1.GET("/projects/resolve", s.resolveProject)
 	v1.GET("/projects", s.listProjects)
@@ -104,12 +142,18 @@ func (s *Server) registerRoutes() {
 	v1.PUT("/projects/:id", s.updateProject)
 	v1.DELETE("/projects/:id", s.deleteProject)
 	v1.GET("/projects/:id/stats", s.getProjectStats)
+	v1.GET("/projects/:id/export", s.exportProject)
 
 	// Per-project config (generic key/value settings)
 	v1.GET("/projects/:id/config", s.getProjectConfig)
 	v1.PUT("/projects/:id/config", s.putProjectConfig)
 	v1.DELETE("/projects/:id/config/:key", s.deleteProjectConfig)
 
+	// Project members (role-based access control)
+	v1.GET("/projects/:id/members", s.listMembers)
+	v1.POST("/projects/:id/members", s.addMember)
+	v1.DELETE("/projects/:id/members/:actor", s.removeMember)
+
 	// Filesystem browser
 	v1.GET("/filesystem/browse", s.browseFilesystem)
 
@@ -131,14 +175,25 @@ func (s *Server) registerRoutes() {
 	v1.DELETE("/plans/:planId/comments/:commentId", s.deletePlanComment)
 
 	// Issues (global lookup by unique ID — no project context required)
-	issues := v1.Group("/issues")
+	issues := v1.Group("/issues", s.resolveIssueAlias)
 	issues.GET("/:id", s.getIssueByID)
+	issues.GET("/:id/history", s.getIssueHistory)
+	issues.GET("/:id/critical-path", s.getCriticalPath)
+	issues.GET("/:id/unblocks", s.getUnblocks)
 	issues.PUT("/:id", s.updateIssue)
 	issues.POST("/:id/close", s.closeIssue)
+	issues.POST("/:id/merge", s.mergeIssue)
+	issues.POST("/:id/move", s.moveIssue)
 	issues.POST("/:id/deps", s.addDependency)
 	issues.DELETE("/:id/deps/:dep", s.removeDependency)
 	issues.POST("/:id/labels", s.addLabelToIssue)
 	issues.DELETE("/:id/labels/:label", s.removeLabelFromIssue)
+	issues.GET("/:id/plans", s.listIssuePlans)
+	issues.POST("/:id/plans", s.linkIssuePlan)
+	issues.DELETE("/:id/plans/:planId", s.unlinkIssuePlan)
+
+	// Full-text search (one project or every visible project)
+	v1.GET("/search", s.searchIssues)
 
 	// Labels (global)
 	v1.GET("/labels", s.listLabels)
@@ -150,14 +205,59 @@ func (s *Server) registerRoutes() {
 	v1.GET("/config", s.getConfig)
 	v1.PUT("/config", s.putConfig)
 
+	// API tokens
+	v1.GET("/tokens", s.listTokens)
+	v1.POST("/tokens", s.createToken)
+	v1.DELETE("/tokens/:id", s.revokeToken)
+
+	// Live change notifications (Server-Sent Events)
+	v1.GET("/events/stream", s.streamEvents)
+
 	// Project-scoped routes (issues, AI sessions, etc.)
 	s.registerProjectRoutes(v1)
 	s.registerProjectAIRoutes(v1)
 }
 
-// registerProjectRoutes sets up project-scoped issue, dependency, label, comment, and event routes.
+// registerMCP mounts the MCP streamable-HTTP endpoint. The MCP tools call
+// back into this server's own REST API through a loopback client so that
+// MCP and CLI callers share exactly the same code paths. Each MCP request
+// gets its own loopback client acting as the caller: an authenticated
+// caller is forwarded with an in-memory token revoked when the request
+// ends, so tool calls are authorized and audited as that caller.
+func (s *Server) registerMCP() {
+	baseURL := loopbackURL(s.address)
+	s.echo.Any("/mcp", func(c echo.Context) error {
+		backend := client.New(baseURL)
+		backend.SetActor(getActor(c))
+		if actor, ok := c.Get(actorContextKey).(string); ok && actor != "" {
+			token, revoke, err := s.auth.grantLoopback(actor, authToken(c))
+			if err != nil {
+				return errorJSON(c, http.StatusInternalServerError, "issue loopback token: "+err.Error())
+			}
+			defer revoke()
+			backend.SetToken(token)
+		}
+		mcp.NewServer(backend, mcp.Options{}).HTTPHandler().ServeHTTP(c.Response(), c.Request())
+		return nil
+	}, s.authenticate)
+}
+
+// loopbackURL converts a listen address such as ":7432" or "0.0.0.0:7432"
+// into a base URL reachable from this process.
+func loopbackURL(address string) string {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "http://" + address
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+	}
+	return "http://" + net.JoinHostPort(host, port)
+}
+
+// registerProjectRoutes sets up project-scoped issue, dependency, label, comment, event, and undo routes.
 func (s *Server) registerProjectRoutes(v1 *echo.Group) {
-	proj := v1.Group("/projects/:pid")
+	proj := v1.Group("/projects/:pid", s.resolveIssueAlias)
 	proj.GET("/issues", s.listIssues)
 	proj.POST("/issues", s.createIssue)
 	proj.GET("/issues/:id", s.getIssue)
@@ -165,12 +265,26 @@ func (s *Server) registerProjectRoutes(v1 *echo.Group) {
 	proj.DELETE("/issues/:id", s.deleteIssue)
 	proj.POST("/issues/:id/close", s.closeIssue)
 	proj.POST("/issues/:id/reopen", s.reopenIssue)
+	proj.POST("/issues/:id/merge", s.mergeIssue)
+	proj.POST("/issues/:id/move", s.moveIssue)
 	proj.GET("/ready", s.getReadyWork)
 	proj.GET("/blocked", s.getBlockedIssues)
 	proj.GET("/team-context", s.getTeamContext)
+	proj.GET("/duplicates", s.listDuplicates)
+	proj.POST("/import/beads", s.importBeads)
+	proj.POST("/sync/github", s.syncGitHub)
+	proj.GET("/templates", s.listIssueTemplates)
+	proj.GET("/templates/:name", s.getIssueTemplate)
+	proj.PUT("/templates/:name", s.saveIssueTemplate)
+	proj.DELETE("/templates/:name", s.deleteIssueTemplate)
+	proj.POST("/templates/:name/instantiate", s.instantiateIssueTemplate)
 	proj.GET("/issues/:id/deps", s.getDependencies)
 	proj.POST("/issues/:id/deps", s.addDependency)
 	proj.DELETE("/issues/:id/deps/:dep", s.removeDependency)
+	proj.GET("/issues/:id/critical-path", s.getCriticalPath)
+	proj.GET("/issues/:id/unblocks", s.getUnblocks)
+	proj.GET("/deps/validate", s.validateDependencies)
+	proj.GET("/graph", s.getGraph)
 	proj.POST("/issues/:id/labels", s.addLabelToIssue)
 	proj.DELETE("/issues/:id/labels/:label", s.removeLabelFromIssue)
 	proj.GET("/issues/:id/comments", s.getComments)
@@ -178,6 +292,13 @@ func (s *Server) registerProjectRoutes(v1 *echo.Group) {
 	proj.PUT("/issues/:id/comments/:cid", s.updateComment)
 	proj.DELETE("/issues/:id/comments/:cid", s.deleteComment)
 	proj.GET("/issues/:id/events", s.getEvents)
+	proj.GET("/issues/:id/history", s.getIssueHistory)
+	proj.POST("/undo", s.undoChanges)
+	proj.GET("/webhooks", s.listWebhooks)
+	proj.POST("/webhooks", s.createWebhook)
+	proj.DELETE("/webhooks/:wid", s.deleteWebhook)
+	proj.POST("/webhooks/:wid/test", s.testWebhook)
+	proj.GET("/webhooks/:wid/deliveries", s.listWebhookDeliveries)
 }
 
 // registerProjectAIRoutes sets up project-scoped AI session routes.
@@ -247,9 +368,13 @@ func createdJSON(c echo.Context, data any) error {
 	return c.JSON(http.StatusCreated, data)
 }
 
-// getActor extracts the actor (user) from the request.
-// TODO: Implement proper authentication
+// getActor returns the actor (user) for the request. A bearer token's actor
+// always wins; the X-Actor header is only consulted for unauthenticated
+// requests, which the server accepts unless auth is required.
 func getActor(c echo.Context) string {
+	if actor, ok := c.Get(actorContextKey).(string); ok && actor != "" {
+		return actor
+	}
 	actor := c.Request().Header.Get("X-Actor")
 	if actor == "" {
 		actor = "anonymous"
@@ -303,7 +428,8 @@ func (s *Server) validateIssueProject(c echo.Context, issueID string) error {
 }
 
 // getIssueInProject fetches an issue and validates it belongs to the specified project.
-// When called from a project-agnostic route (no :pid param), the project check is skipped.
+// When called from a project-agnostic route (no :pid param), the project check is skipped;
+// access control still applies because authorize resolves the issue's project.
 // Returns the issue if valid, or an error if not found or project mismatch.
 func (s *Server) getIssueInProject(c echo.Context, issueID string) (*types.Issue, error) {
 	pID := projectID(c)
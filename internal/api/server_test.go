@@ -1,9 +1,11 @@
 package api //nolint:testpackage // tests use internal helpers that access unexported fields
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -42,3 +44,116 @@ func TestHealthCheck(t *testing.T) {
 		t.Errorf("webui_url = %q, want empty (port is 0 and webui not compiled)", health.WebUIURL)
 	}
 }
+
+func TestLoopbackURL(t *testing.T) {
+	tests := []struct {
+		address string
+		want    string
+	}{
+		{":7432", "http://127.0.0.1:7432"},
+		{"0.0.0.0:7432", "http://127.0.0.1:7432"},
+		{"localhost:8080", "http://localhost:8080"},
+	}
+	for _, tt := range tests {
+		if got := loopbackURL(tt.address); got != tt.want {
+			t.Errorf("loopbackURL(%q) = %q, want %q", tt.address, got, tt.want)
+		}
+	}
+}
+
+func TestMCPEndpointMounted(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+
+	// Disabled by default.
+	body := `{"jsonrpc":"2.0","id":1,"method":"ping"}`
+	req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("POST /mcp without MCP enabled returned %d, want 404", rec.Code)
+	}
+
+	server.registerMCP()
+	req = httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+	rec = httptest.NewRecorder()
+	server.echo.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST /mcp returned %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+// callMCPTool calls an MCP tool over the HTTP endpoint and returns the
+// structured result, failing the test if the call errors.
+func callMCPTool(t *testing.T, server *Server, token, name, args string) map[string]any {
+	t.Helper()
+	result, errText := tryMCPTool(t, server, token, name, args)
+	if errText != "" {
+		t.Fatalf("%s failed: %s", name, errText)
+	}
+	return result
+}
+
+// tryMCPTool calls an MCP tool and returns its structured result, or the
+// tool's error text when the call failed.
+func tryMCPTool(t *testing.T, server *Server, token, name, args string) (map[string]any, string) {
+	t.Helper()
+	body := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"` + name + `","arguments":` + args + `}}`
+	rec := doAuthRequest(server.echo, http.MethodPost, "/mcp", body, token)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST /mcp returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Result struct {
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+			StructuredContent map[string]any `json:"structuredContent"`
+			IsError           bool           `json:"isError"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode MCP response: %v", err)
+	}
+	if resp.Result.IsError {
+		var texts []string
+		for _, c := range resp.Result.Content {
+			texts = append(texts, c.Text)
+		}
+		return nil, strings.Join(texts, "\n")
+	}
+	return resp.Result.StructuredContent, ""
+}
+
+func TestMCPActsAsCaller(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	e := server.echo
+
+	// The MCP tools call back into the API, so it has to be listening.
+	listener := httptest.NewServer(e)
+	defer listener.Close()
+	server.address = listener.Listener.Addr().String()
+	server.registerMCP()
+
+	pID := createTestProject(t, e)
+	tok := createTestToken(t, e, `{"name": "agent", "actor": "alice"}`, "")
+
+	issue := callMCPTool(t, server, tok.Token, "create_issue", `{"project": "`+pID+`", "title": "From MCP"}`)
+	id, _ := issue["id"].(string)
+	events, err := server.store.GetEvents(context.Background(), id, 0)
+	if err != nil || len(events) == 0 {
+		t.Fatalf("GetEvents(%q) = %d, %v", id, len(events), err)
+	}
+	if events[0].Actor != "alice" {
+		t.Errorf("event actor = %q, want alice", events[0].Actor)
+	}
+
+	// The token minted for the tool calls dies with the request.
+	server.auth.mu.Lock()
+	live := len(server.auth.loopback)
+	server.auth.mu.Unlock()
+	if live != 0 {
+		t.Errorf("%d loopback tokens still live after the request", live)
+	}
+}
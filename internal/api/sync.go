@@ -0,0 +1,61 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"os"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sentiolabs/arc/internal/ghsync"
+)
+
+// githubSyncRequest is the request body for syncing with GitHub.
+type githubSyncRequest struct {
+	Repo   string `json:"repo"`
+	Token  string `json:"token"`
+	APIURL string `json:"api_url"`
+	DryRun bool   `json:"dry_run"`
+}
+
+// syncGitHub runs a two-way sync between a project and a GitHub
+// repository. Repo defaults to the one the project last synced with. The
+// server's GITHUB_TOKEN is used when the request has no token, but only
+// against the default API URL so it is never sent to a caller-chosen host.
+func (s *Server) syncGitHub(c echo.Context) error {
+	ctx := c.Request().Context()
+	pID := projectID(c)
+
+	var req githubSyncRequest
+	if err := c.Bind(&req); err != nil {
+		return errorJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+	if req.Repo == "" {
+		repo, err := ghsync.ConfiguredRepo(ctx, s.store, pID)
+		if err != nil {
+			if errors.Is(err, ghsync.ErrNoRepo) {
+				return errorJSON(c, http.StatusBadRequest, "repo is required")
+			}
+			return errorJSON(c, http.StatusInternalServerError, err.Error())
+		}
+		req.Repo = repo
+	}
+	if req.Token == "" && req.APIURL == "" {
+		req.Token = os.Getenv("GITHUB_TOKEN")
+	}
+
+	gh := ghsync.NewClient(req.APIURL, req.Token, nil)
+	report, err := ghsync.Sync(ctx, s.store, gh, ghsync.Options{
+		ProjectID: pID,
+		Repo:      req.Repo,
+		Actor:     getActor(c),
+		DryRun:    req.DryRun,
+	})
+	if err != nil {
+		if errors.Is(err, ghsync.ErrRemote) {
+			return errorJSON(c, http.StatusBadGateway, err.Error())
+		}
+		return errorJSON(c, http.StatusBadRequest, err.Error())
+	}
+
+	return successJSON(c, report)
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sentiolabs/arc/internal/auth"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// createTokenRequest is the request body for issuing an API token.
+type createTokenRequest struct {
+	Name  string `json:"name"`
+	Actor string `json:"actor,omitempty"`
+}
+
+// listTokens returns API tokens. A token-authenticated caller only sees
+// tokens for its own actor.
+func (s *Server) listTokens(c echo.Context) error {
+	tokens, err := s.store.ListAPITokens(c.Request().Context())
+	if err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+
+	if caller := authToken(c); caller != nil {
+		own := make([]*types.APIToken, 0, len(tokens))
+		for _, tok := range tokens {
+			if tok.Actor == caller.Actor {
+				own = append(own, tok)
+			}
+		}
+		tokens = own
+	}
+
+	return successJSON(c, tokens)
+}
+
+// createToken issues a new API token. The plaintext token appears only in
+// this response. Authenticated callers can only issue tokens for themselves;
+// unauthenticated callers (allowed while auth is not required) may name any
+// actor, which is how the first tokens are bootstrapped.
+func (s *Server) createToken(c echo.Context) error {
+	var req createTokenRequest
+	if err := c.Bind(&req); err != nil {
+		return errorJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+	if req.Name == "" {
+		return errorJSON(c, http.StatusBadRequest, "name is required")
+	}
+
+	actor := req.Actor
+	if actor == "" {
+		actor = getActor(c)
+	}
+	if caller := authToken(c); caller != nil && actor != caller.Actor {
+		return errorJSON(c, http.StatusForbidden, "cannot create tokens for another actor")
+	}
+
+	raw, err := auth.GenerateToken()
+	if err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+	tok := &types.APIToken{
+		Name:   req.Name,
+		Actor:  actor,
+		Prefix: auth.Prefix(raw),
+	}
+	if err := s.store.CreateAPIToken(c.Request().Context(), tok, auth.HashToken(raw)); err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+	tok.Token = raw
+
+	return createdJSON(c, tok)
+}
+
+// revokeToken revokes an API token. Authenticated callers can only revoke
+// their own actor's tokens.
+func (s *Server) revokeToken(c echo.Context) error {
+	ctx := c.Request().Context()
+	tok, err := s.store.GetAPIToken(ctx, c.Param("id"))
+	if err != nil {
+		return errorJSON(c, http.StatusNotFound, err.Error())
+	}
+	if caller := authToken(c); caller != nil && tok.Actor != caller.Actor {
+		return errorJSON(c, http.StatusForbidden, "cannot revoke tokens for another actor")
+	}
+
+	if err := s.store.RevokeAPIToken(ctx, tok.ID); err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
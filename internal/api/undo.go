@@ -0,0 +1,58 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sentiolabs/arc/internal/types"
+	"github.com/sentiolabs/arc/internal/undo"
+)
+
+// undoRequest is the request body for undoing recent changes.
+type undoRequest struct {
+	Actor    string  `json:"actor"`     // only undo this actor's changes
+	Last     int     `json:"last"`      // number of changes to undo (default 1)
+	EventIDs []int64 `json:"event_ids"` // undo exactly these events instead
+	DryRun   bool    `json:"dry_run"`   // plan without applying
+}
+
+// undoChanges reverses the most recent reversible changes in a project and
+// returns one step per change. With dry_run the steps are only planned.
+// With event_ids exactly those events are undone, typically the steps of a
+// confirmed dry run; any that now conflict come back skipped. Each applied
+// reversal is recorded as an undone event by the requesting actor.
+func (s *Server) undoChanges(c echo.Context) error {
+	ctx := c.Request().Context()
+	pID := projectID(c)
+
+	var req undoRequest
+	if err := c.Bind(&req); err != nil {
+		return errorJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+	if req.Last == 0 {
+		req.Last = 1
+	}
+	if req.Last < 0 {
+		return errorJSON(c, http.StatusBadRequest, "last must be positive")
+	}
+
+	if _, err := s.store.GetProject(ctx, pID); err != nil {
+		return errorJSON(c, http.StatusNotFound, err.Error())
+	}
+
+	var steps []*types.UndoStep
+	var err error
+	if len(req.EventIDs) > 0 {
+		steps, err = undo.PlanEvents(ctx, s.store, pID, req.EventIDs)
+	} else {
+		steps, err = undo.Plan(ctx, s.store, pID, req.Actor, req.Last)
+	}
+	if err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+	if !req.DryRun {
+		// A failed step carries its error; the steps are returned either way.
+		_ = undo.Apply(ctx, s.store, steps, getActor(c))
+	}
+	return successJSON(c, steps)
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// defaultDeliveryLimit is the number of log entries returned by listWebhookDeliveries.
+const defaultDeliveryLimit = 50
+
+// createWebhookRequest is the request body for registering a webhook.
+type createWebhookRequest struct {
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret,omitempty"`
+	EventTypes []string `json:"event_types,omitempty"`
+	Active     *bool    `json:"active,omitempty"`
+}
+
+// listWebhooks returns a project's webhooks. Secrets are never included.
+func (s *Server) listWebhooks(c echo.Context) error {
+	pID := projectID(c)
+	if _, err := s.store.GetProject(c.Request().Context(), pID); err != nil {
+		return errorJSON(c, http.StatusNotFound, err.Error())
+	}
+
+	hooks, err := s.store.ListWebhooks(c.Request().Context(), pID)
+	if err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+	for _, wh := range hooks {
+		wh.Secret = ""
+	}
+
+	return successJSON(c, hooks)
+}
+
+// createWebhook registers a webhook. The response is the only place the
+// secret is returned, so clients must show or store it immediately.
+func (s *Server) createWebhook(c echo.Context) error {
+	pID := projectID(c)
+	if _, err := s.store.GetProject(c.Request().Context(), pID); err != nil {
+		return errorJSON(c, http.StatusNotFound, err.Error())
+	}
+
+	var req createWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return errorJSON(c, http.StatusBadRequest, "invalid request body")
+	}
+
+	wh := &types.Webhook{
+		ProjectID:  pID,
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: splitQueryList(req.EventTypes),
+		Active:     req.Active == nil || *req.Active,
+	}
+	if err := wh.Validate(); err != nil {
+		return errorJSON(c, http.StatusBadRequest, err.Error())
+	}
+	if err := s.store.CreateWebhook(c.Request().Context(), wh); err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return createdJSON(c, wh)
+}
+
+// deleteWebhook removes a webhook and its delivery log.
+func (s *Server) deleteWebhook(c echo.Context) error {
+	if _, err := s.getWebhookInProject(c); err != nil {
+		return errorJSON(c, http.StatusNotFound, err.Error())
+	}
+
+	if err := s.store.DeleteWebhook(c.Request().Context(), c.Param("wid")); err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// testWebhook sends a webhook.ping delivery synchronously and returns the
+// logged delivery, including the receiver's response status or error.
+func (s *Server) testWebhook(c echo.Context) error {
+	if _, err := s.getWebhookInProject(c); err != nil {
+		return errorJSON(c, http.StatusNotFound, err.Error())
+	}
+
+	delivery, err := s.webhooks.Test(c.Request().Context(), c.Param("wid"))
+	if err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return successJSON(c, delivery)
+}
+
+// listWebhookDeliveries returns a webhook's delivery log, newest first.
+func (s *Server) listWebhookDeliveries(c echo.Context) error {
+	if _, err := s.getWebhookInProject(c); err != nil {
+		return errorJSON(c, http.StatusNotFound, err.Error())
+	}
+
+	limit := queryInt(c, "limit", defaultDeliveryLimit)
+	deliveries, err := s.store.ListWebhookDeliveries(c.Request().Context(), c.Param("wid"), limit)
+	if err != nil {
+		return errorJSON(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return successJSON(c, deliveries)
+}
+
+// getWebhookInProject fetches the :wid webhook, treating a webhook from
+// another project as not found.
+func (s *Server) getWebhookInProject(c echo.Context) (*types.Webhook, error) {
+	wh, err := s.store.GetWebhook(c.Request().Context(), c.Param("wid"))
+	if err != nil {
+		return nil, err
+	}
+	if wh.ProjectID != projectID(c) {
+		return nil, fmt.Errorf("webhook not found: %s", wh.ID)
+	}
+	return wh, nil
+}
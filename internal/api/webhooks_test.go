@@ -0,0 +1,117 @@
+package api //nolint:testpackage // tests use internal helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sentiolabs/arc/internal/types"
+	"github.com/sentiolabs/arc/internal/webhooks"
+)
+
+// doWebhookRequest sends a JSON request to the echo instance and returns the recorder.
+func doWebhookRequest(e *echo.Echo, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestWebhookLifecycle(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	e := server.echo
+	pID := createTestProject(t, e)
+	base := "/api/v1/projects/" + pID + "/webhooks"
+
+	var signatureOK bool
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		signatureOK = webhooks.Verify("topsecret", body, r.Header.Get(webhooks.SignatureHeader))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer receiver.Close()
+
+	rec := doWebhookRequest(e, http.MethodPost, base,
+		`{"url": "`+receiver.URL+`", "secret": "topsecret", "event_types": ["issue.closed"]}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create webhook returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var created types.Webhook
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode webhook: %v", err)
+	}
+	if created.Secret != "topsecret" || !created.Active {
+		t.Errorf("created webhook = %+v", created)
+	}
+
+	// Listing never exposes secrets.
+	rec = doWebhookRequest(e, http.MethodGet, base, "")
+	var hooks []types.Webhook
+	if err := json.Unmarshal(rec.Body.Bytes(), &hooks); err != nil {
+		t.Fatalf("decode webhooks: %v", err)
+	}
+	if len(hooks) != 1 || hooks[0].Secret != "" || hooks[0].EventTypes[0] != "issue.closed" {
+		t.Errorf("listed webhooks = %+v", hooks)
+	}
+
+	rec = doWebhookRequest(e, http.MethodPost, base+"/"+created.ID+"/test", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("test webhook returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var delivery types.WebhookDelivery
+	if err := json.Unmarshal(rec.Body.Bytes(), &delivery); err != nil {
+		t.Fatalf("decode delivery: %v", err)
+	}
+	if delivery.Status != types.DeliverySucceeded || *delivery.ResponseStatus != http.StatusAccepted || !signatureOK {
+		t.Errorf("test delivery = %+v, signature ok = %v", delivery, signatureOK)
+	}
+
+	rec = doWebhookRequest(e, http.MethodGet, base+"/"+created.ID+"/deliveries", "")
+	var deliveries []types.WebhookDelivery
+	if err := json.Unmarshal(rec.Body.Bytes(), &deliveries); err != nil {
+		t.Fatalf("decode deliveries: %v", err)
+	}
+	if len(deliveries) != 1 || deliveries[0].EventType != webhooks.PingEvent {
+		t.Errorf("deliveries = %+v", deliveries)
+	}
+
+	if rec = doWebhookRequest(e, http.MethodDelete, base+"/"+created.ID, ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("delete webhook returned %d", rec.Code)
+	}
+	if rec = doWebhookRequest(e, http.MethodGet, base+"/"+created.ID+"/deliveries", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("deliveries of deleted webhook returned %d, want 404", rec.Code)
+	}
+}
+
+func TestWebhookValidationAndScoping(t *testing.T) {
+	server, cleanup := testServer(t)
+	defer cleanup()
+	e := server.echo
+	pID := createTestProject(t, e)
+
+	rec := doWebhookRequest(e, http.MethodPost, "/api/v1/projects/"+pID+"/webhooks", `{"url": "not a url"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid url returned %d, want 400", rec.Code)
+	}
+
+	rec = doWebhookRequest(e, http.MethodPost, "/api/v1/projects/"+pID+"/webhooks", `{"url": "http://localhost:1/x"}`)
+	var created types.Webhook
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode webhook: %v", err)
+	}
+	if created.Secret == "" {
+		t.Error("expected a generated secret")
+	}
+
+	// A webhook cannot be reached through another project's routes.
+	rec = doWebhookRequest(e, http.MethodDelete, "/api/v1/projects/other/webhooks/"+created.ID, "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("cross-project delete returned %d, want 404", rec.Code)
+	}
+}
@@ -175,7 +175,7 @@ func (m *mockWPStore) ListIssues(_ context.Context, _ types.IssueFilter) ([]*typ
 	panic("not implemented")
 }
 
-func (m *mockWPStore) UpdateIssue(_ context.Context, _ string, _ map[string]any, _ string) error {
+func (m *mockWPStore) UpdateIssue(_ context.Context, _ string, _ map[string]any, _ int64, _ string) error {
 	panic("not implemented")
 }
 
@@ -191,6 +191,22 @@ func (m *mockWPStore) GetIssueDetails(_ context.Context, _ string) (*types.Issue
 	panic("not implemented")
 }
 
+func (m *mockWPStore) MergeIssue(_ context.Context, _, _, _ string) (*types.IssueMergeResult, error) {
+	panic("not implemented")
+}
+
+func (m *mockWPStore) MoveIssue(_ context.Context, _, _, _ string) (*types.IssueMoveResult, error) {
+	panic("not implemented")
+}
+
+func (m *mockWPStore) ResolveIssueAlias(_ context.Context, _ string) (string, error) {
+	panic("not implemented")
+}
+
+func (m *mockWPStore) SearchIssues(_ context.Context, _ types.SearchFilter) ([]*types.SearchHit, error) {
+	panic("not implemented")
+}
+
 func (m *mockWPStore) GetReadyWork(_ context.Context, _ types.WorkFilter) ([]*types.Issue, error) {
 	panic("not implemented")
 }
@@ -251,6 +267,10 @@ func (m *mockWPStore) GetLabelsForIssues(_ context.Context, _ []string) (map[str
 	panic("not implemented")
 }
 
+func (m *mockWPStore) GetFieldsForIssues(_ context.Context, _ []string) (map[string]map[string]string, error) {
+	panic("not implemented")
+}
+
 func (m *mockWPStore) AddComment(_ context.Context, _, _, _ string) (*types.Comment, error) {
 	panic("not implemented")
 }
@@ -277,6 +297,16 @@ func (m *mockWPStore) UpdatePlanStatus(_ context.Context, _, _ string) error {
 
 func (m *mockWPStore) DeletePlan(_ context.Context, _ string) error { panic("not implemented") }
 
+func (m *mockWPStore) SetPlanText(_ context.Context, _, _ string) error { panic("not implemented") }
+
+func (m *mockWPStore) LinkIssuePlan(_ context.Context, _, _ string) error { panic("not implemented") }
+
+func (m *mockWPStore) UnlinkIssuePlan(_ context.Context, _, _ string) error { panic("not implemented") }
+
+func (m *mockWPStore) ListIssuePlans(_ context.Context, _ string) ([]*types.Plan, error) {
+	panic("not implemented")
+}
+
 func (m *mockWPStore) CreatePlanComment(_ context.Context, _ *types.PlanComment) error {
 	panic("not implemented")
 }
@@ -301,6 +331,14 @@ func (m *mockWPStore) GetEvents(_ context.Context, _ string, _ int) ([]*types.Ev
 	panic("not implemented")
 }
 
+func (m *mockWPStore) GetRecentEvents(_ context.Context, _ string, _ int) ([]*types.Event, error) {
+	panic("not implemented")
+}
+
+func (m *mockWPStore) AddEvent(_ context.Context, _ *types.Event) error {
+	panic("not implemented")
+}
+
 func (m *mockWPStore) GetStatistics(_ context.Context, _ string) (*types.Statistics, error) {
 	panic("not implemented")
 }
@@ -343,6 +381,116 @@ func (m *mockWPStore) GetAgentSummariesForSessions(
 	panic("not implemented")
 }
 
+func (m *mockWPStore) CreateWebhook(_ context.Context, _ *types.Webhook) error {
+	panic("not implemented")
+}
+
+func (m *mockWPStore) GetWebhook(_ context.Context, _ string) (*types.Webhook, error) {
+	panic("not implemented")
+}
+
+func (m *mockWPStore) ListWebhooks(_ context.Context, _ string) ([]*types.Webhook, error) {
+	panic("not implemented")
+}
+
+func (m *mockWPStore) DeleteWebhook(_ context.Context, _ string) error {
+	panic("not implemented")
+}
+
+func (m *mockWPStore) CreateWebhookDelivery(_ context.Context, _ *types.WebhookDelivery) error {
+	panic("not implemented")
+}
+
+func (m *mockWPStore) GetWebhookDelivery(_ context.Context, _ int64) (*types.WebhookDelivery, error) {
+	panic("not implemented")
+}
+
+func (m *mockWPStore) ListWebhookDeliveries(_ context.Context, _ string, _ int) ([]*types.WebhookDelivery, error) {
+	panic("not implemented")
+}
+
+func (m *mockWPStore) ListDueWebhookDeliveries(
+	_ context.Context, _ time.Time, _ int,
+) ([]*types.WebhookDelivery, error) {
+	panic("not implemented")
+}
+
+func (m *mockWPStore) UpdateWebhookDelivery(_ context.Context, _ *types.WebhookDelivery) error {
+	panic("not implemented")
+}
+
+func (m *mockWPStore) SaveIssueTemplate(_ context.Context, _ *types.IssueTemplate) error {
+	panic("not implemented")
+}
+
+func (m *mockWPStore) GetIssueTemplate(_ context.Context, _, _ string) (*types.IssueTemplate, error) {
+	panic("not implemented")
+}
+
+func (m *mockWPStore) ListIssueTemplates(_ context.Context, _ string) ([]*types.IssueTemplate, error) {
+	panic("not implemented")
+}
+
+func (m *mockWPStore) ListDueIssueTemplates(_ context.Context, _ time.Time) ([]*types.IssueTemplate, error) {
+	panic("not implemented")
+}
+
+func (m *mockWPStore) RecordIssueTemplateRun(_ context.Context, _, _ string, _ time.Time, _ *time.Time) error {
+	panic("not implemented")
+}
+
+func (m *mockWPStore) DeleteIssueTemplate(_ context.Context, _, _ string) error {
+	panic("not implemented")
+}
+
+func (m *mockWPStore) ExportProject(_ context.Context, _ string) (*types.ProjectArchive, error) {
+	panic("not implemented")
+}
+
+func (m *mockWPStore) ImportProject(_ context.Context, _ *types.ProjectArchive) error {
+	panic("not implemented")
+}
+
+func (m *mockWPStore) ListProjectMembers(_ context.Context, _ string) ([]*types.ProjectMember, error) {
+	return nil, nil
+}
+
+func (m *mockWPStore) GetProjectMember(_ context.Context, _, _ string) (*types.ProjectMember, error) {
+	panic("not implemented")
+}
+
+func (m *mockWPStore) SetProjectMember(_ context.Context, _ *types.ProjectMember) error {
+	panic("not implemented")
+}
+
+func (m *mockWPStore) RemoveProjectMember(_ context.Context, _, _ string) error {
+	panic("not implemented")
+}
+
+func (m *mockWPStore) CreateAPIToken(_ context.Context, _ *types.APIToken, _ string) error {
+	panic("not implemented")
+}
+
+func (m *mockWPStore) GetAPIToken(_ context.Context, _ string) (*types.APIToken, error) {
+	panic("not implemented")
+}
+
+func (m *mockWPStore) GetAPITokenByHash(_ context.Context, _ string) (*types.APIToken, error) {
+	panic("not implemented")
+}
+
+func (m *mockWPStore) ListAPITokens(_ context.Context) ([]*types.APIToken, error) {
+	panic("not implemented")
+}
+
+func (m *mockWPStore) RevokeAPIToken(_ context.Context, _ string) error {
+	panic("not implemented")
+}
+
+func (m *mockWPStore) TouchAPIToken(_ context.Context, _ string, _ time.Time) error {
+	panic("not implemented")
+}
+
 func (m *mockWPStore) Close() error { return nil }
 func (m *mockWPStore) Path() string { return "" }
 
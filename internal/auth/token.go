@@ -0,0 +1,42 @@
+// Package auth generates and hashes API tokens.
+//
+// Tokens are 32 random bytes, hex-encoded behind a recognizable "arc_"
+// prefix. Because they carry full entropy, a plain SHA-256 digest is a safe
+// at-rest representation and lets the server look tokens up by hash.
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// TokenPrefix starts every API token so leaked tokens are easy to spot.
+const TokenPrefix = "arc_"
+
+// tokenBytes is the entropy of a generated token.
+const tokenBytes = 32
+
+// displayPrefixLen is how much of a token Prefix keeps for identification.
+const displayPrefixLen = len(TokenPrefix) + 8
+
+// GenerateToken returns a new random API token.
+func GenerateToken() (string, error) {
+	buf := make([]byte, tokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate token: %w", err)
+	}
+	return TokenPrefix + hex.EncodeToString(buf), nil
+}
+
+// HashToken returns the hex-encoded SHA-256 digest stored for token.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Prefix returns the leading characters of token that are safe to display.
+func Prefix(token string) string {
+	return token[:min(displayPrefixLen, len(token))]
+}
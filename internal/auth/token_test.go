@@ -0,0 +1,47 @@
+package auth_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/auth"
+)
+
+func TestGenerateToken(t *testing.T) {
+	a, err := auth.GenerateToken()
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	b, err := auth.GenerateToken()
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	if !strings.HasPrefix(a, auth.TokenPrefix) || len(a) != len(auth.TokenPrefix)+64 {
+		t.Errorf("GenerateToken = %q, want %q followed by 64 hex characters", a, auth.TokenPrefix)
+	}
+	if a == b {
+		t.Error("GenerateToken returned the same token twice")
+	}
+	if got := auth.Prefix(a); got != a[:12] {
+		t.Errorf("Prefix = %q, want %q", got, a[:12])
+	}
+	if got := auth.Prefix("arc_"); got != "arc_" {
+		t.Errorf("Prefix of short token = %q", got)
+	}
+}
+
+func TestHashToken(t *testing.T) {
+	// SHA-256("arc_test"), so stored hashes stay stable across releases.
+	const want = "1ec535b3d6031258cf7a04faca03b9d67323af3121551b0c982671ab24d257f7"
+	got := auth.HashToken("arc_test")
+	if len(got) != 64 {
+		t.Fatalf("HashToken length = %d, want 64", len(got))
+	}
+	if got != want {
+		t.Errorf("HashToken = %q, want %q", got, want)
+	}
+	if auth.HashToken("arc_other") == got {
+		t.Error("different tokens hashed to the same value")
+	}
+}
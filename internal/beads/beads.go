@@ -0,0 +1,84 @@
+// Package beads reads issue exports from beads repositories
+// (.beads/issues.jsonl) and imports them into an arc project.
+//
+// Each line of a beads export is one issue with its labels, dependencies,
+// and comments inline. Only the fields arc can represent are decoded;
+// everything else is ignored.
+package beads
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// DefaultPath is where beads keeps its export inside a repository.
+const DefaultPath = ".beads/issues.jsonl"
+
+// maxLineBytes bounds a single issue line.
+const maxLineBytes = 16 << 20
+
+// Issue is one beads issue as it appears in issues.jsonl.
+type Issue struct {
+	ID                 string        `json:"id"`
+	Title              string        `json:"title"`
+	Description        string        `json:"description,omitempty"`
+	Design             string        `json:"design,omitempty"`
+	AcceptanceCriteria string        `json:"acceptance_criteria,omitempty"`
+	Notes              string        `json:"notes,omitempty"`
+	Status             string        `json:"status,omitempty"`
+	Priority           int           `json:"priority"`
+	IssueType          string        `json:"issue_type,omitempty"`
+	CreatedAt          time.Time     `json:"created_at"`
+	UpdatedAt          time.Time     `json:"updated_at"`
+	ClosedAt           *time.Time    `json:"closed_at,omitempty"`
+	CloseReason        string        `json:"close_reason,omitempty"`
+	Labels             []string      `json:"labels,omitempty"`
+	Dependencies       []*Dependency `json:"dependencies,omitempty"`
+	Comments           []*Comment    `json:"comments,omitempty"`
+}
+
+// Dependency is a beads dependency. IssueID depends on DependsOnID.
+type Dependency struct {
+	IssueID     string    `json:"issue_id"`
+	DependsOnID string    `json:"depends_on_id"`
+	Type        string    `json:"type"`
+	CreatedAt   time.Time `json:"created_at"`
+	CreatedBy   string    `json:"created_by,omitempty"`
+}
+
+// Comment is a beads issue comment.
+type Comment struct {
+	Author    string    `json:"author"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Read decodes a beads issues.jsonl stream. Blank lines are ignored.
+func Read(r io.Reader) ([]*Issue, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
+
+	issues := []*Issue{}
+	line := 0
+	for sc.Scan() {
+		line++
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var issue Issue
+		if err := json.Unmarshal(sc.Bytes(), &issue); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if issue.ID == "" {
+			return nil, fmt.Errorf("line %d: issue has no id", line)
+		}
+		issues = append(issues, &issue)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read beads issues: %w", err)
+	}
+	return issues, nil
+}
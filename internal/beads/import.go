@@ -0,0 +1,267 @@
+package beads
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/project"
+	"github.com/sentiolabs/arc/internal/storage"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// statusTombstone marks issues deleted in beads; they are not imported.
+const statusTombstone = "tombstone"
+
+// Import adds beads issues to a project. Each arc issue records its beads
+// ID in ExternalRef, and issues already imported on an earlier run are
+// skipped, so re-running an import only adds what is new. Dependencies
+// between previously imported issues are added when missing. Labels and
+// comments are carried over for newly created issues.
+//
+//nolint:gocognit,funlen // one pass each over issues and dependencies
+func Import(
+	ctx context.Context, store storage.Storage, projectID string, issues []*Issue, actor string,
+) (*types.BeadsImportResult, error) {
+	proj, err := store.GetProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &types.BeadsImportResult{IDMap: make(map[string]string)}
+	warned := make(map[string]bool)
+	warn := func(format string, args ...any) {
+		msg := fmt.Sprintf(format, args...)
+		if !warned[msg] {
+			warned[msg] = true
+			result.Warnings = append(result.Warnings, msg)
+		}
+	}
+
+	archive := &types.ProjectArchive{Project: proj}
+	created := make(map[string]bool)
+	kept := make([]*Issue, 0, len(issues))
+	now := time.Now()
+
+	for _, bi := range issues {
+		if bi.Status == statusTombstone {
+			result.Ignored++
+			continue
+		}
+		if _, dup := result.IDMap[bi.ID]; dup {
+			warn("duplicate beads issue %s; kept the first", bi.ID)
+			continue
+		}
+		kept = append(kept, bi)
+
+		existing, err := store.GetIssueByExternalRef(ctx, bi.ID)
+		if err == nil {
+			if existing.ProjectID != projectID {
+				return nil, fmt.Errorf("beads issue %s was already imported into project %s as %s",
+					bi.ID, existing.ProjectID, existing.ID)
+			}
+			result.IDMap[bi.ID] = existing.ID
+			result.Skipped++
+			continue
+		}
+
+		issue := convertIssue(bi, projectID, now, warn)
+		issue.ID = newIssueID(ctx, store, proj.Prefix, bi, created)
+		created[issue.ID] = true
+		result.IDMap[bi.ID] = issue.ID
+		archive.Issues = append(archive.Issues, issue)
+
+		title := issue.Title
+		archive.Events = append(archive.Events, &types.Event{
+			IssueID: issue.ID, EventType: types.EventCreated, Actor: actor, NewValue: &title, CreatedAt: issue.CreatedAt,
+		})
+		for _, bc := range bi.Comments {
+			archive.Comments = append(archive.Comments, &types.Comment{
+				IssueID: issue.ID, Author: bc.Author, Text: bc.Text, CreatedAt: bc.CreatedAt,
+			})
+		}
+	}
+
+	// Dependencies are resolved once every issue has an arc ID. Those
+	// between two previously imported issues go through AddDependency so
+	// existing ones are left alone.
+	type depKey struct{ from, to string }
+	seen := make(map[depKey]bool)
+	var late []*types.Dependency
+	for _, bi := range kept {
+		for _, bd := range bi.Dependencies {
+			fromRef := bd.IssueID
+			if fromRef == "" {
+				fromRef = bi.ID
+			}
+			from, okFrom := result.IDMap[fromRef]
+			to, okTo := result.IDMap[bd.DependsOnID]
+			if !okFrom || !okTo {
+				warn("skipped dependency %s -> %s: issue not in import", fromRef, bd.DependsOnID)
+				continue
+			}
+			key := depKey{from, to}
+			if from == to || seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			dep := &types.Dependency{
+				IssueID: from, DependsOnID: to, Type: convertDepType(bd.Type, warn),
+				CreatedAt: bd.CreatedAt, CreatedBy: bd.CreatedBy,
+			}
+			if created[from] || created[to] {
+				archive.Dependencies = append(archive.Dependencies, dep)
+			} else {
+				late = append(late, dep)
+			}
+		}
+	}
+
+	if len(archive.Issues) > 0 {
+		if err := store.ImportProject(ctx, archive); err != nil {
+			return nil, err
+		}
+	}
+	result.Created = len(archive.Issues)
+	result.Comments = len(archive.Comments)
+	result.Dependencies = len(archive.Dependencies)
+
+	for _, dep := range late {
+		added, err := addMissingDependency(ctx, store, dep, actor)
+		if err != nil {
+			return nil, err
+		}
+		if added {
+			result.Dependencies++
+		}
+	}
+
+	return result, nil
+}
+
+// convertIssue maps a beads issue onto an arc issue without an ID.
+func convertIssue(bi *Issue, projectID string, now time.Time, warn func(string, ...any)) *types.Issue {
+	issue := &types.Issue{
+		ProjectID:   projectID,
+		Title:       bi.Title,
+		Description: composeDescription(bi),
+		Status:      convertStatus(bi.Status, warn),
+		Priority:    min(max(bi.Priority, 0), 4),
+		IssueType:   convertType(bi.IssueType, warn),
+		ExternalRef: bi.ID,
+		CreatedAt:   bi.CreatedAt,
+		UpdatedAt:   bi.UpdatedAt,
+		Labels:      bi.Labels,
+	}
+	if issue.Title == "" {
+		issue.Title = bi.ID
+	}
+	if issue.UpdatedAt.IsZero() {
+		issue.UpdatedAt = issue.CreatedAt
+	}
+
+	if issue.Status == types.StatusClosed {
+		closedAt := now
+		switch {
+		case bi.ClosedAt != nil:
+			closedAt = *bi.ClosedAt
+		case !bi.UpdatedAt.IsZero():
+			closedAt = bi.UpdatedAt
+		}
+		issue.ClosedAt = &closedAt
+		issue.CloseReason = bi.CloseReason
+	}
+	return issue
+}
+
+// composeDescription folds the beads design, acceptance criteria, and
+// notes fields into the description as markdown sections.
+func composeDescription(bi *Issue) string {
+	parts := []string{}
+	if s := strings.TrimSpace(bi.Description); s != "" {
+		parts = append(parts, s)
+	}
+	for _, section := range []struct{ heading, body string }{
+		{"Design", bi.Design},
+		{"Acceptance Criteria", bi.AcceptanceCriteria},
+		{"Notes", bi.Notes},
+	} {
+		if s := strings.TrimSpace(section.body); s != "" {
+			parts = append(parts, "## "+section.heading+"\n\n"+s)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// convertStatus maps beads statuses onto arc's. Statuses arc lacks become
+// open.
+func convertStatus(status string, warn func(string, ...any)) types.Status {
+	if status == "" {
+		return types.StatusOpen
+	}
+	if s := types.Status(status); s.IsValid() {
+		return s
+	}
+	warn("status %q imported as open", status)
+	return types.StatusOpen
+}
+
+// convertType maps beads issue types onto arc's. Types arc lacks become
+// tasks.
+func convertType(issueType string, warn func(string, ...any)) types.IssueType {
+	if issueType == "" {
+		return types.TypeTask
+	}
+	if t := types.IssueType(issueType); t.IsValid() {
+		return t
+	}
+	warn("issue type %q imported as task", issueType)
+	return types.TypeTask
+}
+
+// convertDepType maps beads dependency kinds onto arc's. Kinds arc lacks
+// become related, which never blocks.
+func convertDepType(depType string, warn func(string, ...any)) types.DependencyType {
+	if depType == "" {
+		return types.DepBlocks
+	}
+	if t := types.DependencyType(depType); t.IsValid() {
+		return t
+	}
+	warn("dependency type %q imported as related", depType)
+	return types.DepRelated
+}
+
+// newIssueID generates an arc ID that is neither stored nor already
+// assigned in this import.
+func newIssueID(ctx context.Context, store storage.Storage, prefix string, bi *Issue, assigned map[string]bool) string {
+	for {
+		id := project.GenerateIssueID(prefix, bi.ID+bi.Title)
+		if assigned[id] {
+			continue
+		}
+		if _, err := store.GetIssue(ctx, id); err != nil {
+			return id
+		}
+	}
+}
+
+// addMissingDependency adds dep unless the issue already depends on the
+// same target.
+func addMissingDependency(ctx context.Context, store storage.Storage, dep *types.Dependency, actor string) (bool, error) {
+	deps, err := store.GetDependencies(ctx, dep.IssueID)
+	if err != nil {
+		return false, err
+	}
+	for _, d := range deps {
+		if d.DependsOnID == dep.DependsOnID {
+			return false, nil
+		}
+	}
+	if err := store.AddDependency(ctx, dep, actor); err != nil {
+		return false, err
+	}
+	return true, nil
+}
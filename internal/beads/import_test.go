@@ -0,0 +1,138 @@
+package beads_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/beads"
+	"github.com/sentiolabs/arc/internal/storage/sqlite"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+const export = `{"id":"bd-1","title":"Epic","status":"open","priority":0,"issue_type":"epic","labels":["core"],"created_at":"2025-01-02T03:04:05Z","updated_at":"2025-01-02T03:04:05Z"}
+{"id":"bd-1.1","title":"Child","description":"Do it","design":"Like this","status":"closed","priority":1,"issue_type":"task","closed_at":"2025-02-01T00:00:00Z","close_reason":"done","dependencies":[{"issue_id":"bd-1.1","depends_on_id":"bd-1","type":"parent-child"}],"comments":[{"author":"alice","text":"started","created_at":"2025-01-03T00:00:00Z"}]}
+{"id":"bd-2","title":"Blocked","status":"pinned","priority":3,"issue_type":"molecule","dependencies":[{"issue_id":"bd-2","depends_on_id":"bd-1.1","type":"blocks"},{"issue_id":"bd-2","depends_on_id":"bd-9","type":"blocks"}]}
+{"id":"bd-3","title":"Gone","status":"tombstone"}
+`
+
+func newStore(t *testing.T) (*sqlite.Store, *types.Project) {
+	t.Helper()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	proj := &types.Project{Name: "Migrated", Prefix: "mig"}
+	if err := store.CreateProject(context.Background(), proj); err != nil {
+		t.Fatalf("CreateProject: %v", err)
+	}
+	return store, proj
+}
+
+func TestRead(t *testing.T) {
+	issues, err := beads.Read(strings.NewReader(export + "\n"))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(issues) != 4 {
+		t.Fatalf("got %d issues, want 4", len(issues))
+	}
+	if got := issues[1]; got.Design != "Like this" || len(got.Comments) != 1 || got.ClosedAt == nil {
+		t.Errorf("issue bd-1.1 = %+v", got)
+	}
+
+	if _, err := beads.Read(strings.NewReader(`{"title":"no id"}`)); err == nil {
+		t.Error("expected error for issue without id")
+	}
+}
+
+func TestImport(t *testing.T) {
+	ctx := context.Background()
+	store, proj := newStore(t)
+	issues, err := beads.Read(strings.NewReader(export))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+
+	result, err := beads.Import(ctx, store, proj.ID, issues, "migrator")
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if result.Created != 3 || result.Ignored != 1 || result.Dependencies != 2 || result.Comments != 1 {
+		t.Errorf("result = %+v", result)
+	}
+	if len(result.Warnings) != 3 {
+		t.Errorf("warnings = %v, want status, type, and missing dependency", result.Warnings)
+	}
+
+	epic, err := store.GetIssueByExternalRef(ctx, "bd-1")
+	if err != nil {
+		t.Fatalf("GetIssueByExternalRef: %v", err)
+	}
+	if epic.Priority != 0 || epic.IssueType != types.TypeEpic || !strings.HasPrefix(epic.ID, "mig.") {
+		t.Errorf("epic = %+v", epic)
+	}
+	if labels, _ := store.GetIssueLabels(ctx, epic.ID); len(labels) != 1 || labels[0] != "core" {
+		t.Errorf("epic labels = %v", labels)
+	}
+
+	child, err := store.GetIssue(ctx, result.IDMap["bd-1.1"])
+	if err != nil {
+		t.Fatalf("GetIssue child: %v", err)
+	}
+	if child.Status != types.StatusClosed || child.CloseReason != "done" ||
+		child.Description != "Do it\n\n## Design\n\nLike this" {
+		t.Errorf("child = %+v", child)
+	}
+	deps, _ := store.GetDependencies(ctx, child.ID)
+	if len(deps) != 1 || deps[0].DependsOnID != epic.ID || deps[0].Type != types.DepParentChild {
+		t.Errorf("child deps = %+v", deps)
+	}
+
+	blocked, _ := store.GetIssue(ctx, result.IDMap["bd-2"])
+	if blocked.Status != types.StatusOpen || blocked.IssueType != types.TypeTask {
+		t.Errorf("bd-2 = %+v", blocked)
+	}
+}
+
+func TestImport_Idempotent(t *testing.T) {
+	ctx := context.Background()
+	store, proj := newStore(t)
+	issues, _ := beads.Read(strings.NewReader(export))
+	first, err := beads.Import(ctx, store, proj.ID, issues, "migrator")
+	if err != nil {
+		t.Fatalf("first Import: %v", err)
+	}
+
+	// A later export adds an issue and a dependency between old issues.
+	later := strings.Replace(export, `"labels":["core"]`,
+		`"dependencies":[{"issue_id":"bd-1","depends_on_id":"bd-2","type":"related"}]`, 1) +
+		`{"id":"bd-4","title":"New","dependencies":[{"issue_id":"bd-4","depends_on_id":"bd-1","type":"related"}]}` + "\n"
+	issues, _ = beads.Read(strings.NewReader(later))
+	second, err := beads.Import(ctx, store, proj.ID, issues, "migrator")
+	if err != nil {
+		t.Fatalf("second Import: %v", err)
+	}
+	if second.Created != 1 || second.Skipped != 3 || second.Dependencies != 2 {
+		t.Errorf("second result = %+v", second)
+	}
+	if second.IDMap["bd-1"] != first.IDMap["bd-1"] {
+		t.Errorf("bd-1 remapped from %s to %s", first.IDMap["bd-1"], second.IDMap["bd-1"])
+	}
+
+	third, err := beads.Import(ctx, store, proj.ID, issues, "migrator")
+	if err != nil {
+		t.Fatalf("third Import: %v", err)
+	}
+	if third.Created != 0 || third.Dependencies != 0 {
+		t.Errorf("third result = %+v", third)
+	}
+
+	all, _ := store.ListIssues(ctx, types.IssueFilter{ProjectID: proj.ID})
+	if len(all) != 4 {
+		t.Errorf("project has %d issues, want 4", len(all))
+	}
+}
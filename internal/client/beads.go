@@ -0,0 +1,26 @@
+// Beads import client method for migrating .beads/issues.jsonl exports.
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// ImportBeads uploads a beads issues.jsonl export into a project. Issues
+// imported on an earlier run are skipped.
+func (c *Client) ImportBeads(projectID string, r io.Reader) (*types.BeadsImportResult, error) {
+	resp, err := c.postStream(fmt.Sprintf("/api/v1/projects/%s/import/beads", projectID), "application/x-ndjson", r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result types.BeadsImportResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &result, nil
+}
@@ -28,6 +28,9 @@ type Client struct {
 	httpClient *http.Client
 	// actor identifies the user making requests via the X-Actor header.
 	actor string
+	// token is the API token sent as a bearer credential, if any. When set,
+	// the server derives the actor from it and ignores X-Actor.
+	token string
 }
 
 // New creates a new API client configured to connect to the given base URL.
@@ -52,6 +55,11 @@ func (c *Client) SetActor(actor string) {
 	c.actor = actor
 }
 
+// SetToken sets the API token sent in the Authorization header on all requests.
+func (c *Client) SetToken(token string) {
+	c.token = token
+}
+
 // Health checks the server health by sending a GET /health request.
 func (c *Client) Health() error {
 	resp, err := c.get("/health")
@@ -239,12 +247,18 @@ func (c *Client) ListIssues(projID string, opts ListIssuesOptions) ([]*types.Iss
 	if opts.Query != "" {
 		query.Set("q", opts.Query)
 	}
+	if opts.Where != "" {
+		query.Set("q", opts.Where)
+	}
 	if opts.Limit > 0 {
 		query.Set("limit", strconv.Itoa(opts.Limit))
 	}
 	if opts.Parent != "" {
 		query.Set("parent_id", opts.Parent)
 	}
+	for key, value := range opts.Fields {
+		query.Set("field."+key, value)
+	}
 
 	if len(query) > 0 {
 		path += "?" + query.Encode()
@@ -272,20 +286,117 @@ type ListIssuesOptions struct {
 	Type     string // Filter by issue type (e.g., "bug", "feature")
 	Assignee string // Filter by assignee name
 	Query    string // Full-text search in title/description
+	Where    string // Structured query, e.g. "status:open label:api"; replaces Query
 	Limit    int    // Maximum number of results
 	Parent   string // Filter by parent issue ID
+	// Fields filters by exact custom field values
+	Fields map[string]string
 }
 
-// CreateIssue creates a new issue.
+// SearchOptions configures a full-text search.
+// All fields are optional; zero values are omitted from the query.
+type SearchOptions struct {
+	Project string // Search only this project ID; empty searches every visible project
+	Limit   int    // Maximum number of results
+	Offset  int    // Number of results to skip
+}
+
+// Search runs a full-text search over issue titles, descriptions, comments
+// and labels, returning ranked hits with highlighted snippets.
+func (c *Client) Search(q string, opts SearchOptions) ([]*types.SearchHit, error) {
+	query := url.Values{"q": {q}}
+	if opts.Project != "" {
+		query.Set("project", opts.Project)
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Offset > 0 {
+		query.Set("offset", strconv.Itoa(opts.Offset))
+	}
+
+	resp, err := c.get("/api/v1/search?" + query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Data []*types.SearchHit `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return result.Data, nil
+}
+
+// Duplicates groups a project's open issues that look like duplicates of
+// one another, most similar group first. A zero threshold uses the server's
+// default.
+func (c *Client) Duplicates(projID string, threshold float64) ([]*types.DuplicateCluster, error) {
+	path := fmt.Sprintf("/api/v1/projects/%s/duplicates", projID)
+	if threshold > 0 {
+		path += "?threshold=" + strconv.FormatFloat(threshold, 'f', -1, 64)
+	}
+
+	resp, err := c.get(path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var clusters []*types.DuplicateCluster
+	if err := json.NewDecoder(resp.Body).Decode(&clusters); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return clusters, nil
+}
+
+// CreateIssue creates a new issue. The returned issue lists any open issues
+// it likely duplicates in PossibleDuplicates. If req.NoDuplicates is set and
+// there are any, nothing is created and a *types.DuplicateIssueError is
+// returned instead.
 func (c *Client) CreateIssue(projID string, req CreateIssueRequest) (*types.Issue, error) {
 	path := fmt.Sprintf("/api/v1/projects/%s/issues", projID)
 
-	resp, err := c.post(path, req)
+	jsonBody, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal body: %w", err)
+	}
+
+	httpReq, err := http.NewRequest("POST", c.baseURL+path, bytes.NewReader(jsonBody))
 	if err != nil {
 		return nil, err
 	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	c.setAuthHeaders(httpReq)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusConflict {
+		respBody, _ := io.ReadAll(resp.Body)
+		var conflictResp struct {
+			Error              string                  `json:"error"`
+			Code               string                  `json:"code"`
+			PossibleDuplicates []*types.DuplicateMatch `json:"possible_duplicates"`
+		}
+		if json.Unmarshal(respBody, &conflictResp) == nil && conflictResp.Code == "possible_duplicates" {
+			return nil, &types.DuplicateIssueError{
+				Title:   req.Title,
+				Matches: conflictResp.PossibleDuplicates,
+			}
+		}
+		return nil, fmt.Errorf("%s", string(respBody))
+	}
+
+	if err := c.checkError(resp); err != nil {
+		return nil, err
+	}
+
 	var issue types.Issue
 	if err := json.NewDecoder(resp.Body).Decode(&issue); err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
@@ -301,6 +412,10 @@ type CreateIssueRequest struct {
 	IssueType   string `json:"issue_type,omitempty"`
 	Assignee    string `json:"assignee,omitempty"`
 	ParentID    string `json:"parent_id,omitempty"` // For hierarchical child IDs
+
+	Fields map[string]string `json:"fields,omitempty"` // Custom field values
+
+	NoDuplicates bool `json:"no_duplicates,omitempty"` // Refuse to create a likely duplicate
 }
 
 // Project-agnostic issue methods operate on issues by their globally-unique ID
@@ -341,16 +456,81 @@ func (c *Client) GetIssueDetailsByID(id string) (*types.IssueDetails, error) {
 	return &details, nil
 }
 
+// GetIssueHistory returns an issue as it was at a moment, along with the
+// events recorded up to then. A zero at means now.
+func (c *Client) GetIssueHistory(id string, at time.Time) (*types.IssueHistory, error) {
+	path := "/api/v1/issues/" + id + "/history"
+	if !at.IsZero() {
+		path += "?at=" + url.QueryEscape(at.Format(time.RFC3339Nano))
+	}
+
+	resp, err := c.get(path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var h types.IssueHistory
+	if err := json.NewDecoder(resp.Body).Decode(&h); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &h, nil
+}
+
 // UpdateIssueByID updates an issue by its globally-unique ID without requiring project context.
 func (c *Client) UpdateIssueByID(id string, updates map[string]any) (*types.Issue, error) {
+	return c.UpdateIssueByIDAtVersion(id, updates, 0)
+}
+
+// UpdateIssueByIDAtVersion updates an issue only if it is still at version,
+// as read from Issue.Version. If the issue has changed since, nothing is
+// written and a *types.VersionConflictError is returned so the caller can
+// re-read and retry. A zero version updates unconditionally.
+func (c *Client) UpdateIssueByIDAtVersion(id string, updates map[string]any, version int64) (*types.Issue, error) {
 	path := "/api/v1/issues/" + id
 
-	resp, err := c.put(path, updates)
+	jsonBody, err := json.Marshal(updates)
+	if err != nil {
+		return nil, fmt.Errorf("marshal body: %w", err)
+	}
+
+	req, err := http.NewRequest("PUT", c.baseURL+path, bytes.NewReader(jsonBody))
 	if err != nil {
 		return nil, err
 	}
+	req.Header.Set("Content-Type", "application/json")
+	if version > 0 {
+		req.Header.Set("If-Match", `"`+strconv.FormatInt(version, 10)+`"`)
+	}
+	c.setAuthHeaders(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusPreconditionFailed {
+		respBody, _ := io.ReadAll(resp.Body)
+		var conflictResp struct {
+			Error          string `json:"error"`
+			Code           string `json:"code"`
+			CurrentVersion int64  `json:"current_version"`
+		}
+		if json.Unmarshal(respBody, &conflictResp) == nil && conflictResp.Code == "version_conflict" {
+			return nil, &types.VersionConflictError{
+				IssueID:  id,
+				Expected: version,
+				Current:  conflictResp.CurrentVersion,
+			}
+		}
+		return nil, fmt.Errorf("%s", string(respBody))
+	}
+
+	if err := c.checkError(resp); err != nil {
+		return nil, err
+	}
+
 	var issue types.Issue
 	if err := json.NewDecoder(resp.Body).Decode(&issue); err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
@@ -373,7 +553,7 @@ func (c *Client) CloseIssueByID(id, reason string, cascade bool) (*types.Issue,
 		return nil, err
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Actor", c.actor)
+	c.setAuthHeaders(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -408,21 +588,47 @@ func (c *Client) CloseIssueByID(id, reason string, cascade bool) (*types.Issue,
 	return &issue, nil
 }
 
-// AddDependencyByID adds a dependency between two issues by globally-unique IDs.
-func (c *Client) AddDependencyByID(issueID, dependsOnID, depType string) error {
-	path := fmt.Sprintf("/api/v1/issues/%s/deps", issueID)
+// MergeIssueByID merges a duplicate issue into its canonical issue by
+// globally-unique IDs.
+func (c *Client) MergeIssueByID(duplicateID, canonicalID string) (*types.IssueMergeResult, error) {
+	path := fmt.Sprintf("/api/v1/issues/%s/merge", duplicateID)
 
-	body := map[string]string{
-		"depends_on_id": dependsOnID,
-		"type":          depType,
+	resp, err := c.post(path, map[string]string{"into": canonicalID})
+	if err != nil {
+		return nil, err
 	}
+	defer resp.Body.Close()
 
-	resp, err := c.post(path, body)
+	var result types.IssueMergeResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &result, nil
+}
+
+// MoveIssueByID moves an issue and its descendants to another project by
+// the issue's globally-unique ID.
+func (c *Client) MoveIssueByID(id, projectID string) (*types.IssueMoveResult, error) {
+	path := fmt.Sprintf("/api/v1/issues/%s/move", id)
+
+	resp, err := c.post(path, map[string]string{"project_id": projectID})
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
-	return nil
+
+	var result types.IssueMoveResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &result, nil
+}
+
+// AddDependencyByID adds a dependency between two issues by globally-unique IDs.
+func (c *Client) AddDependencyByID(issueID, dependsOnID, depType string) error {
+	path := fmt.Sprintf("/api/v1/issues/%s/deps", issueID)
+
+	return c.postDependency(path, dependsOnID, depType)
 }
 
 // RemoveDependencyByID removes a dependency between two issues by globally-unique IDs.
@@ -505,7 +711,7 @@ func (c *Client) CloseIssue(projID, id, reason string, cascade bool) (*types.Iss
 		return nil, err
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Actor", c.actor)
+	c.setAuthHeaders(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -555,19 +761,61 @@ func (c *Client) DeleteIssue(projID, id string) error {
 
 // Ready work methods return issues based on their dependency resolution status.
 
-// GetReadyWork returns issues ready to work on.
-// sortPolicy can be: "hybrid" (default), "priority", or "oldest".
-func (c *Client) GetReadyWork(projID string, limit int, sortPolicy string) ([]*types.Issue, error) {
-	path := fmt.Sprintf("/api/v1/projects/%s/ready", projID)
-
+// WorkOptions filters and pages ready and blocked work.
+// All fields are optional; zero values are omitted from the query.
+type WorkOptions struct {
+	Status   string   // Filter by status (e.g., "open", "in_progress")
+	Type     string   // Filter by issue type (e.g., "bug", "feature")
+	Priority *int     // Filter by priority
+	Labels   []string // Issues must carry every one of these labels
+	Parent   string   // Only children of this issue, e.g. an epic
+	Teammate string   // Only issues labeled teammate:<role>
+	Sort     string   // Sort policy for ready work: hybrid, priority, oldest
+	Limit    int      // Maximum number of results
+	Offset   int      // Number of results to skip
+	After    string   // Cursor: the ID of the last issue of the previous page
+}
+
+// query encodes the options as query parameters.
+func (o WorkOptions) query() url.Values {
 	query := url.Values{}
-	if limit > 0 {
-		query.Set("limit", strconv.Itoa(limit))
+	if o.Status != "" {
+		query.Set("status", o.Status)
 	}
-	if sortPolicy != "" {
-		query.Set("sort", sortPolicy)
+	if o.Type != "" {
+		query.Set("type", o.Type)
 	}
-	if len(query) > 0 {
+	if o.Priority != nil {
+		query.Set("priority", strconv.Itoa(*o.Priority))
+	}
+	for _, label := range o.Labels {
+		query.Add("label", label)
+	}
+	if o.Parent != "" {
+		query.Set("parent", o.Parent)
+	}
+	if o.Teammate != "" {
+		query.Set("teammate", o.Teammate)
+	}
+	if o.Sort != "" {
+		query.Set("sort", o.Sort)
+	}
+	if o.Limit > 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Offset > 0 {
+		query.Set("offset", strconv.Itoa(o.Offset))
+	}
+	if o.After != "" {
+		query.Set("after", o.After)
+	}
+	return query
+}
+
+// GetReadyWork returns issues ready to work on.
+func (c *Client) GetReadyWork(projID string, opts WorkOptions) ([]*types.Issue, error) {
+	path := fmt.Sprintf("/api/v1/projects/%s/ready", projID)
+	if query := opts.query(); len(query) > 0 {
 		path += "?" + query.Encode()
 	}
 
@@ -585,10 +833,10 @@ func (c *Client) GetReadyWork(projID string, limit int, sortPolicy string) ([]*t
 }
 
 // GetBlockedIssues returns blocked issues.
-func (c *Client) GetBlockedIssues(projID string, limit int) ([]*types.BlockedIssue, error) {
+func (c *Client) GetBlockedIssues(projID string, opts WorkOptions) ([]*types.BlockedIssue, error) {
 	path := fmt.Sprintf("/api/v1/projects/%s/blocked", projID)
-	if limit > 0 {
-		path += fmt.Sprintf("?limit=%d", limit)
+	if query := opts.query(); len(query) > 0 {
+		path += "?" + query.Encode()
 	}
 
 	resp, err := c.get(path)
@@ -604,6 +852,56 @@ func (c *Client) GetBlockedIssues(projID string, limit int) ([]*types.BlockedIss
 	return issues, nil
 }
 
+// TeamContext is the server-side team context: non-closed issues grouped by
+// their teammate:* label, optionally scoped to a single epic.
+type TeamContext struct {
+	Project string                     `json:"project"`
+	Epic    *TeamContextEpic           `json:"epic,omitempty"`
+	Roles   map[string]TeamContextRole `json:"roles"`
+}
+
+// TeamContextEpic identifies the epic a team context was scoped to.
+type TeamContextEpic struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// TeamContextRole lists the issues labeled for one teammate role.
+type TeamContextRole struct {
+	Issues []TeamContextIssue `json:"issues"`
+}
+
+// TeamContextIssue is the compact issue shape returned in a team context.
+type TeamContextIssue struct {
+	ID       string   `json:"id"`
+	Title    string   `json:"title"`
+	Priority int      `json:"priority"`
+	Status   string   `json:"status"`
+	Type     string   `json:"type"`
+	Deps     []string `json:"deps,omitempty"`
+}
+
+// GetTeamContext returns issues grouped by teammate role.
+// When epicID is non-empty, only children of that epic are included.
+func (c *Client) GetTeamContext(projID, epicID string) (*TeamContext, error) {
+	path := fmt.Sprintf("/api/v1/projects/%s/team-context", projID)
+	if epicID != "" {
+		path += "?epic_id=" + url.QueryEscape(epicID)
+	}
+
+	resp, err := c.get(path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var tc TeamContext
+	if err := json.NewDecoder(resp.Body).Decode(&tc); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &tc, nil
+}
+
 // Dependency methods manage relationships between issues (blocks, parent-child, related).
 
 // AddDependency adds a dependency between two issues.
@@ -611,29 +909,172 @@ func (c *Client) GetBlockedIssues(projID string, limit int) ([]*types.BlockedIss
 func (c *Client) AddDependency(projID, issueID, dependsOnID, depType string) error {
 	path := fmt.Sprintf("/api/v1/projects/%s/issues/%s/deps", projID, issueID)
 
+	return c.postDependency(path, dependsOnID, depType)
+}
+
+// RemoveDependency removes a dependency between two issues.
+func (c *Client) RemoveDependency(projID, issueID, dependsOnID string) error {
+	path := fmt.Sprintf("/api/v1/projects/%s/issues/%s/deps/%s", projID, issueID, dependsOnID)
+
+	resp, err := c.delete(path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
+// UndoRequest selects the changes to undo in a project.
+type UndoRequest struct {
+	Actor    string  `json:"actor,omitempty"`     // only undo this actor's changes
+	Last     int     `json:"last,omitempty"`      // number of changes to undo (default 1)
+	EventIDs []int64 `json:"event_ids,omitempty"` // undo exactly these events instead
+	DryRun   bool    `json:"dry_run,omitempty"`
+}
+
+// Undo reverses a project's most recent reversible changes, or with DryRun
+// only plans the reversal. It returns one step per change, newest first.
+func (c *Client) Undo(projID string, req UndoRequest) ([]*types.UndoStep, error) {
+	path := fmt.Sprintf("/api/v1/projects/%s/undo", projID)
+
+	resp, err := c.post(path, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var steps []*types.UndoStep
+	if err := json.NewDecoder(resp.Body).Decode(&steps); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return steps, nil
+}
+
+// postDependency adds a dependency through path. A dependency that would
+// close a cycle yields a *types.DependencyCycleError.
+func (c *Client) postDependency(path, dependsOnID, depType string) error {
 	body := map[string]string{
 		"depends_on_id": dependsOnID,
 		"type":          depType,
 	}
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal body: %w", err)
+	}
 
-	resp, err := c.post(path, body)
+	req, err := http.NewRequest("POST", c.baseURL+path, bytes.NewReader(jsonBody))
 	if err != nil {
 		return err
 	}
+	req.Header.Set("Content-Type", "application/json")
+	c.setAuthHeaders(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
 	defer resp.Body.Close()
-	return nil
+
+	if resp.StatusCode == http.StatusConflict {
+		respBody, _ := io.ReadAll(resp.Body)
+		var conflictResp struct {
+			Error string   `json:"error"`
+			Code  string   `json:"code"`
+			Cycle []string `json:"cycle"`
+		}
+		if json.Unmarshal(respBody, &conflictResp) == nil && conflictResp.Code == "dependency_cycle" {
+			return &types.DependencyCycleError{Path: conflictResp.Cycle}
+		}
+		return fmt.Errorf("%s", string(respBody))
+	}
+
+	return c.checkError(resp)
 }
 
-// RemoveDependency removes a dependency between two issues.
-func (c *Client) RemoveDependency(projID, issueID, dependsOnID string) error {
-	path := fmt.Sprintf("/api/v1/projects/%s/issues/%s/deps/%s", projID, issueID, dependsOnID)
+// ValidateDependencies audits a project's dependency graph.
+func (c *Client) ValidateDependencies(projID string) (*types.DependencyReport, error) {
+	path := fmt.Sprintf("/api/v1/projects/%s/deps/validate", projID)
 
-	resp, err := c.delete(path)
+	resp, err := c.get(path)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
-	return nil
+
+	var report types.DependencyReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &report, nil
+}
+
+// GraphOptions selects the issues in a dependency graph.
+type GraphOptions struct {
+	Root       string // Grow the graph from this issue instead of taking the whole project
+	HideClosed bool   // Leave out closed issues
+	Depth      int    // With a root, the most dependency hops to follow (0 = no limit)
+}
+
+// GetGraph returns a project's dependency graph.
+func (c *Client) GetGraph(projID string, opts GraphOptions) (*types.Graph, error) {
+	path := fmt.Sprintf("/api/v1/projects/%s/graph", projID)
+
+	query := url.Values{}
+	if opts.Root != "" {
+		query.Set("root", opts.Root)
+	}
+	if opts.HideClosed {
+		query.Set("hide_closed", "true")
+	}
+	if opts.Depth > 0 {
+		query.Set("depth", strconv.Itoa(opts.Depth))
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	resp, err := c.get(path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var g types.Graph
+	if err := json.NewDecoder(resp.Body).Decode(&g); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &g, nil
+}
+
+// GetCriticalPath returns the longest chain of open issues gating an
+// issue's completion.
+func (c *Client) GetCriticalPath(id string) (*types.CriticalPath, error) {
+	resp, err := c.get("/api/v1/issues/" + id + "/critical-path")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var cp types.CriticalPath
+	if err := json.NewDecoder(resp.Body).Decode(&cp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &cp, nil
+}
+
+// GetUnblocks returns the open work waiting on an issue.
+func (c *Client) GetUnblocks(id string) (*types.Unblocks, error) {
+	resp, err := c.get("/api/v1/issues/" + id + "/unblocks")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var u types.Unblocks
+	if err := json.NewDecoder(resp.Body).Decode(&u); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &u, nil
 }
 
 // --- Plan methods ---
@@ -709,6 +1150,48 @@ func (c *Client) DeletePlan(planID string) error {
 	return nil
 }
 
+// LinkIssuePlan links a plan to an issue, making the plan's content
+// searchable with the issue.
+func (c *Client) LinkIssuePlan(issueID, planID string) error {
+	path := "/api/v1/issues/" + issueID + "/plans"
+
+	resp, err := c.post(path, map[string]string{"plan_id": planID})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
+// UnlinkIssuePlan removes the link between an issue and a plan.
+func (c *Client) UnlinkIssuePlan(issueID, planID string) error {
+	path := "/api/v1/issues/" + issueID + "/plans/" + planID
+
+	resp, err := c.delete(path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
+// ListIssuePlans returns the plans linked to an issue.
+func (c *Client) ListIssuePlans(issueID string) ([]*types.Plan, error) {
+	path := "/api/v1/issues/" + issueID + "/plans"
+
+	resp, err := c.get(path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var plans []*types.Plan
+	if err := json.NewDecoder(resp.Body).Decode(&plans); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return plans, nil
+}
+
 // ListPlanComments returns all comments for a plan.
 func (c *Client) ListPlanComments(planID string) ([]*types.PlanComment, error) {
 	path := "/api/v1/plans/" + planID + "/comments"
@@ -1034,7 +1517,15 @@ func (c *Client) GetAgentTranscript(projectID, sessionID, agentID string) ([]map
 }
 
 // HTTP helpers - low-level methods for making requests to the arc server.
-// All methods set the X-Actor header and check for error responses.
+// All methods set the X-Actor and Authorization headers and check for error responses.
+
+// setAuthHeaders identifies the caller on req.
+func (c *Client) setAuthHeaders(req *http.Request) {
+	req.Header.Set("X-Actor", c.actor)
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+}
 
 // get performs an HTTP GET request to the given path.
 func (c *Client) get(path string) (*http.Response, error) {
@@ -1042,7 +1533,7 @@ func (c *Client) get(path string) (*http.Response, error) {
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("X-Actor", c.actor)
+	c.setAuthHeaders(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -1078,7 +1569,7 @@ func (c *Client) delete(path string) (*http.Response, error) {
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("X-Actor", c.actor)
+	c.setAuthHeaders(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -1105,7 +1596,29 @@ func (c *Client) doJSON(method, path string, body any) (*http.Response, error) {
 		return nil, err
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Actor", c.actor)
+	c.setAuthHeaders(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	if err := c.checkError(resp); err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// postStream performs an HTTP POST request streaming body with the given content type.
+func (c *Client) postStream(path, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest("POST", c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	c.setAuthHeaders(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
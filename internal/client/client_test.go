@@ -164,6 +164,31 @@ func TestClientCloseIssueWithCascade(t *testing.T) {
 	}
 }
 
+func TestClientUpdateIssueReturnsVersionConflict(t *testing.T) {
+	c, cleanup := testClientServer(t)
+	defer cleanup()
+
+	proj := createTestProjectClient(t, c)
+	issue := createTestIssueClient(t, c, proj.ID, "Contended")
+
+	updated, err := c.UpdateIssueByIDAtVersion(issue.ID, map[string]any{"title": "First"}, issue.Version)
+	if err != nil {
+		t.Fatalf("UpdateIssueByIDAtVersion failed: %v", err)
+	}
+	if updated.Version != issue.Version+1 {
+		t.Errorf("version after update = %d, want %d", updated.Version, issue.Version+1)
+	}
+
+	_, err = c.UpdateIssueByIDAtVersion(issue.ID, map[string]any{"title": "Stale"}, issue.Version)
+	var conflictErr *types.VersionConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("expected *types.VersionConflictError, got %T: %v", err, err)
+	}
+	if conflictErr.Current != updated.Version {
+		t.Errorf("VersionConflictError.Current = %d, want %d", conflictErr.Current, updated.Version)
+	}
+}
+
 func TestClientHealth(t *testing.T) {
 	client, cleanup := testClientServer(t)
 	defer cleanup()
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// EventStreamOptions filters an event stream. Empty fields match everything.
+type EventStreamOptions struct {
+	ProjectID string
+	IssueID   string
+	// Types lists accepted event types; entries ending in ".*" match by prefix.
+	Types []string
+}
+
+// EventStream is an open Server-Sent Events connection to
+// /api/v1/events/stream. It is not safe for concurrent use.
+type EventStream struct {
+	body   io.ReadCloser
+	reader *bufio.Reader
+}
+
+// OpenEventStream connects to the server's event stream. It returns once the
+// server has accepted the subscription, so any change made after it returns
+// is guaranteed to be delivered (unless the stream later drops).
+// The stream ends when ctx is cancelled or Close is called.
+func (c *Client) OpenEventStream(ctx context.Context, opts EventStreamOptions) (*EventStream, error) {
+	q := url.Values{}
+	if opts.ProjectID != "" {
+		q.Set("project_id", opts.ProjectID)
+	}
+	if opts.IssueID != "" {
+		q.Set("issue_id", opts.IssueID)
+	}
+	if len(opts.Types) > 0 {
+		q.Set("type", strings.Join(opts.Types, ","))
+	}
+	path := "/api/v1/events/stream"
+	if len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	c.setAuthHeaders(req)
+
+	// Streams are long-lived, so use the configured transport without the
+	// per-request timeout; ctx governs the connection lifetime instead.
+	streamClient := &http.Client{Transport: c.httpClient.Transport}
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	if err := c.checkError(resp); err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+
+	return &EventStream{body: resp.Body, reader: bufio.NewReader(resp.Body)}, nil
+}
+
+// Next blocks until the next event arrives. It returns io.EOF when the
+// server ends the stream and the context error when the stream is cancelled.
+func (s *EventStream) Next() (*types.StreamEvent, error) {
+	var data strings.Builder
+	for {
+		line, err := s.reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case line == "":
+			// Blank line dispatches the accumulated event (comment-only
+			// blocks such as heartbeats carry no data and are skipped).
+			if data.Len() == 0 {
+				continue
+			}
+			var ev types.StreamEvent
+			if err := json.Unmarshal([]byte(data.String()), &ev); err != nil {
+				return nil, fmt.Errorf("decode event: %w", err)
+			}
+			return &ev, nil
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+		// Comments (":"), id, event and retry fields need no handling: the
+		// JSON payload carries the sequence number and type.
+	}
+}
+
+// Close terminates the stream.
+func (s *EventStream) Close() error {
+	return s.body.Close()
+}
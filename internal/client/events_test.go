@@ -0,0 +1,55 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/client"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func TestEventStreamReceivesIssueEvents(t *testing.T) {
+	c, cleanup := testClientServer(t)
+	defer cleanup()
+
+	proj, err := c.CreateProject("Stream Project", "strm", "")
+	if err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := c.OpenEventStream(ctx, client.EventStreamOptions{ProjectID: proj.ID})
+	if err != nil {
+		t.Fatalf("open stream: %v", err)
+	}
+	defer stream.Close()
+
+	issue, err := c.CreateIssue(proj.ID, client.CreateIssueRequest{Title: "Watch me"})
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	if _, err := c.CloseIssue(proj.ID, issue.ID, "done", false); err != nil {
+		t.Fatalf("close issue: %v", err)
+	}
+
+	for _, want := range []types.StreamEventType{types.StreamIssueCreated, types.StreamIssueClosed} {
+		ev, err := stream.Next()
+		if err != nil {
+			t.Fatalf("next: %v", err)
+		}
+		if ev.Type != want || ev.IssueID != issue.ID {
+			t.Errorf("got %s for %s, want %s for %s", ev.Type, ev.IssueID, want, issue.ID)
+		}
+		if ev.Actor != "test-user" {
+			t.Errorf("actor = %q, want test-user", ev.Actor)
+		}
+	}
+
+	cancel()
+	if _, err := stream.Next(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Next after cancel = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,96 @@
+// Issue template client methods for storing templates on the server and
+// creating issues from them.
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// InstantiateTemplateRequest holds the options for creating issues from a
+// template.
+type InstantiateTemplateRequest struct {
+	// Content is the template's YAML. When empty, the template stored on
+	// the server under the given name is used.
+	Content  string            `json:"content,omitempty"`
+	Vars     map[string]string `json:"vars,omitempty"`
+	ParentID string            `json:"parent_id,omitempty"`
+}
+
+// ListIssueTemplates returns a project's stored templates.
+func (c *Client) ListIssueTemplates(projectID string) ([]*types.IssueTemplate, error) {
+	resp, err := c.get(fmt.Sprintf("/api/v1/projects/%s/templates", projectID))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var templates []*types.IssueTemplate
+	if err := json.NewDecoder(resp.Body).Decode(&templates); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return templates, nil
+}
+
+// GetIssueTemplate returns a stored template.
+func (c *Client) GetIssueTemplate(projectID, name string) (*types.IssueTemplate, error) {
+	resp, err := c.get(fmt.Sprintf("/api/v1/projects/%s/templates/%s", projectID, url.PathEscape(name)))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var t types.IssueTemplate
+	if err := json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &t, nil
+}
+
+// SaveIssueTemplate stores a template on the server, replacing any with the
+// same name.
+func (c *Client) SaveIssueTemplate(projectID, name, content string) (*types.IssueTemplate, error) {
+	resp, err := c.put(fmt.Sprintf("/api/v1/projects/%s/templates/%s", projectID, url.PathEscape(name)),
+		map[string]string{"content": content})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var t types.IssueTemplate
+	if err := json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &t, nil
+}
+
+// DeleteIssueTemplate removes a stored template.
+func (c *Client) DeleteIssueTemplate(projectID, name string) error {
+	resp, err := c.delete(fmt.Sprintf("/api/v1/projects/%s/templates/%s", projectID, url.PathEscape(name)))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
+// InstantiateTemplate creates the issues described by a template.
+func (c *Client) InstantiateTemplate(
+	projectID, name string, req InstantiateTemplateRequest,
+) (*types.TemplateInstance, error) {
+	resp, err := c.post(
+		fmt.Sprintf("/api/v1/projects/%s/templates/%s/instantiate", projectID, url.PathEscape(name)), req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var inst types.TemplateInstance
+	if err := json.NewDecoder(resp.Body).Decode(&inst); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &inst, nil
+}
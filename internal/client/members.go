@@ -0,0 +1,53 @@
+// Project member client methods for managing who can access a project and
+// with which role.
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// ListMembers returns a project's members.
+func (c *Client) ListMembers(projectID string) ([]*types.ProjectMember, error) {
+	resp, err := c.get(fmt.Sprintf("/api/v1/projects/%s/members", projectID))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var members []*types.ProjectMember
+	if err := json.NewDecoder(resp.Body).Decode(&members); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return members, nil
+}
+
+// AddMember grants actor a role on a project, replacing any existing role.
+func (c *Client) AddMember(projectID, actor string, role types.ProjectRole) (*types.ProjectMember, error) {
+	body := map[string]string{"actor": actor, "role": string(role)}
+
+	resp, err := c.post(fmt.Sprintf("/api/v1/projects/%s/members", projectID), body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var m types.ProjectMember
+	if err := json.NewDecoder(resp.Body).Decode(&m); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &m, nil
+}
+
+// RemoveMember revokes actor's access to a project.
+func (c *Client) RemoveMember(projectID, actor string) error {
+	resp, err := c.delete(fmt.Sprintf("/api/v1/projects/%s/members/%s", projectID, url.PathEscape(actor)))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return nil
+}
@@ -0,0 +1,80 @@
+package client_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/api"
+	"github.com/sentiolabs/arc/internal/auth"
+	"github.com/sentiolabs/arc/internal/client"
+	"github.com/sentiolabs/arc/internal/storage/sqlite"
+	"github.com/sentiolabs/arc/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAuthClientServer creates a test server requiring authentication, as
+// project members do, and returns a client for each actor using its own token.
+func testAuthClientServer(t *testing.T, actors ...string) (map[string]*client.Client, func()) {
+	t.Helper()
+
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+
+	server := api.New(api.ServerOptions{
+		Address:     ":0",
+		Store:       store,
+		RequireAuth: true,
+	})
+	ts := httptest.NewServer(server.Echo())
+
+	clients := make(map[string]*client.Client, len(actors))
+	for _, actor := range actors {
+		raw, err := auth.GenerateToken()
+		require.NoError(t, err)
+		tok := &types.APIToken{Name: "test", Actor: actor, Prefix: auth.Prefix(raw)}
+		require.NoError(t, store.CreateAPIToken(context.Background(), tok, auth.HashToken(raw)))
+
+		c := client.New(ts.URL)
+		c.SetToken(raw)
+		clients[actor] = c
+	}
+
+	cleanup := func() {
+		ts.Close()
+		_ = store.Close()
+	}
+
+	return clients, cleanup
+}
+
+func TestMemberClientRoundTrip(t *testing.T) {
+	clients, cleanup := testAuthClientServer(t, "test-user", "outsider")
+	defer cleanup()
+	c := clients["test-user"]
+	proj := createTestProjectClient(t, c)
+
+	// The token's actor created the project, so it is already the admin.
+	members, err := c.ListMembers(proj.ID)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	assert.Equal(t, types.RoleAdmin, members[0].Role)
+
+	_, err = c.AddMember(proj.ID, "someone@example.com", types.RoleViewer)
+	require.NoError(t, err)
+
+	members, err = c.ListMembers(proj.ID)
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+
+	require.NoError(t, c.RemoveMember(proj.ID, "someone@example.com"))
+	members, err = c.ListMembers(proj.ID)
+	require.NoError(t, err)
+	assert.Len(t, members, 1)
+
+	// Callers without a role are refused.
+	_, err = clients["outsider"].ListMembers(proj.ID)
+	assert.ErrorContains(t, err, "requires viewer role")
+}
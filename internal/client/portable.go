@@ -0,0 +1,48 @@
+// Project export and import client methods for moving a project between
+// servers as a JSONL archive.
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// ExportProject writes a project's JSONL archive to w.
+func (c *Client) ExportProject(projectID string, w io.Writer) error {
+	resp, err := c.get(fmt.Sprintf("/api/v1/projects/%s/export", projectID))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("read archive: %w", err)
+	}
+	return nil
+}
+
+// ImportProject uploads a JSONL archive. When into is set the archive is
+// added to that existing project with its issue IDs re-prefixed; otherwise
+// the archived project is recreated with its original IDs.
+func (c *Client) ImportProject(r io.Reader, into string) (*types.ImportResult, error) {
+	path := "/api/v1/projects/import"
+	if into != "" {
+		path += "?into=" + url.QueryEscape(into)
+	}
+
+	resp, err := c.postStream(path, "application/x-ndjson", r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result types.ImportResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &result, nil
+}
@@ -0,0 +1,39 @@
+package client_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImportProjectClient(t *testing.T) {
+	c, cleanup := testClientServer(t)
+	defer cleanup()
+	proj := createTestProjectClient(t, c)
+
+	issue, err := c.CreateIssue(proj.ID, client.CreateIssueRequest{Title: "Carry me", IssueType: "task"})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, c.ExportProject(proj.ID, &buf))
+	archive := buf.String()
+	assert.Contains(t, archive, issue.ID)
+
+	_, err = c.ImportProject(strings.NewReader(archive), "")
+	assert.ErrorContains(t, err, "already exists")
+
+	target, err := c.CreateProject("Target", "tgt", "")
+	require.NoError(t, err)
+	result, err := c.ImportProject(strings.NewReader(archive), target.ID)
+	require.NoError(t, err)
+	assert.Equal(t, target.ID, result.Project.ID)
+	assert.Equal(t, 1, result.Issues)
+
+	moved, err := c.GetIssueByID(result.IDMap[issue.ID])
+	require.NoError(t, err)
+	assert.Equal(t, "Carry me", moved.Title)
+}
@@ -0,0 +1,32 @@
+// Issue tracker sync client methods.
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// GitHubSyncRequest holds the options for a GitHub sync run.
+type GitHubSyncRequest struct {
+	Repo   string `json:"repo,omitempty"`    // owner/name; defaults to the last synced repo
+	Token  string `json:"token,omitempty"`   // GitHub token; the server's GITHUB_TOKEN when empty
+	APIURL string `json:"api_url,omitempty"` // GitHub-compatible REST API base URL
+	DryRun bool   `json:"dry_run,omitempty"`
+}
+
+// SyncGitHub runs a two-way sync between a project and a GitHub repository.
+func (c *Client) SyncGitHub(projectID string, req GitHubSyncRequest) (*types.SyncReport, error) {
+	resp, err := c.post(fmt.Sprintf("/api/v1/projects/%s/sync/github", projectID), req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var report types.SyncReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &report, nil
+}
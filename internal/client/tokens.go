@@ -0,0 +1,55 @@
+// API token client methods for issuing, listing, and revoking the bearer
+// tokens that authenticate requests to the arc server.
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// ListTokens returns API tokens visible to the caller. Plaintext tokens are
+// never included.
+func (c *Client) ListTokens() ([]*types.APIToken, error) {
+	resp, err := c.get("/api/v1/tokens")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var tokens []*types.APIToken
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return tokens, nil
+}
+
+// CreateToken issues a token named name for actor. An empty actor means the
+// caller's own identity. The returned token's Token field holds the
+// plaintext, which the server will not reveal again.
+func (c *Client) CreateToken(name, actor string) (*types.APIToken, error) {
+	body := map[string]string{"name": name, "actor": actor}
+
+	resp, err := c.post("/api/v1/tokens", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var tok types.APIToken
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &tok, nil
+}
+
+// RevokeToken revokes an API token by ID.
+func (c *Client) RevokeToken(id string) error {
+	resp, err := c.delete("/api/v1/tokens/" + id)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return nil
+}
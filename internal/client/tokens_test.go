@@ -0,0 +1,35 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenClientRoundTrip(t *testing.T) {
+	c, cleanup := testClientServer(t)
+	defer cleanup()
+
+	// Without a token the actor comes from X-Actor.
+	tok, err := c.CreateToken("laptop", "")
+	require.NoError(t, err)
+	assert.Equal(t, "test-user", tok.Actor)
+	assert.NotEmpty(t, tok.Token)
+
+	authed := client.New(c.BaseURL())
+	authed.SetActor("spoofed")
+	authed.SetToken(tok.Token)
+
+	tokens, err := authed.ListTokens()
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	assert.Equal(t, tok.ID, tokens[0].ID)
+	assert.Empty(t, tokens[0].Token)
+	assert.NotNil(t, tokens[0].LastUsedAt)
+
+	require.NoError(t, authed.RevokeToken(tok.ID))
+	_, err = authed.ListTokens()
+	assert.Error(t, err)
+}
@@ -0,0 +1,103 @@
+// Webhook client methods for managing a project's outbound webhooks and
+// inspecting their delivery log.
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// CreateWebhookOptions holds the fields for registering a webhook.
+type CreateWebhookOptions struct {
+	URL string
+	// Secret keys the payload signature; the server generates one if empty.
+	Secret string
+	// EventTypes limits deliveries to these event types ("issue.*" matches by
+	// prefix). Empty means every event.
+	EventTypes []string
+}
+
+// ListWebhooks returns a project's webhooks. Secrets are not included.
+func (c *Client) ListWebhooks(projectID string) ([]*types.Webhook, error) {
+	resp, err := c.get(fmt.Sprintf("/api/v1/projects/%s/webhooks", projectID))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var hooks []*types.Webhook
+	if err := json.NewDecoder(resp.Body).Decode(&hooks); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return hooks, nil
+}
+
+// CreateWebhook registers a webhook. The returned webhook includes its
+// secret, which the server will not reveal again.
+func (c *Client) CreateWebhook(projectID string, opts CreateWebhookOptions) (*types.Webhook, error) {
+	body := map[string]any{
+		"url":         opts.URL,
+		"secret":      opts.Secret,
+		"event_types": opts.EventTypes,
+	}
+
+	resp, err := c.post(fmt.Sprintf("/api/v1/projects/%s/webhooks", projectID), body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var wh types.Webhook
+	if err := json.NewDecoder(resp.Body).Decode(&wh); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &wh, nil
+}
+
+// DeleteWebhook removes a webhook and its delivery log.
+func (c *Client) DeleteWebhook(projectID, webhookID string) error {
+	resp, err := c.delete(fmt.Sprintf("/api/v1/projects/%s/webhooks/%s", projectID, webhookID))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
+// TestWebhook sends a webhook.ping delivery and returns its outcome.
+func (c *Client) TestWebhook(projectID, webhookID string) (*types.WebhookDelivery, error) {
+	resp, err := c.post(fmt.Sprintf("/api/v1/projects/%s/webhooks/%s/test", projectID, webhookID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var delivery types.WebhookDelivery
+	if err := json.NewDecoder(resp.Body).Decode(&delivery); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &delivery, nil
+}
+
+// ListWebhookDeliveries returns a webhook's delivery log, newest first.
+// A limit of 0 uses the server default.
+func (c *Client) ListWebhookDeliveries(projectID, webhookID string, limit int) ([]*types.WebhookDelivery, error) {
+	path := fmt.Sprintf("/api/v1/projects/%s/webhooks/%s/deliveries", projectID, webhookID)
+	if limit > 0 {
+		path += fmt.Sprintf("?limit=%d", limit)
+	}
+
+	resp, err := c.get(path)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var deliveries []*types.WebhookDelivery
+	if err := json.NewDecoder(resp.Body).Decode(&deliveries); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,49 @@
+package client_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/client"
+	"github.com/sentiolabs/arc/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookClientRoundTrip(t *testing.T) {
+	c, cleanup := testClientServer(t)
+	defer cleanup()
+	proj := createTestProjectClient(t, c)
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	wh, err := c.CreateWebhook(proj.ID, client.CreateWebhookOptions{
+		URL:        receiver.URL,
+		EventTypes: []string{"issue.*"},
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, wh.Secret)
+	assert.Equal(t, []string{"issue.*"}, wh.EventTypes)
+
+	hooks, err := c.ListWebhooks(proj.ID)
+	require.NoError(t, err)
+	require.Len(t, hooks, 1)
+	assert.Empty(t, hooks[0].Secret)
+
+	delivery, err := c.TestWebhook(proj.ID, wh.ID)
+	require.NoError(t, err)
+	assert.Equal(t, types.DeliverySucceeded, delivery.Status)
+
+	deliveries, err := c.ListWebhookDeliveries(proj.ID, wh.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, delivery.ID, deliveries[0].ID)
+
+	require.NoError(t, c.DeleteWebhook(proj.ID, wh.ID))
+	_, err = c.TestWebhook(proj.ID, wh.ID)
+	assert.Error(t, err)
+}
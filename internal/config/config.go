@@ -13,12 +13,39 @@ type Config struct {
 // CLIConfig holds settings the arc CLI uses to reach the server.
 type CLIConfig struct {
 	Server string `toml:"server" json:"server"`
+	// Token is the API token sent as a bearer credential. ARC_TOKEN
+	// overrides it. It is never served by the config API.
+	Token string `toml:"token,omitempty" json:"-"`
 }
 
 // ServerConfig holds settings the arc server uses for its own runtime.
 type ServerConfig struct {
 	Port   int    `toml:"port"    json:"port"`
 	DBPath string `toml:"db_path" json:"db_path"`
+	// RequireAuth rejects API requests that lack a valid API token. When
+	// false, tokens are still honored but the X-Actor header is accepted
+	// from unauthenticated callers.
+	RequireAuth bool `toml:"require_auth" json:"require_auth"`
+	// DBDriver selects the storage backend: "sqlite" (the default, using
+	// DBPath) or "postgres" (using DSN).
+	DBDriver string `toml:"db_driver,omitempty" json:"-"`
+	// DSN is the PostgreSQL connection string. It may carry a password, so
+	// it is never served by the config API.
+	DSN string `toml:"dsn,omitempty" json:"-"`
+}
+
+// Storage backends accepted by server.db_driver.
+const (
+	DBDriverSQLite   = "sqlite"
+	DBDriverPostgres = "postgres"
+)
+
+// Driver returns DBDriver, defaulting to SQLite when unset.
+func (s ServerConfig) Driver() string {
+	if s.DBDriver == "" {
+		return DBDriverSQLite
+	}
+	return s.DBDriver
 }
 
 // ResolvedDBPath returns DBPath with a leading ~ expanded to the user's home
@@ -57,5 +84,5 @@ func Default() *Config {
 // running arc-server until the server restarts. Used by the API + web UI to
 // surface a "requires restart" warning.
 func RequiresRestart() []string {
-	return []string{"server.port", "server.db_path"}
+	return []string{"server.port", "server.db_path", "server.require_auth", "server.db_driver", "server.dsn"}
 }
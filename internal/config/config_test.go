@@ -31,7 +31,10 @@ func TestDefaultIsUsable(t *testing.T) {
 
 func TestRequiresRestartContainsServerKeys(t *testing.T) {
 	got := config.RequiresRestart()
-	want := map[string]bool{"server.port": true, "server.db_path": true}
+	want := map[string]bool{
+		"server.port": true, "server.db_path": true, "server.require_auth": true,
+		"server.db_driver": true, "server.dsn": true,
+	}
 	if len(got) != len(want) {
 		t.Fatalf("RequiresRestart() = %v, want keys %v", got, want)
 	}
@@ -46,6 +46,15 @@ func Validate(cfg *Config) error {
 	if cfg.Server.Port < 1 || cfg.Server.Port > 65535 {
 		errs["server.port"] = "must be between 1 and 65535"
 	}
+	switch cfg.Server.Driver() {
+	case DBDriverSQLite:
+	case DBDriverPostgres:
+		if cfg.Server.DSN == "" {
+			errs["server.dsn"] = "must be set when server.db_driver is " + DBDriverPostgres
+		}
+	default:
+		errs["server.db_driver"] = fmt.Sprintf("must be %q or %q", DBDriverSQLite, DBDriverPostgres)
+	}
 	// Check that updates.channel is one of the allowed values.
 	channelOK := false
 	for _, c := range ValidChannels {
@@ -106,3 +106,23 @@ func TestValidatePlansDirFirstUnknownVarReported(t *testing.T) {
 		t.Errorf("error should NOT mention second var 'bar' (first-only reporting), got: %s", msg)
 	}
 }
+
+func TestValidateDBDriver(t *testing.T) {
+	cfg := config.Default()
+	cfg.Server.DBDriver = "mysql"
+	var ve config.ValidationError
+	if !errors.As(config.Validate(cfg), &ve) || ve["server.db_driver"] == "" {
+		t.Errorf("unknown driver: errors = %v, want server.db_driver", ve)
+	}
+
+	cfg.Server.DBDriver = config.DBDriverPostgres
+	ve = nil
+	if !errors.As(config.Validate(cfg), &ve) || ve["server.dsn"] == "" {
+		t.Errorf("postgres without dsn: errors = %v, want server.dsn", ve)
+	}
+
+	cfg.Server.DSN = "postgres://arc@localhost/arc"
+	if err := config.Validate(cfg); err != nil {
+		t.Errorf("postgres with dsn: Validate = %v, want nil", err)
+	}
+}
@@ -0,0 +1,185 @@
+package depgraph
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/sentiolabs/arc/internal/storage"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// waitGraph records which open issues of a project have to be finished
+// before which others. A blocks dependency makes the issue wait on its
+// blocker; a parent-child dependency makes the parent wait on its child,
+// since an epic is done only when its children are.
+type waitGraph struct {
+	issues   map[string]*types.Issue
+	waitsOn  map[string][]string
+	waitedBy map[string][]string
+}
+
+// loadWaitGraph reads the blocking dependencies between a project's open
+// issues.
+func loadWaitGraph(ctx context.Context, store storage.Storage, projectID string) (*waitGraph, error) {
+	all, err := projectIssues(ctx, store, projectID)
+	if err != nil {
+		return nil, err
+	}
+	g := &waitGraph{
+		issues:   make(map[string]*types.Issue),
+		waitsOn:  make(map[string][]string),
+		waitedBy: make(map[string][]string),
+	}
+	for _, issue := range all {
+		if issue.Status != types.StatusClosed {
+			g.issues[issue.ID] = issue
+		}
+	}
+	for id := range g.issues {
+		deps, err := store.GetDependencies(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range deps {
+			if _, open := g.issues[d.DependsOnID]; !open {
+				continue
+			}
+			switch d.Type {
+			case types.DepBlocks:
+				g.addWait(d.IssueID, d.DependsOnID)
+			case types.DepParentChild:
+				g.addWait(d.DependsOnID, d.IssueID)
+			}
+		}
+	}
+	for _, ids := range g.waitsOn {
+		slices.SortFunc(ids, g.compare)
+	}
+	for _, ids := range g.waitedBy {
+		slices.SortFunc(ids, g.compare)
+	}
+	return g, nil
+}
+
+// addWait records that id cannot finish before on.
+func (g *waitGraph) addWait(id, on string) {
+	g.waitsOn[id] = append(g.waitsOn[id], on)
+	g.waitedBy[on] = append(g.waitedBy[on], id)
+}
+
+// compare orders issue IDs by priority, then ID.
+func (g *waitGraph) compare(a, b string) int {
+	return cmp.Or(cmp.Compare(g.issues[a].Priority, g.issues[b].Priority), strings.Compare(a, b))
+}
+
+// longestChains returns, for every issue reachable from root, the longest
+// chain of issues it waits on, itself first. Loops left over from before
+// cycles were rejected are cut where they close.
+func (g *waitGraph) longestChains(root string) map[string][]string {
+	chains := make(map[string][]string)
+	onStack := make(map[string]bool)
+	var walk func(id string) []string
+	walk = func(id string) []string {
+		if chain, ok := chains[id]; ok {
+			return chain
+		}
+		onStack[id] = true
+		var longest []string
+		for _, on := range g.waitsOn[id] {
+			if onStack[on] {
+				continue
+			}
+			if chain := walk(on); len(chain) > len(longest) {
+				longest = chain
+			}
+		}
+		onStack[id] = false
+		chains[id] = append([]string{id}, longest...)
+		return chains[id]
+	}
+	walk(root)
+	return chains
+}
+
+// waiting returns every issue that transitively waits on id, nearest
+// first.
+func (g *waitGraph) waiting(id string) []string {
+	seen := map[string]bool{id: true}
+	var ids []string
+	for queue := []string{id}; len(queue) > 0; queue = queue[1:] {
+		for _, w := range g.waitedBy[queue[0]] {
+			if !seen[w] {
+				seen[w] = true
+				ids = append(ids, w)
+				queue = append(queue, w)
+			}
+		}
+	}
+	return ids
+}
+
+// unblocks returns the work waiting on id. An issue becomes ready when id
+// closes if it is open or in progress and waits on nothing else.
+func (g *waitGraph) unblocks(id string) *types.Unblocks {
+	u := &types.Unblocks{IssueID: id, Ready: []*types.Issue{}, Waiting: []*types.Issue{}}
+	for _, w := range g.waiting(id) {
+		u.Waiting = append(u.Waiting, g.issues[w])
+	}
+	for _, w := range g.waitedBy[id] {
+		issue := g.issues[w]
+		if len(g.waitsOn[w]) == 1 && (issue.Status == types.StatusOpen || issue.Status == types.StatusInProgress) {
+			u.Ready = append(u.Ready, issue)
+		}
+	}
+	return u
+}
+
+// CriticalPath finds the longest chain of open issues that must be
+// finished before rootID, typically an epic, can be, along with the issue
+// on which the most work waits among those gating it.
+func CriticalPath(ctx context.Context, store storage.Storage, projectID, rootID string) (*types.CriticalPath, error) {
+	g, err := loadWaitGraph(ctx, store, projectID)
+	if err != nil {
+		return nil, err
+	}
+	cp := &types.CriticalPath{RootID: rootID, Path: []*types.Issue{}}
+	if _, open := g.issues[rootID]; !open {
+		return cp, nil
+	}
+
+	chains := g.longestChains(rootID)
+	chain := slices.Clone(chains[rootID])
+	slices.Reverse(chain)
+	for _, id := range chain {
+		cp.Path = append(cp.Path, g.issues[id])
+	}
+
+	var gating []string
+	for id := range chains {
+		if id != rootID {
+			gating = append(gating, id)
+		}
+	}
+	slices.SortFunc(gating, g.compare)
+	for _, id := range gating {
+		if u := g.unblocks(id); cp.Bottleneck == nil || len(u.Waiting) > len(cp.Bottleneck.Waiting) {
+			cp.Bottleneck = u
+		}
+	}
+	return cp, nil
+}
+
+// Unblocks returns the open work waiting on an issue: what becomes ready
+// as soon as it closes, and everything that transitively waits on it.
+func Unblocks(ctx context.Context, store storage.Storage, projectID, issueID string) (*types.Unblocks, error) {
+	g, err := loadWaitGraph(ctx, store, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if _, open := g.issues[issueID]; !open {
+		return &types.Unblocks{IssueID: issueID, Ready: []*types.Issue{}, Waiting: []*types.Issue{}}, nil
+	}
+	return g.unblocks(issueID), nil
+}
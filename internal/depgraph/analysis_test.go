@@ -0,0 +1,97 @@
+package depgraph_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/depgraph"
+	"github.com/sentiolabs/arc/internal/storage/memory"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func TestCriticalPathAndUnblocks(t *testing.T) {
+	ctx := context.Background()
+	s := memory.New()
+
+	proj := &types.Project{Name: "Paths", Prefix: "pth"}
+	if err := s.CreateProject(ctx, proj); err != nil {
+		t.Fatalf("CreateProject failed: %v", err)
+	}
+	create := func(title, parentID string) string {
+		t.Helper()
+		issue := &types.Issue{ProjectID: proj.ID, Title: title, ParentID: parentID}
+		if err := s.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue(%s) failed: %v", title, err)
+		}
+		return issue.ID
+	}
+	block := func(issueID, blockerID string) {
+		t.Helper()
+		dep := &types.Dependency{IssueID: issueID, DependsOnID: blockerID, Type: types.DepBlocks}
+		if err := s.AddDependency(ctx, dep, "test"); err != nil {
+			t.Fatalf("AddDependency failed: %v", err)
+		}
+	}
+	ids := func(issues []*types.Issue) []string {
+		var out []string
+		for _, issue := range issues {
+			out = append(out, issue.ID)
+		}
+		return out
+	}
+
+	// epic waits on its children; api and ui wait on schema, which waits
+	// on spike, outside the epic.
+	epic := create("Epic", "")
+	schema := create("Schema", epic)
+	api := create("API", epic)
+	ui := create("UI", epic)
+	spike := create("Spike", "")
+	block(api, schema)
+	block(ui, schema)
+	block(schema, spike)
+
+	cp, err := depgraph.CriticalPath(ctx, s, proj.ID, epic)
+	if err != nil {
+		t.Fatalf("CriticalPath failed: %v", err)
+	}
+	// api and ui tie; either may carry the path.
+	if got := ids(cp.Path); len(got) != 4 || got[0] != spike || got[1] != schema ||
+		(got[2] != api && got[2] != ui) || got[3] != epic {
+		t.Errorf("critical path = %v, want %s, %s, %s or %s, %s", got, spike, schema, api, ui, epic)
+	}
+	if cp.Bottleneck == nil || cp.Bottleneck.IssueID != spike || len(cp.Bottleneck.Waiting) != 4 {
+		t.Errorf("bottleneck = %+v, want %s with 4 waiting", cp.Bottleneck, spike)
+	}
+
+	u, err := depgraph.Unblocks(ctx, s, proj.ID, schema)
+	if err != nil {
+		t.Fatalf("Unblocks failed: %v", err)
+	}
+	if got, want := ids(u.Ready), []string{api, ui}; !sameIDs(got, want) {
+		t.Errorf("ready when %s closes = %v, want %v", schema, got, want)
+	}
+	if got, want := ids(u.Waiting), []string{epic, api, ui}; !sameIDs(got, want) {
+		t.Errorf("waiting on %s = %v, want %v", schema, got, want)
+	}
+
+	// Closed issues drop out of the analysis.
+	if err := s.CloseIssue(ctx, spike, "done", false, "test"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	if cp, err = depgraph.CriticalPath(ctx, s, proj.ID, epic); err != nil {
+		t.Fatalf("CriticalPath failed: %v", err)
+	}
+	if got := ids(cp.Path); len(got) != 3 || got[0] != schema {
+		t.Errorf("critical path after closing %s = %v, want it to start at %s", spike, got, schema)
+	}
+	if u, err = depgraph.Unblocks(ctx, s, proj.ID, spike); err != nil || len(u.Waiting) != 0 {
+		t.Errorf("Unblocks(closed issue) = %+v, %v; want nothing waiting", u, err)
+	}
+}
+
+// sameIDs reports whether a and b hold the same IDs in any order.
+func sameIDs(a, b []string) bool {
+	return slices.Equal(slices.Sorted(slices.Values(a)), slices.Sorted(slices.Values(b)))
+}
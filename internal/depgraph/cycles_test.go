@@ -0,0 +1,32 @@
+package depgraph //nolint:testpackage // exercises the unexported cycle search
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func TestFindCycles(t *testing.T) {
+	ids := []string{"a", "b", "c", "d"}
+	issues := make([]*types.Issue, len(ids))
+	inProject := make(map[string]bool)
+	for i, id := range ids {
+		issues[i] = &types.Issue{ID: id}
+		inProject[id] = true
+	}
+	dep := func(from, to string, depType types.DependencyType) *types.Dependency {
+		return &types.Dependency{IssueID: from, DependsOnID: to, Type: depType}
+	}
+	deps := map[string][]*types.Dependency{
+		"a": {dep("a", "b", types.DepBlocks)},
+		"b": {dep("b", "c", types.DepParentChild)},
+		"c": {dep("c", "a", types.DepBlocks), dep("c", "d", types.DepRelated)},
+		"d": {dep("d", "a", types.DepRelated)},
+	}
+
+	cycles := findCycles(issues, deps, inProject)
+	if len(cycles) != 1 || !slices.Equal(cycles[0], []string{"a", "b", "c", "a"}) {
+		t.Errorf("cycles = %v, want only a -> b -> c -> a", cycles)
+	}
+}
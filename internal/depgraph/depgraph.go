@@ -0,0 +1,273 @@
+// Package depgraph builds, checks and renders the graph formed by issue
+// dependencies.
+//
+// Only blocking dependencies (see types.DependencyType.AffectsReadyWork)
+// can form harmful cycles: an issue on a loop of them waits on itself and
+// never becomes ready. Related and discovered-from links are free to loop.
+package depgraph
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/sentiolabs/arc/internal/storage"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// listPageSize is how many issues are read per ListIssues call.
+const listPageSize = 500
+
+// BlockersFunc returns the IDs of the issues that id waits on through
+// blocking dependencies.
+type BlockersFunc func(id string) ([]string, error)
+
+// Blockers returns a BlockersFunc that reads dependencies through
+// getDependencies, typically a store's GetDependencies.
+func Blockers(
+	ctx context.Context, getDependencies func(context.Context, string) ([]*types.Dependency, error),
+) BlockersFunc {
+	return func(id string) ([]string, error) {
+		deps, err := getDependencies(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		var ids []string
+		for _, d := range deps {
+			if d.Type.AffectsReadyWork() {
+				ids = append(ids, d.DependsOnID)
+			}
+		}
+		return ids, nil
+	}
+}
+
+// CheckCycle returns a *types.DependencyCycleError when adding dep would
+// close a loop of blocking dependencies, found by walking the existing
+// graph through blockers. Non-blocking dependencies are never rejected.
+func CheckCycle(dep *types.Dependency, blockers BlockersFunc) error {
+	if !dep.Type.AffectsReadyWork() {
+		return nil
+	}
+	path, err := findPath(dep.DependsOnID, dep.IssueID, blockers)
+	if err != nil || path == nil {
+		return err
+	}
+	return &types.DependencyCycleError{Path: append([]string{dep.IssueID}, path...)}
+}
+
+// findPath returns the shortest chain of blocking dependencies leading from
+// one issue to another, both included, or nil when there is none.
+func findPath(from, to string, blockers BlockersFunc) ([]string, error) {
+	prev := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if id == to {
+			var path []string
+			for ; id != ""; id = prev[id] {
+				path = append(path, id)
+			}
+			slices.Reverse(path)
+			return path, nil
+		}
+		next, err := blockers(id)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range next {
+			if _, seen := prev[n]; !seen {
+				prev[n] = id
+				queue = append(queue, n)
+			}
+		}
+	}
+	return nil, nil
+}
+
+// HierarchicalParent returns the parent encoded in a child ID such as
+// "arc.a3f8e9.1.2" ("arc.a3f8e9.1"), or "" for a top-level ID. A child ID
+// is its parent's ID followed by a numeric child suffix; the parent part must
+// itself be an ID, so a top-level ID with an all-digit hash is not a child.
+func HierarchicalParent(id string) string {
+	lastDot := strings.LastIndex(id, ".")
+	if lastDot == -1 || lastDot == len(id)-1 || !strings.Contains(id[:lastDot], ".") {
+		return ""
+	}
+	for _, c := range id[lastDot+1:] {
+		if c < '0' || c > '9' {
+			return ""
+		}
+	}
+	return id[:lastDot]
+}
+
+// Validate audits a project's dependency graph for loops of blocking
+// dependencies, dependencies on issues that are missing or belong to
+// another project, and parent-child dependencies that disagree with
+// hierarchical child IDs.
+func Validate(ctx context.Context, store storage.Storage, projectID string) (*types.DependencyReport, error) {
+	issues, err := projectIssues(ctx, store, projectID)
+	if err != nil {
+		return nil, err
+	}
+	report := &types.DependencyReport{ProjectID: projectID, Issues: len(issues), Problems: []*types.DependencyProblem{}}
+
+	inProject := make(map[string]bool, len(issues))
+	for _, issue := range issues {
+		inProject[issue.ID] = true
+	}
+
+	deps := make(map[string][]*types.Dependency, len(issues))
+	for _, issue := range issues {
+		if deps[issue.ID], err = store.GetDependencies(ctx, issue.ID); err != nil {
+			return nil, err
+		}
+		report.Dependencies += len(deps[issue.ID])
+	}
+
+	for _, issue := range issues {
+		for _, d := range deps[issue.ID] {
+			if inProject[d.DependsOnID] {
+				continue
+			}
+			problem, err := outsideProblem(ctx, store, d)
+			if err != nil {
+				return nil, err
+			}
+			report.Problems = append(report.Problems, problem)
+		}
+	}
+
+	for _, issue := range issues {
+		if p := parentProblem(issue.ID, deps[issue.ID], inProject); p != nil {
+			report.Problems = append(report.Problems, p)
+		}
+	}
+
+	for _, cycle := range findCycles(issues, deps, inProject) {
+		report.Problems = append(report.Problems, &types.DependencyProblem{
+			Kind:   types.ProblemCycle,
+			Cycle:  cycle,
+			Detail: strings.Join(cycle, " -> "),
+		})
+	}
+	return report, nil
+}
+
+// projectIssues returns all of a project's issues, open and closed.
+func projectIssues(ctx context.Context, store storage.Storage, projectID string) ([]*types.Issue, error) {
+	var issues []*types.Issue
+	for offset := 0; ; offset += listPageSize {
+		page, err := store.ListIssues(ctx, types.IssueFilter{ProjectID: projectID, Limit: listPageSize, Offset: offset})
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, page...)
+		if len(page) < listPageSize {
+			return issues, nil
+		}
+	}
+}
+
+// outsideProblem classifies a dependency on an issue outside the project.
+func outsideProblem(ctx context.Context, store storage.Storage, d *types.Dependency) (*types.DependencyProblem, error) {
+	target, err := store.GetIssue(ctx, d.DependsOnID)
+	if err != nil {
+		if !strings.Contains(err.Error(), "not found") {
+			return nil, err
+		}
+		return &types.DependencyProblem{
+			Kind:       types.ProblemDangling,
+			Dependency: d,
+			Detail:     fmt.Sprintf("%s depends on missing issue %s", d.IssueID, d.DependsOnID),
+		}, nil
+	}
+	return &types.DependencyProblem{
+		Kind:       types.ProblemCrossProject,
+		Dependency: d,
+		Detail:     fmt.Sprintf("%s depends on %s in project %s", d.IssueID, d.DependsOnID, target.ProjectID),
+	}, nil
+}
+
+// parentProblem reports a hierarchical child whose parent-child
+// dependency points somewhere other than the parent its ID names, or that
+// has none although that parent exists.
+func parentProblem(id string, deps []*types.Dependency, inProject map[string]bool) *types.DependencyProblem {
+	want := HierarchicalParent(id)
+	if want == "" {
+		return nil
+	}
+	for _, d := range deps {
+		if d.Type == types.DepParentChild && d.DependsOnID != want {
+			return &types.DependencyProblem{
+				Kind:       types.ProblemParentMismatch,
+				Dependency: d,
+				Detail:     fmt.Sprintf("%s is a child of %s but its ID names %s as parent", id, d.DependsOnID, want),
+			}
+		}
+	}
+	hasParent := slices.ContainsFunc(deps, func(d *types.Dependency) bool { return d.Type == types.DepParentChild })
+	if !hasParent && inProject[want] {
+		return &types.DependencyProblem{
+			Kind:   types.ProblemParentMismatch,
+			Detail: fmt.Sprintf("%s has no parent-child dependency on %s, the parent its ID names", id, want),
+		}
+	}
+	return nil
+}
+
+// findCycles returns the loops of blocking dependencies among a project's
+// issues, each starting and ending with the same issue. Every loop found
+// by a depth-first walk is reported once, whatever issue it was reached
+// from.
+func findCycles(issues []*types.Issue, deps map[string][]*types.Dependency, inProject map[string]bool) [][]string {
+	const (
+		unvisited = iota
+		onStack
+		done
+	)
+	state := make(map[string]int, len(issues))
+	var stack []string
+	var cycles [][]string
+	seen := make(map[string]bool)
+
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = onStack
+		stack = append(stack, id)
+		for _, d := range deps[id] {
+			if !d.Type.AffectsReadyWork() || !inProject[d.DependsOnID] {
+				continue
+			}
+			switch state[d.DependsOnID] {
+			case unvisited:
+				visit(d.DependsOnID)
+			case onStack:
+				start := slices.Index(stack, d.DependsOnID)
+				cycle := append(slices.Clone(stack[start:]), d.DependsOnID)
+				if key := cycleKey(cycle); !seen[key] {
+					seen[key] = true
+					cycles = append(cycles, cycle)
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[id] = done
+	}
+	for _, issue := range issues {
+		if state[issue.ID] == unvisited {
+			visit(issue.ID)
+		}
+	}
+	return cycles
+}
+
+// cycleKey identifies a cycle independently of where it starts.
+func cycleKey(cycle []string) string {
+	ids := slices.Clone(cycle[:len(cycle)-1])
+	start := slices.Index(ids, slices.Min(ids))
+	return strings.Join(append(ids[start:], ids[:start]...), " ")
+}
@@ -0,0 +1,84 @@
+package depgraph_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/depgraph"
+	"github.com/sentiolabs/arc/internal/storage/memory"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func TestHierarchicalParent(t *testing.T) {
+	for id, want := range map[string]string{
+		"arc.a3f8e9":     "",
+		"arc.123456":     "",
+		"arc.a3f8e9.":    "",
+		"arc.a3f8e9.x1":  "",
+		"arc.a3f8e9.1":   "arc.a3f8e9",
+		"arc.a3f8e9.1.2": "arc.a3f8e9.1",
+	} {
+		if got := depgraph.HierarchicalParent(id); got != want {
+			t.Errorf("HierarchicalParent(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	ctx := context.Background()
+	s := memory.New()
+
+	proj := &types.Project{Name: "Audit", Prefix: "aud"}
+	other := &types.Project{Name: "Other", Prefix: "oth"}
+	for _, p := range []*types.Project{proj, other} {
+		if err := s.CreateProject(ctx, p); err != nil {
+			t.Fatalf("CreateProject failed: %v", err)
+		}
+	}
+	create := func(projectID, title, parentID string) *types.Issue {
+		t.Helper()
+		issue := &types.Issue{ProjectID: projectID, Title: title, ParentID: parentID}
+		if err := s.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue(%s) failed: %v", title, err)
+		}
+		return issue
+	}
+	addDep := func(issueID, dependsOnID string, depType types.DependencyType) {
+		t.Helper()
+		dep := &types.Dependency{IssueID: issueID, DependsOnID: dependsOnID, Type: depType}
+		if err := s.AddDependency(ctx, dep, "test"); err != nil {
+			t.Fatalf("AddDependency(%s -> %s) failed: %v", issueID, dependsOnID, err)
+		}
+	}
+
+	epic := create(proj.ID, "Epic", "")
+	child := create(proj.ID, "Child", epic.ID)
+	orphan := create(proj.ID, "Orphan", epic.ID)
+	stray := create(proj.ID, "Stray", "")
+	foreign := create(other.ID, "Foreign", "")
+
+	addDep(child.ID, stray.ID, types.DepParentChild)
+	if err := s.RemoveDependency(ctx, orphan.ID, epic.ID, "test"); err != nil {
+		t.Fatalf("RemoveDependency failed: %v", err)
+	}
+	addDep(stray.ID, foreign.ID, types.DepBlocks)
+
+	report, err := depgraph.Validate(ctx, s, proj.ID)
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if report.Issues != 4 || report.Dependencies != 3 {
+		t.Errorf("checked %d issues and %d dependencies, want 4 and 3", report.Issues, report.Dependencies)
+	}
+
+	var kinds []string
+	for _, p := range report.Problems {
+		kinds = append(kinds, string(p.Kind))
+	}
+	slices.Sort(kinds)
+	want := []string{"cross_project", "parent_mismatch", "parent_mismatch"}
+	if !slices.Equal(kinds, want) {
+		t.Errorf("problems = %v, want %v", kinds, want)
+	}
+}
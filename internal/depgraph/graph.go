@@ -0,0 +1,126 @@
+package depgraph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sentiolabs/arc/internal/storage"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// GraphOptions selects the issues included in a graph.
+type GraphOptions struct {
+	RootID     string // Grow the graph from this issue; empty means the whole project
+	HideClosed bool   // Leave out closed issues, other than the root
+	MaxDepth   int    // With a root, the most dependency hops to follow; 0 means no limit
+}
+
+// Build assembles the dependency graph of a project. With a root, it
+// holds the issues reachable from the root through dependencies in either
+// direction, so an epic brings in its children and whatever blocks or is
+// blocked by them. Issues in other projects are left out, and hidden
+// closed issues are not walked through.
+func Build(ctx context.Context, store storage.Storage, projectID string, opts GraphOptions) (*types.Graph, error) {
+	g := &types.Graph{ProjectID: projectID, RootID: opts.RootID, Nodes: []*types.GraphNode{}, Edges: []*types.GraphEdge{}}
+	deps := make(map[string][]*types.Dependency)
+
+	if opts.RootID == "" {
+		issues, err := projectIssues(ctx, store, projectID)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			if !opts.HideClosed || issue.Status != types.StatusClosed {
+				g.Nodes = append(g.Nodes, graphNode(issue, 0))
+			}
+		}
+	} else if err := grow(ctx, store, projectID, opts, g, deps); err != nil {
+		return nil, err
+	}
+
+	included := make(map[string]bool, len(g.Nodes))
+	for _, n := range g.Nodes {
+		included[n.ID] = true
+	}
+	for _, n := range g.Nodes {
+		nodeDeps, ok := deps[n.ID]
+		if !ok {
+			var err error
+			if nodeDeps, err = store.GetDependencies(ctx, n.ID); err != nil {
+				return nil, err
+			}
+		}
+		for _, d := range nodeDeps {
+			if included[d.DependsOnID] {
+				g.Edges = append(g.Edges, &types.GraphEdge{From: d.IssueID, To: d.DependsOnID, Type: d.Type})
+			}
+		}
+	}
+	return g, nil
+}
+
+// grow adds the issues reachable from opts.RootID to g, breadth first,
+// recording each walked issue's dependencies in deps.
+func grow(
+	ctx context.Context, store storage.Storage, projectID string, opts GraphOptions,
+	g *types.Graph, deps map[string][]*types.Dependency,
+) error {
+	root, err := store.GetIssue(ctx, opts.RootID)
+	if err != nil {
+		return err
+	}
+	if root.ProjectID != projectID {
+		return fmt.Errorf("issue %s is not in project %s", root.ID, projectID)
+	}
+
+	depth := map[string]int{root.ID: 0}
+	g.Nodes = append(g.Nodes, graphNode(root, 0))
+	for i := 0; i < len(g.Nodes); i++ {
+		id := g.Nodes[i].ID
+		if deps[id], err = store.GetDependencies(ctx, id); err != nil {
+			return err
+		}
+		if opts.MaxDepth > 0 && depth[id] == opts.MaxDepth {
+			continue
+		}
+		dependents, err := store.GetDependents(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		var neighbors []string
+		for _, d := range deps[id] {
+			neighbors = append(neighbors, d.DependsOnID)
+		}
+		for _, d := range dependents {
+			neighbors = append(neighbors, d.IssueID)
+		}
+		for _, n := range neighbors {
+			if _, seen := depth[n]; seen {
+				continue
+			}
+			depth[n] = depth[id] + 1
+			issue, err := store.GetIssue(ctx, n)
+			if err != nil {
+				continue // dangling dependency; see Validate
+			}
+			if issue.ProjectID != projectID || (opts.HideClosed && issue.Status == types.StatusClosed) {
+				continue
+			}
+			g.Nodes = append(g.Nodes, graphNode(issue, depth[n]))
+		}
+	}
+	return nil
+}
+
+// graphNode returns the graph node for an issue.
+func graphNode(issue *types.Issue, depth int) *types.GraphNode {
+	return &types.GraphNode{
+		ID:        issue.ID,
+		Title:     issue.Title,
+		Status:    issue.Status,
+		IssueType: issue.IssueType,
+		Priority:  issue.Priority,
+		Depth:     depth,
+	}
+}
@@ -0,0 +1,106 @@
+package depgraph_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/depgraph"
+	"github.com/sentiolabs/arc/internal/storage/memory"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func TestBuild(t *testing.T) {
+	ctx := context.Background()
+	s := memory.New()
+
+	proj := &types.Project{Name: "Graph", Prefix: "gr"}
+	if err := s.CreateProject(ctx, proj); err != nil {
+		t.Fatalf("CreateProject failed: %v", err)
+	}
+	create := func(title, parentID string) *types.Issue {
+		t.Helper()
+		issue := &types.Issue{ProjectID: proj.ID, Title: title, ParentID: parentID}
+		if err := s.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue(%s) failed: %v", title, err)
+		}
+		return issue
+	}
+
+	epic := create("Epic", "")
+	api := create("API", epic.ID)
+	ui := create("UI", epic.ID)
+	schema := create("Schema", "")
+	unrelated := create("Unrelated", "")
+	dep := &types.Dependency{IssueID: api.ID, DependsOnID: schema.ID, Type: types.DepBlocks}
+	if err := s.AddDependency(ctx, dep, "test"); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+	if err := s.CloseIssue(ctx, ui.ID, "done", false, "test"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		opts  depgraph.GraphOptions
+		nodes []string
+		edges int
+	}{
+		{"project", depgraph.GraphOptions{}, []string{epic.ID, api.ID, ui.ID, schema.ID, unrelated.ID}, 3},
+		{"epic", depgraph.GraphOptions{RootID: epic.ID}, []string{epic.ID, api.ID, ui.ID, schema.ID}, 3},
+		{"depth", depgraph.GraphOptions{RootID: epic.ID, MaxDepth: 1}, []string{epic.ID, api.ID, ui.ID}, 2},
+		{"hide closed", depgraph.GraphOptions{RootID: epic.ID, HideClosed: true}, []string{epic.ID, api.ID, schema.ID}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := depgraph.Build(ctx, s, proj.ID, tt.opts)
+			if err != nil {
+				t.Fatalf("Build failed: %v", err)
+			}
+			var ids []string
+			for _, n := range g.Nodes {
+				ids = append(ids, n.ID)
+			}
+			slices.Sort(ids)
+			want := slices.Sorted(slices.Values(tt.nodes))
+			if !slices.Equal(ids, want) || len(g.Edges) != tt.edges {
+				t.Errorf("nodes %v with %d edges, want %v with %d", ids, len(g.Edges), want, tt.edges)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	g := &types.Graph{
+		RootID: "gr.epic",
+		Nodes: []*types.GraphNode{
+			{ID: "gr.epic", Title: `The "big" one`, Status: types.StatusInProgress, IssueType: types.TypeEpic},
+			{ID: "gr.epic.1", Title: "Child", Status: types.StatusOpen, IssueType: types.TypeBug},
+		},
+		Edges: []*types.GraphEdge{{From: "gr.epic.1", To: "gr.epic", Type: types.DepParentChild}},
+	}
+
+	dot := depgraph.DOT(g)
+	for _, want := range []string{
+		`"gr.epic" [label="gr.epic\nThe \"big\" one", shape=box3d, fillcolor="#fef3c7", color="#d97706", penwidth=2];`,
+		`"gr.epic.1" -> "gr.epic" [style=bold];`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT output missing %s:\n%s", want, dot)
+		}
+	}
+
+	mermaid := depgraph.Mermaid(g)
+	for _, want := range []string{
+		"flowchart LR\n",
+		`n0[["gr.epic<br/>The #quot;big#quot; one"]]`,
+		`n1{{"gr.epic.1<br/>Child"}}`,
+		"n1 ==> n0",
+		"class n0 in_progress",
+	} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("Mermaid output missing %s:\n%s", want, mermaid)
+		}
+	}
+}
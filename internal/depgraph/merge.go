@@ -0,0 +1,114 @@
+package depgraph
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// MergePlan lists how the dependencies of a duplicate issue carry over to
+// the canonical issue it is merged into.
+type MergePlan struct {
+	// Move pairs each dependency to rewrite with its replacement, which
+	// names the canonical issue in place of the duplicate.
+	Move [][2]*types.Dependency
+	// Drop holds dependencies that become redundant once merged: links
+	// between the two issues, links the canonical issue already has, and a
+	// second parent for the canonical issue.
+	Drop []*types.Dependency
+}
+
+// PlanMerge works out how to rewire the dependencies of duplicateID onto
+// canonicalID. deps and dependents are the duplicate's own dependencies and
+// the dependencies on it; canonicalDeps and canonicalDependents are the
+// same for the canonical issue. It fails if either issue has already been
+// merged, and returns a *types.DependencyCycleError if the merged graph
+// would hold a loop of blocking dependencies, found by walking the current
+// graph through blockers.
+func PlanMerge(
+	duplicateID, canonicalID string,
+	deps, dependents, canonicalDeps, canonicalDependents []*types.Dependency,
+	blockers BlockersFunc,
+) (*MergePlan, error) {
+	for _, d := range deps {
+		if d.Type == types.DepDuplicates {
+			return nil, fmt.Errorf("issue %s is already merged into %s", duplicateID, d.DependsOnID)
+		}
+	}
+	for _, d := range canonicalDeps {
+		if d.Type == types.DepDuplicates {
+			return nil, fmt.Errorf("issue %s is merged into %s; merge into that issue instead", canonicalID, d.DependsOnID)
+		}
+	}
+
+	plan := &MergePlan{}
+
+	hasParent := slices.ContainsFunc(canonicalDeps, func(d *types.Dependency) bool {
+		return d.Type == types.DepParentChild
+	})
+	for _, d := range deps {
+		switch {
+		case d.DependsOnID == canonicalID,
+			d.Type == types.DepParentChild && hasParent,
+			slices.ContainsFunc(canonicalDeps, func(c *types.Dependency) bool { return c.DependsOnID == d.DependsOnID }):
+			plan.Drop = append(plan.Drop, d)
+		default:
+			moved := *d
+			moved.IssueID = canonicalID
+			plan.Move = append(plan.Move, [2]*types.Dependency{d, &moved})
+			hasParent = hasParent || d.Type == types.DepParentChild
+		}
+	}
+	for _, d := range dependents {
+		if d.IssueID == canonicalID ||
+			slices.ContainsFunc(canonicalDependents, func(c *types.Dependency) bool { return c.IssueID == d.IssueID }) {
+			plan.Drop = append(plan.Drop, d)
+			continue
+		}
+		moved := *d
+		moved.DependsOnID = canonicalID
+		plan.Move = append(plan.Move, [2]*types.Dependency{d, &moved})
+	}
+
+	merged := mergedBlockers(duplicateID, canonicalID, blockers)
+	for _, m := range plan.Move {
+		if err := CheckCycle(m[1], merged); err != nil {
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+// mergedBlockers returns a BlockersFunc for the graph as it will be once
+// duplicateID is merged into canonicalID: the canonical issue also waits on
+// what the duplicate waited on, and whatever waited on the duplicate waits
+// on the canonical issue instead.
+func mergedBlockers(duplicateID, canonicalID string, blockers BlockersFunc) BlockersFunc {
+	return func(id string) ([]string, error) {
+		if id == duplicateID {
+			return nil, nil
+		}
+		ids, err := blockers(id)
+		if err != nil {
+			return nil, err
+		}
+		if id == canonicalID {
+			more, err := blockers(duplicateID)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(slices.Clone(ids), more...)
+		}
+		out := make([]string, 0, len(ids))
+		for _, b := range ids {
+			if b == duplicateID {
+				b = canonicalID
+			}
+			if b != id && !slices.Contains(out, b) {
+				out = append(out, b)
+			}
+		}
+		return out, nil
+	}
+}
@@ -0,0 +1,93 @@
+package depgraph_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/depgraph"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func TestPlanMerge(t *testing.T) {
+	dep := func(from, to string, depType types.DependencyType) *types.Dependency {
+		return &types.Dependency{IssueID: from, DependsOnID: to, Type: depType}
+	}
+	graph := []*types.Dependency{
+		dep("dup", "epic", types.DepParentChild), // canonical already has a parent: dropped
+		dep("dup", "api", types.DepBlocks),       // moved to canon -> api
+		dep("dup", "canon", types.DepRelated),    // between the two: dropped
+		dep("dup", "shared", types.DepBlocks),    // canonical has it already: dropped
+		dep("ui", "dup", types.DepBlocks),        // rewired to ui -> canon
+		dep("docs", "dup", types.DepBlocks),      // docs already waits on canon: dropped
+		dep("old", "dup", types.DepDuplicates),   // earlier merge, now points at canon
+		dep("canon", "other-epic", types.DepParentChild),
+		dep("canon", "shared", types.DepBlocks),
+		dep("docs", "canon", types.DepBlocks),
+	}
+	from := func(id string) []*types.Dependency {
+		var out []*types.Dependency
+		for _, d := range graph {
+			if d.IssueID == id {
+				out = append(out, d)
+			}
+		}
+		return out
+	}
+	to := func(id string) []*types.Dependency {
+		var out []*types.Dependency
+		for _, d := range graph {
+			if d.DependsOnID == id {
+				out = append(out, d)
+			}
+		}
+		return out
+	}
+	blockers := func(id string) ([]string, error) {
+		var ids []string
+		for _, d := range from(id) {
+			if d.Type.AffectsReadyWork() {
+				ids = append(ids, d.DependsOnID)
+			}
+		}
+		return ids, nil
+	}
+
+	plan, err := depgraph.PlanMerge("dup", "canon", from("dup"), to("dup"), from("canon"), to("canon"), blockers)
+	if err != nil {
+		t.Fatalf("PlanMerge failed: %v", err)
+	}
+	var moved []string
+	for _, m := range plan.Move {
+		moved = append(moved, m[1].IssueID+"->"+m[1].DependsOnID)
+	}
+	want := []string{"canon->api", "ui->canon", "old->canon"}
+	if len(moved) != len(want) {
+		t.Fatalf("moved %v, want %v", moved, want)
+	}
+	for i := range want {
+		if moved[i] != want[i] {
+			t.Errorf("moved %v, want %v", moved, want)
+			break
+		}
+	}
+	if len(plan.Drop) != 4 {
+		t.Errorf("dropped %d dependencies, want 4", len(plan.Drop))
+	}
+
+	// api already waits on canon, so canon waiting on api would be a loop.
+	graph = append(graph, dep("api", "canon", types.DepBlocks))
+	_, err = depgraph.PlanMerge("dup", "canon", from("dup"), to("dup"), from("canon"), to("canon"), blockers)
+	var cycleErr *types.DependencyCycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("PlanMerge = %v, want a DependencyCycleError", err)
+	}
+
+	// Neither issue may already be merged.
+	merged := []*types.Dependency{dep("dup", "elsewhere", types.DepDuplicates)}
+	if _, err := depgraph.PlanMerge("dup", "canon", merged, nil, nil, nil, blockers); err == nil {
+		t.Error("PlanMerge of a merged duplicate succeeded")
+	}
+	if _, err := depgraph.PlanMerge("canon", "dup", nil, nil, merged, nil, blockers); err == nil {
+		t.Error("PlanMerge into a merged issue succeeded")
+	}
+}
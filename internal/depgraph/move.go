@@ -0,0 +1,123 @@
+package depgraph
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sentiolabs/arc/internal/project"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// MovePlan lists the new IDs of an issue subtree moving to another project.
+type MovePlan struct {
+	// IDs maps the current ID of every moved issue to its new ID.
+	IDs map[string]string
+	// Order lists the current IDs of the moved issues, parents before
+	// their children.
+	Order []string
+	// Counters holds the last child number to record under each new
+	// parent ID.
+	Counters map[string]int
+}
+
+// PlanMove works out the IDs root and its descendants take when they move
+// to the project with the given prefix. Descendants are found through
+// dependents, following parent-child dependencies.
+//
+// The root keeps the hash part of its ID unless taken reports the new ID in
+// use, or the root is itself a child; it then gets a fresh ID. A child
+// numbered under its parent keeps its number under the parent's new ID;
+// any other child is numbered after the last child lastChild reports for
+// the parent.
+func PlanMove(
+	root *types.Issue, prefix string,
+	dependents func(id string) ([]*types.Dependency, error),
+	lastChild func(id string) (int, error),
+	taken func(id string) (bool, error),
+) (*MovePlan, error) {
+	rootID, err := movedRootID(root, prefix, taken)
+	if err != nil {
+		return nil, err
+	}
+	plan := &MovePlan{
+		IDs:      map[string]string{root.ID: rootID},
+		Order:    []string{root.ID},
+		Counters: make(map[string]int),
+	}
+
+	for i := 0; i < len(plan.Order); i++ {
+		parent := plan.Order[i]
+		deps, err := dependents(parent)
+		if err != nil {
+			return nil, err
+		}
+		last, err := lastChild(parent)
+		if err != nil {
+			return nil, err
+		}
+		newParent := plan.IDs[parent]
+
+		var renumber []string
+		for _, d := range deps {
+			if d.Type != types.DepParentChild {
+				continue
+			}
+			if _, seen := plan.IDs[d.IssueID]; seen {
+				continue
+			}
+			n, ok := childNumber(d.IssueID, parent)
+			if !ok {
+				renumber = append(renumber, d.IssueID)
+				continue
+			}
+			plan.IDs[d.IssueID] = fmt.Sprintf("%s.%d", newParent, n)
+			plan.Order = append(plan.Order, d.IssueID)
+			last = max(last, n)
+		}
+		for _, id := range renumber {
+			last++
+			plan.IDs[id] = fmt.Sprintf("%s.%d", newParent, last)
+			plan.Order = append(plan.Order, id)
+		}
+		if last > 0 {
+			plan.Counters[newParent] = last
+		}
+	}
+	return plan, nil
+}
+
+// movedRootID returns the ID root takes under prefix.
+func movedRootID(root *types.Issue, prefix string, taken func(id string) (bool, error)) (string, error) {
+	id := ""
+	if HierarchicalParent(root.ID) == "" {
+		if _, hash, ok := strings.Cut(root.ID, "."); ok && hash != "" {
+			id = prefix + "." + hash
+		}
+	}
+	for {
+		if id != "" {
+			inUse, err := taken(id)
+			if err != nil {
+				return "", err
+			}
+			if !inUse {
+				return id, nil
+			}
+		}
+		id = project.GenerateIssueID(prefix, root.Title+id)
+	}
+}
+
+// childNumber returns N when id is "parent.N".
+func childNumber(id, parent string) (int, bool) {
+	suffix, ok := strings.CutPrefix(id, parent+".")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(suffix)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,85 @@
+package depgraph_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/depgraph"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func TestPlanMove(t *testing.T) {
+	child := func(id, parent string) *types.Dependency {
+		return &types.Dependency{IssueID: id, DependsOnID: parent, Type: types.DepParentChild}
+	}
+	graph := []*types.Dependency{
+		child("arc.abc123.1", "arc.abc123"),
+		child("arc.abc123.3", "arc.abc123"),
+		child("arc.zzz999", "arc.abc123"), // reparented: numbered after the last child
+		child("arc.abc123.1.1", "arc.abc123.1"),
+		{IssueID: "arc.other", DependsOnID: "arc.abc123", Type: types.DepBlocks}, // not a child: stays
+	}
+	dependents := func(id string) ([]*types.Dependency, error) {
+		var out []*types.Dependency
+		for _, d := range graph {
+			if d.DependsOnID == id {
+				out = append(out, d)
+			}
+		}
+		return out, nil
+	}
+	lastChild := func(id string) (int, error) {
+		if id == "arc.abc123" {
+			return 4, nil // arc.abc123.4 was created and later deleted
+		}
+		return 0, nil
+	}
+	free := func(string) (bool, error) { return false, nil }
+
+	root := &types.Issue{ID: "arc.abc123", Title: "Epic"}
+	plan, err := depgraph.PlanMove(root, "web", dependents, lastChild, free)
+	if err != nil {
+		t.Fatalf("PlanMove failed: %v", err)
+	}
+	want := map[string]string{
+		"arc.abc123":     "web.abc123",
+		"arc.abc123.1":   "web.abc123.1",
+		"arc.abc123.3":   "web.abc123.3",
+		"arc.zzz999":     "web.abc123.5",
+		"arc.abc123.1.1": "web.abc123.1.1",
+	}
+	if len(plan.IDs) != len(want) {
+		t.Fatalf("IDs = %v, want %v", plan.IDs, want)
+	}
+	for from, to := range want {
+		if plan.IDs[from] != to {
+			t.Errorf("IDs[%s] = %s, want %s", from, plan.IDs[from], to)
+		}
+	}
+	if plan.Order[0] != root.ID || plan.Order[len(plan.Order)-1] != "arc.abc123.1.1" {
+		t.Errorf("Order = %v, want parents before children", plan.Order)
+	}
+	if plan.Counters["web.abc123"] != 5 || plan.Counters["web.abc123.1"] != 1 {
+		t.Errorf("Counters = %v, want web.abc123=5 and web.abc123.1=1", plan.Counters)
+	}
+
+	// A taken hash, or a root that is itself a child, gets a fresh ID.
+	taken := func(id string) (bool, error) { return id == "web.abc123", nil }
+	plan, err = depgraph.PlanMove(root, "web", dependents, lastChild, taken)
+	if err != nil {
+		t.Fatalf("PlanMove failed: %v", err)
+	}
+	if id := plan.IDs[root.ID]; id == "web.abc123" || !strings.HasPrefix(id, "web.") {
+		t.Errorf("root moved to %s, want a fresh web ID", id)
+	}
+	plan, err = depgraph.PlanMove(&types.Issue{ID: "arc.abc123.1", Title: "Child"}, "web", dependents, lastChild, free)
+	if err != nil {
+		t.Fatalf("PlanMove failed: %v", err)
+	}
+	if id := plan.IDs["arc.abc123.1"]; depgraph.HierarchicalParent(id) != "" || !strings.HasPrefix(id, "web.") {
+		t.Errorf("child root moved to %s, want a top-level web ID", id)
+	}
+	if plan.IDs["arc.abc123.1.1"] != plan.IDs["arc.abc123.1"]+".1" {
+		t.Errorf("grandchild moved to %s, want it under %s", plan.IDs["arc.abc123.1.1"], plan.IDs["arc.abc123.1"])
+	}
+}
@@ -0,0 +1,155 @@
+package depgraph
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// Graph export formats.
+const (
+	FormatDOT     = "dot"
+	FormatMermaid = "mermaid"
+	FormatJSON    = "json"
+)
+
+// labelMaxRunes caps the title shown in a node label.
+const labelMaxRunes = 40
+
+// statusColor is the fill and stroke color of a node, by issue status.
+var statusColor = map[types.Status][2]string{
+	types.StatusOpen:       {"#dbeafe", "#3b82f6"},
+	types.StatusInProgress: {"#fef3c7", "#d97706"},
+	types.StatusBlocked:    {"#fee2e2", "#dc2626"},
+	types.StatusDeferred:   {"#e5e7eb", "#6b7280"},
+	types.StatusClosed:     {"#dcfce7", "#16a34a"},
+}
+
+// dotShape is the Graphviz node shape, by issue type.
+var dotShape = map[types.IssueType]string{
+	types.TypeEpic:    "box3d",
+	types.TypeFeature: "ellipse",
+	types.TypeBug:     "octagon",
+	types.TypeTask:    "box",
+	types.TypeChore:   "note",
+}
+
+// dotEdgeStyle is the Graphviz edge attributes, by dependency type.
+var dotEdgeStyle = map[types.DependencyType]string{
+	types.DepBlocks:         `color="#dc2626"`,
+	types.DepParentChild:    `style=bold`,
+	types.DepRelated:        `style=dotted, arrowhead=none`,
+	types.DepDiscoveredFrom: `style=dashed`,
+	types.DepDuplicates:     `style=dashed, color="#6b7280"`,
+}
+
+// mermaidShape is the opening and closing brackets of a Mermaid node, by
+// issue type.
+var mermaidShape = map[types.IssueType][2]string{
+	types.TypeEpic:    {"[[", "]]"},
+	types.TypeFeature: {"([", "])"},
+	types.TypeBug:     {"{{", "}}"},
+	types.TypeTask:    {"[", "]"},
+	types.TypeChore:   {">", "]"},
+}
+
+// mermaidArrow is the Mermaid link, by dependency type.
+var mermaidArrow = map[types.DependencyType]string{
+	types.DepBlocks:         "-->",
+	types.DepParentChild:    "==>",
+	types.DepRelated:        "-.-",
+	types.DepDiscoveredFrom: "-.->",
+	types.DepDuplicates:     "-.->",
+}
+
+// DOT renders g in the Graphviz DOT language. Arrows point from an issue
+// to the issue it depends on.
+func DOT(g *types.Graph) string {
+	var b strings.Builder
+	b.WriteString("digraph arc {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [style=filled, fontname=\"Helvetica\", fontsize=11];\n")
+	for _, n := range g.Nodes {
+		colors := statusColor[n.Status]
+		shape := dotShape[n.IssueType]
+		if shape == "" {
+			shape = "box"
+		}
+		fmt.Fprintf(&b, "  %s [label=%s, shape=%s, fillcolor=%q, color=%q",
+			dotQuote(n.ID), dotQuote(n.ID+"\n"+shorten(n.Title)), shape, colors[0], colors[1])
+		if n.ID == g.RootID {
+			b.WriteString(", penwidth=2")
+		}
+		b.WriteString("];\n")
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s -> %s", dotQuote(e.From), dotQuote(e.To))
+		if style := dotEdgeStyle[e.Type]; style != "" {
+			fmt.Fprintf(&b, " [%s]", style)
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotQuote returns s as a DOT string literal.
+func dotQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// Mermaid renders g as a Mermaid flowchart, ready to paste into a
+// ```mermaid block. Arrows point from an issue to the issue it depends on.
+func Mermaid(g *types.Graph) string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+
+	ids := make(map[string]string, len(g.Nodes))
+	byStatus := make(map[types.Status][]string)
+	var statuses []types.Status
+	for i, n := range g.Nodes {
+		// Issue IDs contain dots, which Mermaid does not allow in node IDs.
+		id := fmt.Sprintf("n%d", i)
+		ids[n.ID] = id
+		shape, ok := mermaidShape[n.IssueType]
+		if !ok {
+			shape = mermaidShape[types.TypeTask]
+		}
+		label := mermaidEscape(n.ID) + "<br/>" + mermaidEscape(shorten(n.Title))
+		fmt.Fprintf(&b, "  %s%s\"%s\"%s\n", id, shape[0], label, shape[1])
+		if _, ok := byStatus[n.Status]; !ok {
+			statuses = append(statuses, n.Status)
+		}
+		byStatus[n.Status] = append(byStatus[n.Status], id)
+	}
+	for _, e := range g.Edges {
+		arrow := mermaidArrow[e.Type]
+		if arrow == "" {
+			arrow = "-->"
+		}
+		fmt.Fprintf(&b, "  %s %s %s\n", ids[e.From], arrow, ids[e.To])
+	}
+	for _, status := range statuses {
+		if colors, ok := statusColor[status]; ok {
+			fmt.Fprintf(&b, "  classDef %s fill:%s,stroke:%s\n", status, colors[0], colors[1])
+			fmt.Fprintf(&b, "  class %s %s\n", strings.Join(byStatus[status], ","), status)
+		}
+	}
+	return b.String()
+}
+
+// mermaidEscape makes s safe inside a quoted Mermaid label.
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;", "\n", " ").Replace(s)
+}
+
+// shorten truncates a title for a node label.
+func shorten(s string) string {
+	runes := []rune(s)
+	if len(runes) <= labelMaxRunes {
+		return s
+	}
+	return string(runes[:labelMaxRunes-1]) + "…"
+}
@@ -73,40 +73,7 @@ func NewSearcher(chunks []DocChunk) (*Searcher, error) {
 // Search performs a search with optional fuzzy matching.
 // When exact is false, fuzzy matching is enabled for typo tolerance.
 func (s *Searcher) Search(queryStr string, limit int, exact bool) ([]SearchResult, error) {
-	var q query.Query
-
-	if exact {
-		// Use match query for exact matching (still uses BM25 scoring)
-		q = bleve.NewMatchQuery(queryStr)
-	} else {
-		// Build a boolean query that combines exact and fuzzy matches
-		// This gives us both precision and typo tolerance
-		boolQuery := bleve.NewBooleanQuery()
-
-		// Add exact match with high boost
-		matchQuery := bleve.NewMatchQuery(queryStr)
-		matchQuery.SetBoost(exactMatchBoost)
-		boolQuery.AddShould(matchQuery)
-
-		// Add fuzzy matches for each term
-		terms := strings.Fields(queryStr)
-		for _, term := range terms {
-			if len(term) >= minFuzzyLength { // Only fuzzy match longer terms
-				fuzzyQuery := bleve.NewFuzzyQuery(term)
-				fuzzyQuery.SetFuzziness(getFuzziness(term))
-				boolQuery.AddShould(fuzzyQuery)
-			}
-		}
-
-		// Also try phrase matching for multi-word queries
-		if len(terms) > 1 {
-			phraseQuery := bleve.NewMatchPhraseQuery(queryStr)
-			phraseQuery.SetBoost(phraseMatchBoost)
-			boolQuery.AddShould(phraseQuery)
-		}
-
-		q = boolQuery
-	}
+	q := BuildQuery(queryStr, exact)
 
 	// Execute search
 	searchRequest := bleve.NewSearchRequest(q)
@@ -131,6 +98,45 @@ func (s *Searcher) Search(queryStr string, limit int, exact bool) ([]SearchResul
 	return results, nil
 }
 
+// BuildQuery returns the query Search runs for queryStr. When exact is false
+// it combines a boosted match query with per-term fuzzy queries and, for
+// multi-word input, a phrase query, so that close wordings still score well.
+// It is exported so other in-memory indexes can share the same scoring.
+func BuildQuery(queryStr string, exact bool) query.Query {
+	if exact {
+		// Use match query for exact matching (still uses BM25 scoring)
+		return bleve.NewMatchQuery(queryStr)
+	}
+
+	// Build a boolean query that combines exact and fuzzy matches
+	// This gives us both precision and typo tolerance
+	boolQuery := bleve.NewBooleanQuery()
+
+	// Add exact match with high boost
+	matchQuery := bleve.NewMatchQuery(queryStr)
+	matchQuery.SetBoost(exactMatchBoost)
+	boolQuery.AddShould(matchQuery)
+
+	// Add fuzzy matches for each term
+	terms := strings.Fields(queryStr)
+	for _, term := range terms {
+		if len(term) >= minFuzzyLength { // Only fuzzy match longer terms
+			fuzzyQuery := bleve.NewFuzzyQuery(term)
+			fuzzyQuery.SetFuzziness(getFuzziness(term))
+			boolQuery.AddShould(fuzzyQuery)
+		}
+	}
+
+	// Also try phrase matching for multi-word queries
+	if len(terms) > 1 {
+		phraseQuery := bleve.NewMatchPhraseQuery(queryStr)
+		phraseQuery.SetBoost(phraseMatchBoost)
+		boolQuery.AddShould(phraseQuery)
+	}
+
+	return boolQuery
+}
+
 // getFuzziness returns the appropriate fuzziness level based on term length.
 // Shorter words get less tolerance, longer words get more.
 func getFuzziness(term string) int {
@@ -0,0 +1,202 @@
+// Package dupes finds open issues that look like duplicates of one another.
+// Titles are compared with the same fuzzy and phrase matching that docsearch
+// uses for documentation, so titles that differ by a typo or word order
+// still match. Descriptions are left out: they vary far more between
+// reports of the same problem than titles do, and dilute the score.
+package dupes
+
+import (
+	"sort"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/sentiolabs/arc/internal/docsearch"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+const (
+	// DefaultThreshold is the similarity at or above which an issue is
+	// reported as a likely duplicate. A title scores 1 against itself; a
+	// reworded or misspelt title with the same key terms typically scores
+	// 0.2 to 0.5, as it misses the boosted exact and phrase matches, while
+	// titles sharing one incidental word stay near 0.1.
+	DefaultThreshold = 0.2
+
+	// maxMatches caps how many duplicates are reported for one issue.
+	maxMatches = 5
+
+	// probeID indexes the issue being checked by Find. Issue IDs never
+	// contain a NUL byte, so it cannot collide with one.
+	probeID = "\x00probe"
+)
+
+// indexDoc is the structure indexed by Bleve.
+type indexDoc struct {
+	Title string `json:"title"`
+}
+
+// Index is an in-memory similarity index over a set of issues, usually the
+// open issues of one project. An Index is not safe for concurrent use.
+type Index struct {
+	index  bleve.Index
+	issues map[string]*types.Issue // ID -> issue for retrieval
+}
+
+// NewIndex indexes the titles of issues.
+func NewIndex(issues []*types.Issue) (*Index, error) {
+	index, err := bleve.NewMemOnly(bleve.NewIndexMapping())
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*types.Issue, len(issues))
+	for _, issue := range issues {
+		byID[issue.ID] = issue
+		if err := index.Index(issue.ID, indexDoc{Title: issue.Title}); err != nil {
+			index.Close()
+			return nil, err
+		}
+	}
+	return &Index{index: index, issues: byID}, nil
+}
+
+// Close releases resources held by the index.
+func (x *Index) Close() error {
+	return x.index.Close()
+}
+
+// Find returns the indexed issues that an issue titled title would
+// duplicate, best match first. Only matches scoring at least threshold are
+// returned.
+func (x *Index) Find(title string, threshold float64) ([]*types.DuplicateMatch, error) {
+	if err := x.index.Index(probeID, indexDoc{Title: title}); err != nil {
+		return nil, err
+	}
+	defer func() { _ = x.index.Delete(probeID) }()
+
+	return x.similar(probeID, title, threshold)
+}
+
+// Clusters groups the indexed issues that look like duplicates of one
+// another, most similar group first. Issues within a group are ordered
+// oldest first, so the first is usually the one to keep.
+func (x *Index) Clusters(threshold float64) ([]*types.DuplicateCluster, error) {
+	ids := make([]string, 0, len(x.issues))
+	for id := range x.issues {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	parent := make(map[string]string, len(ids))
+	var find func(id string) string
+	find = func(id string) string {
+		p, ok := parent[id]
+		if !ok || p == id {
+			return id
+		}
+		root := find(p)
+		parent[id] = root
+		return root
+	}
+
+	type edge struct {
+		from  string
+		score float64
+	}
+	var edges []edge
+	matched := make(map[string]bool)
+	for _, id := range ids {
+		matches, err := x.similar(id, x.issues[id].Title, threshold)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			parent[find(m.Issue.ID)] = find(id)
+			edges = append(edges, edge{from: id, score: m.Score})
+			matched[id], matched[m.Issue.ID] = true, true
+		}
+	}
+
+	groups := make(map[string]*types.DuplicateCluster)
+	for _, id := range ids {
+		if !matched[id] {
+			continue
+		}
+		root := find(id)
+		cluster := groups[root]
+		if cluster == nil {
+			cluster = &types.DuplicateCluster{}
+			groups[root] = cluster
+		}
+		cluster.Issues = append(cluster.Issues, x.issues[id])
+	}
+	for _, e := range edges {
+		if cluster := groups[find(e.from)]; cluster != nil && e.score > cluster.Score {
+			cluster.Score = e.score
+		}
+	}
+
+	clusters := make([]*types.DuplicateCluster, 0, len(groups))
+	for _, cluster := range groups {
+		sort.Slice(cluster.Issues, func(i, j int) bool {
+			a, b := cluster.Issues[i], cluster.Issues[j]
+			if !a.CreatedAt.Equal(b.CreatedAt) {
+				return a.CreatedAt.Before(b.CreatedAt)
+			}
+			return a.ID < b.ID
+		})
+		clusters = append(clusters, cluster)
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].Score != clusters[j].Score {
+			return clusters[i].Score > clusters[j].Score
+		}
+		return clusters[i].Issues[0].ID < clusters[j].Issues[0].ID
+	})
+	return clusters, nil
+}
+
+// similar scores the indexed issues against title, relative to how well
+// title matches document id itself, so scores fall between 0 and 1
+// whatever the size of the index. Document id is left out of the result.
+func (x *Index) similar(id, title string, threshold float64) ([]*types.DuplicateMatch, error) {
+	req := bleve.NewSearchRequest(docsearch.BuildQuery(title, false))
+	req.Size = maxMatches + 1
+
+	result, err := x.index.Search(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Hits) == 0 {
+		return nil, nil
+	}
+
+	// If id did not make the top hits, others match at least as well as it
+	// does, so the best hit stands in for it.
+	self := result.Hits[0].Score
+	for _, hit := range result.Hits {
+		if hit.ID == id {
+			self = hit.Score
+			break
+		}
+	}
+	if self <= 0 {
+		return nil, nil
+	}
+
+	var matches []*types.DuplicateMatch
+	for _, hit := range result.Hits {
+		issue, ok := x.issues[hit.ID]
+		if hit.ID == id || !ok {
+			continue
+		}
+		score := min(hit.Score/self, 1)
+		if score < threshold {
+			continue
+		}
+		matches = append(matches, &types.DuplicateMatch{Issue: issue, Score: score})
+		if len(matches) == maxMatches {
+			break
+		}
+	}
+	return matches, nil
+}
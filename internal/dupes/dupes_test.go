@@ -0,0 +1,92 @@
+package dupes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func testIssues() []*types.Issue {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	issue := func(id, title, description string, age int) *types.Issue {
+		return &types.Issue{ID: id, Title: title, Description: description, CreatedAt: base.Add(time.Duration(age) * time.Hour)}
+	}
+	return []*types.Issue{
+		issue("arc-1", "Login page crashes on Safari", "Blank screen after submitting the form", 0),
+		issue("arc-2", "Login page crash in Safari", "", 1),
+		issue("arc-3", "Add CSV export to reports", "", 2),
+		issue("arc-4", "Loggin page crashes on safari", "", 3),
+		issue("arc-5", "Reports should export CSV", "", 4),
+		issue("arc-6", "Upgrade the database driver", "", 5),
+	}
+}
+
+func TestFind(t *testing.T) {
+	index, err := NewIndex(testIssues())
+	if err != nil {
+		t.Fatalf("NewIndex: %v", err)
+	}
+	defer index.Close()
+
+	matches, err := index.Find("Safari login page crashes", DefaultThreshold)
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	got := make(map[string]float64)
+	for _, m := range matches {
+		got[m.Issue.ID] = m.Score
+		if m.Score <= 0 || m.Score > 1 {
+			t.Errorf("%s scored %v, want a score in (0, 1]", m.Issue.ID, m.Score)
+		}
+	}
+	for _, id := range []string{"arc-1", "arc-4"} {
+		if _, ok := got[id]; !ok {
+			t.Errorf("Find missed %s; got %v", id, got)
+		}
+	}
+	if _, ok := got["arc-6"]; ok {
+		t.Errorf("Find matched unrelated arc-6: %v", got)
+	}
+
+	if matches, err := index.Find("Rotate the signing keys", DefaultThreshold); err != nil || len(matches) != 0 {
+		t.Errorf("Find(unrelated) = %v, %v; want no matches", matches, err)
+	}
+	// The probe document is removed again, so it never shows up later.
+	if matches, _ := index.Find("Safari login page crashes", DefaultThreshold); len(matches) != len(got) {
+		t.Errorf("repeated Find returned %d matches, want %d", len(matches), len(got))
+	}
+}
+
+func TestClusters(t *testing.T) {
+	index, err := NewIndex(testIssues())
+	if err != nil {
+		t.Fatalf("NewIndex: %v", err)
+	}
+	defer index.Close()
+
+	clusters, err := index.Clusters(DefaultThreshold)
+	if err != nil {
+		t.Fatalf("Clusters: %v", err)
+	}
+	var login *types.DuplicateCluster
+	for _, c := range clusters {
+		for _, issue := range c.Issues {
+			if issue.ID == "arc-6" {
+				t.Errorf("unrelated arc-6 was clustered: %+v", c.Issues)
+			}
+		}
+		if c.Issues[0].ID == "arc-1" {
+			login = c
+		}
+	}
+	if login == nil {
+		t.Fatalf("no cluster starts with the oldest login issue; got %d clusters", len(clusters))
+	}
+	if len(login.Issues) != 3 {
+		t.Errorf("login cluster has %d issues, want 3", len(login.Issues))
+	}
+	if login.Score < DefaultThreshold || login.Score > 1 {
+		t.Errorf("login cluster score = %v", login.Score)
+	}
+}
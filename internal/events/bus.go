@@ -0,0 +1,143 @@
+// Package events provides the in-process publish/subscribe bus that carries
+// live change notifications from the storage layer to event stream
+// subscribers (the SSE endpoint, and through it `arc watch`, `arc plan wait`
+// and the web UI).
+//
+// Delivery is best-effort and non-blocking: Publish never waits on a
+// subscriber. A subscriber that falls behind by more than its buffer is
+// dropped and its channel closed, so consumers always learn that they may
+// have missed events instead of silently skipping them.
+package events
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// DefaultBuffer is the per-subscriber channel capacity used when Subscribe
+// is called with a non-positive buffer size.
+const DefaultBuffer = 64
+
+// Filter selects which events a subscription receives. Empty fields match
+// everything.
+type Filter struct {
+	ProjectID string
+	IssueID   string
+	// Types lists accepted event types. An entry ending in ".*" matches every
+	// type with that prefix, e.g. "issue.*".
+	Types []string
+}
+
+// Match reports whether ev passes the filter.
+func (f Filter) Match(ev types.StreamEvent) bool {
+	if f.ProjectID != "" && ev.ProjectID != f.ProjectID {
+		return false
+	}
+	if f.IssueID != "" && ev.IssueID != f.IssueID {
+		return false
+	}
+	if len(f.Types) == 0 {
+		return true
+	}
+	for _, t := range f.Types {
+		if prefix, ok := strings.CutSuffix(t, "*"); ok {
+			if strings.HasPrefix(string(ev.Type), prefix) {
+				return true
+			}
+		} else if string(ev.Type) == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Bus fans published events out to matching subscribers.
+// The zero value is not usable; create one with NewBus.
+type Bus struct {
+	mu   sync.RWMutex
+	subs map[*Subscription]struct{}
+	seq  atomic.Uint64
+}
+
+// NewBus creates an empty event bus.
+func NewBus() *Bus {
+	return &Bus{subs: make(map[*Subscription]struct{})}
+}
+
+// Publish assigns the next sequence number (and a timestamp if unset) and
+// delivers ev to every matching subscriber without blocking.
+func (b *Bus) Publish(ev types.StreamEvent) {
+	ev.Seq = b.seq.Add(1)
+	if ev.Timestamp.IsZero() {
+		ev.Timestamp = time.Now()
+	}
+
+	var slow []*Subscription
+
+	b.mu.RLock()
+	for sub := range b.subs {
+		if !sub.filter.Match(ev) {
+			continue
+		}
+		select {
+		case sub.ch <- ev:
+		default:
+			slow = append(slow, sub)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, sub := range slow {
+		sub.Close()
+	}
+}
+
+// Subscribe registers a new subscription. buffer is the channel capacity;
+// non-positive values use DefaultBuffer. Callers must Close the
+// subscription when done.
+func (b *Bus) Subscribe(filter Filter, buffer int) *Subscription {
+	if buffer <= 0 {
+		buffer = DefaultBuffer
+	}
+	ch := make(chan types.StreamEvent, buffer)
+	sub := &Subscription{C: ch, ch: ch, filter: filter, bus: b}
+
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+
+	return sub
+}
+
+// Subscribers returns the number of active subscriptions.
+func (b *Bus) Subscribers() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subs)
+}
+
+// Subscription is a registered event consumer. C is closed when the
+// subscription is closed, either explicitly or because it fell behind.
+type Subscription struct {
+	C <-chan types.StreamEvent
+
+	ch     chan types.StreamEvent
+	filter Filter
+	bus    *Bus
+	once   sync.Once
+}
+
+// Close unregisters the subscription and closes C. It is safe to call
+// more than once.
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.bus.mu.Lock()
+		delete(s.bus.subs, s)
+		close(s.ch)
+		s.bus.mu.Unlock()
+	})
+}
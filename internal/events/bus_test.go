@@ -0,0 +1,86 @@
+package events_test
+
+import (
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/events"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func TestFilterMatch(t *testing.T) {
+	ev := types.StreamEvent{Type: types.StreamIssueClosed, ProjectID: "proj-1", IssueID: "arc-1"}
+
+	tests := []struct {
+		name   string
+		filter events.Filter
+		want   bool
+	}{
+		{"empty matches all", events.Filter{}, true},
+		{"project match", events.Filter{ProjectID: "proj-1"}, true},
+		{"project mismatch", events.Filter{ProjectID: "proj-2"}, false},
+		{"issue match", events.Filter{IssueID: "arc-1"}, true},
+		{"issue mismatch", events.Filter{IssueID: "arc-2"}, false},
+		{"exact type", events.Filter{Types: []string{"issue.closed"}}, true},
+		{"other type", events.Filter{Types: []string{"issue.created"}}, false},
+		{"prefix type", events.Filter{Types: []string{"plan.*", "issue.*"}}, true},
+		{"prefix mismatch", events.Filter{Types: []string{"plan.*"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(ev); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPublishDeliversToMatchingSubscribers(t *testing.T) {
+	bus := events.NewBus()
+	all := bus.Subscribe(events.Filter{}, 0)
+	defer all.Close()
+	plans := bus.Subscribe(events.Filter{Types: []string{"plan.*"}}, 0)
+	defer plans.Close()
+
+	bus.Publish(types.StreamEvent{Type: types.StreamIssueCreated, IssueID: "arc-1"})
+	bus.Publish(types.StreamEvent{Type: types.StreamPlanCreated, PlanID: "plan.1"})
+
+	first := <-all.C
+	second := <-all.C
+	if first.Seq != 1 || second.Seq != 2 {
+		t.Errorf("sequence = %d, %d; want 1, 2", first.Seq, second.Seq)
+	}
+	if first.Timestamp.IsZero() {
+		t.Error("expected Publish to set a timestamp")
+	}
+
+	got := <-plans.C
+	if got.PlanID != "plan.1" {
+		t.Errorf("plan subscriber got %+v, want plan.1", got)
+	}
+	select {
+	case ev := <-plans.C:
+		t.Errorf("plan subscriber received unexpected event %+v", ev)
+	default:
+	}
+}
+
+func TestSlowSubscriberIsDropped(t *testing.T) {
+	bus := events.NewBus()
+	sub := bus.Subscribe(events.Filter{}, 1)
+
+	bus.Publish(types.StreamEvent{Type: types.StreamIssueCreated})
+	bus.Publish(types.StreamEvent{Type: types.StreamIssueUpdated}) // overflows
+
+	if n := bus.Subscribers(); n != 0 {
+		t.Fatalf("subscribers = %d, want 0 after overflow", n)
+	}
+	if _, ok := <-sub.C; !ok {
+		t.Fatal("expected the buffered event before close")
+	}
+	if _, ok := <-sub.C; ok {
+		t.Fatal("expected channel to be closed")
+	}
+
+	// Closing again must not panic.
+	sub.Close()
+}
@@ -0,0 +1,244 @@
+package ghsync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultAPIURL is the REST endpoint of github.com. GitHub Enterprise and
+// other GitHub-compatible servers use their own base URL.
+const DefaultAPIURL = "https://api.github.com"
+
+// perPage is the page size requested from list endpoints.
+const perPage = 100
+
+// ErrRemote wraps every failure talking to the GitHub API.
+var ErrRemote = errors.New("github request failed")
+
+// Client is a minimal client for the GitHub Issues REST API.
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient creates a client for the REST API at baseURL (DefaultAPIURL
+// when empty). httpClient may be nil to use a client with a 30s timeout;
+// tests pass their own to talk to a local fake server.
+func NewClient(baseURL, token string, httpClient *http.Client) *Client {
+	if baseURL == "" {
+		baseURL = DefaultAPIURL
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		token:      token,
+		httpClient: httpClient,
+	}
+}
+
+// Issue is a GitHub issue. Pull requests are returned by the issues
+// endpoint too and carry a non-nil PullRequest.
+type Issue struct {
+	Number      int        `json:"number"`
+	Title       string     `json:"title"`
+	Body        string     `json:"body"`
+	State       string     `json:"state"`
+	StateReason string     `json:"state_reason,omitempty"`
+	Labels      []Label    `json:"labels"`
+	HTMLURL     string     `json:"html_url"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	ClosedAt    *time.Time `json:"closed_at,omitempty"`
+	PullRequest *struct{}  `json:"pull_request,omitempty"`
+}
+
+// Label is a GitHub label.
+type Label struct {
+	Name        string `json:"name"`
+	Color       string `json:"color,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Comment is a GitHub issue comment.
+type Comment struct {
+	ID        int64     `json:"id"`
+	Body      string    `json:"body"`
+	User      User      `json:"user"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// User is the author of a comment.
+type User struct {
+	Login string `json:"login"`
+}
+
+// ListIssues returns the repository's issues, excluding pull requests,
+// updated at or after since (all issues when since is zero).
+func (c *Client) ListIssues(ctx context.Context, repo string, since time.Time) ([]*Issue, error) {
+	query := url.Values{"state": {"all"}, "sort": {"updated"}, "direction": {"asc"}}
+	if !since.IsZero() {
+		query.Set("since", since.UTC().Format(time.RFC3339))
+	}
+
+	var issues []*Issue
+	err := c.paginate(ctx, "/repos/"+repo+"/issues", query, func(dec *json.Decoder) (int, error) {
+		var page []*Issue
+		if err := dec.Decode(&page); err != nil {
+			return 0, err
+		}
+		for _, issue := range page {
+			if issue.PullRequest == nil {
+				issues = append(issues, issue)
+			}
+		}
+		return len(page), nil
+	})
+	return issues, err
+}
+
+// GetIssue returns one issue.
+func (c *Client) GetIssue(ctx context.Context, repo string, number int) (*Issue, error) {
+	var issue Issue
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/issues/%d", repo, number), nil, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// ListComments returns an issue's comments updated at or after since.
+func (c *Client) ListComments(ctx context.Context, repo string, number int, since time.Time) ([]*Comment, error) {
+	query := url.Values{}
+	if !since.IsZero() {
+		query.Set("since", since.UTC().Format(time.RFC3339))
+	}
+
+	var comments []*Comment
+	path := fmt.Sprintf("/repos/%s/issues/%d/comments", repo, number)
+	err := c.paginate(ctx, path, query, func(dec *json.Decoder) (int, error) {
+		var page []*Comment
+		if err := dec.Decode(&page); err != nil {
+			return 0, err
+		}
+		comments = append(comments, page...)
+		return len(page), nil
+	})
+	return comments, err
+}
+
+// CreateComment adds a comment to an issue.
+func (c *Client) CreateComment(ctx context.Context, repo string, number int, body string) (*Comment, error) {
+	var comment Comment
+	path := fmt.Sprintf("/repos/%s/issues/%d/comments", repo, number)
+	if err := c.do(ctx, http.MethodPost, path, map[string]string{"body": body}, &comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// SetIssueState opens or closes an issue. stateReason is only sent when
+// closing ("completed" or "not_planned").
+func (c *Client) SetIssueState(ctx context.Context, repo string, number int, state, stateReason string) (*Issue, error) {
+	body := map[string]string{"state": state}
+	if state == "closed" && stateReason != "" {
+		body["state_reason"] = stateReason
+	}
+
+	var issue Issue
+	if err := c.do(ctx, http.MethodPatch, fmt.Sprintf("/repos/%s/issues/%d", repo, number), body, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// paginate requests successive pages of path until one comes back short.
+// decodePage returns the number of items on the page.
+func (c *Client) paginate(
+	ctx context.Context, path string, query url.Values, decodePage func(*json.Decoder) (int, error),
+) error {
+	query.Set("per_page", strconv.Itoa(perPage))
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+		resp, err := c.send(ctx, http.MethodGet, path+"?"+query.Encode(), nil)
+		if err != nil {
+			return err
+		}
+		n, err := decodePage(json.NewDecoder(resp.Body))
+		_ = resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("%w: decode %s: %w", ErrRemote, path, err)
+		}
+		if n < perPage {
+			return nil
+		}
+	}
+}
+
+// do sends a request with an optional JSON body and decodes the response
+// into out.
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	resp, err := c.send(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%w: decode %s: %w", ErrRemote, path, err)
+	}
+	return nil
+}
+
+// send performs a request and turns non-2xx responses into errors.
+func (c *Client) send(ctx context.Context, method, path string, body any) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("marshal body: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRemote, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		var errResp struct {
+			Message string `json:"message"`
+		}
+		data, _ := io.ReadAll(resp.Body)
+		if json.Unmarshal(data, &errResp) == nil && errResp.Message != "" {
+			return nil, fmt.Errorf("%w: %s %s: %s (HTTP %d)", ErrRemote, method, path, errResp.Message, resp.StatusCode)
+		}
+		return nil, fmt.Errorf("%w: %s %s: HTTP %d", ErrRemote, method, path, resp.StatusCode)
+	}
+	return resp, nil
+}
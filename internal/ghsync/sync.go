@@ -0,0 +1,525 @@
+// Package ghsync syncs a project's issues with a GitHub repository.
+//
+// Issues, labels, and comments are pulled from GitHub; status changes and
+// comments made in arc are pushed back. Each synced issue records
+// "owner/name#N" in ExternalRef. The sync cursor (the newest GitHub
+// updated_at seen) and the time of the last run are kept in project config
+// so each run only looks at what changed since the previous one. An issue
+// changed on both sides since the last run is reported as a conflict and
+// its fields are left alone.
+package ghsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/storage"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// Project config keys holding sync state.
+const (
+	ConfigRepo     = "sync.github.repo"
+	ConfigCursor   = "sync.github.cursor"
+	ConfigLastSync = "sync.github.last_sync"
+)
+
+// Action directions.
+const (
+	DirectionPull = "pull"
+	DirectionPush = "push"
+)
+
+const (
+	// remoteAuthorPrefix marks comments pulled from GitHub, which are never
+	// pushed back.
+	remoteAuthorPrefix = "github:"
+
+	// pushedMarker tags comments pushed from arc, which are never pulled
+	// back.
+	pushedMarker = "<!-- arc-comment:%d -->"
+
+	listPageSize = 100
+)
+
+// ErrNoRepo is returned when no repository is given and none is recorded
+// for the project.
+var ErrNoRepo = errors.New("no repository given and none recorded for this project")
+
+var (
+	repoPattern   = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`)
+	pushedPattern = regexp.MustCompile(`<!-- arc-comment:\d+ -->`)
+)
+
+// Options controls a sync run.
+type Options struct {
+	ProjectID string
+	Repo      string // "owner/name"
+	Actor     string // recorded on changes made in arc
+	DryRun    bool
+}
+
+// ExternalRef returns the ExternalRef of the arc issue synced with an issue.
+func ExternalRef(repo string, number int) string {
+	return repo + "#" + strconv.Itoa(number)
+}
+
+// ConfiguredRepo returns the repository a project last synced with.
+func ConfiguredRepo(ctx context.Context, store storage.Storage, projectID string) (string, error) {
+	cfg, err := store.GetProjectConfig(ctx, projectID)
+	if err != nil {
+		return "", err
+	}
+	if cfg[ConfigRepo] == "" {
+		return "", ErrNoRepo
+	}
+	return cfg[ConfigRepo], nil
+}
+
+// refNumber returns the issue number of ref if it belongs to repo.
+func refNumber(repo, ref string) (int, bool) {
+	rest, ok := strings.CutPrefix(ref, repo+"#")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(rest)
+	return n, err == nil
+}
+
+// syncer holds the state of one run.
+type syncer struct {
+	store    storage.Storage
+	gh       *Client
+	opts     Options
+	report   *types.SyncReport
+	cursor   time.Time // newest remote updated_at seen by the previous run
+	lastSync time.Time // when the previous run finished, in arc's clock
+}
+
+// Sync runs one two-way sync between a project and a GitHub repository.
+// A project syncs with one repository; pass the same repo on every run.
+func Sync(ctx context.Context, store storage.Storage, gh *Client, opts Options) (*types.SyncReport, error) {
+	if !repoPattern.MatchString(opts.Repo) {
+		return nil, fmt.Errorf("invalid repository %q (want owner/name)", opts.Repo)
+	}
+
+	s := &syncer{
+		store:  store,
+		gh:     gh,
+		opts:   opts,
+		report: &types.SyncReport{Repo: opts.Repo, DryRun: opts.DryRun, Actions: []*types.SyncAction{}},
+	}
+	if err := s.loadState(ctx); err != nil {
+		return nil, err
+	}
+
+	remote, err := gh.ListIssues(ctx, opts.Repo, s.cursor)
+	if err != nil {
+		return nil, err
+	}
+	linked, err := s.linkedIssues(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// GitHub's since filter has second precision, so the listing can include
+	// issues that have not changed since the cursor; only those updated after
+	// it count as changed remotely.
+	newCursor := s.cursor
+	changed := make(map[int]*Issue, len(remote))
+	conflicted := make(map[int]bool)
+	for _, ri := range remote {
+		if !ri.UpdatedAt.After(s.cursor) {
+			continue
+		}
+		changed[ri.Number] = ri
+		if ri.UpdatedAt.After(newCursor) {
+			newCursor = ri.UpdatedAt
+		}
+		if li := linked[ri.Number]; li != nil && s.localChanged(li) && differs(li, ri) {
+			conflicted[ri.Number] = true
+			s.report.Conflicts = append(s.report.Conflicts, &types.SyncConflict{
+				IssueID:         li.ID,
+				ExternalRef:     li.ExternalRef,
+				LocalUpdatedAt:  li.UpdatedAt,
+				RemoteUpdatedAt: ri.UpdatedAt,
+			})
+		}
+	}
+
+	for _, ri := range remote {
+		if changed[ri.Number] == nil {
+			continue
+		}
+		if err := s.pullIssue(ctx, ri, linked[ri.Number], conflicted[ri.Number]); err != nil {
+			return nil, err
+		}
+	}
+	for _, number := range slices.Sorted(maps.Keys(linked)) {
+		if conflicted[number] {
+			continue
+		}
+		if err := s.pushIssue(ctx, number, linked[number], changed[number] != nil); err != nil {
+			return nil, err
+		}
+	}
+
+	s.report.Cursor = newCursor
+	for _, a := range s.report.Actions {
+		if a.Direction == DirectionPull {
+			s.report.Pulled++
+		} else {
+			s.report.Pushed++
+		}
+	}
+	if opts.DryRun {
+		return s.report, nil
+	}
+	return s.report, s.saveState(ctx, newCursor)
+}
+
+// loadState reads the cursor and last-run time from project config.
+func (s *syncer) loadState(ctx context.Context) error {
+	if _, err := s.store.GetProject(ctx, s.opts.ProjectID); err != nil {
+		return err
+	}
+	cfg, err := s.store.GetProjectConfig(ctx, s.opts.ProjectID)
+	if err != nil {
+		return err
+	}
+	if repo := cfg[ConfigRepo]; repo != "" && repo != s.opts.Repo {
+		return fmt.Errorf("project %s syncs with %s, not %s", s.opts.ProjectID, repo, s.opts.Repo)
+	}
+	if v := cfg[ConfigCursor]; v != "" {
+		if s.cursor, err = time.Parse(time.RFC3339Nano, v); err != nil {
+			return fmt.Errorf("invalid %s: %w", ConfigCursor, err)
+		}
+	}
+	if v := cfg[ConfigLastSync]; v != "" {
+		if s.lastSync, err = time.Parse(time.RFC3339Nano, v); err != nil {
+			return fmt.Errorf("invalid %s: %w", ConfigLastSync, err)
+		}
+	}
+	return nil
+}
+
+// saveState records the new cursor and the end of this run.
+func (s *syncer) saveState(ctx context.Context, cursor time.Time) error {
+	state := map[string]string{
+		ConfigRepo:     s.opts.Repo,
+		ConfigLastSync: time.Now().UTC().Format(time.RFC3339Nano),
+	}
+	if !cursor.IsZero() {
+		state[ConfigCursor] = cursor.UTC().Format(time.RFC3339Nano)
+	}
+	for key, value := range state {
+		if err := s.store.SetProjectConfig(ctx, s.opts.ProjectID, key, value); err != nil {
+			return fmt.Errorf("save sync state: %w", err)
+		}
+	}
+	return nil
+}
+
+// linkedIssues returns the project's issues synced with the repository,
+// keyed by issue number.
+func (s *syncer) linkedIssues(ctx context.Context) (map[int]*types.Issue, error) {
+	linked := make(map[int]*types.Issue)
+	for offset := 0; ; offset += listPageSize {
+		page, err := s.store.ListIssues(ctx, types.IssueFilter{
+			ProjectID: s.opts.ProjectID, Limit: listPageSize, Offset: offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range page {
+			if n, ok := refNumber(s.opts.Repo, issue.ExternalRef); ok {
+				linked[n] = issue
+			}
+		}
+		if len(page) < listPageSize {
+			return linked, nil
+		}
+	}
+}
+
+// localChanged reports whether an arc issue changed since the last run.
+func (s *syncer) localChanged(li *types.Issue) bool {
+	return !s.lastSync.IsZero() && li.UpdatedAt.After(s.lastSync)
+}
+
+// differs reports whether the synced fields of the two sides disagree.
+func differs(li *types.Issue, ri *Issue) bool {
+	return li.Title != ri.Title || li.Description != ri.Body ||
+		(li.Status == types.StatusClosed) != (ri.State == "closed")
+}
+
+func (s *syncer) record(direction, kind string, issue *types.Issue, ref, detail string) {
+	a := &types.SyncAction{Direction: direction, Kind: kind, ExternalRef: ref, Detail: detail}
+	if issue != nil {
+		a.IssueID = issue.ID
+	}
+	s.report.Actions = append(s.report.Actions, a)
+}
+
+func (s *syncer) warn(format string, args ...any) {
+	s.report.Warnings = append(s.report.Warnings, fmt.Sprintf(format, args...))
+}
+
+// pullIssue applies a GitHub issue to its arc issue, creating it when
+// there is none. Fields are left alone when conflicted.
+func (s *syncer) pullIssue(ctx context.Context, ri *Issue, li *types.Issue, conflicted bool) error {
+	ref := ExternalRef(s.opts.Repo, ri.Number)
+
+	if li == nil {
+		if other, err := s.store.GetIssueByExternalRef(ctx, ref); err == nil {
+			s.warn("%s is already synced with %s in project %s; skipped", ref, other.ID, other.ProjectID)
+			return nil
+		}
+		created, err := s.createIssue(ctx, ri, ref)
+		if err != nil {
+			return err
+		}
+		li = created
+	} else if !conflicted && !s.localChanged(li) {
+		if err := s.updateIssue(ctx, ri, li, ref); err != nil {
+			return err
+		}
+	}
+
+	if err := s.pullLabels(ctx, ri, li, ref); err != nil {
+		return err
+	}
+	return s.pullComments(ctx, ri, li, ref)
+}
+
+func (s *syncer) createIssue(ctx context.Context, ri *Issue, ref string) (*types.Issue, error) {
+	issue := &types.Issue{
+		ProjectID:   s.opts.ProjectID,
+		Title:       ri.Title,
+		Description: ri.Body,
+		Status:      types.StatusOpen,
+		IssueType:   issueTypeFromLabels(ri.Labels),
+		ExternalRef: ref,
+	}
+	if ri.State == "closed" {
+		closedAt := time.Now()
+		if ri.ClosedAt != nil {
+			closedAt = *ri.ClosedAt
+		}
+		issue.Status = types.StatusClosed
+		issue.ClosedAt = &closedAt
+		issue.CloseReason = closeReason(ri)
+	}
+
+	s.record(DirectionPull, "create", nil, ref, ri.Title)
+	if s.opts.DryRun {
+		return issue, nil
+	}
+	if err := s.store.CreateIssue(ctx, issue, s.opts.Actor); err != nil {
+		return nil, fmt.Errorf("create issue for %s: %w", ref, err)
+	}
+	s.report.Actions[len(s.report.Actions)-1].IssueID = issue.ID
+	return issue, nil
+}
+
+func (s *syncer) updateIssue(ctx context.Context, ri *Issue, li *types.Issue, ref string) error {
+	updates := map[string]any{}
+	if li.Title != ri.Title {
+		updates["title"] = ri.Title
+	}
+	if li.Description != ri.Body {
+		updates["description"] = ri.Body
+	}
+	if len(updates) > 0 {
+		s.record(DirectionPull, "update", li, ref, strings.Join(slices.Sorted(maps.Keys(updates)), ", "))
+		if !s.opts.DryRun {
+			if err := s.store.UpdateIssue(ctx, li.ID, updates, 0, s.opts.Actor); err != nil {
+				return fmt.Errorf("update %s from %s: %w", li.ID, ref, err)
+			}
+		}
+	}
+
+	remoteClosed := ri.State == "closed"
+	switch {
+	case remoteClosed && li.Status != types.StatusClosed:
+		s.record(DirectionPull, "close", li, ref, closeReason(ri))
+		if !s.opts.DryRun {
+			if err := s.store.CloseIssue(ctx, li.ID, closeReason(ri), false, s.opts.Actor); err != nil {
+				s.warn("could not close %s: %v", li.ID, err)
+			}
+		}
+	case !remoteClosed && li.Status == types.StatusClosed:
+		s.record(DirectionPull, "reopen", li, ref, "")
+		if !s.opts.DryRun {
+			if err := s.store.ReopenIssue(ctx, li.ID, s.opts.Actor); err != nil {
+				return fmt.Errorf("reopen %s: %w", li.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// pullLabels adds GitHub labels missing from the arc issue, defining them
+// first when arc does not know them. Labels are never removed.
+func (s *syncer) pullLabels(ctx context.Context, ri *Issue, li *types.Issue, ref string) error {
+	var have []string
+	if li.ID != "" {
+		var err error
+		if have, err = s.store.GetIssueLabels(ctx, li.ID); err != nil {
+			return err
+		}
+	}
+
+	for _, label := range ri.Labels {
+		if slices.Contains(have, label.Name) {
+			continue
+		}
+		s.record(DirectionPull, "label", li, ref, label.Name)
+		if s.opts.DryRun {
+			continue
+		}
+		if _, err := s.store.GetLabel(ctx, label.Name); err != nil {
+			def := &types.Label{Name: label.Name, Description: label.Description}
+			if label.Color != "" {
+				def.Color = "#" + label.Color
+			}
+			if err := s.store.CreateLabel(ctx, def); err != nil {
+				return fmt.Errorf("create label %s: %w", label.Name, err)
+			}
+		}
+		if err := s.store.AddLabelToIssue(ctx, li.ID, label.Name, s.opts.Actor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pullComments adds GitHub comments made since the cursor. Comments that
+// were pushed from arc, or that arc already has, are skipped.
+func (s *syncer) pullComments(ctx context.Context, ri *Issue, li *types.Issue, ref string) error {
+	remote, err := s.gh.ListComments(ctx, s.opts.Repo, ri.Number, s.cursor)
+	if err != nil {
+		return err
+	}
+
+	var local []*types.Comment
+	if li.ID != "" {
+		if local, err = s.store.GetComments(ctx, li.ID); err != nil {
+			return err
+		}
+	}
+
+	for _, rc := range remote {
+		if pushedPattern.MatchString(rc.Body) {
+			continue
+		}
+		author := remoteAuthorPrefix + rc.User.Login
+		if hasComment(local, author, rc.Body) {
+			continue
+		}
+		s.record(DirectionPull, "comment", li, ref, author)
+		if s.opts.DryRun {
+			continue
+		}
+		if _, err := s.store.AddComment(ctx, li.ID, author, rc.Body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pushIssue sends an arc issue's status change and new comments to
+// GitHub. Nothing is pushed on the first run, when every change in arc
+// predates the sync.
+func (s *syncer) pushIssue(ctx context.Context, number int, li *types.Issue, pulled bool) error {
+	if s.lastSync.IsZero() {
+		return nil
+	}
+
+	if s.localChanged(li) && !pulled {
+		ri, err := s.gh.GetIssue(ctx, s.opts.Repo, number)
+		if err != nil {
+			return err
+		}
+		if err := s.pushState(ctx, number, li, ri); err != nil {
+			return err
+		}
+	}
+	return s.pushComments(ctx, number, li)
+}
+
+func (s *syncer) pushState(ctx context.Context, number int, li *types.Issue, ri *Issue) error {
+	localClosed := li.Status == types.StatusClosed
+	if localClosed == (ri.State == "closed") {
+		return nil
+	}
+
+	state, kind, reason := "open", "reopen", ""
+	if localClosed {
+		state, kind, reason = "closed", "close", "completed"
+	}
+	s.record(DirectionPush, kind, li, li.ExternalRef, "")
+	if s.opts.DryRun {
+		return nil
+	}
+	_, err := s.gh.SetIssueState(ctx, s.opts.Repo, number, state, reason)
+	return err
+}
+
+func (s *syncer) pushComments(ctx context.Context, number int, li *types.Issue) error {
+	comments, err := s.store.GetComments(ctx, li.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range comments {
+		if strings.HasPrefix(c.Author, remoteAuthorPrefix) || !c.CreatedAt.After(s.lastSync) {
+			continue
+		}
+		s.record(DirectionPush, "comment", li, li.ExternalRef, c.Author)
+		if s.opts.DryRun {
+			continue
+		}
+		body := fmt.Sprintf("%s\n\n_%s, via arc_\n"+pushedMarker, c.Text, c.Author, c.ID)
+		if _, err := s.gh.CreateComment(ctx, s.opts.Repo, number, body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// issueTypeFromLabels picks an arc issue type from GitHub's default labels.
+func issueTypeFromLabels(labels []Label) types.IssueType {
+	for _, l := range labels {
+		switch strings.ToLower(l.Name) {
+		case "bug":
+			return types.TypeBug
+		case "enhancement", "feature":
+			return types.TypeFeature
+		}
+	}
+	return types.TypeTask
+}
+
+func closeReason(ri *Issue) string {
+	if ri.StateReason != "" {
+		return "closed on GitHub (" + strings.ReplaceAll(ri.StateReason, "_", " ") + ")"
+	}
+	return "closed on GitHub"
+}
+
+func hasComment(comments []*types.Comment, author, text string) bool {
+	for _, c := range comments {
+		if c.Author == author && c.Text == text {
+			return true
+		}
+	}
+	return false
+}
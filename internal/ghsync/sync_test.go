@@ -0,0 +1,320 @@
+package ghsync_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/ghsync"
+	"github.com/sentiolabs/arc/internal/storage/sqlite"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+const repo = "acme/widgets"
+
+// fakeGitHub is an in-memory stand-in for the GitHub Issues REST API.
+type fakeGitHub struct {
+	mu       sync.Mutex
+	issues   map[int]*ghsync.Issue
+	comments map[int][]*ghsync.Comment
+	nextID   int64
+}
+
+func newFakeGitHub(t *testing.T) (*fakeGitHub, *ghsync.Client) {
+	t.Helper()
+	f := &fakeGitHub{issues: map[int]*ghsync.Issue{}, comments: map[int][]*ghsync.Comment{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/acme/widgets/issues", f.listIssues)
+	mux.HandleFunc("GET /repos/acme/widgets/issues/{n}", f.getIssue)
+	mux.HandleFunc("PATCH /repos/acme/widgets/issues/{n}", f.patchIssue)
+	mux.HandleFunc("GET /repos/acme/widgets/issues/{n}/comments", f.listComments)
+	mux.HandleFunc("POST /repos/acme/widgets/issues/{n}/comments", f.createComment)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	return f, ghsync.NewClient(srv.URL, "test-token", srv.Client())
+}
+
+func (f *fakeGitHub) addIssue(number int, title, state string, labels ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	issue := &ghsync.Issue{Number: number, Title: title, Body: title + " body", State: state,
+		CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	for _, l := range labels {
+		issue.Labels = append(issue.Labels, ghsync.Label{Name: l, Color: "d73a4a"})
+	}
+	f.issues[number] = issue
+}
+
+func (f *fakeGitHub) addComment(number int, login, body string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID++
+	now := time.Now()
+	f.comments[number] = append(f.comments[number], &ghsync.Comment{
+		ID: f.nextID, Body: body, User: ghsync.User{Login: login}, CreatedAt: now, UpdatedAt: now,
+	})
+	f.issues[number].UpdatedAt = now
+}
+
+func sinceParam(r *http.Request) time.Time {
+	since, _ := time.Parse(time.RFC3339, r.URL.Query().Get("since"))
+	return since
+}
+
+func (f *fakeGitHub) listIssues(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := []*ghsync.Issue{}
+	if r.URL.Query().Get("page") == "1" {
+		since := sinceParam(r)
+		for _, issue := range f.issues {
+			if !issue.UpdatedAt.Before(since) {
+				out = append(out, issue)
+			}
+		}
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (f *fakeGitHub) issue(w http.ResponseWriter, r *http.Request) *ghsync.Issue {
+	n, _ := strconv.Atoi(r.PathValue("n"))
+	issue := f.issues[n]
+	if issue == nil {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+	}
+	return issue
+}
+
+func (f *fakeGitHub) getIssue(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if issue := f.issue(w, r); issue != nil {
+		_ = json.NewEncoder(w).Encode(issue)
+	}
+}
+
+func (f *fakeGitHub) patchIssue(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	issue := f.issue(w, r)
+	if issue == nil {
+		return
+	}
+	var body struct {
+		State string `json:"state"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	issue.State = body.State
+	issue.UpdatedAt = time.Now()
+	_ = json.NewEncoder(w).Encode(issue)
+}
+
+func (f *fakeGitHub) listComments(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n, _ := strconv.Atoi(r.PathValue("n"))
+	out := []*ghsync.Comment{}
+	if r.URL.Query().Get("page") == "1" {
+		since := sinceParam(r)
+		for _, c := range f.comments[n] {
+			if !c.UpdatedAt.Before(since) {
+				out = append(out, c)
+			}
+		}
+	}
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+func (f *fakeGitHub) createComment(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Body string `json:"body"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	n, _ := strconv.Atoi(r.PathValue("n"))
+	f.addComment(n, "arc-bot", body.Body)
+	w.WriteHeader(http.StatusCreated)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	list := f.comments[n]
+	_ = json.NewEncoder(w).Encode(list[len(list)-1])
+}
+
+func setup(t *testing.T) (*sqlite.Store, *types.Project) {
+	t.Helper()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	proj := &types.Project{Name: "Widgets", Prefix: "wid"}
+	if err := store.CreateProject(context.Background(), proj); err != nil {
+		t.Fatalf("CreateProject: %v", err)
+	}
+	return store, proj
+}
+
+func runSync(t *testing.T, store *sqlite.Store, gh *ghsync.Client, projID string, dryRun bool) *types.SyncReport {
+	t.Helper()
+	// Keep timestamps on either side of a run distinct.
+	time.Sleep(5 * time.Millisecond)
+	report, err := ghsync.Sync(context.Background(), store, gh, ghsync.Options{
+		ProjectID: projID, Repo: repo, Actor: "syncer", DryRun: dryRun,
+	})
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	return report
+}
+
+func TestSync_FirstRunPulls(t *testing.T) {
+	ctx := context.Background()
+	store, proj := setup(t)
+	fake, gh := newFakeGitHub(t)
+	fake.addIssue(1, "Crash on start", "open", "bug")
+	fake.addIssue(2, "Old request", "closed")
+	fake.addComment(1, "octocat", "Same here")
+
+	dry := runSync(t, store, gh, proj.ID, true)
+	if dry.Pulled != 4 || !dry.DryRun {
+		t.Errorf("dry run = %+v", dry)
+	}
+	if issues, _ := store.ListIssues(ctx, types.IssueFilter{ProjectID: proj.ID}); len(issues) != 0 {
+		t.Fatalf("dry run created %d issues", len(issues))
+	}
+	if cfg, _ := store.GetProjectConfig(ctx, proj.ID); cfg[ghsync.ConfigCursor] != "" {
+		t.Errorf("dry run saved cursor %q", cfg[ghsync.ConfigCursor])
+	}
+
+	report := runSync(t, store, gh, proj.ID, false)
+	if report.Pulled != 4 || report.Pushed != 0 {
+		t.Errorf("report = %+v", report)
+	}
+
+	crash, err := store.GetIssueByExternalRef(ctx, "acme/widgets#1")
+	if err != nil {
+		t.Fatalf("issue #1 not pulled: %v", err)
+	}
+	if crash.IssueType != types.TypeBug || crash.Description != "Crash on start body" {
+		t.Errorf("issue #1 = %+v", crash)
+	}
+	if labels, _ := store.GetIssueLabels(ctx, crash.ID); len(labels) != 1 || labels[0] != "bug" {
+		t.Errorf("labels = %v", labels)
+	}
+	if comments, _ := store.GetComments(ctx, crash.ID); len(comments) != 1 || comments[0].Author != "github:octocat" {
+		t.Errorf("comments = %+v", comments)
+	}
+	old, _ := store.GetIssueByExternalRef(ctx, "acme/widgets#2")
+	if old == nil || old.Status != types.StatusClosed {
+		t.Errorf("issue #2 = %+v", old)
+	}
+
+	cfg, _ := store.GetProjectConfig(ctx, proj.ID)
+	if cfg[ghsync.ConfigRepo] != repo || cfg[ghsync.ConfigCursor] == "" || cfg[ghsync.ConfigLastSync] == "" {
+		t.Errorf("sync state = %v", cfg)
+	}
+
+	// Nothing changed, so nothing is pulled twice.
+	again := runSync(t, store, gh, proj.ID, false)
+	if again.Pushed != 0 {
+		t.Errorf("rerun pushed %d", again.Pushed)
+	}
+	if comments, _ := store.GetComments(ctx, crash.ID); len(comments) != 1 {
+		t.Errorf("rerun duplicated comments: %+v", comments)
+	}
+}
+
+func TestSync_PushesStatusAndComments(t *testing.T) {
+	ctx := context.Background()
+	store, proj := setup(t)
+	fake, gh := newFakeGitHub(t)
+	fake.addIssue(1, "Crash on start", "open")
+	fake.addIssue(2, "Docs", "open")
+	runSync(t, store, gh, proj.ID, false)
+
+	crash, _ := store.GetIssueByExternalRef(ctx, "acme/widgets#1")
+	if err := store.CloseIssue(ctx, crash.ID, "fixed", false, "alice"); err != nil {
+		t.Fatalf("CloseIssue: %v", err)
+	}
+	if _, err := store.AddComment(ctx, crash.ID, "alice", "Fixed in v2"); err != nil {
+		t.Fatalf("AddComment: %v", err)
+	}
+	fake.addComment(2, "octocat", "Needs examples")
+
+	report := runSync(t, store, gh, proj.ID, false)
+	if report.Pushed != 2 {
+		t.Errorf("pushed %d, want close and comment: %+v", report.Pushed, report.Actions)
+	}
+	if state := fake.issues[1].State; state != "closed" {
+		t.Errorf("GitHub #1 state = %s, want closed", state)
+	}
+	pushed := fake.comments[1]
+	if len(pushed) != 1 || !strings.HasPrefix(pushed[0].Body, "Fixed in v2") {
+		t.Fatalf("GitHub #1 comments = %+v", pushed)
+	}
+	docs, _ := store.GetIssueByExternalRef(ctx, "acme/widgets#2")
+	if comments, _ := store.GetComments(ctx, docs.ID); len(comments) != 1 || comments[0].Text != "Needs examples" {
+		t.Errorf("docs comments = %+v", comments)
+	}
+
+	// The pushed comment is not pulled back, and the arc comment is not
+	// pushed again.
+	runSync(t, store, gh, proj.ID, false)
+	if comments, _ := store.GetComments(ctx, crash.ID); len(comments) != 1 {
+		t.Errorf("crash comments after rerun = %+v", comments)
+	}
+	if len(fake.comments[1]) != 1 {
+		t.Errorf("GitHub #1 comments after rerun = %d", len(fake.comments[1]))
+	}
+}
+
+func TestSync_Conflict(t *testing.T) {
+	ctx := context.Background()
+	store, proj := setup(t)
+	fake, gh := newFakeGitHub(t)
+	fake.addIssue(1, "Crash on start", "open")
+	runSync(t, store, gh, proj.ID, false)
+
+	crash, _ := store.GetIssueByExternalRef(ctx, "acme/widgets#1")
+	if err := store.UpdateIssue(ctx, crash.ID, map[string]any{"title": "Crash on launch"}, 0, "alice"); err != nil {
+		t.Fatalf("UpdateIssue: %v", err)
+	}
+	fake.mu.Lock()
+	fake.issues[1].State = "closed"
+	fake.issues[1].UpdatedAt = time.Now()
+	fake.mu.Unlock()
+
+	report := runSync(t, store, gh, proj.ID, false)
+	if len(report.Conflicts) != 1 || report.Conflicts[0].IssueID != crash.ID {
+		t.Fatalf("conflicts = %+v", report.Conflicts)
+	}
+
+	got, _ := store.GetIssue(ctx, crash.ID)
+	if got.Title != "Crash on launch" || got.Status != types.StatusOpen {
+		t.Errorf("conflicted issue changed locally: %+v", got)
+	}
+	if fake.issues[1].State != "closed" {
+		t.Errorf("conflicted issue changed on GitHub")
+	}
+}
+
+func TestSync_RejectsOtherRepo(t *testing.T) {
+	store, proj := setup(t)
+	_, gh := newFakeGitHub(t)
+	runSync(t, store, gh, proj.ID, false)
+
+	_, err := ghsync.Sync(context.Background(), store, gh, ghsync.Options{ProjectID: proj.ID, Repo: "acme/other"})
+	if err == nil || !strings.Contains(err.Error(), "syncs with acme/widgets") {
+		t.Errorf("error = %v", err)
+	}
+}
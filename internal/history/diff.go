@@ -0,0 +1,67 @@
+package history
+
+import "strings"
+
+// LineOp marks how a line of a diff relates the old text to the new.
+type LineOp byte
+
+const (
+	LineSame    LineOp = ' '
+	LineRemoved LineOp = '-'
+	LineAdded   LineOp = '+'
+)
+
+// Line is one line of a line-based diff.
+type Line struct {
+	Op   LineOp
+	Text string
+}
+
+// DiffLines returns a minimal line diff turning before into after, with
+// removed lines listed before the lines that replace them.
+func DiffLines(before, after string) []Line {
+	a, b := splitLines(before), splitLines(after)
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	lines := make([]Line, 0, max(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, Line{Op: LineSame, Text: a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, Line{Op: LineRemoved, Text: a[i]})
+			i++
+		default:
+			lines = append(lines, Line{Op: LineAdded, Text: b[j]})
+			j++
+		}
+	}
+	return lines
+}
+
+// splitLines splits text into lines, treating the empty string as no
+// lines at all.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
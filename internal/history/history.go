@@ -0,0 +1,252 @@
+// Package history records field-level issue changes and rebuilds past
+// states of an issue from its audit trail.
+//
+// Every change to a tracked issue field is recorded as one updated event
+// whose old and new values have the form "field=value". Replaying those
+// events backwards from the current issue, together with the status,
+// close, reopen, label, custom field and move events, yields the issue as
+// it was at any earlier moment.
+package history
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// TrackedFields lists the issue fields recorded by updated events, in the
+// order their changes are recorded.
+var TrackedFields = []string{
+	"title", "description", "priority", "issue_type", "ai_session_id", "external_ref", "rank",
+}
+
+// ErrNotCreated is returned by At for a moment before the issue existed.
+var ErrNotCreated = errors.New("issue did not exist yet")
+
+// Change is a tracked field whose value changed.
+type Change struct {
+	Field    string
+	Old, New string
+}
+
+// EventValues returns the change in the form stored on updated events.
+func (c Change) EventValues() (oldValue, newValue *string) {
+	o := c.Field + "=" + c.Old
+	n := c.Field + "=" + c.New
+	return &o, &n
+}
+
+// Changes returns the tracked fields in updates whose value differs from
+// issue, in TrackedFields order. Other keys are ignored.
+func Changes(issue *types.Issue, updates map[string]any) []Change {
+	var changes []Change
+	for _, field := range TrackedFields {
+		value, ok := updates[field]
+		if !ok {
+			continue
+		}
+		next := fmt.Sprint(value)
+		if prev := FieldValue(issue, field); prev != next {
+			changes = append(changes, Change{Field: field, Old: prev, New: next})
+		}
+	}
+	return changes
+}
+
+// FieldValue returns a tracked field of issue formatted as it is recorded.
+func FieldValue(issue *types.Issue, field string) string {
+	switch field {
+	case "title":
+		return issue.Title
+	case "description":
+		return issue.Description
+	case "priority":
+		return strconv.Itoa(issue.Priority)
+	case "issue_type":
+		return string(issue.IssueType)
+	case "ai_session_id":
+		return issue.AISessionID
+	case "external_ref":
+		return issue.ExternalRef
+	case "rank":
+		return strconv.Itoa(issue.Rank)
+	}
+	return ""
+}
+
+// setFieldValue sets a tracked field of issue from its recorded form.
+func setFieldValue(issue *types.Issue, field, value string) {
+	switch field {
+	case "title":
+		issue.Title = value
+	case "description":
+		issue.Description = value
+	case "priority":
+		issue.Priority, _ = strconv.Atoi(value)
+	case "issue_type":
+		issue.IssueType = types.IssueType(value)
+	case "ai_session_id":
+		issue.AISessionID = value
+	case "external_ref":
+		issue.ExternalRef = value
+	case "rank":
+		issue.Rank, _ = strconv.Atoi(value)
+	}
+}
+
+// ParseValue splits an event value of the form "field=value". ok is false
+// when v is nil or not in that form.
+func ParseValue(v *string) (field, value string, ok bool) {
+	if v == nil {
+		return "", "", false
+	}
+	return strings.Cut(*v, "=")
+}
+
+// MoveValue returns the value a moved event records for an issue's ID
+// within a project, in the form "id@project".
+func MoveValue(id, projectID string) *string {
+	v := id + "@" + projectID
+	return &v
+}
+
+// ParseMoveValue splits a moved event value into the issue ID and project
+// ID. Values recorded before moves carried the project hold only the ID,
+// and projectID is then empty.
+func ParseMoveValue(v *string) (id, projectID string) {
+	if v == nil {
+		return "", ""
+	}
+	id, projectID, _ = strings.Cut(*v, "@")
+	return id, projectID
+}
+
+// Sort orders events oldest first, breaking ties by ID.
+func Sort(events []*types.Event) {
+	slices.SortFunc(events, func(a, b *types.Event) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+}
+
+// At returns a copy of current as it was at t, undoing every event in
+// events recorded after t. events must be the issue's complete audit trail,
+// oldest first. Version is cleared since it only describes the current
+// state. Events recorded before changes carried their old values cannot
+// be undone and are skipped.
+func At(current *types.Issue, events []*types.Event, t time.Time) (*types.Issue, error) {
+	if t.Before(current.CreatedAt) {
+		return nil, ErrNotCreated
+	}
+
+	issue := *current
+	issue.Version = 0
+	issue.Labels = slices.Clone(current.Labels)
+	issue.Fields = make(map[string]string, len(current.Fields))
+	maps.Copy(issue.Fields, current.Fields)
+	issue.ClosedAt = nil
+	if current.ClosedAt != nil {
+		closedAt := *current.ClosedAt
+		issue.ClosedAt = &closedAt
+	}
+
+	i := len(events) - 1
+	for ; i >= 0 && events[i].CreatedAt.After(t); i-- {
+		Revert(&issue, events[i], events[:i])
+	}
+
+	issue.UpdatedAt = issue.CreatedAt
+	for _, e := range events[:i+1] {
+		if changesIssue(e.EventType) && e.CreatedAt.After(issue.UpdatedAt) {
+			issue.UpdatedAt = e.CreatedAt
+		}
+	}
+	if len(issue.Labels) == 0 {
+		issue.Labels = nil
+	}
+	if len(issue.Fields) == 0 {
+		issue.Fields = nil
+	}
+	return &issue, nil
+}
+
+// Revert undoes one event on issue in place. earlier holds the events
+// recorded before e, oldest first; they supply the close time restored when
+// a reopen is undone. issue.Fields must not be nil.
+func Revert(issue *types.Issue, e *types.Event, earlier []*types.Event) {
+	switch e.EventType {
+	case types.EventUpdated:
+		if field, value, ok := ParseValue(e.OldValue); ok {
+			setFieldValue(issue, field, value)
+		}
+	case types.EventStatusChanged:
+		if e.OldValue != nil {
+			issue.Status = types.Status(*e.OldValue)
+		}
+	case types.EventClosed:
+		issue.Status = types.StatusOpen
+		if e.OldValue != nil {
+			issue.Status = types.Status(*e.OldValue)
+		}
+		issue.ClosedAt = nil
+		issue.CloseReason = ""
+	case types.EventReopened:
+		issue.Status = types.StatusClosed
+		issue.CloseReason = ""
+		if e.OldValue != nil {
+			issue.CloseReason = *e.OldValue
+		}
+		closedAt := lastClosedAt(earlier, e.CreatedAt)
+		issue.ClosedAt = &closedAt
+	case types.EventLabelAdded:
+		if e.NewValue != nil {
+			issue.Labels = slices.DeleteFunc(issue.Labels, func(l string) bool { return l == *e.NewValue })
+		}
+	case types.EventLabelRemoved:
+		if e.OldValue != nil && !slices.Contains(issue.Labels, *e.OldValue) {
+			issue.Labels = append(issue.Labels, *e.OldValue)
+			slices.Sort(issue.Labels)
+		}
+	case types.EventFieldChanged:
+		if key, value, ok := ParseValue(e.OldValue); ok {
+			issue.Fields[key] = value
+		} else if key, _, ok := ParseValue(e.NewValue); ok {
+			delete(issue.Fields, key)
+		}
+	case types.EventMoved:
+		if id, projectID := ParseMoveValue(e.OldValue); id != "" {
+			issue.ID = id
+			if projectID != "" {
+				issue.ProjectID = projectID
+			}
+		}
+	}
+}
+
+// lastClosedAt returns when the issue was last closed according to
+// events, or fallback when no close was recorded.
+func lastClosedAt(events []*types.Event, fallback time.Time) time.Time {
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].EventType == types.EventClosed {
+			return events[i].CreatedAt
+		}
+	}
+	return fallback
+}
+
+// changesIssue reports whether an event type records a change to the
+// issue row itself, and so moves its updated_at.
+func changesIssue(et types.EventType) bool {
+	switch et {
+	case types.EventUpdated, types.EventStatusChanged, types.EventClosed,
+		types.EventReopened, types.EventFieldChanged, types.EventMoved:
+		return true
+	}
+	return false
+}
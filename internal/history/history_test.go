@@ -0,0 +1,136 @@
+package history_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/history"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func ptr(s string) *string { return &s }
+
+func TestChanges(t *testing.T) {
+	issue := &types.Issue{Title: "Old", Priority: 2, Rank: 0, IssueType: types.TypeTask}
+	got := history.Changes(issue, map[string]any{
+		"rank": 4, "title": "New", "priority": 2, "status": "closed", "issue_type": "bug",
+	})
+	want := []history.Change{
+		{Field: "title", Old: "Old", New: "New"},
+		{Field: "issue_type", Old: "task", New: "bug"},
+		{Field: "rank", Old: "0", New: "4"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Changes = %+v, want %+v", got, want)
+	}
+
+	oldValue, newValue := want[0].EventValues()
+	if *oldValue != "title=Old" || *newValue != "title=New" {
+		t.Errorf("EventValues = %q, %q", *oldValue, *newValue)
+	}
+}
+
+func TestAtUndoesLabelsAndFields(t *testing.T) {
+	base := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+
+	current := &types.Issue{
+		ID: "arc-1", Title: "Now", Status: types.StatusOpen, CreatedAt: base,
+		Labels: []string{"bug"}, Fields: map[string]string{"team": "core"},
+	}
+	events := []*types.Event{
+		{ID: 1, EventType: types.EventCreated, NewValue: ptr("Then"), CreatedAt: base},
+		{ID: 2, EventType: types.EventLabelAdded, NewValue: ptr("ui"), CreatedAt: at(1)},
+		{ID: 3, EventType: types.EventFieldChanged, NewValue: ptr("team=web"), CreatedAt: at(2)},
+		{ID: 4, EventType: types.EventUpdated, OldValue: ptr("title=Then"), NewValue: ptr("title=Now"), CreatedAt: at(3)},
+		{ID: 5, EventType: types.EventLabelAdded, NewValue: ptr("bug"), CreatedAt: at(4)},
+		{ID: 6, EventType: types.EventLabelRemoved, OldValue: ptr("ui"), CreatedAt: at(5)},
+		{ID: 7, EventType: types.EventFieldChanged, OldValue: ptr("team=web"), NewValue: ptr("team=core"), CreatedAt: at(6)},
+	}
+
+	tests := []struct {
+		at        time.Time
+		title     string
+		labels    []string
+		team      string
+		updatedAt time.Time
+	}{
+		{base, "Then", nil, "", base},
+		{at(2), "Then", []string{"ui"}, "web", at(2)},
+		{at(4), "Now", []string{"bug", "ui"}, "web", at(3)},
+		{at(10), "Now", []string{"bug"}, "core", at(6)},
+	}
+	for _, tt := range tests {
+		got, err := history.At(current, events, tt.at)
+		if err != nil {
+			t.Fatalf("At(%v) failed: %v", tt.at, err)
+		}
+		if got.Title != tt.title || !slices.Equal(got.Labels, tt.labels) || got.Fields["team"] != tt.team {
+			t.Errorf("At(%v) = %q %v %v, want %q %v team=%q",
+				tt.at, got.Title, got.Labels, got.Fields, tt.title, tt.labels, tt.team)
+		}
+		if !got.UpdatedAt.Equal(tt.updatedAt) {
+			t.Errorf("At(%v).UpdatedAt = %v, want %v", tt.at, got.UpdatedAt, tt.updatedAt)
+		}
+	}
+	if current.Title != "Now" || !slices.Equal(current.Labels, []string{"bug"}) || current.Fields["team"] != "core" {
+		t.Errorf("At modified the current issue: %+v", current)
+	}
+}
+
+func TestAtUndoesMoves(t *testing.T) {
+	base := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+
+	current := &types.Issue{ID: "web.b2", ProjectID: "proj-web", Title: "Now", CreatedAt: base}
+	events := []*types.Event{
+		{ID: 1, EventType: types.EventCreated, NewValue: ptr("Then"), CreatedAt: base},
+		{ID: 2, EventType: types.EventMoved, OldValue: history.MoveValue("arc.a1", "proj-arc"),
+			NewValue: history.MoveValue("web.b2", "proj-web"), CreatedAt: at(1)},
+		{ID: 3, EventType: types.EventUpdated, OldValue: ptr("title=Then"), NewValue: ptr("title=Now"), CreatedAt: at(2)},
+		// Moves recorded before they carried the project only restore the ID.
+		{ID: 4, EventType: types.EventMoved, OldValue: ptr("web.b2"), NewValue: ptr("web.b2"), CreatedAt: at(3)},
+	}
+
+	tests := []struct {
+		at                 time.Time
+		id, project, title string
+		updatedAt          time.Time
+	}{
+		{base, "arc.a1", "proj-arc", "Then", base},
+		{at(1), "web.b2", "proj-web", "Then", at(1)},
+		{at(2), "web.b2", "proj-web", "Now", at(2)},
+	}
+	for _, tt := range tests {
+		got, err := history.At(current, events, tt.at)
+		if err != nil {
+			t.Fatalf("At(%v) failed: %v", tt.at, err)
+		}
+		if got.ID != tt.id || got.ProjectID != tt.project || got.Title != tt.title {
+			t.Errorf("At(%v) = %s in %s %q, want %s in %s %q",
+				tt.at, got.ID, got.ProjectID, got.Title, tt.id, tt.project, tt.title)
+		}
+		if !got.UpdatedAt.Equal(tt.updatedAt) {
+			t.Errorf("At(%v).UpdatedAt = %v, want %v", tt.at, got.UpdatedAt, tt.updatedAt)
+		}
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	got := history.DiffLines("intro\nold step\nend\n", "intro\nnew step\nextra\nend")
+	want := []history.Line{
+		{Op: history.LineSame, Text: "intro"},
+		{Op: history.LineRemoved, Text: "old step"},
+		{Op: history.LineAdded, Text: "new step"},
+		{Op: history.LineAdded, Text: "extra"},
+		{Op: history.LineSame, Text: "end"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("DiffLines = %+v, want %+v", got, want)
+	}
+
+	if got := history.DiffLines("", "only"); !slices.Equal(got, []history.Line{{Op: history.LineAdded, Text: "only"}}) {
+		t.Errorf("DiffLines from empty = %+v", got)
+	}
+}
@@ -0,0 +1,403 @@
+// Package issuequery parses the compact query syntax used to list issues,
+// such as
+//
+//	status:open,in_progress label:area:api -label:wontfix priority<=1 updated>7d sort:-priority
+//
+// into a types.IssueFilter.
+//
+// A query is a list of whitespace-separated terms that must all match.
+// The word OR splits it into groups, any one of which may match. Terms:
+//
+//	status:a,b    status is a or b        -status:a,b    status is neither
+//	type:a,b      issue type is a or b    -type:a,b      issue type is neither
+//	priority:0,1  priority is 0 or 1      -priority:4    priority is not 4
+//	priority<=1   also <, >, >=
+//	label:x       carries label x         -label:x       does not carry label x
+//	parent:id     child of issue id
+//	field.k:v     custom field k is v
+//	created>7d    created in the last 7 days; also <, <=, >=
+//	updated<2026-03-01  updated before that day
+//	sort:-priority,updated  order by priority descending, then updated
+//	anything else must appear in the title or description
+//
+// Relative times (30m, 24h, 7d, 2w) name the moment that long ago, so
+// updated>7d reads "updated after a week ago". Values may be double-quoted
+// to include spaces. Sort terms apply to the whole query, whichever group
+// they appear in.
+package issuequery
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// ConfigPrefix prefixes the project config keys that hold saved queries.
+// The key "query.triage" holds the query run by "@triage".
+const ConfigPrefix = "query."
+
+// orKeyword separates alternative groups of terms.
+const orKeyword = "OR"
+
+// comparison operators, longest first so "<=" is not read as "<".
+var operators = []string{"<=", ">=", ":", "<", ">", "="}
+
+// Parse parses a query into a filter. Relative times are measured back from
+// now. A query with OR keeps each group in the filter's Or; otherwise the
+// conditions are set on the filter itself.
+func Parse(input string, now time.Time) (types.IssueFilter, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return types.IssueFilter{}, err
+	}
+
+	var (
+		top    types.IssueFilter
+		groups []types.IssueFilter
+		group  types.IssueFilter
+		terms  int
+		text   []string
+	)
+	endGroup := func() error {
+		if terms == 0 {
+			return errors.New("OR needs terms on both sides")
+		}
+		group.Text = text
+		groups = append(groups, group)
+		group, terms, text = types.IssueFilter{}, 0, nil
+		return nil
+	}
+
+	for _, tok := range tokens {
+		switch {
+		case tok.word == orKeyword && !tok.quoted:
+			if err := endGroup(); err != nil {
+				return types.IssueFilter{}, err
+			}
+		case tok.quoted:
+			text = append(text, tok.word)
+			terms++
+		default:
+			isTerm, err := applyTerm(&top, &group, tok.word, now)
+			if err != nil {
+				return types.IssueFilter{}, err
+			}
+			if !isTerm {
+				text = append(text, tok.word)
+			}
+			terms++
+		}
+	}
+	if terms == 0 && len(groups) == 0 {
+		return top, nil
+	}
+	if err := endGroup(); err != nil {
+		return types.IssueFilter{}, err
+	}
+
+	if len(groups) == 1 {
+		groups[0].Sort = top.Sort
+		return groups[0], nil
+	}
+	top.Or = groups
+	return top, nil
+}
+
+// IsText reports whether f holds nothing but free text, as a plain search
+// query parses to.
+func IsText(f types.IssueFilter) bool {
+	text := f.Text
+	f.Text = nil
+	return len(text) > 0 && isZero(f)
+}
+
+// isZero reports whether f sets no condition, order or group.
+func isZero(f types.IssueFilter) bool {
+	return f.ProjectID == "" && len(f.Statuses) == 0 && len(f.Priorities) == 0 &&
+		len(f.IssueTypes) == 0 && f.AISessionID == nil && len(f.Labels) == 0 &&
+		f.ParentID == "" && f.Query == "" && len(f.IDs) == 0 && len(f.Fields) == 0 &&
+		len(f.ExcludeStatuses) == 0 && len(f.ExcludePriorities) == 0 &&
+		len(f.ExcludeIssueTypes) == 0 && len(f.ExcludeLabels) == 0 &&
+		f.MinPriority == nil && f.MaxPriority == nil &&
+		f.CreatedAfter == nil && f.CreatedBefore == nil &&
+		f.UpdatedAfter == nil && f.UpdatedBefore == nil &&
+		len(f.Text) == 0 && len(f.Or) == 0 && len(f.Sort) == 0
+}
+
+// Merge narrows base by a parsed query: the query's conditions become
+// base's Or groups (a single group when it has no OR) and its sort order
+// replaces base's.
+func Merge(base, query types.IssueFilter) types.IssueFilter {
+	if len(query.Sort) > 0 {
+		base.Sort = query.Sort
+	}
+	query.Sort = nil
+	switch {
+	case len(query.Or) > 0:
+		base.Or = query.Or
+	case !isZero(query):
+		base.Or = []types.IssueFilter{query}
+	}
+	return base
+}
+
+// Expand resolves a reference to a saved query. A query starting with
+// @name is replaced by the query saved under that name, followed by any
+// further terms; a saved query containing OR cannot take further terms.
+// Other queries are returned unchanged.
+func Expand(input string, saved map[string]string) (string, error) {
+	input = strings.TrimSpace(input)
+	ref, ok := strings.CutPrefix(input, "@")
+	if !ok {
+		return input, nil
+	}
+	name, rest, _ := strings.Cut(ref, " ")
+	query, ok := saved[ConfigPrefix+name]
+	if !ok {
+		return "", fmt.Errorf("no saved query named %q", name)
+	}
+	rest = strings.TrimSpace(rest)
+	if rest == "" {
+		return query, nil
+	}
+	if tokens, _ := tokenize(query); containsOr(tokens) {
+		return "", fmt.Errorf("saved query %q uses OR and cannot be combined with other terms", name)
+	}
+	return query + " " + rest, nil
+}
+
+// ValidName reports whether name can name a saved query.
+func ValidName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+type token struct {
+	word   string
+	quoted bool
+}
+
+func containsOr(tokens []token) bool {
+	for _, tok := range tokens {
+		if tok.word == orKeyword && !tok.quoted {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenize splits a query on whitespace. Double quotes group text with
+// spaces; a token that opens with a quote is always text, while a quoted
+// value after an operator stays part of its term.
+func tokenize(input string) ([]token, error) {
+	var (
+		tokens  []token
+		cur     strings.Builder
+		inQuote bool
+		quoted  bool
+	)
+	flush := func() {
+		if cur.Len() > 0 {
+			tokens = append(tokens, token{word: cur.String(), quoted: quoted})
+		}
+		cur.Reset()
+		quoted = false
+	}
+	for _, r := range input {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			if cur.Len() == 0 {
+				quoted = true
+			}
+		case !inQuote && (r == ' ' || r == '\t' || r == '\n'):
+			flush()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if inQuote {
+		return nil, errors.New("unterminated quote")
+	}
+	flush()
+	return tokens, nil
+}
+
+// applyTerm applies one key/value term to group, or a sort term to top.
+// It reports false for words that are not terms, which are text.
+func applyTerm(top, group *types.IssueFilter, word string, now time.Time) (bool, error) {
+	negate := false
+	body := word
+	if rest, ok := strings.CutPrefix(word, "-"); ok && len(rest) > 0 {
+		negate, body = true, rest
+	}
+
+	key, op, value := splitTerm(body)
+	if op == "" {
+		return false, nil
+	}
+	if value == "" {
+		return true, fmt.Errorf("%s: missing value", word)
+	}
+	if negate && op != ":" && op != "=" {
+		return true, fmt.Errorf("%s: only key:value terms can be negated", word)
+	}
+	isEq := op == ":" || op == "="
+
+	switch {
+	case key == "status" && isEq:
+		for _, v := range strings.Split(value, ",") {
+			st := types.Status(v)
+			if !st.IsValid() {
+				return true, fmt.Errorf("%s: invalid status %q", word, v)
+			}
+			if negate {
+				group.ExcludeStatuses = append(group.ExcludeStatuses, st)
+			} else {
+				group.Statuses = append(group.Statuses, st)
+			}
+		}
+	case key == "type" && isEq:
+		for _, v := range strings.Split(value, ",") {
+			t := types.IssueType(v)
+			if !t.IsValid() {
+				return true, fmt.Errorf("%s: invalid type %q", word, v)
+			}
+			if negate {
+				group.ExcludeIssueTypes = append(group.ExcludeIssueTypes, t)
+			} else {
+				group.IssueTypes = append(group.IssueTypes, t)
+			}
+		}
+	case key == "priority":
+		return true, applyPriority(group, word, op, value, negate)
+	case key == "label" && isEq:
+		if negate {
+			group.ExcludeLabels = append(group.ExcludeLabels, value)
+		} else {
+			group.Labels = append(group.Labels, value)
+		}
+	case key == "parent" && isEq && !negate:
+		group.ParentID = value
+	case strings.HasPrefix(key, types.FieldConfigPrefix) && isEq && !negate:
+		if group.Fields == nil {
+			group.Fields = make(map[string]string)
+		}
+		group.Fields[strings.TrimPrefix(key, types.FieldConfigPrefix)] = value
+	case (key == "created" || key == "updated") && !isEq:
+		t, err := parseTime(value, now)
+		if err != nil {
+			return true, fmt.Errorf("%s: %w", word, err)
+		}
+		after, before := &group.CreatedAfter, &group.CreatedBefore
+		if key == "updated" {
+			after, before = &group.UpdatedAfter, &group.UpdatedBefore
+		}
+		if op == ">" || op == ">=" {
+			*after = &t
+		} else {
+			*before = &t
+		}
+	case key == "sort" && isEq && !negate:
+		for _, v := range strings.Split(value, ",") {
+			var s types.IssueSort
+			name, desc := strings.CutPrefix(v, "-")
+			s.Field, s.Desc = types.IssueSortField(name), desc
+			if !s.Field.IsValid() {
+				return true, fmt.Errorf("%s: cannot sort by %q", word, name)
+			}
+			top.Sort = append(top.Sort, s)
+		}
+	default:
+		return true, fmt.Errorf("unknown term %q", word)
+	}
+	return true, nil
+}
+
+// splitTerm splits key<op>value at the first operator. Words without one,
+// or starting with one, are not terms.
+func splitTerm(word string) (key, op, value string) {
+	best := -1
+	for _, o := range operators {
+		if i := strings.Index(word, o); i > 0 && (best < 0 || i < best || i == best && len(o) > len(op)) {
+			best, op = i, o
+		}
+	}
+	if best < 0 {
+		return "", "", ""
+	}
+	return word[:best], op, word[best+len(op):]
+}
+
+// applyPriority applies a priority term.
+func applyPriority(group *types.IssueFilter, word, op, value string, negate bool) error {
+	var priorities []int
+	for _, v := range strings.Split(value, ",") {
+		p, err := strconv.Atoi(v)
+		if err != nil || p < 0 || p > 4 {
+			return fmt.Errorf("%s: invalid priority %q (want 0-4)", word, v)
+		}
+		priorities = append(priorities, p)
+	}
+	if op != ":" && op != "=" {
+		if len(priorities) != 1 {
+			return fmt.Errorf("%s: compare against a single priority", word)
+		}
+		p := priorities[0]
+		switch op {
+		case "<":
+			p--
+			group.MaxPriority = &p
+		case "<=":
+			group.MaxPriority = &p
+		case ">":
+			p++
+			group.MinPriority = &p
+		case ">=":
+			group.MinPriority = &p
+		}
+		return nil
+	}
+	if negate {
+		group.ExcludePriorities = append(group.ExcludePriorities, priorities...)
+	} else {
+		group.Priorities = append(group.Priorities, priorities...)
+	}
+	return nil
+}
+
+// relativeUnits maps the suffix of a relative time to its duration.
+var relativeUnits = map[byte]time.Duration{
+	'm': time.Minute,
+	'h': time.Hour,
+	'd': 24 * time.Hour,
+	'w': 7 * 24 * time.Hour,
+}
+
+// parseTime parses a relative time (7d), a date (2026-03-01, midnight UTC)
+// or an RFC 3339 timestamp.
+func parseTime(value string, now time.Time) (time.Time, error) {
+	if unit, ok := relativeUnits[value[len(value)-1]]; ok {
+		if n, err := strconv.Atoi(value[:len(value)-1]); err == nil && n >= 0 {
+			return now.Add(-time.Duration(n) * unit).UTC(), nil
+		}
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (want e.g. 7d, 24h, 2026-03-01)", value)
+}
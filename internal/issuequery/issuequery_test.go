@@ -0,0 +1,163 @@
+package issuequery_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/issuequery"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func TestParse(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	weekAgo := now.Add(-7 * 24 * time.Hour)
+	march := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	one, two := 1, 2
+
+	tests := []struct {
+		query string
+		want  types.IssueFilter
+	}{
+		{"", types.IssueFilter{}},
+		{
+			"status:open,in_progress label:area:api -label:wontfix priority<=1 updated>7d type:bug sort:-priority",
+			types.IssueFilter{
+				Statuses:      []types.Status{types.StatusOpen, types.StatusInProgress},
+				Labels:        []string{"area:api"},
+				ExcludeLabels: []string{"wontfix"},
+				MaxPriority:   &one,
+				UpdatedAfter:  &weekAgo,
+				IssueTypes:    []types.IssueType{types.TypeBug},
+				Sort:          []types.IssueSort{{Field: types.SortByPriority, Desc: true}},
+			},
+		},
+		{
+			"-status:closed -type:chore -priority:3,4 priority>1 created<2026-03-01",
+			types.IssueFilter{
+				ExcludeStatuses:   []types.Status{types.StatusClosed},
+				ExcludeIssueTypes: []types.IssueType{types.TypeChore},
+				ExcludePriorities: []int{3, 4},
+				MinPriority:       &two,
+				CreatedBefore:     &march,
+			},
+		},
+		{
+			`parent:ar.x1 field.component:ui login "save button" sort:title,-updated`,
+			types.IssueFilter{
+				ParentID: "ar.x1",
+				Fields:   map[string]string{"component": "ui"},
+				Text:     []string{"login", "save button"},
+				Sort:     []types.IssueSort{{Field: types.SortByTitle}, {Field: types.SortByUpdated, Desc: true}},
+			},
+		},
+		{
+			"label:urgent OR priority:0 sort:created",
+			types.IssueFilter{
+				Or: []types.IssueFilter{
+					{Labels: []string{"urgent"}},
+					{Priorities: []int{0}},
+				},
+				Sort: []types.IssueSort{{Field: types.SortByCreated}},
+			},
+		},
+		{`"status:open" "OR"`, types.IssueFilter{Text: []string{"status:open", "OR"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := issuequery.Parse(tt.query, now)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, query := range []string{
+		"status:bogus",
+		"type:story",
+		"priority:9",
+		"priority<1,2",
+		"-priority<2",
+		"updated>soon",
+		"sort:color",
+		"colour:red",
+		"label:",
+		"OR status:open",
+		"status:open OR",
+		`"unterminated`,
+	} {
+		if _, err := issuequery.Parse(query, time.Now()); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", query)
+		}
+	}
+}
+
+func TestIsText(t *testing.T) {
+	for query, want := range map[string]bool{
+		"login redirect":        true,
+		"login status:open":     false,
+		"sort:title":            false,
+		"login OR logout":       false,
+		"":                      false,
+		`"exact phrase" search`: true,
+	} {
+		f, err := issuequery.Parse(query, time.Now())
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", query, err)
+		}
+		if got := issuequery.IsText(f); got != want {
+			t.Errorf("IsText(%q) = %v, want %v", query, got, want)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	base := types.IssueFilter{ProjectID: "proj", Statuses: []types.Status{types.StatusOpen}, Limit: 10}
+
+	got := issuequery.Merge(base, types.IssueFilter{
+		Labels: []string{"api"},
+		Sort:   []types.IssueSort{{Field: types.SortByID}},
+	})
+	want := base
+	want.Or = []types.IssueFilter{{Labels: []string{"api"}}}
+	want.Sort = []types.IssueSort{{Field: types.SortByID}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge(single group) = %+v, want %+v", got, want)
+	}
+
+	groups := []types.IssueFilter{{Labels: []string{"a"}}, {Labels: []string{"b"}}}
+	if got := issuequery.Merge(base, types.IssueFilter{Or: groups}); !reflect.DeepEqual(got.Or, groups) {
+		t.Errorf("Merge(OR groups).Or = %+v, want %+v", got.Or, groups)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	saved := map[string]string{
+		"query.triage": "status:open -label:wontfix",
+		"query.hot":    "priority:0 OR label:urgent",
+		"field.size":   "number",
+	}
+	tests := []struct {
+		input, want string
+		wantErr     bool
+	}{
+		{"status:open", "status:open", false},
+		{"@triage", "status:open -label:wontfix", false},
+		{" @triage label:api ", "status:open -label:wontfix label:api", false},
+		{"@hot", "priority:0 OR label:urgent", false},
+		{"@hot type:bug", "", true},
+		{"@size", "", true},
+		{"@missing", "", true},
+	}
+	for _, tt := range tests {
+		got, err := issuequery.Expand(tt.input, saved)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("Expand(%q) = %q, %v; want %q, error %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
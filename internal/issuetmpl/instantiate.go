@@ -0,0 +1,102 @@
+package issuetmpl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/storage"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// Options controls an instantiation.
+type Options struct {
+	ProjectID string
+	Name      string            // template name, reported in the result
+	Vars      map[string]string // override the template's defaults
+	// ParentID, when set, makes the root issue a child of this issue.
+	ParentID string
+	Actor    string
+	// Now dates the instance for {{date}}; zero means time.Now.
+	Now time.Time
+}
+
+// Instantiate renders a template and creates its issues. Every issue below
+// the root is a hierarchical child of the issue above it (e.g.
+// arc-a3f8e9.2). Sibling depends_on entries become blocks dependencies.
+func Instantiate(
+	ctx context.Context, store storage.Storage, t *Template, opts Options,
+) (*types.TemplateInstance, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	root, err := t.Render(opts.Vars, now)
+	if err != nil {
+		return nil, err
+	}
+
+	inst := &types.TemplateInstance{Template: opts.Name, IssueIDs: []string{}}
+	inst.RootID, err = createNode(ctx, store, opts.ProjectID, root, opts.ParentID, opts.Actor, inst)
+	if err != nil {
+		return inst, err
+	}
+	return inst, nil
+}
+
+// createNode creates n and its subtree under parentID and returns n's ID.
+// Created IDs are appended to inst as they are made, so a partial tree is
+// reported if a later issue fails.
+func createNode(
+	ctx context.Context, store storage.Storage, projectID string, n *Node, parentID, actor string,
+	inst *types.TemplateInstance,
+) (string, error) {
+	issue := &types.Issue{
+		ProjectID:   projectID,
+		Title:       n.Title,
+		Description: n.Description,
+		IssueType:   types.IssueType(n.Type),
+		ParentID:    parentID,
+	}
+	if n.Priority != nil {
+		issue.Priority = *n.Priority
+	}
+	if err := store.CreateIssue(ctx, issue, actor); err != nil {
+		return "", fmt.Errorf("create %q: %w", n.Title, err)
+	}
+	inst.IssueIDs = append(inst.IssueIDs, issue.ID)
+
+	// CreateIssue treats priority 0 as unset.
+	if n.Priority != nil && *n.Priority == 0 {
+		if err := store.UpdateIssue(ctx, issue.ID, map[string]any{"priority": 0}, 0, actor); err != nil {
+			return "", fmt.Errorf("set priority of %s: %w", issue.ID, err)
+		}
+	}
+	for _, label := range n.Labels {
+		if err := store.AddLabelToIssue(ctx, issue.ID, label, actor); err != nil {
+			return "", err
+		}
+	}
+
+	byKey := make(map[string]string, len(n.Children))
+	for _, child := range n.Children {
+		id, err := createNode(ctx, store, projectID, child, issue.ID, actor, inst)
+		if err != nil {
+			return "", err
+		}
+		if child.Key != "" {
+			byKey[child.Key] = id
+		}
+	}
+	for _, child := range n.Children {
+		for _, dep := range child.DependsOn {
+			err := store.AddDependency(ctx, &types.Dependency{
+				IssueID: byKey[child.Key], DependsOnID: byKey[dep], Type: types.DepBlocks,
+			}, actor)
+			if err != nil {
+				return "", fmt.Errorf("add dependency %s -> %s: %w", byKey[child.Key], byKey[dep], err)
+			}
+		}
+	}
+	return issue.ID, nil
+}
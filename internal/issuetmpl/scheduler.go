@@ -0,0 +1,120 @@
+package issuetmpl
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/storage"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// Scheduler defaults.
+const (
+	DefaultPollInterval = time.Minute
+
+	// SchedulerActor is recorded as the creator of scheduled issues.
+	SchedulerActor = "arc-scheduler"
+)
+
+// NextRun returns when a template with the given recurrence should next be
+// instantiated after now, or nil when it does not recur.
+func NextRun(recurrence string, now time.Time) (*time.Time, error) {
+	if recurrence == "" {
+		return nil, nil //nolint:nilnil // no recurrence means no next run
+	}
+	sched, err := ParseSchedule(recurrence)
+	if err != nil {
+		return nil, err
+	}
+	next := sched.Next(now)
+	if next.IsZero() {
+		return nil, nil //nolint:nilnil // the schedule never matches again
+	}
+	return &next, nil
+}
+
+// Scheduler materializes recurring templates when their next run comes due.
+type Scheduler struct {
+	store        storage.Storage
+	pollInterval time.Duration
+	now          func() time.Time
+}
+
+// NewScheduler creates a Scheduler backed by store. A zero pollInterval
+// selects DefaultPollInterval; now may be nil to use time.Now.
+func NewScheduler(store storage.Storage, pollInterval time.Duration, now func() time.Time) *Scheduler {
+	if pollInterval <= 0 {
+		pollInterval = DefaultPollInterval
+	}
+	if now == nil {
+		now = time.Now
+	}
+	return &Scheduler{store: store, pollInterval: pollInterval, now: now}
+}
+
+// Run instantiates due templates until ctx is cancelled. Runs missed while
+// the server was down are made up once, on the first pass.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.RunDue(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("templates: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue instantiates every template whose next run has come and schedules
+// its following run. A template that fails to instantiate is logged and
+// still rescheduled, so a broken template does not fire on every pass.
+func (s *Scheduler) RunDue(ctx context.Context) ([]*types.TemplateInstance, error) {
+	now := s.now()
+	due, err := s.store.ListDueIssueTemplates(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	instances := []*types.TemplateInstance{}
+	for _, stored := range due {
+		if inst, err := s.runTemplate(ctx, stored, now); err != nil {
+			log.Printf("templates: %s/%s: %v", stored.ProjectID, stored.Name, err)
+		} else {
+			instances = append(instances, inst)
+		}
+
+		next, err := NextRun(stored.Recurrence, now)
+		if err != nil {
+			log.Printf("templates: %s/%s: %v", stored.ProjectID, stored.Name, err)
+		}
+		if err := s.store.RecordIssueTemplateRun(ctx, stored.ProjectID, stored.Name, now, next); err != nil {
+			return instances, err
+		}
+	}
+	return instances, nil
+}
+
+func (s *Scheduler) runTemplate(
+	ctx context.Context, stored *types.IssueTemplate, now time.Time,
+) (*types.TemplateInstance, error) {
+	t, err := Parse([]byte(stored.Content))
+	if err != nil {
+		return nil, err
+	}
+	inst, err := Instantiate(ctx, s.store, t, Options{
+		ProjectID: stored.ProjectID,
+		Name:      stored.Name,
+		Actor:     SchedulerActor,
+		Now:       now,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("instantiate: %w", err)
+	}
+	return inst, nil
+}
@@ -0,0 +1,229 @@
+// Package issuetmpl defines issue templates: YAML descriptions of a tree of
+// issues that can be instantiated on demand or on a recurring schedule.
+//
+// A template has one root issue whose children may have children of their
+// own. Sibling issues can block each other through depends_on, which names
+// other siblings by key. Titles, descriptions, and labels may reference
+// variables as {{name}}; values come from the caller, the template's vars
+// defaults, and the built-in {{date}}.
+//
+//	description: Release checklist
+//	vars:
+//	  version: "0.0"
+//	recurrence: "0 9 * * 1"
+//	issue:
+//	  title: Release {{version}}
+//	  type: epic
+//	  children:
+//	    - key: freeze
+//	      title: Code freeze for {{version}}
+//	    - key: tag
+//	      title: Tag v{{version}}
+//	      depends_on: [freeze]
+package issuetmpl
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// Dir is where templates are looked up relative to the working directory,
+// as <name>.yaml or <name>.yml.
+const Dir = ".arc/templates"
+
+// dateVar is the built-in variable holding the instantiation date.
+const dateVar = "date"
+
+var (
+	namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+	varPattern  = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_.-]*)\s*\}\}`)
+)
+
+// Template is a parsed issue template.
+type Template struct {
+	Description string            `yaml:"description,omitempty"`
+	Vars        map[string]string `yaml:"vars,omitempty"`
+	// Recurrence is a cron expression; see ParseSchedule.
+	Recurrence string `yaml:"recurrence,omitempty"`
+	Issue      *Node  `yaml:"issue"`
+}
+
+// Node is one issue in a template tree.
+type Node struct {
+	// Key names the issue for depends_on references between siblings.
+	Key         string   `yaml:"key,omitempty"`
+	Title       string   `yaml:"title"`
+	Description string   `yaml:"description,omitempty"`
+	Type        string   `yaml:"type,omitempty"`
+	Priority    *int     `yaml:"priority,omitempty"`
+	Labels      []string `yaml:"labels,omitempty"`
+	// DependsOn lists keys of siblings that block this issue.
+	DependsOn []string `yaml:"depends_on,omitempty"`
+	Children  []*Node  `yaml:"children,omitempty"`
+}
+
+// ValidName reports whether name can name a template.
+func ValidName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+// Parse reads and validates a template.
+func Parse(data []byte) (*Template, error) {
+	var t Template
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&t); err != nil {
+		return nil, fmt.Errorf("parse template: %w", err)
+	}
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Validate checks the issue tree and the recurrence.
+func (t *Template) Validate() error {
+	if t.Issue == nil {
+		return errors.New("template has no issue")
+	}
+	if t.Recurrence != "" {
+		if _, err := ParseSchedule(t.Recurrence); err != nil {
+			return err
+		}
+	}
+	if len(t.Issue.DependsOn) > 0 {
+		return errors.New("issue: the root issue has no siblings to depend on")
+	}
+	return validateNode(t.Issue, "issue")
+}
+
+func validateNode(n *Node, path string) error {
+	if strings.TrimSpace(n.Title) == "" {
+		return fmt.Errorf("%s: title is required", path)
+	}
+	if n.Type != "" && !types.IssueType(n.Type).IsValid() {
+		return fmt.Errorf("%s: invalid type %q", path, n.Type)
+	}
+	if n.Priority != nil && (*n.Priority < 0 || *n.Priority > 4) {
+		return fmt.Errorf("%s: priority must be between 0 and 4", path)
+	}
+
+	keys := make(map[string]*Node, len(n.Children))
+	for i, child := range n.Children {
+		if child.Key == "" {
+			continue
+		}
+		if keys[child.Key] != nil {
+			return fmt.Errorf("%s.children[%d]: duplicate key %q", path, i, child.Key)
+		}
+		keys[child.Key] = child
+	}
+	for i, child := range n.Children {
+		childPath := fmt.Sprintf("%s.children[%d]", path, i)
+		for _, dep := range child.DependsOn {
+			if keys[dep] == nil || keys[dep] == child {
+				return fmt.Errorf("%s: depends_on %q is not a sibling key", childPath, dep)
+			}
+		}
+		if err := validateNode(child, childPath); err != nil {
+			return err
+		}
+	}
+	if err := checkSiblingCycles(n.Children, keys); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// checkSiblingCycles rejects depends_on chains that loop back on
+// themselves.
+func checkSiblingCycles(children []*Node, keys map[string]*Node) error {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int, len(keys))
+	var visit func(key string) error
+	visit = func(key string) error {
+		switch state[key] {
+		case visiting:
+			return fmt.Errorf("depends_on cycle through %q", key)
+		case done:
+			return nil
+		}
+		state[key] = visiting
+		for _, dep := range keys[key].DependsOn {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[key] = done
+		return nil
+	}
+	for _, child := range children {
+		if child.Key != "" {
+			if err := visit(child.Key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Render returns a copy of the issue tree with variables substituted.
+// vars take precedence over the template's defaults; {{date}} is the date
+// of now. Referencing a variable that has no value is an error.
+func (t *Template) Render(vars map[string]string, now time.Time) (*Node, error) {
+	values := map[string]string{dateVar: now.Format(time.DateOnly)}
+	maps.Copy(values, t.Vars)
+	maps.Copy(values, vars)
+
+	var missing []string
+	expand := func(s string) string {
+		return varPattern.ReplaceAllStringFunc(s, func(m string) string {
+			name := varPattern.FindStringSubmatch(m)[1]
+			v, ok := values[name]
+			if !ok {
+				if !slices.Contains(missing, name) {
+					missing = append(missing, name)
+				}
+				return m
+			}
+			return v
+		})
+	}
+
+	root := renderNode(t.Issue, expand)
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing template variables: %s", strings.Join(missing, ", "))
+	}
+	return root, nil
+}
+
+func renderNode(n *Node, expand func(string) string) *Node {
+	out := &Node{
+		Key:         n.Key,
+		Title:       expand(n.Title),
+		Description: expand(n.Description),
+		Type:        n.Type,
+		Priority:    n.Priority,
+		DependsOn:   n.DependsOn,
+	}
+	for _, l := range n.Labels {
+		out.Labels = append(out.Labels, expand(l))
+	}
+	for _, child := range n.Children {
+		out.Children = append(out.Children, renderNode(child, expand))
+	}
+	return out
+}
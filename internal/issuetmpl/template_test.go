@@ -0,0 +1,212 @@
+package issuetmpl_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/issuetmpl"
+	"github.com/sentiolabs/arc/internal/storage/sqlite"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+const release = `description: Release checklist
+vars:
+  version: "0.0"
+issue:
+  title: Release {{version}}
+  type: epic
+  priority: 0
+  labels: [release]
+  children:
+    - key: freeze
+      title: Code freeze for {{ version }}
+    - key: tag
+      title: Tag v{{version}}
+      depends_on: [freeze]
+      children:
+        - title: Publish notes for {{version}} on {{date}}
+    - key: announce
+      title: Announce {{version}}
+      type: chore
+      depends_on: [tag, freeze]
+`
+
+func newStore(t *testing.T) (*sqlite.Store, *types.Project) {
+	t.Helper()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	proj := &types.Project{Name: "Releases", Prefix: "rel"}
+	if err := store.CreateProject(context.Background(), proj); err != nil {
+		t.Fatalf("CreateProject: %v", err)
+	}
+	return store, proj
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name, content, want string
+	}{
+		{"no issue", "description: x\n", "no issue"},
+		{"unknown field", "issue:\n  title: x\n  owner: me\n", "field owner not found"},
+		{"missing title", "issue:\n  children:\n    - title: a\n", "issue: title is required"},
+		{"bad type", "issue:\n  title: x\n  type: saga\n", `invalid type "saga"`},
+		{"bad priority", "issue:\n  title: x\n  priority: 7\n", "priority"},
+		{"unknown dep", "issue:\n  title: x\n  children:\n    - title: a\n      depends_on: [b]\n", "not a sibling"},
+		{"duplicate key", "issue:\n  title: x\n  children:\n    - {key: a, title: a}\n    - {key: a, title: b}\n", "duplicate key"},
+		{
+			"cycle",
+			"issue:\n  title: x\n  children:\n    - {key: a, title: a, depends_on: [b]}\n    - {key: b, title: b, depends_on: [a]}\n",
+			"cycle",
+		},
+		{"bad recurrence", "recurrence: every day\nissue:\n  title: x\n", "want 5 fields"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := issuetmpl.Parse([]byte(tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	tmpl, err := issuetmpl.Parse([]byte(release))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	now := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	root, err := tmpl.Render(map[string]string{"version": "1.4"}, now)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if root.Title != "Release 1.4" || root.Children[0].Title != "Code freeze for 1.4" {
+		t.Errorf("root = %q, first child = %q", root.Title, root.Children[0].Title)
+	}
+	if got := root.Children[1].Children[0].Title; got != "Publish notes for 1.4 on 2026-03-04" {
+		t.Errorf("grandchild = %q", got)
+	}
+	if tmpl.Issue.Title != "Release {{version}}" {
+		t.Errorf("Render modified the template: %q", tmpl.Issue.Title)
+	}
+
+	defaults, err := tmpl.Render(nil, now)
+	if err != nil || defaults.Title != "Release 0.0" {
+		t.Errorf("defaults = %+v, %v", defaults, err)
+	}
+
+	bare, _ := issuetmpl.Parse([]byte("issue:\n  title: '{{who}} and {{what}}'\n"))
+	if _, err := bare.Render(nil, now); err == nil || !strings.Contains(err.Error(), "who, what") {
+		t.Errorf("missing vars error = %v", err)
+	}
+}
+
+func TestInstantiate(t *testing.T) {
+	ctx := context.Background()
+	store, proj := newStore(t)
+	tmpl, _ := issuetmpl.Parse([]byte(release))
+
+	inst, err := issuetmpl.Instantiate(ctx, store, tmpl, issuetmpl.Options{
+		ProjectID: proj.ID, Name: "release", Vars: map[string]string{"version": "1.4"}, Actor: "alice",
+	})
+	if err != nil {
+		t.Fatalf("Instantiate: %v", err)
+	}
+	if len(inst.IssueIDs) != 5 {
+		t.Fatalf("created %v, want 5 issues", inst.IssueIDs)
+	}
+
+	root, _ := store.GetIssue(ctx, inst.RootID)
+	if root.Title != "Release 1.4" || root.IssueType != types.TypeEpic || root.Priority != 0 {
+		t.Errorf("root = %+v", root)
+	}
+	if labels, _ := store.GetIssueLabels(ctx, root.ID); len(labels) != 1 || labels[0] != "release" {
+		t.Errorf("root labels = %v", labels)
+	}
+
+	freeze, tag, notes, announce := root.ID+".1", root.ID+".2", root.ID+".2.1", root.ID+".3"
+	want := []string{root.ID, freeze, tag, notes, announce}
+	for i, id := range want {
+		if inst.IssueIDs[i] != id {
+			t.Errorf("IssueIDs[%d] = %s, want %s", i, inst.IssueIDs[i], id)
+		}
+	}
+
+	deps, _ := store.GetDependencies(ctx, announce)
+	blockers := map[string]types.DependencyType{}
+	for _, d := range deps {
+		blockers[d.DependsOnID] = d.Type
+	}
+	if blockers[tag] != types.DepBlocks || blockers[freeze] != types.DepBlocks || blockers[root.ID] != types.DepParentChild {
+		t.Errorf("announce deps = %v", blockers)
+	}
+}
+
+func TestInstantiate_UnderParent(t *testing.T) {
+	ctx := context.Background()
+	store, proj := newStore(t)
+	parent := &types.Issue{ProjectID: proj.ID, Title: "Q3"}
+	if err := store.CreateIssue(ctx, parent, "alice"); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	tmpl, _ := issuetmpl.Parse([]byte("issue:\n  title: Sprint {{date}}\n  children:\n    - title: Retro\n"))
+
+	inst, err := issuetmpl.Instantiate(ctx, store, tmpl, issuetmpl.Options{
+		ProjectID: proj.ID, Name: "sprint", ParentID: parent.ID, Actor: "alice",
+	})
+	if err != nil {
+		t.Fatalf("Instantiate: %v", err)
+	}
+	if inst.RootID != parent.ID+".1" || inst.IssueIDs[1] != parent.ID+".1.1" {
+		t.Errorf("instance = %+v", inst)
+	}
+}
+
+func TestScheduler_RunDue(t *testing.T) {
+	ctx := context.Background()
+	store, proj := newStore(t)
+	now := time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC) // a Monday
+	due := now.Add(-time.Hour)
+	later := now.Add(time.Hour)
+
+	weekly := "recurrence: '0 9 * * 1'\nissue:\n  title: Weekly {{date}}\n"
+	for name, next := range map[string]*time.Time{"weekly": &due, "future": &later, "manual": nil} {
+		err := store.SaveIssueTemplate(ctx, &types.IssueTemplate{
+			ProjectID: proj.ID, Name: name, Content: weekly, Recurrence: "0 9 * * 1", NextRunAt: next,
+		})
+		if err != nil {
+			t.Fatalf("SaveIssueTemplate: %v", err)
+		}
+	}
+
+	sched := issuetmpl.NewScheduler(store, 0, func() time.Time { return now })
+	instances, err := sched.RunDue(ctx)
+	if err != nil {
+		t.Fatalf("RunDue: %v", err)
+	}
+	if len(instances) != 1 || instances[0].Template != "weekly" {
+		t.Fatalf("instances = %+v", instances)
+	}
+	issue, _ := store.GetIssue(ctx, instances[0].RootID)
+	if issue.Title != "Weekly 2026-03-02" {
+		t.Errorf("title = %q", issue.Title)
+	}
+
+	stored, _ := store.GetIssueTemplate(ctx, proj.ID, "weekly")
+	wantNext := time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)
+	if stored.LastRunAt == nil || stored.NextRunAt == nil || !stored.NextRunAt.Equal(wantNext) {
+		t.Errorf("weekly after run: last=%v next=%v, want next %v", stored.LastRunAt, stored.NextRunAt, wantNext)
+	}
+
+	if again, _ := sched.RunDue(ctx); len(again) != 0 {
+		t.Errorf("second pass ran %+v", again)
+	}
+}
@@ -0,0 +1,36 @@
+package mcp
+
+import (
+	"io"
+	"net/http"
+)
+
+// HTTPHandler serves the MCP streamable-HTTP transport in its stateless,
+// JSON-response form: every JSON-RPC message is POSTed and answered in the
+// same HTTP response. Notifications are acknowledged with 202 Accepted.
+// arc never initiates server-to-client messages, so GET (the optional SSE
+// stream) is answered with 405 as the spec permits.
+func (s *Server) HTTPHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxMessageSize))
+		if err != nil {
+			http.Error(w, "read body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		out := s.HandleMessage(r.Context(), body)
+		if out == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(out)
+	})
+}
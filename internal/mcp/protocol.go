@@ -0,0 +1,116 @@
+// Package mcp implements a Model Context Protocol server for arc.
+//
+// The server speaks JSON-RPC 2.0 and exposes arc operations (issues, ready
+// work, dependencies, team context, plans) as MCP tools with typed input
+// schemas. Every tool is backed by the arc HTTP API through a Backend,
+// normally an *internal/client.Client, so the MCP server never touches
+// storage directly. Two transports are provided: newline-delimited stdio
+// (ServeStdio, used by `arc mcp`) and streamable HTTP (HTTPHandler, mounted
+// on the arc server at /mcp).
+package mcp
+
+import "encoding/json"
+
+// ProtocolVersion is the MCP protocol revision this server implements.
+const ProtocolVersion = "2025-06-18"
+
+// jsonRPCVersion is the only JSON-RPC version accepted on the wire.
+const jsonRPCVersion = "2.0"
+
+// Standard JSON-RPC 2.0 error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// request is an incoming JSON-RPC message. A nil ID marks a notification,
+// which never receives a response.
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// isNotification reports whether the message expects no response.
+func (r *request) isNotification() bool {
+	return len(r.ID) == 0
+}
+
+// response is an outgoing JSON-RPC message. Exactly one of Result and
+// Error is set.
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError is the JSON-RPC error object.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// initializeResult is the result of the initialize handshake.
+type initializeResult struct {
+	ProtocolVersion string         `json:"protocolVersion"`
+	Capabilities    map[string]any `json:"capabilities"`
+	ServerInfo      serverInfo     `json:"serverInfo"`
+	Instructions    string         `json:"instructions,omitempty"`
+}
+
+// serverInfo identifies this server to the MCP client.
+type serverInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Tool describes one callable tool as advertised by tools/list.
+type Tool struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	InputSchema Schema `json:"inputSchema"`
+}
+
+// Schema is the JSON Schema subset used for tool inputs.
+type Schema struct {
+	Type       string             `json:"type"`
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+	Items      *Schema            `json:"items,omitempty"`
+	Enum       []string           `json:"enum,omitempty"`
+	Minimum    *int               `json:"minimum,omitempty"`
+	Maximum    *int               `json:"maximum,omitempty"`
+	// Description documents a single property.
+	Description string `json:"description,omitempty"`
+}
+
+// listToolsResult is the result of tools/list.
+type listToolsResult struct {
+	Tools []Tool `json:"tools"`
+}
+
+// callToolParams is the params object of tools/call.
+type callToolParams struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+// content is one block of tool output. arc only emits text blocks.
+type content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// CallToolResult is the result of tools/call. Tool failures are reported
+// in-band with IsError set, not as JSON-RPC errors, so the model can see
+// and react to them.
+type CallToolResult struct {
+	Content           []content `json:"content"`
+	StructuredContent any       `json:"structuredContent,omitempty"`
+	IsError           bool      `json:"isError,omitempty"`
+}
@@ -0,0 +1,214 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/client"
+	"github.com/sentiolabs/arc/internal/types"
+	"github.com/sentiolabs/arc/internal/version"
+)
+
+// maxMessageSize caps a single stdio message. Plan content and long issue
+// descriptions can exceed bufio.Scanner's 64 KiB default.
+const maxMessageSize = 4 << 20
+
+// Backend is the subset of the arc API the MCP tools call.
+// *client.Client satisfies it; tests substitute a fake.
+//
+//nolint:interfacebloat // mirrors the client methods the tool set needs
+type Backend interface {
+	CreateIssue(projID string, req client.CreateIssueRequest) (*types.Issue, error)
+	AddLabelToIssueByID(issueID, label string) error
+	GetIssueDetailsByID(id string) (*types.IssueDetails, error)
+	UpdateIssueByID(id string, updates map[string]any) (*types.Issue, error)
+	CloseIssueByID(id, reason string, cascade bool) (*types.Issue, error)
+	GetReadyWork(projID string, opts client.WorkOptions) ([]*types.Issue, error)
+	AddDependencyByID(issueID, dependsOnID, depType string) error
+	RemoveDependencyByID(issueID, dependsOnID string) error
+	GetTeamContext(projID, epicID string) (*client.TeamContext, error)
+	CreatePlan(filePath string) (*types.Plan, error)
+	GetPlan(planID string) (*types.PlanWithContent, error)
+	ListPlanComments(planID string) ([]*types.PlanComment, error)
+}
+
+// Ensure the HTTP client can back the MCP server.
+var _ Backend = (*client.Client)(nil)
+
+// Options configures an MCP server.
+type Options struct {
+	// DefaultProject is used by project-scoped tools when the caller omits
+	// the project argument. `arc mcp` fills it from normal project resolution;
+	// the HTTP endpoint leaves it empty so callers must be explicit.
+	DefaultProject string
+	// PlanPollInterval is how often wait_plan re-checks plan status.
+	// Zero means planPollInterval.
+	PlanPollInterval time.Duration
+}
+
+// Server dispatches MCP JSON-RPC messages to arc tools.
+type Server struct {
+	backend Backend
+	opts    Options
+	tools   map[string]*toolDef
+}
+
+// NewServer creates an MCP server backed by the given arc API backend.
+func NewServer(backend Backend, opts Options) *Server {
+	if opts.PlanPollInterval <= 0 {
+		opts.PlanPollInterval = planPollInterval
+	}
+	s := &Server{
+		backend: backend,
+		opts:    opts,
+		tools:   make(map[string]*toolDef),
+	}
+	for _, t := range s.toolDefs() {
+		s.tools[t.Name] = t
+	}
+	return s
+}
+
+// Tools returns the advertised tools sorted by name.
+func (s *Server) Tools() []Tool {
+	tools := make([]Tool, 0, len(s.tools))
+	for _, t := range s.tools {
+		tools = append(tools, t.Tool)
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools
+}
+
+// HandleMessage processes one raw JSON-RPC message and returns the encoded
+// response. The returned slice is nil for notifications.
+func (s *Server) HandleMessage(ctx context.Context, msg []byte) []byte {
+	var req request
+	if err := json.Unmarshal(msg, &req); err != nil {
+		return encode(errorResponse(nil, codeParseError, "parse error: "+err.Error()))
+	}
+	if req.JSONRPC != jsonRPCVersion || req.Method == "" {
+		if req.isNotification() {
+			return nil
+		}
+		return encode(errorResponse(req.ID, codeInvalidRequest, "invalid JSON-RPC 2.0 request"))
+	}
+
+	result, rpcErr := s.dispatch(ctx, &req)
+	if req.isNotification() {
+		return nil
+	}
+	if rpcErr != nil {
+		return encode(&response{JSONRPC: jsonRPCVersion, ID: req.ID, Error: rpcErr})
+	}
+	return encode(&response{JSONRPC: jsonRPCVersion, ID: req.ID, Result: result})
+}
+
+// dispatch routes a request to its method handler.
+func (s *Server) dispatch(ctx context.Context, req *request) (any, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		return initializeResult{
+			ProtocolVersion: ProtocolVersion,
+			Capabilities:    map[string]any{"tools": map[string]any{}},
+			ServerInfo:      serverInfo{Name: "arc", Version: version.Version},
+			Instructions: "arc is an issue tracker. Use ready_work to find unblocked issues, " +
+				"update_issue to claim them, and close_issue when done.",
+		}, nil
+	case "ping":
+		return struct{}{}, nil
+	case "notifications/initialized", "notifications/cancelled":
+		return nil, nil
+	case "tools/list":
+		return listToolsResult{Tools: s.Tools()}, nil
+	case "tools/call":
+		var params callToolParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: "invalid tools/call params: " + err.Error()}
+		}
+		tool, ok := s.tools[params.Name]
+		if !ok {
+			return nil, &rpcError{Code: codeInvalidParams, Message: "unknown tool: " + params.Name}
+		}
+		return s.callTool(ctx, tool, params.Arguments), nil
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: "method not found: " + req.Method}
+	}
+}
+
+// callTool runs a tool handler and wraps its outcome as a CallToolResult.
+func (s *Server) callTool(ctx context.Context, tool *toolDef, args json.RawMessage) *CallToolResult {
+	if len(args) == 0 {
+		args = json.RawMessage("{}")
+	}
+	out, err := tool.handler(ctx, args)
+	if err != nil {
+		return &CallToolResult{
+			Content: []content{{Type: "text", Text: err.Error()}},
+			IsError: true,
+		}
+	}
+	text, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return &CallToolResult{
+			Content: []content{{Type: "text", Text: fmt.Sprintf("encode result: %v", err)}},
+			IsError: true,
+		}
+	}
+	return &CallToolResult{
+		Content:           []content{{Type: "text", Text: string(text)}},
+		StructuredContent: out,
+	}
+}
+
+// ServeStdio reads newline-delimited JSON-RPC messages from r and writes
+// responses to w until r is exhausted or ctx is cancelled.
+func (s *Server) ServeStdio(ctx context.Context, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxMessageSize)
+
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		out := s.HandleMessage(ctx, line)
+		if out == nil {
+			continue
+		}
+		if _, err := w.Write(append(out, '\n')); err != nil {
+			return fmt.Errorf("write response: %w", err)
+		}
+	}
+	if err := scanner.Err(); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("read request: %w", err)
+	}
+	return nil
+}
+
+// errorResponse builds a JSON-RPC error response.
+func errorResponse(id json.RawMessage, code int, message string) *response {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &response{JSONRPC: jsonRPCVersion, ID: id, Error: &rpcError{Code: code, Message: message}}
+}
+
+// encode marshals a response. Responses only contain JSON-safe values, so a
+// marshal failure is reported as an internal error rather than dropped.
+func encode(resp *response) []byte {
+	b, err := json.Marshal(resp)
+	if err != nil {
+		b, _ = json.Marshal(errorResponse(resp.ID, codeInternalError, err.Error()))
+	}
+	return b
+}
@@ -0,0 +1,327 @@
+package mcp_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/client"
+	"github.com/sentiolabs/arc/internal/mcp"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// fakeBackend records calls and returns canned results.
+type fakeBackend struct {
+	createdProject string
+	created        client.CreateIssueRequest
+	labels         []string
+	closeErr       error
+	planStatuses   []string
+	planCalls      int
+}
+
+func (f *fakeBackend) CreateIssue(projID string, req client.CreateIssueRequest) (*types.Issue, error) {
+	f.createdProject = projID
+	f.created = req
+	return &types.Issue{ID: "arc-abc123", ProjectID: projID, Title: req.Title}, nil
+}
+
+func (f *fakeBackend) AddLabelToIssueByID(_, label string) error {
+	f.labels = append(f.labels, label)
+	return nil
+}
+
+func (f *fakeBackend) GetIssueDetailsByID(id string) (*types.IssueDetails, error) {
+	return &types.IssueDetails{Issue: types.Issue{ID: id}}, nil
+}
+
+func (f *fakeBackend) UpdateIssueByID(id string, _ map[string]any) (*types.Issue, error) {
+	return &types.Issue{ID: id}, nil
+}
+
+func (f *fakeBackend) CloseIssueByID(id, _ string, _ bool) (*types.Issue, error) {
+	if f.closeErr != nil {
+		return nil, f.closeErr
+	}
+	return &types.Issue{ID: id, Status: types.StatusClosed}, nil
+}
+
+func (f *fakeBackend) GetReadyWork(_ string, _ client.WorkOptions) ([]*types.Issue, error) {
+	return []*types.Issue{{ID: "arc-ready1"}}, nil
+}
+
+func (f *fakeBackend) AddDependencyByID(_, _, _ string) error { return nil }
+
+func (f *fakeBackend) RemoveDependencyByID(_, _ string) error { return nil }
+
+func (f *fakeBackend) GetTeamContext(projID, _ string) (*client.TeamContext, error) {
+	return &client.TeamContext{Project: projID}, nil
+}
+
+func (f *fakeBackend) CreatePlan(_ string) (*types.Plan, error) {
+	return &types.Plan{ID: "plan.abc", Status: types.PlanStatusDraft}, nil
+}
+
+func (f *fakeBackend) GetPlan(planID string) (*types.PlanWithContent, error) {
+	status := f.planStatuses[min(f.planCalls, len(f.planStatuses)-1)]
+	f.planCalls++
+	return &types.PlanWithContent{Plan: types.Plan{ID: planID, Status: status}}, nil
+}
+
+func (f *fakeBackend) ListPlanComments(_ string) ([]*types.PlanComment, error) {
+	return []*types.PlanComment{{ID: "c1", Content: "looks good"}}, nil
+}
+
+// call sends one JSON-RPC request and decodes the response envelope.
+func call(t *testing.T, srv *mcp.Server, method string, params any) map[string]json.RawMessage {
+	t.Helper()
+	msg := map[string]any{"jsonrpc": "2.0", "id": 1, "method": method}
+	if params != nil {
+		msg["params"] = params
+	}
+	raw, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatalf("marshal request: %v", err)
+	}
+	out := srv.HandleMessage(context.Background(), raw)
+	if out == nil {
+		t.Fatalf("%s: expected a response, got nil", method)
+	}
+	var resp map[string]json.RawMessage
+	if err := json.Unmarshal(out, &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return resp
+}
+
+// callTool invokes tools/call and returns the decoded result.
+func callTool(t *testing.T, srv *mcp.Server, name string, args any) mcp.CallToolResult {
+	t.Helper()
+	resp := call(t, srv, "tools/call", map[string]any{"name": name, "arguments": args})
+	if _, ok := resp["error"]; ok {
+		t.Fatalf("tools/call %s returned JSON-RPC error: %s", name, resp["error"])
+	}
+	var result mcp.CallToolResult
+	if err := json.Unmarshal(resp["result"], &result); err != nil {
+		t.Fatalf("decode tool result: %v", err)
+	}
+	return result
+}
+
+func TestInitialize(t *testing.T) {
+	srv := mcp.NewServer(&fakeBackend{}, mcp.Options{})
+	resp := call(t, srv, "initialize", map[string]any{"protocolVersion": mcp.ProtocolVersion})
+
+	var result struct {
+		ProtocolVersion string `json:"protocolVersion"`
+		ServerInfo      struct {
+			Name string `json:"name"`
+		} `json:"serverInfo"`
+	}
+	if err := json.Unmarshal(resp["result"], &result); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if result.ProtocolVersion != mcp.ProtocolVersion {
+		t.Errorf("protocolVersion = %q, want %q", result.ProtocolVersion, mcp.ProtocolVersion)
+	}
+	if result.ServerInfo.Name != "arc" {
+		t.Errorf("serverInfo.name = %q, want arc", result.ServerInfo.Name)
+	}
+}
+
+func TestToolsList(t *testing.T) {
+	srv := mcp.NewServer(&fakeBackend{}, mcp.Options{})
+	resp := call(t, srv, "tools/list", nil)
+
+	var result struct {
+		Tools []mcp.Tool `json:"tools"`
+	}
+	if err := json.Unmarshal(resp["result"], &result); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	want := []string{
+		"add_dependency", "close_issue", "create_issue", "create_plan", "ready_work",
+		"remove_dependency", "show_issue", "team_context", "update_issue", "wait_plan",
+	}
+	if len(result.Tools) != len(want) {
+		t.Fatalf("got %d tools, want %d", len(result.Tools), len(want))
+	}
+	for i, tool := range result.Tools {
+		if tool.Name != want[i] {
+			t.Errorf("tools[%d] = %q, want %q", i, tool.Name, want[i])
+		}
+		if tool.InputSchema.Type != "object" {
+			t.Errorf("%s: input schema type = %q, want object", tool.Name, tool.InputSchema.Type)
+		}
+	}
+}
+
+func TestCreateIssueUsesDefaultProject(t *testing.T) {
+	backend := &fakeBackend{}
+	srv := mcp.NewServer(backend, mcp.Options{DefaultProject: "proj-1"})
+
+	result := callTool(t, srv, "create_issue", map[string]any{
+		"title":    "Fix login",
+		"priority": 1,
+		"labels":   []string{"bug", "auth"},
+	})
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %+v", result.Content)
+	}
+	if backend.createdProject != "proj-1" {
+		t.Errorf("project = %q, want proj-1", backend.createdProject)
+	}
+	if backend.created.Priority != 1 {
+		t.Errorf("priority = %d, want 1", backend.created.Priority)
+	}
+	if strings.Join(backend.labels, ",") != "bug,auth" {
+		t.Errorf("labels = %v, want [bug auth]", backend.labels)
+	}
+}
+
+func TestCreateIssueWithoutProject(t *testing.T) {
+	srv := mcp.NewServer(&fakeBackend{}, mcp.Options{})
+
+	result := callTool(t, srv, "create_issue", map[string]any{"title": "x"})
+	if !result.IsError {
+		t.Fatal("expected tool error when no project is available")
+	}
+}
+
+func TestToolErrorsAreInBand(t *testing.T) {
+	backend := &fakeBackend{closeErr: errors.New("issue not found")}
+	srv := mcp.NewServer(backend, mcp.Options{})
+
+	result := callTool(t, srv, "close_issue", map[string]any{"id": "arc-missing"})
+	if !result.IsError {
+		t.Fatal("expected isError result")
+	}
+	if len(result.Content) != 1 || !strings.Contains(result.Content[0].Text, "issue not found") {
+		t.Errorf("content = %+v, want error text", result.Content)
+	}
+}
+
+func TestUnknownArgumentRejected(t *testing.T) {
+	srv := mcp.NewServer(&fakeBackend{}, mcp.Options{})
+
+	result := callTool(t, srv, "show_issue", map[string]any{"id": "arc-1", "bogus": true})
+	if !result.IsError {
+		t.Fatal("expected isError for unknown argument")
+	}
+}
+
+func TestUnknownToolAndMethod(t *testing.T) {
+	srv := mcp.NewServer(&fakeBackend{}, mcp.Options{})
+
+	resp := call(t, srv, "tools/call", map[string]any{"name": "nope"})
+	if _, ok := resp["error"]; !ok {
+		t.Error("unknown tool: expected JSON-RPC error")
+	}
+
+	resp = call(t, srv, "resources/list", nil)
+	var rpcErr struct {
+		Code int `json:"code"`
+	}
+	if err := json.Unmarshal(resp["error"], &rpcErr); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if rpcErr.Code != -32601 {
+		t.Errorf("code = %d, want -32601", rpcErr.Code)
+	}
+}
+
+func TestWaitPlanReturnsDecision(t *testing.T) {
+	backend := &fakeBackend{planStatuses: []string{
+		types.PlanStatusInReview, types.PlanStatusInReview, types.PlanStatusApproved,
+	}}
+	srv := mcp.NewServer(backend, mcp.Options{PlanPollInterval: time.Millisecond})
+
+	result := callTool(t, srv, "wait_plan", map[string]any{"plan_id": "plan.abc", "timeout_seconds": 5})
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %+v", result.Content)
+	}
+	if !strings.Contains(result.Content[0].Text, `"status": "approved"`) {
+		t.Errorf("result = %s, want approved status", result.Content[0].Text)
+	}
+	if backend.planCalls != 3 {
+		t.Errorf("GetPlan called %d times, want 3", backend.planCalls)
+	}
+}
+
+func TestNotificationHasNoResponse(t *testing.T) {
+	srv := mcp.NewServer(&fakeBackend{}, mcp.Options{})
+
+	out := srv.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","method":"notifications/initialized"}`))
+	if out != nil {
+		t.Errorf("notification produced response: %s", out)
+	}
+}
+
+func TestServeStdio(t *testing.T) {
+	srv := mcp.NewServer(&fakeBackend{}, mcp.Options{})
+	in := strings.NewReader(strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		``,
+		`{"jsonrpc":"2.0","id":2,"method":"ping"}`,
+	}, "\n"))
+	var out bytes.Buffer
+
+	if err := srv.ServeStdio(context.Background(), in, &out); err != nil {
+		t.Fatalf("ServeStdio: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d response lines, want 2:\n%s", len(lines), out.String())
+	}
+	if !strings.Contains(lines[1], `"id":2`) {
+		t.Errorf("second response = %s, want id 2", lines[1])
+	}
+}
+
+func TestHTTPHandler(t *testing.T) {
+	srv := mcp.NewServer(&fakeBackend{}, mcp.Options{})
+	handler := srv.HTTPHandler()
+
+	t.Run("request", func(t *testing.T) {
+		body := `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`
+		req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200", rec.Code)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+	})
+
+	t.Run("notification", func(t *testing.T) {
+		body := `{"jsonrpc":"2.0","method":"notifications/initialized"}`
+		req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusAccepted {
+			t.Errorf("status = %d, want 202", rec.Code)
+		}
+	})
+
+	t.Run("get not allowed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/mcp", nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf("status = %d, want 405", rec.Code)
+		}
+	})
+}
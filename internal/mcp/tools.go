@@ -0,0 +1,432 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/client"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+const (
+	// planPollInterval is the default wait_plan status check interval.
+	planPollInterval = 2 * time.Second
+	// defaultPlanWaitSeconds is the default wait_plan timeout.
+	defaultPlanWaitSeconds = 300
+	// maxPlanWaitSeconds caps wait_plan so one call cannot hold a client forever.
+	maxPlanWaitSeconds = 3600
+	// defaultReadyLimit is the default ready_work result count.
+	defaultReadyLimit = 10
+	// maxPriority is the lowest issue priority (backlog).
+	maxPriority = 4
+)
+
+// toolHandler executes a tool with its raw JSON arguments.
+type toolHandler func(ctx context.Context, args json.RawMessage) (any, error)
+
+// toolDef pairs an advertised tool with its handler.
+type toolDef struct {
+	Tool
+	handler toolHandler
+}
+
+// Reusable property schemas.
+var (
+	minPriority  = 0
+	maxPrio      = maxPriority
+	minOne       = 1
+	propProject  = &Schema{Type: "string", Description: "Project ID. Defaults to the server's active project."}
+	propIssueID  = &Schema{Type: "string", Description: "Issue ID (e.g. arc-a1b2c3)."}
+	propPriority = &Schema{
+		Type: "integer", Minimum: &minPriority, Maximum: &maxPrio,
+		Description: "Priority: 0 (critical) to 4 (backlog).",
+	}
+	propIssueType = &Schema{Type: "string", Enum: enumOf(types.AllIssueTypes()), Description: "Issue type."}
+	propStatus    = &Schema{Type: "string", Enum: enumOf(types.AllStatuses()), Description: "Issue status."}
+	propDepType   = &Schema{
+		Type: "string", Enum: enumOf(types.AllDependencyTypes()),
+		Description: "Dependency type. Defaults to blocks.",
+	}
+)
+
+// toolDefs builds the full tool set.
+func (s *Server) toolDefs() []*toolDef {
+	return []*toolDef{
+		{Tool{
+			Name:        "create_issue",
+			Description: "Create an issue. Set parent_id to create a hierarchical child (parent.N).",
+			InputSchema: Schema{Type: "object", Required: []string{"title"}, Properties: map[string]*Schema{
+				"project":     propProject,
+				"title":       {Type: "string", Description: "Issue title."},
+				"description": {Type: "string", Description: "Markdown description."},
+				"priority":    propPriority,
+				"issue_type":  propIssueType,
+				"parent_id":   {Type: "string", Description: "Parent issue ID."},
+				"labels":      {Type: "array", Items: &Schema{Type: "string"}, Description: "Labels to apply."},
+			}},
+		}, s.createIssue},
+		{Tool{
+			Name:        "show_issue",
+			Description: "Show an issue with its labels, dependencies, dependents and comments.",
+			InputSchema: Schema{Type: "object", Required: []string{"id"}, Properties: map[string]*Schema{
+				"id": propIssueID,
+			}},
+		}, s.showIssue},
+		{Tool{
+			Name:        "update_issue",
+			Description: "Update fields of an issue. Only provided fields change.",
+			InputSchema: Schema{Type: "object", Required: []string{"id"}, Properties: map[string]*Schema{
+				"id":          propIssueID,
+				"title":       {Type: "string"},
+				"description": {Type: "string"},
+				"status":      propStatus,
+				"priority":    propPriority,
+				"issue_type":  propIssueType,
+			}},
+		}, s.updateIssue},
+		{Tool{
+			Name:        "close_issue",
+			Description: "Close an issue. Fails with the open children listed unless cascade is set.",
+			InputSchema: Schema{Type: "object", Required: []string{"id"}, Properties: map[string]*Schema{
+				"id":      propIssueID,
+				"reason":  {Type: "string", Description: "Close reason."},
+				"cascade": {Type: "boolean", Description: "Close open child issues recursively."},
+			}},
+		}, s.closeIssue},
+		{Tool{
+			Name:        "ready_work",
+			Description: "List issues that are not blocked by any open dependency.",
+			InputSchema: Schema{Type: "object", Properties: map[string]*Schema{
+				"project": propProject,
+				"limit":   {Type: "integer", Minimum: &minOne, Description: "Maximum results (default 10)."},
+				"sort": {
+					Type: "string", Enum: enumOf(types.AllSortPolicies()),
+					Description: "Sort policy (default hybrid).",
+				},
+			}},
+		}, s.readyWork},
+		{Tool{
+			Name:        "add_dependency",
+			Description: "Record that issue_id depends on depends_on_id.",
+			InputSchema: Schema{Type: "object", Required: []string{"issue_id", "depends_on_id"}, Properties: map[string]*Schema{
+				"issue_id":      propIssueID,
+				"depends_on_id": propIssueID,
+				"type":          propDepType,
+			}},
+		}, s.addDependency},
+		{Tool{
+			Name:        "remove_dependency",
+			Description: "Remove the dependency of issue_id on depends_on_id.",
+			InputSchema: Schema{Type: "object", Required: []string{"issue_id", "depends_on_id"}, Properties: map[string]*Schema{
+				"issue_id":      propIssueID,
+				"depends_on_id": propIssueID,
+			}},
+		}, s.removeDependency},
+		{Tool{
+			Name:        "team_context",
+			Description: "Group open issues by teammate:* role label, optionally scoped to one epic.",
+			InputSchema: Schema{Type: "object", Properties: map[string]*Schema{
+				"project": propProject,
+				"epic_id": {Type: "string", Description: "Only include children of this epic."},
+			}},
+		}, s.teamContext},
+		{Tool{
+			Name:        "create_plan",
+			Description: "Register a markdown plan file for review in the arc web UI.",
+			InputSchema: Schema{Type: "object", Required: []string{"file_path"}, Properties: map[string]*Schema{
+				"file_path": {Type: "string", Description: "Absolute path to the plan markdown file."},
+			}},
+		}, s.createPlan},
+		{Tool{
+			Name: "wait_plan",
+			Description: "Block until a plan leaves draft/in_review and return the decision " +
+				"with all review comments.",
+			InputSchema: Schema{Type: "object", Required: []string{"plan_id"}, Properties: map[string]*Schema{
+				"plan_id": {Type: "string"},
+				"timeout_seconds": {
+					Type: "integer", Minimum: &minOne,
+					Description: "How long to wait (default 300, max 3600).",
+				},
+			}},
+		}, s.waitPlan},
+	}
+}
+
+// projectArg returns the explicit project or the server default.
+func (s *Server) projectArg(project string) (string, error) {
+	if project != "" {
+		return project, nil
+	}
+	if s.opts.DefaultProject != "" {
+		return s.opts.DefaultProject, nil
+	}
+	return "", errors.New("project is required (no default project configured)")
+}
+
+// decodeArgs unmarshals tool arguments, rejecting unknown fields so typos
+// surface instead of being silently ignored.
+func decodeArgs(raw json.RawMessage, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+func (s *Server) createIssue(_ context.Context, raw json.RawMessage) (any, error) {
+	var args struct {
+		Project     string   `json:"project"`
+		Title       string   `json:"title"`
+		Description string   `json:"description"`
+		Priority    *int     `json:"priority"`
+		IssueType   string   `json:"issue_type"`
+		ParentID    string   `json:"parent_id"`
+		Labels      []string `json:"labels"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	if args.Title == "" {
+		return nil, errors.New("title is required")
+	}
+	projID, err := s.projectArg(args.Project)
+	if err != nil {
+		return nil, err
+	}
+
+	req := client.CreateIssueRequest{
+		Title:       args.Title,
+		Description: args.Description,
+		IssueType:   args.IssueType,
+		ParentID:    args.ParentID,
+	}
+	if args.Priority != nil {
+		req.Priority = *args.Priority
+	}
+	issue, err := s.backend.CreateIssue(projID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, label := range args.Labels {
+		if err := s.backend.AddLabelToIssueByID(issue.ID, label); err != nil {
+			return nil, fmt.Errorf("created %s but failed to add label %q: %w", issue.ID, label, err)
+		}
+		issue.Labels = append(issue.Labels, label)
+	}
+	return issue, nil
+}
+
+func (s *Server) showIssue(_ context.Context, raw json.RawMessage) (any, error) {
+	var args struct {
+		ID string `json:"id"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	return s.backend.GetIssueDetailsByID(args.ID)
+}
+
+func (s *Server) updateIssue(_ context.Context, raw json.RawMessage) (any, error) {
+	var args struct {
+		ID          string  `json:"id"`
+		Title       *string `json:"title"`
+		Description *string `json:"description"`
+		Status      *string `json:"status"`
+		Priority    *int    `json:"priority"`
+		IssueType   *string `json:"issue_type"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]any)
+	if args.Title != nil {
+		updates["title"] = *args.Title
+	}
+	if args.Description != nil {
+		updates["description"] = *args.Description
+	}
+	if args.Status != nil {
+		updates["status"] = *args.Status
+	}
+	if args.Priority != nil {
+		updates["priority"] = *args.Priority
+	}
+	if args.IssueType != nil {
+		updates["issue_type"] = *args.IssueType
+	}
+	if len(updates) == 0 {
+		return nil, errors.New("no updates provided")
+	}
+	return s.backend.UpdateIssueByID(args.ID, updates)
+}
+
+func (s *Server) closeIssue(_ context.Context, raw json.RawMessage) (any, error) {
+	var args struct {
+		ID      string `json:"id"`
+		Reason  string `json:"reason"`
+		Cascade bool   `json:"cascade"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	issue, err := s.backend.CloseIssueByID(args.ID, args.Reason, args.Cascade)
+	if err != nil {
+		var openChildren *types.OpenChildrenError
+		if errors.As(err, &openChildren) {
+			ids := make([]string, len(openChildren.Children))
+			for i, c := range openChildren.Children {
+				ids[i] = c.ID
+			}
+			return nil, fmt.Errorf("%w: open children %v (set cascade to close them)", err, ids)
+		}
+		return nil, err
+	}
+	return issue, nil
+}
+
+func (s *Server) readyWork(_ context.Context, raw json.RawMessage) (any, error) {
+	var args struct {
+		Project string `json:"project"`
+		Limit   int    `json:"limit"`
+		Sort    string `json:"sort"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	projID, err := s.projectArg(args.Project)
+	if err != nil {
+		return nil, err
+	}
+	if args.Limit <= 0 {
+		args.Limit = defaultReadyLimit
+	}
+	issues, err := s.backend.GetReadyWork(projID, client.WorkOptions{Limit: args.Limit, Sort: args.Sort})
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"issues": issues}, nil
+}
+
+func (s *Server) addDependency(_ context.Context, raw json.RawMessage) (any, error) {
+	var args struct {
+		IssueID     string `json:"issue_id"`
+		DependsOnID string `json:"depends_on_id"`
+		Type        string `json:"type"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	if args.Type == "" {
+		args.Type = string(types.DepBlocks)
+	}
+	if err := s.backend.AddDependencyByID(args.IssueID, args.DependsOnID, args.Type); err != nil {
+		return nil, err
+	}
+	return map[string]string{"issue_id": args.IssueID, "depends_on_id": args.DependsOnID, "type": args.Type}, nil
+}
+
+func (s *Server) removeDependency(_ context.Context, raw json.RawMessage) (any, error) {
+	var args struct {
+		IssueID     string `json:"issue_id"`
+		DependsOnID string `json:"depends_on_id"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	if err := s.backend.RemoveDependencyByID(args.IssueID, args.DependsOnID); err != nil {
+		return nil, err
+	}
+	return map[string]string{"issue_id": args.IssueID, "depends_on_id": args.DependsOnID}, nil
+}
+
+func (s *Server) teamContext(_ context.Context, raw json.RawMessage) (any, error) {
+	var args struct {
+		Project string `json:"project"`
+		EpicID  string `json:"epic_id"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	projID, err := s.projectArg(args.Project)
+	if err != nil {
+		return nil, err
+	}
+	return s.backend.GetTeamContext(projID, args.EpicID)
+}
+
+func (s *Server) createPlan(_ context.Context, raw json.RawMessage) (any, error) {
+	var args struct {
+		FilePath string `json:"file_path"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	if args.FilePath == "" {
+		return nil, errors.New("file_path is required")
+	}
+	return s.backend.CreatePlan(args.FilePath)
+}
+
+// planDecision is the wait_plan result once a reviewer has acted.
+type planDecision struct {
+	PlanID   string               `json:"plan_id"`
+	Status   string               `json:"status"`
+	Comments []*types.PlanComment `json:"comments"`
+}
+
+func (s *Server) waitPlan(ctx context.Context, raw json.RawMessage) (any, error) {
+	var args struct {
+		PlanID         string `json:"plan_id"`
+		TimeoutSeconds int    `json:"timeout_seconds"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	if args.TimeoutSeconds <= 0 {
+		args.TimeoutSeconds = defaultPlanWaitSeconds
+	}
+	args.TimeoutSeconds = min(args.TimeoutSeconds, maxPlanWaitSeconds)
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(args.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	ticker := time.NewTicker(s.opts.PlanPollInterval)
+	defer ticker.Stop()
+
+	for {
+		plan, err := s.backend.GetPlan(args.PlanID)
+		if err != nil {
+			return nil, err
+		}
+		if plan.Status != types.PlanStatusDraft && plan.Status != types.PlanStatusInReview {
+			comments, err := s.backend.ListPlanComments(args.PlanID)
+			if err != nil {
+				return nil, err
+			}
+			return planDecision{PlanID: args.PlanID, Status: plan.Status, Comments: comments}, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out after %ds waiting for a decision on %s (status: %s)",
+				args.TimeoutSeconds, args.PlanID, plan.Status)
+		case <-ticker.C:
+		}
+	}
+}
+
+// enumOf converts a typed string slice to plain strings for schema enums.
+func enumOf[T ~string](vals []T) []string {
+	out := make([]string, len(vals))
+	for i, v := range vals {
+		out[i] = string(v)
+	}
+	return out
+}
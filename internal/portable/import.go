@@ -0,0 +1,290 @@
+package portable
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sentiolabs/arc/internal/project"
+	"github.com/sentiolabs/arc/internal/storage"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// ErrConflict is returned when a preserving import collides with data that
+// already exists on the server.
+var ErrConflict = errors.New("import conflicts with existing data")
+
+// ImportOptions controls how an archive is imported.
+type ImportOptions struct {
+	// TargetProjectID, when set, imports the archive into this existing
+	// project instead of recreating the archived one. Issue IDs are
+	// re-prefixed with the target's prefix and every reference to them is
+	// rewritten. When empty, all IDs are preserved and any collision with
+	// existing data fails the import.
+	TargetProjectID string
+}
+
+// Import writes an archive to store according to opts. The archive is
+// modified in place as IDs are rewritten.
+func Import(ctx context.Context, store storage.Storage, a *types.ProjectArchive, opts ImportOptions) (*types.ImportResult, error) {
+	if a.Project == nil {
+		return nil, errors.New("archive has no project")
+	}
+
+	result := &types.ImportResult{}
+	var err error
+	if opts.TargetProjectID == "" {
+		err = checkPreserve(ctx, store, a)
+	} else {
+		err = retarget(ctx, store, a, opts.TargetProjectID, result)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := store.ImportProject(ctx, a); err != nil {
+		return nil, err
+	}
+
+	result.Project = a.Project
+	result.Issues = len(a.Issues)
+	result.Dependencies = len(a.Dependencies)
+	result.Comments = len(a.Comments)
+	result.Events = len(a.Events)
+	result.Plans = len(a.Plans)
+	result.Workspaces = len(a.Workspaces)
+	return result, nil
+}
+
+// checkPreserve verifies that nothing in the archive already exists, so
+// the import can keep every ID as is.
+func checkPreserve(ctx context.Context, store storage.Storage, a *types.ProjectArchive) error {
+	if _, err := store.GetProject(ctx, a.Project.ID); err == nil {
+		return fmt.Errorf("%w: project %s already exists (use a target project to import into it)",
+			ErrConflict, a.Project.ID)
+	}
+	if _, err := store.GetProjectByName(ctx, a.Project.Name); err == nil {
+		return fmt.Errorf("%w: a project named %q already exists", ErrConflict, a.Project.Name)
+	}
+	for _, issue := range a.Issues {
+		if _, err := store.GetIssue(ctx, issue.ID); err == nil {
+			return fmt.Errorf("%w: issue %s already exists", ErrConflict, issue.ID)
+		}
+	}
+	for _, ws := range a.Workspaces {
+		if _, err := store.GetWorkspace(ctx, ws.ID); err == nil {
+			return fmt.Errorf("%w: workspace %s already exists", ErrConflict, ws.ID)
+		}
+	}
+	for _, plan := range a.Plans {
+		if _, err := store.GetPlan(ctx, plan.ID); err == nil {
+			return fmt.Errorf("%w: plan %s already exists", ErrConflict, plan.ID)
+		}
+	}
+	return nil
+}
+
+// retarget rewrites the archive so it can be added to an existing project.
+// Project config is not carried over; the target keeps its own.
+func retarget(
+	ctx context.Context, store storage.Storage, a *types.ProjectArchive, targetID string, result *types.ImportResult,
+) error {
+	target, err := store.GetProject(ctx, targetID)
+	if err != nil {
+		return fmt.Errorf("target project: %w", err)
+	}
+
+	idMap, err := remapIssueIDs(ctx, store, a.Issues, target.Prefix)
+	if err != nil {
+		return err
+	}
+	rewrite := referenceRewriter(idMap)
+
+	for _, issue := range a.Issues {
+		issue.ID = idMap[issue.ID]
+		issue.ProjectID = target.ID
+		issue.Description = rewrite(issue.Description)
+		if issue.ExternalRef != "" {
+			if _, err := store.GetIssueByExternalRef(ctx, issue.ExternalRef); err == nil {
+				issue.ExternalRef = ""
+			}
+		}
+	}
+	for _, dep := range a.Dependencies {
+		dep.IssueID = idMap[dep.IssueID]
+		dep.DependsOnID = idMap[dep.DependsOnID]
+	}
+	for _, c := range a.Comments {
+		c.IssueID = idMap[c.IssueID]
+		c.Text = rewrite(c.Text)
+	}
+	for _, e := range a.Events {
+		e.IssueID = idMap[e.IssueID]
+		e.OldValue = rewritePtr(rewrite, e.OldValue)
+		e.NewValue = rewritePtr(rewrite, e.NewValue)
+		e.Comment = rewritePtr(rewrite, e.Comment)
+	}
+
+	if err := retargetWorkspaces(ctx, store, a, target.ID, result); err != nil {
+		return err
+	}
+	remapPlans(ctx, store, a)
+
+	a.Project = target
+	a.Config = nil
+	for oldID, newID := range idMap {
+		if oldID != newID {
+			if result.IDMap == nil {
+				result.IDMap = make(map[string]string)
+			}
+			result.IDMap[oldID] = newID
+		}
+	}
+	return nil
+}
+
+// remapIssueIDs maps every archived issue ID to its ID under prefix. The
+// hash part of a top-level ID is kept when it is free; children keep
+// their number under the new parent ID.
+func remapIssueIDs(ctx context.Context, store storage.Storage, issues []*types.Issue, prefix string) (map[string]string, error) {
+	bases := make(map[string]string)
+	taken := make(map[string]bool)
+	titles := make(map[string]string, len(issues))
+	for _, issue := range issues {
+		titles[issue.ID] = issue.Title
+	}
+
+	idMap := make(map[string]string, len(issues))
+	for _, issue := range issues {
+		base, rest := splitIssueID(issue.ID)
+		if base == "" {
+			return nil, fmt.Errorf("issue %s: unrecognized ID format", issue.ID)
+		}
+
+		newBase, ok := bases[base]
+		if !ok {
+			_, hash, _ := strings.Cut(base, ".")
+			newBase = prefix + "." + hash
+			for taken[newBase] || issueExists(ctx, store, newBase) {
+				newBase = project.GenerateIssueID(prefix, titles[base]+newBase)
+			}
+			bases[base] = newBase
+			taken[newBase] = true
+		}
+		idMap[issue.ID] = newBase + rest
+	}
+	return idMap, nil
+}
+
+// splitIssueID splits "prefix.hash.1.2" into "prefix.hash" and ".1.2".
+func splitIssueID(id string) (base, rest string) {
+	parts := strings.SplitN(id, ".", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", ""
+	}
+	base = parts[0] + "." + parts[1]
+	return base, id[len(base):]
+}
+
+func issueExists(ctx context.Context, store storage.Storage, id string) bool {
+	_, err := store.GetIssue(ctx, id)
+	return err == nil
+}
+
+// referenceRewriter returns a function replacing mentions of old issue IDs
+// in free text with their new IDs.
+func referenceRewriter(idMap map[string]string) func(string) string {
+	if len(idMap) == 0 {
+		return func(s string) string { return s }
+	}
+	prefixes := make(map[string]bool)
+	for oldID := range idMap {
+		p, _, _ := strings.Cut(oldID, ".")
+		prefixes[regexp.QuoteMeta(p)] = true
+	}
+	alternatives := make([]string, 0, len(prefixes))
+	for p := range prefixes {
+		alternatives = append(alternatives, p)
+	}
+	re := regexp.MustCompile(`\b(?:` + strings.Join(alternatives, "|") + `)\.[0-9a-z]+(?:\.[0-9]+)*\b`)
+
+	return func(s string) string {
+		return re.ReplaceAllStringFunc(s, func(m string) string {
+			if newID, ok := idMap[m]; ok {
+				return newID
+			}
+			return m
+		})
+	}
+}
+
+func rewritePtr(rewrite func(string) string, s *string) *string {
+	if s == nil {
+		return nil
+	}
+	v := rewrite(*s)
+	return &v
+}
+
+// retargetWorkspaces drops workspaces the target already has and gives the
+// rest fresh IDs where theirs are taken.
+func retargetWorkspaces(
+	ctx context.Context, store storage.Storage, a *types.ProjectArchive, targetID string, result *types.ImportResult,
+) error {
+	existing, err := store.ListWorkspaces(ctx, targetID)
+	if err != nil {
+		return err
+	}
+	registered := make(map[string]bool, len(existing))
+	for _, ws := range existing {
+		registered[ws.Path] = true
+	}
+
+	kept := a.Workspaces[:0]
+	for _, ws := range a.Workspaces {
+		if registered[ws.Path] {
+			result.SkippedWorkspaces++
+			continue
+		}
+		registered[ws.Path] = true
+		ws.ProjectID = targetID
+		for {
+			if _, err := store.GetWorkspace(ctx, ws.ID); err != nil {
+				break
+			}
+			ws.ID = project.GenerateProjectID("ws", ws.Path)
+		}
+		kept = append(kept, ws)
+	}
+	a.Workspaces = kept
+	return nil
+}
+
+// remapPlans gives plans and plan comments new IDs where theirs are taken.
+func remapPlans(ctx context.Context, store storage.Storage, a *types.ProjectArchive) {
+	planIDs := make(map[string]string, len(a.Plans))
+	for _, plan := range a.Plans {
+		oldID := plan.ID
+		for {
+			if _, err := store.GetPlan(ctx, plan.ID); err != nil {
+				break
+			}
+			plan.ID = project.GeneratePlanID(plan.FilePath)
+		}
+		planIDs[oldID] = plan.ID
+	}
+
+	for _, pc := range a.PlanComments {
+		if newID, ok := planIDs[pc.PlanID]; ok {
+			pc.PlanID = newID
+		}
+		for {
+			if _, err := store.GetPlanComment(ctx, pc.ID); err != nil {
+				break
+			}
+			pc.ID = "pc." + project.GeneratePlanID("comment")
+		}
+	}
+}
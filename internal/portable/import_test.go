@@ -0,0 +1,146 @@
+package portable_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/portable"
+	"github.com/sentiolabs/arc/internal/storage/sqlite"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func newStore(t *testing.T) *sqlite.Store {
+	t.Helper()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+// exportFixture builds a project with a parent, a child, a blocker that
+// mentions the parent, and a workspace, then exports it.
+func exportFixture(t *testing.T, store *sqlite.Store) *types.ProjectArchive {
+	t.Helper()
+	ctx := context.Background()
+
+	proj := &types.Project{Name: "Source", Prefix: "src"}
+	if err := store.CreateProject(ctx, proj); err != nil {
+		t.Fatalf("CreateProject: %v", err)
+	}
+	if err := store.CreateWorkspace(ctx, &types.Workspace{ProjectID: proj.ID, Path: "/work/src"}); err != nil {
+		t.Fatalf("CreateWorkspace: %v", err)
+	}
+
+	parent := &types.Issue{ProjectID: proj.ID, Title: "Parent", Status: types.StatusOpen, IssueType: types.TypeEpic}
+	if err := store.CreateIssue(ctx, parent, "tester"); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	child := &types.Issue{ProjectID: proj.ID, Title: "Child", ParentID: parent.ID,
+		Status: types.StatusOpen, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, child, "tester"); err != nil {
+		t.Fatalf("CreateIssue child: %v", err)
+	}
+	blocker := &types.Issue{ProjectID: proj.ID, Title: "Blocker", Description: "Unblocks " + parent.ID,
+		Status: types.StatusOpen, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, blocker, "tester"); err != nil {
+		t.Fatalf("CreateIssue blocker: %v", err)
+	}
+	dep := &types.Dependency{IssueID: parent.ID, DependsOnID: blocker.ID, Type: types.DepBlocks}
+	if err := store.AddDependency(ctx, dep, "tester"); err != nil {
+		t.Fatalf("AddDependency: %v", err)
+	}
+	if _, err := store.AddComment(ctx, child.ID, "tester", "part of "+parent.ID); err != nil {
+		t.Fatalf("AddComment: %v", err)
+	}
+
+	archive, err := store.ExportProject(ctx, proj.ID)
+	if err != nil {
+		t.Fatalf("ExportProject: %v", err)
+	}
+	return archive
+}
+
+func TestImport_PreserveIDs(t *testing.T) {
+	ctx := context.Background()
+	archive := exportFixture(t, newStore(t))
+	projID := archive.Project.ID
+
+	dst := newStore(t)
+	result, err := portable.Import(ctx, dst, archive, portable.ImportOptions{})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if result.Project.ID != projID || result.Issues != 3 || result.Dependencies != 2 || len(result.IDMap) != 0 {
+		t.Errorf("result = %+v", result)
+	}
+
+	// Importing the same archive again collides with what now exists.
+	_, err = portable.Import(ctx, dst, archive, portable.ImportOptions{})
+	if !errors.Is(err, portable.ErrConflict) {
+		t.Errorf("second import error = %v, want ErrConflict", err)
+	}
+}
+
+func TestImport_IntoTarget(t *testing.T) {
+	ctx := context.Background()
+	archive := exportFixture(t, newStore(t))
+
+	dst := newStore(t)
+	target := &types.Project{Name: "Team", Prefix: "team"}
+	if err := dst.CreateProject(ctx, target); err != nil {
+		t.Fatalf("CreateProject: %v", err)
+	}
+	if err := dst.CreateWorkspace(ctx, &types.Workspace{ProjectID: target.ID, Path: "/work/src"}); err != nil {
+		t.Fatalf("CreateWorkspace: %v", err)
+	}
+
+	var parentID, childID, blockerID string
+	for _, issue := range archive.Issues {
+		switch issue.Title {
+		case "Parent":
+			parentID = issue.ID
+		case "Child":
+			childID = issue.ID
+		case "Blocker":
+			blockerID = issue.ID
+		}
+	}
+
+	result, err := portable.Import(ctx, dst, archive, portable.ImportOptions{TargetProjectID: target.ID})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if result.Project.ID != target.ID || result.Issues != 3 || result.SkippedWorkspaces != 1 {
+		t.Errorf("result = %+v", result)
+	}
+
+	newParent := result.IDMap[parentID]
+	if !strings.HasPrefix(newParent, "team.") {
+		t.Fatalf("parent %s mapped to %q, want team prefix", parentID, newParent)
+	}
+	if want := newParent + ".1"; result.IDMap[childID] != want {
+		t.Errorf("child mapped to %q, want %q", result.IDMap[childID], want)
+	}
+
+	blocker, err := dst.GetIssue(ctx, result.IDMap[blockerID])
+	if err != nil {
+		t.Fatalf("GetIssue blocker: %v", err)
+	}
+	if blocker.ProjectID != target.ID || blocker.Description != "Unblocks "+newParent {
+		t.Errorf("blocker = %+v", blocker)
+	}
+
+	deps, err := dst.GetDependencies(ctx, newParent)
+	if err != nil || len(deps) != 1 || deps[0].DependsOnID != result.IDMap[blockerID] {
+		t.Errorf("parent dependencies = %+v (%v)", deps, err)
+	}
+	comments, err := dst.GetComments(ctx, result.IDMap[childID])
+	if err != nil || len(comments) != 1 || comments[0].Text != "part of "+newParent {
+		t.Errorf("child comments = %+v (%v)", comments, err)
+	}
+}
@@ -0,0 +1,251 @@
+// Package portable reads and writes project archives in a portable JSONL
+// format and imports them with ID-collision handling.
+//
+// An archive is one JSON object per line:
+//
+//	{"type":"header","data":{"format":"arc-project","version":1,"exported_at":"..."}}
+//	{"type":"project","data":{...}}
+//	{"type":"issue","data":{...}}
+//
+// The header comes first and the project second; the remaining records
+// follow in dependency order (workspaces, labels, issues, dependencies,
+// comments, events, plans, plan comments) so a reader can apply them in a
+// single pass.
+package portable
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// Format identifies arc project archives in the header record.
+const Format = "arc-project"
+
+// Version is the archive format version written by Write. Read accepts
+// archives up to this version.
+const Version = 1
+
+// maxLineBytes bounds a single record; issue descriptions can be long.
+const maxLineBytes = 16 << 20
+
+// Record types.
+const (
+	RecordHeader      = "header"
+	RecordProject     = "project"
+	RecordConfig      = "config"
+	RecordWorkspace   = "workspace"
+	RecordLabel       = "label"
+	RecordIssue       = "issue"
+	RecordDependency  = "dependency"
+	RecordComment     = "comment"
+	RecordEvent       = "event"
+	RecordPlan        = "plan"
+	RecordPlanComment = "plan_comment"
+)
+
+// Record is one line of an archive.
+type Record struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Header is the data of the first record.
+type Header struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+}
+
+// configEntry is the data of a config record.
+type configEntry struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Write encodes an archive as JSONL, one record per line.
+func Write(w io.Writer, a *types.ProjectArchive) error {
+	if a.Project == nil {
+		return errors.New("archive has no project")
+	}
+
+	enc := &encoder{bw: bufio.NewWriter(w)}
+	enc.write(RecordHeader, Header{Format: Format, Version: Version, ExportedAt: time.Now().UTC()})
+	enc.write(RecordProject, a.Project)
+	for key, value := range a.Config {
+		enc.write(RecordConfig, configEntry{Key: key, Value: value})
+	}
+	for _, ws := range a.Workspaces {
+		enc.write(RecordWorkspace, ws)
+	}
+	for _, label := range a.Labels {
+		enc.write(RecordLabel, label)
+	}
+	for _, issue := range a.Issues {
+		enc.write(RecordIssue, issue)
+	}
+	for _, dep := range a.Dependencies {
+		enc.write(RecordDependency, dep)
+	}
+	for _, c := range a.Comments {
+		enc.write(RecordComment, c)
+	}
+	for _, e := range a.Events {
+		enc.write(RecordEvent, e)
+	}
+	for _, plan := range a.Plans {
+		enc.write(RecordPlan, plan)
+	}
+	for _, pc := range a.PlanComments {
+		enc.write(RecordPlanComment, pc)
+	}
+
+	if enc.err != nil {
+		return enc.err
+	}
+	return enc.bw.Flush()
+}
+
+// encoder writes records and remembers the first error.
+type encoder struct {
+	bw  *bufio.Writer
+	err error
+}
+
+func (e *encoder) write(recordType string, data any) {
+	if e.err != nil {
+		return
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		e.err = fmt.Errorf("encode %s record: %w", recordType, err)
+		return
+	}
+	line, err := json.Marshal(Record{Type: recordType, Data: raw})
+	if err != nil {
+		e.err = fmt.Errorf("encode %s record: %w", recordType, err)
+		return
+	}
+	if _, err := e.bw.Write(append(line, '\n')); err != nil {
+		e.err = err
+	}
+}
+
+// Read decodes a JSONL archive. Blank lines are ignored; unknown record
+// types are an error so that data is never silently dropped.
+//
+//nolint:gocyclo,cyclop // one case per record type
+func Read(r io.Reader) (*types.ProjectArchive, error) {
+	a := &types.ProjectArchive{}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
+
+	line := 0
+	sawHeader := false
+	for sc.Scan() {
+		line++
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+
+		var rec Record
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if !sawHeader && rec.Type != RecordHeader {
+			return nil, fmt.Errorf("line %d: expected %s record, got %q", line, RecordHeader, rec.Type)
+		}
+
+		var err error
+		switch rec.Type {
+		case RecordHeader:
+			err = readHeader(rec.Data, sawHeader)
+			sawHeader = true
+		case RecordProject:
+			if a.Project != nil {
+				err = errors.New("duplicate project record")
+				break
+			}
+			err = decodeInto(rec.Data, &a.Project)
+		case RecordConfig:
+			var entry configEntry
+			if err = json.Unmarshal(rec.Data, &entry); err == nil {
+				if a.Config == nil {
+					a.Config = make(map[string]string)
+				}
+				a.Config[entry.Key] = entry.Value
+			}
+		case RecordWorkspace:
+			a.Workspaces, err = appendDecoded(a.Workspaces, rec.Data)
+		case RecordLabel:
+			a.Labels, err = appendDecoded(a.Labels, rec.Data)
+		case RecordIssue:
+			a.Issues, err = appendDecoded(a.Issues, rec.Data)
+		case RecordDependency:
+			a.Dependencies, err = appendDecoded(a.Dependencies, rec.Data)
+		case RecordComment:
+			a.Comments, err = appendDecoded(a.Comments, rec.Data)
+		case RecordEvent:
+			a.Events, err = appendDecoded(a.Events, rec.Data)
+		case RecordPlan:
+			a.Plans, err = appendDecoded(a.Plans, rec.Data)
+		case RecordPlanComment:
+			a.PlanComments, err = appendDecoded(a.PlanComments, rec.Data)
+		default:
+			err = fmt.Errorf("unknown record type %q", rec.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read archive: %w", err)
+	}
+
+	if !sawHeader {
+		return nil, errors.New("empty archive")
+	}
+	if a.Project == nil {
+		return nil, errors.New("archive has no project record")
+	}
+	return a, nil
+}
+
+func readHeader(data json.RawMessage, seen bool) error {
+	if seen {
+		return errors.New("duplicate header record")
+	}
+	var h Header
+	if err := json.Unmarshal(data, &h); err != nil {
+		return err
+	}
+	if h.Format != Format {
+		return fmt.Errorf("not an arc project archive (format %q)", h.Format)
+	}
+	if h.Version < 1 || h.Version > Version {
+		return fmt.Errorf("unsupported archive version %d (this arc reads up to %d)", h.Version, Version)
+	}
+	return nil
+}
+
+func decodeInto[T any](data json.RawMessage, dst **T) error {
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*dst = &v
+	return nil
+}
+
+func appendDecoded[T any](list []*T, data json.RawMessage) ([]*T, error) {
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return list, err
+	}
+	return append(list, &v), nil
+}
@@ -0,0 +1,86 @@
+package portable_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/portable"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func TestWriteRead_RoundTrip(t *testing.T) {
+	desc := "see arc.aaaaaa"
+	archive := &types.ProjectArchive{
+		Project:    &types.Project{ID: "arc-k3x9f2", Name: "Arc", Prefix: "arc"},
+		Config:     map[string]string{"share.author": "alice"},
+		Workspaces: []*types.Workspace{{ID: "ws-1", ProjectID: "arc-k3x9f2", Path: "/work/arc"}},
+		Labels:     []*types.Label{{Name: "backend"}},
+		Issues: []*types.Issue{
+			{ID: "arc.aaaaaa", Title: "One", Labels: []string{"backend"}},
+			{ID: "arc.bbbbbb", Title: "Two", Description: desc},
+		},
+		Dependencies: []*types.Dependency{{IssueID: "arc.bbbbbb", DependsOnID: "arc.aaaaaa", Type: types.DepBlocks}},
+		Comments:     []*types.Comment{{IssueID: "arc.aaaaaa", Author: "alice", Text: "hi"}},
+		Events:       []*types.Event{{IssueID: "arc.aaaaaa", EventType: types.EventCreated, Actor: "alice"}},
+		Plans:        []*types.Plan{{ID: "plan.abc123", FilePath: "/work/arc/plan.md", Status: "draft"}},
+		PlanComments: []*types.PlanComment{{ID: "pc.1", PlanID: "plan.abc123", Content: "ok"}},
+	}
+
+	var buf bytes.Buffer
+	if err := portable.Write(&buf, archive); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 12 {
+		t.Fatalf("got %d records, want 12", len(lines))
+	}
+	if !strings.HasPrefix(lines[0], `{"type":"header"`) || !strings.HasPrefix(lines[1], `{"type":"project"`) {
+		t.Errorf("first records = %s, %s", lines[0], lines[1])
+	}
+
+	got, err := portable.Read(&buf)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if got.Project.ID != "arc-k3x9f2" || got.Config["share.author"] != "alice" {
+		t.Errorf("project/config = %+v %v", got.Project, got.Config)
+	}
+	if len(got.Issues) != 2 || got.Issues[1].Description != desc || got.Issues[0].Labels[0] != "backend" {
+		t.Errorf("issues = %+v", got.Issues)
+	}
+	if len(got.Dependencies) != 1 || len(got.Comments) != 1 || len(got.Events) != 1 ||
+		len(got.Workspaces) != 1 || len(got.Labels) != 1 || len(got.Plans) != 1 || len(got.PlanComments) != 1 {
+		t.Errorf("record counts do not round-trip: %+v", got)
+	}
+}
+
+func TestRead_Errors(t *testing.T) {
+	header := `{"type":"header","data":{"format":"arc-project","version":1}}` + "\n"
+	project := `{"type":"project","data":{"id":"arc-1","name":"Arc","prefix":"arc"}}` + "\n"
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"empty", "", "empty archive"},
+		{"missing header", project, "line 1: expected header record"},
+		{"wrong format", `{"type":"header","data":{"format":"other","version":1}}`, "not an arc project archive"},
+		{"future version", `{"type":"header","data":{"format":"arc-project","version":99}}`, "unsupported archive version"},
+		{"no project", header, "no project record"},
+		{"unknown type", header + project + `{"type":"widget","data":{}}`, `line 3: unknown record type "widget"`},
+		{"bad json", header + "{not json", "line 2:"},
+		{"duplicate project", header + project + project, "line 3: duplicate project record"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := portable.Read(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Read() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -12,7 +12,13 @@ import (
 	"time"
 
 	"github.com/sentiolabs/arc/internal/api"
+	"github.com/sentiolabs/arc/internal/config"
+	"github.com/sentiolabs/arc/internal/events"
+	"github.com/sentiolabs/arc/internal/issuetmpl"
+	"github.com/sentiolabs/arc/internal/storage"
+	"github.com/sentiolabs/arc/internal/storage/postgres"
 	"github.com/sentiolabs/arc/internal/storage/sqlite"
+	"github.com/sentiolabs/arc/internal/webhooks"
 )
 
 // Server lifecycle constants.
@@ -25,6 +31,13 @@ const (
 type Config struct {
 	Address string // Server address (e.g., ":7432")
 	DBPath  string // Database path (empty for default)
+	// DBDriver selects the storage backend ("sqlite" or "postgres"); empty
+	// means SQLite. DSN is the PostgreSQL connection string.
+	DBDriver string
+	DSN      string
+	MCP      bool // Serve the MCP streamable-HTTP endpoint at /mcp
+	// RequireAuth rejects API and MCP requests without a valid bearer token.
+	RequireAuth bool
 }
 
 // DefaultDataDir returns the default data directory (~/.arc).
@@ -48,6 +61,24 @@ func LogPath() string {
 	return filepath.Join(DefaultDataDir(), "server.log")
 }
 
+// notifyingStore is a storage backend that can publish change notifications.
+type notifyingStore interface {
+	storage.Storage
+	storage.Notifier
+}
+
+// openStore opens the storage backend selected by cfg.DBDriver.
+func openStore(cfg Config) (notifyingStore, error) {
+	switch cfg.DBDriver {
+	case "", config.DBDriverSQLite:
+		return sqlite.New(cfg.DBPath)
+	case config.DBDriverPostgres:
+		return postgres.New(cfg.DSN)
+	default:
+		return nil, fmt.Errorf("unknown database driver %q", cfg.DBDriver)
+	}
+}
+
 // Run starts the server and blocks until shutdown.
 // It handles graceful shutdown on SIGINT/SIGTERM.
 func Run(cfg Config) error {
@@ -57,16 +88,30 @@ func Run(cfg Config) error {
 	}
 
 	// Initialize storage
-	store, err := sqlite.New(cfg.DBPath)
+	store, err := openStore(cfg)
 	if err != nil {
 		return fmt.Errorf("initialize storage: %w", err)
 	}
 	defer store.Close()
 
+	// Change notifications feed both the event stream and outbound webhooks.
+	// Recurring issue templates share the background context.
+	bus := events.NewBus()
+	store.SetPublisher(bus)
+	dispatcher := webhooks.New(store, webhooks.Options{})
+	dispatchCtx, stopDispatch := context.WithCancel(context.Background())
+	defer stopDispatch()
+	go dispatcher.Run(dispatchCtx, bus)
+	go issuetmpl.NewScheduler(store, 0, nil).Run(dispatchCtx)
+
 	// Create API server
 	server := api.New(api.ServerOptions{
-		Address: cfg.Address,
-		Store:   store,
+		Address:     cfg.Address,
+		Store:       store,
+		MCP:         cfg.MCP,
+		Events:      bus,
+		Webhooks:    dispatcher,
+		RequireAuth: cfg.RequireAuth,
 	})
 
 	// Start server in goroutine
@@ -74,6 +119,9 @@ func Run(cfg Config) error {
 	go func() {
 		log.Printf("Starting arc server on %s", cfg.Address)
 		log.Printf("Database: %s", store.Path())
+		if cfg.RequireAuth {
+			log.Printf("API authentication required")
+		}
 		if err := server.Start(); err != nil {
 			errCh <- err
 		}
@@ -0,0 +1,197 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// CreateAISession creates a new AI session record.
+func (s *Store) CreateAISession(_ context.Context, session *types.AISession) error {
+	s.lock()
+	defer s.unlock()
+
+	if _, ok := s.projects[session.ProjectID]; !ok {
+		return fmt.Errorf("create ai session: project not found: %s", session.ProjectID)
+	}
+	if _, ok := s.sessions[session.ID]; ok {
+		return fmt.Errorf("create ai session: ai session already exists: %s", session.ID)
+	}
+	stored := *session
+	s.sessions[session.ID] = &stored
+
+	s.publish(types.StreamEvent{
+		Type:      types.StreamAISessionCreated,
+		ProjectID: session.ProjectID,
+		SessionID: session.ID,
+	})
+	return nil
+}
+
+// GetAISession retrieves an AI session by ID.
+func (s *Store) GetAISession(_ context.Context, id string) (*types.AISession, error) {
+	s.lock()
+	defer s.unlock()
+
+	session, ok := s.sessions[id]
+	if !ok {
+		return nil, fmt.Errorf("ai session not found: %s", id)
+	}
+	out := *session
+	return &out, nil
+}
+
+// ListAISessionsByProject returns AI sessions for a project ordered by started_at descending.
+func (s *Store) ListAISessionsByProject(
+	_ context.Context, projectID string, limit, offset int,
+) ([]*types.AISession, error) {
+	s.lock()
+	defer s.unlock()
+
+	sessions := []*types.AISession{}
+	for _, session := range s.sessions {
+		if session.ProjectID == projectID {
+			out := *session
+			sessions = append(sessions, &out)
+		}
+	}
+	slices.SortFunc(sessions, func(a, b *types.AISession) int { return b.StartedAt.Compare(a.StartedAt) })
+
+	offset = min(max(offset, 0), len(sessions))
+	sessions = sessions[offset:]
+	if limit >= 0 && len(sessions) > limit {
+		sessions = sessions[:limit]
+	}
+	return sessions, nil
+}
+
+// CountAISessionsByProject returns the total number of AI sessions for a project.
+func (s *Store) CountAISessionsByProject(_ context.Context, projectID string) (int64, error) {
+	s.lock()
+	defer s.unlock()
+
+	var count int64
+	for _, session := range s.sessions {
+		if session.ProjectID == projectID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// DeleteAISession deletes an AI session and its agents.
+func (s *Store) DeleteAISession(_ context.Context, id string) error {
+	s.lock()
+	defer s.unlock()
+
+	s.deleteAISession(id)
+	return nil
+}
+
+func (s *Store) deleteAISession(id string) {
+	s.agents = slices.DeleteFunc(s.agents, func(a *types.AIAgent) bool { return a.SessionID == id })
+	delete(s.sessions, id)
+}
+
+// CreateAIAgent creates a new AI agent record.
+func (s *Store) CreateAIAgent(_ context.Context, agent *types.AIAgent) error {
+	s.lock()
+	defer s.unlock()
+
+	session, ok := s.sessions[agent.SessionID]
+	if !ok {
+		return fmt.Errorf("create ai agent: ai session not found: %s", agent.SessionID)
+	}
+	if s.agentIndex(agent.ID) >= 0 {
+		return fmt.Errorf("create ai agent: ai agent already exists: %s", agent.ID)
+	}
+	s.agents = append(s.agents, cloneAIAgent(agent))
+
+	s.publish(types.StreamEvent{
+		Type:      types.StreamAIAgentRegistered,
+		ProjectID: session.ProjectID,
+		SessionID: agent.SessionID,
+		Data:      map[string]any{"agent_id": agent.ID, "status": agent.Status},
+	})
+	return nil
+}
+
+// GetAIAgent retrieves an AI agent by ID.
+func (s *Store) GetAIAgent(_ context.Context, id string) (*types.AIAgent, error) {
+	s.lock()
+	defer s.unlock()
+
+	i := s.agentIndex(id)
+	if i < 0 {
+		return nil, fmt.Errorf("ai agent not found: %s", id)
+	}
+	return cloneAIAgent(s.agents[i]), nil
+}
+
+// ListAIAgents returns AI agents for a session ordered by created_at ascending.
+func (s *Store) ListAIAgents(_ context.Context, sessionID string) ([]*types.AIAgent, error) {
+	s.lock()
+	defer s.unlock()
+
+	agents := []*types.AIAgent{}
+	for _, agent := range s.agents {
+		if agent.SessionID == sessionID {
+			agents = append(agents, cloneAIAgent(agent))
+		}
+	}
+	slices.SortStableFunc(agents, func(a, b *types.AIAgent) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return agents, nil
+}
+
+// GetAgentSummariesForSessions returns aggregated agent status counts for each session.
+func (s *Store) GetAgentSummariesForSessions(
+	_ context.Context, sessionIDs []string,
+) (map[string]*types.AgentSummary, error) {
+	s.lock()
+	defer s.unlock()
+
+	result := make(map[string]*types.AgentSummary)
+	for _, agent := range s.agents {
+		if !slices.Contains(sessionIDs, agent.SessionID) {
+			continue
+		}
+		summary := result[agent.SessionID]
+		if summary == nil {
+			summary = &types.AgentSummary{}
+			result[agent.SessionID] = summary
+		}
+		summary.AgentCount++
+		switch agent.Status {
+		case "running":
+			summary.RunningCount++
+		case "completed":
+			summary.CompletedCount++
+		case "error":
+			summary.ErrorCount++
+		}
+	}
+	return result, nil
+}
+
+func (s *Store) agentIndex(id string) int {
+	return slices.IndexFunc(s.agents, func(a *types.AIAgent) bool { return a.ID == id })
+}
+
+func cloneAIAgent(agent *types.AIAgent) *types.AIAgent {
+	out := *agent
+	out.DurationMs = cloneInt(agent.DurationMs)
+	out.TotalTokens = cloneInt(agent.TotalTokens)
+	out.ToolUseCount = cloneInt(agent.ToolUseCount)
+	return &out
+}
+
+// cloneInt copies an optional integer.
+func cloneInt(p *int) *int {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
@@ -0,0 +1,121 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/project"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// CreateAPIToken stores a token under its hash. The plaintext tok.Token is
+// never kept.
+func (s *Store) CreateAPIToken(_ context.Context, tok *types.APIToken, tokenHash string) error {
+	if tok.Name == "" {
+		return errors.New("token name is required")
+	}
+	if tok.Actor == "" {
+		return errors.New("token actor is required")
+	}
+
+	s.lock()
+	defer s.unlock()
+
+	if tok.ID == "" {
+		tok.ID = project.GenerateProjectID("tok", tok.Actor+tok.Name)
+	}
+	if _, ok := s.tokens[tok.ID]; ok {
+		return fmt.Errorf("create api token: api token already exists: %s", tok.ID)
+	}
+	for _, other := range s.tokens {
+		if other.hash == tokenHash {
+			return errors.New("create api token: token hash already exists")
+		}
+	}
+	tok.CreatedAt = time.Now()
+
+	stored := &apiToken{APIToken: *cloneAPIToken(tok), hash: tokenHash}
+	stored.Token = ""
+	s.tokens[tok.ID] = stored
+	return nil
+}
+
+// GetAPIToken retrieves a token's metadata by ID.
+func (s *Store) GetAPIToken(_ context.Context, id string) (*types.APIToken, error) {
+	s.lock()
+	defer s.unlock()
+
+	tok, ok := s.tokens[id]
+	if !ok {
+		return nil, fmt.Errorf("api token not found: %s", id)
+	}
+	return cloneAPIToken(&tok.APIToken), nil
+}
+
+// GetAPITokenByHash looks a token up by the hash of its plaintext.
+// Revoked tokens are returned too; callers must check RevokedAt.
+func (s *Store) GetAPITokenByHash(_ context.Context, tokenHash string) (*types.APIToken, error) {
+	s.lock()
+	defer s.unlock()
+
+	for _, tok := range s.tokens {
+		if tok.hash == tokenHash {
+			return cloneAPIToken(&tok.APIToken), nil
+		}
+	}
+	return nil, errors.New("api token not found")
+}
+
+// ListAPITokens returns all tokens, including revoked ones, oldest first.
+func (s *Store) ListAPITokens(_ context.Context) ([]*types.APIToken, error) {
+	s.lock()
+	defer s.unlock()
+
+	tokens := make([]*types.APIToken, 0, len(s.tokens))
+	for _, tok := range s.tokens {
+		tokens = append(tokens, cloneAPIToken(&tok.APIToken))
+	}
+	slices.SortFunc(tokens, func(a, b *types.APIToken) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), strings.Compare(a.ID, b.ID))
+	})
+	return tokens, nil
+}
+
+// RevokeAPIToken marks a token revoked. Revoking twice is a no-op.
+func (s *Store) RevokeAPIToken(_ context.Context, id string) error {
+	s.lock()
+	defer s.unlock()
+
+	tok, ok := s.tokens[id]
+	if !ok {
+		return fmt.Errorf("api token not found: %s", id)
+	}
+	if tok.RevokedAt == nil {
+		now := time.Now()
+		tok.RevokedAt = &now
+	}
+	return nil
+}
+
+// TouchAPIToken records when a token was last used.
+func (s *Store) TouchAPIToken(_ context.Context, id string, at time.Time) error {
+	s.lock()
+	defer s.unlock()
+
+	if tok, ok := s.tokens[id]; ok {
+		tok.LastUsedAt = &at
+	}
+	return nil
+}
+
+func cloneAPIToken(tok *types.APIToken) *types.APIToken {
+	out := *tok
+	out.LastUsedAt = cloneTime(tok.LastUsedAt)
+	out.RevokedAt = cloneTime(tok.RevokedAt)
+	return &out
+}
@@ -0,0 +1,358 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/depgraph"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// ExportProject reads a complete copy of a project. Dependencies are
+// included only when both ends belong to the project.
+func (s *Store) ExportProject(_ context.Context, projectID string) (*types.ProjectArchive, error) {
+	s.lock()
+	defer s.unlock()
+
+	proj, ok := s.projects[projectID]
+	if !ok {
+		return nil, fmt.Errorf("project not found: %s", projectID)
+	}
+	p := *proj
+	a := &types.ProjectArchive{
+		Project:      &p,
+		Config:       s.projectConfig(projectID),
+		Workspaces:   s.listWorkspaces(projectID),
+		Issues:       s.exportIssues(projectID),
+		Dependencies: []*types.Dependency{},
+		Comments:     []*types.Comment{},
+		Events:       []*types.Event{},
+	}
+	a.Labels = s.exportLabels(a.Issues)
+
+	inProject := func(id string) bool { return s.issueProjectID(id) == projectID }
+	for _, d := range s.dependencies {
+		if inProject(d.IssueID) && inProject(d.DependsOnID) {
+			dep := *d
+			a.Dependencies = append(a.Dependencies, &dep)
+		}
+	}
+	for _, c := range s.comments {
+		if inProject(c.IssueID) {
+			comment := *c
+			a.Comments = append(a.Comments, &comment)
+		}
+	}
+	for _, e := range s.events {
+		if inProject(e.IssueID) {
+			a.Events = append(a.Events, cloneEvent(e))
+		}
+	}
+
+	a.Plans = s.exportPlans(a.Workspaces)
+	for _, plan := range a.Plans {
+		a.PlanComments = append(a.PlanComments, s.listPlanComments(plan.ID)...)
+	}
+
+	return a, nil
+}
+
+// exportIssues returns a project's issues, oldest first, with their labels
+// and custom fields.
+func (s *Store) exportIssues(projectID string) []*types.Issue {
+	issues := []*types.Issue{}
+	for _, issue := range s.issues {
+		if issue.ProjectID != projectID {
+			continue
+		}
+		out := cloneIssue(issue)
+		out.Version = 0 // versions are local to a store and not archived
+		if len(s.issueLabels[issue.ID]) > 0 {
+			out.Labels = s.labelsOf(issue.ID)
+		}
+		if len(s.issueFields[issue.ID]) > 0 {
+			out.Fields = maps.Clone(s.issueFields[issue.ID])
+		}
+		issues = append(issues, out)
+	}
+	slices.SortFunc(issues, func(a, b *types.Issue) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), strings.Compare(a.ID, b.ID))
+	})
+	return issues
+}
+
+// exportLabels returns the definitions of every label used by issues.
+func (s *Store) exportLabels(issues []*types.Issue) []*types.Label {
+	seen := make(map[string]bool)
+	labels := []*types.Label{}
+	for _, issue := range issues {
+		for _, name := range issue.Labels {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			label := &types.Label{Name: name} // labels can be attached without a definition
+			if def, ok := s.labels[name]; ok {
+				*label = *def
+			}
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// exportPlans returns plans whose file lives under one of the workspaces.
+func (s *Store) exportPlans(workspaces []*types.Workspace) []*types.Plan {
+	plans := []*types.Plan{}
+	for _, plan := range s.plans {
+		for _, ws := range workspaces {
+			if pathWithin(plan.FilePath, ws.Path) {
+				out := *plan
+				plans = append(plans, &out)
+				break
+			}
+		}
+	}
+	slices.SortFunc(plans, func(a, b *types.Plan) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), strings.Compare(a.ID, b.ID))
+	})
+	return plans
+}
+
+// pathWithin reports whether path is dir or lies beneath it.
+func pathWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// ImportProject writes an archive, keeping every ID and timestamp as given.
+// The project is created unless it already exists, in which case the
+// archive is added to it. Comment and event IDs are reassigned. Any
+// conflict with existing records aborts the whole import before anything
+// is written.
+func (s *Store) ImportProject(_ context.Context, a *types.ProjectArchive) error {
+	if a.Project == nil {
+		return errors.New("archive has no project")
+	}
+	if err := a.Project.Validate(); err != nil {
+		return fmt.Errorf("validate project: %w", err)
+	}
+	pID := a.Project.ID
+	for _, issue := range a.Issues {
+		issue.ProjectID = pID
+		if err := issue.Validate(); err != nil {
+			return fmt.Errorf("validate issue %s: %w", issue.ID, err)
+		}
+	}
+
+	s.lock()
+	defer s.unlock()
+
+	if err := s.checkImport(a); err != nil {
+		return err
+	}
+	s.importProjectRecords(a)
+	return nil
+}
+
+// checkImport reports the first archive record that would conflict with
+// the store or with another record of the archive.
+//
+//nolint:gocognit // one linear pass over each record kind
+func (s *Store) checkImport(a *types.ProjectArchive) error {
+	p := a.Project
+	if _, exists := s.projects[p.ID]; !exists {
+		if s.projectByName(p.Name) != nil {
+			return fmt.Errorf("import project: project name already exists: %s", p.Name)
+		}
+	}
+
+	paths := make(map[string]bool)
+	ids := make(map[string]bool)
+	for _, ws := range a.Workspaces {
+		if _, ok := s.workspaces[ws.ID]; ok || ids[ws.ID] {
+			return fmt.Errorf("import workspace %s: workspace already exists: %s", ws.Path, ws.ID)
+		}
+		if paths[ws.Path] || slices.ContainsFunc(s.listWorkspaces(p.ID), func(o *types.Workspace) bool {
+			return o.Path == ws.Path
+		}) {
+			return fmt.Errorf("import workspace %s: path already registered for project", ws.Path)
+		}
+		ids[ws.ID], paths[ws.Path] = true, true
+	}
+
+	imported := make(map[string]bool, len(a.Issues))
+	for _, issue := range a.Issues {
+		if _, ok := s.issues[issue.ID]; ok || imported[issue.ID] {
+			return fmt.Errorf("import issue %s: issue already exists", issue.ID)
+		}
+		imported[issue.ID] = true
+	}
+	issueExists := func(id string) bool {
+		_, ok := s.issues[id]
+		return ok || imported[id]
+	}
+
+	deps := make(map[[2]string]bool)
+	for _, dep := range a.Dependencies {
+		key := [2]string{dep.IssueID, dep.DependsOnID}
+		if !issueExists(dep.IssueID) || !issueExists(dep.DependsOnID) {
+			return fmt.Errorf("import dependency %s -> %s: issue not found", dep.IssueID, dep.DependsOnID)
+		}
+		if deps[key] || s.dependencyIndex(dep.IssueID, dep.DependsOnID) >= 0 {
+			return fmt.Errorf("import dependency %s -> %s: dependency already exists", dep.IssueID, dep.DependsOnID)
+		}
+		deps[key] = true
+	}
+	for _, c := range a.Comments {
+		if !issueExists(c.IssueID) {
+			return fmt.Errorf("import comment on %s: issue not found", c.IssueID)
+		}
+	}
+	for _, e := range a.Events {
+		if !issueExists(e.IssueID) {
+			return fmt.Errorf("import event on %s: issue not found", e.IssueID)
+		}
+	}
+
+	plans := make(map[string]bool, len(a.Plans))
+	for _, plan := range a.Plans {
+		if _, ok := s.plans[plan.ID]; ok || plans[plan.ID] {
+			return fmt.Errorf("import plan %s: plan already exists", plan.ID)
+		}
+		plans[plan.ID] = true
+	}
+	planComments := make(map[string]bool, len(a.PlanComments))
+	for _, pc := range a.PlanComments {
+		if _, ok := s.plans[pc.PlanID]; !ok && !plans[pc.PlanID] {
+			return fmt.Errorf("import plan comment %s: plan not found: %s", pc.ID, pc.PlanID)
+		}
+		if s.planCommentIndex(pc.ID) >= 0 || planComments[pc.ID] {
+			return fmt.Errorf("import plan comment %s: plan comment already exists", pc.ID)
+		}
+		planComments[pc.ID] = true
+	}
+	return nil
+}
+
+// importProjectRecords writes an archive that passed checkImport.
+//
+//nolint:gocognit,funlen // one linear pass over each record kind
+func (s *Store) importProjectRecords(a *types.ProjectArchive) {
+	p := a.Project
+	now := time.Now()
+
+	if _, exists := s.projects[p.ID]; !exists {
+		proj := *p
+		proj.CreatedAt = orNow(p.CreatedAt, now)
+		proj.UpdatedAt = orNow(p.UpdatedAt, now)
+		s.projects[p.ID] = &proj
+	}
+
+	if len(a.Config) > 0 && s.config[p.ID] == nil {
+		s.config[p.ID] = make(map[string]string)
+	}
+	for key, value := range a.Config {
+		if _, ok := s.config[p.ID][key]; !ok {
+			s.config[p.ID][key] = value
+		}
+	}
+
+	for _, ws := range a.Workspaces {
+		stored := cloneWorkspace(ws)
+		stored.ProjectID = p.ID
+		if stored.PathType == "" {
+			stored.PathType = "canonical"
+		}
+		stored.CreatedAt = orNow(ws.CreatedAt, now)
+		stored.UpdatedAt = orNow(ws.UpdatedAt, now)
+		s.workspaces[ws.ID] = stored
+	}
+
+	for _, label := range a.Labels {
+		if _, ok := s.labels[label.Name]; !ok {
+			stored := *label
+			s.labels[label.Name] = &stored
+		}
+	}
+
+	imported := make(map[string]bool, len(a.Issues))
+	for _, issue := range a.Issues {
+		imported[issue.ID] = true
+	}
+	for _, issue := range a.Issues {
+		stored := cloneIssue(issue)
+		stored.CreatedAt = orNow(issue.CreatedAt, now)
+		stored.UpdatedAt = orNow(issue.UpdatedAt, now)
+		stored.Version = 1
+		s.issues[issue.ID] = stored
+
+		for _, label := range issue.Labels {
+			if s.issueLabels[issue.ID] == nil {
+				s.issueLabels[issue.ID] = make(map[string]bool)
+			}
+			s.issueLabels[issue.ID][label] = true
+		}
+		if len(issue.Fields) > 0 {
+			s.issueFields[issue.ID] = maps.Clone(issue.Fields)
+		}
+
+		// Keep child ID generation from reusing imported child numbers.
+		if parentID := depgraph.HierarchicalParent(issue.ID); parentID != "" && imported[parentID] {
+			n, _ := strconv.Atoi(issue.ID[len(parentID)+1:])
+			s.childCounters[parentID] = max(s.childCounters[parentID], n)
+		}
+	}
+
+	for _, dep := range a.Dependencies {
+		stored := *dep
+		stored.CreatedAt = orNow(dep.CreatedAt, now)
+		s.dependencies = append(s.dependencies, &stored)
+	}
+
+	for _, c := range a.Comments {
+		s.lastCommentID++
+		stored := *c
+		stored.ID = s.lastCommentID
+		stored.CreatedAt = orNow(c.CreatedAt, now)
+		s.comments = append(s.comments, &stored)
+	}
+
+	for _, e := range a.Events {
+		s.lastEventID++
+		stored := cloneEvent(e)
+		stored.ID = s.lastEventID
+		stored.CreatedAt = orNow(e.CreatedAt, now)
+		s.events = append(s.events, stored)
+	}
+
+	for _, plan := range a.Plans {
+		stored := *plan
+		stored.CreatedAt = orNow(plan.CreatedAt, now)
+		stored.UpdatedAt = orNow(plan.UpdatedAt, now)
+		s.plans[plan.ID] = &stored
+	}
+
+	for _, pc := range a.PlanComments {
+		stored := clonePlanComment(pc)
+		stored.CreatedAt = orNow(pc.CreatedAt, now)
+		s.planComments = append(s.planComments, stored)
+	}
+}
+
+// orNow substitutes fallback for a zero timestamp.
+func orNow(t, fallback time.Time) time.Time {
+	if t.IsZero() {
+		return fallback
+	}
+	return t
+}
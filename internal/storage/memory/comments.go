@@ -0,0 +1,161 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// AddComment adds a comment to an issue and records a corresponding event.
+func (s *Store) AddComment(_ context.Context, issueID, author, text string) (*types.Comment, error) {
+	s.lock()
+	defer s.unlock()
+
+	if _, ok := s.issues[issueID]; !ok {
+		return nil, fmt.Errorf("add comment: issue not found: %s", issueID)
+	}
+
+	s.lastCommentID++
+	comment := &types.Comment{
+		ID:        s.lastCommentID,
+		IssueID:   issueID,
+		Author:    author,
+		Text:      text,
+		CreatedAt: time.Now(),
+	}
+	stored := *comment
+	s.comments = append(s.comments, &stored)
+
+	s.recordEvent(issueID, types.EventCommented, author, nil, &text)
+
+	return comment, nil
+}
+
+// GetComments returns all comments for an issue.
+func (s *Store) GetComments(_ context.Context, issueID string) ([]*types.Comment, error) {
+	s.lock()
+	defer s.unlock()
+
+	return s.issueComments(issueID), nil
+}
+
+// issueComments returns copies of an issue's comments, oldest first.
+func (s *Store) issueComments(issueID string) []*types.Comment {
+	comments := []*types.Comment{}
+	for _, c := range s.comments {
+		if c.IssueID == issueID {
+			out := *c
+			comments = append(comments, &out)
+		}
+	}
+	return comments
+}
+
+// UpdateComment updates a comment's text.
+func (s *Store) UpdateComment(_ context.Context, commentID int64, text string) error {
+	s.lock()
+	defer s.unlock()
+
+	i := s.commentIndex(commentID)
+	if i < 0 {
+		return nil
+	}
+	c := s.comments[i]
+	c.Text = text
+	c.UpdatedAt = time.Now()
+
+	s.publishCommentChange(types.StreamCommentUpdated, c.IssueID, commentID)
+	return nil
+}
+
+// DeleteComment deletes a comment.
+func (s *Store) DeleteComment(_ context.Context, commentID int64) error {
+	s.lock()
+	defer s.unlock()
+
+	i := s.commentIndex(commentID)
+	if i < 0 {
+		return nil
+	}
+	issueID := s.comments[i].IssueID
+	s.comments = slices.Delete(s.comments, i, i+1)
+
+	s.publishCommentChange(types.StreamCommentDeleted, issueID, commentID)
+	return nil
+}
+
+func (s *Store) commentIndex(id int64) int {
+	return slices.IndexFunc(s.comments, func(c *types.Comment) bool { return c.ID == id })
+}
+
+// publishCommentChange notifies subscribers that an existing comment changed.
+func (s *Store) publishCommentChange(evType types.StreamEventType, issueID string, commentID int64) {
+	s.publish(types.StreamEvent{
+		Type:      evType,
+		ProjectID: s.issueProjectID(issueID),
+		IssueID:   issueID,
+		Data:      map[string]any{"comment_id": commentID},
+	})
+}
+
+// GetEvents returns the event history for an issue, newest first.
+// Defaults to a limit of 50 events if limit is zero or negative.
+func (s *Store) GetEvents(_ context.Context, issueID string, limit int) ([]*types.Event, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	s.lock()
+	defer s.unlock()
+
+	events := []*types.Event{}
+	for i := len(s.events) - 1; i >= 0 && len(events) < limit; i-- {
+		if e := s.events[i]; e.IssueID == issueID {
+			events = append(events, cloneEvent(e))
+		}
+	}
+	return events, nil
+}
+
+// GetRecentEvents returns the latest events on a project's issues, newest
+// first. Defaults to a limit of 50 events if limit is zero or negative.
+func (s *Store) GetRecentEvents(_ context.Context, projectID string, limit int) ([]*types.Event, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	s.lock()
+	defer s.unlock()
+
+	events := []*types.Event{}
+	for i := len(s.events) - 1; i >= 0 && len(events) < limit; i-- {
+		if e := s.events[i]; s.issueProjectID(e.IssueID) == projectID {
+			events = append(events, cloneEvent(e))
+		}
+	}
+	return events, nil
+}
+
+// AddEvent records an event on an existing issue in the audit trail and
+// publishes it like any other change.
+func (s *Store) AddEvent(_ context.Context, event *types.Event) error {
+	s.lock()
+	defer s.unlock()
+
+	if _, ok := s.issues[event.IssueID]; !ok {
+		return fmt.Errorf("issue not found: %s", event.IssueID)
+	}
+	s.recordEvent(event.IssueID, event.EventType, event.Actor, event.OldValue, event.NewValue)
+	return nil
+}
+
+func cloneEvent(e *types.Event) *types.Event {
+	out := *e
+	out.OldValue = nonEmpty(e.OldValue)
+	out.NewValue = nonEmpty(e.NewValue)
+	out.Comment = nonEmpty(e.Comment)
+	return &out
+}
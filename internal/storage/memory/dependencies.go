@@ -0,0 +1,153 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/depgraph"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// AddDependency adds a dependency between two issues.
+// It validates that the issue does not depend on itself, that the dependency type is valid,
+// and that a blocking dependency would not close a cycle.
+func (s *Store) AddDependency(_ context.Context, dep *types.Dependency, actor string) error {
+	s.lock()
+	defer s.unlock()
+
+	return s.addDependency(dep, actor)
+}
+
+func (s *Store) addDependency(dep *types.Dependency, actor string) error {
+	if dep.IssueID == dep.DependsOnID {
+		return errors.New("issue cannot depend on itself")
+	}
+
+	if !dep.Type.IsValid() {
+		return fmt.Errorf("invalid dependency type: %s", dep.Type)
+	}
+
+	for _, id := range []string{dep.IssueID, dep.DependsOnID} {
+		if _, ok := s.issues[id]; !ok {
+			return fmt.Errorf("add dependency: issue not found: %s", id)
+		}
+	}
+
+	blockers := depgraph.Blockers(context.Background(), func(_ context.Context, id string) ([]*types.Dependency, error) {
+		return s.queryDependencies(func(d *types.Dependency) bool { return d.IssueID == id }), nil
+	})
+	if err := depgraph.CheckCycle(dep, blockers); err != nil {
+		return err
+	}
+
+	dep.CreatedAt = time.Now()
+	dep.CreatedBy = actor
+
+	stored := *dep
+	if i := s.dependencyIndex(dep.IssueID, dep.DependsOnID); i >= 0 {
+		s.dependencies[i] = &stored
+	} else {
+		s.dependencies = append(s.dependencies, &stored)
+	}
+
+	newVal := fmt.Sprintf("%s depends on %s (%s)", dep.IssueID, dep.DependsOnID, dep.Type)
+	s.recordEvent(dep.IssueID, types.EventDependencyAdded, actor, nil, &newVal)
+
+	return nil
+}
+
+// dependencyIndex returns the position of the issueID -> dependsOnID
+// dependency, or -1.
+func (s *Store) dependencyIndex(issueID, dependsOnID string) int {
+	return slices.IndexFunc(s.dependencies, func(d *types.Dependency) bool {
+		return d.IssueID == issueID && d.DependsOnID == dependsOnID
+	})
+}
+
+// hasDependency reports whether issueID depends on dependsOnID with the
+// given type.
+func (s *Store) hasDependency(issueID, dependsOnID string, depType types.DependencyType) bool {
+	i := s.dependencyIndex(issueID, dependsOnID)
+	return i >= 0 && s.dependencies[i].Type == depType
+}
+
+// RemoveDependency removes a dependency between two issues. The event
+// records the type of the removed dependency so it can be restored.
+func (s *Store) RemoveDependency(_ context.Context, issueID, dependsOnID string, actor string) error {
+	s.lock()
+	defer s.unlock()
+
+	oldVal := fmt.Sprintf("%s no longer depends on %s", issueID, dependsOnID)
+	if i := s.dependencyIndex(issueID, dependsOnID); i >= 0 {
+		oldVal += fmt.Sprintf(" (%s)", s.dependencies[i].Type)
+		s.dependencies = slices.Delete(s.dependencies, i, i+1)
+	}
+
+	s.recordEvent(issueID, types.EventDependencyRemoved, actor, &oldVal, nil)
+
+	return nil
+}
+
+// GetDependencies returns the dependencies of an issue.
+func (s *Store) GetDependencies(_ context.Context, issueID string) ([]*types.Dependency, error) {
+	s.lock()
+	defer s.unlock()
+
+	return s.queryDependencies(func(d *types.Dependency) bool { return d.IssueID == issueID }), nil
+}
+
+// GetDependents returns issues that depend on the given issue.
+func (s *Store) GetDependents(_ context.Context, issueID string) ([]*types.Dependency, error) {
+	s.lock()
+	defer s.unlock()
+
+	return s.queryDependencies(func(d *types.Dependency) bool { return d.DependsOnID == issueID }), nil
+}
+
+// queryDependencies returns copies of the dependencies that match, in the
+// order they were added.
+func (s *Store) queryDependencies(match func(*types.Dependency) bool) []*types.Dependency {
+	deps := []*types.Dependency{}
+	for _, d := range s.dependencies {
+		if match(d) {
+			dep := *d
+			deps = append(deps, &dep)
+		}
+	}
+	return deps
+}
+
+// GetOpenChildIssues returns open (non-closed) child issues of a given parent
+// via parent-child dependencies.
+func (s *Store) GetOpenChildIssues(_ context.Context, parentID string) ([]*types.Issue, error) {
+	s.lock()
+	defer s.unlock()
+
+	children := s.openChildIssues(parentID)
+	for i, c := range children {
+		children[i] = cloneIssue(c)
+	}
+	return children, nil
+}
+
+// openChildIssues returns the stored open children of parentID, by
+// priority and then age.
+func (s *Store) openChildIssues(parentID string) []*types.Issue {
+	var children []*types.Issue
+	for _, d := range s.dependencies {
+		if d.DependsOnID != parentID || d.Type != types.DepParentChild {
+			continue
+		}
+		if child, ok := s.issues[d.IssueID]; ok && child.Status != types.StatusClosed {
+			children = append(children, child)
+		}
+	}
+	slices.SortStableFunc(children, func(a, b *types.Issue) int {
+		return cmp.Or(cmp.Compare(a.Priority, b.Priority), a.CreatedAt.Compare(b.CreatedAt))
+	})
+	return children
+}
@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// normalizeFields checks fields against the project's definitions and
+// returns them in canonical form. With allowClear, an empty value is kept
+// as "" to mean "remove this field".
+func (s *Store) normalizeFields(projectID string, fields map[string]string, allowClear bool) (map[string]string, error) {
+	defs := types.FieldDefs(s.config[projectID])
+
+	out := make(map[string]string, len(fields))
+	for key, value := range fields {
+		def, ok := defs[key]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s is not defined for this project", types.ErrInvalidField, key)
+		}
+		if allowClear && strings.TrimSpace(value) == "" {
+			out[key] = ""
+			continue
+		}
+		var err error
+		if out[key], err = def.Normalize(value); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// writeIssueFields applies normalized field values to an issue, removing
+// fields whose value is empty, and records a field_changed event for each
+// value that actually changed. Event values have the form key=value.
+func (s *Store) writeIssueFields(issueID string, fields map[string]string, actor string) {
+	if _, ok := s.issues[issueID]; !ok {
+		return
+	}
+	old := s.issueFields[issueID]
+
+	for _, key := range slices.Sorted(maps.Keys(fields)) {
+		value := fields[key]
+		prev, had := old[key]
+		if prev == value {
+			continue
+		}
+		if value == "" {
+			delete(s.issueFields[issueID], key)
+		} else {
+			if s.issueFields[issueID] == nil {
+				s.issueFields[issueID] = make(map[string]string)
+			}
+			s.issueFields[issueID][key] = value
+		}
+
+		var oldValue, newValue *string
+		if had {
+			v := key + "=" + prev
+			oldValue = &v
+		}
+		if value != "" {
+			v := key + "=" + value
+			newValue = &v
+		}
+		s.recordEvent(issueID, types.EventFieldChanged, actor, oldValue, newValue)
+	}
+	if len(s.issueFields[issueID]) == 0 {
+		delete(s.issueFields, issueID)
+	}
+}
+
+// GetFieldsForIssues fetches custom field values for multiple issues.
+// Returns a map of issue_id -> key -> value; issues without fields are
+// absent.
+func (s *Store) GetFieldsForIssues(_ context.Context, issueIDs []string) (map[string]map[string]string, error) {
+	s.lock()
+	defer s.unlock()
+
+	result := make(map[string]map[string]string)
+	for _, id := range issueIDs {
+		if fields := s.issueFields[id]; len(fields) > 0 {
+			result[id] = maps.Clone(fields)
+		}
+	}
+	return result, nil
+}
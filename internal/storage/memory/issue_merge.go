@@ -0,0 +1,116 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/depgraph"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// MergeIssue folds a duplicate issue into its canonical issue. Comments and
+// labels move across, dependencies are rewired as depgraph.PlanMerge lays
+// out, and the duplicate is closed with a duplicates dependency on the
+// canonical issue. Each label the canonical issue gains is recorded as a
+// label_added event on it.
+func (s *Store) MergeIssue(
+	_ context.Context, duplicateID, canonicalID, actor string,
+) (*types.IssueMergeResult, error) {
+	s.lock()
+	defer s.unlock()
+
+	if duplicateID == canonicalID {
+		return nil, errors.New("cannot merge an issue into itself")
+	}
+	dup, ok := s.issues[duplicateID]
+	if !ok {
+		return nil, fmt.Errorf("issue not found: %s", duplicateID)
+	}
+	canonical, ok := s.issues[canonicalID]
+	if !ok {
+		return nil, fmt.Errorf("issue not found: %s", canonicalID)
+	}
+	if dup.ProjectID != canonical.ProjectID {
+		return nil, fmt.Errorf("cannot merge %s into %s: issues are in different projects", duplicateID, canonicalID)
+	}
+
+	from := func(id string) []*types.Dependency {
+		return s.queryDependencies(func(d *types.Dependency) bool { return d.IssueID == id })
+	}
+	to := func(id string) []*types.Dependency {
+		return s.queryDependencies(func(d *types.Dependency) bool { return d.DependsOnID == id })
+	}
+	blockers := depgraph.Blockers(context.Background(), func(_ context.Context, id string) ([]*types.Dependency, error) {
+		return from(id), nil
+	})
+	plan, err := depgraph.PlanMerge(duplicateID, canonicalID,
+		from(duplicateID), to(duplicateID), from(canonicalID), to(canonicalID), blockers)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &types.IssueMergeResult{DependenciesMoved: len(plan.Move)}
+	for _, c := range s.comments {
+		if c.IssueID == duplicateID {
+			c.IssueID = canonicalID
+			result.CommentsMoved++
+		}
+	}
+	for label := range s.issueLabels[duplicateID] {
+		if s.issueLabels[canonicalID] == nil {
+			s.issueLabels[canonicalID] = make(map[string]bool)
+		}
+		if !s.issueLabels[canonicalID][label] {
+			s.issueLabels[canonicalID][label] = true
+			s.recordEvent(canonicalID, types.EventLabelAdded, actor, nil, &label)
+			result.LabelsMoved++
+		}
+	}
+	delete(s.issueLabels, duplicateID)
+
+	for _, d := range plan.Drop {
+		s.dependencies = slices.DeleteFunc(s.dependencies, func(x *types.Dependency) bool {
+			return x.IssueID == d.IssueID && x.DependsOnID == d.DependsOnID
+		})
+	}
+	for _, m := range plan.Move {
+		if i := s.dependencyIndex(m[0].IssueID, m[0].DependsOnID); i >= 0 {
+			s.dependencies[i].IssueID = m[1].IssueID
+			s.dependencies[i].DependsOnID = m[1].DependsOnID
+		}
+	}
+
+	now := time.Now()
+	s.dependencies = append(s.dependencies, &types.Dependency{
+		IssueID:     duplicateID,
+		DependsOnID: canonicalID,
+		Type:        types.DepDuplicates,
+		CreatedAt:   now,
+		CreatedBy:   actor,
+	})
+
+	oldStatus := string(dup.Status)
+	if dup.ClosedAt == nil {
+		dup.ClosedAt = &now
+	}
+	dup.Status = types.StatusClosed
+	dup.CloseReason = types.CloseReasonDuplicate
+	dup.UpdatedAt = now
+	dup.Version++
+
+	if oldStatus != string(types.StatusClosed) {
+		reason := types.CloseReasonDuplicate
+		s.recordEvent(duplicateID, types.EventClosed, actor, &oldStatus, &reason)
+	}
+	into := "merged into " + canonicalID
+	s.recordEvent(duplicateID, types.EventMerged, actor, nil, &into)
+	merged := "merged from " + duplicateID
+	s.recordEvent(canonicalID, types.EventMerged, actor, nil, &merged)
+
+	result.Canonical = cloneIssue(canonical)
+	result.Duplicate = cloneIssue(dup)
+	return result, nil
+}
@@ -0,0 +1,155 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/depgraph"
+	"github.com/sentiolabs/arc/internal/history"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// MoveIssue moves an issue and its descendants to another project. IDs are
+// reissued under the target prefix as depgraph.PlanMove lays out, every
+// record naming a moved issue follows its new ID, and each old ID becomes
+// an alias. The root's parent-child dependency on an issue left behind is
+// dropped, as are custom field values the target project does not accept.
+func (s *Store) MoveIssue(_ context.Context, issueID, projectID, actor string) (*types.IssueMoveResult, error) {
+	s.lock()
+	defer s.unlock()
+
+	root, ok := s.issues[issueID]
+	if !ok {
+		if root, ok = s.issues[s.aliases[issueID]]; !ok {
+			return nil, fmt.Errorf("issue not found: %s", issueID)
+		}
+	}
+	target, ok := s.projects[projectID]
+	if !ok {
+		return nil, fmt.Errorf("project not found: %s", projectID)
+	}
+	if root.ProjectID == target.ID {
+		return nil, fmt.Errorf("issue %s is already in project %s", root.ID, target.ID)
+	}
+	sourceProject := root.ProjectID
+
+	rootID := root.ID
+	dependents := func(id string) ([]*types.Dependency, error) {
+		return s.queryDependencies(func(d *types.Dependency) bool { return d.DependsOnID == id }), nil
+	}
+	lastChild := func(id string) (int, error) {
+		return s.childCounters[id], nil
+	}
+	taken := func(id string) (bool, error) {
+		_, exists := s.issues[id]
+		current, aliased := s.aliases[id]
+		return exists || (aliased && current != rootID), nil
+	}
+	plan, err := depgraph.PlanMove(root, target.Prefix, dependents, lastChild, taken)
+	if err != nil {
+		return nil, err
+	}
+
+	s.dependencies = slices.DeleteFunc(s.dependencies, func(d *types.Dependency) bool {
+		return d.IssueID == rootID && d.Type == types.DepParentChild
+	})
+
+	rename := func(id *string) {
+		if newID, ok := plan.IDs[*id]; ok {
+			*id = newID
+		}
+	}
+	for _, d := range s.dependencies {
+		rename(&d.IssueID)
+		rename(&d.DependsOnID)
+	}
+	for _, c := range s.comments {
+		rename(&c.IssueID)
+	}
+	for _, e := range s.events {
+		rename(&e.IssueID)
+	}
+	for alias, current := range s.aliases {
+		if newID, ok := plan.IDs[current]; ok {
+			s.aliases[alias] = newID
+		}
+	}
+
+	now := time.Now()
+	for _, oldID := range plan.Order {
+		newID := plan.IDs[oldID]
+		issue := s.issues[oldID]
+		delete(s.issues, oldID)
+		issue.ID = newID
+		issue.ProjectID = target.ID
+		issue.UpdatedAt = now
+		issue.Version++
+		s.issues[newID] = issue
+
+		if labels, ok := s.issueLabels[oldID]; ok {
+			delete(s.issueLabels, oldID)
+			s.issueLabels[newID] = labels
+		}
+		if fields, ok := s.issueFields[oldID]; ok {
+			delete(s.issueFields, oldID)
+			s.issueFields[newID] = fields
+		}
+		for i, link := range s.issuePlans {
+			if link.issueID == oldID {
+				s.issuePlans[i].issueID = newID
+			}
+		}
+		if last, ok := s.childCounters[oldID]; ok {
+			delete(s.childCounters, oldID)
+			s.childCounters[newID] = last
+		}
+		// An issue ID outranks an alias of the same name, so drop the alias
+		// when an issue moves back to an ID it once had.
+		delete(s.aliases, newID)
+		s.aliases[oldID] = newID
+	}
+	for parentID, last := range plan.Counters {
+		s.childCounters[parentID] = max(s.childCounters[parentID], last)
+	}
+
+	defs := types.FieldDefs(s.config[target.ID])
+	var dropped map[string][]string
+	for _, oldID := range plan.Order {
+		newID := plan.IDs[oldID]
+		s.recordEvent(newID, types.EventMoved, actor,
+			history.MoveValue(oldID, sourceProject), history.MoveValue(newID, target.ID))
+		if keys := types.RejectedFields(defs, s.issueFields[newID]); len(keys) > 0 {
+			unset := make(map[string]string, len(keys))
+			for _, key := range keys {
+				unset[key] = ""
+			}
+			s.writeIssueFields(newID, unset, actor)
+			if dropped == nil {
+				dropped = make(map[string][]string)
+			}
+			dropped[newID] = keys
+		}
+	}
+
+	out := *target
+	return &types.IssueMoveResult{
+		Issue:         cloneIssue(s.issues[plan.IDs[rootID]]),
+		TargetProject: &out,
+		IDMap:         plan.IDs,
+		DroppedFields: dropped,
+	}, nil
+}
+
+// ResolveIssueAlias returns the current ID of the issue once known as id:
+// the issue it was moved to or merged into, or id itself.
+func (s *Store) ResolveIssueAlias(_ context.Context, id string) (string, error) {
+	s.lock()
+	defer s.unlock()
+
+	if current, ok := s.aliases[id]; ok {
+		id = current
+	}
+	return s.canonicalOf(id), nil
+}
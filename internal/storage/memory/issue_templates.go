@@ -0,0 +1,129 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// SaveIssueTemplate creates a template or replaces the one with the same
+// name in the project. LastRunAt is kept when replacing.
+func (s *Store) SaveIssueTemplate(_ context.Context, t *types.IssueTemplate) error {
+	if t.ProjectID == "" || t.Name == "" {
+		return errors.New("template project_id and name are required")
+	}
+
+	s.lock()
+	defer s.unlock()
+
+	if _, ok := s.projects[t.ProjectID]; !ok {
+		return fmt.Errorf("save issue template: project not found: %s", t.ProjectID)
+	}
+
+	now := time.Now().UTC()
+	t.UpdatedAt = now
+	if existing, ok := s.templates[t.ProjectID][t.Name]; ok {
+		t.CreatedAt = existing.CreatedAt
+		t.LastRunAt = cloneTime(existing.LastRunAt)
+	} else {
+		t.CreatedAt = now
+		t.LastRunAt = nil
+	}
+
+	if s.templates[t.ProjectID] == nil {
+		s.templates[t.ProjectID] = make(map[string]*types.IssueTemplate)
+	}
+	s.templates[t.ProjectID][t.Name] = cloneIssueTemplate(t)
+	return nil
+}
+
+// GetIssueTemplate retrieves a project's template by name.
+func (s *Store) GetIssueTemplate(_ context.Context, projectID, name string) (*types.IssueTemplate, error) {
+	s.lock()
+	defer s.unlock()
+
+	t, ok := s.templates[projectID][name]
+	if !ok {
+		return nil, fmt.Errorf("issue template not found: %s", name)
+	}
+	return cloneIssueTemplate(t), nil
+}
+
+// ListIssueTemplates returns a project's templates ordered by name.
+func (s *Store) ListIssueTemplates(_ context.Context, projectID string) ([]*types.IssueTemplate, error) {
+	s.lock()
+	defer s.unlock()
+
+	templates := []*types.IssueTemplate{}
+	for _, name := range slices.Sorted(maps.Keys(s.templates[projectID])) {
+		templates = append(templates, cloneIssueTemplate(s.templates[projectID][name]))
+	}
+	return templates, nil
+}
+
+// ListDueIssueTemplates returns recurring templates, across all projects,
+// whose next run is at or before now, oldest first.
+func (s *Store) ListDueIssueTemplates(_ context.Context, now time.Time) ([]*types.IssueTemplate, error) {
+	s.lock()
+	defer s.unlock()
+
+	templates := []*types.IssueTemplate{}
+	for _, byName := range s.templates {
+		for _, t := range byName {
+			if t.NextRunAt != nil && !t.NextRunAt.After(now) {
+				templates = append(templates, cloneIssueTemplate(t))
+			}
+		}
+	}
+	slices.SortFunc(templates, func(a, b *types.IssueTemplate) int {
+		return cmp.Or(
+			a.NextRunAt.Compare(*b.NextRunAt),
+			strings.Compare(a.ProjectID, b.ProjectID),
+			strings.Compare(a.Name, b.Name),
+		)
+	})
+	return templates, nil
+}
+
+// RecordIssueTemplateRun records that a template was instantiated at
+// ranAt and schedules its next run (nil for none).
+func (s *Store) RecordIssueTemplateRun(
+	_ context.Context, projectID, name string, ranAt time.Time, next *time.Time,
+) error {
+	s.lock()
+	defer s.unlock()
+
+	t, ok := s.templates[projectID][name]
+	if !ok {
+		return fmt.Errorf("issue template not found: %s", name)
+	}
+	t.LastRunAt = &ranAt
+	t.NextRunAt = cloneTime(next)
+	return nil
+}
+
+// DeleteIssueTemplate removes a project's template.
+func (s *Store) DeleteIssueTemplate(_ context.Context, projectID, name string) error {
+	s.lock()
+	defer s.unlock()
+
+	if _, ok := s.templates[projectID][name]; !ok {
+		return fmt.Errorf("issue template not found: %s", name)
+	}
+	delete(s.templates[projectID], name)
+	return nil
+}
+
+func cloneIssueTemplate(t *types.IssueTemplate) *types.IssueTemplate {
+	out := *t
+	out.NextRunAt = cloneTime(t.NextRunAt)
+	out.LastRunAt = cloneTime(t.LastRunAt)
+	return &out
+}
@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// CreateLabel creates a new global label definition, replacing any label
+// with the same name.
+func (s *Store) CreateLabel(_ context.Context, label *types.Label) error {
+	s.lock()
+	defer s.unlock()
+
+	stored := *label
+	s.labels[label.Name] = &stored
+	return nil
+}
+
+// GetLabel retrieves a label by name.
+func (s *Store) GetLabel(_ context.Context, name string) (*types.Label, error) {
+	s.lock()
+	defer s.unlock()
+
+	label, ok := s.labels[name]
+	if !ok {
+		return nil, fmt.Errorf("label not found: %s", name)
+	}
+	out := *label
+	return &out, nil
+}
+
+// ListLabels returns all global labels.
+func (s *Store) ListLabels(_ context.Context) ([]*types.Label, error) {
+	s.lock()
+	defer s.unlock()
+
+	labels := make([]*types.Label, 0, len(s.labels))
+	for _, label := range s.labels {
+		out := *label
+		labels = append(labels, &out)
+	}
+	slices.SortFunc(labels, func(a, b *types.Label) int { return strings.Compare(a.Name, b.Name) })
+	return labels, nil
+}
+
+// UpdateLabel updates a label.
+func (s *Store) UpdateLabel(_ context.Context, label *types.Label) error {
+	s.lock()
+	defer s.unlock()
+
+	if stored, ok := s.labels[label.Name]; ok {
+		stored.Color = label.Color
+		stored.Description = label.Description
+	}
+	return nil
+}
+
+// DeleteLabel deletes a label definition. Issues keep the label name.
+func (s *Store) DeleteLabel(_ context.Context, name string) error {
+	s.lock()
+	defer s.unlock()
+
+	delete(s.labels, name)
+	return nil
+}
+
+// AddLabelToIssue adds a label to an issue.
+func (s *Store) AddLabelToIssue(_ context.Context, issueID, label, actor string) error {
+	s.lock()
+	defer s.unlock()
+
+	if _, ok := s.issues[issueID]; !ok {
+		return fmt.Errorf("add label to issue: issue not found: %s", issueID)
+	}
+	if s.issueLabels[issueID] == nil {
+		s.issueLabels[issueID] = make(map[string]bool)
+	}
+	s.issueLabels[issueID][label] = true
+
+	s.recordEvent(issueID, types.EventLabelAdded, actor, nil, &label)
+	return nil
+}
+
+// RemoveLabelFromIssue removes a label from an issue.
+func (s *Store) RemoveLabelFromIssue(_ context.Context, issueID, label, actor string) error {
+	s.lock()
+	defer s.unlock()
+
+	delete(s.issueLabels[issueID], label)
+
+	s.recordEvent(issueID, types.EventLabelRemoved, actor, &label, nil)
+	return nil
+}
+
+// GetIssueLabels returns all labels for an issue.
+func (s *Store) GetIssueLabels(_ context.Context, issueID string) ([]string, error) {
+	s.lock()
+	defer s.unlock()
+
+	return s.labelsOf(issueID), nil
+}
+
+// labelsOf returns an issue's labels in name order.
+func (s *Store) labelsOf(issueID string) []string {
+	labels := slices.Sorted(maps.Keys(s.issueLabels[issueID]))
+	if labels == nil {
+		labels = []string{}
+	}
+	return labels
+}
+
+// GetLabelsForIssues fetches labels for multiple issues.
+// Returns a map of issue_id -> []labels; issues without labels are absent.
+func (s *Store) GetLabelsForIssues(_ context.Context, issueIDs []string) (map[string][]string, error) {
+	s.lock()
+	defer s.unlock()
+
+	result := make(map[string][]string)
+	for _, id := range issueIDs {
+		if len(s.issueLabels[id]) > 0 {
+			result[id] = s.labelsOf(id)
+		}
+	}
+	return result, nil
+}
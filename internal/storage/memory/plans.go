@@ -0,0 +1,246 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// CreatePlan persists a new plan. The caller must set plan.ID before calling.
+func (s *Store) CreatePlan(_ context.Context, plan *types.Plan) error {
+	s.lock()
+	defer s.unlock()
+
+	if _, ok := s.plans[plan.ID]; ok {
+		return fmt.Errorf("create plan: plan already exists: %s", plan.ID)
+	}
+
+	now := time.Now()
+	plan.CreatedAt = now
+	plan.UpdatedAt = now
+
+	stored := *plan
+	s.plans[plan.ID] = &stored
+
+	s.publish(types.StreamEvent{
+		Type:   types.StreamPlanCreated,
+		PlanID: plan.ID,
+		Data:   map[string]any{"status": plan.Status},
+	})
+	return nil
+}
+
+// GetPlan retrieves a plan by ID. Returns an error if not found.
+func (s *Store) GetPlan(_ context.Context, id string) (*types.Plan, error) {
+	s.lock()
+	defer s.unlock()
+
+	plan, ok := s.plans[id]
+	if !ok {
+		return nil, fmt.Errorf("plan not found: %s", id)
+	}
+	out := *plan
+	return &out, nil
+}
+
+// UpdatePlanStatus changes a plan's status.
+func (s *Store) UpdatePlanStatus(_ context.Context, id string, status string) error {
+	s.lock()
+	defer s.unlock()
+
+	if plan, ok := s.plans[id]; ok {
+		plan.Status = status
+		plan.UpdatedAt = time.Now()
+	}
+
+	s.publish(types.StreamEvent{
+		Type:   types.StreamPlanStatusChanged,
+		PlanID: id,
+		Data:   map[string]any{"status": status},
+	})
+	return nil
+}
+
+// DeletePlan deletes a plan, its comments and its issue links.
+func (s *Store) DeletePlan(_ context.Context, id string) error {
+	s.lock()
+	defer s.unlock()
+
+	s.planComments = slices.DeleteFunc(s.planComments, func(c *types.PlanComment) bool { return c.PlanID == id })
+	s.issuePlans = slices.DeleteFunc(s.issuePlans, func(l issuePlan) bool { return l.planID == id })
+	delete(s.planText, id)
+	delete(s.plans, id)
+
+	s.publish(types.StreamEvent{Type: types.StreamPlanDeleted, PlanID: id})
+	return nil
+}
+
+// SetPlanText stores the copy of a plan's file content that search indexes
+// for the issues linked to it.
+func (s *Store) SetPlanText(_ context.Context, id, text string) error {
+	s.lock()
+	defer s.unlock()
+
+	if _, ok := s.plans[id]; ok {
+		s.planText[id] = text
+	}
+	return nil
+}
+
+// LinkIssuePlan links a plan to an issue. Linking a pair twice is a no-op.
+func (s *Store) LinkIssuePlan(_ context.Context, issueID, planID string) error {
+	s.lock()
+	defer s.unlock()
+
+	issue, ok := s.resolveIssue(issueID)
+	if !ok {
+		return fmt.Errorf("issue not found: %s", issueID)
+	}
+	if _, ok := s.plans[planID]; !ok {
+		return fmt.Errorf("plan not found: %s", planID)
+	}
+	link := issuePlan{issueID: issue.ID, planID: planID}
+	if !slices.Contains(s.issuePlans, link) {
+		s.issuePlans = append(s.issuePlans, link)
+	}
+	return nil
+}
+
+// UnlinkIssuePlan removes the link between an issue and a plan, if any.
+func (s *Store) UnlinkIssuePlan(_ context.Context, issueID, planID string) error {
+	s.lock()
+	defer s.unlock()
+
+	link := issuePlan{issueID: issueID, planID: planID}
+	s.issuePlans = slices.DeleteFunc(s.issuePlans, func(l issuePlan) bool { return l == link })
+	return nil
+}
+
+// ListIssuePlans returns the plans linked to an issue, in the order they
+// were linked.
+func (s *Store) ListIssuePlans(_ context.Context, issueID string) ([]*types.Plan, error) {
+	s.lock()
+	defer s.unlock()
+
+	plans := []*types.Plan{}
+	for _, link := range s.issuePlans {
+		if link.issueID == issueID {
+			plan := *s.plans[link.planID]
+			plans = append(plans, &plan)
+		}
+	}
+	return plans, nil
+}
+
+// CreatePlanComment persists a new comment on a plan.
+func (s *Store) CreatePlanComment(_ context.Context, comment *types.PlanComment) error {
+	s.lock()
+	defer s.unlock()
+
+	if _, ok := s.plans[comment.PlanID]; !ok {
+		return fmt.Errorf("create plan comment: plan not found: %s", comment.PlanID)
+	}
+	if s.planCommentIndex(comment.ID) >= 0 {
+		return fmt.Errorf("create plan comment: plan comment already exists: %s", comment.ID)
+	}
+
+	comment.CreatedAt = time.Now()
+	s.planComments = append(s.planComments, clonePlanComment(comment))
+
+	s.publishPlanComment(types.StreamPlanCommentAdded, comment.PlanID, comment.ID)
+	return nil
+}
+
+// ListPlanComments returns all comments for a plan, ordered by creation time.
+func (s *Store) ListPlanComments(_ context.Context, planID string) ([]*types.PlanComment, error) {
+	s.lock()
+	defer s.unlock()
+
+	return s.listPlanComments(planID), nil
+}
+
+func (s *Store) listPlanComments(planID string) []*types.PlanComment {
+	comments := []*types.PlanComment{}
+	for _, c := range s.planComments {
+		if c.PlanID == planID {
+			comments = append(comments, clonePlanComment(c))
+		}
+	}
+	return comments
+}
+
+// GetPlanComment returns a single plan comment by ID.
+func (s *Store) GetPlanComment(_ context.Context, id string) (*types.PlanComment, error) {
+	s.lock()
+	defer s.unlock()
+
+	i := s.planCommentIndex(id)
+	if i < 0 {
+		return nil, fmt.Errorf("get plan comment: plan comment not found: %s", id)
+	}
+	return clonePlanComment(s.planComments[i]), nil
+}
+
+// UpdatePlanComment overwrites the mutable fields of a plan comment.
+func (s *Store) UpdatePlanComment(_ context.Context, comment *types.PlanComment) error {
+	s.lock()
+	defer s.unlock()
+
+	if i := s.planCommentIndex(comment.ID); i >= 0 {
+		stored := s.planComments[i]
+		updated := clonePlanComment(comment)
+		updated.PlanID = stored.PlanID
+		updated.CreatedAt = stored.CreatedAt
+		s.planComments[i] = updated
+	}
+
+	s.publishPlanComment(types.StreamPlanCommentUpdated, comment.PlanID, comment.ID)
+	return nil
+}
+
+// DeletePlanComment removes a plan comment.
+func (s *Store) DeletePlanComment(_ context.Context, id string) error {
+	s.lock()
+	defer s.unlock()
+
+	i := s.planCommentIndex(id)
+	if i < 0 {
+		return nil
+	}
+	planID := s.planComments[i].PlanID
+	s.planComments = slices.Delete(s.planComments, i, i+1)
+
+	s.publishPlanComment(types.StreamPlanCommentDeleted, planID, id)
+	return nil
+}
+
+func (s *Store) planCommentIndex(id string) int {
+	return slices.IndexFunc(s.planComments, func(c *types.PlanComment) bool { return c.ID == id })
+}
+
+// publishPlanComment notifies subscribers of a plan comment change.
+func (s *Store) publishPlanComment(evType types.StreamEventType, planID, commentID string) {
+	s.publish(types.StreamEvent{
+		Type:   evType,
+		PlanID: planID,
+		Data:   map[string]any{"comment_id": commentID},
+	})
+}
+
+func clonePlanComment(c *types.PlanComment) *types.PlanComment {
+	out := *c
+	if c.LineNumber != nil {
+		n := *c.LineNumber
+		out.LineNumber = &n
+	}
+	if c.Anchor != nil {
+		anchor := *c.Anchor
+		out.Anchor = &anchor
+	}
+	out.UpdatedAt = cloneTime(c.UpdatedAt)
+	out.ResolvedAt = cloneTime(c.ResolvedAt)
+	return &out
+}
@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"maps"
+)
+
+// GetProjectConfig returns all per-project config rows as a key/value map.
+// A project with no config rows yields an empty (non-nil) map.
+func (s *Store) GetProjectConfig(_ context.Context, projectID string) (map[string]string, error) {
+	s.lock()
+	defer s.unlock()
+
+	return s.projectConfig(projectID), nil
+}
+
+// projectConfig returns a copy of a project's config.
+func (s *Store) projectConfig(projectID string) map[string]string {
+	values := make(map[string]string, len(s.config[projectID]))
+	maps.Copy(values, s.config[projectID])
+	return values
+}
+
+// SetProjectConfig upserts a single per-project config key.
+func (s *Store) SetProjectConfig(_ context.Context, projectID, key, value string) error {
+	s.lock()
+	defer s.unlock()
+
+	if _, ok := s.projects[projectID]; !ok {
+		return fmt.Errorf("set project config: project not found: %s", projectID)
+	}
+	if s.config[projectID] == nil {
+		s.config[projectID] = make(map[string]string)
+	}
+	s.config[projectID][key] = value
+	return nil
+}
+
+// DeleteProjectConfig removes a single per-project config key.
+func (s *Store) DeleteProjectConfig(_ context.Context, projectID, key string) error {
+	s.lock()
+	defer s.unlock()
+
+	delete(s.config[projectID], key)
+	return nil
+}
@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// ListProjectMembers returns a project's members ordered by actor.
+func (s *Store) ListProjectMembers(_ context.Context, projectID string) ([]*types.ProjectMember, error) {
+	s.lock()
+	defer s.unlock()
+
+	members := []*types.ProjectMember{}
+	for _, actor := range slices.Sorted(maps.Keys(s.members[projectID])) {
+		m := *s.members[projectID][actor]
+		members = append(members, &m)
+	}
+	return members, nil
+}
+
+// GetProjectMember returns actor's membership in a project.
+func (s *Store) GetProjectMember(_ context.Context, projectID, actor string) (*types.ProjectMember, error) {
+	s.lock()
+	defer s.unlock()
+
+	m, ok := s.members[projectID][actor]
+	if !ok {
+		return nil, fmt.Errorf("%s is not a member of project %s", actor, projectID)
+	}
+	out := *m
+	return &out, nil
+}
+
+// SetProjectMember adds a member or changes an existing member's role.
+func (s *Store) SetProjectMember(_ context.Context, m *types.ProjectMember) error {
+	if m.Actor == "" {
+		return errors.New("member actor is required")
+	}
+	if !m.Role.IsValid() {
+		return fmt.Errorf("invalid role: %s", m.Role)
+	}
+
+	s.lock()
+	defer s.unlock()
+
+	if _, ok := s.projects[m.ProjectID]; !ok {
+		return fmt.Errorf("set project member: project not found: %s", m.ProjectID)
+	}
+	if existing, ok := s.members[m.ProjectID][m.Actor]; ok {
+		existing.Role = m.Role
+		m.CreatedAt = existing.CreatedAt
+		return nil
+	}
+
+	m.CreatedAt = time.Now()
+	if s.members[m.ProjectID] == nil {
+		s.members[m.ProjectID] = make(map[string]*types.ProjectMember)
+	}
+	stored := *m
+	s.members[m.ProjectID][m.Actor] = &stored
+	return nil
+}
+
+// RemoveProjectMember removes actor from a project.
+func (s *Store) RemoveProjectMember(_ context.Context, projectID, actor string) error {
+	s.lock()
+	defer s.unlock()
+
+	if _, ok := s.members[projectID][actor]; !ok {
+		return fmt.Errorf("%s is not a member of project %s", actor, projectID)
+	}
+	delete(s.members[projectID], actor)
+	return nil
+}
@@ -0,0 +1,207 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/project"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// CreateProject creates a new project.
+func (s *Store) CreateProject(_ context.Context, p *types.Project) error {
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("validate project: %w", err)
+	}
+
+	s.lock()
+	defer s.unlock()
+
+	// Generate ID if not provided
+	if p.ID == "" {
+		p.ID = project.GenerateProjectID("proj", p.Name)
+	}
+	if _, ok := s.projects[p.ID]; ok {
+		return fmt.Errorf("create project: project already exists: %s", p.ID)
+	}
+	if s.projectByName(p.Name) != nil {
+		return fmt.Errorf("create project: project name already exists: %s", p.Name)
+	}
+
+	now := time.Now()
+	p.CreatedAt = now
+	p.UpdatedAt = now
+
+	stored := *p
+	s.projects[p.ID] = &stored
+	return nil
+}
+
+// GetProject retrieves a project by ID.
+func (s *Store) GetProject(_ context.Context, id string) (*types.Project, error) {
+	s.lock()
+	defer s.unlock()
+
+	p, ok := s.projects[id]
+	if !ok {
+		return nil, fmt.Errorf("project not found: %s", id)
+	}
+	out := *p
+	return &out, nil
+}
+
+// GetProjectByName retrieves a project by name.
+func (s *Store) GetProjectByName(_ context.Context, name string) (*types.Project, error) {
+	s.lock()
+	defer s.unlock()
+
+	p := s.projectByName(name)
+	if p == nil {
+		return nil, fmt.Errorf("project not found: %s", name)
+	}
+	out := *p
+	return &out, nil
+}
+
+func (s *Store) projectByName(name string) *types.Project {
+	for _, p := range s.projects {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// ListProjects returns all projects.
+func (s *Store) ListProjects(_ context.Context) ([]*types.Project, error) {
+	s.lock()
+	defer s.unlock()
+
+	projects := make([]*types.Project, 0, len(s.projects))
+	for _, p := range s.projects {
+		out := *p
+		projects = append(projects, &out)
+	}
+	slices.SortFunc(projects, func(a, b *types.Project) int { return strings.Compare(a.Name, b.Name) })
+	return projects, nil
+}
+
+// UpdateProject updates a project.
+func (s *Store) UpdateProject(_ context.Context, p *types.Project) error {
+	s.lock()
+	defer s.unlock()
+
+	p.UpdatedAt = time.Now()
+
+	stored, ok := s.projects[p.ID]
+	if !ok {
+		return nil
+	}
+	if other := s.projectByName(p.Name); other != nil && other.ID != p.ID {
+		return fmt.Errorf("update project: project name already exists: %s", p.Name)
+	}
+	stored.Name = p.Name
+	stored.Description = p.Description
+	stored.UpdatedAt = p.UpdatedAt
+	return nil
+}
+
+// DeleteProject deletes a project and all its issues.
+// Accepts either project ID (e.g., "proj-00blnw") or name (e.g., "my-project-a1b2c3").
+func (s *Store) DeleteProject(_ context.Context, idOrName string) error {
+	s.lock()
+	defer s.unlock()
+
+	p, ok := s.projects[idOrName]
+	if !ok {
+		if p = s.projectByName(idOrName); p == nil {
+			return fmt.Errorf("project not found: %s", idOrName)
+		}
+	}
+
+	s.deleteProject(p.ID)
+	return nil
+}
+
+// deleteProject removes a project and everything that belongs to it.
+func (s *Store) deleteProject(id string) {
+	for issueID, issue := range s.issues {
+		if issue.ProjectID == id {
+			s.deleteIssue(issueID)
+		}
+	}
+	for wsID, ws := range s.workspaces {
+		if ws.ProjectID == id {
+			delete(s.workspaces, wsID)
+		}
+	}
+	for sessionID, session := range s.sessions {
+		if session.ProjectID == id {
+			s.deleteAISession(sessionID)
+		}
+	}
+	for whID, wh := range s.webhooks {
+		if wh.ProjectID == id {
+			s.deleteWebhook(whID)
+		}
+	}
+	delete(s.config, id)
+	delete(s.members, id)
+	delete(s.templates, id)
+	delete(s.projects, id)
+}
+
+// MergeProjects moves all issues from source projects into the target
+// project, deletes the sources, and returns a summary. Every source is
+// checked before anything changes, so a failed merge leaves the store as it
+// was.
+func (s *Store) MergeProjects(
+	_ context.Context, targetID string, sourceIDs []string, actor string,
+) (*types.MergeResult, error) {
+	s.lock()
+	defer s.unlock()
+
+	target, ok := s.projects[targetID]
+	if !ok {
+		return nil, fmt.Errorf("target project not found: %s", targetID)
+	}
+	for _, srcID := range sourceIDs {
+		if srcID == targetID {
+			return nil, fmt.Errorf("source project cannot be the same as target: %s", srcID)
+		}
+		if _, ok := s.projects[srcID]; !ok {
+			return nil, fmt.Errorf("source project not found: %s", srcID)
+		}
+	}
+
+	var movedIssueIDs, deletedSources []string
+	for _, srcID := range sourceIDs {
+		var moved []string
+		for id, issue := range s.issues {
+			if issue.ProjectID == srcID {
+				issue.ProjectID = targetID
+				moved = append(moved, id)
+			}
+		}
+		slices.Sort(moved)
+		movedIssueIDs = append(movedIssueIDs, moved...)
+
+		s.deleteProject(srcID)
+		deletedSources = append(deletedSources, srcID)
+	}
+
+	for _, issueID := range movedIssueIDs {
+		newValue := "merged into " + targetID
+		s.recordEvent(issueID, types.EventMerged, actor, nil, &newValue)
+	}
+
+	out := *target
+	return &types.MergeResult{
+		TargetProject:  &out,
+		IssuesMoved:    len(movedIssueIDs),
+		SourcesDeleted: deletedSources,
+	}, nil
+}
@@ -0,0 +1,281 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// defaultWorkLimit is the default maximum number of issues returned by work queries.
+const defaultWorkLimit = 100
+
+// recentWindow is how recently an issue must have been updated for the
+// hybrid sort policy to order it by priority rather than age.
+const recentWindow = 48 * time.Hour
+
+// unrankedRank stands in for rank 0 (unranked) so unranked issues sort last.
+const unrankedRank = 999999
+
+// openBlockers returns the open issues blocking issueID through 'blocks'
+// dependencies, by priority. Parent-child links are organizational only
+// and never block.
+func (s *Store) openBlockers(issueID string) []*types.Issue {
+	var blockers []*types.Issue
+	for _, d := range s.dependencies {
+		if d.IssueID != issueID || d.Type != types.DepBlocks {
+			continue
+		}
+		if blocker, ok := s.issues[d.DependsOnID]; ok && blocker.Status != types.StatusClosed {
+			blockers = append(blockers, blocker)
+		}
+	}
+	slices.SortStableFunc(blockers, func(a, b *types.Issue) int {
+		return cmp.Or(cmp.Compare(a.Priority, b.Priority), strings.Compare(a.ID, b.ID))
+	})
+	return blockers
+}
+
+// unblockedOpenIssues returns a project's open and in-progress issues that
+// have no open blocker.
+func (s *Store) unblockedOpenIssues(projectID string) []*types.Issue {
+	var issues []*types.Issue
+	for _, issue := range s.issues {
+		if issue.ProjectID != projectID {
+			continue
+		}
+		if issue.Status != types.StatusOpen && issue.Status != types.StatusInProgress {
+			continue
+		}
+		if len(s.openBlockers(issue.ID)) == 0 {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// readyOrder returns the comparison for a sort policy. The hybrid policy
+// puts issues touched in the last 48 hours first, by priority and rank,
+// followed by older issues oldest first.
+func readyOrder(policy types.SortPolicy, now time.Time) func(a, b *types.Issue) int {
+	byPriority := func(a, b *types.Issue) int {
+		return cmp.Or(
+			cmp.Compare(a.Priority, b.Priority),
+			cmp.Compare(effectiveRank(a), effectiveRank(b)),
+			a.CreatedAt.Compare(b.CreatedAt),
+			strings.Compare(a.ID, b.ID),
+		)
+	}
+	byAge := func(a, b *types.Issue) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), strings.Compare(a.ID, b.ID))
+	}
+
+	switch policy {
+	case types.SortPolicyPriority:
+		return byPriority
+	case types.SortPolicyOldest:
+		return byAge
+	default:
+		cutoff := now.Add(-recentWindow)
+		return func(a, b *types.Issue) int {
+			aRecent, bRecent := !a.UpdatedAt.Before(cutoff), !b.UpdatedAt.Before(cutoff)
+			switch {
+			case aRecent && bRecent:
+				return byPriority(a, b)
+			case aRecent:
+				return -1
+			case bRecent:
+				return 1
+			default:
+				return byAge(a, b)
+			}
+		}
+	}
+}
+
+func effectiveRank(issue *types.Issue) int {
+	if issue.Rank == 0 {
+		return unrankedRank
+	}
+	return issue.Rank
+}
+
+// matchesWork reports whether issue passes the predicates of filter that
+// ready and blocked queries share, other than the cursor.
+func (s *Store) matchesWork(issue *types.Issue, filter types.WorkFilter) bool {
+	if filter.IssueType != nil && issue.IssueType != *filter.IssueType {
+		return false
+	}
+	if filter.Priority != nil && issue.Priority != *filter.Priority {
+		return false
+	}
+	if filter.Status != nil && issue.Status != *filter.Status {
+		return false
+	}
+	if filter.ParentID != "" && !s.hasDependency(issue.ID, filter.ParentID, types.DepParentChild) {
+		return false
+	}
+	return s.hasLabels(issue.ID, filter.Labels)
+}
+
+// after keeps the issues of a sorted result that order after the issue
+// with ID cursor, as the SQL backends do. An unknown cursor matches nothing.
+func after[T any](issues []T, cursor *types.Issue, order func(a, b T) int, wrap func(*types.Issue) T) []T {
+	if cursor == nil {
+		return nil
+	}
+	i, found := slices.BinarySearchFunc(issues, wrap(cursor), order)
+	if found {
+		i++
+	}
+	return issues[i:]
+}
+
+// GetReadyWork returns issues that are ready to work on (not blocked).
+// Results are sorted according to the filter's SortPolicy (hybrid, priority, or oldest).
+// Every filter is applied before the page is cut, as in the SQL backends.
+func (s *Store) GetReadyWork(_ context.Context, filter types.WorkFilter) ([]*types.Issue, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultWorkLimit
+	}
+
+	sortPolicy := filter.SortPolicy
+	if sortPolicy == "" || !sortPolicy.IsValid() {
+		sortPolicy = types.SortPolicyHybrid
+	}
+
+	s.lock()
+	defer s.unlock()
+
+	var ready []*types.Issue
+	for _, issue := range s.unblockedOpenIssues(filter.ProjectID) {
+		if s.matchesWork(issue, filter) {
+			ready = append(ready, issue)
+		}
+	}
+	order := readyOrder(sortPolicy, time.Now())
+	slices.SortFunc(ready, order)
+	if filter.After != "" {
+		ready = after(ready, s.issues[filter.After], order, func(i *types.Issue) *types.Issue { return i })
+	}
+
+	issues := []*types.Issue{}
+	for _, issue := range page(ready, limit, max(filter.Offset, 0)) {
+		issues = append(issues, cloneIssue(issue))
+	}
+	return issues, nil
+}
+
+// GetBlockedIssues returns issues that are blocked by other issues.
+// For each blocked issue, it also lists the IDs of the issues blocking it.
+func (s *Store) GetBlockedIssues(_ context.Context, filter types.WorkFilter) ([]*types.BlockedIssue, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultWorkLimit
+	}
+
+	s.lock()
+	defer s.unlock()
+
+	issues := []*types.BlockedIssue{}
+	for _, issue := range s.issues {
+		if issue.ProjectID != filter.ProjectID || issue.Status == types.StatusClosed {
+			continue
+		}
+		if !s.matchesWork(issue, filter) {
+			continue
+		}
+		blockers := s.openBlockers(issue.ID)
+		if len(blockers) == 0 {
+			continue
+		}
+		blockedBy := make([]string, len(blockers))
+		for i, b := range blockers {
+			blockedBy[i] = b.ID
+		}
+		issues = append(issues, &types.BlockedIssue{
+			Issue:          *cloneIssue(issue),
+			BlockedByCount: len(blockedBy),
+			BlockedBy:      blockedBy,
+		})
+	}
+	order := func(a, b *types.BlockedIssue) int {
+		return cmp.Or(cmp.Compare(a.Priority, b.Priority), strings.Compare(a.ID, b.ID))
+	}
+	slices.SortFunc(issues, order)
+	if filter.After != "" {
+		issues = after(issues, s.issues[filter.After], order, func(i *types.Issue) *types.BlockedIssue {
+			return &types.BlockedIssue{Issue: *i}
+		})
+	}
+
+	offset := max(filter.Offset, 0)
+	if offset >= len(issues) {
+		return []*types.BlockedIssue{}, nil
+	}
+	issues = issues[offset:]
+	if len(issues) > limit {
+		issues = issues[:limit]
+	}
+	return issues, nil
+}
+
+// IsBlocked checks if an issue is blocked by any open issues.
+// Returns true and the list of blocking issue IDs if blocked, false otherwise.
+func (s *Store) IsBlocked(_ context.Context, issueID string) (bool, []string, error) {
+	s.lock()
+	defer s.unlock()
+
+	blockers := s.openBlockers(issueID)
+	if len(blockers) == 0 {
+		return false, nil, nil
+	}
+	ids := make([]string, len(blockers))
+	for i, b := range blockers {
+		ids[i] = b.ID
+	}
+	return true, ids, nil
+}
+
+// GetStatistics returns aggregate statistics for a project.
+// Includes counts by status, ready issue count, and average lead time.
+func (s *Store) GetStatistics(_ context.Context, projectID string) (*types.Statistics, error) {
+	s.lock()
+	defer s.unlock()
+
+	stats := &types.Statistics{ProjectID: projectID}
+	var leadHours float64
+	var closedWithTime int
+	for _, issue := range s.issues {
+		if issue.ProjectID != projectID {
+			continue
+		}
+		stats.TotalIssues++
+		switch issue.Status {
+		case types.StatusOpen:
+			stats.OpenIssues++
+		case types.StatusInProgress:
+			stats.InProgressIssues++
+		case types.StatusClosed:
+			stats.ClosedIssues++
+			if issue.ClosedAt != nil {
+				leadHours += issue.ClosedAt.Sub(issue.CreatedAt).Hours()
+				closedWithTime++
+			}
+		case types.StatusBlocked:
+			stats.BlockedIssues++
+		case types.StatusDeferred:
+			stats.DeferredIssues++
+		}
+	}
+	if closedWithTime > 0 {
+		stats.AvgLeadTimeHours = leadHours / float64(closedWithTime)
+	}
+	stats.ReadyIssues = len(s.unblockedOpenIssues(projectID))
+
+	return stats, nil
+}
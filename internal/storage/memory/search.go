@@ -0,0 +1,273 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// Search weights, matching the bm25 column weights of the SQLite backend.
+const (
+	titleWeight       = 10
+	descriptionWeight = 5
+	commentsWeight    = 2
+	labelsWeight      = 3
+	plansWeight       = 1
+)
+
+// snippetWords is the length of a search snippet, in words.
+const snippetWords = 16
+
+// searchTerm is one term or quoted phrase of a search query, as lowercase
+// words. A term matches any word it prefixes; a phrase matches its words
+// in sequence.
+type searchTerm struct {
+	words  []string
+	phrase bool
+}
+
+// searchField is one searchable field of an issue.
+type searchField struct {
+	field  types.SearchField
+	text   string
+	weight int
+}
+
+// searchFields returns the searchable fields of an issue, in order of
+// preference.
+func (s *Store) searchFields(issue *types.Issue) []searchField {
+	var comments []string
+	for _, c := range s.comments {
+		if c.IssueID == issue.ID {
+			comments = append(comments, c.Text)
+		}
+	}
+	labels := slices.Sorted(maps.Keys(s.issueLabels[issue.ID]))
+	var plans []string
+	for _, link := range s.issuePlans {
+		if link.issueID == issue.ID {
+			plans = append(plans, s.planText[link.planID])
+		}
+	}
+	return []searchField{
+		{types.SearchFieldTitle, issue.Title, titleWeight},
+		{types.SearchFieldDescription, issue.Description, descriptionWeight},
+		{types.SearchFieldComments, strings.Join(comments, "\n"), commentsWeight},
+		{types.SearchFieldLabels, strings.Join(labels, " "), labelsWeight},
+		{types.SearchFieldPlans, strings.Join(plans, "\n"), plansWeight},
+	}
+}
+
+// searchIssues returns the issues of the given projects (all projects if
+// none are given) matching every term of query in their title,
+// description, comments, labels or linked plans. Title matches rank above
+// description matches, then most recently updated first.
+func (s *Store) searchIssues(projectIDs []string, query string) []*types.SearchHit {
+	terms := parseSearchQuery(query)
+	if len(terms) == 0 {
+		return []*types.SearchHit{}
+	}
+
+	type hit struct {
+		issue *types.Issue
+		score int
+		field searchField
+		words []string
+	}
+	var hits []hit
+	for _, issue := range s.issues {
+		if len(projectIDs) > 0 && !slices.Contains(projectIDs, issue.ProjectID) {
+			continue
+		}
+		fields := s.searchFields(issue)
+		words := make([][]string, len(fields))
+		for i, f := range fields {
+			words[i] = searchWords(f.text)
+		}
+		h := hit{issue: issue, field: fields[0], words: words[0]}
+		matched := -1
+		for _, term := range terms {
+			found := false
+			for i, f := range fields {
+				if !term.matches(words[i]) {
+					continue
+				}
+				found = true
+				h.score += f.weight
+				if matched == -1 || i < matched {
+					matched = i
+				}
+			}
+			if !found {
+				h.score = -1
+				break
+			}
+		}
+		if h.score > 0 {
+			h.field, h.words = fields[matched], words[matched]
+			hits = append(hits, h)
+		}
+	}
+	slices.SortFunc(hits, func(a, b hit) int {
+		return cmp.Or(
+			cmp.Compare(b.score, a.score),
+			b.issue.UpdatedAt.Compare(a.issue.UpdatedAt),
+			strings.Compare(a.issue.ID, b.issue.ID),
+		)
+	})
+
+	out := make([]*types.SearchHit, len(hits))
+	for i, h := range hits {
+		out[i] = &types.SearchHit{
+			Issue:   cloneIssue(h.issue),
+			Field:   h.field.field,
+			Snippet: snippet(h.field.text, terms),
+			Score:   float64(h.score),
+		}
+	}
+	return out
+}
+
+// SearchIssues runs a ranked full-text search over issues, their comments
+// and labels, in the given projects or in all of them.
+func (s *Store) SearchIssues(_ context.Context, filter types.SearchFilter) ([]*types.SearchHit, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	s.lock()
+	defer s.unlock()
+
+	hits := page(s.searchIssues(filter.ProjectIDs, filter.Query), limit, max(filter.Offset, 0))
+	if hits == nil {
+		hits = []*types.SearchHit{}
+	}
+	return hits, nil
+}
+
+// snippet returns an excerpt of text around its first match of terms, with
+// every matched word highlighted.
+func snippet(text string, terms []searchTerm) string {
+	spans := wordSpans(text)
+	words := make([]string, len(spans))
+	for i, span := range spans {
+		words[i] = strings.ToLower(text[span[0]:span[1]])
+	}
+	marked := make([]bool, len(words))
+	for _, term := range terms {
+		for i := range words {
+			if term.matchesAt(words, i) {
+				for j := range term.words {
+					marked[i+j] = true
+				}
+			}
+		}
+	}
+	first := max(slices.Index(marked, true), 0)
+	from := max(first-snippetWords/4, 0)
+	to := min(from+snippetWords, len(spans))
+	if from == to {
+		return ""
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	pos := spans[from][0]
+	for i := from; i < to; i++ {
+		b.WriteString(text[pos:spans[i][0]])
+		word := text[spans[i][0]:spans[i][1]]
+		if marked[i] {
+			word = types.HighlightStart + word + types.HighlightEnd
+		}
+		b.WriteString(word)
+		pos = spans[i][1]
+	}
+	if to < len(spans) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// wordSpans returns the byte offsets of each word of text, words being
+// runs of letters and digits as in searchWords.
+func wordSpans(text string) [][2]int {
+	var spans [][2]int
+	start := -1
+	for i, r := range text {
+		inWord := unicode.IsLetter(r) || unicode.IsNumber(r)
+		switch {
+		case inWord && start == -1:
+			start = i
+		case !inWord && start != -1:
+			spans = append(spans, [2]int{start, i})
+			start = -1
+		}
+	}
+	if start != -1 {
+		spans = append(spans, [2]int{start, len(text)})
+	}
+	return spans
+}
+
+// parseSearchQuery splits a query into terms and quoted phrases. An
+// unclosed quote runs to the end of the input.
+func parseSearchQuery(query string) []searchTerm {
+	var terms []searchTerm
+	for remaining := strings.TrimSpace(query); remaining != ""; remaining = strings.TrimSpace(remaining) {
+		var (
+			text   string
+			phrase bool
+		)
+		if remaining[0] == '"' {
+			phrase = true
+			end := strings.IndexByte(remaining[1:], '"')
+			if end == -1 {
+				text, remaining = remaining[1:], ""
+			} else {
+				text, remaining = remaining[1:end+1], remaining[end+2:]
+			}
+		} else {
+			end := strings.IndexAny(remaining, " \t\"")
+			if end == -1 {
+				end = len(remaining)
+			}
+			text, remaining = remaining[:end], remaining[end:]
+		}
+		if words := searchWords(text); len(words) > 0 {
+			terms = append(terms, searchTerm{words: words, phrase: phrase || len(words) > 1})
+		}
+	}
+	return terms
+}
+
+// searchWords splits text into lowercase words of letters and digits.
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+func (t searchTerm) matches(words []string) bool {
+	for i := range words {
+		if t.matchesAt(words, i) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAt reports whether the term matches words starting at index i.
+func (t searchTerm) matchesAt(words []string, i int) bool {
+	if !t.phrase {
+		return strings.HasPrefix(words[i], t.words[0])
+	}
+	return i+len(t.words) <= len(words) && slices.Equal(words[i:i+len(t.words)], t.words)
+}
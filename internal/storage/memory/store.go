@@ -0,0 +1,146 @@
+// Package memory implements the storage interface with in-process maps.
+// Nothing is persisted; the store suits tests and short-lived tools that
+// want the full storage contract without a database file.
+package memory
+
+import (
+	"sync"
+
+	"github.com/sentiolabs/arc/internal/storage"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// Path is the value returned by Store.Path.
+const Path = ":memory:"
+
+// Store implements the storage.Storage interface in memory. It is safe for
+// concurrent use. Values are copied on the way in and out, so callers never
+// share memory with the store.
+type Store struct {
+	mu        sync.Mutex
+	publisher storage.Publisher
+	pending   []types.StreamEvent // published when mu is released
+
+	projects      map[string]*types.Project
+	config        map[string]map[string]string // project ID -> key -> value
+	workspaces    map[string]*types.Workspace
+	issues        map[string]*types.Issue
+	dependencies  []*types.Dependency // insertion order
+	labels        map[string]*types.Label
+	issueLabels   map[string]map[string]bool   // issue ID -> label set
+	issueFields   map[string]map[string]string // issue ID -> key -> value
+	comments      []*types.Comment
+	events        []*types.Event
+	childCounters map[string]int
+	aliases       map[string]string // old issue ID -> current issue ID
+	plans         map[string]*types.Plan
+	planText      map[string]string // plan ID -> text indexed for search
+	issuePlans    []issuePlan       // link order
+	planComments  []*types.PlanComment
+	sessions      map[string]*types.AISession
+	agents        []*types.AIAgent
+	webhooks      map[string]*types.Webhook
+	deliveries    []*types.WebhookDelivery
+	tokens        map[string]*apiToken
+	members       map[string]map[string]*types.ProjectMember // project ID -> actor -> member
+	templates     map[string]map[string]*types.IssueTemplate // project ID -> name -> template
+
+	lastCommentID  int64
+	lastEventID    int64
+	lastDeliveryID int64
+}
+
+// issuePlan links a plan to an issue.
+type issuePlan struct {
+	issueID, planID string
+}
+
+// apiToken is a stored token together with the hash it is looked up by.
+type apiToken struct {
+	types.APIToken
+	hash string
+}
+
+// New returns an empty store.
+func New() *Store {
+	return &Store{
+		projects:      make(map[string]*types.Project),
+		config:        make(map[string]map[string]string),
+		workspaces:    make(map[string]*types.Workspace),
+		issues:        make(map[string]*types.Issue),
+		labels:        make(map[string]*types.Label),
+		issueLabels:   make(map[string]map[string]bool),
+		issueFields:   make(map[string]map[string]string),
+		childCounters: make(map[string]int),
+		aliases:       make(map[string]string),
+		plans:         make(map[string]*types.Plan),
+		planText:      make(map[string]string),
+		sessions:      make(map[string]*types.AISession),
+		webhooks:      make(map[string]*types.Webhook),
+		tokens:        make(map[string]*apiToken),
+		members:       make(map[string]map[string]*types.ProjectMember),
+		templates:     make(map[string]map[string]*types.IssueTemplate),
+	}
+}
+
+// Close is a no-op; the store's contents are dropped with it.
+func (s *Store) Close() error {
+	return nil
+}
+
+// Path returns ":memory:".
+func (s *Store) Path() string {
+	return Path
+}
+
+// SetPublisher registers the receiver of live change notifications.
+// A nil publisher disables publishing.
+func (s *Store) SetPublisher(p storage.Publisher) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.publisher = p
+}
+
+// lock acquires the store mutex. Every exported method holds it for its
+// whole duration; unexported helpers assume it is held.
+func (s *Store) lock() {
+	s.mu.Lock()
+}
+
+// unlock releases the store mutex and then delivers the change
+// notifications queued while it was held, so a publisher that reads the
+// store back cannot deadlock.
+func (s *Store) unlock() {
+	pending, publisher := s.pending, s.publisher
+	s.pending = nil
+	s.mu.Unlock()
+
+	if publisher == nil {
+		return
+	}
+	for _, ev := range pending {
+		publisher.Publish(ev)
+	}
+}
+
+// publish queues a change notification for delivery by unlock.
+func (s *Store) publish(ev types.StreamEvent) {
+	if s.publisher != nil {
+		s.pending = append(s.pending, ev)
+	}
+}
+
+// issueProjectID returns the project an issue belongs to, or "" if the
+// issue cannot be found. Used to scope change notifications.
+func (s *Store) issueProjectID(issueID string) string {
+	if issue, ok := s.issues[issueID]; ok {
+		return issue.ProjectID
+	}
+	return ""
+}
+
+// Ensure Store implements storage.Storage and storage.Notifier
+var (
+	_ storage.Storage  = (*Store)(nil)
+	_ storage.Notifier = (*Store)(nil)
+)
@@ -0,0 +1,210 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/project"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// webhookSecretBytes is the entropy of generated webhook secrets.
+const webhookSecretBytes = 32
+
+// CreateWebhook registers a webhook. A random secret is generated when none
+// is provided; the caller sees it in wh.Secret.
+func (s *Store) CreateWebhook(_ context.Context, wh *types.Webhook) error {
+	if err := wh.Validate(); err != nil {
+		return fmt.Errorf("validate webhook: %w", err)
+	}
+
+	s.lock()
+	defer s.unlock()
+
+	if wh.ID == "" {
+		wh.ID = project.GenerateProjectID("wh", wh.ProjectID+wh.URL)
+	}
+	if _, ok := s.projects[wh.ProjectID]; !ok {
+		return fmt.Errorf("create webhook: project not found: %s", wh.ProjectID)
+	}
+	if _, ok := s.webhooks[wh.ID]; ok {
+		return fmt.Errorf("create webhook: webhook already exists: %s", wh.ID)
+	}
+	if wh.Secret == "" {
+		buf := make([]byte, webhookSecretBytes)
+		if _, err := rand.Read(buf); err != nil {
+			return fmt.Errorf("generate webhook secret: %w", err)
+		}
+		wh.Secret = hex.EncodeToString(buf)
+	}
+	wh.CreatedAt = time.Now()
+
+	s.webhooks[wh.ID] = cloneWebhook(wh)
+	return nil
+}
+
+// GetWebhook retrieves a webhook by ID, including its secret.
+func (s *Store) GetWebhook(_ context.Context, id string) (*types.Webhook, error) {
+	s.lock()
+	defer s.unlock()
+
+	wh, ok := s.webhooks[id]
+	if !ok {
+		return nil, fmt.Errorf("webhook not found: %s", id)
+	}
+	return cloneWebhook(wh), nil
+}
+
+// ListWebhooks returns a project's webhooks, oldest first.
+func (s *Store) ListWebhooks(_ context.Context, projectID string) ([]*types.Webhook, error) {
+	s.lock()
+	defer s.unlock()
+
+	webhooks := []*types.Webhook{}
+	for _, wh := range s.webhooks {
+		if wh.ProjectID == projectID {
+			webhooks = append(webhooks, cloneWebhook(wh))
+		}
+	}
+	slices.SortFunc(webhooks, func(a, b *types.Webhook) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), strings.Compare(a.ID, b.ID))
+	})
+	return webhooks, nil
+}
+
+// DeleteWebhook removes a webhook and its delivery log.
+func (s *Store) DeleteWebhook(_ context.Context, id string) error {
+	s.lock()
+	defer s.unlock()
+
+	if _, ok := s.webhooks[id]; !ok {
+		return fmt.Errorf("webhook not found: %s", id)
+	}
+	s.deleteWebhook(id)
+	return nil
+}
+
+func (s *Store) deleteWebhook(id string) {
+	s.deliveries = slices.DeleteFunc(s.deliveries, func(d *types.WebhookDelivery) bool { return d.WebhookID == id })
+	delete(s.webhooks, id)
+}
+
+// CreateWebhookDelivery queues a delivery. Status defaults to pending and
+// NextAttemptAt to now.
+func (s *Store) CreateWebhookDelivery(_ context.Context, d *types.WebhookDelivery) error {
+	s.lock()
+	defer s.unlock()
+
+	if _, ok := s.webhooks[d.WebhookID]; !ok {
+		return fmt.Errorf("create webhook delivery: webhook not found: %s", d.WebhookID)
+	}
+
+	now := time.Now().UTC()
+	d.CreatedAt = now
+	if d.Status == "" {
+		d.Status = types.DeliveryPending
+	}
+	if d.NextAttemptAt.IsZero() {
+		d.NextAttemptAt = now
+	}
+
+	s.lastDeliveryID++
+	d.ID = s.lastDeliveryID
+	s.deliveries = append(s.deliveries, cloneDelivery(d))
+	return nil
+}
+
+// GetWebhookDelivery retrieves a single delivery by ID.
+func (s *Store) GetWebhookDelivery(_ context.Context, id int64) (*types.WebhookDelivery, error) {
+	s.lock()
+	defer s.unlock()
+
+	i := s.deliveryIndex(id)
+	if i < 0 {
+		return nil, fmt.Errorf("webhook delivery not found: %d", id)
+	}
+	return cloneDelivery(s.deliveries[i]), nil
+}
+
+// ListWebhookDeliveries returns a webhook's delivery log, newest first.
+func (s *Store) ListWebhookDeliveries(
+	_ context.Context, webhookID string, limit int,
+) ([]*types.WebhookDelivery, error) {
+	s.lock()
+	defer s.unlock()
+
+	deliveries := []*types.WebhookDelivery{}
+	for i := len(s.deliveries) - 1; i >= 0 && len(deliveries) < limit; i-- {
+		if d := s.deliveries[i]; d.WebhookID == webhookID {
+			deliveries = append(deliveries, cloneDelivery(d))
+		}
+	}
+	return deliveries, nil
+}
+
+// ListDueWebhookDeliveries returns pending deliveries whose next attempt is
+// at or before now, oldest first.
+func (s *Store) ListDueWebhookDeliveries(
+	_ context.Context, now time.Time, limit int,
+) ([]*types.WebhookDelivery, error) {
+	s.lock()
+	defer s.unlock()
+
+	deliveries := []*types.WebhookDelivery{}
+	for _, d := range s.deliveries {
+		if d.Status == types.DeliveryPending && !d.NextAttemptAt.After(now) {
+			deliveries = append(deliveries, cloneDelivery(d))
+		}
+	}
+	slices.SortStableFunc(deliveries, func(a, b *types.WebhookDelivery) int {
+		return a.NextAttemptAt.Compare(b.NextAttemptAt)
+	})
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+// UpdateWebhookDelivery records the outcome of a delivery attempt.
+func (s *Store) UpdateWebhookDelivery(_ context.Context, d *types.WebhookDelivery) error {
+	s.lock()
+	defer s.unlock()
+
+	i := s.deliveryIndex(d.ID)
+	if i < 0 {
+		return nil
+	}
+	stored := s.deliveries[i]
+	stored.Status = d.Status
+	stored.Attempts = d.Attempts
+	stored.NextAttemptAt = d.NextAttemptAt
+	stored.LastAttemptAt = cloneTime(d.LastAttemptAt)
+	stored.ResponseStatus = cloneInt(d.ResponseStatus)
+	stored.LastError = d.LastError
+	stored.DeliveredAt = cloneTime(d.DeliveredAt)
+	return nil
+}
+
+func (s *Store) deliveryIndex(id int64) int {
+	return slices.IndexFunc(s.deliveries, func(d *types.WebhookDelivery) bool { return d.ID == id })
+}
+
+func cloneWebhook(wh *types.Webhook) *types.Webhook {
+	out := *wh
+	out.EventTypes = slices.Clone(wh.EventTypes)
+	return &out
+}
+
+func cloneDelivery(d *types.WebhookDelivery) *types.WebhookDelivery {
+	out := *d
+	out.LastAttemptAt = cloneTime(d.LastAttemptAt)
+	out.ResponseStatus = cloneInt(d.ResponseStatus)
+	out.DeliveredAt = cloneTime(d.DeliveredAt)
+	return &out
+}
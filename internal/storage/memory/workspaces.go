@@ -0,0 +1,167 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/project"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// CreateWorkspace creates a new workspace (directory path entry).
+func (s *Store) CreateWorkspace(_ context.Context, ws *types.Workspace) error {
+	if err := ws.Validate(); err != nil {
+		return fmt.Errorf("validate workspace: %w", err)
+	}
+
+	s.lock()
+	defer s.unlock()
+
+	if ws.ID == "" {
+		ws.ID = project.GenerateProjectID("ws", ws.Path)
+	}
+	if err := s.checkNewWorkspace(ws); err != nil {
+		return fmt.Errorf("create workspace: %w", err)
+	}
+
+	now := time.Now()
+	ws.CreatedAt = now
+	ws.UpdatedAt = now
+
+	if ws.PathType == "" {
+		ws.PathType = "canonical"
+	}
+
+	s.workspaces[ws.ID] = cloneWorkspace(ws)
+	return nil
+}
+
+// checkNewWorkspace enforces the constraints a new workspace must meet.
+func (s *Store) checkNewWorkspace(ws *types.Workspace) error {
+	if _, ok := s.projects[ws.ProjectID]; !ok {
+		return fmt.Errorf("project not found: %s", ws.ProjectID)
+	}
+	if _, ok := s.workspaces[ws.ID]; ok {
+		return fmt.Errorf("workspace already exists: %s", ws.ID)
+	}
+	for _, other := range s.workspaces {
+		if other.ProjectID == ws.ProjectID && other.Path == ws.Path {
+			return fmt.Errorf("path already registered for project: %s", ws.Path)
+		}
+	}
+	return nil
+}
+
+// GetWorkspace retrieves a workspace by its unique ID.
+// Returns an error if no workspace exists with the given ID.
+func (s *Store) GetWorkspace(_ context.Context, id string) (*types.Workspace, error) {
+	s.lock()
+	defer s.unlock()
+
+	ws, ok := s.workspaces[id]
+	if !ok {
+		return nil, fmt.Errorf("workspace not found: %s", id)
+	}
+	return cloneWorkspace(ws), nil
+}
+
+// ListWorkspaces returns all workspaces for a project.
+func (s *Store) ListWorkspaces(_ context.Context, projectID string) ([]*types.Workspace, error) {
+	s.lock()
+	defer s.unlock()
+
+	return s.listWorkspaces(projectID), nil
+}
+
+func (s *Store) listWorkspaces(projectID string) []*types.Workspace {
+	result := []*types.Workspace{}
+	for _, ws := range s.workspaces {
+		if ws.ProjectID == projectID {
+			result = append(result, cloneWorkspace(ws))
+		}
+	}
+	slices.SortFunc(result, func(a, b *types.Workspace) int { return strings.Compare(a.Path, b.Path) })
+	return result
+}
+
+// UpdateWorkspace updates a workspace entry.
+func (s *Store) UpdateWorkspace(_ context.Context, ws *types.Workspace) error {
+	s.lock()
+	defer s.unlock()
+
+	ws.UpdatedAt = time.Now()
+
+	if ws.PathType == "" {
+		ws.PathType = "canonical"
+	}
+
+	if stored, ok := s.workspaces[ws.ID]; ok {
+		stored.Label = ws.Label
+		stored.Hostname = ws.Hostname
+		stored.GitRemote = ws.GitRemote
+		stored.PathType = ws.PathType
+		stored.UpdatedAt = ws.UpdatedAt
+	}
+	return nil
+}
+
+// DeleteWorkspace removes a workspace entry.
+func (s *Store) DeleteWorkspace(_ context.Context, id string) error {
+	s.lock()
+	defer s.unlock()
+
+	delete(s.workspaces, id)
+	return nil
+}
+
+// ResolveProjectByPath finds the workspace whose path is `path` exactly or
+// is the longest registered ancestor of `path` (component-wise).
+func (s *Store) ResolveProjectByPath(_ context.Context, path string) (*types.Workspace, error) {
+	s.lock()
+	defer s.unlock()
+
+	var best *types.Workspace
+	for _, ws := range s.workspaces {
+		if ws.Path != path && !strings.HasPrefix(path, ws.Path+"/") {
+			continue
+		}
+		if best == nil || len(ws.Path) > len(best.Path) {
+			best = ws
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("workspace not found for path: %s", path)
+	}
+	return cloneWorkspace(best), nil
+}
+
+// UpdateWorkspaceLastAccessed updates the last_accessed_at timestamp for a workspace.
+func (s *Store) UpdateWorkspaceLastAccessed(_ context.Context, id string) error {
+	s.lock()
+	defer s.unlock()
+
+	if ws, ok := s.workspaces[id]; ok {
+		now := time.Now()
+		ws.LastAccessedAt = &now
+		ws.UpdatedAt = now
+	}
+	return nil
+}
+
+func cloneWorkspace(ws *types.Workspace) *types.Workspace {
+	out := *ws
+	out.LastAccessedAt = cloneTime(ws.LastAccessedAt)
+	return &out
+}
+
+// cloneTime copies an optional timestamp.
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	v := *t
+	return &v
+}
@@ -0,0 +1,238 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+const (
+	aiSessionColumns = `id, project_id, transcript_path, cwd, started_at`
+	aiAgentColumns   = `id, session_id, description, prompt, agent_type, model, status,
+		duration_ms, total_tokens, tool_use_count, created_at`
+)
+
+// CreateAISession creates a new AI session record.
+func (s *Store) CreateAISession(ctx context.Context, session *types.AISession) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO ai_sessions (`+aiSessionColumns+`) VALUES ($1, $2, $3, $4, $5)
+	`, session.ID, session.ProjectID, session.TranscriptPath, toNullString(session.CWD), session.StartedAt)
+	if err != nil {
+		return fmt.Errorf("create ai session: %w", err)
+	}
+
+	s.publish(types.StreamEvent{
+		Type:      types.StreamAISessionCreated,
+		ProjectID: session.ProjectID,
+		SessionID: session.ID,
+	})
+	return nil
+}
+
+// GetAISession retrieves an AI session by ID.
+func (s *Store) GetAISession(ctx context.Context, id string) (*types.AISession, error) {
+	session, err := scanAISession(s.db.QueryRowContext(ctx,
+		`SELECT `+aiSessionColumns+` FROM ai_sessions WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("ai session not found: %s", id)
+		}
+		return nil, fmt.Errorf("get ai session: %w", err)
+	}
+	return session, nil
+}
+
+// ListAISessionsByProject returns AI sessions for a project ordered by started_at descending.
+func (s *Store) ListAISessionsByProject(
+	ctx context.Context, projectID string, limit, offset int,
+) ([]*types.AISession, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+aiSessionColumns+` FROM ai_sessions
+		WHERE project_id = $1
+		ORDER BY started_at DESC
+		LIMIT $2 OFFSET $3
+	`, projectID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("list ai sessions by project: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []*types.AISession{}
+	for rows.Next() {
+		session, err := scanAISession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan ai session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+// CountAISessionsByProject returns the total number of AI sessions for a project.
+func (s *Store) CountAISessionsByProject(ctx context.Context, projectID string) (int64, error) {
+	var count int64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT count(*) FROM ai_sessions WHERE project_id = $1`, projectID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("count ai sessions by project: %w", err)
+	}
+	return count, nil
+}
+
+// DeleteAISession deletes an AI session; its agents go with it through
+// ON DELETE CASCADE.
+func (s *Store) DeleteAISession(ctx context.Context, id string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM ai_sessions WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("delete ai session: %w", err)
+	}
+	return nil
+}
+
+// CreateAIAgent creates a new AI agent record.
+func (s *Store) CreateAIAgent(ctx context.Context, agent *types.AIAgent) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO ai_agents (`+aiAgentColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`, agent.ID, agent.SessionID, toNullString(agent.Description), toNullString(agent.Prompt),
+		toNullString(agent.AgentType), toNullString(agent.Model), agent.Status,
+		nullInt(agent.DurationMs), nullInt(agent.TotalTokens), nullInt(agent.ToolUseCount),
+		agent.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("create ai agent: %w", err)
+	}
+
+	if s.publisher != nil {
+		var projectID string
+		if session, err := s.GetAISession(ctx, agent.SessionID); err == nil {
+			projectID = session.ProjectID
+		}
+		s.publish(types.StreamEvent{
+			Type:      types.StreamAIAgentRegistered,
+			ProjectID: projectID,
+			SessionID: agent.SessionID,
+			Data:      map[string]any{"agent_id": agent.ID, "status": agent.Status},
+		})
+	}
+	return nil
+}
+
+// GetAIAgent retrieves an AI agent by ID.
+func (s *Store) GetAIAgent(ctx context.Context, id string) (*types.AIAgent, error) {
+	agent, err := scanAIAgent(s.db.QueryRowContext(ctx,
+		`SELECT `+aiAgentColumns+` FROM ai_agents WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("ai agent not found: %s", id)
+		}
+		return nil, fmt.Errorf("get ai agent: %w", err)
+	}
+	return agent, nil
+}
+
+// ListAIAgents returns AI agents for a session ordered by created_at ascending.
+func (s *Store) ListAIAgents(ctx context.Context, sessionID string) ([]*types.AIAgent, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+aiAgentColumns+` FROM ai_agents
+		WHERE session_id = $1
+		ORDER BY created_at ASC
+	`, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("list ai agents: %w", err)
+	}
+	defer rows.Close()
+
+	agents := []*types.AIAgent{}
+	for rows.Next() {
+		agent, err := scanAIAgent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan ai agent: %w", err)
+		}
+		agents = append(agents, agent)
+	}
+	return agents, rows.Err()
+}
+
+// GetAgentSummariesForSessions returns aggregated agent status counts for each session.
+func (s *Store) GetAgentSummariesForSessions(
+	ctx context.Context, sessionIDs []string,
+) (map[string]*types.AgentSummary, error) {
+	result := make(map[string]*types.AgentSummary)
+	if len(sessionIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT session_id,
+		       count(*),
+		       count(*) FILTER (WHERE status = 'running'),
+		       count(*) FILTER (WHERE status = 'completed'),
+		       count(*) FILTER (WHERE status = 'error')
+		FROM ai_agents
+		WHERE session_id = ANY($1)
+		GROUP BY session_id
+	`, sessionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("get agent summaries for sessions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			sessionID string
+			summary   types.AgentSummary
+		)
+		err := rows.Scan(&sessionID, &summary.AgentCount,
+			&summary.RunningCount, &summary.CompletedCount, &summary.ErrorCount)
+		if err != nil {
+			return nil, fmt.Errorf("scan agent summary: %w", err)
+		}
+		result[sessionID] = &summary
+	}
+	return result, rows.Err()
+}
+
+func scanAISession(row rowScanner) (*types.AISession, error) {
+	var (
+		session types.AISession
+		cwd     sql.NullString
+	)
+	err := row.Scan(&session.ID, &session.ProjectID, &session.TranscriptPath, &cwd, &session.StartedAt)
+	if err != nil {
+		return nil, err
+	}
+	session.CWD = fromNullString(cwd)
+	return &session, nil
+}
+
+func scanAIAgent(row rowScanner) (*types.AIAgent, error) {
+	var (
+		agent                                 types.AIAgent
+		description, prompt, agentType, model sql.NullString
+		durationMs, totalTokens, toolUseCount sql.NullInt64
+	)
+	err := row.Scan(&agent.ID, &agent.SessionID, &description, &prompt, &agentType, &model,
+		&agent.Status, &durationMs, &totalTokens, &toolUseCount, &agent.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	agent.Description = fromNullString(description)
+	agent.Prompt = fromNullString(prompt)
+	agent.AgentType = fromNullString(agentType)
+	agent.Model = fromNullString(model)
+	agent.DurationMs = intPtr(durationMs)
+	agent.TotalTokens = intPtr(totalTokens)
+	agent.ToolUseCount = intPtr(toolUseCount)
+	return &agent, nil
+}
+
+// intPtr converts a sql.NullInt64 to an *int, nil when NULL.
+func intPtr(n sql.NullInt64) *int {
+	if !n.Valid {
+		return nil
+	}
+	v := int(n.Int64)
+	return &v
+}
@@ -0,0 +1,444 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/depgraph"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// ExportProject reads a complete copy of a project. Dependencies are
+// included only when both ends belong to the project.
+func (s *Store) ExportProject(ctx context.Context, projectID string) (*types.ProjectArchive, error) {
+	proj, err := s.GetProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	a := &types.ProjectArchive{Project: proj}
+
+	if a.Config, err = s.GetProjectConfig(ctx, projectID); err != nil {
+		return nil, err
+	}
+	if a.Workspaces, err = s.ListWorkspaces(ctx, projectID); err != nil {
+		return nil, err
+	}
+	if a.Issues, err = s.exportIssues(ctx, projectID); err != nil {
+		return nil, err
+	}
+	if a.Labels, err = s.exportLabels(ctx, a.Issues); err != nil {
+		return nil, err
+	}
+	if a.Dependencies, err = s.exportDependencies(ctx, projectID); err != nil {
+		return nil, err
+	}
+	if a.Comments, err = s.exportComments(ctx, projectID); err != nil {
+		return nil, err
+	}
+	if a.Events, err = s.exportEvents(ctx, projectID); err != nil {
+		return nil, err
+	}
+	if a.Plans, err = s.exportPlans(ctx, a.Workspaces); err != nil {
+		return nil, err
+	}
+	for _, plan := range a.Plans {
+		comments, err := s.ListPlanComments(ctx, plan.ID)
+		if err != nil {
+			return nil, err
+		}
+		a.PlanComments = append(a.PlanComments, comments...)
+	}
+
+	return a, nil
+}
+
+func (s *Store) exportIssues(ctx context.Context, projectID string) ([]*types.Issue, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+issueColumns+`
+		FROM issues i WHERE i.project_id = $1 ORDER BY i.created_at, i.id
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("export issues: %w", err)
+	}
+	issues, err := scanIssues(rows)
+	if err != nil {
+		return nil, fmt.Errorf("export issues: %w", err)
+	}
+	if issues == nil {
+		issues = []*types.Issue{}
+	}
+	for _, issue := range issues {
+		issue.Version = 0 // versions are local to a store and not archived
+	}
+
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	labels, err := s.GetLabelsForIssues(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := s.GetFieldsForIssues(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, issue := range issues {
+		issue.Labels = labels[issue.ID]
+		issue.Fields = fields[issue.ID]
+	}
+	return issues, nil
+}
+
+// exportLabels returns the definitions of every label used by issues.
+func (s *Store) exportLabels(ctx context.Context, issues []*types.Issue) ([]*types.Label, error) {
+	seen := make(map[string]bool)
+	labels := []*types.Label{}
+	for _, issue := range issues {
+		for _, name := range issue.Labels {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			label, err := s.GetLabel(ctx, name)
+			if err != nil {
+				// Labels can be attached without a definition.
+				label = &types.Label{Name: name}
+			}
+			labels = append(labels, label)
+		}
+	}
+	return labels, nil
+}
+
+func (s *Store) exportDependencies(ctx context.Context, projectID string) ([]*types.Dependency, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT d.issue_id, d.depends_on_id, d.type, d.created_at, d.created_by
+		FROM dependencies d
+		JOIN issues a ON a.id = d.issue_id
+		JOIN issues b ON b.id = d.depends_on_id
+		WHERE a.project_id = $1 AND b.project_id = $1
+		ORDER BY d.created_at, d.issue_id, d.depends_on_id
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("export dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	deps := []*types.Dependency{}
+	for rows.Next() {
+		var (
+			dep       types.Dependency
+			depType   string
+			createdBy sql.NullString
+		)
+		if err := rows.Scan(&dep.IssueID, &dep.DependsOnID, &depType, &dep.CreatedAt, &createdBy); err != nil {
+			return nil, fmt.Errorf("scan dependency: %w", err)
+		}
+		dep.Type = types.DependencyType(depType)
+		dep.CreatedBy = fromNullString(createdBy)
+		deps = append(deps, &dep)
+	}
+	return deps, rows.Err()
+}
+
+func (s *Store) exportComments(ctx context.Context, projectID string) ([]*types.Comment, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT c.id, c.issue_id, c.author, c.text, c.created_at, c.updated_at
+		FROM comments c JOIN issues i ON i.id = c.issue_id
+		WHERE i.project_id = $1 ORDER BY c.id
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("export comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []*types.Comment{}
+	for rows.Next() {
+		var (
+			c         types.Comment
+			updatedAt sql.NullTime
+		)
+		if err := rows.Scan(&c.ID, &c.IssueID, &c.Author, &c.Text, &c.CreatedAt, &updatedAt); err != nil {
+			return nil, fmt.Errorf("scan comment: %w", err)
+		}
+		if updatedAt.Valid {
+			c.UpdatedAt = updatedAt.Time
+		}
+		comments = append(comments, &c)
+	}
+	return comments, rows.Err()
+}
+
+func (s *Store) exportEvents(ctx context.Context, projectID string) ([]*types.Event, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT e.id, e.issue_id, e.event_type, e.actor, e.old_value, e.new_value, e.comment, e.created_at
+		FROM events e JOIN issues i ON i.id = e.issue_id
+		WHERE i.project_id = $1 ORDER BY e.id
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("export events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*types.Event{}
+	for rows.Next() {
+		var (
+			e                           types.Event
+			eventType                   string
+			oldValue, newValue, comment sql.NullString
+		)
+		if err := rows.Scan(&e.ID, &e.IssueID, &eventType, &e.Actor, &oldValue, &newValue, &comment, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan event: %w", err)
+		}
+		e.EventType = types.EventType(eventType)
+		e.OldValue = nullStringToPtr(oldValue)
+		e.NewValue = nullStringToPtr(newValue)
+		e.Comment = nullStringToPtr(comment)
+		events = append(events, &e)
+	}
+	return events, rows.Err()
+}
+
+// exportPlans returns plans whose file lives under one of the workspaces.
+func (s *Store) exportPlans(ctx context.Context, workspaces []*types.Workspace) ([]*types.Plan, error) {
+	if len(workspaces) == 0 {
+		return []*types.Plan{}, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, file_path, status, created_at, updated_at FROM plans ORDER BY created_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("export plans: %w", err)
+	}
+	defer rows.Close()
+
+	plans := []*types.Plan{}
+	for rows.Next() {
+		var plan types.Plan
+		if err := rows.Scan(&plan.ID, &plan.FilePath, &plan.Status, &plan.CreatedAt, &plan.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan plan: %w", err)
+		}
+		for _, ws := range workspaces {
+			if pathWithin(plan.FilePath, ws.Path) {
+				plans = append(plans, &plan)
+				break
+			}
+		}
+	}
+	return plans, rows.Err()
+}
+
+// pathWithin reports whether path is dir or lies beneath it.
+func pathWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// ImportProject writes an archive in a single transaction, keeping every
+// ID and timestamp as given. The project is created unless it already
+// exists, in which case the archive is added to it. Comment and event IDs
+// are reassigned. Any conflict with existing rows aborts the whole import.
+func (s *Store) ImportProject(ctx context.Context, a *types.ProjectArchive) error {
+	if a.Project == nil {
+		return errors.New("archive has no project")
+	}
+	if err := a.Project.Validate(); err != nil {
+		return fmt.Errorf("validate project: %w", err)
+	}
+	pID := a.Project.ID
+	for _, issue := range a.Issues {
+		issue.ProjectID = pID
+		if err := issue.Validate(); err != nil {
+			return fmt.Errorf("validate issue %s: %w", issue.ID, err)
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if err := importProjectRows(ctx, tx, a); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit import: %w", err)
+	}
+	return nil
+}
+
+//nolint:gocognit,funlen // one linear pass over each record kind
+func importProjectRows(ctx context.Context, tx *sql.Tx, a *types.ProjectArchive) error {
+	p := a.Project
+	now := time.Now()
+
+	var exists int
+	err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM projects WHERE id = $1`, p.ID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("check project: %w", err)
+	}
+	if exists == 0 {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO projects (id, name, description, prefix, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, p.ID, p.Name, toNullString(p.Description), p.Prefix, orNow(p.CreatedAt, now), orNow(p.UpdatedAt, now))
+		if err != nil {
+			return fmt.Errorf("import project: %w", err)
+		}
+	}
+
+	for key, value := range a.Config {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO config (project_id, key, value) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+			p.ID, key, value); err != nil {
+			return fmt.Errorf("import config %s: %w", key, err)
+		}
+	}
+
+	for _, ws := range a.Workspaces {
+		pathType := ws.PathType
+		if pathType == "" {
+			pathType = "canonical"
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO workspaces (id, project_id, path, label, hostname, git_remote, path_type,
+			                        last_accessed_at, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		`, ws.ID, p.ID, ws.Path, toNullString(ws.Label), toNullString(ws.Hostname), toNullString(ws.GitRemote),
+			pathType, toNullTime(ws.LastAccessedAt), orNow(ws.CreatedAt, now), orNow(ws.UpdatedAt, now)); err != nil {
+			return fmt.Errorf("import workspace %s: %w", ws.Path, err)
+		}
+	}
+
+	for _, label := range a.Labels {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO labels (name, color, description) VALUES ($1, $2, $3) ON CONFLICT (name) DO NOTHING`,
+			label.Name, toNullString(label.Color), toNullString(label.Description)); err != nil {
+			return fmt.Errorf("import label %s: %w", label.Name, err)
+		}
+	}
+
+	imported := make(map[string]bool, len(a.Issues))
+	for _, issue := range a.Issues {
+		imported[issue.ID] = true
+	}
+	childCounters := make(map[string]int)
+	for _, issue := range a.Issues {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO issues (id, project_id, title, description, status, priority, issue_type,
+			                    ai_session_id, external_ref, rank, created_at, updated_at, closed_at, close_reason)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		`, issue.ID, p.ID, issue.Title, toNullString(issue.Description), string(issue.Status), issue.Priority,
+			string(issue.IssueType), toNullString(issue.AISessionID), toNullString(issue.ExternalRef), issue.Rank,
+			orNow(issue.CreatedAt, now), orNow(issue.UpdatedAt, now), toNullTime(issue.ClosedAt),
+			toNullString(issue.CloseReason)); err != nil {
+			return fmt.Errorf("import issue %s: %w", issue.ID, err)
+		}
+		for _, label := range issue.Labels {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO issue_labels (issue_id, label) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+				issue.ID, label); err != nil {
+				return fmt.Errorf("import label %s on %s: %w", label, issue.ID, err)
+			}
+		}
+		for key, value := range issue.Fields {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO issue_fields (issue_id, key, value) VALUES ($1, $2, $3)`,
+				issue.ID, key, value); err != nil {
+				return fmt.Errorf("import field %s on %s: %w", key, issue.ID, err)
+			}
+		}
+		if parentID := depgraph.HierarchicalParent(issue.ID); parentID != "" && imported[parentID] {
+			n, _ := strconv.Atoi(issue.ID[len(parentID)+1:])
+			childCounters[parentID] = max(childCounters[parentID], n)
+		}
+	}
+
+	// Keep child ID generation from reusing imported child numbers.
+	for parentID, last := range childCounters {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO child_counters (parent_id, last_child) VALUES ($1, $2)
+			ON CONFLICT (parent_id) DO UPDATE SET last_child = GREATEST(child_counters.last_child, excluded.last_child)
+		`, parentID, last); err != nil {
+			return fmt.Errorf("import child counter for %s: %w", parentID, err)
+		}
+	}
+
+	for _, dep := range a.Dependencies {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO dependencies (issue_id, depends_on_id, type, created_at, created_by)
+			VALUES ($1, $2, $3, $4, $5)
+		`, dep.IssueID, dep.DependsOnID, string(dep.Type), orNow(dep.CreatedAt, now),
+			toNullString(dep.CreatedBy)); err != nil {
+			return fmt.Errorf("import dependency %s -> %s: %w", dep.IssueID, dep.DependsOnID, err)
+		}
+	}
+
+	for _, c := range a.Comments {
+		var updatedAt sql.NullTime
+		if !c.UpdatedAt.IsZero() {
+			updatedAt = sql.NullTime{Time: c.UpdatedAt, Valid: true}
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO comments (issue_id, author, text, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)`,
+			c.IssueID, c.Author, c.Text, orNow(c.CreatedAt, now), updatedAt); err != nil {
+			return fmt.Errorf("import comment on %s: %w", c.IssueID, err)
+		}
+	}
+
+	for _, e := range a.Events {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO events (issue_id, event_type, actor, old_value, new_value, comment, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, e.IssueID, string(e.EventType), e.Actor, toNullString(ptrToString(e.OldValue)),
+			toNullString(ptrToString(e.NewValue)), toNullString(ptrToString(e.Comment)),
+			orNow(e.CreatedAt, now)); err != nil {
+			return fmt.Errorf("import event on %s: %w", e.IssueID, err)
+		}
+	}
+
+	for _, plan := range a.Plans {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO plans (id, file_path, status, created_at, updated_at) VALUES ($1, $2, $3, $4, $5)`,
+			plan.ID, plan.FilePath, plan.Status, orNow(plan.CreatedAt, now), orNow(plan.UpdatedAt, now)); err != nil {
+			return fmt.Errorf("import plan %s: %w", plan.ID, err)
+		}
+	}
+
+	for _, pc := range a.PlanComments {
+		var lineNumber sql.NullInt64
+		if pc.LineNumber != nil {
+			lineNumber = sql.NullInt64{Int64: int64(*pc.LineNumber), Valid: true}
+		}
+		lineStart, lineEnd, occurrence, quoted, slug, before, after := planCommentAnchorParams(pc.Anchor)
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO plan_comments (id, plan_id, line_number, content, created_at, line_start, line_end,
+			                           quoted_text, occurrence, heading_slug, context_before, context_after,
+			                           updated_at, resolved_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		`, pc.ID, pc.PlanID, lineNumber, pc.Content, orNow(pc.CreatedAt, now), lineStart, lineEnd,
+			quoted, occurrence, slug, before, after, toNullTime(pc.UpdatedAt), toNullTime(pc.ResolvedAt)); err != nil {
+			return fmt.Errorf("import plan comment %s: %w", pc.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// orNow substitutes fallback for a zero timestamp.
+func orNow(t, fallback time.Time) time.Time {
+	if t.IsZero() {
+		return fallback
+	}
+	return t
+}
@@ -0,0 +1,193 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// AddComment adds a comment to an issue and records a corresponding event.
+func (s *Store) AddComment(ctx context.Context, issueID, author, text string) (*types.Comment, error) {
+	comment := &types.Comment{IssueID: issueID, Author: author, Text: text, CreatedAt: time.Now()}
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO comments (issue_id, author, text, created_at) VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`, issueID, author, text, comment.CreatedAt).Scan(&comment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("add comment: %w", err)
+	}
+
+	s.recordEvent(ctx, issueID, types.EventCommented, author, nil, &text)
+
+	return comment, nil
+}
+
+// GetComments returns all comments for an issue.
+func (s *Store) GetComments(ctx context.Context, issueID string) ([]*types.Comment, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, issue_id, author, text, created_at, updated_at
+		FROM comments WHERE issue_id = $1
+		ORDER BY created_at ASC, id ASC
+	`, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("get comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []*types.Comment{}
+	for rows.Next() {
+		var (
+			c         types.Comment
+			updatedAt sql.NullTime
+		)
+		if err := rows.Scan(&c.ID, &c.IssueID, &c.Author, &c.Text, &c.CreatedAt, &updatedAt); err != nil {
+			return nil, fmt.Errorf("scan comment: %w", err)
+		}
+		if updatedAt.Valid {
+			c.UpdatedAt = updatedAt.Time
+		}
+		comments = append(comments, &c)
+	}
+	return comments, rows.Err()
+}
+
+// UpdateComment updates a comment's text.
+func (s *Store) UpdateComment(ctx context.Context, commentID int64, text string) error {
+	var issueID string
+	err := s.db.QueryRowContext(ctx,
+		`UPDATE comments SET text = $1, updated_at = $2 WHERE id = $3 RETURNING issue_id`,
+		text, time.Now(), commentID,
+	).Scan(&issueID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("update comment: %w", err)
+	}
+
+	s.publishCommentChange(ctx, types.StreamCommentUpdated, issueID, commentID)
+	return nil
+}
+
+// DeleteComment deletes a comment.
+func (s *Store) DeleteComment(ctx context.Context, commentID int64) error {
+	var issueID string
+	err := s.db.QueryRowContext(ctx,
+		`DELETE FROM comments WHERE id = $1 RETURNING issue_id`, commentID,
+	).Scan(&issueID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("delete comment: %w", err)
+	}
+
+	s.publishCommentChange(ctx, types.StreamCommentDeleted, issueID, commentID)
+	return nil
+}
+
+// publishCommentChange notifies subscribers that an existing comment changed.
+func (s *Store) publishCommentChange(
+	ctx context.Context, evType types.StreamEventType, issueID string, commentID int64,
+) {
+	if s.publisher == nil {
+		return
+	}
+	s.publish(types.StreamEvent{
+		Type:      evType,
+		ProjectID: s.issueProjectID(ctx, issueID),
+		IssueID:   issueID,
+		Data:      map[string]any{"comment_id": commentID},
+	})
+}
+
+// GetEvents returns the event history for an issue, newest first.
+// Defaults to a limit of 50 events if limit is zero or negative.
+func (s *Store) GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	events, err := s.queryEvents(ctx, `
+		SELECT id, issue_id, event_type, actor, old_value, new_value, comment, created_at
+		FROM events WHERE issue_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`, issueID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get events: %w", err)
+	}
+	return events, nil
+}
+
+// GetRecentEvents returns the latest events on a project's issues, newest
+// first. Defaults to a limit of 50 events if limit is zero or negative.
+func (s *Store) GetRecentEvents(ctx context.Context, projectID string, limit int) ([]*types.Event, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	events, err := s.queryEvents(ctx, `
+		SELECT e.id, e.issue_id, e.event_type, e.actor, e.old_value, e.new_value, e.comment, e.created_at
+		FROM events e JOIN issues i ON i.id = e.issue_id
+		WHERE i.project_id = $1
+		ORDER BY e.created_at DESC, e.id DESC
+		LIMIT $2
+	`, projectID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get recent events: %w", err)
+	}
+	return events, nil
+}
+
+// queryEvents runs a query selecting every events column and scans the rows.
+func (s *Store) queryEvents(ctx context.Context, query string, args ...any) ([]*types.Event, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []*types.Event{}
+	for rows.Next() {
+		var (
+			ev                          types.Event
+			eventType                   string
+			oldValue, newValue, comment sql.NullString
+		)
+		err := rows.Scan(&ev.ID, &ev.IssueID, &eventType, &ev.Actor,
+			&oldValue, &newValue, &comment, &ev.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("scan event: %w", err)
+		}
+		ev.EventType = types.EventType(eventType)
+		ev.OldValue = nullStringToPtr(oldValue)
+		ev.NewValue = nullStringToPtr(newValue)
+		ev.Comment = nullStringToPtr(comment)
+		events = append(events, &ev)
+	}
+	return events, rows.Err()
+}
+
+// AddEvent records an event on an existing issue in the audit trail and
+// publishes it like any other change.
+func (s *Store) AddEvent(ctx context.Context, event *types.Event) error {
+	if _, err := s.GetIssue(ctx, event.IssueID); err != nil {
+		return err
+	}
+	s.recordEvent(ctx, event.IssueID, event.EventType, event.Actor, event.OldValue, event.NewValue)
+	return nil
+}
+
+// nullStringToPtr converts a sql.NullString to a *string pointer.
+// Returns nil if the NullString is not valid.
+func nullStringToPtr(ns sql.NullString) *string {
+	if ns.Valid {
+		return &ns.String
+	}
+	return nil
+}
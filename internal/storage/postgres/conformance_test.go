@@ -0,0 +1,16 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/storage"
+	"github.com/sentiolabs/arc/internal/storage/storagetest"
+)
+
+func TestConformance(t *testing.T) {
+	storagetest.RunConformance(t, func(t *testing.T) storage.Storage {
+		store, cleanup := setupTestStore(t)
+		t.Cleanup(cleanup)
+		return store
+	})
+}
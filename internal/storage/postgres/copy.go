@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// copyTables lists the tables copied by CopyFrom, parents before children
+// so foreign keys hold at every insert.
+var copyTables = []string{
+	"projects",
+	"config",
+	"workspaces",
+	"issues",
+	"dependencies",
+	"labels",
+	"issue_labels",
+	"issue_fields",
+	"comments",
+	"events",
+	"child_counters",
+	"issue_aliases",
+	"plans",
+	"plan_comments",
+	"ai_sessions",
+	"ai_agents",
+	"webhooks",
+	"webhook_deliveries",
+	"api_tokens",
+	"project_members",
+	"issue_templates",
+}
+
+// identityTables lists tables whose id column is generated, with sequences
+// that must be advanced past the copied IDs.
+var identityTables = []string{"comments", "events", "webhook_deliveries"}
+
+// TableCount is the number of rows copied from one table.
+type TableCount struct {
+	Table string `json:"table"`
+	Rows  int64  `json:"rows"`
+}
+
+// CopyFrom copies every arc table from src, a SQLite database at the
+// current schema version, into the store in a single transaction. IDs and
+// timestamps are kept as-is. The store must not contain any projects.
+func (s *Store) CopyFrom(ctx context.Context, src *sql.DB) ([]TableCount, error) {
+	var existing int
+	if err := s.db.QueryRowContext(ctx, `SELECT count(*) FROM projects`).Scan(&existing); err != nil {
+		return nil, fmt.Errorf("check target: %w", err)
+	}
+	if existing > 0 {
+		return nil, errors.New("target database already contains projects")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	counts := make([]TableCount, 0, len(copyTables))
+	for _, table := range copyTables {
+		n, err := copyTable(ctx, src, tx, table)
+		if err != nil {
+			return nil, fmt.Errorf("copy %s: %w", table, err)
+		}
+		counts = append(counts, TableCount{Table: table, Rows: n})
+	}
+
+	for _, table := range identityTables {
+		if _, err := tx.ExecContext(ctx, `
+			SELECT setval(pg_get_serial_sequence('`+table+`', 'id'), COALESCE(MAX(id), 0) + 1, false)
+			FROM `+table); err != nil {
+			return nil, fmt.Errorf("reset %s id sequence: %w", table, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit copy: %w", err)
+	}
+	return counts, nil
+}
+
+// copyTable copies every row of table from src into tx, column for column.
+func copyTable(ctx context.Context, src *sql.DB, tx *sql.Tx, table string) (int64, error) {
+	rows, err := src.QueryContext(ctx, `SELECT * FROM `+table)
+	if err != nil {
+		return 0, fmt.Errorf("read: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, fmt.Errorf("read columns: %w", err)
+	}
+	placeholders := make([]string, len(columns))
+	for i := range columns {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO `+table+` (`+strings.Join(columns, ", ")+
+		`) VALUES (`+strings.Join(placeholders, ", ")+`)`)
+	if err != nil {
+		return 0, fmt.Errorf("prepare insert: %w", err)
+	}
+	defer stmt.Close()
+
+	var n int64
+	values := make([]any, len(columns))
+	ptrs := make([]any, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return n, fmt.Errorf("scan row: %w", err)
+		}
+		for i, col := range columns {
+			values[i] = convertValue(table, col, values[i])
+		}
+		if _, err := stmt.ExecContext(ctx, values...); err != nil {
+			return n, fmt.Errorf("insert row %d: %w", n+1, err)
+		}
+		n++
+	}
+	return n, rows.Err()
+}
+
+// convertValue adapts a SQLite value to its PostgreSQL column type. SQLite
+// stores booleans as integers and may hand back text columns as bytes.
+func convertValue(table, column string, v any) any {
+	switch v := v.(type) {
+	case int64:
+		if table == "webhooks" && column == "active" {
+			return v != 0
+		}
+	case []byte:
+		return string(v)
+	}
+	return v
+}
@@ -0,0 +1,82 @@
+package postgres_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/storage/sqlite"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func TestCopyFromSQLite(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	src, err := sqlite.New(filepath.Join(t.TempDir(), "src.db"))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer src.Close()
+
+	proj := &types.Project{Name: "Source", Prefix: "src"}
+	if err := src.CreateProject(ctx, proj); err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	parent := &types.Issue{ProjectID: proj.ID, Title: "Parent", Priority: 1, IssueType: types.TypeEpic}
+	if err := src.CreateIssue(ctx, parent, "test-actor"); err != nil {
+		t.Fatalf("create parent: %v", err)
+	}
+	child := &types.Issue{ProjectID: proj.ID, Title: "Child", Priority: 2, IssueType: types.TypeTask, ParentID: parent.ID}
+	if err := src.CreateIssue(ctx, child, "test-actor"); err != nil {
+		t.Fatalf("create child: %v", err)
+	}
+	if _, err := src.AddComment(ctx, child.ID, "test-actor", "copied"); err != nil {
+		t.Fatalf("add comment: %v", err)
+	}
+	wh := &types.Webhook{ProjectID: proj.ID, URL: "https://example.com/hook", Active: true}
+	if err := src.CreateWebhook(ctx, wh); err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+
+	counts, err := store.CopyFrom(ctx, src.DB())
+	if err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	rows := make(map[string]int64, len(counts))
+	for _, c := range counts {
+		rows[c.Table] = c.Rows
+	}
+	if rows["projects"] != 1 || rows["issues"] != 2 || rows["comments"] != 1 || rows["webhooks"] != 1 {
+		t.Errorf("copied rows = %v", rows)
+	}
+
+	got, err := store.GetIssue(ctx, child.ID)
+	if err != nil {
+		t.Fatalf("get copied issue: %v", err)
+	}
+	if got.Title != "Child" || !got.CreatedAt.Equal(child.CreatedAt) {
+		t.Errorf("copied issue = %+v, want title Child created %v", got, child.CreatedAt)
+	}
+	copiedHook, err := store.GetWebhook(ctx, wh.ID)
+	if err != nil || !copiedHook.Active || copiedHook.Secret != wh.Secret {
+		t.Errorf("copied webhook = %+v, %v; want active with the same secret", copiedHook, err)
+	}
+
+	// Sequences and child counters continue past the copied rows.
+	if _, err := store.AddComment(ctx, child.ID, "test-actor", "new"); err != nil {
+		t.Fatalf("add comment after copy: %v", err)
+	}
+	next, err := store.GetNextChildID(ctx, parent.ID)
+	if err != nil {
+		t.Fatalf("next child id: %v", err)
+	}
+	if next != parent.ID+".2" {
+		t.Errorf("next child ID = %s, want %s.2", next, parent.ID)
+	}
+
+	if _, err := store.CopyFrom(ctx, src.DB()); err == nil {
+		t.Error("second copy into a non-empty database succeeded, want error")
+	}
+}
@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/depgraph"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// dependencyLock names the advisory lock held while a blocking dependency is
+// checked for cycles and inserted.
+const dependencyLock = "arc.dependencies"
+
+// AddDependency adds a dependency between two issues.
+// It validates that the issue does not depend on itself, that the dependency type is valid,
+// and that a blocking dependency would not close a cycle.
+func (s *Store) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error {
+	if dep.IssueID == dep.DependsOnID {
+		return errors.New("issue cannot depend on itself")
+	}
+
+	if !dep.Type.IsValid() {
+		return fmt.Errorf("invalid dependency type: %s", dep.Type)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	// Blocking dependencies may cross projects, so every blocking add takes
+	// the same lock: a concurrent add closing the other half of a cycle
+	// waits, then sees this dependency in its walk.
+	if dep.Type.AffectsReadyWork() {
+		if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, dependencyLock); err != nil {
+			return fmt.Errorf("lock dependencies: %w", err)
+		}
+	}
+	getDependencies := func(ctx context.Context, id string) ([]*types.Dependency, error) {
+		return queryDependencies(ctx, tx, `WHERE issue_id = $1`, id)
+	}
+	if err := depgraph.CheckCycle(dep, depgraph.Blockers(ctx, getDependencies)); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	dep.CreatedAt = now
+	dep.CreatedBy = actor
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO dependencies (issue_id, depends_on_id, type, created_at, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (issue_id, depends_on_id) DO UPDATE SET
+			type = excluded.type,
+			created_at = excluded.created_at,
+			created_by = excluded.created_by
+	`, dep.IssueID, dep.DependsOnID, string(dep.Type), now, toNullString(actor))
+	if err != nil {
+		return fmt.Errorf("add dependency: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit dependency: %w", err)
+	}
+
+	newVal := fmt.Sprintf("%s depends on %s (%s)", dep.IssueID, dep.DependsOnID, dep.Type)
+	s.recordEvent(ctx, dep.IssueID, types.EventDependencyAdded, actor, nil, &newVal)
+
+	return nil
+}
+
+// RemoveDependency removes a dependency between two issues. The event
+// records the type of the removed dependency so it can be restored.
+func (s *Store) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error {
+	var depType string
+	err := s.db.QueryRowContext(ctx,
+		`DELETE FROM dependencies WHERE issue_id = $1 AND depends_on_id = $2 RETURNING type`,
+		issueID, dependsOnID).Scan(&depType)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("remove dependency: %w", err)
+	}
+
+	oldVal := fmt.Sprintf("%s no longer depends on %s", issueID, dependsOnID)
+	if depType != "" {
+		oldVal += fmt.Sprintf(" (%s)", depType)
+	}
+	s.recordEvent(ctx, issueID, types.EventDependencyRemoved, actor, &oldVal, nil)
+
+	return nil
+}
+
+// GetDependencies returns the dependencies of an issue.
+func (s *Store) GetDependencies(ctx context.Context, issueID string) ([]*types.Dependency, error) {
+	deps, err := queryDependencies(ctx, s.db, `WHERE issue_id = $1`, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("get dependencies: %w", err)
+	}
+	return deps, nil
+}
+
+// GetDependents returns issues that depend on the given issue.
+func (s *Store) GetDependents(ctx context.Context, issueID string) ([]*types.Dependency, error) {
+	deps, err := queryDependencies(ctx, s.db, `WHERE depends_on_id = $1`, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("get dependents: %w", err)
+	}
+	return deps, nil
+}
+
+// GetOpenChildIssues returns open (non-closed) child issues of a given parent
+// via parent-child dependencies.
+func (s *Store) GetOpenChildIssues(ctx context.Context, parentID string) ([]*types.Issue, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+issueColumns+`
+		FROM issues i
+		JOIN dependencies d ON d.issue_id = i.id
+		WHERE d.depends_on_id = $1
+		  AND d.type = 'parent-child'
+		  AND i.status != 'closed'
+		ORDER BY i.priority ASC, i.created_at ASC
+	`, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("get open child issues: %w", err)
+	}
+	return scanIssues(rows)
+}
+
+func queryDependencies(ctx context.Context, q querier, where string, arg any) ([]*types.Dependency, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT issue_id, depends_on_id, type, created_at, created_by
+		FROM dependencies `+where+`
+		ORDER BY created_at, depends_on_id
+	`, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deps := []*types.Dependency{}
+	for rows.Next() {
+		var (
+			dep       types.Dependency
+			depType   string
+			createdBy sql.NullString
+		)
+		if err := rows.Scan(&dep.IssueID, &dep.DependsOnID, &depType, &dep.CreatedAt, &createdBy); err != nil {
+			return nil, err
+		}
+		dep.Type = types.DependencyType(depType)
+		dep.CreatedBy = fromNullString(createdBy)
+		deps = append(deps, &dep)
+	}
+	return deps, rows.Err()
+}
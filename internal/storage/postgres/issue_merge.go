@@ -0,0 +1,179 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/depgraph"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// MergeIssue folds a duplicate issue into its canonical issue. Comments and
+// labels move across, dependencies are rewired as depgraph.PlanMerge lays
+// out, and the duplicate is closed with a duplicates dependency on the
+// canonical issue. The moves run inside a single transaction along with
+// the label_added events of the labels the canonical issue gains; the
+// merged events on both issues are recorded after it commits.
+func (s *Store) MergeIssue(
+	ctx context.Context, duplicateID, canonicalID, actor string,
+) (*types.IssueMergeResult, error) {
+	if duplicateID == canonicalID {
+		return nil, errors.New("cannot merge an issue into itself")
+	}
+
+	dup, err := s.getIssue(ctx, duplicateID)
+	if err != nil {
+		return nil, err
+	}
+	canonical, err := s.getIssue(ctx, canonicalID)
+	if err != nil {
+		return nil, err
+	}
+	if dup.ProjectID != canonical.ProjectID {
+		return nil, fmt.Errorf("cannot merge %s into %s: issues are in different projects", duplicateID, canonicalID)
+	}
+	plan, err := s.planIssueMerge(ctx, duplicateID, canonicalID)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := s.labelsToMerge(ctx, duplicateID, canonicalID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	res, err := tx.ExecContext(ctx, `UPDATE comments SET issue_id = $1 WHERE issue_id = $2`, canonicalID, duplicateID)
+	if err != nil {
+		return nil, fmt.Errorf("move comments: %w", err)
+	}
+	comments, _ := res.RowsAffected()
+
+	// Each label the canonical issue gains gets its label_added event in the
+	// same transaction, so history and undo see it like any other label.
+	now := time.Now()
+	for _, label := range labels {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO issue_labels (issue_id, label) VALUES ($1, $2)`,
+			canonicalID, label); err != nil {
+			return nil, fmt.Errorf("move labels: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO events (issue_id, event_type, actor, new_value, created_at)
+			VALUES ($1, $2, $3, $4, $5)`,
+			canonicalID, string(types.EventLabelAdded), actor, label, now); err != nil {
+			return nil, fmt.Errorf("record label event: %w", err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM issue_labels WHERE issue_id = $1`, duplicateID); err != nil {
+		return nil, fmt.Errorf("move labels: %w", err)
+	}
+
+	for _, d := range plan.Drop {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM dependencies WHERE issue_id = $1 AND depends_on_id = $2`,
+			d.IssueID, d.DependsOnID); err != nil {
+			return nil, fmt.Errorf("drop dependency: %w", err)
+		}
+	}
+	for _, m := range plan.Move {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE dependencies SET issue_id = $1, depends_on_id = $2
+			WHERE issue_id = $3 AND depends_on_id = $4`,
+			m[1].IssueID, m[1].DependsOnID, m[0].IssueID, m[0].DependsOnID); err != nil {
+			return nil, fmt.Errorf("move dependency: %w", err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO dependencies (issue_id, depends_on_id, type, created_at, created_by)
+		VALUES ($1, $2, $3, $4, $5)`,
+		duplicateID, canonicalID, string(types.DepDuplicates), now, toNullString(actor)); err != nil {
+		return nil, fmt.Errorf("add duplicates dependency: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE issues SET
+			status = 'closed',
+			closed_at = COALESCE(closed_at, $1),
+			close_reason = $2,
+			updated_at = $1,
+			version = version + 1
+		WHERE id = $3`,
+		now, types.CloseReasonDuplicate, duplicateID); err != nil {
+		return nil, fmt.Errorf("close duplicate: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit merge: %w", err)
+	}
+
+	s.recordIssueMerge(ctx, dup, canonicalID, actor)
+
+	result := &types.IssueMergeResult{
+		CommentsMoved:     int(comments),
+		LabelsMoved:       len(labels),
+		DependenciesMoved: len(plan.Move),
+	}
+	if result.Canonical, err = s.getIssue(ctx, canonicalID); err != nil {
+		return nil, fmt.Errorf("fetch canonical issue: %w", err)
+	}
+	if result.Duplicate, err = s.getIssue(ctx, duplicateID); err != nil {
+		return nil, fmt.Errorf("fetch duplicate issue: %w", err)
+	}
+	return result, nil
+}
+
+// planIssueMerge reads the dependencies around both issues and works out
+// how to rewire them.
+func (s *Store) planIssueMerge(ctx context.Context, duplicateID, canonicalID string) (*depgraph.MergePlan, error) {
+	deps, err := s.GetDependencies(ctx, duplicateID)
+	if err != nil {
+		return nil, err
+	}
+	dependents, err := s.GetDependents(ctx, duplicateID)
+	if err != nil {
+		return nil, err
+	}
+	canonicalDeps, err := s.GetDependencies(ctx, canonicalID)
+	if err != nil {
+		return nil, err
+	}
+	canonicalDependents, err := s.GetDependents(ctx, canonicalID)
+	if err != nil {
+		return nil, err
+	}
+	return depgraph.PlanMerge(duplicateID, canonicalID, deps, dependents, canonicalDeps, canonicalDependents,
+		depgraph.Blockers(ctx, s.GetDependencies))
+}
+
+// labelsToMerge returns the duplicate's labels that the canonical issue
+// does not have yet.
+func (s *Store) labelsToMerge(ctx context.Context, duplicateID, canonicalID string) ([]string, error) {
+	labels, err := s.GetIssueLabels(ctx, duplicateID)
+	if err != nil {
+		return nil, err
+	}
+	have, err := s.GetIssueLabels(ctx, canonicalID)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(labels, func(l string) bool { return slices.Contains(have, l) }), nil
+}
+
+// recordIssueMerge records the events of a merge: the duplicate's close,
+// unless it was closed already, and a merged event on each issue.
+func (s *Store) recordIssueMerge(ctx context.Context, dup *types.Issue, canonicalID, actor string) {
+	if dup.Status != types.StatusClosed {
+		oldStatus, reason := string(dup.Status), types.CloseReasonDuplicate
+		s.recordEvent(ctx, dup.ID, types.EventClosed, actor, &oldStatus, &reason)
+	}
+	into := "merged into " + canonicalID
+	s.recordEvent(ctx, dup.ID, types.EventMerged, actor, nil, &into)
+	from := "merged from " + dup.ID
+	s.recordEvent(ctx, canonicalID, types.EventMerged, actor, nil, &from)
+}
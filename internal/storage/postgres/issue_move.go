@@ -0,0 +1,230 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/depgraph"
+	"github.com/sentiolabs/arc/internal/history"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// issueIDColumns lists the columns, as "table.column", that hold issue IDs
+// and are rewritten when an issue moves.
+var issueIDColumns = [][2]string{
+	{"dependencies", "issue_id"},
+	{"dependencies", "depends_on_id"},
+	{"issue_labels", "issue_id"},
+	{"issue_fields", "issue_id"},
+	{"comments", "issue_id"},
+	{"events", "issue_id"},
+	{"child_counters", "parent_id"},
+	{"issue_aliases", "issue_id"},
+	{"issue_plans", "issue_id"},
+}
+
+// MoveIssue moves an issue and its descendants to another project. IDs are
+// reissued under the target prefix as depgraph.PlanMove lays out, every
+// row naming a moved issue follows its new ID, and each old ID becomes an
+// alias. The root's parent-child dependency on an issue left behind is
+// dropped, as are custom field values the target project does not accept.
+// The moves run inside a single transaction; the moved and field_changed
+// events are recorded after it commits.
+func (s *Store) MoveIssue(ctx context.Context, issueID, projectID, actor string) (*types.IssueMoveResult, error) {
+	id, err := s.issueAlias(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+	root, err := s.getIssue(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.GetProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if root.ProjectID == target.ID {
+		return nil, fmt.Errorf("issue %s is already in project %s", root.ID, target.ID)
+	}
+	sourceProject := root.ProjectID
+	plan, err := s.planIssueMove(ctx, root, target.Prefix)
+	if err != nil {
+		return nil, err
+	}
+	fields, rejected, err := s.rejectedFields(ctx, target.ID, plan.Order)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	// Rows are rewritten one table at a time, so references are briefly
+	// out of step with the issues they name; check them at commit.
+	if _, err := tx.ExecContext(ctx, `SET CONSTRAINTS ALL DEFERRED`); err != nil {
+		return nil, fmt.Errorf("defer foreign keys: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM dependencies WHERE issue_id = $1 AND type = $2`,
+		root.ID, string(types.DepParentChild)); err != nil {
+		return nil, fmt.Errorf("detach from parent: %w", err)
+	}
+
+	now := time.Now()
+	dropped := make(map[string][]fieldChange)
+	for _, oldID := range plan.Order {
+		newID := plan.IDs[oldID]
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE issues SET id = $1, project_id = $2, updated_at = $3, version = version + 1
+			WHERE id = $4`,
+			newID, target.ID, now, oldID); err != nil {
+			return nil, fmt.Errorf("move issue %s: %w", oldID, err)
+		}
+		for _, col := range issueIDColumns {
+			if _, err := tx.ExecContext(ctx,
+				`UPDATE `+col[0]+` SET `+col[1]+` = $1 WHERE `+col[1]+` = $2`, newID, oldID); err != nil {
+				return nil, fmt.Errorf("move issue %s: rewrite %s.%s: %w", oldID, col[0], col[1], err)
+			}
+		}
+		// An issue ID outranks an alias of the same name, so drop the alias
+		// when an issue moves back to an ID it once had.
+		if _, err := tx.ExecContext(ctx, `DELETE FROM issue_aliases WHERE alias = $1`, newID); err != nil {
+			return nil, fmt.Errorf("move issue %s: %w", oldID, err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO issue_aliases (alias, issue_id, created_at) VALUES ($1, $2, $3)`,
+			oldID, newID, now); err != nil {
+			return nil, fmt.Errorf("move issue %s: add alias: %w", oldID, err)
+		}
+		if keys := rejected[oldID]; len(keys) > 0 {
+			unset := make(map[string]string, len(keys))
+			for _, key := range keys {
+				unset[key] = ""
+			}
+			if dropped[newID], err = writeIssueFields(ctx, tx, newID, fields[oldID], unset); err != nil {
+				return nil, fmt.Errorf("move issue %s: %w", oldID, err)
+			}
+		}
+	}
+	for parentID, last := range plan.Counters {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO child_counters (parent_id, last_child) VALUES ($1, $2)
+			ON CONFLICT (parent_id) DO UPDATE SET last_child = GREATEST(child_counters.last_child, excluded.last_child)`,
+			parentID, last); err != nil {
+			return nil, fmt.Errorf("update child counter for %s: %w", parentID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit move: %w", err)
+	}
+
+	for _, oldID := range plan.Order {
+		newID := plan.IDs[oldID]
+		s.recordEvent(ctx, newID, types.EventMoved, actor,
+			history.MoveValue(oldID, sourceProject), history.MoveValue(newID, target.ID))
+		s.recordFieldChanges(ctx, newID, dropped[newID], actor)
+	}
+
+	moved, err := s.getIssue(ctx, plan.IDs[root.ID])
+	if err != nil {
+		return nil, fmt.Errorf("fetch moved issue: %w", err)
+	}
+	result := &types.IssueMoveResult{Issue: moved, TargetProject: target, IDMap: plan.IDs}
+	for oldID, keys := range rejected {
+		if result.DroppedFields == nil {
+			result.DroppedFields = make(map[string][]string)
+		}
+		result.DroppedFields[plan.IDs[oldID]] = keys
+	}
+	return result, nil
+}
+
+// rejectedFields returns the custom field values of the issues in ids and,
+// by issue ID, the keys of those the project does not accept.
+func (s *Store) rejectedFields(
+	ctx context.Context, projectID string, ids []string,
+) (map[string]map[string]string, map[string][]string, error) {
+	config, err := s.GetProjectConfig(ctx, projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+	values, err := s.GetFieldsForIssues(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	defs := types.FieldDefs(config)
+	rejected := make(map[string][]string)
+	for id, fields := range values {
+		if keys := types.RejectedFields(defs, fields); len(keys) > 0 {
+			rejected[id] = keys
+		}
+	}
+	return values, rejected, nil
+}
+
+// planIssueMove reads the subtree under root and works out its new IDs
+// under prefix.
+func (s *Store) planIssueMove(ctx context.Context, root *types.Issue, prefix string) (*depgraph.MovePlan, error) {
+	dependents := func(id string) ([]*types.Dependency, error) {
+		return s.GetDependents(ctx, id)
+	}
+	lastChild := func(id string) (int, error) {
+		var last int
+		err := s.db.QueryRowContext(ctx, `SELECT last_child FROM child_counters WHERE parent_id = $1`, id).Scan(&last)
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return last, err
+	}
+	taken := func(id string) (bool, error) {
+		var n int
+		err := s.db.QueryRowContext(ctx, `
+			SELECT (SELECT COUNT(*) FROM issues WHERE id = $1) +
+			       (SELECT COUNT(*) FROM issue_aliases WHERE alias = $1 AND issue_id <> $2)`,
+			id, root.ID).Scan(&n)
+		return n > 0, err
+	}
+	return depgraph.PlanMove(root, prefix, dependents, lastChild, taken)
+}
+
+// ResolveIssueAlias returns the current ID of the issue once known as id:
+// the issue it was moved to or merged into, or id itself.
+func (s *Store) ResolveIssueAlias(ctx context.Context, id string) (string, error) {
+	id, err := s.issueAlias(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	var current string
+	err = s.db.QueryRowContext(ctx, `
+		SELECT d.depends_on_id FROM dependencies d
+		JOIN issues i ON i.id = d.issue_id
+		WHERE d.issue_id = $1 AND d.type = $2 AND i.status = 'closed'`,
+		id, string(types.DepDuplicates)).Scan(&current)
+	switch {
+	case err == nil:
+		return current, nil
+	case errors.Is(err, sql.ErrNoRows):
+		return id, nil
+	}
+	return "", fmt.Errorf("resolve merged issue: %w", err)
+}
+
+// issueAlias returns the issue an ID was moved to, or id itself when it is
+// not an alias. Unlike ResolveIssueAlias it does not follow merges.
+func (s *Store) issueAlias(ctx context.Context, id string) (string, error) {
+	var current string
+	err := s.db.QueryRowContext(ctx, `SELECT issue_id FROM issue_aliases WHERE alias = $1`, id).Scan(&current)
+	if errors.Is(err, sql.ErrNoRows) {
+		return id, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("resolve issue alias: %w", err)
+	}
+	return current, nil
+}
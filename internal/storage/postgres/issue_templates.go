@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+const issueTemplateColumns = `project_id, name, content, recurrence, next_run_at, last_run_at, created_at, updated_at`
+
+// SaveIssueTemplate creates a template or replaces the one with the same
+// name in the project. LastRunAt is kept when replacing.
+func (s *Store) SaveIssueTemplate(ctx context.Context, t *types.IssueTemplate) error {
+	if t.ProjectID == "" || t.Name == "" {
+		return errors.New("template project_id and name are required")
+	}
+	now := time.Now().UTC()
+	t.UpdatedAt = now
+
+	var lastRunAt sql.NullTime
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO issue_templates (project_id, name, content, recurrence, next_run_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (project_id, name) DO UPDATE SET
+			content = excluded.content,
+			recurrence = excluded.recurrence,
+			next_run_at = excluded.next_run_at,
+			updated_at = excluded.updated_at
+		RETURNING created_at, last_run_at
+	`, t.ProjectID, t.Name, t.Content, t.Recurrence, toNullTime(t.NextRunAt), now, now,
+	).Scan(&t.CreatedAt, &lastRunAt)
+	if err != nil {
+		return fmt.Errorf("save issue template: %w", err)
+	}
+	t.LastRunAt = fromNullTime(lastRunAt)
+	return nil
+}
+
+// GetIssueTemplate retrieves a project's template by name.
+func (s *Store) GetIssueTemplate(ctx context.Context, projectID, name string) (*types.IssueTemplate, error) {
+	row := s.db.QueryRowContext(ctx,
+		`SELECT `+issueTemplateColumns+` FROM issue_templates WHERE project_id = $1 AND name = $2`, projectID, name)
+	t, err := scanIssueTemplate(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("issue template not found: %s", name)
+		}
+		return nil, fmt.Errorf("get issue template: %w", err)
+	}
+	return t, nil
+}
+
+// ListIssueTemplates returns a project's templates ordered by name.
+func (s *Store) ListIssueTemplates(ctx context.Context, projectID string) ([]*types.IssueTemplate, error) {
+	return s.queryIssueTemplates(ctx,
+		`SELECT `+issueTemplateColumns+` FROM issue_templates WHERE project_id = $1 ORDER BY name`, projectID)
+}
+
+// ListDueIssueTemplates returns recurring templates, across all projects,
+// whose next run is at or before now, oldest first.
+func (s *Store) ListDueIssueTemplates(ctx context.Context, now time.Time) ([]*types.IssueTemplate, error) {
+	return s.queryIssueTemplates(ctx, `
+		SELECT `+issueTemplateColumns+` FROM issue_templates
+		WHERE next_run_at IS NOT NULL AND next_run_at <= $1
+		ORDER BY next_run_at, project_id, name
+	`, now)
+}
+
+// RecordIssueTemplateRun records that a template was instantiated at
+// ranAt and schedules its next run (nil for none).
+func (s *Store) RecordIssueTemplateRun(
+	ctx context.Context, projectID, name string, ranAt time.Time, next *time.Time,
+) error {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE issue_templates SET last_run_at = $1, next_run_at = $2
+		WHERE project_id = $3 AND name = $4
+	`, ranAt, toNullTime(next), projectID, name)
+	if err != nil {
+		return fmt.Errorf("record issue template run: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("issue template not found: %s", name)
+	}
+	return nil
+}
+
+// DeleteIssueTemplate removes a project's template.
+func (s *Store) DeleteIssueTemplate(ctx context.Context, projectID, name string) error {
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM issue_templates WHERE project_id = $1 AND name = $2`, projectID, name)
+	if err != nil {
+		return fmt.Errorf("delete issue template: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("issue template not found: %s", name)
+	}
+	return nil
+}
+
+func (s *Store) queryIssueTemplates(ctx context.Context, query string, args ...any) ([]*types.IssueTemplate, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list issue templates: %w", err)
+	}
+	defer rows.Close()
+
+	templates := []*types.IssueTemplate{}
+	for rows.Next() {
+		t, err := scanIssueTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan issue template: %w", err)
+		}
+		templates = append(templates, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate issue templates: %w", err)
+	}
+	return templates, nil
+}
+
+func scanIssueTemplate(row rowScanner) (*types.IssueTemplate, error) {
+	var (
+		t         types.IssueTemplate
+		nextRunAt sql.NullTime
+		lastRunAt sql.NullTime
+	)
+	err := row.Scan(&t.ProjectID, &t.Name, &t.Content, &t.Recurrence, &nextRunAt, &lastRunAt, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	t.NextRunAt = fromNullTime(nextRunAt)
+	t.LastRunAt = fromNullTime(lastRunAt)
+	return &t, nil
+}
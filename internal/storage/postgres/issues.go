@@ -0,0 +1,741 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/history"
+	"github.com/sentiolabs/arc/internal/project"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// issueColumns lists the issue columns read by scanIssue, in order.
+const issueColumns = `i.id, i.project_id, i.title, i.description, i.status, i.priority,
+	i.issue_type, i.ai_session_id, i.external_ref, i.rank,
+	i.created_at, i.updated_at, i.closed_at, i.close_reason, i.version`
+
+// getNextChildNumber atomically increments and returns the next child counter for a parent.
+func (s *Store) getNextChildNumber(ctx context.Context, parentID string) (int, error) {
+	var nextChild int
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO child_counters (parent_id, last_child)
+		VALUES ($1, 1)
+		ON CONFLICT (parent_id) DO UPDATE SET
+			last_child = child_counters.last_child + 1
+		RETURNING last_child
+	`, parentID).Scan(&nextChild)
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate next child number for parent %s: %w", parentID, err)
+	}
+	return nextChild, nil
+}
+
+// GetNextChildID generates the next hierarchical child ID for a given parent.
+// Returns formatted ID as parentID.{counter} (e.g., arc-a3f8e9.1)
+func (s *Store) GetNextChildID(ctx context.Context, parentID string) (string, error) {
+	if _, err := s.GetIssue(ctx, parentID); err != nil {
+		return "", fmt.Errorf("parent issue not found: %s", parentID)
+	}
+
+	nextNum, err := s.getNextChildNumber(ctx, parentID)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s.%d", parentID, nextNum), nil
+}
+
+// CreateIssue creates a new issue.
+// If ParentID is set, generates a hierarchical child ID (e.g., parent.1) and
+// automatically creates a parent-child dependency.
+func (s *Store) CreateIssue(ctx context.Context, issue *types.Issue, actor string) error {
+	issue.SetDefaults()
+
+	if err := issue.Validate(); err != nil {
+		return fmt.Errorf("validate issue: %w", err)
+	}
+
+	// Get project prefix for ID generation
+	proj, err := s.GetProject(ctx, issue.ProjectID)
+	if err != nil {
+		return fmt.Errorf("get project for ID generation: %w", err)
+	}
+
+	var fields map[string]string
+	if len(issue.Fields) > 0 {
+		if fields, err = s.normalizeFields(ctx, issue.ProjectID, issue.Fields, false); err != nil {
+			return err
+		}
+		issue.Fields = fields
+	}
+
+	// Generate ID - use hierarchical ID if parent is specified
+	if issue.ID == "" {
+		if issue.ParentID != "" {
+			childID, err := s.GetNextChildID(ctx, issue.ParentID)
+			if err != nil {
+				return fmt.Errorf("generate child ID: %w", err)
+			}
+			issue.ID = childID
+		} else {
+			issue.ID = project.GenerateIssueID(proj.Prefix, issue.Title)
+		}
+	}
+
+	now := time.Now()
+	issue.CreatedAt = now
+	issue.UpdatedAt = now
+	issue.Version = 1
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO issues (
+			id, project_id, title, description,
+			status, priority, issue_type, ai_session_id, external_ref,
+			created_at, updated_at, closed_at, close_reason
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`, issue.ID, issue.ProjectID, issue.Title, toNullString(issue.Description),
+		string(issue.Status), issue.Priority, string(issue.IssueType),
+		toNullString(issue.AISessionID), toNullString(issue.ExternalRef),
+		now, now, toNullTime(issue.ClosedAt), toNullString(issue.CloseReason))
+	if err != nil {
+		return fmt.Errorf("create issue: %w", err)
+	}
+
+	s.recordEvent(ctx, issue.ID, types.EventCreated, actor, nil, &issue.Title)
+
+	// Auto-create parent-child dependency if this is a child issue
+	if issue.ParentID != "" {
+		dep := &types.Dependency{
+			IssueID:     issue.ID,
+			DependsOnID: issue.ParentID,
+			Type:        types.DepParentChild,
+			CreatedAt:   now,
+			CreatedBy:   actor,
+		}
+		// Best-effort: dependency creation failure shouldn't rollback the issue
+		_ = s.AddDependency(ctx, dep, actor)
+	}
+
+	changes, err := writeIssueFields(ctx, s.db, issue.ID, nil, fields)
+	if err != nil {
+		return err
+	}
+	s.recordFieldChanges(ctx, issue.ID, changes, actor)
+	return nil
+}
+
+// GetIssue retrieves an issue by ID.
+// An ID the issue had before MoveIssue resolves to the issue.
+func (s *Store) GetIssue(ctx context.Context, id string) (*types.Issue, error) {
+	issue, err := s.getIssue(ctx, id)
+	if err == nil {
+		return issue, nil
+	}
+	current, aliasErr := s.issueAlias(ctx, id)
+	if aliasErr != nil || current == id {
+		return nil, err
+	}
+	return s.getIssue(ctx, current)
+}
+
+// getIssue retrieves the issue stored under id, without following aliases.
+func (s *Store) getIssue(ctx context.Context, id string) (*types.Issue, error) {
+	issue, err := scanIssue(s.db.QueryRowContext(ctx,
+		`SELECT `+issueColumns+` FROM issues i WHERE i.id = $1`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("issue not found: %s", id)
+		}
+		return nil, fmt.Errorf("get issue: %w", err)
+	}
+	return issue, nil
+}
+
+// GetIssueByExternalRef retrieves an issue by its external reference.
+func (s *Store) GetIssueByExternalRef(ctx context.Context, externalRef string) (*types.Issue, error) {
+	issue, err := scanIssue(s.db.QueryRowContext(ctx,
+		`SELECT `+issueColumns+` FROM issues i WHERE i.external_ref = $1 LIMIT 1`, externalRef))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("issue not found with external ref: %s", externalRef)
+		}
+		return nil, fmt.Errorf("get issue by external ref: %w", err)
+	}
+	return issue, nil
+}
+
+// ListIssues returns issues matching the filter.
+// All filter fields are composed with AND semantics, and the issue must
+// match at least one of the filter's Or groups, if any.
+func (s *Store) ListIssues(ctx context.Context, filter types.IssueFilter) ([]*types.Issue, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	offset := max(filter.Offset, 0)
+
+	if filter.Query != "" {
+		return s.searchIssues(ctx, filter.ProjectID, filter.Query, limit, offset)
+	}
+
+	// Normalize custom field filters so "3.0" matches a stored "3"
+	if len(filter.Fields) > 0 {
+		fields, err := s.normalizeFields(ctx, filter.ProjectID, filter.Fields, false)
+		if err != nil {
+			return nil, err
+		}
+		filter.Fields = fields
+	}
+	filter.Or = slices.Clone(filter.Or)
+	for i, group := range filter.Or {
+		if len(group.Fields) == 0 {
+			continue
+		}
+		fields, err := s.normalizeFields(ctx, filter.ProjectID, group.Fields, false)
+		if err != nil {
+			return nil, err
+		}
+		filter.Or[i].Fields = fields
+	}
+
+	query, args := buildListIssuesQuery(filter, limit, offset)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list issues: %w", err)
+	}
+	return scanIssues(rows)
+}
+
+// buildListIssuesQuery constructs the dynamic SQL and args for ListIssues.
+// Only placeholder references are interpolated, never user-supplied values.
+func buildListIssuesQuery(filter types.IssueFilter, limit, offset int) (string, []any) {
+	q := &queryArgs{}
+	where := "i.project_id = " + q.arg(filter.ProjectID) + issueConditions(q, filter)
+	if len(filter.Or) > 0 {
+		groups := make([]string, len(filter.Or))
+		for i, group := range filter.Or {
+			groups[i] = "(TRUE" + issueConditions(q, group) + ")"
+		}
+		where += "\n  AND (" + strings.Join(groups, " OR ") + ")"
+	}
+	page := " LIMIT " + q.arg(int64(limit)) + " OFFSET " + q.arg(int64(offset))
+
+	query := `SELECT ` + issueColumns + `
+FROM issues i
+WHERE ` + where + `
+ORDER BY ` + issueOrderBy(filter.Sort) + page
+
+	return query, q.args
+}
+
+// issueConditions returns the WHERE conditions, each prefixed with AND, for
+// the condition fields of filter.
+func issueConditions(q *queryArgs, filter types.IssueFilter) string {
+	var clauses strings.Builder
+	if len(filter.Statuses) > 0 {
+		fmt.Fprintf(&clauses, "\n  AND i.status = ANY(%s)", q.arg(toStrings(filter.Statuses)))
+	}
+	if len(filter.ExcludeStatuses) > 0 {
+		fmt.Fprintf(&clauses, "\n  AND i.status <> ALL(%s)", q.arg(toStrings(filter.ExcludeStatuses)))
+	}
+	if len(filter.IssueTypes) > 0 {
+		fmt.Fprintf(&clauses, "\n  AND i.issue_type = ANY(%s)", q.arg(toStrings(filter.IssueTypes)))
+	}
+	if len(filter.ExcludeIssueTypes) > 0 {
+		fmt.Fprintf(&clauses, "\n  AND i.issue_type <> ALL(%s)", q.arg(toStrings(filter.ExcludeIssueTypes)))
+	}
+	if len(filter.Priorities) > 0 {
+		fmt.Fprintf(&clauses, "\n  AND i.priority = ANY(%s)", q.arg(toInt64s(filter.Priorities)))
+	}
+	if len(filter.ExcludePriorities) > 0 {
+		fmt.Fprintf(&clauses, "\n  AND i.priority <> ALL(%s)", q.arg(toInt64s(filter.ExcludePriorities)))
+	}
+
+	compare := func(expr, op string, v any) {
+		fmt.Fprintf(&clauses, "\n  AND %s %s %s", expr, op, q.arg(v))
+	}
+	if filter.MinPriority != nil {
+		compare("i.priority", ">=", *filter.MinPriority)
+	}
+	if filter.MaxPriority != nil {
+		compare("i.priority", "<=", *filter.MaxPriority)
+	}
+	if filter.CreatedAfter != nil {
+		compare("i.created_at", ">=", *filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		compare("i.created_at", "<", *filter.CreatedBefore)
+	}
+	if filter.UpdatedAfter != nil {
+		compare("i.updated_at", ">=", *filter.UpdatedAfter)
+	}
+	if filter.UpdatedBefore != nil {
+		compare("i.updated_at", "<", *filter.UpdatedBefore)
+	}
+	if filter.AISessionID != nil {
+		compare("i.ai_session_id", "=", *filter.AISessionID)
+	}
+	if filter.ParentID != "" {
+		fmt.Fprintf(&clauses, `
+  AND EXISTS (SELECT 1 FROM dependencies p
+    WHERE p.issue_id = i.id AND p.type = 'parent-child' AND p.depends_on_id = %s)`, q.arg(filter.ParentID))
+	}
+	for _, label := range filter.Labels {
+		fmt.Fprintf(&clauses,
+			"\n  AND EXISTS (SELECT 1 FROM issue_labels l WHERE l.issue_id = i.id AND l.label = %s)", q.arg(label))
+	}
+	for _, label := range filter.ExcludeLabels {
+		fmt.Fprintf(&clauses,
+			"\n  AND NOT EXISTS (SELECT 1 FROM issue_labels l WHERE l.issue_id = i.id AND l.label = %s)", q.arg(label))
+	}
+	fieldKeys := make([]string, 0, len(filter.Fields))
+	for key := range filter.Fields {
+		fieldKeys = append(fieldKeys, key)
+	}
+	slices.Sort(fieldKeys)
+	for _, key := range fieldKeys {
+		fmt.Fprintf(&clauses,
+			"\n  AND EXISTS (SELECT 1 FROM issue_fields f WHERE f.issue_id = i.id AND f.key = %s AND f.value = %s)",
+			q.arg(key), q.arg(filter.Fields[key]))
+	}
+	for _, text := range filter.Text {
+		pattern := q.arg("%" + text + "%")
+		fmt.Fprintf(&clauses, "\n  AND (i.title ILIKE %s OR i.description ILIKE %s)", pattern, pattern)
+	}
+	return clauses.String()
+}
+
+// issueSortColumns maps sort fields to the columns they order by.
+var issueSortColumns = map[types.IssueSortField]string{
+	types.SortByPriority: "i.priority",
+	types.SortByCreated:  "i.created_at",
+	types.SortByUpdated:  "i.updated_at",
+	types.SortByRank:     "CASE WHEN i.rank = 0 THEN 999999 ELSE i.rank END",
+	types.SortByTitle:    "lower(i.title)",
+	types.SortByStatus:   "i.status",
+	types.SortByType:     "i.issue_type",
+	types.SortByID:       "i.id",
+}
+
+// issueOrderBy returns the ORDER BY list for an issue sort, by default
+// priority and then most recently updated. Explicit sorts end with the
+// issue ID so pages are stable.
+func issueOrderBy(sort []types.IssueSort) string {
+	if len(sort) == 0 {
+		return "i.priority ASC, i.updated_at DESC"
+	}
+	keys := make([]string, 0, len(sort)+1)
+	for _, s := range sort {
+		dir := "ASC"
+		if s.Desc {
+			dir = "DESC"
+		}
+		keys = append(keys, issueSortColumns[s.Field]+" "+dir)
+	}
+	return strings.Join(append(keys, "i.id ASC"), ", ")
+}
+
+// toStrings converts a typed string slice to []string.
+func toStrings[T ~string](vals []T) []string {
+	out := make([]string, len(vals))
+	for i, v := range vals {
+		out[i] = string(v)
+	}
+	return out
+}
+
+// toInt64s converts []int to []int64.
+func toInt64s(vals []int) []int64 {
+	out := make([]int64, len(vals))
+	for i, v := range vals {
+		out[i] = int64(v)
+	}
+	return out
+}
+
+// issueUpdateColumns maps UpdateIssue keys to the column each one sets.
+var issueUpdateColumns = map[string]string{
+	"title":         "title",
+	"description":   "description",
+	"status":        "status",
+	"priority":      "priority",
+	"issue_type":    "issue_type",
+	"ai_session_id": "ai_session_id",
+	"external_ref":  "external_ref",
+	"rank":          "rank",
+}
+
+// UpdateIssue applies updates to an issue in a single transaction and
+// increments its version. When ifVersion is non-zero and the issue has
+// moved past it, nothing is written and a *types.VersionConflictError is
+// returned. Each changed field is recorded as its own event, with the
+// values before and after, once the transaction has committed.
+// Custom fields are passed under "fields" as a map[string]string; an empty
+// value removes that field.
+//
+//nolint:gocognit // validation, then one case per kind of column
+func (s *Store) UpdateIssue(
+	ctx context.Context, id string, updates map[string]any, ifVersion int64, actor string,
+) error {
+	now := time.Now()
+
+	// Validate everything up front so a bad value can't fail the transaction
+	// halfway through.
+	for field := range updates {
+		if _, ok := issueUpdateColumns[field]; !ok && field != "fields" {
+			return fmt.Errorf("unknown field: %s", field)
+		}
+	}
+	var fields, oldFields map[string]string
+	if raw, ok := updates["fields"]; ok {
+		var err error
+		if fields, err = s.normalizeFields(ctx, s.issueProjectID(ctx, id), raw.(map[string]string), true); err != nil {
+			return err
+		}
+		current, err := s.GetFieldsForIssues(ctx, []string{id})
+		if err != nil {
+			return err
+		}
+		oldFields = current[id]
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	found, err := bumpIssueVersion(ctx, tx, id, ifVersion, now)
+	if err != nil || !found {
+		return err
+	}
+	old, err := scanIssue(tx.QueryRowContext(ctx, `SELECT `+issueColumns+` FROM issues i WHERE i.id = $1`, id))
+	if err != nil {
+		return fmt.Errorf("get issue: %w", err)
+	}
+
+	var changes []fieldChange
+	for field, value := range updates {
+		switch field {
+		case "fields":
+			changes, err = writeIssueFields(ctx, tx, id, oldFields, fields)
+		case "description", "ai_session_id", "external_ref":
+			err = setIssueColumn(ctx, tx, id, issueUpdateColumns[field], toNullString(value.(string)))
+		case "priority", "rank":
+			err = setIssueColumn(ctx, tx, id, issueUpdateColumns[field], value.(int))
+		default: // title, issue_type, status
+			err = setIssueColumn(ctx, tx, id, issueUpdateColumns[field], value.(string))
+		}
+		if err != nil {
+			return fmt.Errorf("update %s: %w", field, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit update: %w", err)
+	}
+
+	if status, ok := updates["status"].(string); ok && status != string(old.Status) {
+		oldStatus := string(old.Status)
+		s.recordEvent(ctx, id, types.EventStatusChanged, actor, &oldStatus, &status)
+	}
+	for _, c := range history.Changes(old, updates) {
+		oldValue, newValue := c.EventValues()
+		s.recordEvent(ctx, id, types.EventUpdated, actor, oldValue, newValue)
+	}
+	s.recordFieldChanges(ctx, id, changes, actor)
+	return nil
+}
+
+// setIssueColumn sets one column of an issue. column must come from
+// issueUpdateColumns.
+func setIssueColumn(ctx context.Context, q querier, id, column string, value any) error {
+	//nolint:gosec // G202: column comes from the issueUpdateColumns allow-list
+	_, err := q.ExecContext(ctx, `UPDATE issues SET `+column+` = $1 WHERE id = $2`, value, id)
+	return err
+}
+
+// bumpIssueVersion increments an issue's version and updated_at inside tx,
+// first checking the version against ifVersion when that is non-zero.
+// found is false when the issue does not exist.
+func bumpIssueVersion(ctx context.Context, tx *sql.Tx, id string, ifVersion int64, now time.Time) (bool, error) {
+	var current int64
+	err := tx.QueryRowContext(ctx,
+		`SELECT version FROM issues WHERE id = $1 FOR UPDATE`, id).Scan(&current)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("get version: %w", err)
+	}
+	if ifVersion != 0 && ifVersion != current {
+		return false, &types.VersionConflictError{IssueID: id, Expected: ifVersion, Current: current}
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE issues SET version = version + 1, updated_at = $1 WHERE id = $2`, now, id); err != nil {
+		return false, fmt.Errorf("bump version: %w", err)
+	}
+	return true, nil
+}
+
+// CloseIssue closes an issue.
+// When cascade is false, it checks for open child issues and returns an
+// *types.OpenChildrenError if any are found. When cascade is true, it
+// recursively closes all open descendants leaf-first before closing the
+// target issue. Each cascade-closed child gets a reason of
+// "<reason> (cascade closed by <parent-id>)" where parent-id is the
+// original issue being closed.
+func (s *Store) CloseIssue(ctx context.Context, id string, reason string, cascade bool, actor string) error {
+	openChildren, err := s.GetOpenChildIssues(ctx, id)
+	if err != nil {
+		return fmt.Errorf("check open children: %w", err)
+	}
+
+	if len(openChildren) > 0 && !cascade {
+		children := make([]types.Issue, len(openChildren))
+		for i, c := range openChildren {
+			children[i] = *c
+		}
+		return &types.OpenChildrenError{
+			IssueID:  id,
+			Children: children,
+		}
+	}
+
+	if cascade {
+		if err := s.cascadeCloseDescendants(ctx, id, id, reason, actor); err != nil {
+			return err
+		}
+	}
+
+	return s.closeIssueSingle(ctx, id, reason, actor)
+}
+
+// cascadeCloseDescendants recursively closes all open descendants of parentID
+// in leaf-first order. rootID is the original issue being closed (for reason formatting).
+func (s *Store) cascadeCloseDescendants(ctx context.Context, parentID, rootID, reason, actor string) error {
+	openChildren, err := s.GetOpenChildIssues(ctx, parentID)
+	if err != nil {
+		return fmt.Errorf("get open children of %s: %w", parentID, err)
+	}
+
+	for _, child := range openChildren {
+		if err := s.cascadeCloseDescendants(ctx, child.ID, rootID, reason, actor); err != nil {
+			return err
+		}
+
+		cascadeReason := fmt.Sprintf("%s (cascade closed by %s)", reason, rootID)
+		if err := s.closeIssueSingle(ctx, child.ID, cascadeReason, actor); err != nil {
+			return fmt.Errorf("cascade close %s: %w", child.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// closeIssueSingle closes a single issue without any cascade logic. The
+// closed event records the status the issue had before.
+func (s *Store) closeIssueSingle(ctx context.Context, id string, reason string, actor string) error {
+	var oldStatus *string
+	if issue, err := s.getIssue(ctx, id); err == nil {
+		status := string(issue.Status)
+		oldStatus = &status
+	}
+
+	now := time.Now()
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE issues SET status = 'closed', closed_at = $1, close_reason = $2, updated_at = $1,
+			version = version + 1
+		WHERE id = $3
+	`, now, toNullString(reason), id)
+	if err != nil {
+		return fmt.Errorf("close issue: %w", err)
+	}
+
+	s.recordEvent(ctx, id, types.EventClosed, actor, oldStatus, &reason)
+	return nil
+}
+
+// ReopenIssue reopens a closed issue. The reopened event records the close
+// reason it clears.
+func (s *Store) ReopenIssue(ctx context.Context, id string, actor string) error {
+	var oldReason *string
+	if issue, err := s.getIssue(ctx, id); err == nil && issue.CloseReason != "" {
+		oldReason = &issue.CloseReason
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE issues SET status = 'open', closed_at = NULL, close_reason = NULL, updated_at = $1,
+			version = version + 1
+		WHERE id = $2
+	`, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("reopen issue: %w", err)
+	}
+
+	s.recordEvent(ctx, id, types.EventReopened, actor, oldReason, nil)
+	return nil
+}
+
+// DeleteIssue deletes an issue. Its dependencies, labels, comments, fields
+// and events go with it through ON DELETE CASCADE.
+func (s *Store) DeleteIssue(ctx context.Context, id string) error {
+	// Capture the project before the row disappears so the deletion can be scoped
+	projectID := s.issueProjectID(ctx, id)
+
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM issues WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("delete issue: %w", err)
+	}
+
+	s.publish(types.StreamEvent{Type: types.StreamIssueDeleted, ProjectID: projectID, IssueID: id})
+	return nil
+}
+
+// GetIssueDetails retrieves an issue with all its relational data. Like
+// GetIssue, it resolves an ID the issue had before MoveIssue.
+func (s *Store) GetIssueDetails(ctx context.Context, id string) (*types.IssueDetails, error) {
+	issue, err := s.GetIssue(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	id = issue.ID
+
+	labels, err := s.GetIssueLabels(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get labels: %w", err)
+	}
+
+	deps, err := s.GetDependencies(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get dependencies: %w", err)
+	}
+
+	dependents, err := s.GetDependents(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get dependents: %w", err)
+	}
+
+	comments, err := s.GetComments(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get comments: %w", err)
+	}
+
+	fields, err := s.GetFieldsForIssues(ctx, []string{id})
+	if err != nil {
+		return nil, fmt.Errorf("get fields: %w", err)
+	}
+	issue.Fields = fields[id]
+
+	return &types.IssueDetails{
+		Issue:        *issue,
+		Labels:       labels,
+		Dependencies: deps,
+		Dependents:   dependents,
+		Comments:     comments,
+	}, nil
+}
+
+// scanIssue scans a row selected with issueColumns.
+func scanIssue(row rowScanner) (*types.Issue, error) {
+	var (
+		issue                                              types.Issue
+		status, issueType                                  string
+		description, aiSessionID, externalRef, closeReason sql.NullString
+		closedAt                                           sql.NullTime
+	)
+	err := row.Scan(
+		&issue.ID, &issue.ProjectID, &issue.Title, &description,
+		&status, &issue.Priority, &issueType,
+		&aiSessionID, &externalRef, &issue.Rank,
+		&issue.CreatedAt, &issue.UpdatedAt, &closedAt, &closeReason, &issue.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+	issue.Status = types.Status(status)
+	issue.IssueType = types.IssueType(issueType)
+	issue.Description = fromNullString(description)
+	issue.AISessionID = fromNullString(aiSessionID)
+	issue.ExternalRef = fromNullString(externalRef)
+	issue.CloseReason = fromNullString(closeReason)
+	issue.ClosedAt = fromNullTime(closedAt)
+	return &issue, nil
+}
+
+// withExtra wraps row so that columns selected after issueColumns are
+// scanned into extra.
+func withExtra(row rowScanner, extra ...any) rowScanner {
+	return extraScanner{row: row, extra: extra}
+}
+
+type extraScanner struct {
+	row   rowScanner
+	extra []any
+}
+
+func (e extraScanner) Scan(dest ...any) error {
+	return e.row.Scan(append(dest, e.extra...)...)
+}
+
+// scanIssues reads every row selected with issueColumns and closes rows.
+func scanIssues(rows *sql.Rows) ([]*types.Issue, error) {
+	defer rows.Close()
+	var issues []*types.Issue
+	for rows.Next() {
+		issue, err := scanIssue(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan issue: %w", err)
+		}
+		issues = append(issues, issue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate issues: %w", err)
+	}
+	return issues, nil
+}
+
+// recordEvent records an event in the audit trail.
+// Errors are intentionally ignored because event recording is best-effort
+// and should not fail the parent operation.
+//
+//nolint:revive,lll // argument-limit: event recording requires all these parameters
+func (s *Store) recordEvent(ctx context.Context, issueID string, eventType types.EventType, actor string, oldValue, newValue *string) {
+	_, _ = s.db.ExecContext(ctx, `
+		INSERT INTO events (issue_id, event_type, actor, old_value, new_value, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, issueID, string(eventType), actor,
+		toNullString(ptrToString(oldValue)), toNullString(ptrToString(newValue)), time.Now())
+
+	if s.publisher == nil {
+		return
+	}
+	var data map[string]any
+	if oldValue != nil || newValue != nil {
+		data = make(map[string]any, 2) //nolint:mnd // old and new value
+		if oldValue != nil {
+			data["old_value"] = *oldValue
+		}
+		if newValue != nil {
+			data["new_value"] = *newValue
+		}
+	}
+	s.publish(types.StreamEvent{
+		Type:      types.StreamTypeForEvent(eventType),
+		ProjectID: s.issueProjectID(ctx, issueID),
+		IssueID:   issueID,
+		Actor:     actor,
+		Data:      data,
+	})
+}
+
+func ptrToString(p *string) string {
+	if p == nil {
+		return ""
+	}
+	return *p
+}
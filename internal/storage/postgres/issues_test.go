@@ -0,0 +1,105 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/storage/postgres"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// createTestIssue creates issue with a default priority.
+func createTestIssue(t *testing.T, store *postgres.Store, issue *types.Issue) *types.Issue {
+	t.Helper()
+	if issue.Priority == 0 {
+		issue.Priority = 2
+	}
+	if err := store.CreateIssue(context.Background(), issue, "test-actor"); err != nil {
+		t.Fatalf("create issue %q: %v", issue.Title, err)
+	}
+	return issue
+}
+
+func TestChildIssueIDs(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+	proj := setupTestProject(t, store)
+
+	parent := createTestIssue(t, store, &types.Issue{ProjectID: proj.ID, Title: "Epic", IssueType: types.TypeEpic})
+	first := createTestIssue(t, store, &types.Issue{ProjectID: proj.ID, Title: "One", ParentID: parent.ID})
+	second := createTestIssue(t, store, &types.Issue{ProjectID: proj.ID, Title: "Two", ParentID: parent.ID})
+
+	if first.ID != parent.ID+".1" || second.ID != parent.ID+".2" {
+		t.Fatalf("child IDs = %s, %s; want %s.1, %s.2", first.ID, second.ID, parent.ID, parent.ID)
+	}
+
+	children, err := store.ListIssues(ctx, types.IssueFilter{ProjectID: proj.ID, ParentID: parent.ID})
+	if err != nil {
+		t.Fatalf("list children: %v", err)
+	}
+	if len(children) != 2 {
+		t.Errorf("got %d children, want 2", len(children))
+	}
+
+	if err := store.CloseIssue(ctx, parent.ID, "done", true, "test-actor"); err != nil {
+		t.Fatalf("cascade close: %v", err)
+	}
+	open, err := store.GetOpenChildIssues(ctx, parent.ID)
+	if err != nil {
+		t.Fatalf("get open children: %v", err)
+	}
+	if len(open) != 0 {
+		t.Errorf("got %d open children after cascade close, want 0", len(open))
+	}
+}
+
+func TestReadyAndBlockedWork(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+	proj := setupTestProject(t, store)
+
+	blocker := createTestIssue(t, store, &types.Issue{ProjectID: proj.ID, Title: "Blocker", IssueType: types.TypeTask})
+	blocked := createTestIssue(t, store, &types.Issue{ProjectID: proj.ID, Title: "Blocked", IssueType: types.TypeTask})
+	dep := &types.Dependency{IssueID: blocked.ID, DependsOnID: blocker.ID, Type: types.DepBlocks}
+	if err := store.AddDependency(ctx, dep, "test-actor"); err != nil {
+		t.Fatalf("add dependency: %v", err)
+	}
+
+	ready, err := store.GetReadyWork(ctx, types.WorkFilter{ProjectID: proj.ID})
+	if err != nil {
+		t.Fatalf("get ready work: %v", err)
+	}
+	if len(ready) != 1 || ready[0].ID != blocker.ID {
+		t.Errorf("ready = %v, want only %s", ready, blocker.ID)
+	}
+
+	blockedIssues, err := store.GetBlockedIssues(ctx, types.WorkFilter{ProjectID: proj.ID})
+	if err != nil {
+		t.Fatalf("get blocked issues: %v", err)
+	}
+	if len(blockedIssues) != 1 || len(blockedIssues[0].BlockedBy) != 1 ||
+		blockedIssues[0].BlockedBy[0] != blocker.ID {
+		t.Fatalf("blocked = %+v, want %s blocked by %s", blockedIssues, blocked.ID, blocker.ID)
+	}
+
+	stats, err := store.GetStatistics(ctx, proj.ID)
+	if err != nil {
+		t.Fatalf("get statistics: %v", err)
+	}
+	if stats.TotalIssues != 2 || stats.ReadyIssues != 1 {
+		t.Errorf("stats = %+v, want 2 total and 1 ready", stats)
+	}
+
+	if err := store.CloseIssue(ctx, blocker.ID, "done", false, "test-actor"); err != nil {
+		t.Fatalf("close blocker: %v", err)
+	}
+	isBlocked, _, err := store.IsBlocked(ctx, blocked.ID)
+	if err != nil {
+		t.Fatalf("is blocked: %v", err)
+	}
+	if isBlocked {
+		t.Error("issue still blocked after its blocker closed")
+	}
+}
@@ -0,0 +1,161 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// CreateLabel creates a new global label definition.
+func (s *Store) CreateLabel(ctx context.Context, label *types.Label) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO labels (name, color, description) VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO UPDATE SET
+			color = excluded.color,
+			description = excluded.description
+	`, label.Name, toNullString(label.Color), toNullString(label.Description))
+	if err != nil {
+		return fmt.Errorf("create label: %w", err)
+	}
+
+	return nil
+}
+
+// GetLabel retrieves a label by name.
+func (s *Store) GetLabel(ctx context.Context, name string) (*types.Label, error) {
+	label, err := scanLabel(s.db.QueryRowContext(ctx,
+		`SELECT name, color, description FROM labels WHERE name = $1`, name))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("label not found: %s", name)
+		}
+		return nil, fmt.Errorf("get label: %w", err)
+	}
+	return label, nil
+}
+
+// ListLabels returns all global labels.
+func (s *Store) ListLabels(ctx context.Context) ([]*types.Label, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT name, color, description FROM labels ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("list labels: %w", err)
+	}
+	defer rows.Close()
+
+	labels := []*types.Label{}
+	for rows.Next() {
+		label, err := scanLabel(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan label: %w", err)
+		}
+		labels = append(labels, label)
+	}
+	return labels, rows.Err()
+}
+
+// UpdateLabel updates a label.
+func (s *Store) UpdateLabel(ctx context.Context, label *types.Label) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE labels SET color = $1, description = $2 WHERE name = $3`,
+		toNullString(label.Color), toNullString(label.Description), label.Name)
+	if err != nil {
+		return fmt.Errorf("update label: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteLabel deletes a label.
+func (s *Store) DeleteLabel(ctx context.Context, name string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM labels WHERE name = $1`, name); err != nil {
+		return fmt.Errorf("delete label: %w", err)
+	}
+
+	return nil
+}
+
+// AddLabelToIssue adds a label to an issue.
+func (s *Store) AddLabelToIssue(ctx context.Context, issueID, label, actor string) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO issue_labels (issue_id, label) VALUES ($1, $2)
+		ON CONFLICT (issue_id, label) DO NOTHING
+	`, issueID, label)
+	if err != nil {
+		return fmt.Errorf("add label to issue: %w", err)
+	}
+
+	s.recordEvent(ctx, issueID, types.EventLabelAdded, actor, nil, &label)
+	return nil
+}
+
+// RemoveLabelFromIssue removes a label from an issue.
+func (s *Store) RemoveLabelFromIssue(ctx context.Context, issueID, label, actor string) error {
+	_, err := s.db.ExecContext(ctx,
+		`DELETE FROM issue_labels WHERE issue_id = $1 AND label = $2`, issueID, label)
+	if err != nil {
+		return fmt.Errorf("remove label from issue: %w", err)
+	}
+
+	s.recordEvent(ctx, issueID, types.EventLabelRemoved, actor, &label, nil)
+	return nil
+}
+
+// GetIssueLabels returns all labels for an issue.
+func (s *Store) GetIssueLabels(ctx context.Context, issueID string) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT label FROM issue_labels WHERE issue_id = $1 ORDER BY label`, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("get issue labels: %w", err)
+	}
+	labels, err := scanStrings(rows)
+	if err != nil {
+		return nil, fmt.Errorf("get issue labels: %w", err)
+	}
+	if labels == nil {
+		labels = []string{}
+	}
+	return labels, nil
+}
+
+// GetLabelsForIssues fetches labels for multiple issues in a single query.
+// Returns a map of issue_id -> []labels.
+func (s *Store) GetLabelsForIssues(ctx context.Context, issueIDs []string) (map[string][]string, error) {
+	result := make(map[string][]string)
+	if len(issueIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT issue_id, label FROM issue_labels
+		WHERE issue_id = ANY($1)
+		ORDER BY issue_id, label
+	`, issueIDs)
+	if err != nil {
+		return nil, fmt.Errorf("batch get labels: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var issueID, label string
+		if err := rows.Scan(&issueID, &label); err != nil {
+			return nil, err
+		}
+		result[issueID] = append(result[issueID], label)
+	}
+	return result, rows.Err()
+}
+
+func scanLabel(row rowScanner) (*types.Label, error) {
+	var (
+		label              types.Label
+		color, description sql.NullString
+	)
+	if err := row.Scan(&label.Name, &color, &description); err != nil {
+		return nil, err
+	}
+	label.Color = fromNullString(color)
+	label.Description = fromNullString(description)
+	return &label, nil
+}
@@ -0,0 +1,258 @@
+-- +goose Up
+-- Arc schema for PostgreSQL. Mirrors the SQLite schema as of its migration
+-- 024; later changes get their own numbered migration in both backends.
+
+CREATE TABLE projects (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    description TEXT,
+    prefix TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE config (
+    project_id TEXT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    key TEXT NOT NULL,
+    value TEXT,
+    PRIMARY KEY (project_id, key)
+);
+
+CREATE TABLE workspaces (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    path TEXT NOT NULL,
+    label TEXT,
+    hostname TEXT,
+    git_remote TEXT,
+    path_type TEXT NOT NULL DEFAULT 'canonical',
+    last_accessed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (project_id, path)
+);
+CREATE INDEX idx_workspaces_project_id ON workspaces(project_id);
+CREATE INDEX idx_workspaces_path ON workspaces(path);
+
+-- search backs full-text search: title terms outrank description terms.
+-- The 'simple' configuration does no stemming, matching SQLite's FTS5.
+CREATE TABLE issues (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    description TEXT,
+    status TEXT NOT NULL DEFAULT 'open',
+    priority INTEGER NOT NULL DEFAULT 2,
+    issue_type TEXT NOT NULL DEFAULT 'task',
+    ai_session_id TEXT,
+    external_ref TEXT,
+    rank INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    closed_at TIMESTAMPTZ,
+    close_reason TEXT,
+    search TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', title), 'A') ||
+        setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+    ) STORED
+);
+CREATE INDEX idx_issues_project ON issues(project_id);
+CREATE INDEX idx_issues_status ON issues(project_id, status);
+CREATE INDEX idx_issues_priority ON issues(project_id, priority);
+CREATE INDEX idx_issues_type ON issues(project_id, issue_type);
+CREATE INDEX idx_issues_updated ON issues(project_id, updated_at DESC);
+CREATE INDEX idx_issues_external_ref ON issues(external_ref) WHERE external_ref IS NOT NULL;
+CREATE INDEX idx_issues_search ON issues USING GIN (search);
+
+CREATE TABLE dependencies (
+    issue_id TEXT NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    depends_on_id TEXT NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    type TEXT NOT NULL DEFAULT 'blocks',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_by TEXT,
+    PRIMARY KEY (issue_id, depends_on_id)
+);
+CREATE INDEX idx_dependencies_depends_on ON dependencies(depends_on_id);
+
+CREATE TABLE labels (
+    name TEXT PRIMARY KEY,
+    color TEXT,
+    description TEXT
+);
+
+CREATE TABLE issue_labels (
+    issue_id TEXT NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    label TEXT NOT NULL,
+    PRIMARY KEY (issue_id, label)
+);
+CREATE INDEX idx_issue_labels_label ON issue_labels(label);
+
+CREATE TABLE issue_fields (
+    issue_id TEXT NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    PRIMARY KEY (issue_id, key)
+);
+CREATE INDEX idx_issue_fields_key_value ON issue_fields(key, value);
+
+CREATE TABLE comments (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    issue_id TEXT NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    author TEXT NOT NULL,
+    text TEXT NOT NULL,
+    comment_type TEXT NOT NULL DEFAULT 'comment',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ
+);
+CREATE INDEX idx_comments_type ON comments(issue_id, comment_type);
+
+CREATE TABLE events (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    issue_id TEXT NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    event_type TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    old_value TEXT,
+    new_value TEXT,
+    comment TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_events_issue ON events(issue_id, created_at DESC);
+
+CREATE TABLE child_counters (
+    parent_id TEXT PRIMARY KEY REFERENCES issues(id) ON DELETE CASCADE,
+    last_child INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE plans (
+    id TEXT PRIMARY KEY,
+    file_path TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'draft',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE plan_comments (
+    id TEXT PRIMARY KEY,
+    plan_id TEXT NOT NULL REFERENCES plans(id) ON DELETE CASCADE,
+    line_number INTEGER,
+    content TEXT NOT NULL,
+    line_start INTEGER,
+    line_end INTEGER,
+    quoted_text TEXT,
+    occurrence INTEGER,
+    heading_slug TEXT,
+    context_before TEXT,
+    context_after TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ,
+    resolved_at TIMESTAMPTZ
+);
+CREATE INDEX idx_plan_comments_plan ON plan_comments(plan_id);
+
+CREATE TABLE ai_sessions (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    transcript_path TEXT NOT NULL DEFAULT '',
+    cwd TEXT DEFAULT '',
+    started_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_ai_sessions_project_id ON ai_sessions(project_id, started_at DESC);
+
+CREATE TABLE ai_agents (
+    id TEXT PRIMARY KEY,
+    session_id TEXT NOT NULL REFERENCES ai_sessions(id) ON DELETE CASCADE,
+    description TEXT,
+    prompt TEXT,
+    agent_type TEXT,
+    model TEXT,
+    status TEXT NOT NULL DEFAULT 'running',
+    duration_ms BIGINT,
+    total_tokens BIGINT,
+    tool_use_count BIGINT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_ai_agents_session ON ai_agents(session_id);
+
+CREATE TABLE webhooks (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL DEFAULT '',
+    event_types TEXT NOT NULL DEFAULT '',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_webhooks_project ON webhooks(project_id);
+
+CREATE TABLE webhook_deliveries (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    webhook_id TEXT NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event_type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_attempt_at TIMESTAMPTZ,
+    response_status INTEGER,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    delivered_at TIMESTAMPTZ
+);
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(status, next_attempt_at);
+CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, id DESC);
+
+CREATE TABLE api_tokens (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    prefix TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ
+);
+CREATE INDEX idx_api_tokens_actor ON api_tokens(actor);
+
+CREATE TABLE project_members (
+    project_id TEXT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    actor TEXT NOT NULL,
+    role TEXT NOT NULL CHECK (role IN ('viewer', 'contributor', 'maintainer', 'admin')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (project_id, actor)
+);
+CREATE INDEX idx_project_members_actor ON project_members(actor);
+
+CREATE TABLE issue_templates (
+    project_id TEXT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    content TEXT NOT NULL,
+    recurrence TEXT NOT NULL DEFAULT '',
+    next_run_at TIMESTAMPTZ,
+    last_run_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (project_id, name)
+);
+CREATE INDEX idx_issue_templates_due ON issue_templates(next_run_at) WHERE next_run_at IS NOT NULL;
+
+-- +goose Down
+DROP TABLE issue_templates;
+DROP TABLE project_members;
+DROP TABLE api_tokens;
+DROP TABLE webhook_deliveries;
+DROP TABLE webhooks;
+DROP TABLE ai_agents;
+DROP TABLE ai_sessions;
+DROP TABLE plan_comments;
+DROP TABLE plans;
+DROP TABLE child_counters;
+DROP TABLE events;
+DROP TABLE comments;
+DROP TABLE issue_fields;
+DROP TABLE issue_labels;
+DROP TABLE labels;
+DROP TABLE dependencies;
+DROP TABLE issues;
+DROP TABLE workspaces;
+DROP TABLE config;
+DROP TABLE projects;
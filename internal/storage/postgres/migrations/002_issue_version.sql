@@ -0,0 +1,7 @@
+-- +goose Up
+-- Optimistic concurrency: every write to an issue increments its version,
+-- and conditional updates compare against it.
+ALTER TABLE issues ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+-- +goose Down
+ALTER TABLE issues DROP COLUMN version;
@@ -0,0 +1,67 @@
+-- +goose Up
+-- Extend full-text search to comments and labels. Their text is copied onto
+-- the issue by triggers so the generated search vector can cover it:
+-- labels rank below the description, comments below labels.
+ALTER TABLE issues ADD COLUMN search_comments TEXT NOT NULL DEFAULT '';
+ALTER TABLE issues ADD COLUMN search_labels TEXT NOT NULL DEFAULT '';
+
+DROP INDEX idx_issues_search;
+ALTER TABLE issues DROP COLUMN search;
+ALTER TABLE issues ADD COLUMN search TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', title), 'A') ||
+    setweight(to_tsvector('simple', coalesce(description, '')), 'B') ||
+    setweight(to_tsvector('simple', search_labels), 'C') ||
+    setweight(to_tsvector('simple', search_comments), 'D')
+) STORED;
+CREATE INDEX idx_issues_search ON issues USING GIN (search);
+
+-- +goose StatementBegin
+CREATE FUNCTION refresh_issue_search(p_issue_id TEXT) RETURNS VOID AS $$
+BEGIN
+    UPDATE issues SET
+        search_comments = coalesce((SELECT string_agg(text, E'\n' ORDER BY id) FROM comments WHERE issue_id = p_issue_id), ''),
+        search_labels = coalesce((SELECT string_agg(label, ' ' ORDER BY label) FROM issue_labels WHERE issue_id = p_issue_id), '')
+    WHERE id = p_issue_id;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION issue_search_source_changed() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM refresh_issue_search(OLD.issue_id);
+    END IF;
+    IF TG_OP = 'INSERT' THEN
+        PERFORM refresh_issue_search(NEW.issue_id);
+    ELSIF TG_OP = 'UPDATE' AND NEW.issue_id <> OLD.issue_id THEN
+        PERFORM refresh_issue_search(NEW.issue_id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER comments_search AFTER INSERT OR UPDATE OR DELETE ON comments
+    FOR EACH ROW EXECUTE FUNCTION issue_search_source_changed();
+CREATE TRIGGER issue_labels_search AFTER INSERT OR UPDATE OR DELETE ON issue_labels
+    FOR EACH ROW EXECUTE FUNCTION issue_search_source_changed();
+
+UPDATE issues i SET
+    search_comments = coalesce((SELECT string_agg(c.text, E'\n' ORDER BY c.id) FROM comments c WHERE c.issue_id = i.id), ''),
+    search_labels = coalesce((SELECT string_agg(l.label, ' ' ORDER BY l.label) FROM issue_labels l WHERE l.issue_id = i.id), '');
+
+-- +goose Down
+DROP TRIGGER issue_labels_search ON issue_labels;
+DROP TRIGGER comments_search ON comments;
+DROP FUNCTION issue_search_source_changed();
+DROP FUNCTION refresh_issue_search(TEXT);
+DROP INDEX idx_issues_search;
+ALTER TABLE issues DROP COLUMN search;
+ALTER TABLE issues DROP COLUMN search_labels;
+ALTER TABLE issues DROP COLUMN search_comments;
+ALTER TABLE issues ADD COLUMN search TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', title), 'A') ||
+    setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+) STORED;
+CREATE INDEX idx_issues_search ON issues USING GIN (search);
@@ -0,0 +1,30 @@
+-- +goose Up
+-- IDs issues had before they were moved to another project. An alias keeps
+-- resolving to the issue wherever the old ID was pasted.
+--
+-- Moving an issue rewrites its ID in every table that references it, so
+-- those references become deferrable: the move defers them to commit.
+ALTER TABLE dependencies ALTER CONSTRAINT dependencies_issue_id_fkey DEFERRABLE;
+ALTER TABLE dependencies ALTER CONSTRAINT dependencies_depends_on_id_fkey DEFERRABLE;
+ALTER TABLE issue_labels ALTER CONSTRAINT issue_labels_issue_id_fkey DEFERRABLE;
+ALTER TABLE issue_fields ALTER CONSTRAINT issue_fields_issue_id_fkey DEFERRABLE;
+ALTER TABLE comments ALTER CONSTRAINT comments_issue_id_fkey DEFERRABLE;
+ALTER TABLE events ALTER CONSTRAINT events_issue_id_fkey DEFERRABLE;
+ALTER TABLE child_counters ALTER CONSTRAINT child_counters_parent_id_fkey DEFERRABLE;
+
+CREATE TABLE issue_aliases (
+    alias TEXT PRIMARY KEY,
+    issue_id TEXT NOT NULL REFERENCES issues(id) ON DELETE CASCADE DEFERRABLE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_issue_aliases_issue ON issue_aliases(issue_id);
+
+-- +goose Down
+DROP TABLE issue_aliases;
+ALTER TABLE child_counters ALTER CONSTRAINT child_counters_parent_id_fkey NOT DEFERRABLE;
+ALTER TABLE events ALTER CONSTRAINT events_issue_id_fkey NOT DEFERRABLE;
+ALTER TABLE comments ALTER CONSTRAINT comments_issue_id_fkey NOT DEFERRABLE;
+ALTER TABLE issue_fields ALTER CONSTRAINT issue_fields_issue_id_fkey NOT DEFERRABLE;
+ALTER TABLE issue_labels ALTER CONSTRAINT issue_labels_issue_id_fkey NOT DEFERRABLE;
+ALTER TABLE dependencies ALTER CONSTRAINT dependencies_depends_on_id_fkey NOT DEFERRABLE;
+ALTER TABLE dependencies ALTER CONSTRAINT dependencies_issue_id_fkey NOT DEFERRABLE;
@@ -0,0 +1,85 @@
+-- +goose Up
+-- Link issues to plans and extend full-text search to the text of linked
+-- plans. Plan content lives on the filesystem, so plans.search_text keeps a
+-- copy of it for the index. It is copied onto each linked issue like
+-- comments and labels are, and ranks with comments.
+ALTER TABLE plans ADD COLUMN search_text TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE issue_plans (
+    issue_id TEXT NOT NULL REFERENCES issues(id) ON DELETE CASCADE DEFERRABLE,
+    plan_id TEXT NOT NULL REFERENCES plans(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (issue_id, plan_id)
+);
+CREATE INDEX idx_issue_plans_plan ON issue_plans(plan_id);
+
+ALTER TABLE issues ADD COLUMN search_plans TEXT NOT NULL DEFAULT '';
+
+DROP INDEX idx_issues_search;
+ALTER TABLE issues DROP COLUMN search;
+ALTER TABLE issues ADD COLUMN search TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', title), 'A') ||
+    setweight(to_tsvector('simple', coalesce(description, '')), 'B') ||
+    setweight(to_tsvector('simple', search_labels), 'C') ||
+    setweight(to_tsvector('simple', search_comments), 'D') ||
+    setweight(to_tsvector('simple', search_plans), 'D')
+) STORED;
+CREATE INDEX idx_issues_search ON issues USING GIN (search);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION refresh_issue_search(p_issue_id TEXT) RETURNS VOID AS $$
+BEGIN
+    UPDATE issues SET
+        search_comments = coalesce((SELECT string_agg(text, E'\n' ORDER BY id) FROM comments WHERE issue_id = p_issue_id), ''),
+        search_labels = coalesce((SELECT string_agg(label, ' ' ORDER BY label) FROM issue_labels WHERE issue_id = p_issue_id), ''),
+        search_plans = coalesce((SELECT string_agg(p.search_text, E'\n' ORDER BY ip.created_at, p.id)
+                                 FROM issue_plans ip JOIN plans p ON p.id = ip.plan_id
+                                 WHERE ip.issue_id = p_issue_id), '')
+    WHERE id = p_issue_id;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION plan_search_changed() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM refresh_issue_search(issue_id) FROM issue_plans WHERE plan_id = NEW.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER issue_plans_search AFTER INSERT OR UPDATE OR DELETE ON issue_plans
+    FOR EACH ROW EXECUTE FUNCTION issue_search_source_changed();
+CREATE TRIGGER plans_search AFTER UPDATE OF search_text ON plans
+    FOR EACH ROW EXECUTE FUNCTION plan_search_changed();
+
+-- +goose Down
+DROP TRIGGER plans_search ON plans;
+DROP TRIGGER issue_plans_search ON issue_plans;
+DROP FUNCTION plan_search_changed();
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION refresh_issue_search(p_issue_id TEXT) RETURNS VOID AS $$
+BEGIN
+    UPDATE issues SET
+        search_comments = coalesce((SELECT string_agg(text, E'\n' ORDER BY id) FROM comments WHERE issue_id = p_issue_id), ''),
+        search_labels = coalesce((SELECT string_agg(label, ' ' ORDER BY label) FROM issue_labels WHERE issue_id = p_issue_id), '')
+    WHERE id = p_issue_id;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+DROP INDEX idx_issues_search;
+ALTER TABLE issues DROP COLUMN search;
+ALTER TABLE issues DROP COLUMN search_plans;
+ALTER TABLE issues ADD COLUMN search TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', title), 'A') ||
+    setweight(to_tsvector('simple', coalesce(description, '')), 'B') ||
+    setweight(to_tsvector('simple', search_labels), 'C') ||
+    setweight(to_tsvector('simple', search_comments), 'D')
+) STORED;
+CREATE INDEX idx_issues_search ON issues USING GIN (search);
+
+DROP TABLE issue_plans;
+ALTER TABLE plans DROP COLUMN search_text;
@@ -0,0 +1,302 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+const planCommentColumns = `id, plan_id, line_number, content, created_at,
+	line_start, line_end, quoted_text, occurrence,
+	heading_slug, context_before, context_after,
+	updated_at, resolved_at`
+
+// CreatePlan persists a new plan. The caller must set plan.ID before calling.
+func (s *Store) CreatePlan(ctx context.Context, plan *types.Plan) error {
+	now := time.Now()
+	plan.CreatedAt = now
+	plan.UpdatedAt = now
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO plans (id, file_path, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, plan.ID, plan.FilePath, plan.Status, plan.CreatedAt, plan.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("create plan: %w", err)
+	}
+
+	s.publish(types.StreamEvent{
+		Type:   types.StreamPlanCreated,
+		PlanID: plan.ID,
+		Data:   map[string]any{"status": plan.Status},
+	})
+	return nil
+}
+
+// GetPlan retrieves a plan by ID. Returns an error if not found.
+func (s *Store) GetPlan(ctx context.Context, id string) (*types.Plan, error) {
+	var plan types.Plan
+	err := s.db.QueryRowContext(ctx,
+		`SELECT id, file_path, status, created_at, updated_at FROM plans WHERE id = $1`, id,
+	).Scan(&plan.ID, &plan.FilePath, &plan.Status, &plan.CreatedAt, &plan.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("plan not found: %s", id)
+		}
+		return nil, fmt.Errorf("get plan: %w", err)
+	}
+	return &plan, nil
+}
+
+// UpdatePlanStatus changes a plan's status.
+func (s *Store) UpdatePlanStatus(ctx context.Context, id string, status string) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE plans SET status = $1, updated_at = $2 WHERE id = $3`, status, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("update plan status: %w", err)
+	}
+
+	s.publish(types.StreamEvent{
+		Type:   types.StreamPlanStatusChanged,
+		PlanID: id,
+		Data:   map[string]any{"status": status},
+	})
+	return nil
+}
+
+// DeletePlan deletes a plan; its comments and issue links go with it
+// through ON DELETE CASCADE.
+func (s *Store) DeletePlan(ctx context.Context, id string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM plans WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("delete plan: %w", err)
+	}
+
+	s.publish(types.StreamEvent{Type: types.StreamPlanDeleted, PlanID: id})
+	return nil
+}
+
+// SetPlanText stores the copy of a plan's file content that search indexes
+// for the issues linked to it. A trigger reindexes those issues.
+func (s *Store) SetPlanText(ctx context.Context, id, text string) error {
+	if _, err := s.db.ExecContext(ctx, `UPDATE plans SET search_text = $1 WHERE id = $2`, text, id); err != nil {
+		return fmt.Errorf("set plan text: %w", err)
+	}
+	return nil
+}
+
+// LinkIssuePlan links a plan to an issue. Linking a pair twice is a no-op.
+func (s *Store) LinkIssuePlan(ctx context.Context, issueID, planID string) error {
+	issue, err := s.GetIssue(ctx, issueID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.GetPlan(ctx, planID); err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO issue_plans (issue_id, plan_id, created_at) VALUES ($1, $2, $3)
+		ON CONFLICT (issue_id, plan_id) DO NOTHING
+	`, issue.ID, planID, time.Now())
+	if err != nil {
+		return fmt.Errorf("link plan: %w", err)
+	}
+	return nil
+}
+
+// UnlinkIssuePlan removes the link between an issue and a plan, if any.
+func (s *Store) UnlinkIssuePlan(ctx context.Context, issueID, planID string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM issue_plans WHERE issue_id = $1 AND plan_id = $2`, issueID, planID)
+	if err != nil {
+		return fmt.Errorf("unlink plan: %w", err)
+	}
+	return nil
+}
+
+// ListIssuePlans returns the plans linked to an issue, in the order they
+// were linked.
+func (s *Store) ListIssuePlans(ctx context.Context, issueID string) ([]*types.Plan, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT p.id, p.file_path, p.status, p.created_at, p.updated_at
+		FROM issue_plans ip
+		JOIN plans p ON p.id = ip.plan_id
+		WHERE ip.issue_id = $1
+		ORDER BY ip.created_at, p.id
+	`, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("list issue plans: %w", err)
+	}
+	defer rows.Close()
+
+	plans := []*types.Plan{}
+	for rows.Next() {
+		var plan types.Plan
+		if err := rows.Scan(&plan.ID, &plan.FilePath, &plan.Status, &plan.CreatedAt, &plan.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan issue plan: %w", err)
+		}
+		plans = append(plans, &plan)
+	}
+	return plans, rows.Err()
+}
+
+// CreatePlanComment persists a new comment on a plan.
+func (s *Store) CreatePlanComment(ctx context.Context, comment *types.PlanComment) error {
+	comment.CreatedAt = time.Now()
+
+	lineStart, lineEnd, occurrence, quoted, slug, before, after := planCommentAnchorParams(comment.Anchor)
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO plan_comments (`+planCommentColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`, comment.ID, comment.PlanID, nullInt(comment.LineNumber), comment.Content, comment.CreatedAt,
+		lineStart, lineEnd, quoted, occurrence, slug, before, after,
+		toNullTime(comment.UpdatedAt), toNullTime(comment.ResolvedAt))
+	if err != nil {
+		return fmt.Errorf("create plan comment: %w", err)
+	}
+
+	s.publishPlanComment(types.StreamPlanCommentAdded, comment.PlanID, comment.ID)
+	return nil
+}
+
+// ListPlanComments returns all comments for a plan, ordered by creation time.
+func (s *Store) ListPlanComments(ctx context.Context, planID string) ([]*types.PlanComment, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+planCommentColumns+` FROM plan_comments
+		WHERE plan_id = $1 ORDER BY created_at ASC, id ASC
+	`, planID)
+	if err != nil {
+		return nil, fmt.Errorf("list plan comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []*types.PlanComment{}
+	for rows.Next() {
+		c, err := scanPlanComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan plan comment: %w", err)
+		}
+		comments = append(comments, c)
+	}
+	return comments, rows.Err()
+}
+
+// GetPlanComment returns a single plan comment by ID.
+func (s *Store) GetPlanComment(ctx context.Context, id string) (*types.PlanComment, error) {
+	c, err := scanPlanComment(s.db.QueryRowContext(ctx,
+		`SELECT `+planCommentColumns+` FROM plan_comments WHERE id = $1`, id))
+	if err != nil {
+		return nil, fmt.Errorf("get plan comment: %w", err)
+	}
+	return c, nil
+}
+
+// UpdatePlanComment overwrites the mutable fields of a plan comment.
+func (s *Store) UpdatePlanComment(ctx context.Context, comment *types.PlanComment) error {
+	lineStart, lineEnd, occurrence, quoted, slug, before, after := planCommentAnchorParams(comment.Anchor)
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE plan_comments
+		SET content = $1, line_number = $2,
+		    line_start = $3, line_end = $4, quoted_text = $5, occurrence = $6,
+		    heading_slug = $7, context_before = $8, context_after = $9,
+		    updated_at = $10, resolved_at = $11
+		WHERE id = $12
+	`, comment.Content, nullInt(comment.LineNumber),
+		lineStart, lineEnd, quoted, occurrence, slug, before, after,
+		toNullTime(comment.UpdatedAt), toNullTime(comment.ResolvedAt), comment.ID)
+	if err != nil {
+		return fmt.Errorf("update plan comment: %w", err)
+	}
+
+	s.publishPlanComment(types.StreamPlanCommentUpdated, comment.PlanID, comment.ID)
+	return nil
+}
+
+// DeletePlanComment removes a plan comment.
+func (s *Store) DeletePlanComment(ctx context.Context, id string) error {
+	var planID string
+	err := s.db.QueryRowContext(ctx,
+		`DELETE FROM plan_comments WHERE id = $1 RETURNING plan_id`, id,
+	).Scan(&planID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("delete plan comment: %w", err)
+	}
+
+	s.publishPlanComment(types.StreamPlanCommentDeleted, planID, id)
+	return nil
+}
+
+// publishPlanComment notifies subscribers of a plan comment change.
+func (s *Store) publishPlanComment(evType types.StreamEventType, planID, commentID string) {
+	s.publish(types.StreamEvent{
+		Type:   evType,
+		PlanID: planID,
+		Data:   map[string]any{"comment_id": commentID},
+	})
+}
+
+// nullInt returns a valid sql.NullInt64 when p is non-nil.
+func nullInt(p *int) sql.NullInt64 {
+	if p == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(*p), Valid: true}
+}
+
+// planCommentAnchorParams maps the anchor fields of a PlanComment to nullable params.
+func planCommentAnchorParams(a *types.PlanCommentAnchor) (
+	lineStart, lineEnd, occurrence sql.NullInt64,
+	quoted, slug, before, after sql.NullString,
+) {
+	if a == nil {
+		return
+	}
+	lineStart = sql.NullInt64{Int64: int64(a.LineStart), Valid: true}
+	lineEnd = sql.NullInt64{Int64: int64(a.LineEnd), Valid: true}
+	occurrence = sql.NullInt64{Int64: int64(a.Occurrence), Valid: true}
+	quoted = sql.NullString{String: a.QuotedText, Valid: true} // quoted_text NOT NULL marks "anchored"
+	slug = toNullString(a.HeadingSlug)
+	before = toNullString(a.ContextBefore)
+	after = toNullString(a.ContextAfter)
+	return
+}
+
+func scanPlanComment(row rowScanner) (*types.PlanComment, error) {
+	var (
+		c                              types.PlanComment
+		lineNumber                     sql.NullInt64
+		lineStart, lineEnd, occurrence sql.NullInt64
+		quoted, slug, before, after    sql.NullString
+		updatedAt, resolvedAt          sql.NullTime
+	)
+	err := row.Scan(&c.ID, &c.PlanID, &lineNumber, &c.Content, &c.CreatedAt,
+		&lineStart, &lineEnd, &quoted, &occurrence, &slug, &before, &after,
+		&updatedAt, &resolvedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if lineNumber.Valid {
+		v := int(lineNumber.Int64)
+		c.LineNumber = &v
+	}
+	if quoted.Valid {
+		c.Anchor = &types.PlanCommentAnchor{
+			LineStart:     int(lineStart.Int64),
+			LineEnd:       int(lineEnd.Int64),
+			QuotedText:    quoted.String,
+			Occurrence:    int(occurrence.Int64),
+			HeadingSlug:   slug.String,
+			ContextBefore: before.String,
+			ContextAfter:  after.String,
+		}
+	}
+	c.UpdatedAt = fromNullTime(updatedAt)
+	c.ResolvedAt = fromNullTime(resolvedAt)
+	return &c, nil
+}
@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// GetProjectConfig returns all per-project config rows as a key/value map.
+// A project with no config rows yields an empty (non-nil) map.
+func (s *Store) GetProjectConfig(ctx context.Context, projectID string) (map[string]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT key, value FROM config WHERE project_id = $1`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("get project config: %w", err)
+	}
+	defer rows.Close()
+
+	values := map[string]string{}
+	for rows.Next() {
+		var (
+			key   string
+			value sql.NullString
+		)
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, fmt.Errorf("get project config: %w", err)
+		}
+		values[key] = value.String
+	}
+	return values, rows.Err()
+}
+
+// SetProjectConfig upserts a single per-project config key.
+func (s *Store) SetProjectConfig(ctx context.Context, projectID, key, value string) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO config (project_id, key, value) VALUES ($1, $2, $3)
+		ON CONFLICT (project_id, key) DO UPDATE SET value = excluded.value
+	`, projectID, key, toNullString(value))
+	if err != nil {
+		return fmt.Errorf("set project config: %w", err)
+	}
+	return nil
+}
+
+// DeleteProjectConfig removes a single per-project config key.
+func (s *Store) DeleteProjectConfig(ctx context.Context, projectID, key string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM config WHERE project_id = $1 AND key = $2`, projectID, key)
+	if err != nil {
+		return fmt.Errorf("delete project config: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// ListProjectMembers returns a project's members ordered by actor.
+func (s *Store) ListProjectMembers(ctx context.Context, projectID string) ([]*types.ProjectMember, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT project_id, actor, role, created_at FROM project_members
+		WHERE project_id = $1 ORDER BY actor
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list project members: %w", err)
+	}
+	defer rows.Close()
+
+	members := []*types.ProjectMember{}
+	for rows.Next() {
+		m, err := scanProjectMember(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan project member: %w", err)
+		}
+		members = append(members, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate project members: %w", err)
+	}
+	return members, nil
+}
+
+// GetProjectMember returns actor's membership in a project.
+func (s *Store) GetProjectMember(ctx context.Context, projectID, actor string) (*types.ProjectMember, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT project_id, actor, role, created_at FROM project_members
+		WHERE project_id = $1 AND actor = $2
+	`, projectID, actor)
+	m, err := scanProjectMember(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s is not a member of project %s", actor, projectID)
+		}
+		return nil, fmt.Errorf("get project member: %w", err)
+	}
+	return m, nil
+}
+
+// SetProjectMember adds a member or changes an existing member's role.
+func (s *Store) SetProjectMember(ctx context.Context, m *types.ProjectMember) error {
+	if m.Actor == "" {
+		return errors.New("member actor is required")
+	}
+	if !m.Role.IsValid() {
+		return fmt.Errorf("invalid role: %s", m.Role)
+	}
+
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO project_members (project_id, actor, role, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (project_id, actor) DO UPDATE SET role = excluded.role
+		RETURNING created_at
+	`, m.ProjectID, m.Actor, string(m.Role), time.Now()).Scan(&m.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("set project member: %w", err)
+	}
+	return nil
+}
+
+// RemoveProjectMember removes actor from a project.
+func (s *Store) RemoveProjectMember(ctx context.Context, projectID, actor string) error {
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM project_members WHERE project_id = $1 AND actor = $2`, projectID, actor)
+	if err != nil {
+		return fmt.Errorf("remove project member: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("%s is not a member of project %s", actor, projectID)
+	}
+	return nil
+}
+
+func scanProjectMember(row rowScanner) (*types.ProjectMember, error) {
+	var (
+		m    types.ProjectMember
+		role string
+	)
+	if err := row.Scan(&m.ProjectID, &m.Actor, &role, &m.CreatedAt); err != nil {
+		return nil, err
+	}
+	m.Role = types.ProjectRole(role)
+	return &m, nil
+}
@@ -0,0 +1,262 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/project"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// querier is satisfied by both *sql.DB and *sql.Tx.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+const projectColumns = `id, name, description, prefix, created_at, updated_at`
+
+// CreateProject creates a new project.
+func (s *Store) CreateProject(ctx context.Context, p *types.Project) error {
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("validate project: %w", err)
+	}
+
+	// Generate ID if not provided
+	if p.ID == "" {
+		p.ID = project.GenerateProjectID("proj", p.Name)
+	}
+
+	now := time.Now()
+	p.CreatedAt = now
+	p.UpdatedAt = now
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO projects (id, name, description, prefix, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, p.ID, p.Name, toNullString(p.Description), p.Prefix, now, now)
+	if err != nil {
+		return fmt.Errorf("create project: %w", err)
+	}
+
+	return nil
+}
+
+// GetProject retrieves a project by ID.
+func (s *Store) GetProject(ctx context.Context, id string) (*types.Project, error) {
+	p, err := getProject(ctx, s.db, `WHERE id = $1`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("project not found: %s", id)
+		}
+		return nil, fmt.Errorf("get project: %w", err)
+	}
+	return p, nil
+}
+
+// GetProjectByName retrieves a project by name.
+func (s *Store) GetProjectByName(ctx context.Context, name string) (*types.Project, error) {
+	p, err := getProject(ctx, s.db, `WHERE name = $1`, name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("project not found: %s", name)
+		}
+		return nil, fmt.Errorf("get project by name: %w", err)
+	}
+	return p, nil
+}
+
+// ListProjects returns all projects.
+func (s *Store) ListProjects(ctx context.Context) ([]*types.Project, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+projectColumns+` FROM projects ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("list projects: %w", err)
+	}
+	defer rows.Close()
+
+	projects := []*types.Project{}
+	for rows.Next() {
+		p, err := scanProject(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan project: %w", err)
+		}
+		projects = append(projects, p)
+	}
+	return projects, rows.Err()
+}
+
+// UpdateProject updates a project.
+func (s *Store) UpdateProject(ctx context.Context, p *types.Project) error {
+	p.UpdatedAt = time.Now()
+
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE projects SET name = $1, description = $2, updated_at = $3 WHERE id = $4
+	`, p.Name, toNullString(p.Description), p.UpdatedAt, p.ID)
+	if err != nil {
+		return fmt.Errorf("update project: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteProject deletes a project and all its issues.
+// Accepts either project ID (e.g., "proj-00blnw") or name (e.g., "my-project-a1b2c3").
+func (s *Store) DeleteProject(ctx context.Context, idOrName string) error {
+	p, err := s.GetProject(ctx, idOrName)
+	if err != nil {
+		p, err = s.GetProjectByName(ctx, idOrName)
+		if err != nil {
+			return fmt.Errorf("project not found: %s", idOrName)
+		}
+	}
+
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM projects WHERE id = $1`, p.ID); err != nil {
+		return fmt.Errorf("delete project: %w", err)
+	}
+
+	return nil
+}
+
+// MergeProjects moves all issues from source projects into the target
+// project, deletes the sources, and returns a summary. The entire operation
+// runs inside a single transaction for atomicity.
+func (s *Store) MergeProjects(
+	ctx context.Context, targetID string, sourceIDs []string, actor string,
+) (*types.MergeResult, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if _, err := getProject(ctx, tx, `WHERE id = $1`, targetID); err != nil {
+		return nil, fmt.Errorf("target project not found: %s", targetID)
+	}
+
+	var movedIssueIDs, deletedSources []string
+	for _, srcID := range sourceIDs {
+		moved, err := mergeOneSource(ctx, tx, targetID, srcID)
+		if err != nil {
+			return nil, err
+		}
+		movedIssueIDs = append(movedIssueIDs, moved...)
+		deletedSources = append(deletedSources, srcID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit merge: %w", err)
+	}
+
+	// Best-effort post-commit audit trail
+	for _, issueID := range movedIssueIDs {
+		newValue := "merged into " + targetID
+		s.recordEvent(ctx, issueID, types.EventMerged, actor, nil, &newValue)
+	}
+
+	target, err := s.GetProject(ctx, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("fetch merged project: %w", err)
+	}
+	return &types.MergeResult{
+		TargetProject:  target,
+		IssuesMoved:    len(movedIssueIDs),
+		SourcesDeleted: deletedSources,
+	}, nil
+}
+
+// mergeOneSource moves all issues from a single source project into the
+// target, deletes the source config and project, and returns the moved IDs.
+func mergeOneSource(ctx context.Context, tx *sql.Tx, targetID, srcID string) ([]string, error) {
+	if srcID == targetID {
+		return nil, fmt.Errorf("source project cannot be the same as target: %s", srcID)
+	}
+	if _, err := getProject(ctx, tx, `WHERE id = $1`, srcID); err != nil {
+		return nil, fmt.Errorf("source project not found: %s", srcID)
+	}
+
+	rows, err := tx.QueryContext(ctx,
+		`UPDATE issues SET project_id = $1 WHERE project_id = $2 RETURNING id`, targetID, srcID)
+	if err != nil {
+		return nil, fmt.Errorf("move issues from %s: %w", srcID, err)
+	}
+	moved, err := scanStrings(rows)
+	if err != nil {
+		return nil, fmt.Errorf("move issues from %s: %w", srcID, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM config WHERE project_id = $1`, srcID); err != nil {
+		return nil, fmt.Errorf("delete config for %s: %w", srcID, err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM projects WHERE id = $1`, srcID); err != nil {
+		return nil, fmt.Errorf("delete project %s: %w", srcID, err)
+	}
+	return moved, nil
+}
+
+func getProject(ctx context.Context, q querier, where string, arg any) (*types.Project, error) {
+	return scanProject(q.QueryRowContext(ctx, `SELECT `+projectColumns+` FROM projects `+where, arg))
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanProject(row rowScanner) (*types.Project, error) {
+	var (
+		p           types.Project
+		description sql.NullString
+	)
+	if err := row.Scan(&p.ID, &p.Name, &description, &p.Prefix, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	p.Description = fromNullString(description)
+	return &p, nil
+}
+
+// scanStrings reads a single-column result set and closes rows.
+func scanStrings(rows *sql.Rows) ([]string, error) {
+	defer rows.Close()
+	var out []string
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+// Helper functions for nullable fields
+func toNullString(s string) sql.NullString {
+	if s == "" {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: s, Valid: true}
+}
+
+func fromNullString(ns sql.NullString) string {
+	if ns.Valid {
+		return ns.String
+	}
+	return ""
+}
+
+func toNullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+func fromNullTime(nt sql.NullTime) *time.Time {
+	if nt.Valid {
+		return &nt.Time
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// defaultWorkLimit is the default maximum number of issues returned by work queries.
+const defaultWorkLimit = 100
+
+// unblockedOpenIssues selects a project's open and in-progress issues that
+// have no open 'blocks' dependency. Parent-child links are organizational
+// only and never block.
+const unblockedOpenIssues = `
+	SELECT ` + issueColumns + `
+	FROM issues i
+	WHERE i.project_id = $1
+	  AND i.status IN ('open', 'in_progress')
+	  AND NOT EXISTS (
+	    SELECT 1 FROM dependencies d
+	    JOIN issues blocker ON d.depends_on_id = blocker.id
+	    WHERE d.issue_id = i.id
+	      AND d.type = 'blocks'
+	      AND blocker.status != 'closed'
+	  )`
+
+// readySortKeys holds, for each sort policy, the expressions ready work is
+// ordered by, written against the alias %[1]s. The hybrid policy puts issues
+// touched in the last 48 hours first, by priority and rank, followed by
+// older issues oldest first. Every key ends in the issue ID so the order is
+// total and a cursor can resume from any issue.
+var readySortKeys = map[types.SortPolicy]string{
+	types.SortPolicyHybrid: `
+	  CASE WHEN %[1]s.updated_at >= now() - interval '48 hours' THEN 0 ELSE 1 END,
+	  CASE WHEN %[1]s.updated_at >= now() - interval '48 hours' THEN %[1]s.priority ELSE 999 END,
+	  CASE WHEN %[1]s.updated_at >= now() - interval '48 hours'
+	       THEN CASE WHEN %[1]s.rank = 0 THEN 999999 ELSE %[1]s.rank END
+	       ELSE 999999 END,
+	  %[1]s.created_at, %[1]s.id`,
+	types.SortPolicyPriority: `
+	  %[1]s.priority, CASE WHEN %[1]s.rank = 0 THEN 999999 ELSE %[1]s.rank END, %[1]s.created_at, %[1]s.id`,
+	types.SortPolicyOldest: `
+	  %[1]s.created_at, %[1]s.id`,
+}
+
+// blockedSortKey orders blocked issues by priority.
+const blockedSortKey = `%[1]s.priority, %[1]s.id`
+
+// queryArgs accumulates the positional args of a dynamically built query.
+type queryArgs struct {
+	args []any
+}
+
+// arg adds v to the query's args and returns its placeholder.
+func (q *queryArgs) arg(v any) string {
+	q.args = append(q.args, v)
+	return fmt.Sprintf("$%d", len(q.args))
+}
+
+// filterClauses returns the WHERE conditions for every predicate of filter
+// that ready and blocked queries share, sortKey being the order used to
+// resume after filter.After. Only placeholder references are interpolated,
+// never user-supplied values.
+func (q *queryArgs) filterClauses(filter types.WorkFilter, sortKey string) string {
+	var clauses strings.Builder
+	if filter.Status != nil {
+		fmt.Fprintf(&clauses, " AND i.status = %s", q.arg(string(*filter.Status)))
+	}
+	if filter.IssueType != nil {
+		fmt.Fprintf(&clauses, " AND i.issue_type = %s", q.arg(string(*filter.IssueType)))
+	}
+	if filter.Priority != nil {
+		fmt.Fprintf(&clauses, " AND i.priority = %s", q.arg(*filter.Priority))
+	}
+	if filter.ParentID != "" {
+		fmt.Fprintf(&clauses, ` AND EXISTS (SELECT 1 FROM dependencies p
+	    WHERE p.issue_id = i.id AND p.type = 'parent-child' AND p.depends_on_id = %s)`, q.arg(filter.ParentID))
+	}
+	for _, label := range filter.Labels {
+		fmt.Fprintf(&clauses,
+			" AND EXISTS (SELECT 1 FROM issue_labels l WHERE l.issue_id = i.id AND l.label = %s)", q.arg(label))
+	}
+	if filter.After != "" {
+		fmt.Fprintf(&clauses, " AND (%s) > (SELECT %s FROM issues c WHERE c.id = %s)",
+			fmt.Sprintf(sortKey, "i"), fmt.Sprintf(sortKey, "c"), q.arg(filter.After))
+	}
+	return clauses.String()
+}
+
+// page returns the LIMIT and OFFSET clause for filter.
+func (q *queryArgs) page(filter types.WorkFilter) string {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultWorkLimit
+	}
+	return fmt.Sprintf(" LIMIT %s OFFSET %s", q.arg(limit), q.arg(max(filter.Offset, 0)))
+}
+
+// GetReadyWork returns issues that are ready to work on (not blocked).
+// Results are sorted according to the filter's SortPolicy (hybrid, priority, or oldest).
+// Every filter is applied in SQL before the page is cut.
+func (s *Store) GetReadyWork(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error) {
+	sortPolicy := filter.SortPolicy
+	if sortPolicy == "" || !sortPolicy.IsValid() {
+		sortPolicy = types.SortPolicyHybrid
+	}
+	sortKey := readySortKeys[sortPolicy]
+
+	q := &queryArgs{args: []any{filter.ProjectID}}
+	query := unblockedOpenIssues + q.filterClauses(filter, sortKey) +
+		"\n\tORDER BY " + fmt.Sprintf(sortKey, "i") + q.page(filter)
+
+	rows, err := s.db.QueryContext(ctx, query, q.args...)
+	if err != nil {
+		return nil, fmt.Errorf("get ready work: %w", err)
+	}
+	issues, err := scanIssues(rows)
+	if err != nil {
+		return nil, fmt.Errorf("get ready work: %w", err)
+	}
+	if issues == nil {
+		issues = []*types.Issue{}
+	}
+	return issues, nil
+}
+
+// GetBlockedIssues returns issues that are blocked by other issues.
+// For each blocked issue, it also fetches the IDs of the issues blocking it.
+func (s *Store) GetBlockedIssues(ctx context.Context, filter types.WorkFilter) ([]*types.BlockedIssue, error) {
+	q := &queryArgs{args: []any{filter.ProjectID}}
+	query := `
+		SELECT ` + issueColumns + `, string_agg(blocker.id, ',' ORDER BY blocker.priority, blocker.id)
+		FROM issues i
+		JOIN dependencies d ON d.issue_id = i.id AND d.type = 'blocks'
+		JOIN issues blocker ON d.depends_on_id = blocker.id AND blocker.status != 'closed'
+		WHERE i.project_id = $1
+		  AND i.status != 'closed'` + q.filterClauses(filter, blockedSortKey) + `
+		GROUP BY i.id
+		ORDER BY ` + fmt.Sprintf(blockedSortKey, "i") + q.page(filter)
+
+	rows, err := s.db.QueryContext(ctx, query, q.args...)
+	if err != nil {
+		return nil, fmt.Errorf("get blocked issues: %w", err)
+	}
+	defer rows.Close()
+
+	issues := []*types.BlockedIssue{}
+	for rows.Next() {
+		var blockedBy string
+		issue, err := scanIssue(withExtra(rows, &blockedBy))
+		if err != nil {
+			return nil, fmt.Errorf("scan blocked issue: %w", err)
+		}
+		blockers := strings.Split(blockedBy, ",")
+		issues = append(issues, &types.BlockedIssue{
+			Issue:          *issue,
+			BlockedByCount: len(blockers),
+			BlockedBy:      blockers,
+		})
+	}
+	return issues, rows.Err()
+}
+
+// IsBlocked checks if an issue is blocked by any open issues.
+// Returns true and the list of blocking issue IDs if blocked, false otherwise.
+func (s *Store) IsBlocked(ctx context.Context, issueID string) (bool, []string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT i.id FROM issues i
+		JOIN dependencies d ON i.id = d.depends_on_id
+		WHERE d.issue_id = $1
+		  AND d.type = 'blocks'
+		  AND i.status != 'closed'
+		ORDER BY i.priority ASC
+	`, issueID)
+	if err != nil {
+		return false, nil, fmt.Errorf("get blocking issues: %w", err)
+	}
+	blockerIDs, err := scanStrings(rows)
+	if err != nil {
+		return false, nil, fmt.Errorf("get blocking issues: %w", err)
+	}
+	if len(blockerIDs) == 0 {
+		return false, nil, nil
+	}
+	return true, blockerIDs, nil
+}
+
+// GetStatistics returns aggregate statistics for a project.
+// Includes counts by status, ready issue count, and average lead time.
+func (s *Store) GetStatistics(ctx context.Context, projectID string) (*types.Statistics, error) {
+	stats := &types.Statistics{ProjectID: projectID}
+	var avgLeadTime sql.NullFloat64
+	err := s.db.QueryRowContext(ctx, `
+		SELECT
+			count(*),
+			count(*) FILTER (WHERE status = 'open'),
+			count(*) FILTER (WHERE status = 'in_progress'),
+			count(*) FILTER (WHERE status = 'closed'),
+			count(*) FILTER (WHERE status = 'blocked'),
+			count(*) FILTER (WHERE status = 'deferred'),
+			(avg(extract(epoch FROM closed_at - created_at) / 3600)
+				FILTER (WHERE status = 'closed' AND closed_at IS NOT NULL))::float8
+		FROM issues WHERE project_id = $1
+	`, projectID).Scan(
+		&stats.TotalIssues, &stats.OpenIssues, &stats.InProgressIssues,
+		&stats.ClosedIssues, &stats.BlockedIssues, &stats.DeferredIssues, &avgLeadTime,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get project stats: %w", err)
+	}
+	stats.AvgLeadTimeHours = avgLeadTime.Float64
+
+	err = s.db.QueryRowContext(ctx,
+		`SELECT count(*) FROM (`+unblockedOpenIssues+`) ready`, projectID,
+	).Scan(&stats.ReadyIssues)
+	if err != nil {
+		return nil, fmt.Errorf("get ready issue count: %w", err)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,207 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// searchIssues runs a full-text search against the generated issues.search
+// tsvector and ranks matches with ts_rank, title terms outranking
+// description terms. Falls back to ILIKE matching if the query cannot be
+// parsed as a tsquery.
+func (s *Store) searchIssues(
+	ctx context.Context, projectID, query string, limit, offset int,
+) ([]*types.Issue, error) {
+	tsQuery := PrepareSearchQuery(query)
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+issueColumns+`
+		FROM issues i, to_tsquery('simple', $2) q
+		WHERE i.project_id = $1
+		  AND i.search @@ q
+		ORDER BY ts_rank(i.search, q) DESC, i.updated_at DESC
+		LIMIT $3 OFFSET $4
+	`, projectID, tsQuery, limit, offset)
+	if err != nil {
+		log.Printf("search: tsquery failed (query=%q, tsquery=%q), falling back to ILIKE: %v", query, tsQuery, err)
+		return s.searchIssuesLike(ctx, projectID, query, limit, offset)
+	}
+	issues, err := scanIssues(rows)
+	if err != nil {
+		return nil, fmt.Errorf("search issues: %w", err)
+	}
+	return issues, nil
+}
+
+// headlineOptions configures the ts_headline snippets SearchIssues reads
+// back. The match delimiters cannot occur in issue text, so a snippet
+// containing the start delimiter is known to come from a field that
+// matched; they are replaced with the public highlight markers afterwards.
+const headlineOptions = "StartSel=\x02, StopSel=\x03, MaxWords=16, MinWords=6, MaxFragments=1, FragmentDelimiter=…"
+
+// highlighter replaces the ts_headline match delimiters with the public
+// highlight markers.
+var highlighter = strings.NewReplacer("\x02", types.HighlightStart, "\x03", types.HighlightEnd)
+
+// searchFields are the issue columns a search hit can match, in order of
+// preference.
+var searchFields = []struct {
+	column string
+	field  types.SearchField
+}{
+	{"i.title", types.SearchFieldTitle},
+	{"coalesce(i.description, '')", types.SearchFieldDescription},
+	{"i.search_comments", types.SearchFieldComments},
+	{"i.search_labels", types.SearchFieldLabels},
+	{"i.search_plans", types.SearchFieldPlans},
+}
+
+// SearchIssues runs a ranked full-text search over issues, their comments,
+// labels and linked plans, in the given projects or in all of them.
+func (s *Store) SearchIssues(ctx context.Context, filter types.SearchFilter) ([]*types.SearchHit, error) {
+	hits := []*types.SearchHit{}
+	tsQuery := PrepareSearchQuery(filter.Query)
+	if tsQuery == "" {
+		return hits, nil
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	var q queryArgs
+	from := "issues i, to_tsquery('simple', " + q.arg(tsQuery) + ") q"
+	options := q.arg(headlineOptions)
+	headlines := make([]string, len(searchFields))
+	for i, f := range searchFields {
+		headlines[i] = fmt.Sprintf("ts_headline('simple', %s, q, %s)", f.column, options)
+	}
+	where := "i.search @@ q"
+	if len(filter.ProjectIDs) > 0 {
+		where += " AND i.project_id = ANY(" + q.arg(filter.ProjectIDs) + ")"
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+issueColumns+`, ts_rank(i.search, q) AS score, `+strings.Join(headlines, ", ")+`
+		FROM `+from+`
+		WHERE `+where+`
+		ORDER BY score DESC, i.id
+		LIMIT `+q.arg(limit)+` OFFSET `+q.arg(max(filter.Offset, 0)),
+		q.args...)
+	if err != nil {
+		return nil, fmt.Errorf("search issues: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			score     float64
+			snippets  = make([]string, len(searchFields))
+			extraDest = []any{&score}
+		)
+		for i := range snippets {
+			extraDest = append(extraDest, &snippets[i])
+		}
+		issue, err := scanIssue(scanWith{rows, extraDest})
+		if err != nil {
+			return nil, fmt.Errorf("scan search hit: %w", err)
+		}
+
+		hit := &types.SearchHit{Issue: issue, Score: score}
+		for i, snippet := range snippets {
+			if strings.Contains(snippet, "\x02") {
+				hit.Field, hit.Snippet = searchFields[i].field, highlighter.Replace(snippet)
+				break
+			}
+		}
+		hits = append(hits, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate search hits: %w", err)
+	}
+	return hits, nil
+}
+
+// scanWith is a rowScanner that scans extra trailing columns into dest
+// after the ones the caller asks for.
+type scanWith struct {
+	rows *sql.Rows
+	dest []any
+}
+
+func (s scanWith) Scan(dest ...any) error {
+	return s.rows.Scan(append(dest, s.dest...)...)
+}
+
+// searchIssuesLike is the fallback search using ILIKE pattern matching.
+func (s *Store) searchIssuesLike(
+	ctx context.Context, projectID, query string, limit, offset int,
+) ([]*types.Issue, error) {
+	pattern := "%" + query + "%"
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+issueColumns+`
+		FROM issues i
+		WHERE i.project_id = $1
+		  AND (i.title ILIKE $2 OR i.description ILIKE $2)
+		ORDER BY i.updated_at DESC
+		LIMIT $3 OFFSET $4
+	`, projectID, pattern, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("ILIKE search: %w", err)
+	}
+	return scanIssues(rows)
+}
+
+// PrepareSearchQuery transforms user input into a to_tsquery expression
+// with the same semantics as the SQLite backend's FTS5 query:
+//   - Each unquoted term is a prefix match ('term':*)
+//   - Quoted phrases match their words in sequence
+//   - All terms must match
+//   - Empty input returns empty string
+func PrepareSearchQuery(input string) string {
+	var parts []string
+	remaining := input
+	for {
+		remaining = strings.TrimSpace(remaining)
+		if remaining == "" {
+			break
+		}
+		var term string
+		var phrase bool
+		term, phrase, remaining = nextSearchToken(remaining)
+		if term == "" {
+			continue
+		}
+		lexeme := "'" + tsQueryEscaper.Replace(term) + "'"
+		if !phrase {
+			lexeme += ":*"
+		}
+		parts = append(parts, lexeme)
+	}
+	return strings.Join(parts, " & ")
+}
+
+// tsQueryEscaper escapes a lexeme for use between single quotes in a tsquery.
+var tsQueryEscaper = strings.NewReplacer(`\`, `\\`, `'`, `''`)
+
+// nextSearchToken splits the next term or quoted phrase off s. An unclosed
+// quote runs to the end of the input.
+func nextSearchToken(s string) (term string, phrase bool, rest string) {
+	if s[0] == '"' {
+		end := strings.IndexByte(s[1:], '"')
+		if end == -1 {
+			return strings.TrimSpace(s[1:]), true, ""
+		}
+		return strings.TrimSpace(s[1 : end+1]), true, s[end+2:]
+	}
+	end := strings.IndexAny(s, " \t\"")
+	if end == -1 {
+		return s, false, ""
+	}
+	return s[:end], false, s[end:]
+}
@@ -0,0 +1,74 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/storage/postgres"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func TestPrepareSearchQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"empty string", "", ""},
+		{"single term", "auth", "'auth':*"},
+		{"multiple terms", "fix bug", "'fix':* & 'bug':*"},
+		{"quoted phrase", `"fix bug"`, "'fix bug'"},
+		{"mixed terms and quotes", `auth "fix bug" deploy`, "'auth':* & 'fix bug' & 'deploy':*"},
+		{"extra whitespace", "  auth   deploy  ", "'auth':* & 'deploy':*"},
+		{"unclosed quote", `"fix bug`, "'fix bug'"},
+		{"quote in term", "o'brien", "'o''brien':*"},
+		{"empty phrase", `"" auth`, "'auth':*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := postgres.PrepareSearchQuery(tt.input)
+			if got != tt.want {
+				t.Errorf("PrepareSearchQuery(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchRanksTitleMatchesFirst(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+	proj := setupTestProject(t, store)
+
+	inDescription := createTestIssue(t, store, &types.Issue{
+		ProjectID: proj.ID, Title: "Refresh docs", Description: "Cover the authentication flow",
+		IssueType: types.TypeTask,
+	})
+	inTitle := createTestIssue(t, store, &types.Issue{
+		ProjectID: proj.ID, Title: "Fix authentication bug", IssueType: types.TypeBug,
+	})
+	createTestIssue(t, store, &types.Issue{ProjectID: proj.ID, Title: "Deploy pipeline", IssueType: types.TypeTask})
+
+	results, err := store.ListIssues(ctx, types.IssueFilter{ProjectID: proj.ID, Query: "auth"})
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results for 'auth', want 2", len(results))
+	}
+	if results[0].ID != inTitle.ID || results[1].ID != inDescription.ID {
+		t.Errorf("order = [%s %s], want title match %s first", results[0].ID, results[1].ID, inTitle.ID)
+	}
+
+	if err := store.UpdateIssue(ctx, inTitle.ID, map[string]any{"title": "Fix login bug"}, 0, "test-actor"); err != nil {
+		t.Fatalf("update issue: %v", err)
+	}
+	results, err = store.ListIssues(ctx, types.IssueFilter{ProjectID: proj.ID, Query: "login"})
+	if err != nil {
+		t.Fatalf("search after update: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != inTitle.ID {
+		t.Errorf("search after update = %v, want %s", results, inTitle.ID)
+	}
+}
@@ -0,0 +1,147 @@
+// Package postgres implements the storage interface using PostgreSQL.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pressly/goose/v3"
+	"github.com/sentiolabs/arc/internal/storage"
+	"github.com/sentiolabs/arc/internal/types"
+
+	_ "github.com/jackc/pgx/v5/stdlib" // registers the "pgx" database/sql driver
+)
+
+// Connection pool settings.
+const (
+	maxOpenConns    = 10
+	maxIdleConns    = 5
+	connMaxLifetime = time.Hour
+)
+
+// migrations embeds the PostgreSQL schema migrations. They are numbered
+// independently of the SQLite migrations.
+//
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Store implements the storage.Storage interface using PostgreSQL.
+type Store struct {
+	db        *sql.DB
+	dsn       string
+	publisher storage.Publisher
+}
+
+// New connects to the PostgreSQL database at dsn (a postgres:// URL or a
+// key=value connection string) and applies any pending migrations.
+func New(dsn string) (*Store, error) {
+	if dsn == "" {
+		return nil, fmt.Errorf("postgres DSN is required")
+	}
+
+	sqlDB, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	sqlDB.SetMaxOpenConns(maxOpenConns)
+	sqlDB.SetMaxIdleConns(maxIdleConns)
+	sqlDB.SetConnMaxLifetime(connMaxLifetime)
+
+	if err := sqlDB.PingContext(context.Background()); err != nil {
+		_ = sqlDB.Close()
+		return nil, fmt.Errorf("connect to database: %w", err)
+	}
+
+	if err := RunMigrations(context.Background(), sqlDB); err != nil {
+		_ = sqlDB.Close()
+		return nil, fmt.Errorf("init schema: %w", err)
+	}
+
+	return &Store{db: sqlDB, dsn: dsn}, nil
+}
+
+// RunMigrations applies all pending schema migrations.
+func RunMigrations(ctx context.Context, db *sql.DB) error {
+	fsys, err := fs.Sub(migrations, "migrations")
+	if err != nil {
+		return err
+	}
+	provider, err := goose.NewProvider(goose.DialectPostgres, db, fsys)
+	if err != nil {
+		return fmt.Errorf("create migration provider: %w", err)
+	}
+	if _, err := provider.Up(ctx); err != nil {
+		return fmt.Errorf("run migrations: %w", err)
+	}
+	return nil
+}
+
+// DB returns the underlying *sql.DB connection.
+// It is used by callers that need direct database access.
+func (s *Store) DB() *sql.DB {
+	return s.db
+}
+
+// Close closes the database connection pool.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Path returns the connection string with any password removed, so it is
+// safe to log.
+func (s *Store) Path() string {
+	return RedactDSN(s.dsn)
+}
+
+// RedactDSN masks the password in a postgres:// URL. Key=value connection
+// strings are reduced to their host, port, database and user.
+func RedactDSN(dsn string) string {
+	if dsn == "" {
+		return ""
+	}
+	if u, err := url.Parse(dsn); err == nil && u.Scheme != "" {
+		return u.Redacted()
+	}
+	cfg, err := pgconn.ParseConfig(dsn)
+	if err != nil {
+		return "postgres"
+	}
+	return fmt.Sprintf("host=%s port=%d dbname=%s user=%s", cfg.Host, cfg.Port, cfg.Database, cfg.User)
+}
+
+// SetPublisher registers the receiver of live change notifications.
+// A nil publisher disables publishing.
+func (s *Store) SetPublisher(p storage.Publisher) {
+	s.publisher = p
+}
+
+// publish forwards a change notification to the registered publisher, if any.
+func (s *Store) publish(ev types.StreamEvent) {
+	if s.publisher != nil {
+		s.publisher.Publish(ev)
+	}
+}
+
+// issueProjectID returns the project an issue belongs to, or "" if the
+// issue cannot be found. Used to scope change notifications.
+func (s *Store) issueProjectID(ctx context.Context, issueID string) string {
+	var projectID string
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT project_id FROM issues WHERE id = $1`, issueID,
+	).Scan(&projectID); err != nil {
+		return ""
+	}
+	return projectID
+}
+
+// Ensure Store implements storage.Storage and storage.Notifier
+var (
+	_ storage.Storage  = (*Store)(nil)
+	_ storage.Notifier = (*Store)(nil)
+)
@@ -0,0 +1,98 @@
+package postgres_test
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/storage/postgres"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// testDSNEnv names the environment variable holding the connection string
+// of a scratch PostgreSQL server, e.g. one started with
+//
+//	docker run --rm -e POSTGRES_PASSWORD=arc -p 5432:5432 postgres:16
+//
+// Tests that need a database are skipped when it is unset.
+const testDSNEnv = "ARC_TEST_POSTGRES_DSN"
+
+// setupTestStore opens a store in a fresh schema that is dropped on cleanup,
+// so tests can share one server.
+func setupTestStore(t *testing.T) (*postgres.Store, func()) {
+	t.Helper()
+
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s not set", testDSNEnv)
+	}
+
+	admin, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open admin connection: %v", err)
+	}
+	buf := make([]byte, 6)
+	_, _ = rand.Read(buf)
+	schema := "arc_test_" + hex.EncodeToString(buf)
+	if _, err := admin.Exec(`CREATE SCHEMA ` + schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatalf("%s must be a postgres:// URL: %v", testDSNEnv, err)
+	}
+	q := u.Query()
+	q.Set("search_path", schema)
+	u.RawQuery = q.Encode()
+
+	store, err := postgres.New(u.String())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	cleanup := func() {
+		_ = store.Close()
+		_, _ = admin.Exec(`DROP SCHEMA ` + schema + ` CASCADE`)
+		_ = admin.Close()
+	}
+	return store, cleanup
+}
+
+// setupTestProject creates a project for testing.
+func setupTestProject(t *testing.T, store *postgres.Store) *types.Project {
+	t.Helper()
+
+	proj := &types.Project{Name: "Test Project", Prefix: "test"}
+	if err := store.CreateProject(context.Background(), proj); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+	return proj
+}
+
+func TestNewRequiresDSN(t *testing.T) {
+	if _, err := postgres.New(""); err == nil {
+		t.Fatal("New(\"\") succeeded, want error")
+	}
+}
+
+func TestRedactDSN(t *testing.T) {
+	tests := []struct {
+		dsn  string
+		want string
+	}{
+		{"", ""},
+		{"postgres://arc:secret@db:5432/arc", "postgres://arc:xxxxx@db:5432/arc"},
+		{"postgres://arc@db/arc?sslmode=disable", "postgres://arc@db/arc?sslmode=disable"},
+		{"host=db port=5433 dbname=arc user=arc password=secret", "host=db port=5433 dbname=arc user=arc"},
+	}
+	for _, tt := range tests {
+		if got := postgres.RedactDSN(tt.dsn); got != tt.want {
+			t.Errorf("RedactDSN(%q) = %q, want %q", tt.dsn, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/project"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+const workspaceColumns = `id, project_id, path, label, hostname, git_remote,
+	path_type, last_accessed_at, created_at, updated_at`
+
+// CreateWorkspace creates a new workspace (directory path entry).
+func (s *Store) CreateWorkspace(ctx context.Context, ws *types.Workspace) error {
+	if err := ws.Validate(); err != nil {
+		return fmt.Errorf("validate workspace: %w", err)
+	}
+
+	if ws.ID == "" {
+		ws.ID = project.GenerateProjectID("ws", ws.Path)
+	}
+
+	now := time.Now()
+	ws.CreatedAt = now
+	ws.UpdatedAt = now
+
+	if ws.PathType == "" {
+		ws.PathType = "canonical"
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO workspaces (id, project_id, path, label, hostname, git_remote,
+		                        path_type, last_accessed_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, ws.ID, ws.ProjectID, ws.Path, toNullString(ws.Label), toNullString(ws.Hostname),
+		toNullString(ws.GitRemote), ws.PathType, toNullTime(ws.LastAccessedAt), now, now)
+	if err != nil {
+		return fmt.Errorf("create workspace: %w", err)
+	}
+
+	return nil
+}
+
+// GetWorkspace retrieves a workspace by its unique ID.
+// Returns an error if no workspace exists with the given ID.
+func (s *Store) GetWorkspace(ctx context.Context, id string) (*types.Workspace, error) {
+	ws, err := scanWorkspace(s.db.QueryRowContext(ctx,
+		`SELECT `+workspaceColumns+` FROM workspaces WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("workspace not found: %s", id)
+		}
+		return nil, fmt.Errorf("get workspace: %w", err)
+	}
+	return ws, nil
+}
+
+// ListWorkspaces returns all workspaces for a project.
+func (s *Store) ListWorkspaces(ctx context.Context, projectID string) ([]*types.Workspace, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+workspaceColumns+` FROM workspaces WHERE project_id = $1 ORDER BY path`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list workspaces: %w", err)
+	}
+	defer rows.Close()
+
+	result := []*types.Workspace{}
+	for rows.Next() {
+		ws, err := scanWorkspace(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan workspace: %w", err)
+		}
+		result = append(result, ws)
+	}
+	return result, rows.Err()
+}
+
+// UpdateWorkspace updates a workspace entry.
+func (s *Store) UpdateWorkspace(ctx context.Context, ws *types.Workspace) error {
+	ws.UpdatedAt = time.Now()
+
+	if ws.PathType == "" {
+		ws.PathType = "canonical"
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE workspaces
+		SET label = $1, hostname = $2, git_remote = $3, path_type = $4, updated_at = $5
+		WHERE id = $6
+	`, toNullString(ws.Label), toNullString(ws.Hostname), toNullString(ws.GitRemote),
+		ws.PathType, ws.UpdatedAt, ws.ID)
+	if err != nil {
+		return fmt.Errorf("update workspace: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteWorkspace removes a workspace entry.
+func (s *Store) DeleteWorkspace(ctx context.Context, id string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM workspaces WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("delete workspace: %w", err)
+	}
+	return nil
+}
+
+// resolveProjectByPathSQL escapes \, % and _ in the stored path so that
+// workspace paths containing those characters do not act as LIKE wildcards.
+const resolveProjectByPathSQL = `
+	SELECT w.id, w.project_id, w.path, w.label, w.hostname, w.git_remote,
+	       w.path_type, w.last_accessed_at, w.created_at, w.updated_at
+	FROM workspaces w
+	JOIN projects p ON w.project_id = p.id
+	WHERE w.path = $1
+	   OR $1 LIKE replace(replace(replace(w.path, '\', '\\'), '%', '\%'), '_', '\_') || '/%' ESCAPE '\'
+	ORDER BY length(w.path) DESC
+	LIMIT 1
+`
+
+// ResolveProjectByPath finds the workspace whose path is `path` exactly or
+// is the longest registered ancestor of `path` (component-wise).
+func (s *Store) ResolveProjectByPath(ctx context.Context, path string) (*types.Workspace, error) {
+	ws, err := scanWorkspace(s.db.QueryRowContext(ctx, resolveProjectByPathSQL, path))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("workspace not found for path: %s", path)
+		}
+		return nil, fmt.Errorf("resolve project by path: %w", err)
+	}
+	return ws, nil
+}
+
+// UpdateWorkspaceLastAccessed updates the last_accessed_at timestamp for a workspace.
+func (s *Store) UpdateWorkspaceLastAccessed(ctx context.Context, id string) error {
+	now := time.Now()
+
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE workspaces SET last_accessed_at = $1, updated_at = $1 WHERE id = $2`, now, id)
+	if err != nil {
+		return fmt.Errorf("update workspace last accessed: %w", err)
+	}
+
+	return nil
+}
+
+func scanWorkspace(row rowScanner) (*types.Workspace, error) {
+	var (
+		ws                         types.Workspace
+		label, hostname, gitRemote sql.NullString
+		lastAccessedAt             sql.NullTime
+	)
+	err := row.Scan(&ws.ID, &ws.ProjectID, &ws.Path, &label, &hostname, &gitRemote,
+		&ws.PathType, &lastAccessedAt, &ws.CreatedAt, &ws.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	ws.Label = fromNullString(label)
+	ws.Hostname = fromNullString(hostname)
+	ws.GitRemote = fromNullString(gitRemote)
+	ws.LastAccessedAt = fromNullTime(lastAccessedAt)
+	return &ws, nil
+}
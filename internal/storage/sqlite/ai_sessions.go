@@ -25,6 +25,12 @@ func (s *Store) CreateAISession(ctx context.Context, session *types.AISession) e
 	if err != nil {
 		return fmt.Errorf("create ai session: %w", err)
 	}
+
+	s.publish(types.StreamEvent{
+		Type:      types.StreamAISessionCreated,
+		ProjectID: session.ProjectID,
+		SessionID: session.ID,
+	})
 	return nil
 }
 
@@ -100,6 +106,19 @@ func (s *Store) CreateAIAgent(ctx context.Context, agent *types.AIAgent) error {
 	if err != nil {
 		return fmt.Errorf("create ai agent: %w", err)
 	}
+
+	if s.publisher != nil {
+		var projectID string
+		if session, err := s.queries.GetAISession(ctx, agent.SessionID); err == nil {
+			projectID = session.ProjectID
+		}
+		s.publish(types.StreamEvent{
+			Type:      types.StreamAIAgentRegistered,
+			ProjectID: projectID,
+			SessionID: agent.SessionID,
+			Data:      map[string]any{"agent_id": agent.ID, "status": agent.Status},
+		})
+	}
 	return nil
 }
 
@@ -0,0 +1,122 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/project"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+const apiTokenColumns = `id, name, actor, prefix, created_at, last_used_at, revoked_at`
+
+// CreateAPIToken stores a token under its hash. The plaintext tok.Token is
+// never written.
+func (s *Store) CreateAPIToken(ctx context.Context, tok *types.APIToken, tokenHash string) error {
+	if tok.Name == "" {
+		return errors.New("token name is required")
+	}
+	if tok.Actor == "" {
+		return errors.New("token actor is required")
+	}
+	if tok.ID == "" {
+		tok.ID = project.GenerateProjectID("tok", tok.Actor+tok.Name)
+	}
+	tok.CreatedAt = time.Now()
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO api_tokens (id, name, actor, token_hash, prefix, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, tok.ID, tok.Name, tok.Actor, tokenHash, tok.Prefix, tok.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("create api token: %w", err)
+	}
+	return nil
+}
+
+// GetAPIToken retrieves a token's metadata by ID.
+func (s *Store) GetAPIToken(ctx context.Context, id string) (*types.APIToken, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+apiTokenColumns+` FROM api_tokens WHERE id = ?`, id)
+	tok, err := scanAPIToken(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("api token not found: %s", id)
+		}
+		return nil, fmt.Errorf("get api token: %w", err)
+	}
+	return tok, nil
+}
+
+// GetAPITokenByHash looks a token up by the hash of its plaintext.
+// Revoked tokens are returned too; callers must check RevokedAt.
+func (s *Store) GetAPITokenByHash(ctx context.Context, tokenHash string) (*types.APIToken, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+apiTokenColumns+` FROM api_tokens WHERE token_hash = ?`, tokenHash)
+	tok, err := scanAPIToken(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("api token not found")
+		}
+		return nil, fmt.Errorf("get api token: %w", err)
+	}
+	return tok, nil
+}
+
+// ListAPITokens returns all tokens, including revoked ones, oldest first.
+func (s *Store) ListAPITokens(ctx context.Context) ([]*types.APIToken, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+apiTokenColumns+` FROM api_tokens ORDER BY created_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("list api tokens: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := []*types.APIToken{}
+	for rows.Next() {
+		tok, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan api token: %w", err)
+		}
+		tokens = append(tokens, tok)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate api tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// RevokeAPIToken marks a token revoked. Revoking twice is a no-op.
+func (s *Store) RevokeAPIToken(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx,
+		`UPDATE api_tokens SET revoked_at = COALESCE(revoked_at, ?) WHERE id = ?`, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("revoke api token: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("api token not found: %s", id)
+	}
+	return nil
+}
+
+// TouchAPIToken records when a token was last used.
+func (s *Store) TouchAPIToken(ctx context.Context, id string, at time.Time) error {
+	if _, err := s.db.ExecContext(ctx, `UPDATE api_tokens SET last_used_at = ? WHERE id = ?`, at, id); err != nil {
+		return fmt.Errorf("touch api token: %w", err)
+	}
+	return nil
+}
+
+func scanAPIToken(row rowScanner) (*types.APIToken, error) {
+	var (
+		tok        types.APIToken
+		lastUsedAt sql.NullTime
+		revokedAt  sql.NullTime
+	)
+	if err := row.Scan(&tok.ID, &tok.Name, &tok.Actor, &tok.Prefix, &tok.CreatedAt, &lastUsedAt, &revokedAt); err != nil {
+		return nil, err
+	}
+	tok.LastUsedAt = fromNullTime(lastUsedAt)
+	tok.RevokedAt = fromNullTime(revokedAt)
+	return &tok, nil
+}
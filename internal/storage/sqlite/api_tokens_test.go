@@ -0,0 +1,63 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func TestAPITokenLifecycle(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	tok := &types.APIToken{Name: "laptop", Actor: "alice", Prefix: "arc_01234567"}
+	if err := store.CreateAPIToken(ctx, tok, "hash-1"); err != nil {
+		t.Fatalf("CreateAPIToken: %v", err)
+	}
+	if tok.ID == "" {
+		t.Fatal("expected a generated token ID")
+	}
+	if err := store.CreateAPIToken(ctx, &types.APIToken{Name: "dup", Actor: "bob"}, "hash-1"); err == nil {
+		t.Error("expected error for duplicate token hash")
+	}
+	if err := store.CreateAPIToken(ctx, &types.APIToken{Name: "nobody"}, "hash-2"); err == nil {
+		t.Error("expected error for missing actor")
+	}
+
+	got, err := store.GetAPITokenByHash(ctx, "hash-1")
+	if err != nil {
+		t.Fatalf("GetAPITokenByHash: %v", err)
+	}
+	if got.ID != tok.ID || got.Actor != "alice" || got.Prefix != "arc_01234567" || got.LastUsedAt != nil {
+		t.Errorf("GetAPITokenByHash = %+v", got)
+	}
+	if _, err := store.GetAPITokenByHash(ctx, "unknown"); err == nil {
+		t.Error("expected error for unknown hash")
+	}
+
+	usedAt := time.Now().UTC().Truncate(time.Second)
+	if err := store.TouchAPIToken(ctx, tok.ID, usedAt); err != nil {
+		t.Fatalf("TouchAPIToken: %v", err)
+	}
+	if err := store.RevokeAPIToken(ctx, tok.ID); err != nil {
+		t.Fatalf("RevokeAPIToken: %v", err)
+	}
+	got, err = store.GetAPIToken(ctx, tok.ID)
+	if err != nil {
+		t.Fatalf("GetAPIToken: %v", err)
+	}
+	if got.LastUsedAt == nil || !got.LastUsedAt.Equal(usedAt) || got.RevokedAt == nil {
+		t.Errorf("GetAPIToken after touch and revoke = %+v", got)
+	}
+
+	tokens, err := store.ListAPITokens(ctx)
+	if err != nil || len(tokens) != 1 {
+		t.Fatalf("ListAPITokens = %d, %v; want 1", len(tokens), err)
+	}
+	if err := store.RevokeAPIToken(ctx, "tok-missing"); err == nil {
+		t.Error("expected error revoking a missing token")
+	}
+}
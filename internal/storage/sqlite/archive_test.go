@@ -0,0 +1,131 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func TestExportImportProject_RoundTrip(t *testing.T) {
+	src, cleanupSrc := setupTestStore(t)
+	defer cleanupSrc()
+	ctx := context.Background()
+
+	proj := setupTestProject(t, src)
+	if err := src.SetProjectConfig(ctx, proj.ID, "share.author", "alice"); err != nil {
+		t.Fatalf("SetProjectConfig: %v", err)
+	}
+	if err := src.SetProjectConfig(ctx, proj.ID, "field.estimate", "number"); err != nil {
+		t.Fatalf("SetProjectConfig: %v", err)
+	}
+	ws := &types.Workspace{ProjectID: proj.ID, Path: "/work/repo"}
+	if err := src.CreateWorkspace(ctx, ws); err != nil {
+		t.Fatalf("CreateWorkspace: %v", err)
+	}
+
+	parent := setupTestIssue(t, src, proj, "Parent")
+	child := &types.Issue{ProjectID: proj.ID, Title: "Child", ParentID: parent.ID,
+		Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask, Fields: map[string]string{"estimate": "2"}}
+	if err := src.CreateIssue(ctx, child, "tester"); err != nil {
+		t.Fatalf("CreateIssue child: %v", err)
+	}
+	if err := src.CreateLabel(ctx, &types.Label{Name: "backend", Color: "#00ff00"}); err != nil {
+		t.Fatalf("CreateLabel: %v", err)
+	}
+	if err := src.AddLabelToIssue(ctx, parent.ID, "backend", "tester"); err != nil {
+		t.Fatalf("AddLabelToIssue: %v", err)
+	}
+	if _, err := src.AddComment(ctx, parent.ID, "tester", "first comment"); err != nil {
+		t.Fatalf("AddComment: %v", err)
+	}
+	plan := &types.Plan{ID: "plan.abc123", FilePath: "/work/repo/docs/plan.md", Status: types.PlanStatusDraft}
+	if err := src.CreatePlan(ctx, plan); err != nil {
+		t.Fatalf("CreatePlan: %v", err)
+	}
+	if err := src.CreatePlanComment(ctx, &types.PlanComment{ID: "pc.1", PlanID: plan.ID, Content: "looks good"}); err != nil {
+		t.Fatalf("CreatePlanComment: %v", err)
+	}
+
+	archive, err := src.ExportProject(ctx, proj.ID)
+	if err != nil {
+		t.Fatalf("ExportProject: %v", err)
+	}
+	if len(archive.Issues) != 2 || len(archive.Dependencies) != 1 || len(archive.Comments) != 1 {
+		t.Fatalf("export counts: issues=%d deps=%d comments=%d",
+			len(archive.Issues), len(archive.Dependencies), len(archive.Comments))
+	}
+	if len(archive.Plans) != 1 || len(archive.PlanComments) != 1 || len(archive.Labels) != 1 {
+		t.Fatalf("export counts: plans=%d plan comments=%d labels=%d",
+			len(archive.Plans), len(archive.PlanComments), len(archive.Labels))
+	}
+
+	dst, cleanupDst := setupTestStore(t)
+	defer cleanupDst()
+	if err := dst.ImportProject(ctx, archive); err != nil {
+		t.Fatalf("ImportProject: %v", err)
+	}
+
+	got, err := dst.GetIssue(ctx, child.ID)
+	if err != nil {
+		t.Fatalf("GetIssue after import: %v", err)
+	}
+	if got.Title != "Child" || got.Priority != 1 || got.ProjectID != proj.ID {
+		t.Errorf("imported child = %+v", got)
+	}
+	if fields, _ := dst.GetFieldsForIssues(ctx, []string{child.ID}); fields[child.ID]["estimate"] != "2" {
+		t.Errorf("fields = %v, want estimate=2", fields)
+	}
+	labels, _ := dst.GetIssueLabels(ctx, parent.ID)
+	if len(labels) != 1 || labels[0] != "backend" {
+		t.Errorf("labels = %v, want [backend]", labels)
+	}
+	deps, _ := dst.GetDependencies(ctx, child.ID)
+	if len(deps) != 1 || deps[0].DependsOnID != parent.ID {
+		t.Errorf("dependencies = %+v", deps)
+	}
+	cfg, _ := dst.GetProjectConfig(ctx, proj.ID)
+	if cfg["share.author"] != "alice" {
+		t.Errorf("config = %v", cfg)
+	}
+	if _, err := dst.GetPlanComment(ctx, "pc.1"); err != nil {
+		t.Errorf("plan comment not imported: %v", err)
+	}
+
+	// The next child must not reuse an imported child number.
+	next := &types.Issue{ProjectID: proj.ID, Title: "Second child", ParentID: parent.ID,
+		Status: types.StatusOpen, IssueType: types.TypeTask}
+	if err := dst.CreateIssue(ctx, next, "tester"); err != nil {
+		t.Fatalf("CreateIssue after import: %v", err)
+	}
+	if next.ID == child.ID {
+		t.Errorf("new child reused imported ID %s", child.ID)
+	}
+}
+
+func TestImportProject_ConflictRollsBack(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	proj := setupTestProject(t, store)
+	existing := setupTestIssue(t, store, proj, "Existing")
+
+	archive := &types.ProjectArchive{
+		Project: &types.Project{ID: "other-abc123", Name: "Other", Prefix: "other"},
+		Issues: []*types.Issue{
+			{ID: "other.aaaaaa", Title: "New", Status: types.StatusOpen, IssueType: types.TypeTask},
+			{ID: existing.ID, Title: "Clash", Status: types.StatusOpen, IssueType: types.TypeTask},
+		},
+	}
+	if err := store.ImportProject(ctx, archive); err == nil {
+		t.Fatal("expected conflict error")
+	}
+
+	if _, err := store.GetProject(ctx, "other-abc123"); err == nil {
+		t.Error("project from failed import should not exist")
+	}
+	if _, err := store.GetIssue(ctx, "other.aaaaaa"); err == nil {
+		t.Error("issue from failed import should not exist")
+	}
+}
@@ -27,7 +27,6 @@ func (s *Store) AddComment(ctx context.Context, issueID, author, text string) (*
 	}
 
 	s.recordEvent(ctx, issueID, types.EventCommented, author, nil, &text)
-	s.rebuildFTSForIssue(ctx, issueID)
 
 	return &types.Comment{
 		ID:        result.ID,
@@ -80,7 +79,7 @@ func (s *Store) UpdateComment(ctx context.Context, commentID int64, text string)
 	}
 
 	if comment != nil {
-		s.rebuildFTSForIssue(ctx, comment.IssueID)
+		s.publishCommentChange(ctx, types.StreamCommentUpdated, comment)
 	}
 
 	return nil
@@ -97,12 +96,25 @@ func (s *Store) DeleteComment(ctx context.Context, commentID int64) error {
 	}
 
 	if comment != nil {
-		s.rebuildFTSForIssue(ctx, comment.IssueID)
+		s.publishCommentChange(ctx, types.StreamCommentDeleted, comment)
 	}
 
 	return nil
 }
 
+// publishCommentChange notifies subscribers that an existing comment changed.
+func (s *Store) publishCommentChange(ctx context.Context, evType types.StreamEventType, comment *db.Comment) {
+	if s.publisher == nil {
+		return
+	}
+	s.publish(types.StreamEvent{
+		Type:      evType,
+		ProjectID: s.issueProjectID(ctx, comment.IssueID),
+		IssueID:   comment.IssueID,
+		Data:      map[string]any{"comment_id": comment.ID},
+	})
+}
+
 // GetEvents returns the event history for an issue, ordered by creation time.
 // Defaults to a limit of 50 events if limit is zero or negative.
 func (s *Store) GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error) {
@@ -138,6 +150,57 @@ func (s *Store) GetEvents(ctx context.Context, issueID string, limit int) ([]*ty
 	return events, nil
 }
 
+// GetRecentEvents returns the latest events on a project's issues, newest
+// first. Defaults to a limit of 50 events if limit is zero or negative.
+func (s *Store) GetRecentEvents(ctx context.Context, projectID string, limit int) ([]*types.Event, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT e.id, e.issue_id, e.event_type, e.actor, e.old_value, e.new_value, e.comment, e.created_at
+		FROM events e JOIN issues i ON i.id = e.issue_id
+		WHERE i.project_id = ?
+		ORDER BY e.created_at DESC, e.id DESC
+		LIMIT ?
+	`, projectID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get recent events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*types.Event{}
+	for rows.Next() {
+		var row db.Event
+		err := rows.Scan(&row.ID, &row.IssueID, &row.EventType, &row.Actor,
+			&row.OldValue, &row.NewValue, &row.Comment, &row.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("scan event: %w", err)
+		}
+		events = append(events, &types.Event{
+			ID:        row.ID,
+			IssueID:   row.IssueID,
+			EventType: types.EventType(row.EventType),
+			Actor:     row.Actor,
+			OldValue:  nullStringToPtr(row.OldValue),
+			NewValue:  nullStringToPtr(row.NewValue),
+			Comment:   nullStringToPtr(row.Comment),
+			CreatedAt: row.CreatedAt,
+		})
+	}
+	return events, rows.Err()
+}
+
+// AddEvent records an event on an existing issue in the audit trail and
+// publishes it like any other change.
+func (s *Store) AddEvent(ctx context.Context, event *types.Event) error {
+	if _, err := s.GetIssue(ctx, event.IssueID); err != nil {
+		return err
+	}
+	s.recordEvent(ctx, event.IssueID, event.EventType, event.Actor, event.OldValue, event.NewValue)
+	return nil
+}
+
 // nullStringToPtr converts a sql.NullString to a *string pointer.
 // Returns nil if the NullString is not valid.
 func nullStringToPtr(ns sql.NullString) *string {
@@ -86,84 +86,8 @@ func (q *Queries) DeleteDependenciesByIssue(ctx context.Context, arg DeleteDepen
 	return err
 }
 
-const getBlockedIssuesInProject = `-- name: GetBlockedIssuesInProject :many
-SELECT i.id, i.project_id, i.title, i.description,
-       i.status, i.priority, i.issue_type, i.external_ref,
-       i.rank, i.created_at, i.updated_at, i.closed_at, i.close_reason,
-       COUNT(blocker.id) as blocked_by_count
-FROM issues i
-JOIN dependencies d ON d.issue_id = i.id AND d.type = 'blocks'
-JOIN issues blocker ON d.depends_on_id = blocker.id AND blocker.status != 'closed'
-WHERE i.project_id = ?
-  AND i.status != 'closed'
-GROUP BY i.id
-ORDER BY i.priority ASC
-LIMIT ?
-`
-
-type GetBlockedIssuesInProjectParams struct {
-	ProjectID string `json:"project_id"`
-	Limit     int64  `json:"limit"`
-}
-
-type GetBlockedIssuesInProjectRow struct {
-	ID             string         `json:"id"`
-	ProjectID      string         `json:"project_id"`
-	Title          string         `json:"title"`
-	Description    sql.NullString `json:"description"`
-	Status         string         `json:"status"`
-	Priority       int64          `json:"priority"`
-	IssueType      string         `json:"issue_type"`
-	ExternalRef    sql.NullString `json:"external_ref"`
-	Rank           int64          `json:"rank"`
-	CreatedAt      time.Time      `json:"created_at"`
-	UpdatedAt      time.Time      `json:"updated_at"`
-	ClosedAt       sql.NullTime   `json:"closed_at"`
-	CloseReason    sql.NullString `json:"close_reason"`
-	BlockedByCount int64          `json:"blocked_by_count"`
-}
-
-// Note: Only 'blocks' dependencies are blocking; parent-child is organizational only.
-func (q *Queries) GetBlockedIssuesInProject(ctx context.Context, arg GetBlockedIssuesInProjectParams) ([]*GetBlockedIssuesInProjectRow, error) {
-	rows, err := q.db.QueryContext(ctx, getBlockedIssuesInProject, arg.ProjectID, arg.Limit)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []*GetBlockedIssuesInProjectRow{}
-	for rows.Next() {
-		var i GetBlockedIssuesInProjectRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.ProjectID,
-			&i.Title,
-			&i.Description,
-			&i.Status,
-			&i.Priority,
-			&i.IssueType,
-			&i.ExternalRef,
-			&i.Rank,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.ClosedAt,
-			&i.CloseReason,
-			&i.BlockedByCount,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, &i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
 const getBlockingIssues = `-- name: GetBlockingIssues :many
-SELECT i.id, i.project_id, i.title, i.description, i.status, i.priority, i.issue_type, i.ai_session_id, i.external_ref, i.rank, i.created_at, i.updated_at, i.closed_at, i.close_reason FROM issues i
+SELECT i.id, i.project_id, i.title, i.description, i.status, i.priority, i.issue_type, i.ai_session_id, i.external_ref, i.rank, i.created_at, i.updated_at, i.closed_at, i.close_reason, i.version FROM issues i
 JOIN dependencies d ON i.id = d.depends_on_id
 WHERE d.issue_id = ?
   AND d.type = 'blocks'
@@ -196,6 +120,7 @@ func (q *Queries) GetBlockingIssues(ctx context.Context, issueID string) ([]*Iss
 			&i.UpdatedAt,
 			&i.ClosedAt,
 			&i.CloseReason,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -211,7 +136,7 @@ func (q *Queries) GetBlockingIssues(ctx context.Context, issueID string) ([]*Iss
 }
 
 const getDependencies = `-- name: GetDependencies :many
-SELECT i.id, i.project_id, i.title, i.description, i.status, i.priority, i.issue_type, i.ai_session_id, i.external_ref, i.rank, i.created_at, i.updated_at, i.closed_at, i.close_reason FROM issues i
+SELECT i.id, i.project_id, i.title, i.description, i.status, i.priority, i.issue_type, i.ai_session_id, i.external_ref, i.rank, i.created_at, i.updated_at, i.closed_at, i.close_reason, i.version FROM issues i
 JOIN dependencies d ON i.id = d.depends_on_id
 WHERE d.issue_id = ?
 ORDER BY i.priority ASC
@@ -241,6 +166,7 @@ func (q *Queries) GetDependencies(ctx context.Context, issueID string) ([]*Issue
 			&i.UpdatedAt,
 			&i.ClosedAt,
 			&i.CloseReason,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -322,7 +248,7 @@ func (q *Queries) GetDependentRecords(ctx context.Context, dependsOnID string) (
 }
 
 const getDependents = `-- name: GetDependents :many
-SELECT i.id, i.project_id, i.title, i.description, i.status, i.priority, i.issue_type, i.ai_session_id, i.external_ref, i.rank, i.created_at, i.updated_at, i.closed_at, i.close_reason FROM issues i
+SELECT i.id, i.project_id, i.title, i.description, i.status, i.priority, i.issue_type, i.ai_session_id, i.external_ref, i.rank, i.created_at, i.updated_at, i.closed_at, i.close_reason, i.version FROM issues i
 JOIN dependencies d ON i.id = d.issue_id
 WHERE d.depends_on_id = ?
 ORDER BY i.priority ASC
@@ -352,6 +278,7 @@ func (q *Queries) GetDependents(ctx context.Context, dependsOnID string) ([]*Iss
 			&i.UpdatedAt,
 			&i.ClosedAt,
 			&i.CloseReason,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -367,7 +294,7 @@ func (q *Queries) GetDependents(ctx context.Context, dependsOnID string) ([]*Iss
 }
 
 const getOpenChildIssues = `-- name: GetOpenChildIssues :many
-SELECT i.id, i.project_id, i.title, i.description, i.status, i.priority, i.issue_type, i.ai_session_id, i.external_ref, i.rank, i.created_at, i.updated_at, i.closed_at, i.close_reason FROM issues i
+SELECT i.id, i.project_id, i.title, i.description, i.status, i.priority, i.issue_type, i.ai_session_id, i.external_ref, i.rank, i.created_at, i.updated_at, i.closed_at, i.close_reason, i.version FROM issues i
 JOIN dependencies d ON d.issue_id = i.id
 WHERE d.depends_on_id = ?
   AND d.type = 'parent-child'
@@ -400,6 +327,7 @@ func (q *Queries) GetOpenChildIssues(ctx context.Context, dependsOnID string) ([
 			&i.UpdatedAt,
 			&i.ClosedAt,
 			&i.CloseReason,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -62,7 +62,7 @@ func (q *Queries) DeleteEventsByIssue(ctx context.Context, issueID string) error
 const getEvents = `-- name: GetEvents :many
 SELECT id, issue_id, event_type, actor, old_value, new_value, comment, created_at FROM events
 WHERE issue_id = ?
-ORDER BY created_at DESC
+ORDER BY created_at DESC, id DESC
 LIMIT ?
 `
 
@@ -105,7 +105,7 @@ func (q *Queries) GetEvents(ctx context.Context, arg GetEventsParams) ([]*Event,
 
 const getRecentEvents = `-- name: GetRecentEvents :many
 SELECT id, issue_id, event_type, actor, old_value, new_value, comment, created_at FROM events
-ORDER BY created_at DESC
+ORDER BY created_at DESC, id DESC
 LIMIT ?
 `
 
@@ -17,7 +17,8 @@ UPDATE issues SET
     status = 'closed',
     closed_at = ?,
     close_reason = ?,
-    updated_at = ?
+    updated_at = ?,
+    version = version + 1
 WHERE id = ?
 `
 
@@ -129,7 +130,7 @@ func (q *Queries) DeleteIssue(ctx context.Context, id string) error {
 }
 
 const getIssue = `-- name: GetIssue :one
-SELECT id, project_id, title, description, status, priority, issue_type, ai_session_id, external_ref, rank, created_at, updated_at, closed_at, close_reason FROM issues WHERE id = ?
+SELECT id, project_id, title, description, status, priority, issue_type, ai_session_id, external_ref, rank, created_at, updated_at, closed_at, close_reason, version FROM issues WHERE id = ?
 `
 
 func (q *Queries) GetIssue(ctx context.Context, id string) (*Issue, error) {
@@ -150,12 +151,13 @@ func (q *Queries) GetIssue(ctx context.Context, id string) (*Issue, error) {
 		&i.UpdatedAt,
 		&i.ClosedAt,
 		&i.CloseReason,
+		&i.Version,
 	)
 	return &i, err
 }
 
 const getIssueByExternalRef = `-- name: GetIssueByExternalRef :one
-SELECT id, project_id, title, description, status, priority, issue_type, ai_session_id, external_ref, rank, created_at, updated_at, closed_at, close_reason FROM issues WHERE external_ref = ?
+SELECT id, project_id, title, description, status, priority, issue_type, ai_session_id, external_ref, rank, created_at, updated_at, closed_at, close_reason, version FROM issues WHERE external_ref = ?
 `
 
 func (q *Queries) GetIssueByExternalRef(ctx context.Context, externalRef sql.NullString) (*Issue, error) {
@@ -176,12 +178,13 @@ func (q *Queries) GetIssueByExternalRef(ctx context.Context, externalRef sql.Nul
 		&i.UpdatedAt,
 		&i.ClosedAt,
 		&i.CloseReason,
+		&i.Version,
 	)
 	return &i, err
 }
 
 const getOpenNonBlockedIssues = `-- name: GetOpenNonBlockedIssues :many
-SELECT i.id, i.project_id, i.title, i.description, i.status, i.priority, i.issue_type, i.ai_session_id, i.external_ref, i.rank, i.created_at, i.updated_at, i.closed_at, i.close_reason FROM issues i
+SELECT i.id, i.project_id, i.title, i.description, i.status, i.priority, i.issue_type, i.ai_session_id, i.external_ref, i.rank, i.created_at, i.updated_at, i.closed_at, i.close_reason, i.version FROM issues i
 LEFT JOIN dependencies d ON d.issue_id = i.id AND d.type = 'blocks'
 LEFT JOIN issues blocker ON d.depends_on_id = blocker.id AND blocker.status != 'closed'
 WHERE i.project_id = ?
@@ -221,191 +224,7 @@ func (q *Queries) GetOpenNonBlockedIssues(ctx context.Context, arg GetOpenNonBlo
 			&i.UpdatedAt,
 			&i.ClosedAt,
 			&i.CloseReason,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, &i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const getReadyIssuesHybrid = `-- name: GetReadyIssuesHybrid :many
-SELECT i.id, i.project_id, i.title, i.description, i.status, i.priority, i.issue_type, i.ai_session_id, i.external_ref, i.rank, i.created_at, i.updated_at, i.closed_at, i.close_reason FROM issues i
-LEFT JOIN dependencies d ON d.issue_id = i.id AND d.type = 'blocks'
-LEFT JOIN issues blocker ON d.depends_on_id = blocker.id AND blocker.status != 'closed'
-WHERE i.project_id = ?
-  AND i.status IN ('open', 'in_progress')
-GROUP BY i.id
-HAVING COUNT(blocker.id) = 0
-ORDER BY
-  CASE WHEN i.updated_at >= datetime('now', '-48 hours') THEN 0 ELSE 1 END ASC,
-  CASE WHEN i.updated_at >= datetime('now', '-48 hours')
-       THEN i.priority
-       ELSE 999 END ASC,
-  CASE WHEN i.updated_at >= datetime('now', '-48 hours')
-       THEN CASE WHEN i.rank = 0 THEN 999999 ELSE i.rank END
-       ELSE 999999 END ASC,
-  CASE WHEN i.updated_at >= datetime('now', '-48 hours')
-       THEN datetime('9999-12-31')
-       ELSE i.created_at END ASC
-LIMIT ?
-`
-
-type GetReadyIssuesHybridParams struct {
-	ProjectID string `json:"project_id"`
-	Limit     int64  `json:"limit"`
-}
-
-// Hybrid sort: recent issues (<48h) by priority/rank, older issues by age.
-// Uses CASE to create two sorting groups, then appropriate sub-ordering within each.
-// Note: Only 'blocks' dependencies are blocking; parent-child is organizational only.
-func (q *Queries) GetReadyIssuesHybrid(ctx context.Context, arg GetReadyIssuesHybridParams) ([]*Issue, error) {
-	rows, err := q.db.QueryContext(ctx, getReadyIssuesHybrid, arg.ProjectID, arg.Limit)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []*Issue{}
-	for rows.Next() {
-		var i Issue
-		if err := rows.Scan(
-			&i.ID,
-			&i.ProjectID,
-			&i.Title,
-			&i.Description,
-			&i.Status,
-			&i.Priority,
-			&i.IssueType,
-			&i.AiSessionID,
-			&i.ExternalRef,
-			&i.Rank,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.ClosedAt,
-			&i.CloseReason,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, &i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const getReadyIssuesOldest = `-- name: GetReadyIssuesOldest :many
-SELECT i.id, i.project_id, i.title, i.description, i.status, i.priority, i.issue_type, i.ai_session_id, i.external_ref, i.rank, i.created_at, i.updated_at, i.closed_at, i.close_reason FROM issues i
-LEFT JOIN dependencies d ON d.issue_id = i.id AND d.type = 'blocks'
-LEFT JOIN issues blocker ON d.depends_on_id = blocker.id AND blocker.status != 'closed'
-WHERE i.project_id = ?
-  AND i.status IN ('open', 'in_progress')
-GROUP BY i.id
-HAVING COUNT(blocker.id) = 0
-ORDER BY i.created_at ASC
-LIMIT ?
-`
-
-type GetReadyIssuesOldestParams struct {
-	ProjectID string `json:"project_id"`
-	Limit     int64  `json:"limit"`
-}
-
-// Oldest-first sort: for backlog clearing.
-// Note: Only 'blocks' dependencies are blocking; parent-child is organizational only.
-func (q *Queries) GetReadyIssuesOldest(ctx context.Context, arg GetReadyIssuesOldestParams) ([]*Issue, error) {
-	rows, err := q.db.QueryContext(ctx, getReadyIssuesOldest, arg.ProjectID, arg.Limit)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []*Issue{}
-	for rows.Next() {
-		var i Issue
-		if err := rows.Scan(
-			&i.ID,
-			&i.ProjectID,
-			&i.Title,
-			&i.Description,
-			&i.Status,
-			&i.Priority,
-			&i.IssueType,
-			&i.AiSessionID,
-			&i.ExternalRef,
-			&i.Rank,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.ClosedAt,
-			&i.CloseReason,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, &i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const getReadyIssuesPriority = `-- name: GetReadyIssuesPriority :many
-SELECT i.id, i.project_id, i.title, i.description, i.status, i.priority, i.issue_type, i.ai_session_id, i.external_ref, i.rank, i.created_at, i.updated_at, i.closed_at, i.close_reason FROM issues i
-LEFT JOIN dependencies d ON d.issue_id = i.id AND d.type = 'blocks'
-LEFT JOIN issues blocker ON d.depends_on_id = blocker.id AND blocker.status != 'closed'
-WHERE i.project_id = ?
-  AND i.status IN ('open', 'in_progress')
-GROUP BY i.id
-HAVING COUNT(blocker.id) = 0
-ORDER BY
-  i.priority ASC,
-  CASE WHEN i.rank = 0 THEN 999999 ELSE i.rank END ASC,
-  i.created_at ASC
-LIMIT ?
-`
-
-type GetReadyIssuesPriorityParams struct {
-	ProjectID string `json:"project_id"`
-	Limit     int64  `json:"limit"`
-}
-
-// Priority-first sort: priority -> rank -> created_at.
-// Note: Only 'blocks' dependencies are blocking; parent-child is organizational only.
-func (q *Queries) GetReadyIssuesPriority(ctx context.Context, arg GetReadyIssuesPriorityParams) ([]*Issue, error) {
-	rows, err := q.db.QueryContext(ctx, getReadyIssuesPriority, arg.ProjectID, arg.Limit)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []*Issue{}
-	for rows.Next() {
-		var i Issue
-		if err := rows.Scan(
-			&i.ID,
-			&i.ProjectID,
-			&i.Title,
-			&i.Description,
-			&i.Status,
-			&i.Priority,
-			&i.IssueType,
-			&i.AiSessionID,
-			&i.ExternalRef,
-			&i.Rank,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.ClosedAt,
-			&i.CloseReason,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -523,7 +342,8 @@ UPDATE issues SET
     status = 'open',
     closed_at = NULL,
     close_reason = NULL,
-    updated_at = ?
+    updated_at = ?,
+    version = version + 1
 WHERE id = ?
 `
 
@@ -538,7 +358,7 @@ func (q *Queries) ReopenIssue(ctx context.Context, arg ReopenIssueParams) error
 }
 
 const searchIssues = `-- name: SearchIssues :many
-SELECT id, project_id, title, description, status, priority, issue_type, ai_session_id, external_ref, rank, created_at, updated_at, closed_at, close_reason FROM issues
+SELECT id, project_id, title, description, status, priority, issue_type, ai_session_id, external_ref, rank, created_at, updated_at, closed_at, close_reason, version FROM issues
 WHERE project_id = ?
   AND (title LIKE ? OR description LIKE ?)
 ORDER BY priority ASC, updated_at DESC
@@ -583,6 +403,7 @@ func (q *Queries) SearchIssues(ctx context.Context, arg SearchIssuesParams) ([]*
 			&i.UpdatedAt,
 			&i.ClosedAt,
 			&i.CloseReason,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
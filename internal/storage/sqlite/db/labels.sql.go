@@ -92,7 +92,7 @@ func (q *Queries) GetIssueLabels(ctx context.Context, issueID string) ([]string,
 }
 
 const getIssuesByLabel = `-- name: GetIssuesByLabel :many
-SELECT i.id, i.project_id, i.title, i.description, i.status, i.priority, i.issue_type, i.ai_session_id, i.external_ref, i.rank, i.created_at, i.updated_at, i.closed_at, i.close_reason FROM issues i
+SELECT i.id, i.project_id, i.title, i.description, i.status, i.priority, i.issue_type, i.ai_session_id, i.external_ref, i.rank, i.created_at, i.updated_at, i.closed_at, i.close_reason, i.version FROM issues i
 JOIN issue_labels il ON i.id = il.issue_id
 WHERE il.label = ?
 ORDER BY i.priority ASC, i.updated_at DESC
@@ -122,6 +122,7 @@ func (q *Queries) GetIssuesByLabel(ctx context.Context, label string) ([]*Issue,
 			&i.UpdatedAt,
 			&i.ClosedAt,
 			&i.CloseReason,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -97,6 +97,7 @@ type Issue struct {
 	UpdatedAt   time.Time      `json:"updated_at"`
 	ClosedAt    sql.NullTime   `json:"closed_at"`
 	CloseReason sql.NullString `json:"close_reason"`
+	Version     int64          `json:"version"`
 }
 
 type IssueLabel struct {
@@ -5,12 +5,12 @@ VALUES (?, ?, ?, ?, ?, ?, ?);
 -- name: GetEvents :many
 SELECT * FROM events
 WHERE issue_id = ?
-ORDER BY created_at DESC
+ORDER BY created_at DESC, id DESC
 LIMIT ?;
 
 -- name: GetRecentEvents :many
 SELECT * FROM events
-ORDER BY created_at DESC
+ORDER BY created_at DESC, id DESC
 LIMIT ?;
 
 -- name: CountEvents :one
@@ -61,7 +61,8 @@ UPDATE issues SET
     status = 'closed',
     closed_at = ?,
     close_reason = ?,
-    updated_at = ?
+    updated_at = ?,
+    version = version + 1
 WHERE id = ?;
 
 -- name: ReopenIssue :exec
@@ -69,7 +70,8 @@ UPDATE issues SET
     status = 'open',
     closed_at = NULL,
     close_reason = NULL,
-    updated_at = ?
+    updated_at = ?,
+    version = version + 1
 WHERE id = ?;
 
 -- name: DeleteIssue :exec
@@ -95,58 +97,5 @@ HAVING COUNT(blocker.id) = 0
 ORDER BY i.priority ASC, i.updated_at DESC
 LIMIT ?;
 
--- name: GetReadyIssuesHybrid :many
--- Hybrid sort: recent issues (<48h) by priority/rank, older issues by age.
--- Uses CASE to create two sorting groups, then appropriate sub-ordering within each.
--- Note: Only 'blocks' dependencies are blocking; parent-child is organizational only.
-SELECT i.* FROM issues i
-LEFT JOIN dependencies d ON d.issue_id = i.id AND d.type = 'blocks'
-LEFT JOIN issues blocker ON d.depends_on_id = blocker.id AND blocker.status != 'closed'
-WHERE i.project_id = ?
-  AND i.status IN ('open', 'in_progress')
-GROUP BY i.id
-HAVING COUNT(blocker.id) = 0
-ORDER BY
-  CASE WHEN i.updated_at >= datetime('now', '-48 hours') THEN 0 ELSE 1 END ASC,
-  CASE WHEN i.updated_at >= datetime('now', '-48 hours')
-       THEN i.priority
-       ELSE 999 END ASC,
-  CASE WHEN i.updated_at >= datetime('now', '-48 hours')
-       THEN CASE WHEN i.rank = 0 THEN 999999 ELSE i.rank END
-       ELSE 999999 END ASC,
-  CASE WHEN i.updated_at >= datetime('now', '-48 hours')
-       THEN datetime('9999-12-31')
-       ELSE i.created_at END ASC
-LIMIT ?;
-
--- name: GetReadyIssuesPriority :many
--- Priority-first sort: priority -> rank -> created_at.
--- Note: Only 'blocks' dependencies are blocking; parent-child is organizational only.
-SELECT i.* FROM issues i
-LEFT JOIN dependencies d ON d.issue_id = i.id AND d.type = 'blocks'
-LEFT JOIN issues blocker ON d.depends_on_id = blocker.id AND blocker.status != 'closed'
-WHERE i.project_id = ?
-  AND i.status IN ('open', 'in_progress')
-GROUP BY i.id
-HAVING COUNT(blocker.id) = 0
-ORDER BY
-  i.priority ASC,
-  CASE WHEN i.rank = 0 THEN 999999 ELSE i.rank END ASC,
-  i.created_at ASC
-LIMIT ?;
-
--- name: GetReadyIssuesOldest :many
--- Oldest-first sort: for backlog clearing.
--- Note: Only 'blocks' dependencies are blocking; parent-child is organizational only.
-SELECT i.* FROM issues i
-LEFT JOIN dependencies d ON d.issue_id = i.id AND d.type = 'blocks'
-LEFT JOIN issues blocker ON d.depends_on_id = blocker.id AND blocker.status != 'closed'
-WHERE i.project_id = ?
-  AND i.status IN ('open', 'in_progress')
-GROUP BY i.id
-HAVING COUNT(blocker.id) = 0
-ORDER BY i.created_at ASC
-LIMIT ?;
-
 -- name: UpdateIssueRank :exec
 UPDATE issues SET rank = ?, updated_at = ? WHERE id = ?;
@@ -46,6 +46,7 @@ CREATE TABLE issues (
     updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
     closed_at TIMESTAMP,
     close_reason TEXT,
+    version INTEGER NOT NULL DEFAULT 1,
     FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
 );
 
@@ -155,7 +156,8 @@ CREATE TABLE plans (
     file_path TEXT NOT NULL,
     status TEXT NOT NULL DEFAULT 'draft',
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    search_text TEXT NOT NULL DEFAULT ''
 );
 
 -- Plan review comments (overall, legacy line-level, or quoted-range anchored)
@@ -206,3 +208,111 @@ CREATE TABLE ai_agents (
 );
 
 CREATE INDEX idx_ai_agents_session ON ai_agents(session_id);
+
+-- Outbound webhook subscriptions (per project; event_types is comma-separated, empty = all)
+CREATE TABLE webhooks (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL DEFAULT '',
+    event_types TEXT NOT NULL DEFAULT '',
+    active INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_webhooks_project ON webhooks(project_id);
+
+-- Webhook delivery queue and log
+CREATE TABLE webhook_deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    webhook_id TEXT NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event_type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_attempt_at TIMESTAMP,
+    response_status INTEGER,
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP
+);
+
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(status, next_attempt_at);
+CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, id DESC);
+
+-- API tokens (only a SHA-256 hash of each token is stored)
+CREATE TABLE api_tokens (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    prefix TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX idx_api_tokens_actor ON api_tokens(actor);
+
+-- Project memberships (a project with no members is open to every caller)
+CREATE TABLE project_members (
+    project_id TEXT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    actor TEXT NOT NULL,
+    role TEXT NOT NULL CHECK (role IN ('viewer', 'contributor', 'maintainer', 'admin')),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (project_id, actor)
+);
+
+CREATE INDEX idx_project_members_actor ON project_members(actor);
+
+-- Server-side issue templates, optionally instantiated on a schedule
+CREATE TABLE issue_templates (
+    project_id TEXT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    content TEXT NOT NULL,
+    recurrence TEXT NOT NULL DEFAULT '',
+    next_run_at TIMESTAMP,
+    last_run_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (project_id, name)
+);
+
+CREATE INDEX idx_issue_templates_due ON issue_templates(next_run_at) WHERE next_run_at IS NOT NULL;
+
+-- Custom issue field values (definitions live in config under field.<key>)
+CREATE TABLE issue_fields (
+    issue_id TEXT NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    PRIMARY KEY (issue_id, key)
+);
+
+CREATE INDEX idx_issue_fields_key_value ON issue_fields(key, value);
+
+-- Former IDs of moved issues, which keep resolving to the issue
+CREATE TABLE issue_aliases (
+    alias TEXT PRIMARY KEY,
+    issue_id TEXT NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_issue_aliases_issue ON issue_aliases(issue_id);
+
+-- Stable keys of full-text index rows (issues rowids may change on VACUUM)
+CREATE TABLE issue_fts_keys (
+    key INTEGER PRIMARY KEY,
+    issue_id TEXT NOT NULL UNIQUE
+);
+
+-- Plans linked to issues; the search_text of linked plans is indexed with
+-- the issue
+CREATE TABLE issue_plans (
+    issue_id TEXT NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    plan_id TEXT NOT NULL REFERENCES plans(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (issue_id, plan_id)
+);
+
+CREATE INDEX idx_issue_plans_plan ON issue_plans(plan_id);
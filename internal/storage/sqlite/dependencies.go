@@ -8,12 +8,14 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/sentiolabs/arc/internal/depgraph"
 	"github.com/sentiolabs/arc/internal/storage/sqlite/db"
 	"github.com/sentiolabs/arc/internal/types"
 )
 
 // AddDependency adds a dependency between two issues.
-// It validates that the issue does not depend on itself and that the dependency type is valid.
+// It validates that the issue does not depend on itself, that the dependency type is valid,
+// and that a blocking dependency would not close a cycle.
 func (s *Store) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error {
 	if dep.IssueID == dep.DependsOnID {
 		return errors.New("issue cannot depend on itself")
@@ -23,11 +25,27 @@ func (s *Store) AddDependency(ctx context.Context, dep *types.Dependency, actor
 		return fmt.Errorf("invalid dependency type: %s", dep.Type)
 	}
 
+	// The store has a single connection, so no other write can land between
+	// the cycle walk and the insert while the transaction holds it.
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+	qtx := s.queries.WithTx(tx)
+
+	getDependencies := func(ctx context.Context, id string) ([]*types.Dependency, error) {
+		return dependencyRecords(ctx, qtx, id)
+	}
+	if err := depgraph.CheckCycle(dep, depgraph.Blockers(ctx, getDependencies)); err != nil {
+		return err
+	}
+
 	now := time.Now()
 	dep.CreatedAt = now
 	dep.CreatedBy = actor
 
-	err := s.queries.AddDependency(ctx, db.AddDependencyParams{
+	err = qtx.AddDependency(ctx, db.AddDependencyParams{
 		IssueID:     dep.IssueID,
 		DependsOnID: dep.DependsOnID,
 		Type:        string(dep.Type),
@@ -37,6 +55,9 @@ func (s *Store) AddDependency(ctx context.Context, dep *types.Dependency, actor
 	if err != nil {
 		return fmt.Errorf("add dependency: %w", err)
 	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit dependency: %w", err)
+	}
 
 	// Record event
 	newVal := fmt.Sprintf("%s depends on %s (%s)", dep.IssueID, dep.DependsOnID, dep.Type)
@@ -45,8 +66,18 @@ func (s *Store) AddDependency(ctx context.Context, dep *types.Dependency, actor
 	return nil
 }
 
-// RemoveDependency removes a dependency between two issues.
+// RemoveDependency removes a dependency between two issues. The event
+// records the type of the removed dependency so it can be restored.
 func (s *Store) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error {
+	oldVal := fmt.Sprintf("%s no longer depends on %s", issueID, dependsOnID)
+	if deps, err := s.GetDependencies(ctx, issueID); err == nil {
+		for _, d := range deps {
+			if d.DependsOnID == dependsOnID {
+				oldVal += fmt.Sprintf(" (%s)", d.Type)
+			}
+		}
+	}
+
 	err := s.queries.RemoveDependency(ctx, db.RemoveDependencyParams{
 		IssueID:     issueID,
 		DependsOnID: dependsOnID,
@@ -56,7 +87,6 @@ func (s *Store) RemoveDependency(ctx context.Context, issueID, dependsOnID strin
 	}
 
 	// Record event
-	oldVal := fmt.Sprintf("%s no longer depends on %s", issueID, dependsOnID)
 	s.recordEvent(ctx, issueID, types.EventDependencyRemoved, actor, &oldVal, nil)
 
 	return nil
@@ -64,7 +94,13 @@ func (s *Store) RemoveDependency(ctx context.Context, issueID, dependsOnID strin
 
 // GetDependencies returns the dependencies of an issue.
 func (s *Store) GetDependencies(ctx context.Context, issueID string) ([]*types.Dependency, error) {
-	rows, err := s.queries.GetDependencyRecords(ctx, issueID)
+	return dependencyRecords(ctx, s.queries, issueID)
+}
+
+// dependencyRecords reads the dependencies of an issue through q, which may
+// be bound to a transaction.
+func dependencyRecords(ctx context.Context, q *db.Queries, issueID string) ([]*types.Dependency, error) {
+	rows, err := q.GetDependencyRecords(ctx, issueID)
 	if err != nil {
 		return nil, fmt.Errorf("get dependencies: %w", err)
 	}
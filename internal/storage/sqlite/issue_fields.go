@@ -0,0 +1,144 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/sentiolabs/arc/internal/storage/sqlite/db"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// Custom issue fields are defined per project in the config table, under
+// field.<key> (see types.FieldDef), and their values live in issue_fields.
+// Values are validated and normalized against the definitions on write.
+
+// normalizeFields checks fields against the project's definitions and
+// returns them in canonical form. With allowClear, an empty value is kept
+// as "" to mean "remove this field".
+func (s *Store) normalizeFields(
+	ctx context.Context, projectID string, fields map[string]string, allowClear bool,
+) (map[string]string, error) {
+	config, err := s.GetProjectConfig(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defs := types.FieldDefs(config)
+
+	out := make(map[string]string, len(fields))
+	for key, value := range fields {
+		def, ok := defs[key]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s is not defined for this project", types.ErrInvalidField, key)
+		}
+		if allowClear && strings.TrimSpace(value) == "" {
+			out[key] = ""
+			continue
+		}
+		if out[key], err = def.Normalize(value); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// fieldChange is a custom field value changed by writeIssueFields, in the
+// key=value form used for field_changed events. A nil side means the field
+// was unset.
+type fieldChange struct {
+	oldValue, newValue *string
+}
+
+// writeIssueFields applies normalized field values to an issue through q,
+// removing fields whose value is empty. old holds the issue's current
+// values. It returns the values that actually changed so the caller can
+// record events once the write is committed.
+func writeIssueFields(
+	ctx context.Context, q db.DBTX, issueID string, old, fields map[string]string,
+) ([]fieldChange, error) {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var changes []fieldChange
+	for _, key := range keys {
+		value := fields[key]
+		prev, had := old[key]
+		if prev == value {
+			continue
+		}
+		var err error
+		if value == "" {
+			_, err = q.ExecContext(ctx, `DELETE FROM issue_fields WHERE issue_id = ? AND key = ?`, issueID, key)
+		} else {
+			_, err = q.ExecContext(ctx, `
+				INSERT INTO issue_fields (issue_id, key, value) VALUES (?, ?, ?)
+				ON CONFLICT (issue_id, key) DO UPDATE SET value = excluded.value
+			`, issueID, key, value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("set field %s: %w", key, err)
+		}
+
+		var change fieldChange
+		if had {
+			v := key + "=" + prev
+			change.oldValue = &v
+		}
+		if value != "" {
+			v := key + "=" + value
+			change.newValue = &v
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// recordFieldChanges records a field_changed event for each change.
+func (s *Store) recordFieldChanges(ctx context.Context, issueID string, changes []fieldChange, actor string) {
+	for _, c := range changes {
+		s.recordEvent(ctx, issueID, types.EventFieldChanged, actor, c.oldValue, c.newValue)
+	}
+}
+
+// GetFieldsForIssues fetches custom field values for multiple issues in a
+// single query. Returns a map of issue_id -> key -> value; issues without
+// fields are absent.
+func (s *Store) GetFieldsForIssues(ctx context.Context, issueIDs []string) (map[string]map[string]string, error) {
+	result := make(map[string]map[string]string)
+	if len(issueIDs) == 0 {
+		return result, nil
+	}
+
+	args := make([]any, len(issueIDs))
+	marks := make([]string, len(issueIDs))
+	for i, id := range issueIDs {
+		args[i] = id
+		marks[i] = "?"
+	}
+
+	//nolint:gosec // G202: placeholders are parameterized; IN clause built from integer indices
+	query := `SELECT issue_id, key, value FROM issue_fields WHERE issue_id IN (` +
+		strings.Join(marks, ",") + `)`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("batch get fields: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var issueID, key, value string
+		if err := rows.Scan(&issueID, &key, &value); err != nil {
+			return nil, err
+		}
+		if result[issueID] == nil {
+			result[issueID] = make(map[string]string)
+		}
+		result[issueID][key] = value
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,111 @@
+package sqlite_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func TestIssueFields(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+	proj := setupTestProject(t, store)
+
+	for key, spec := range map[string]string{"component": "enum:ui,api", "estimate": "number"} {
+		if err := store.SetProjectConfig(ctx, proj.ID, types.FieldConfigPrefix+key, spec); err != nil {
+			t.Fatalf("SetProjectConfig: %v", err)
+		}
+	}
+
+	ui := &types.Issue{ProjectID: proj.ID, Title: "Button", Fields: map[string]string{"component": "ui", "estimate": "3.0"}}
+	if err := store.CreateIssue(ctx, ui, "alice"); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	api := &types.Issue{ProjectID: proj.ID, Title: "Endpoint", Fields: map[string]string{"component": "api"}}
+	if err := store.CreateIssue(ctx, api, "alice"); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+
+	bad := &types.Issue{ProjectID: proj.ID, Title: "Bad", Fields: map[string]string{"customer": "acme"}}
+	if err := store.CreateIssue(ctx, bad, "alice"); !errors.Is(err, types.ErrInvalidField) {
+		t.Errorf("undefined field error = %v", err)
+	}
+
+	details, err := store.GetIssueDetails(ctx, ui.ID)
+	if err != nil {
+		t.Fatalf("GetIssueDetails: %v", err)
+	}
+	if details.Fields["estimate"] != "3" || details.Fields["component"] != "ui" {
+		t.Errorf("fields = %v", details.Fields)
+	}
+
+	// Filters are normalized and combined with AND.
+	list, err := store.ListIssues(ctx, types.IssueFilter{
+		ProjectID: proj.ID, Fields: map[string]string{"component": "ui", "estimate": "3"},
+	})
+	if err != nil || len(list) != 1 || list[0].ID != ui.ID {
+		t.Errorf("ListIssues = %v, %v", list, err)
+	}
+	if _, err := store.ListIssues(ctx, types.IssueFilter{
+		ProjectID: proj.ID, Fields: map[string]string{"component": "db"},
+	}); !errors.Is(err, types.ErrInvalidField) {
+		t.Errorf("invalid filter error = %v", err)
+	}
+
+	// An invalid value rejects the whole update.
+	err = store.UpdateIssue(ctx, api.ID, map[string]any{
+		"title": "Renamed", "fields": map[string]string{"estimate": "lots"},
+	}, 0, "bob")
+	if !errors.Is(err, types.ErrInvalidField) {
+		t.Errorf("invalid update error = %v", err)
+	}
+	if got, _ := store.GetIssue(ctx, api.ID); got.Title != "Endpoint" {
+		t.Errorf("title changed to %q by a rejected update", got.Title)
+	}
+
+	err = store.UpdateIssue(ctx, api.ID, map[string]any{
+		"fields": map[string]string{"component": "", "estimate": "5"},
+	}, 0, "bob")
+	if err != nil {
+		t.Fatalf("UpdateIssue: %v", err)
+	}
+	fields, err := store.GetFieldsForIssues(ctx, []string{ui.ID, api.ID})
+	if err != nil {
+		t.Fatalf("GetFieldsForIssues: %v", err)
+	}
+	if len(fields[api.ID]) != 1 || fields[api.ID]["estimate"] != "5" || len(fields[ui.ID]) != 2 {
+		t.Errorf("fields after update = %v", fields)
+	}
+
+	events, err := store.GetEvents(ctx, api.ID, 0)
+	if err != nil {
+		t.Fatalf("GetEvents: %v", err)
+	}
+	var changes []string
+	for _, e := range events {
+		if e.EventType != types.EventFieldChanged {
+			continue
+		}
+		change := ""
+		if e.OldValue != nil {
+			change = *e.OldValue
+		}
+		change += " -> "
+		if e.NewValue != nil {
+			change += *e.NewValue
+		}
+		changes = append(changes, change)
+	}
+	want := map[string]bool{" -> component=api": true, "component=api -> ": true, " -> estimate=5": true}
+	if len(changes) != len(want) {
+		t.Fatalf("field events = %q", changes)
+	}
+	for _, c := range changes {
+		if !want[c] {
+			t.Errorf("unexpected field event %q", c)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func TestIssueTemplateCRUD(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+	proj := setupTestProject(t, store)
+
+	next := time.Now().Add(-time.Minute)
+	tmpl := &types.IssueTemplate{
+		ProjectID: proj.ID, Name: "release", Content: "issue:\n  title: x\n", Recurrence: "@daily", NextRunAt: &next,
+	}
+	if err := store.SaveIssueTemplate(ctx, tmpl); err != nil {
+		t.Fatalf("SaveIssueTemplate: %v", err)
+	}
+	if err := store.SaveIssueTemplate(ctx, &types.IssueTemplate{
+		ProjectID: proj.ID, Name: "adhoc", Content: "issue:\n  title: y\n",
+	}); err != nil {
+		t.Fatalf("SaveIssueTemplate adhoc: %v", err)
+	}
+
+	due, err := store.ListDueIssueTemplates(ctx, time.Now())
+	if err != nil || len(due) != 1 || due[0].Name != "release" {
+		t.Fatalf("ListDueIssueTemplates = %+v, %v", due, err)
+	}
+
+	ranAt := time.Now()
+	later := ranAt.Add(24 * time.Hour)
+	if err := store.RecordIssueTemplateRun(ctx, proj.ID, "release", ranAt, &later); err != nil {
+		t.Fatalf("RecordIssueTemplateRun: %v", err)
+	}
+	if due, _ := store.ListDueIssueTemplates(ctx, time.Now()); len(due) != 0 {
+		t.Errorf("still due after run: %+v", due)
+	}
+
+	// Replacing a template keeps its creation time and last run.
+	created := tmpl.CreatedAt
+	tmpl.Content = "issue:\n  title: z\n"
+	if err := store.SaveIssueTemplate(ctx, tmpl); err != nil {
+		t.Fatalf("SaveIssueTemplate replace: %v", err)
+	}
+	got, err := store.GetIssueTemplate(ctx, proj.ID, "release")
+	if err != nil {
+		t.Fatalf("GetIssueTemplate: %v", err)
+	}
+	if got.Content != tmpl.Content || !got.CreatedAt.Equal(created) || got.LastRunAt == nil {
+		t.Errorf("GetIssueTemplate = %+v", got)
+	}
+
+	list, err := store.ListIssueTemplates(ctx, proj.ID)
+	if err != nil || len(list) != 2 || list[0].Name != "adhoc" {
+		t.Errorf("ListIssueTemplates = %+v, %v", list, err)
+	}
+
+	if err := store.DeleteIssueTemplate(ctx, proj.ID, "release"); err != nil {
+		t.Fatalf("DeleteIssueTemplate: %v", err)
+	}
+	if err := store.DeleteIssueTemplate(ctx, proj.ID, "release"); err == nil {
+		t.Error("expected error deleting a missing template")
+	}
+}
@@ -5,39 +5,16 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/sentiolabs/arc/internal/history"
 	"github.com/sentiolabs/arc/internal/project"
 	"github.com/sentiolabs/arc/internal/storage/sqlite/db"
 	"github.com/sentiolabs/arc/internal/types"
 )
 
-// IsHierarchicalID checks if an issue ID is hierarchical (has a parent).
-// Hierarchical IDs have the format {parentID}.{N} where N is a numeric child suffix.
-// Returns true and the parent ID if hierarchical, false and empty string otherwise.
-func IsHierarchicalID(id string) (isHierarchical bool, parentID string) {
-	lastDot := strings.LastIndex(id, ".")
-	if lastDot == -1 {
-		return false, ""
-	}
-
-	// Check if the suffix after the last dot is purely numeric
-	suffix := id[lastDot+1:]
-	if len(suffix) == 0 {
-		return false, ""
-	}
-
-	for _, c := range suffix {
-		if c < '0' || c > '9' {
-			return false, ""
-		}
-	}
-
-	// It's hierarchical - parent is everything before the last dot
-	return true, id[:lastDot]
-}
-
 // getNextChildNumber atomically increments and returns the next child counter for a parent.
 func (s *Store) getNextChildNumber(ctx context.Context, parentID string) (int, error) {
 	var nextChild int
@@ -90,6 +67,14 @@ func (s *Store) CreateIssue(ctx context.Context, issue *types.Issue, actor strin
 		return fmt.Errorf("get project for ID generation: %w", err)
 	}
 
+	var fields map[string]string
+	if len(issue.Fields) > 0 {
+		if fields, err = s.normalizeFields(ctx, issue.ProjectID, issue.Fields, false); err != nil {
+			return err
+		}
+		issue.Fields = fields
+	}
+
 	// Generate ID - use hierarchical ID if parent is specified
 	if issue.ID == "" {
 		if issue.ParentID != "" {
@@ -107,6 +92,7 @@ func (s *Store) CreateIssue(ctx context.Context, issue *types.Issue, actor strin
 	now := time.Now()
 	issue.CreatedAt = now
 	issue.UpdatedAt = now
+	issue.Version = 1
 
 	err = s.queries.CreateIssue(ctx, db.CreateIssueParams{
 		ID:          issue.ID,
@@ -143,13 +129,31 @@ func (s *Store) CreateIssue(ctx context.Context, issue *types.Issue, actor strin
 		_ = s.AddDependency(ctx, dep, actor)
 	}
 
-	s.rebuildFTSForIssue(ctx, issue.ID)
+	changes, err := writeIssueFields(ctx, s.db, issue.ID, nil, fields)
+	if err != nil {
+		return err
+	}
+	s.recordFieldChanges(ctx, issue.ID, changes, actor)
 
 	return nil
 }
 
 // GetIssue retrieves an issue by ID.
+// An ID the issue had before MoveIssue resolves to the issue.
 func (s *Store) GetIssue(ctx context.Context, id string) (*types.Issue, error) {
+	issue, err := s.getIssue(ctx, id)
+	if err == nil {
+		return issue, nil
+	}
+	current, aliasErr := s.issueAlias(ctx, id)
+	if aliasErr != nil || current == id {
+		return nil, err
+	}
+	return s.getIssue(ctx, current)
+}
+
+// getIssue retrieves the issue stored under id, without following aliases.
+func (s *Store) getIssue(ctx context.Context, id string) (*types.Issue, error) {
 	row, err := s.queries.GetIssue(ctx, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -174,18 +178,10 @@ func (s *Store) GetIssueByExternalRef(ctx context.Context, externalRef string) (
 	return dbIssueToType(row), nil
 }
 
-// allStatuses contains every valid status string for use as a default filter.
-var allStatuses = []string{"open", "in_progress", "blocked", "deferred", "closed"}
-
-// allIssueTypes contains every valid issue type string for use as a default filter.
-var allIssueTypes = []string{"bug", "feature", "task", "epic", "chore"}
-
-// allPriorities contains every valid priority value for use as a default filter.
-var allPriorities = []int64{0, 1, 2, 3, 4}
-
 // ListIssues returns issues matching the filter.
 // All filter fields are composed with AND semantics so multiple filters
-// (e.g. --parent + --status) work together via a dynamic SQL query.
+// (e.g. --parent + --status) work together via a dynamic SQL query, and
+// the issue must match at least one of the filter's Or groups, if any.
 // We use dynamic SQL because sqlc.slice and sqlc.narg positional placeholders
 // are incompatible when mixed in the same query (positional ?N offsets shift
 // when slice placeholders expand to multiple values).
@@ -200,6 +196,26 @@ func (s *Store) ListIssues(ctx context.Context, filter types.IssueFilter) ([]*ty
 		return s.searchIssuesFTS(ctx, filter.ProjectID, filter.Query, limit, offset)
 	}
 
+	// Normalize custom field filters so "3.0" matches a stored "3"
+	if len(filter.Fields) > 0 {
+		fields, err := s.normalizeFields(ctx, filter.ProjectID, filter.Fields, false)
+		if err != nil {
+			return nil, err
+		}
+		filter.Fields = fields
+	}
+	filter.Or = slices.Clone(filter.Or)
+	for i, group := range filter.Or {
+		if len(group.Fields) == 0 {
+			continue
+		}
+		fields, err := s.normalizeFields(ctx, filter.ProjectID, group.Fields, false)
+		if err != nil {
+			return nil, err
+		}
+		filter.Or[i].Fields = fields
+	}
+
 	query, args := buildListIssuesQuery(filter, limit, offset)
 
 	rows, err := s.db.QueryContext(ctx, query, args...)
@@ -215,7 +231,7 @@ func (s *Store) ListIssues(ctx context.Context, filter types.IssueFilter) ([]*ty
 			&row.ID, &row.ProjectID, &row.Title, &row.Description,
 			&row.Status, &row.Priority, &row.IssueType,
 			&row.AiSessionID, &row.ExternalRef, &row.Rank,
-			&row.CreatedAt, &row.UpdatedAt, &row.ClosedAt, &row.CloseReason,
+			&row.CreatedAt, &row.UpdatedAt, &row.ClosedAt, &row.CloseReason, &row.Version,
 		); err != nil {
 			return nil, fmt.Errorf("scan issue: %w", err)
 		}
@@ -232,166 +248,315 @@ func (s *Store) ListIssues(ctx context.Context, filter types.IssueFilter) ([]*ty
 // All string interpolation uses only positional placeholder references (?N),
 // never user-supplied values directly.
 func buildListIssuesQuery(filter types.IssueFilter, limit, offset int) (string, []any) {
-	statuses := toStringSliceOrDefault(filter.Statuses, allStatuses)
-	issueTypes := toStringSliceOrDefault(filter.IssueTypes, allIssueTypes)
-	priorities := toInt64SliceOrDefault(filter.Priorities, allPriorities)
+	q := &queryArgs{}
+	where := "i.project_id = " + q.arg(filter.ProjectID) + issueConditions(q, filter)
+	if len(filter.Or) > 0 {
+		groups := make([]string, len(filter.Or))
+		for i, group := range filter.Or {
+			groups[i] = "(1 = 1" + issueConditions(q, group) + ")"
+		}
+		where += "\n  AND (" + strings.Join(groups, " OR ") + ")"
+	}
+	page := fmt.Sprintf("LIMIT %s OFFSET %s", q.arg(int64(limit)), q.arg(int64(offset)))
 
-	args := []any{filter.ProjectID}
-	argIdx := 2
+	query := `
+SELECT i.id, i.project_id, i.title, i.description, i.status, i.priority,
+       i.issue_type, i.ai_session_id, i.external_ref, i.rank,
+       i.created_at, i.updated_at, i.closed_at, i.close_reason, i.version
+FROM issues i
+WHERE ` + where + `
+ORDER BY ` + issueOrderBy(filter.Sort) + `
+` + page
 
-	statusPH := appendSlice(&args, &argIdx, statuses)
-	typePH := appendSlice(&args, &argIdx, issueTypes)
-	priorityPH := appendSlice(&args, &argIdx, priorities)
+	return query, q.args
+}
 
-	var sessionClause, parentClause, parentJoin string
+// issueConditions returns the WHERE conditions, each prefixed with AND, for
+// the condition fields of filter.
+func issueConditions(q *queryArgs, filter types.IssueFilter) string {
+	var clauses strings.Builder
+	in := func(column string, vals []any, negate bool) {
+		if len(vals) == 0 {
+			return
+		}
+		placeholders := make([]string, len(vals))
+		for i, v := range vals {
+			placeholders[i] = q.arg(v)
+		}
+		op := "IN"
+		if negate {
+			op = "NOT IN"
+		}
+		fmt.Fprintf(&clauses, "\n  AND %s %s (%s)", column, op, strings.Join(placeholders, ", "))
+	}
+	in("i.status", stringArgs(filter.Statuses), false)
+	in("i.status", stringArgs(filter.ExcludeStatuses), true)
+	in("i.issue_type", stringArgs(filter.IssueTypes), false)
+	in("i.issue_type", stringArgs(filter.ExcludeIssueTypes), true)
+	in("i.priority", intArgs(filter.Priorities), false)
+	in("i.priority", intArgs(filter.ExcludePriorities), true)
 
+	compare := func(expr, op string, v any) {
+		fmt.Fprintf(&clauses, "\n  AND %s %s %s", expr, op, q.arg(v))
+	}
+	if filter.MinPriority != nil {
+		compare("i.priority", ">=", int64(*filter.MinPriority))
+	}
+	if filter.MaxPriority != nil {
+		compare("i.priority", "<=", int64(*filter.MaxPriority))
+	}
+	// Timestamps are stored in the writer's time zone; see unixnano.
+	if filter.CreatedAfter != nil {
+		compare("unixnano(i.created_at)", ">=", filter.CreatedAfter.UnixNano())
+	}
+	if filter.CreatedBefore != nil {
+		compare("unixnano(i.created_at)", "<", filter.CreatedBefore.UnixNano())
+	}
+	if filter.UpdatedAfter != nil {
+		compare("unixnano(i.updated_at)", ">=", filter.UpdatedAfter.UnixNano())
+	}
+	if filter.UpdatedBefore != nil {
+		compare("unixnano(i.updated_at)", "<", filter.UpdatedBefore.UnixNano())
+	}
 	if filter.AISessionID != nil {
-		sessionClause = fmt.Sprintf("AND i.ai_session_id = ?%d", argIdx)
-		args = append(args, *filter.AISessionID)
-		argIdx++
+		compare("i.ai_session_id", "=", *filter.AISessionID)
 	}
 	if filter.ParentID != "" {
-		parentJoin = "JOIN dependencies d ON d.issue_id = i.id AND d.type = 'parent-child'"
-		parentClause = fmt.Sprintf("AND d.depends_on_id = ?%d", argIdx)
-		args = append(args, filter.ParentID)
-		argIdx++
+		fmt.Fprintf(&clauses, `
+  AND EXISTS (SELECT 1 FROM dependencies p
+    WHERE p.issue_id = i.id AND p.type = 'parent-child' AND p.depends_on_id = %s)`, q.arg(filter.ParentID))
 	}
+	for _, label := range filter.Labels {
+		fmt.Fprintf(&clauses,
+			"\n  AND EXISTS (SELECT 1 FROM issue_labels l WHERE l.issue_id = i.id AND l.label = %s)", q.arg(label))
+	}
+	for _, label := range filter.ExcludeLabels {
+		fmt.Fprintf(&clauses,
+			"\n  AND NOT EXISTS (SELECT 1 FROM issue_labels l WHERE l.issue_id = i.id AND l.label = %s)", q.arg(label))
+	}
+	fieldKeys := make([]string, 0, len(filter.Fields))
+	for key := range filter.Fields {
+		fieldKeys = append(fieldKeys, key)
+	}
+	slices.Sort(fieldKeys)
+	for _, key := range fieldKeys {
+		fmt.Fprintf(&clauses,
+			"\n  AND EXISTS (SELECT 1 FROM issue_fields f WHERE f.issue_id = i.id AND f.key = %s AND f.value = %s)",
+			q.arg(key), q.arg(filter.Fields[key]))
+	}
+	for _, text := range filter.Text {
+		pattern := q.arg("%" + text + "%")
+		fmt.Fprintf(&clauses, "\n  AND (i.title LIKE %s OR i.description LIKE %s)", pattern, pattern)
+	}
+	return clauses.String()
+}
 
-	offsetPH := fmt.Sprintf("?%d", argIdx)
-	args = append(args, int64(offset))
-	argIdx++
-	limitPH := fmt.Sprintf("?%d", argIdx)
-	args = append(args, int64(limit))
-
-	query := fmt.Sprintf(`
-SELECT i.id, i.project_id, i.title, i.description, i.status, i.priority,
-       i.issue_type, i.ai_session_id, i.external_ref, i.rank,
-       i.created_at, i.updated_at, i.closed_at, i.close_reason
-FROM issues i
-%s
-WHERE i.project_id = ?1
-  AND i.status IN (%s)
-  AND i.issue_type IN (%s)
-  AND i.priority IN (%s)
-  %s
-  %s
-ORDER BY i.priority ASC, i.updated_at DESC
-LIMIT %s OFFSET %s
-`, parentJoin, statusPH, typePH, priorityPH,
-		sessionClause, parentClause,
-		limitPH, offsetPH)
-
-	return query, args
+// issueSortColumns maps sort fields to the columns they order by.
+var issueSortColumns = map[types.IssueSortField]string{
+	types.SortByPriority: "i.priority",
+	types.SortByCreated:  "i.created_at",
+	types.SortByUpdated:  "i.updated_at",
+	types.SortByRank:     "CASE WHEN i.rank = 0 THEN 999999 ELSE i.rank END",
+	types.SortByTitle:    "i.title COLLATE NOCASE",
+	types.SortByStatus:   "i.status",
+	types.SortByType:     "i.issue_type",
+	types.SortByID:       "i.id",
 }
 
-// toStringSliceOrDefault converts a typed slice to []string, returning defaults if empty.
-func toStringSliceOrDefault[T ~string](vals []T, defaults []string) []string {
-	if len(vals) == 0 {
-		return defaults
+// issueOrderBy returns the ORDER BY list for an issue sort, by default
+// priority and then most recently updated. Explicit sorts end with the
+// issue ID so pages are stable.
+func issueOrderBy(sort []types.IssueSort) string {
+	if len(sort) == 0 {
+		return "i.priority ASC, i.updated_at DESC"
+	}
+	keys := make([]string, 0, len(sort)+1)
+	for _, s := range sort {
+		dir := "ASC"
+		if s.Desc {
+			dir = "DESC"
+		}
+		keys = append(keys, issueSortColumns[s.Field]+" "+dir)
 	}
-	out := make([]string, len(vals))
+	return strings.Join(append(keys, "i.id ASC"), ", ")
+}
+
+// stringArgs converts a typed string slice to query args.
+func stringArgs[T ~string](vals []T) []any {
+	out := make([]any, len(vals))
 	for i, v := range vals {
 		out[i] = string(v)
 	}
 	return out
 }
 
-// toInt64SliceOrDefault converts []int to []int64, returning defaults if empty.
-func toInt64SliceOrDefault(vals []int, defaults []int64) []int64 {
-	if len(vals) == 0 {
-		return defaults
-	}
-	out := make([]int64, len(vals))
+// intArgs converts an int slice to query args.
+func intArgs(vals []int) []any {
+	out := make([]any, len(vals))
 	for i, v := range vals {
 		out[i] = int64(v)
 	}
 	return out
 }
 
-// appendSlice adds slice values to args and returns the SQL placeholder string.
-func appendSlice[T any](args *[]any, argIdx *int, vals []T) string {
-	ph := buildPlaceholders(argIdx, len(vals))
-	for _, v := range vals {
-		*args = append(*args, v)
+// UpdateIssue applies updates to an issue in a single transaction and
+// increments its version. When ifVersion is non-zero and the issue has
+// moved past it, nothing is written and a *types.VersionConflictError is
+// returned. Each changed field is recorded as its own event, with the
+// values before and after, once the transaction has committed.
+// Custom fields are passed under "fields" as a map[string]string; an empty
+// value removes that field.
+//
+//nolint:gocognit,funlen // one case per updatable column
+func (s *Store) UpdateIssue(
+	ctx context.Context, id string, updates map[string]any, ifVersion int64, actor string,
+) error {
+	now := time.Now()
+
+	// Validate everything up front so a bad value can't fail the transaction
+	// halfway through.
+	for field := range updates {
+		if !updatableIssueFields[field] {
+			return fmt.Errorf("unknown field: %s", field)
+		}
+	}
+	var fields, oldFields map[string]string
+	if raw, ok := updates["fields"]; ok {
+		var err error
+		if fields, err = s.normalizeFields(ctx, s.issueProjectID(ctx, id), raw.(map[string]string), true); err != nil {
+			return err
+		}
+		current, err := s.GetFieldsForIssues(ctx, []string{id})
+		if err != nil {
+			return err
+		}
+		oldFields = current[id]
 	}
-	return ph
-}
 
-// buildPlaceholders generates a comma-separated string of SQL placeholders
-// like "?2, ?3, ?4" and advances the argIdx accordingly.
-func buildPlaceholders(argIdx *int, count int) string {
-	placeholders := make([]string, count)
-	for i := range count {
-		placeholders[i] = fmt.Sprintf("?%d", *argIdx)
-		*argIdx++
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
 	}
-	return strings.Join(placeholders, ", ")
-}
+	defer tx.Rollback() //nolint:errcheck
 
-// UpdateIssue updates an issue with the given updates.
-func (s *Store) UpdateIssue(ctx context.Context, id string, updates map[string]any, actor string) error {
-	now := time.Now()
+	found, err := bumpIssueVersion(ctx, tx, id, ifVersion, now)
+	if err != nil || !found {
+		return err
+	}
+
+	qtx := s.queries.WithTx(tx)
+	row, err := qtx.GetIssue(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get issue: %w", err)
+	}
+	old := dbIssueToType(row)
 
+	var changes []fieldChange
 	for field, value := range updates {
-		var err error
 		switch field {
 		case "title":
-			err = s.queries.UpdateIssueTitle(ctx, db.UpdateIssueTitleParams{
+			err = qtx.UpdateIssueTitle(ctx, db.UpdateIssueTitleParams{
 				Title:     value.(string),
 				UpdatedAt: now,
 				ID:        id,
 			})
 		case "description":
-			err = s.queries.UpdateIssueDescription(ctx, db.UpdateIssueDescriptionParams{
+			err = qtx.UpdateIssueDescription(ctx, db.UpdateIssueDescriptionParams{
 				Description: toNullString(value.(string)),
 				UpdatedAt:   now,
 				ID:          id,
 			})
 		case "status":
-			status := value.(string)
-			err = s.queries.UpdateIssueStatus(ctx, db.UpdateIssueStatusParams{
-				Status:    status,
+			err = qtx.UpdateIssueStatus(ctx, db.UpdateIssueStatusParams{
+				Status:    value.(string),
 				UpdatedAt: now,
 				ID:        id,
 			})
-			s.recordEvent(ctx, id, types.EventStatusChanged, actor, nil, &status)
 		case "priority":
-			err = s.queries.UpdateIssuePriority(ctx, db.UpdateIssuePriorityParams{
+			err = qtx.UpdateIssuePriority(ctx, db.UpdateIssuePriorityParams{
 				Priority:  int64(value.(int)),
 				UpdatedAt: now,
 				ID:        id,
 			})
 		case "issue_type":
-			err = s.queries.UpdateIssueType(ctx, db.UpdateIssueTypeParams{
+			err = qtx.UpdateIssueType(ctx, db.UpdateIssueTypeParams{
 				IssueType: value.(string),
 				UpdatedAt: now,
 				ID:        id,
 			})
 		case "ai_session_id":
-			err = s.queries.UpdateIssueAISessionID(ctx, db.UpdateIssueAISessionIDParams{
+			err = qtx.UpdateIssueAISessionID(ctx, db.UpdateIssueAISessionIDParams{
 				AiSessionID: toNullString(value.(string)),
 				UpdatedAt:   now,
 				ID:          id,
 			})
 		case "external_ref":
-			err = s.queries.UpdateIssueExternalRef(ctx, db.UpdateIssueExternalRefParams{
+			err = qtx.UpdateIssueExternalRef(ctx, db.UpdateIssueExternalRefParams{
 				ExternalRef: toNullString(value.(string)),
 				UpdatedAt:   now,
 				ID:          id,
 			})
-		default:
-			return fmt.Errorf("unknown field: %s", field)
+		case "rank":
+			err = qtx.UpdateIssueRank(ctx, db.UpdateIssueRankParams{
+				Rank:      int64(value.(int)),
+				UpdatedAt: now,
+				ID:        id,
+			})
+		case "fields":
+			changes, err = writeIssueFields(ctx, tx, id, oldFields, fields)
 		}
 		if err != nil {
 			return fmt.Errorf("update %s: %w", field, err)
 		}
 	}
 
-	s.recordEvent(ctx, id, types.EventUpdated, actor, nil, nil)
-	s.rebuildFTSForIssue(ctx, id)
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit update: %w", err)
+	}
+
+	if status, ok := updates["status"].(string); ok && status != string(old.Status) {
+		oldStatus := string(old.Status)
+		s.recordEvent(ctx, id, types.EventStatusChanged, actor, &oldStatus, &status)
+	}
+	for _, c := range history.Changes(old, updates) {
+		oldValue, newValue := c.EventValues()
+		s.recordEvent(ctx, id, types.EventUpdated, actor, oldValue, newValue)
+	}
+	s.recordFieldChanges(ctx, id, changes, actor)
 	return nil
 }
 
+// updatableIssueFields lists the keys UpdateIssue accepts.
+var updatableIssueFields = map[string]bool{
+	"title": true, "description": true, "status": true, "priority": true, "issue_type": true,
+	"ai_session_id": true, "external_ref": true, "rank": true, "fields": true,
+}
+
+// bumpIssueVersion increments an issue's version inside tx, first checking
+// it against ifVersion when that is non-zero. found is false when the issue
+// does not exist.
+func bumpIssueVersion(ctx context.Context, tx *sql.Tx, id string, ifVersion int64, now time.Time) (bool, error) {
+	res, err := tx.ExecContext(ctx,
+		`UPDATE issues SET version = version + 1, updated_at = ? WHERE id = ? AND (? = 0 OR version = ?)`,
+		now, id, ifVersion, ifVersion)
+	if err != nil {
+		return false, fmt.Errorf("bump version: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		return true, nil
+	}
+
+	var current int64
+	err = tx.QueryRowContext(ctx, `SELECT version FROM issues WHERE id = ?`, id).Scan(&current)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("get version: %w", err)
+	}
+	return false, &types.VersionConflictError{IssueID: id, Expected: ifVersion, Current: current}
+}
+
 // CloseIssue closes an issue.
 // When cascade is false, it checks for open child issues and returns an
 // *types.OpenChildrenError if any are found. When cascade is true, it
@@ -452,8 +617,15 @@ func (s *Store) cascadeCloseDescendants(ctx context.Context, parentID, rootID, r
 	return nil
 }
 
-// closeIssueSingle closes a single issue without any cascade logic.
+// closeIssueSingle closes a single issue without any cascade logic. The
+// closed event records the status the issue had before.
 func (s *Store) closeIssueSingle(ctx context.Context, id string, reason string, actor string) error {
+	var oldStatus *string
+	if issue, err := s.getIssue(ctx, id); err == nil {
+		status := string(issue.Status)
+		oldStatus = &status
+	}
+
 	now := time.Now()
 	err := s.queries.CloseIssue(ctx, db.CloseIssueParams{
 		ClosedAt:    toNullTime(&now),
@@ -465,12 +637,18 @@ func (s *Store) closeIssueSingle(ctx context.Context, id string, reason string,
 		return fmt.Errorf("close issue: %w", err)
 	}
 
-	s.recordEvent(ctx, id, types.EventClosed, actor, nil, &reason)
+	s.recordEvent(ctx, id, types.EventClosed, actor, oldStatus, &reason)
 	return nil
 }
 
-// ReopenIssue reopens a closed issue.
+// ReopenIssue reopens a closed issue. The reopened event records the close
+// reason it clears.
 func (s *Store) ReopenIssue(ctx context.Context, id string, actor string) error {
+	var oldReason *string
+	if issue, err := s.getIssue(ctx, id); err == nil && issue.CloseReason != "" {
+		oldReason = &issue.CloseReason
+	}
+
 	now := time.Now()
 	err := s.queries.ReopenIssue(ctx, db.ReopenIssueParams{
 		UpdatedAt: now,
@@ -480,14 +658,14 @@ func (s *Store) ReopenIssue(ctx context.Context, id string, actor string) error
 		return fmt.Errorf("reopen issue: %w", err)
 	}
 
-	s.recordEvent(ctx, id, types.EventReopened, actor, nil, nil)
+	s.recordEvent(ctx, id, types.EventReopened, actor, oldReason, nil)
 	return nil
 }
 
 // DeleteIssue deletes an issue.
 func (s *Store) DeleteIssue(ctx context.Context, id string) error {
-	// Delete from FTS index before removing the issue
-	s.deleteFTSForIssue(ctx, id)
+	// Capture the project before the row disappears so the deletion can be scoped
+	projectID := s.issueProjectID(ctx, id)
 
 	// Delete dependencies first
 	err := s.queries.DeleteDependenciesByIssue(ctx, db.DeleteDependenciesByIssueParams{
@@ -516,15 +694,18 @@ func (s *Store) DeleteIssue(ctx context.Context, id string) error {
 		return fmt.Errorf("delete issue: %w", err)
 	}
 
+	s.publish(types.StreamEvent{Type: types.StreamIssueDeleted, ProjectID: projectID, IssueID: id})
 	return nil
 }
 
-// GetIssueDetails retrieves an issue with all its relational data.
+// GetIssueDetails retrieves an issue with all its relational data. Like
+// GetIssue, it resolves an ID the issue had before MoveIssue.
 func (s *Store) GetIssueDetails(ctx context.Context, id string) (*types.IssueDetails, error) {
 	issue, err := s.GetIssue(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	id = issue.ID
 
 	labels, err := s.GetIssueLabels(ctx, id)
 	if err != nil {
@@ -546,6 +727,12 @@ func (s *Store) GetIssueDetails(ctx context.Context, id string) (*types.IssueDet
 		return nil, fmt.Errorf("get comments: %w", err)
 	}
 
+	fields, err := s.GetFieldsForIssues(ctx, []string{id})
+	if err != nil {
+		return nil, fmt.Errorf("get fields: %w", err)
+	}
+	issue.Fields = fields[id]
+
 	return &types.IssueDetails{
 		Issue:        *issue,
 		Labels:       labels,
@@ -572,6 +759,7 @@ func dbIssueToType(row *db.Issue) *types.Issue {
 		UpdatedAt:   row.UpdatedAt,
 		ClosedAt:    fromNullTime(row.ClosedAt),
 		CloseReason: fromNullString(row.CloseReason),
+		Version:     row.Version,
 	}
 }
 
@@ -589,6 +777,27 @@ func (s *Store) recordEvent(ctx context.Context, issueID string, eventType types
 		NewValue:  toNullString(ptrToString(newValue)),
 		CreatedAt: time.Now(),
 	})
+
+	if s.publisher == nil {
+		return
+	}
+	var data map[string]any
+	if oldValue != nil || newValue != nil {
+		data = make(map[string]any, 2) //nolint:mnd // old and new value
+		if oldValue != nil {
+			data["old_value"] = *oldValue
+		}
+		if newValue != nil {
+			data["new_value"] = *newValue
+		}
+	}
+	s.publish(types.StreamEvent{
+		Type:      types.StreamTypeForEvent(eventType),
+		ProjectID: s.issueProjectID(ctx, issueID),
+		IssueID:   issueID,
+		Actor:     actor,
+		Data:      data,
+	})
 }
 
 func ptrToString(p *string) string {
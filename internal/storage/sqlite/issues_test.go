@@ -3,6 +3,7 @@ package sqlite_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/sentiolabs/arc/internal/types"
 )
@@ -20,7 +21,7 @@ func TestListIssuesMultiFilterStatuses(t *testing.T) {
 
 	issueInProgress := setupTestIssue(t, store, proj, "In Progress Issue")
 	err := store.UpdateIssue(ctx, issueInProgress.ID,
-		map[string]any{"status": string(types.StatusInProgress)}, "test-actor")
+		map[string]any{"status": string(types.StatusInProgress)}, 0, "test-actor")
 	if err != nil {
 		t.Fatalf("failed to update issue status: %v", err)
 	}
@@ -209,7 +210,7 @@ func TestListIssuesMultiFilterEmptySlicesReturnAll(t *testing.T) {
 	_ = setupTestIssue(t, store, proj, "Issue 1")
 	issue2 := setupTestIssue(t, store, proj, "Issue 2")
 	err := store.UpdateIssue(ctx, issue2.ID,
-		map[string]any{"status": string(types.StatusInProgress)}, "test-actor")
+		map[string]any{"status": string(types.StatusInProgress)}, 0, "test-actor")
 	if err != nil {
 		t.Fatalf("failed to update issue: %v", err)
 	}
@@ -270,7 +271,7 @@ func TestListIssuesMultiFilterCombined(t *testing.T) {
 		t.Fatalf("failed to create issue: %v", err)
 	}
 	err := store.UpdateIssue(ctx, issue3.ID,
-		map[string]any{"status": string(types.StatusInProgress)}, "test-actor")
+		map[string]any{"status": string(types.StatusInProgress)}, 0, "test-actor")
 	if err != nil {
 		t.Fatalf("failed to update issue: %v", err)
 	}
@@ -294,3 +295,46 @@ func TestListIssuesMultiFilterCombined(t *testing.T) {
 		t.Errorf("expected issue %s, got %s", issue1.ID, issues[0].ID)
 	}
 }
+
+func TestListIssuesTimeBoundsNonUTC(t *testing.T) {
+	// Timestamps are written in the local zone; the bounds must still
+	// compare as instants rather than as text.
+	saved := time.Local
+	time.Local = time.FixedZone("EST", -5*60*60)
+	defer func() { time.Local = saved }()
+
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	proj := setupTestProject(t, store)
+	issue := setupTestIssue(t, store, proj, "Just updated")
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]any{"priority": 1}, 0, "test-actor"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+
+	hourAgo := time.Now().UTC().Add(-time.Hour)
+	inHour := time.Now().UTC().Add(time.Hour)
+	tests := []struct {
+		name   string
+		filter types.IssueFilter
+		want   int
+	}{
+		{"updated after", types.IssueFilter{UpdatedAfter: &hourAgo}, 1},
+		{"created after", types.IssueFilter{CreatedAfter: &hourAgo}, 1},
+		{"updated before", types.IssueFilter{UpdatedBefore: &hourAgo}, 0},
+		{"created before", types.IssueFilter{CreatedBefore: &inHour}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.filter.ProjectID = proj.ID
+			issues, err := store.ListIssues(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("ListIssues failed: %v", err)
+			}
+			if len(issues) != tt.want {
+				t.Errorf("ListIssues returned %d issues, want %d", len(issues), tt.want)
+			}
+		})
+	}
+}
@@ -98,7 +98,6 @@ func (s *Store) AddLabelToIssue(ctx context.Context, issueID, label, actor strin
 	}
 
 	s.recordEvent(ctx, issueID, types.EventLabelAdded, actor, nil, &label)
-	s.rebuildFTSForIssue(ctx, issueID)
 	return nil
 }
 
@@ -113,7 +112,6 @@ func (s *Store) RemoveLabelFromIssue(ctx context.Context, issueID, label, actor
 	}
 
 	s.recordEvent(ctx, issueID, types.EventLabelRemoved, actor, &label, nil)
-	s.rebuildFTSForIssue(ctx, issueID)
 	return nil
 }
 
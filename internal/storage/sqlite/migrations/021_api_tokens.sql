@@ -0,0 +1,19 @@
+-- +goose Up
+-- API tokens authenticate requests as an actor. Only a SHA-256 hash of each
+-- token is stored; prefix keeps the first characters for identification.
+CREATE TABLE api_tokens (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    prefix TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX idx_api_tokens_actor ON api_tokens(actor);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_api_tokens_actor;
+DROP TABLE IF EXISTS api_tokens;
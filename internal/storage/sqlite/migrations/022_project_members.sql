@@ -0,0 +1,16 @@
+-- +goose Up
+-- Project memberships grant actors a role on a project. A project with no
+-- members is open to every caller.
+CREATE TABLE project_members (
+    project_id TEXT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    actor TEXT NOT NULL,
+    role TEXT NOT NULL CHECK (role IN ('viewer', 'contributor', 'maintainer', 'admin')),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (project_id, actor)
+);
+
+CREATE INDEX idx_project_members_actor ON project_members(actor);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_project_members_actor;
+DROP TABLE IF EXISTS project_members;
@@ -0,0 +1,19 @@
+-- +goose Up
+-- Server-side issue templates, optionally instantiated on a schedule.
+CREATE TABLE issue_templates (
+    project_id TEXT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    content TEXT NOT NULL,
+    recurrence TEXT NOT NULL DEFAULT '',
+    next_run_at TIMESTAMP,
+    last_run_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (project_id, name)
+);
+
+CREATE INDEX idx_issue_templates_due ON issue_templates(next_run_at) WHERE next_run_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_issue_templates_due;
+DROP TABLE IF EXISTS issue_templates;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- Values of per-project custom issue fields. Field definitions live in the
+-- project config table under field.<key>.
+CREATE TABLE issue_fields (
+    issue_id TEXT NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    PRIMARY KEY (issue_id, key)
+);
+
+CREATE INDEX idx_issue_fields_key_value ON issue_fields(key, value);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_issue_fields_key_value;
+DROP TABLE IF EXISTS issue_fields;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- Extend full-text search to comments and labels. The index is no longer an
+-- external-content table over issues: each row aggregates an issue with its
+-- comments and labels, so it stores its own copy of the text.
+DROP TABLE IF EXISTS issues_fts;
+CREATE VIRTUAL TABLE issues_fts USING fts5(id UNINDEXED, title, description, comments, labels);
+INSERT INTO issues_fts(id, title, description, comments, labels)
+SELECT i.id, i.title, COALESCE(i.description, ''),
+       COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+       COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+FROM issues i;
+
+-- +goose Down
+DROP TABLE IF EXISTS issues_fts;
+CREATE VIRTUAL TABLE issues_fts USING fts5(id, title, description, content=issues, content_rowid=rowid);
+INSERT INTO issues_fts(id, title, description) SELECT id, title, COALESCE(description, '') FROM issues;
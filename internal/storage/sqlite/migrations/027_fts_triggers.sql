@@ -0,0 +1,136 @@
+-- +goose Up
+-- Keep the full-text index current from triggers, in the same transaction
+-- as the write, instead of rebuilding entries from Go after the fact. Each
+-- index row shares its rowid with its issue so triggers can find it without
+-- scanning. The index is left empty here: the store rebuilds it on startup
+-- because the index version marker in global_config is cleared.
+DROP TABLE IF EXISTS issues_fts;
+CREATE VIRTUAL TABLE issues_fts USING fts5(id UNINDEXED, title, description, comments, labels);
+DELETE FROM global_config WHERE key = 'fts_index_version';
+
+-- +goose StatementBegin
+CREATE TRIGGER issues_fts_insert AFTER INSERT ON issues BEGIN
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT i.rowid, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    WHERE i.rowid = NEW.rowid;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER issues_fts_update AFTER UPDATE OF id, title, description ON issues BEGIN
+    DELETE FROM issues_fts WHERE rowid = OLD.rowid;
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT i.rowid, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    WHERE i.rowid = NEW.rowid;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER issues_fts_delete AFTER DELETE ON issues BEGIN
+    DELETE FROM issues_fts WHERE rowid = OLD.rowid;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER comments_fts_insert AFTER INSERT ON comments BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT rowid FROM issues WHERE id = NEW.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT i.rowid, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    WHERE i.id = NEW.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER comments_fts_update AFTER UPDATE OF text, issue_id ON comments BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT rowid FROM issues WHERE id = OLD.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT i.rowid, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    WHERE i.id = OLD.issue_id;
+    DELETE FROM issues_fts WHERE rowid = (SELECT rowid FROM issues WHERE id = NEW.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT i.rowid, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    WHERE i.id = NEW.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER comments_fts_delete AFTER DELETE ON comments BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT rowid FROM issues WHERE id = OLD.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT i.rowid, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    WHERE i.id = OLD.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER issue_labels_fts_insert AFTER INSERT ON issue_labels BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT rowid FROM issues WHERE id = NEW.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT i.rowid, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    WHERE i.id = NEW.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER issue_labels_fts_update AFTER UPDATE OF label, issue_id ON issue_labels BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT rowid FROM issues WHERE id = OLD.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT i.rowid, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    WHERE i.id = OLD.issue_id;
+    DELETE FROM issues_fts WHERE rowid = (SELECT rowid FROM issues WHERE id = NEW.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT i.rowid, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    WHERE i.id = NEW.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER issue_labels_fts_delete AFTER DELETE ON issue_labels BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT rowid FROM issues WHERE id = OLD.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT i.rowid, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    WHERE i.id = OLD.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS issue_labels_fts_delete;
+DROP TRIGGER IF EXISTS issue_labels_fts_update;
+DROP TRIGGER IF EXISTS issue_labels_fts_insert;
+DROP TRIGGER IF EXISTS comments_fts_delete;
+DROP TRIGGER IF EXISTS comments_fts_update;
+DROP TRIGGER IF EXISTS comments_fts_insert;
+DROP TRIGGER IF EXISTS issues_fts_delete;
+DROP TRIGGER IF EXISTS issues_fts_update;
+DROP TRIGGER IF EXISTS issues_fts_insert;
+DELETE FROM global_config WHERE key = 'fts_index_version';
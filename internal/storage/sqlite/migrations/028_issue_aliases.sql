@@ -0,0 +1,14 @@
+-- +goose Up
+-- IDs issues had before they were moved to another project. An alias keeps
+-- resolving to the issue wherever the old ID was pasted.
+CREATE TABLE issue_aliases (
+    alias TEXT PRIMARY KEY,
+    issue_id TEXT NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_issue_aliases_issue ON issue_aliases(issue_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_issue_aliases_issue;
+DROP TABLE IF EXISTS issue_aliases;
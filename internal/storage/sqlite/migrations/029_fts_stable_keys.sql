@@ -0,0 +1,281 @@
+-- +goose Up
+-- Key full-text index rows by issue_fts_keys instead of the issues rowid.
+-- issues has a TEXT primary key, so SQLite may renumber its implicit rowids
+-- whenever it rebuilds the table, as VACUUM does; triggers that looked rows
+-- up by rowid could then delete or overwrite another issue's entry. The
+-- INTEGER PRIMARY KEY of issue_fts_keys never changes. The version marker
+-- is cleared so the store rebuilds the index under the new keys on startup.
+CREATE TABLE issue_fts_keys (
+    key INTEGER PRIMARY KEY,
+    issue_id TEXT NOT NULL UNIQUE
+);
+INSERT INTO issue_fts_keys (issue_id) SELECT id FROM issues ORDER BY rowid;
+DELETE FROM global_config WHERE key = 'fts_index_version';
+
+DROP TRIGGER IF EXISTS issues_fts_insert;
+DROP TRIGGER IF EXISTS issues_fts_update;
+DROP TRIGGER IF EXISTS issues_fts_delete;
+DROP TRIGGER IF EXISTS comments_fts_insert;
+DROP TRIGGER IF EXISTS comments_fts_update;
+DROP TRIGGER IF EXISTS comments_fts_delete;
+DROP TRIGGER IF EXISTS issue_labels_fts_insert;
+DROP TRIGGER IF EXISTS issue_labels_fts_update;
+DROP TRIGGER IF EXISTS issue_labels_fts_delete;
+
+-- +goose StatementBegin
+CREATE TRIGGER issues_fts_insert AFTER INSERT ON issues BEGIN
+    INSERT OR IGNORE INTO issue_fts_keys (issue_id) VALUES (NEW.id);
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = NEW.id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER issues_fts_update AFTER UPDATE OF id, title, description ON issues BEGIN
+    UPDATE issue_fts_keys SET issue_id = NEW.id WHERE issue_id = OLD.id;
+    INSERT OR IGNORE INTO issue_fts_keys (issue_id) VALUES (NEW.id);
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = NEW.id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER issues_fts_delete AFTER DELETE ON issues BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = OLD.id);
+    DELETE FROM issue_fts_keys WHERE issue_id = OLD.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER comments_fts_insert AFTER INSERT ON comments BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = NEW.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = NEW.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER comments_fts_update AFTER UPDATE OF text, issue_id ON comments BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = OLD.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = OLD.issue_id;
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = NEW.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = NEW.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER comments_fts_delete AFTER DELETE ON comments BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = OLD.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = OLD.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER issue_labels_fts_insert AFTER INSERT ON issue_labels BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = NEW.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = NEW.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER issue_labels_fts_update AFTER UPDATE OF label, issue_id ON issue_labels BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = OLD.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = OLD.issue_id;
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = NEW.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = NEW.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER issue_labels_fts_delete AFTER DELETE ON issue_labels BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = OLD.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = OLD.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS issues_fts_insert;
+DROP TRIGGER IF EXISTS issues_fts_update;
+DROP TRIGGER IF EXISTS issues_fts_delete;
+DROP TRIGGER IF EXISTS comments_fts_insert;
+DROP TRIGGER IF EXISTS comments_fts_update;
+DROP TRIGGER IF EXISTS comments_fts_delete;
+DROP TRIGGER IF EXISTS issue_labels_fts_insert;
+DROP TRIGGER IF EXISTS issue_labels_fts_update;
+DROP TRIGGER IF EXISTS issue_labels_fts_delete;
+DROP TABLE IF EXISTS issue_fts_keys;
+DELETE FROM global_config WHERE key = 'fts_index_version';
+
+-- +goose StatementBegin
+CREATE TRIGGER issues_fts_insert AFTER INSERT ON issues BEGIN
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT i.rowid, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    WHERE i.rowid = NEW.rowid;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER issues_fts_update AFTER UPDATE OF id, title, description ON issues BEGIN
+    DELETE FROM issues_fts WHERE rowid = OLD.rowid;
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT i.rowid, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    WHERE i.rowid = NEW.rowid;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER issues_fts_delete AFTER DELETE ON issues BEGIN
+    DELETE FROM issues_fts WHERE rowid = OLD.rowid;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER comments_fts_insert AFTER INSERT ON comments BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT rowid FROM issues WHERE id = NEW.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT i.rowid, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    WHERE i.id = NEW.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER comments_fts_update AFTER UPDATE OF text, issue_id ON comments BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT rowid FROM issues WHERE id = OLD.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT i.rowid, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    WHERE i.id = OLD.issue_id;
+    DELETE FROM issues_fts WHERE rowid = (SELECT rowid FROM issues WHERE id = NEW.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT i.rowid, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    WHERE i.id = NEW.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER comments_fts_delete AFTER DELETE ON comments BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT rowid FROM issues WHERE id = OLD.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT i.rowid, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    WHERE i.id = OLD.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER issue_labels_fts_insert AFTER INSERT ON issue_labels BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT rowid FROM issues WHERE id = NEW.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT i.rowid, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    WHERE i.id = NEW.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER issue_labels_fts_update AFTER UPDATE OF label, issue_id ON issue_labels BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT rowid FROM issues WHERE id = OLD.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT i.rowid, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    WHERE i.id = OLD.issue_id;
+    DELETE FROM issues_fts WHERE rowid = (SELECT rowid FROM issues WHERE id = NEW.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT i.rowid, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    WHERE i.id = NEW.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER issue_labels_fts_delete AFTER DELETE ON issue_labels BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT rowid FROM issues WHERE id = OLD.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT i.rowid, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    WHERE i.id = OLD.issue_id;
+END;
+-- +goose StatementEnd
@@ -0,0 +1,386 @@
+-- +goose Up
+-- Link issues to plans and index the text of linked plans. Plan content
+-- lives on the filesystem, so plans.search_text keeps a copy of it for the
+-- index, refreshed whenever the content is written through the API or the
+-- plan is linked. issues_fts gains a plans column; FTS5 tables cannot be
+-- altered, so it is recreated empty and the version marker is cleared for
+-- the store to rebuild it on startup.
+ALTER TABLE plans ADD COLUMN search_text TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE issue_plans (
+    issue_id TEXT NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    plan_id TEXT NOT NULL REFERENCES plans(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (issue_id, plan_id)
+);
+CREATE INDEX idx_issue_plans_plan ON issue_plans(plan_id);
+
+DROP TRIGGER IF EXISTS issues_fts_insert;
+DROP TRIGGER IF EXISTS issues_fts_update;
+DROP TRIGGER IF EXISTS issues_fts_delete;
+DROP TRIGGER IF EXISTS comments_fts_insert;
+DROP TRIGGER IF EXISTS comments_fts_update;
+DROP TRIGGER IF EXISTS comments_fts_delete;
+DROP TRIGGER IF EXISTS issue_labels_fts_insert;
+DROP TRIGGER IF EXISTS issue_labels_fts_update;
+DROP TRIGGER IF EXISTS issue_labels_fts_delete;
+
+DROP TABLE IF EXISTS issues_fts;
+CREATE VIRTUAL TABLE issues_fts USING fts5(id UNINDEXED, title, description, comments, labels, plans);
+DELETE FROM global_config WHERE key = 'fts_index_version';
+
+-- +goose StatementBegin
+CREATE TRIGGER issues_fts_insert AFTER INSERT ON issues BEGIN
+    INSERT OR IGNORE INTO issue_fts_keys (issue_id) VALUES (NEW.id);
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = NEW.id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels, plans)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(p.search_text, char(10)) FROM issue_plans ip JOIN plans p ON p.id = ip.plan_id WHERE ip.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER issues_fts_update AFTER UPDATE OF id, title, description ON issues BEGIN
+    UPDATE issue_fts_keys SET issue_id = NEW.id WHERE issue_id = OLD.id;
+    INSERT OR IGNORE INTO issue_fts_keys (issue_id) VALUES (NEW.id);
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = NEW.id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels, plans)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(p.search_text, char(10)) FROM issue_plans ip JOIN plans p ON p.id = ip.plan_id WHERE ip.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER issues_fts_delete AFTER DELETE ON issues BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = OLD.id);
+    DELETE FROM issue_fts_keys WHERE issue_id = OLD.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER comments_fts_insert AFTER INSERT ON comments BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = NEW.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels, plans)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(p.search_text, char(10)) FROM issue_plans ip JOIN plans p ON p.id = ip.plan_id WHERE ip.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = NEW.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER comments_fts_update AFTER UPDATE OF text, issue_id ON comments BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = OLD.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels, plans)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(p.search_text, char(10)) FROM issue_plans ip JOIN plans p ON p.id = ip.plan_id WHERE ip.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = OLD.issue_id;
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = NEW.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels, plans)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(p.search_text, char(10)) FROM issue_plans ip JOIN plans p ON p.id = ip.plan_id WHERE ip.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = NEW.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER comments_fts_delete AFTER DELETE ON comments BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = OLD.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels, plans)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(p.search_text, char(10)) FROM issue_plans ip JOIN plans p ON p.id = ip.plan_id WHERE ip.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = OLD.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER issue_labels_fts_insert AFTER INSERT ON issue_labels BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = NEW.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels, plans)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(p.search_text, char(10)) FROM issue_plans ip JOIN plans p ON p.id = ip.plan_id WHERE ip.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = NEW.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER issue_labels_fts_update AFTER UPDATE OF label, issue_id ON issue_labels BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = OLD.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels, plans)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(p.search_text, char(10)) FROM issue_plans ip JOIN plans p ON p.id = ip.plan_id WHERE ip.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = OLD.issue_id;
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = NEW.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels, plans)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(p.search_text, char(10)) FROM issue_plans ip JOIN plans p ON p.id = ip.plan_id WHERE ip.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = NEW.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER issue_labels_fts_delete AFTER DELETE ON issue_labels BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = OLD.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels, plans)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(p.search_text, char(10)) FROM issue_plans ip JOIN plans p ON p.id = ip.plan_id WHERE ip.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = OLD.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER issue_plans_fts_insert AFTER INSERT ON issue_plans BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = NEW.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels, plans)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(p.search_text, char(10)) FROM issue_plans ip JOIN plans p ON p.id = ip.plan_id WHERE ip.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = NEW.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER issue_plans_fts_update AFTER UPDATE OF plan_id, issue_id ON issue_plans BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = OLD.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels, plans)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(p.search_text, char(10)) FROM issue_plans ip JOIN plans p ON p.id = ip.plan_id WHERE ip.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = OLD.issue_id;
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = NEW.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels, plans)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(p.search_text, char(10)) FROM issue_plans ip JOIN plans p ON p.id = ip.plan_id WHERE ip.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = NEW.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER issue_plans_fts_delete AFTER DELETE ON issue_plans BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = OLD.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels, plans)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(p.search_text, char(10)) FROM issue_plans ip JOIN plans p ON p.id = ip.plan_id WHERE ip.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = OLD.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER plans_fts_update AFTER UPDATE OF search_text ON plans BEGIN
+    DELETE FROM issues_fts WHERE rowid IN (SELECT key FROM issue_fts_keys WHERE issue_id IN (SELECT issue_id FROM issue_plans WHERE plan_id = NEW.id));
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels, plans)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(p.search_text, char(10)) FROM issue_plans ip JOIN plans p ON p.id = ip.plan_id WHERE ip.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id IN (SELECT issue_id FROM issue_plans WHERE plan_id = NEW.id);
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS issues_fts_insert;
+DROP TRIGGER IF EXISTS issues_fts_update;
+DROP TRIGGER IF EXISTS issues_fts_delete;
+DROP TRIGGER IF EXISTS comments_fts_insert;
+DROP TRIGGER IF EXISTS comments_fts_update;
+DROP TRIGGER IF EXISTS comments_fts_delete;
+DROP TRIGGER IF EXISTS issue_labels_fts_insert;
+DROP TRIGGER IF EXISTS issue_labels_fts_update;
+DROP TRIGGER IF EXISTS issue_labels_fts_delete;
+DROP TRIGGER IF EXISTS issue_plans_fts_insert;
+DROP TRIGGER IF EXISTS issue_plans_fts_update;
+DROP TRIGGER IF EXISTS issue_plans_fts_delete;
+DROP TRIGGER IF EXISTS plans_fts_update;
+DROP TABLE IF EXISTS issue_plans;
+ALTER TABLE plans DROP COLUMN search_text;
+
+DROP TABLE IF EXISTS issues_fts;
+CREATE VIRTUAL TABLE issues_fts USING fts5(id UNINDEXED, title, description, comments, labels);
+DELETE FROM global_config WHERE key = 'fts_index_version';
+
+-- +goose StatementBegin
+CREATE TRIGGER issues_fts_insert AFTER INSERT ON issues BEGIN
+    INSERT OR IGNORE INTO issue_fts_keys (issue_id) VALUES (NEW.id);
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = NEW.id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER issues_fts_update AFTER UPDATE OF id, title, description ON issues BEGIN
+    UPDATE issue_fts_keys SET issue_id = NEW.id WHERE issue_id = OLD.id;
+    INSERT OR IGNORE INTO issue_fts_keys (issue_id) VALUES (NEW.id);
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = NEW.id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER issues_fts_delete AFTER DELETE ON issues BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = OLD.id);
+    DELETE FROM issue_fts_keys WHERE issue_id = OLD.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER comments_fts_insert AFTER INSERT ON comments BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = NEW.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = NEW.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER comments_fts_update AFTER UPDATE OF text, issue_id ON comments BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = OLD.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = OLD.issue_id;
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = NEW.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = NEW.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER comments_fts_delete AFTER DELETE ON comments BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = OLD.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = OLD.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER issue_labels_fts_insert AFTER INSERT ON issue_labels BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = NEW.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = NEW.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER issue_labels_fts_update AFTER UPDATE OF label, issue_id ON issue_labels BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = OLD.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = OLD.issue_id;
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = NEW.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = NEW.issue_id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER issue_labels_fts_delete AFTER DELETE ON issue_labels BEGIN
+    DELETE FROM issues_fts WHERE rowid = (SELECT key FROM issue_fts_keys WHERE issue_id = OLD.issue_id);
+    INSERT INTO issues_fts(rowid, id, title, description, comments, labels)
+    SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+           COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+           COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), '')
+    FROM issues i
+    JOIN issue_fts_keys k ON k.issue_id = i.id
+    WHERE i.id = OLD.issue_id;
+END;
+-- +goose StatementEnd
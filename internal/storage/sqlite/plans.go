@@ -29,6 +29,12 @@ func (s *Store) CreatePlan(ctx context.Context, plan *types.Plan) error {
 	if err != nil {
 		return fmt.Errorf("create plan: %w", err)
 	}
+
+	s.publish(types.StreamEvent{
+		Type:   types.StreamPlanCreated,
+		PlanID: plan.ID,
+		Data:   map[string]any{"status": plan.Status},
+	})
 	return nil
 }
 
@@ -54,23 +60,100 @@ func (s *Store) UpdatePlanStatus(ctx context.Context, id string, status string)
 	if err != nil {
 		return fmt.Errorf("update plan status: %w", err)
 	}
+
+	s.publish(types.StreamEvent{
+		Type:   types.StreamPlanStatusChanged,
+		PlanID: id,
+		Data:   map[string]any{"status": status},
+	})
 	return nil
 }
 
-// DeletePlan deletes a plan and its associated comments.
-// Comments are deleted explicitly to ensure cascade behavior regardless of
+// DeletePlan deletes a plan, its associated comments and its issue links.
+// Both are deleted explicitly to ensure cascade behavior regardless of
 // whether the SQLite driver honours the ON DELETE CASCADE pragma.
 func (s *Store) DeletePlan(ctx context.Context, id string) error {
 	if _, err := s.db.ExecContext(ctx, "DELETE FROM plan_comments WHERE plan_id = ?", id); err != nil {
 		return fmt.Errorf("delete plan comments for plan: %w", err)
 	}
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM issue_plans WHERE plan_id = ?", id); err != nil {
+		return fmt.Errorf("delete issue links for plan: %w", err)
+	}
 	err := s.queries.DeletePlan(ctx, id)
 	if err != nil {
 		return fmt.Errorf("delete plan: %w", err)
 	}
+
+	s.publish(types.StreamEvent{Type: types.StreamPlanDeleted, PlanID: id})
+	return nil
+}
+
+// SetPlanText stores the copy of a plan's file content that search indexes
+// for the issues linked to it. The FTS triggers reindex those issues.
+func (s *Store) SetPlanText(ctx context.Context, id, text string) error {
+	if _, err := s.db.ExecContext(ctx, "UPDATE plans SET search_text = ? WHERE id = ?", text, id); err != nil {
+		return fmt.Errorf("set plan text: %w", err)
+	}
 	return nil
 }
 
+// LinkIssuePlan links a plan to an issue. Linking a pair twice is a no-op.
+func (s *Store) LinkIssuePlan(ctx context.Context, issueID, planID string) error {
+	issue, err := s.GetIssue(ctx, issueID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.GetPlan(ctx, planID); err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO issue_plans (issue_id, plan_id, created_at) VALUES (?, ?, ?)
+		ON CONFLICT (issue_id, plan_id) DO NOTHING
+	`, issue.ID, planID, time.Now())
+	if err != nil {
+		return fmt.Errorf("link plan: %w", err)
+	}
+	return nil
+}
+
+// UnlinkIssuePlan removes the link between an issue and a plan, if any.
+func (s *Store) UnlinkIssuePlan(ctx context.Context, issueID, planID string) error {
+	_, err := s.db.ExecContext(ctx, "DELETE FROM issue_plans WHERE issue_id = ? AND plan_id = ?", issueID, planID)
+	if err != nil {
+		return fmt.Errorf("unlink plan: %w", err)
+	}
+	return nil
+}
+
+// ListIssuePlans returns the plans linked to an issue, in the order they
+// were linked.
+func (s *Store) ListIssuePlans(ctx context.Context, issueID string) ([]*types.Plan, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT p.id, p.file_path, p.status, p.created_at, p.updated_at
+		FROM issue_plans ip
+		JOIN plans p ON p.id = ip.plan_id
+		WHERE ip.issue_id = ?
+		ORDER BY ip.created_at, p.id
+	`, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("list issue plans: %w", err)
+	}
+	defer rows.Close()
+
+	plans := []*types.Plan{}
+	for rows.Next() {
+		var plan types.Plan
+		if err := rows.Scan(&plan.ID, &plan.FilePath, &plan.Status, &plan.CreatedAt, &plan.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan issue plan: %w", err)
+		}
+		plans = append(plans, &plan)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list issue plans: %w", err)
+	}
+	return plans, nil
+}
+
 // CreatePlanComment persists a new comment on a plan.
 func (s *Store) CreatePlanComment(ctx context.Context, comment *types.PlanComment) error {
 	now := time.Now()
@@ -102,6 +185,8 @@ func (s *Store) CreatePlanComment(ctx context.Context, comment *types.PlanCommen
 	if err != nil {
 		return fmt.Errorf("create plan comment: %w", err)
 	}
+
+	s.publishPlanComment(types.StreamPlanCommentAdded, comment.PlanID, comment.ID)
 	return nil
 }
 
@@ -152,17 +237,35 @@ func (s *Store) UpdatePlanComment(ctx context.Context, comment *types.PlanCommen
 	if err != nil {
 		return fmt.Errorf("update plan comment: %w", err)
 	}
+
+	s.publishPlanComment(types.StreamPlanCommentUpdated, comment.PlanID, comment.ID)
 	return nil
 }
 
 // DeletePlanComment removes a plan comment.
 func (s *Store) DeletePlanComment(ctx context.Context, id string) error {
+	// Look up the plan before delete so the notification can be scoped
+	existing, _ := s.queries.GetPlanComment(ctx, id)
+
 	if err := s.queries.DeletePlanComment(ctx, id); err != nil {
 		return fmt.Errorf("delete plan comment: %w", err)
 	}
+
+	if existing != nil {
+		s.publishPlanComment(types.StreamPlanCommentDeleted, existing.PlanID, id)
+	}
 	return nil
 }
 
+// publishPlanComment notifies subscribers of a plan comment change.
+func (s *Store) publishPlanComment(evType types.StreamEventType, planID, commentID string) {
+	s.publish(types.StreamEvent{
+		Type:   evType,
+		PlanID: planID,
+		Data:   map[string]any{"comment_id": commentID},
+	})
+}
+
 // nullString returns a valid sql.NullString for non-empty s.
 func nullString(s string) sql.NullString {
 	return sql.NullString{String: s, Valid: s != ""}
@@ -0,0 +1,64 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func TestProjectMembers(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+	proj := setupTestProject(t, store)
+
+	members, err := store.ListProjectMembers(ctx, proj.ID)
+	if err != nil || len(members) != 0 {
+		t.Fatalf("ListProjectMembers on new project = %d, %v; want 0", len(members), err)
+	}
+
+	alice := &types.ProjectMember{ProjectID: proj.ID, Actor: "alice", Role: types.RoleAdmin}
+	bob := &types.ProjectMember{ProjectID: proj.ID, Actor: "bob", Role: types.RoleViewer}
+	for _, m := range []*types.ProjectMember{alice, bob} {
+		if err := store.SetProjectMember(ctx, m); err != nil {
+			t.Fatalf("SetProjectMember(%s): %v", m.Actor, err)
+		}
+	}
+	if err := store.SetProjectMember(ctx, &types.ProjectMember{ProjectID: proj.ID, Actor: "eve", Role: "owner"}); err == nil {
+		t.Error("expected error for invalid role")
+	}
+
+	// Setting an existing member changes the role and keeps created_at.
+	promoted := &types.ProjectMember{ProjectID: proj.ID, Actor: "bob", Role: types.RoleMaintainer}
+	if err := store.SetProjectMember(ctx, promoted); err != nil {
+		t.Fatalf("SetProjectMember(promote): %v", err)
+	}
+	got, err := store.GetProjectMember(ctx, proj.ID, "bob")
+	if err != nil {
+		t.Fatalf("GetProjectMember: %v", err)
+	}
+	if got.Role != types.RoleMaintainer || !got.CreatedAt.Equal(bob.CreatedAt) {
+		t.Errorf("GetProjectMember = %+v, want maintainer created at %v", got, bob.CreatedAt)
+	}
+
+	members, err = store.ListProjectMembers(ctx, proj.ID)
+	if err != nil || len(members) != 2 || members[0].Actor != "alice" {
+		t.Fatalf("ListProjectMembers = %+v, %v", members, err)
+	}
+
+	if err := store.RemoveProjectMember(ctx, proj.ID, "bob"); err != nil {
+		t.Fatalf("RemoveProjectMember: %v", err)
+	}
+	if err := store.RemoveProjectMember(ctx, proj.ID, "bob"); err == nil {
+		t.Error("expected error removing a non-member")
+	}
+
+	// Members go away with their project.
+	if err := store.DeleteProject(ctx, proj.ID); err != nil {
+		t.Fatalf("DeleteProject: %v", err)
+	}
+	if _, err := store.GetProjectMember(ctx, proj.ID, "alice"); err == nil {
+		t.Error("expected membership to be deleted with the project")
+	}
+}
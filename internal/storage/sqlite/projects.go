@@ -171,7 +171,6 @@ func (s *Store) MergeProjects(
 
 	// Best-effort post-commit work (outside transaction)
 	for _, issueID := range movedIssueIDs {
-		s.rebuildFTSForIssue(ctx, issueID)
 		newValue := "merged into " + targetID
 		s.recordEvent(ctx, issueID, types.EventMerged, actor, nil, &newValue)
 	}
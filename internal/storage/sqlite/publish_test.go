@@ -0,0 +1,130 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// recordingPublisher collects published events.
+type recordingPublisher struct {
+	events []types.StreamEvent
+}
+
+func (r *recordingPublisher) Publish(ev types.StreamEvent) {
+	r.events = append(r.events, ev)
+}
+
+// types returns the published event types in order.
+func (r *recordingPublisher) types() []types.StreamEventType {
+	out := make([]types.StreamEventType, len(r.events))
+	for i, ev := range r.events {
+		out[i] = ev.Type
+	}
+	return out
+}
+
+func TestStorePublishesIssueChanges(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	proj := setupTestProject(t, store)
+	pub := &recordingPublisher{}
+	store.SetPublisher(pub)
+
+	issue := setupTestIssue(t, store, proj, "Published")
+	if err := store.AddLabelToIssue(ctx, issue.ID, "bug", "alice"); err != nil {
+		t.Fatalf("add label: %v", err)
+	}
+	comment, err := store.AddComment(ctx, issue.ID, "alice", "first")
+	if err != nil {
+		t.Fatalf("add comment: %v", err)
+	}
+	if err := store.UpdateComment(ctx, comment.ID, "edited"); err != nil {
+		t.Fatalf("update comment: %v", err)
+	}
+	if err := store.CloseIssue(ctx, issue.ID, "done", false, "alice"); err != nil {
+		t.Fatalf("close issue: %v", err)
+	}
+	if err := store.DeleteIssue(ctx, issue.ID); err != nil {
+		t.Fatalf("delete issue: %v", err)
+	}
+
+	want := []types.StreamEventType{
+		types.StreamIssueCreated,
+		types.StreamLabelAdded,
+		types.StreamCommentAdded,
+		types.StreamCommentUpdated,
+		types.StreamIssueClosed,
+		types.StreamIssueDeleted,
+	}
+	got := pub.types()
+	if len(got) != len(want) {
+		t.Fatalf("published %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event[%d] = %s, want %s", i, got[i], want[i])
+		}
+		if pub.events[i].ProjectID != proj.ID {
+			t.Errorf("event[%d] project = %q, want %q", i, pub.events[i].ProjectID, proj.ID)
+		}
+		if pub.events[i].IssueID != issue.ID {
+			t.Errorf("event[%d] issue = %q, want %q", i, pub.events[i].IssueID, issue.ID)
+		}
+	}
+	if v := pub.events[1].Data["new_value"]; v != "bug" {
+		t.Errorf("label event new_value = %v, want bug", v)
+	}
+}
+
+func TestStorePublishesPlanAndAgentChanges(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	proj := setupTestProject(t, store)
+	pub := &recordingPublisher{}
+	store.SetPublisher(pub)
+
+	plan := &types.Plan{ID: "plan.pub", FilePath: "/tmp/plan.md", Status: types.PlanStatusDraft}
+	if err := store.CreatePlan(ctx, plan); err != nil {
+		t.Fatalf("create plan: %v", err)
+	}
+	if err := store.UpdatePlanStatus(ctx, plan.ID, types.PlanStatusApproved); err != nil {
+		t.Fatalf("update plan status: %v", err)
+	}
+	session := &types.AISession{ID: "sess-1", ProjectID: proj.ID, TranscriptPath: "/tmp/t.jsonl", StartedAt: time.Now()}
+	if err := store.CreateAISession(ctx, session); err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	agent := &types.AIAgent{ID: "agent-1", SessionID: session.ID, Status: "running", CreatedAt: time.Now()}
+	if err := store.CreateAIAgent(ctx, agent); err != nil {
+		t.Fatalf("create agent: %v", err)
+	}
+
+	want := []types.StreamEventType{
+		types.StreamPlanCreated,
+		types.StreamPlanStatusChanged,
+		types.StreamAISessionCreated,
+		types.StreamAIAgentRegistered,
+	}
+	got := pub.types()
+	if len(got) != len(want) {
+		t.Fatalf("published %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+	if status := pub.events[1].Data["status"]; status != types.PlanStatusApproved {
+		t.Errorf("plan status event data = %v, want approved", status)
+	}
+	if agentEv := pub.events[3]; agentEv.ProjectID != proj.ID || agentEv.SessionID != session.ID {
+		t.Errorf("agent event = %+v, want project %s session %s", agentEv, proj.ID, session.ID)
+	}
+}
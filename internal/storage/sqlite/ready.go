@@ -14,112 +14,191 @@ import (
 // defaultWorkLimit is the default maximum number of issues returned by work queries.
 const defaultWorkLimit = 100
 
-// GetReadyWork returns issues that are ready to work on (not blocked).
-// Results are sorted according to the filter's SortPolicy (hybrid, priority, or oldest).
-// Additional filters for issue type, priority, and status are applied in-memory.
-func (s *Store) GetReadyWork(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error) {
+// readySortKeys holds, for each sort policy, the expressions ready work is
+// ordered by, written against the alias %[1]s. The hybrid policy puts issues
+// touched in the last 48 hours first, by priority and rank, followed by
+// older issues oldest first. Every key ends in the issue ID so the order is
+// total and a cursor can resume from any issue.
+var readySortKeys = map[types.SortPolicy]string{
+	types.SortPolicyHybrid: `
+  CASE WHEN %[1]s.updated_at >= datetime('now', '-48 hours') THEN 0 ELSE 1 END,
+  CASE WHEN %[1]s.updated_at >= datetime('now', '-48 hours') THEN %[1]s.priority ELSE 999 END,
+  CASE WHEN %[1]s.updated_at >= datetime('now', '-48 hours')
+       THEN CASE WHEN %[1]s.rank = 0 THEN 999999 ELSE %[1]s.rank END
+       ELSE 999999 END,
+  %[1]s.created_at, %[1]s.id`,
+	types.SortPolicyPriority: `
+  %[1]s.priority, CASE WHEN %[1]s.rank = 0 THEN 999999 ELSE %[1]s.rank END, %[1]s.created_at, %[1]s.id`,
+	types.SortPolicyOldest: `
+  %[1]s.created_at, %[1]s.id`,
+}
+
+// blockedSortKey orders blocked issues by priority.
+const blockedSortKey = `%[1]s.priority, %[1]s.id`
+
+// queryArgs accumulates the positional args of a dynamically built query.
+type queryArgs struct {
+	args []any
+}
+
+// arg adds v to the query's args and returns its placeholder.
+func (q *queryArgs) arg(v any) string {
+	q.args = append(q.args, v)
+	return fmt.Sprintf("?%d", len(q.args))
+}
+
+// filterClauses returns the WHERE conditions for every predicate of filter
+// that ready and blocked queries share, sortKey being the order used to
+// resume after filter.After. Only placeholder references are interpolated,
+// never user-supplied values.
+func (q *queryArgs) filterClauses(filter types.WorkFilter, sortKey string) string {
+	var clauses strings.Builder
+	if filter.Status != nil {
+		fmt.Fprintf(&clauses, " AND i.status = %s", q.arg(string(*filter.Status)))
+	}
+	if filter.IssueType != nil {
+		fmt.Fprintf(&clauses, " AND i.issue_type = %s", q.arg(string(*filter.IssueType)))
+	}
+	if filter.Priority != nil {
+		fmt.Fprintf(&clauses, " AND i.priority = %s", q.arg(int64(*filter.Priority)))
+	}
+	if filter.ParentID != "" {
+		fmt.Fprintf(&clauses, ` AND EXISTS (SELECT 1 FROM dependencies p
+    WHERE p.issue_id = i.id AND p.type = 'parent-child' AND p.depends_on_id = %s)`, q.arg(filter.ParentID))
+	}
+	for _, label := range filter.Labels {
+		fmt.Fprintf(&clauses,
+			" AND EXISTS (SELECT 1 FROM issue_labels l WHERE l.issue_id = i.id AND l.label = %s)", q.arg(label))
+	}
+	if filter.After != "" {
+		fmt.Fprintf(&clauses, " AND (%s) > (SELECT %s FROM issues c WHERE c.id = %s)",
+			fmt.Sprintf(sortKey, "i"), fmt.Sprintf(sortKey, "c"), q.arg(filter.After))
+	}
+	return clauses.String()
+}
+
+// page returns the LIMIT and OFFSET clause for filter.
+func (q *queryArgs) page(filter types.WorkFilter) string {
 	limit := filter.Limit
 	if limit <= 0 {
 		limit = defaultWorkLimit
 	}
+	return fmt.Sprintf("LIMIT %s OFFSET %s", q.arg(int64(limit)), q.arg(int64(max(filter.Offset, 0))))
+}
 
+// GetReadyWork returns issues that are ready to work on (not blocked).
+// Results are sorted according to the filter's SortPolicy (hybrid, priority, or oldest).
+// Every filter is applied in SQL before the page is cut.
+func (s *Store) GetReadyWork(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error) {
 	// Default to hybrid sort policy if not specified
 	sortPolicy := filter.SortPolicy
 	if sortPolicy == "" || !sortPolicy.IsValid() {
 		sortPolicy = types.SortPolicyHybrid
 	}
+	sortKey := readySortKeys[sortPolicy]
 
-	var rows []*db.Issue
-	var err error
-
-	// Fetch issues using the appropriate sort query
-	switch sortPolicy {
-	case types.SortPolicyPriority:
-		rows, err = s.queries.GetReadyIssuesPriority(ctx, db.GetReadyIssuesPriorityParams{
-			ProjectID: filter.ProjectID,
-			Limit:     int64(limit),
-		})
-	case types.SortPolicyOldest:
-		rows, err = s.queries.GetReadyIssuesOldest(ctx, db.GetReadyIssuesOldestParams{
-			ProjectID: filter.ProjectID,
-			Limit:     int64(limit),
-		})
-	default: // SortPolicyHybrid
-		rows, err = s.queries.GetReadyIssuesHybrid(ctx, db.GetReadyIssuesHybridParams{
-			ProjectID: filter.ProjectID,
-			Limit:     int64(limit),
-		})
-	}
+	// Only 'blocks' dependencies are blocking; parent-child is organizational only.
+	q := &queryArgs{}
+	query := `
+SELECT i.id, i.project_id, i.title, i.description, i.status, i.priority,
+       i.issue_type, i.ai_session_id, i.external_ref, i.rank,
+       i.created_at, i.updated_at, i.closed_at, i.close_reason, i.version
+FROM issues i
+WHERE i.project_id = ` + q.arg(filter.ProjectID) + `
+  AND i.status IN ('open', 'in_progress')
+  AND NOT EXISTS (
+    SELECT 1 FROM dependencies d
+    JOIN issues blocker ON d.depends_on_id = blocker.id
+    WHERE d.issue_id = i.id AND d.type = 'blocks' AND blocker.status != 'closed'
+  )` + q.filterClauses(filter, sortKey) + `
+ORDER BY ` + fmt.Sprintf(sortKey, "i") + `
+` + q.page(filter)
 
+	rows, err := s.db.QueryContext(ctx, query, q.args...)
 	if err != nil {
 		return nil, fmt.Errorf("get ready work: %w", err)
 	}
+	defer rows.Close()
 
-	// Apply additional in-memory filters
-	issues := make([]*types.Issue, 0, len(rows))
-	for _, row := range rows {
-		issue := dbIssueToType(row)
-
-		if filter.IssueType != nil && issue.IssueType != *filter.IssueType {
-			continue
-		}
-		if filter.Priority != nil && issue.Priority != *filter.Priority {
-			continue
+	issues := []*types.Issue{}
+	for rows.Next() {
+		var row db.Issue
+		if err := rows.Scan(
+			&row.ID, &row.ProjectID, &row.Title, &row.Description,
+			&row.Status, &row.Priority, &row.IssueType,
+			&row.AiSessionID, &row.ExternalRef, &row.Rank,
+			&row.CreatedAt, &row.UpdatedAt, &row.ClosedAt, &row.CloseReason, &row.Version,
+		); err != nil {
+			return nil, fmt.Errorf("scan ready issue: %w", err)
 		}
-		if filter.Status != nil && issue.Status != *filter.Status {
-			continue
-		}
-
-		issues = append(issues, issue)
+		issues = append(issues, dbIssueToType(&row))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get ready work rows: %w", err)
 	}
-
 	return issues, nil
 }
 
 // GetBlockedIssues returns issues that are blocked by other issues.
 // For each blocked issue, it also fetches the IDs of the issues blocking it.
 func (s *Store) GetBlockedIssues(ctx context.Context, filter types.WorkFilter) ([]*types.BlockedIssue, error) {
-	limit := filter.Limit
-	if limit <= 0 {
-		limit = defaultWorkLimit
-	}
+	// Only 'blocks' dependencies are blocking; parent-child is organizational only.
+	q := &queryArgs{}
+	query := `
+SELECT i.id, i.project_id, i.title, i.description, i.status, i.priority,
+       i.issue_type, i.ai_session_id, i.external_ref, i.rank,
+       i.created_at, i.updated_at, i.closed_at, i.close_reason, i.version,
+       COUNT(blocker.id)
+FROM issues i
+JOIN dependencies d ON d.issue_id = i.id AND d.type = 'blocks'
+JOIN issues blocker ON d.depends_on_id = blocker.id AND blocker.status != 'closed'
+WHERE i.project_id = ` + q.arg(filter.ProjectID) + `
+  AND i.status != 'closed'` + q.filterClauses(filter, blockedSortKey) + `
+GROUP BY i.id
+ORDER BY ` + fmt.Sprintf(blockedSortKey, "i") + `
+` + q.page(filter)
 
-	rows, err := s.queries.GetBlockedIssuesInProject(ctx, db.GetBlockedIssuesInProjectParams{
-		ProjectID: filter.ProjectID,
-		Limit:     int64(limit),
-	})
+	rows, err := s.db.QueryContext(ctx, query, q.args...)
 	if err != nil {
 		return nil, fmt.Errorf("get blocked issues: %w", err)
 	}
+	defer rows.Close()
 
-	issues := make([]*types.BlockedIssue, 0, len(rows))
-	for _, row := range rows {
-		// Get blocking issue IDs for this blocked issue
-		blockingIDs := []string{}
-		blockingIssues, _ := s.queries.GetBlockingIssues(ctx, row.ID)
-		for _, bi := range blockingIssues {
-			blockingIDs = append(blockingIDs, bi.ID)
+	issues := []*types.BlockedIssue{}
+	for rows.Next() {
+		var (
+			row   db.Issue
+			count int64
+		)
+		if err := rows.Scan(
+			&row.ID, &row.ProjectID, &row.Title, &row.Description,
+			&row.Status, &row.Priority, &row.IssueType,
+			&row.AiSessionID, &row.ExternalRef, &row.Rank,
+			&row.CreatedAt, &row.UpdatedAt, &row.ClosedAt, &row.CloseReason, &row.Version,
+			&count,
+		); err != nil {
+			return nil, fmt.Errorf("scan blocked issue: %w", err)
 		}
+		issues = append(issues, &types.BlockedIssue{
+			Issue:          *dbIssueToType(&row),
+			BlockedByCount: int(count),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get blocked issues rows: %w", err)
+	}
+	rows.Close()
 
-		blocked := &types.BlockedIssue{
-			Issue: types.Issue{
-				ID:          row.ID,
-				ProjectID:   row.ProjectID,
-				Title:       row.Title,
-				Description: fromNullString(row.Description),
-				Status:      types.Status(row.Status),
-				Priority:    int(row.Priority),
-				IssueType:   types.IssueType(row.IssueType),
-				ExternalRef: fromNullString(row.ExternalRef),
-				CreatedAt:   row.CreatedAt,
-				UpdatedAt:   row.UpdatedAt,
-				ClosedAt:    fromNullTime(row.ClosedAt),
-				CloseReason: fromNullString(row.CloseReason),
-			},
-			BlockedByCount: int(row.BlockedByCount),
-			BlockedBy:      blockingIDs,
+	// Get blocking issue IDs for each blocked issue
+	for _, blocked := range issues {
+		blockingIssues, err := s.queries.GetBlockingIssues(ctx, blocked.ID)
+		if err != nil {
+			return nil, fmt.Errorf("get blocking issues: %w", err)
+		}
+		blocked.BlockedBy = make([]string, len(blockingIssues))
+		for i, bi := range blockingIssues {
+			blocked.BlockedBy[i] = bi.ID
 		}
-		issues = append(issues, blocked)
 	}
 
 	return issues, nil
@@ -3,90 +3,174 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/sentiolabs/arc/internal/storage/sqlite/db"
 	"github.com/sentiolabs/arc/internal/types"
 )
 
-// rebuildFTSForIssue rebuilds the FTS5 index entry for a single issue.
-// It aggregates title, description, all comments/plans, and labels into one row.
-// Errors are logged but not propagated — FTS sync is best-effort.
-func (s *Store) rebuildFTSForIssue(ctx context.Context, issueID string) {
-	issue, err := s.queries.GetIssue(ctx, issueID)
-	if err != nil {
-		log.Printf("fts: failed to get issue %s for reindex: %v", issueID, err)
-		return
+// ftsIndexVersion identifies the shape of the FTS5 index: its columns and
+// what each row aggregates. Bump it whenever either changes so the index is
+// rebuilt the next time the store opens.
+const ftsIndexVersion = "3"
+
+// ftsVersionKey is the global_config key holding the version of the index
+// on disk. Migrations that change the index clear it.
+const ftsVersionKey = "fts_index_version"
+
+// ftsRebuildBatch is the number of issues indexed per statement during a
+// rebuild.
+const ftsRebuildBatch = 1000
+
+// ftsDocuments selects the FTS5 row of each issue: its title and
+// description together with all of its comments, labels and the text of
+// its linked plans, under the issue's key in issue_fts_keys. The implicit
+// rowids of issues are not stable across table rebuilds, so they cannot key
+// the index. It must match the trigger bodies of migration 030.
+const ftsDocuments = `
+	SELECT k.key, i.id, i.title, COALESCE(i.description, ''),
+	       COALESCE((SELECT group_concat(c.text, char(10)) FROM comments c WHERE c.issue_id = i.id), ''),
+	       COALESCE((SELECT group_concat(l.label, ' ') FROM issue_labels l WHERE l.issue_id = i.id), ''),
+	       COALESCE((SELECT group_concat(p.search_text, char(10))
+	                 FROM issue_plans ip JOIN plans p ON p.id = ip.plan_id
+	                 WHERE ip.issue_id = i.id), '')
+	FROM issues i
+	JOIN issue_fts_keys k ON k.issue_id = i.id`
+
+// ftsRank scores an FTS5 match: title terms outrank description terms,
+// which outrank labels, comments and then linked plans. Lower is better.
+const ftsRank = `bm25(issues_fts, 0.0, 10.0, 5.0, 2.0, 3.0, 1.0)`
+
+// ensureSearchIndex rebuilds the FTS5 index unless its version marker shows
+// it is current. Triggers keep a current index in step with every write, in
+// the writing transaction, so a rebuild is only needed once the index
+// changes shape or the marker is cleared.
+func (s *Store) ensureSearchIndex(ctx context.Context) error {
+	version, err := s.queries.GetGlobalConfig(ctx, ftsVersionKey)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("read search index version: %w", err)
+	}
+	if version.Valid && version.String == ftsIndexVersion {
+		return nil
 	}
 
-	description := fromNullString(issue.Description)
-
-	// Delete existing entry then insert new one.
-	s.deleteFTSForIssue(ctx, issueID)
-
-	_, err = s.db.ExecContext(ctx,
-		`INSERT INTO issues_fts(id, title, description)
-		 VALUES (?, ?, ?)`,
-		issueID, issue.Title, description,
-	)
+	count, err := s.RebuildSearchIndex(ctx, nil)
 	if err != nil {
-		log.Printf("fts: failed to insert issue %s into FTS index: %v", issueID, err)
+		return err
 	}
+	log.Printf("fts: rebuilt index for %d issues", count)
+	return nil
 }
 
-// deleteFTSForIssue removes an issue's entry from the FTS5 index.
-func (s *Store) deleteFTSForIssue(ctx context.Context, issueID string) {
-	_, err := s.db.ExecContext(ctx,
-		`DELETE FROM issues_fts WHERE id = ?`, issueID,
-	)
+// RebuildSearchIndex recreates the FTS5 index from the issues, comments,
+// labels and linked plans in one transaction and returns the number of
+// issues indexed. If progress is not nil it is called after each batch with
+// the number of issues indexed so far and the total. Use it to recover from
+// a corrupted index.
+func (s *Store) RebuildSearchIndex(ctx context.Context, progress func(done, total int)) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		log.Printf("fts: failed to delete issue %s from FTS index: %v", issueID, err)
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := recreateFTSTable(ctx, tx); err != nil {
+		return 0, err
+	}
+	if err := syncFTSKeys(ctx, tx); err != nil {
+		return 0, err
 	}
-}
 
-// populateFTS rebuilds the entire FTS5 index from scratch.
-// Called on startup to reconcile the index against the issues table.
-func (s *Store) populateFTS(ctx context.Context) {
-	// Always recreate from scratch — this handles both clean state and corruption.
-	s.recreateFTSTable(ctx)
+	var total int
+	if err := tx.QueryRowContext(ctx, `SELECT count(*) FROM issues`).Scan(&total); err != nil {
+		return 0, fmt.Errorf("count issues: %w", err)
+	}
 
-	// Bulk-insert all issues into the fresh FTS table.
-	result, err := s.db.ExecContext(ctx,
-		`INSERT INTO issues_fts(id, title, description)
-		 SELECT id, title, COALESCE(description, '') FROM issues`)
+	var (
+		done int
+		last int64
+	)
+	for {
+		result, err := tx.ExecContext(ctx,
+			`INSERT INTO issues_fts(rowid, id, title, description, comments, labels, plans)`+ftsDocuments+`
+			 WHERE k.key > ? ORDER BY k.key LIMIT ?`,
+			last, ftsRebuildBatch)
+		if err != nil {
+			return 0, fmt.Errorf("index issues: %w", err)
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("index issues: %w", err)
+		}
+		if n == 0 {
+			break
+		}
+		done += int(n)
+		if err := tx.QueryRowContext(ctx, `SELECT max(rowid) FROM issues_fts`).Scan(&last); err != nil {
+			return 0, fmt.Errorf("index issues: %w", err)
+		}
+		if progress != nil {
+			progress(done, total)
+		}
+	}
+
+	err = s.queries.WithTx(tx).SetGlobalConfig(ctx, db.SetGlobalConfigParams{
+		Key:   ftsVersionKey,
+		Value: toNullString(ftsIndexVersion),
+	})
 	if err != nil {
-		log.Printf("fts: failed to populate FTS index: %v", err)
-		return
+		return 0, fmt.Errorf("record search index version: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit search index: %w", err)
 	}
+	return done, nil
+}
 
-	count, _ := result.RowsAffected()
-	log.Printf("fts: populated index for %d issues", count)
+// syncFTSKeys gives every issue a key in issue_fts_keys and drops the keys
+// of issues that no longer exist, in case rows changed behind the triggers'
+// back.
+func syncFTSKeys(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM issue_fts_keys WHERE issue_id NOT IN (SELECT id FROM issues)`); err != nil {
+		return fmt.Errorf("prune search index keys: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT OR IGNORE INTO issue_fts_keys (issue_id) SELECT id FROM issues`); err != nil {
+		return fmt.Errorf("add search index keys: %w", err)
+	}
+	return nil
 }
 
 // recreateFTSTable drops and recreates the FTS5 virtual table.
-// Used to recover from a corrupted index (e.g. after table recreation in migrations).
 // When an FTS5 table is corrupted, DROP TABLE can also fail, so we manually
-// remove the shadow tables as a fallback.
-func (s *Store) recreateFTSTable(ctx context.Context) {
-	if _, err := s.db.ExecContext(ctx, `DROP TABLE IF EXISTS issues_fts`); err != nil {
+// remove the shadow tables as a fallback. The triggers that maintain the
+// index refer to it by name and survive the swap.
+func recreateFTSTable(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS issues_fts`); err != nil {
 		log.Printf("fts: DROP TABLE failed, removing shadow tables manually: %v", err)
 		// FTS5 shadow tables follow the pattern <table>_<suffix>
 		for _, suffix := range []string{"content", "docsize", "config", "data", "idx"} {
-			if _, dropErr := s.db.ExecContext(ctx, `DROP TABLE IF EXISTS issues_fts_`+suffix); dropErr != nil {
+			if _, dropErr := tx.ExecContext(ctx, `DROP TABLE IF EXISTS issues_fts_`+suffix); dropErr != nil {
 				log.Printf("fts: failed to drop shadow table issues_fts_%s: %v", suffix, dropErr)
 			}
 		}
 		// Try dropping the virtual table again now that shadow tables are gone
-		if _, dropErr := s.db.ExecContext(ctx, `DROP TABLE IF EXISTS issues_fts`); dropErr != nil {
+		if _, dropErr := tx.ExecContext(ctx, `DROP TABLE IF EXISTS issues_fts`); dropErr != nil {
 			log.Printf("fts: second DROP TABLE attempt also failed: %v", dropErr)
 		}
 	}
 	createSQL := `CREATE VIRTUAL TABLE IF NOT EXISTS issues_fts
-		USING fts5(id, title, description, content=issues, content_rowid=rowid)`
-	if _, err := s.db.ExecContext(ctx, createSQL); err != nil {
-		log.Printf("fts: failed to recreate FTS table: %v", err)
+		USING fts5(id UNINDEXED, title, description, comments, labels, plans)`
+	if _, err := tx.ExecContext(ctx, createSQL); err != nil {
+		return fmt.Errorf("recreate FTS table: %w", err)
 	}
+	return nil
 }
 
 // searchIssuesFTS searches for issues using FTS5 full-text search with BM25 ranking.
@@ -99,8 +183,8 @@ func (s *Store) searchIssuesFTS(
 	rows, err := s.db.QueryContext(ctx, `
 		SELECT i.id, i.project_id, i.title, i.description, i.status, i.priority,
 		       i.issue_type, i.external_ref, i.rank,
-		       i.created_at, i.updated_at, i.closed_at, i.close_reason,
-		       bm25(issues_fts, 0.0, 10.0, 5.0) as relevance
+		       i.created_at, i.updated_at, i.closed_at, i.close_reason, i.version,
+		       `+ftsRank+` as relevance
 		FROM issues_fts
 		JOIN issues i ON i.id = issues_fts.id
 		WHERE i.project_id = ?
@@ -138,7 +222,7 @@ func (s *Store) searchIssuesLIKE(
 	rows, err := s.db.QueryContext(ctx, `
 		SELECT id, project_id, title, description, status, priority,
 		       issue_type, external_ref, rank,
-		       created_at, updated_at, closed_at, close_reason
+		       created_at, updated_at, closed_at, close_reason, version
 		FROM issues
 		WHERE project_id = ?
 		  AND (title LIKE ? OR description LIKE ?)
@@ -165,6 +249,120 @@ func (s *Store) searchIssuesLIKE(
 	return issues, nil
 }
 
+// snippetOpen and snippetClose delimit matched terms in the snippets
+// SearchIssues reads back. They cannot occur in issue text, so a snippet
+// containing snippetOpen is known to come from a field that matched.
+const (
+	snippetOpen  = "\x02"
+	snippetClose = "\x03"
+)
+
+// searchFields are the FTS5 columns a search hit can match, by column
+// index, in order of preference.
+var searchFields = []struct {
+	column int
+	field  types.SearchField
+}{
+	{1, types.SearchFieldTitle},
+	{2, types.SearchFieldDescription},
+	{3, types.SearchFieldComments},
+	{4, types.SearchFieldLabels},
+	{5, types.SearchFieldPlans},
+}
+
+// SearchIssues runs a ranked full-text search over issues, their comments,
+// labels and linked plans, in the given projects or in all of them.
+func (s *Store) SearchIssues(ctx context.Context, filter types.SearchFilter) ([]*types.SearchHit, error) {
+	hits := []*types.SearchHit{}
+	ftsQuery := PrepareSearchQuery(filter.Query)
+	if ftsQuery == "" {
+		return hits, nil
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	var q queryArgs
+	snippets := make([]string, len(searchFields))
+	for i, f := range searchFields {
+		snippets[i] = fmt.Sprintf("snippet(issues_fts, %d, %s, %s, '…', 16)",
+			f.column, q.arg(snippetOpen), q.arg(snippetClose))
+	}
+	where := "issues_fts MATCH " + q.arg(ftsQuery)
+	if len(filter.ProjectIDs) > 0 {
+		placeholders := make([]string, len(filter.ProjectIDs))
+		for i, id := range filter.ProjectIDs {
+			placeholders[i] = q.arg(id)
+		}
+		where += " AND i.project_id IN (" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT i.id, i.project_id, i.title, i.description, i.status, i.priority,
+		       i.issue_type, i.external_ref, i.rank,
+		       i.created_at, i.updated_at, i.closed_at, i.close_reason, i.version,
+		       `+ftsRank+` AS relevance, `+strings.Join(snippets, ", ")+`
+		FROM issues_fts
+		JOIN issues i ON i.id = issues_fts.id
+		WHERE `+where+`
+		ORDER BY relevance, i.id
+		LIMIT `+q.arg(limit)+` OFFSET `+q.arg(max(filter.Offset, 0)),
+		q.args...)
+	if err != nil {
+		return nil, fmt.Errorf("search issues: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			issue       types.Issue
+			description sql.NullString
+			externalRef sql.NullString
+			closedAt    sql.NullTime
+			closeReason sql.NullString
+			relevance   float64
+			fields      = make([]string, len(searchFields))
+		)
+		dest := []any{
+			&issue.ID, &issue.ProjectID, &issue.Title, &description,
+			&issue.Status, &issue.Priority, &issue.IssueType,
+			&externalRef, &issue.Rank,
+			&issue.CreatedAt, &issue.UpdatedAt, &closedAt, &closeReason, &issue.Version,
+			&relevance,
+		}
+		for i := range fields {
+			dest = append(dest, &fields[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("scan search hit: %w", err)
+		}
+		issue.Description = fromNullString(description)
+		issue.ExternalRef = fromNullString(externalRef)
+		issue.CloseReason = fromNullString(closeReason)
+		issue.ClosedAt = fromNullTime(closedAt)
+
+		hit := &types.SearchHit{Issue: &issue, Score: -relevance}
+		for i, snippet := range fields {
+			if strings.Contains(snippet, snippetOpen) {
+				hit.Field, hit.Snippet = searchFields[i].field, highlightSnippet(snippet)
+				break
+			}
+		}
+		hits = append(hits, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate search hits: %w", err)
+	}
+	return hits, nil
+}
+
+// highlightSnippet replaces the internal match delimiters of an FTS5
+// snippet with the public highlight markers.
+func highlightSnippet(snippet string) string {
+	return strings.NewReplacer(snippetOpen, types.HighlightStart, snippetClose, types.HighlightEnd).Replace(snippet)
+}
+
 // scanIssueRow scans a row from a search query into a types.Issue.
 // If hasRelevance is true, it also scans a trailing relevance float64 column.
 func scanIssueRow(rows *sql.Rows, hasRelevance bool) (*types.Issue, error) {
@@ -180,7 +378,7 @@ func scanIssueRow(rows *sql.Rows, hasRelevance bool) (*types.Issue, error) {
 		&issue.ID, &issue.ProjectID, &issue.Title, &description,
 		&issue.Status, &issue.Priority, &issue.IssueType,
 		&externalRef, &issue.Rank,
-		&issue.CreatedAt, &issue.UpdatedAt, &closedAt, &closeReason,
+		&issue.CreatedAt, &issue.UpdatedAt, &closedAt, &closeReason, &issue.Version,
 	}
 
 	if hasRelevance {
@@ -230,7 +428,8 @@ func PrepareSearchQuery(input string) string {
 }
 
 // parseNextToken extracts the next search token from the input.
-// Quoted phrases are returned as-is; unquoted terms get a `*` suffix.
+// Quoted phrases are returned as-is; unquoted terms get a `*` suffix,
+// and are quoted first if they are not FTS5 barewords (e.g. "ar-12").
 // Returns the token and the remaining unparsed input.
 func parseNextToken(s string) (token, rest string) {
 	if s[0] == '"' {
@@ -239,9 +438,15 @@ func parseNextToken(s string) (token, rest string) {
 
 	end := strings.IndexAny(s, " \t\"")
 	if end == -1 {
-		return s + "*", ""
+		end = len(s)
+	}
+	term := s[:end]
+	if strings.ContainsFunc(term, func(r rune) bool {
+		return r < utf8.RuneSelf && r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		term = `"` + term + `"`
 	}
-	return s[:end] + "*", s[end:]
+	return term + "*", s[end:]
 }
 
 // parseQuotedPhrase extracts a quoted phrase from the input.
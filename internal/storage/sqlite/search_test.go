@@ -2,6 +2,7 @@ package sqlite_test
 
 import (
 	"context"
+	"path/filepath"
 	"testing"
 
 	"github.com/sentiolabs/arc/internal/storage/sqlite"
@@ -22,6 +23,7 @@ func TestPrepareSearchQuery(t *testing.T) {
 		{"extra whitespace", "  auth   deploy  ", "auth* deploy*"},
 		{"unclosed quote", `"fix bug`, `"fix bug`},
 		{"single character", "a", "a*"},
+		{"punctuation", "ar-12 v1.2", `"ar-12"* "v1.2"*`},
 	}
 
 	for _, tt := range tests {
@@ -201,7 +203,7 @@ func TestFTSStemming(t *testing.T) {
 	}
 }
 
-func TestFTSCommentSearchNotIndexed(t *testing.T) {
+func TestFTSCommentSearch(t *testing.T) {
 	store, cleanup := setupTestStore(t)
 	defer cleanup()
 
@@ -212,27 +214,43 @@ func TestFTSCommentSearchNotIndexed(t *testing.T) {
 
 	// Add a comment with unique searchable text
 	issue := setupTestIssue(t, store, ws, "Another issue")
-	_, err := store.AddComment(ctx, issue.ID, "author", "The frobnicator module needs refactoring")
+	comment, err := store.AddComment(ctx, issue.ID, "author", "The frobnicator module needs refactoring")
 	if err != nil {
 		t.Fatalf("failed to add comment: %v", err)
 	}
 
-	// FTS only indexes title and description (simplified in migration 010).
-	// Comments are not indexed, so searching for comment-only text returns no results.
-	results, err := store.ListIssues(ctx, types.IssueFilter{
-		ProjectID: ws.ID,
-		Query:     "frobnicator",
-	})
-	if err != nil {
-		t.Fatalf("search failed: %v", err)
+	count := func(query string) int {
+		t.Helper()
+		results, err := store.ListIssues(ctx, types.IssueFilter{ProjectID: ws.ID, Query: query})
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		return len(results)
 	}
 
-	if len(results) != 0 {
-		t.Errorf("expected 0 results for comment-only text (not indexed), got %d", len(results))
+	if n := count("frobnicator"); n != 1 {
+		t.Errorf("expected 1 result for comment text, got %d", n)
+	}
+
+	// Edits and deletes keep the index current.
+	if err := store.UpdateComment(ctx, comment.ID, "The widget module needs refactoring"); err != nil {
+		t.Fatalf("failed to update comment: %v", err)
+	}
+	if n := count("frobnicator"); n != 0 {
+		t.Errorf("expected 0 results for edited-out comment text, got %d", n)
+	}
+	if n := count("widget"); n != 1 {
+		t.Errorf("expected 1 result for edited comment text, got %d", n)
+	}
+	if err := store.DeleteComment(ctx, comment.ID); err != nil {
+		t.Fatalf("failed to delete comment: %v", err)
+	}
+	if n := count("widget"); n != 0 {
+		t.Errorf("expected 0 results for deleted comment text, got %d", n)
 	}
 }
 
-func TestFTSLabelSearchNotIndexed(t *testing.T) {
+func TestFTSLabelSearch(t *testing.T) {
 	store, cleanup := setupTestStore(t)
 	defer cleanup()
 
@@ -250,8 +268,6 @@ func TestFTSLabelSearchNotIndexed(t *testing.T) {
 		t.Fatalf("failed to add label: %v", err)
 	}
 
-	// FTS only indexes title and description (simplified in migration 010).
-	// Labels are not indexed, so searching for label-only text returns no results.
 	results, err := store.ListIssues(ctx, types.IssueFilter{
 		ProjectID: ws.ID,
 		Query:     "frontend",
@@ -259,9 +275,22 @@ func TestFTSLabelSearchNotIndexed(t *testing.T) {
 	if err != nil {
 		t.Fatalf("search failed: %v", err)
 	}
+	if len(results) != 1 {
+		t.Errorf("expected 1 result for label text, got %d", len(results))
+	}
 
+	if err := store.RemoveLabelFromIssue(ctx, issue.ID, "frontend", "test-actor"); err != nil {
+		t.Fatalf("failed to remove label: %v", err)
+	}
+	results, err = store.ListIssues(ctx, types.IssueFilter{
+		ProjectID: ws.ID,
+		Query:     "frontend",
+	})
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
 	if len(results) != 0 {
-		t.Errorf("expected 0 results for label-only text (not indexed), got %d", len(results))
+		t.Errorf("expected 0 results after removing the label, got %d", len(results))
 	}
 }
 
@@ -285,8 +314,8 @@ func TestFTSPlanSearchNotIndexed(t *testing.T) {
 		t.Fatalf("failed to create plan: %v", err)
 	}
 
-	// FTS only indexes issue title and description.
-	// Plan metadata is not indexed, so searching for plan-related text returns no results.
+	// Plans are not linked to issues, so their metadata is not indexed and
+	// searching for plan-related text returns no results.
 	results, err := store.ListIssues(ctx, types.IssueFilter{
 		ProjectID: ws.ID,
 		Query:     "zigzag",
@@ -360,3 +389,105 @@ func TestFTSDeletedIssueNotSearchable(t *testing.T) {
 		t.Errorf("expected 0 results after delete, got %d", len(results))
 	}
 }
+
+func TestSearchIndexSurvivesRowidChanges(t *testing.T) {
+	ctx := context.Background()
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ws := setupTestProject(t, store)
+	first := setupTestIssue(t, store, ws, "Quokka burrow").ID
+	second := setupTestIssue(t, store, ws, "Wombat burrow").ID
+
+	// Rebuilding the issues table, as VACUUM does, may renumber its rowids.
+	// Swap them to make sure the index does not depend on them.
+	for _, stmt := range []string{
+		`UPDATE issues SET rowid = rowid + 1000000`,
+		`UPDATE issues SET rowid = 1 WHERE id = '` + second + `'`,
+		`UPDATE issues SET rowid = 2 WHERE id = '` + first + `'`,
+	} {
+		if _, err := store.DB().ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	if err := store.UpdateIssue(ctx, first, map[string]any{"title": "Quokka den"}, 0, "tester"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+
+	for query, want := range map[string]int{"quokka": 1, "wombat": 1, "burrow": 1, "den": 1} {
+		results, err := store.ListIssues(ctx, types.IssueFilter{ProjectID: ws.ID, Query: query})
+		if err != nil {
+			t.Fatalf("search %q failed: %v", query, err)
+		}
+		if len(results) != want {
+			t.Errorf("search %q = %d results, want %d", query, len(results), want)
+		}
+	}
+}
+
+func TestSearchIndexRebuild(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	open := func() *sqlite.Store {
+		t.Helper()
+		store, err := sqlite.New(dbPath)
+		if err != nil {
+			t.Fatalf("failed to open store: %v", err)
+		}
+		return store
+	}
+	count := func(store *sqlite.Store, projectID string) int {
+		t.Helper()
+		results, err := store.ListIssues(ctx, types.IssueFilter{ProjectID: projectID, Query: "reindexed"})
+		if err != nil {
+			t.Fatalf("search failed: %v", err)
+		}
+		return len(results)
+	}
+
+	store := open()
+	ws := setupTestProject(t, store)
+	setupTestIssue(t, store, ws, "Reindexed task")
+	setupTestIssue(t, store, ws, "Another reindexed task")
+	if n := count(store, ws.ID); n != 2 {
+		t.Fatalf("expected 2 results, got %d", n)
+	}
+
+	// A current index is not rebuilt on open, so rows lost behind the
+	// triggers' back stay lost until a manual rebuild.
+	if _, err := store.DB().ExecContext(ctx, `DELETE FROM issues_fts`); err != nil {
+		t.Fatalf("failed to clear index: %v", err)
+	}
+	_ = store.Close()
+	store = open()
+	if n := count(store, ws.ID); n != 0 {
+		t.Errorf("expected the index to be left alone on open, got %d results", n)
+	}
+
+	var calls [][2]int
+	indexed, err := store.RebuildSearchIndex(ctx, func(done, total int) {
+		calls = append(calls, [2]int{done, total})
+	})
+	if err != nil {
+		t.Fatalf("RebuildSearchIndex failed: %v", err)
+	}
+	if indexed != 2 || len(calls) != 1 || calls[0] != [2]int{2, 2} {
+		t.Errorf("RebuildSearchIndex = %d with progress %v, want 2 with [[2 2]]", indexed, calls)
+	}
+	if n := count(store, ws.ID); n != 2 {
+		t.Errorf("expected 2 results after rebuild, got %d", n)
+	}
+
+	// Clearing the version marker forces a rebuild on the next open.
+	if _, err := store.DB().ExecContext(ctx, `DELETE FROM issues_fts`); err != nil {
+		t.Fatalf("failed to clear index: %v", err)
+	}
+	if _, err := store.DB().ExecContext(ctx, `DELETE FROM global_config WHERE key = 'fts_index_version'`); err != nil {
+		t.Fatalf("failed to clear index version: %v", err)
+	}
+	_ = store.Close()
+	store = open()
+	defer store.Close()
+	if n := count(store, ws.ID); n != 2 {
+		t.Errorf("expected 2 results after reopening without a version marker, got %d", n)
+	}
+}
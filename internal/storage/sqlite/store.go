@@ -12,6 +12,7 @@ import (
 
 	"github.com/sentiolabs/arc/internal/storage"
 	"github.com/sentiolabs/arc/internal/storage/sqlite/db"
+	"github.com/sentiolabs/arc/internal/types"
 
 	_ "modernc.org/sqlite"
 )
@@ -21,9 +22,10 @@ const dirPermissions = 0o755
 
 // Store implements the storage.Storage interface using SQLite.
 type Store struct {
-	db      *sql.DB
-	queries *db.Queries
-	path    string
+	db        *sql.DB
+	queries   *db.Queries
+	path      string
+	publisher storage.Publisher
 }
 
 // New creates a new SQLite store at the given path.
@@ -75,8 +77,11 @@ func New(path string) (*Store, error) {
 	// Clean up orphaned workspaces from before foreign keys were enforced
 	_, _ = sqlDB.Exec("DELETE FROM workspaces WHERE project_id NOT IN (SELECT id FROM projects)")
 
-	// Populate FTS5 search index
-	store.populateFTS(context.Background())
+	// Rebuild the FTS5 search index if it is missing or out of date
+	if err := store.ensureSearchIndex(context.Background()); err != nil {
+		_ = sqlDB.Close()
+		return nil, fmt.Errorf("init search index: %w", err)
+	}
 
 	return store, nil
 }
@@ -131,5 +136,31 @@ func (s *Store) Path() string {
 	return s.path
 }
 
-// Ensure Store implements storage.Storage
-var _ storage.Storage = (*Store)(nil)
+// SetPublisher registers the receiver of live change notifications.
+// A nil publisher disables publishing.
+func (s *Store) SetPublisher(p storage.Publisher) {
+	s.publisher = p
+}
+
+// publish forwards a change notification to the registered publisher, if any.
+func (s *Store) publish(ev types.StreamEvent) {
+	if s.publisher != nil {
+		s.publisher.Publish(ev)
+	}
+}
+
+// issueProjectID returns the project an issue belongs to, or "" if the
+// issue cannot be found. Used to scope change notifications.
+func (s *Store) issueProjectID(ctx context.Context, issueID string) string {
+	row, err := s.queries.GetIssue(ctx, issueID)
+	if err != nil {
+		return ""
+	}
+	return row.ProjectID
+}
+
+// Ensure Store implements storage.Storage and storage.Notifier
+var (
+	_ storage.Storage  = (*Store)(nil)
+	_ storage.Notifier = (*Store)(nil)
+)
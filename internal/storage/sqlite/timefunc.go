@@ -0,0 +1,55 @@
+// Package sqlite implements the storage interface using SQLite.
+// This file registers the SQL function used to compare stored timestamps.
+package sqlite
+
+import (
+	"database/sql/driver"
+	"strings"
+	"time"
+
+	"modernc.org/sqlite"
+)
+
+// storedTimeLayouts are the layouts timestamps are stored in: the driver
+// writes time.Time values with Time.String, in the writer's time zone, and
+// older rows and imports may hold SQLite's own formats.
+var storedTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+}
+
+// init registers unixnano(t), which returns a stored timestamp as Unix
+// nanoseconds, or NULL when it cannot be parsed. Stored timestamps carry the
+// time zone of whoever wrote them, so they cannot be compared as text;
+// queries compare unixnano(column) with a bound time.Time.UnixNano instead.
+func init() {
+	sqlite.MustRegisterDeterministicScalarFunction("unixnano", 1,
+		func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+			switch v := args[0].(type) {
+			case string:
+				if t, ok := parseStoredTime(v); ok {
+					return t.UnixNano(), nil
+				}
+			case time.Time:
+				return v.UnixNano(), nil
+			}
+			return nil, nil
+		})
+}
+
+// parseStoredTime parses a timestamp in any of storedTimeLayouts.
+func parseStoredTime(s string) (time.Time, bool) {
+	// Time.String appends the monotonic clock reading, e.g. " m=+0.001".
+	if i := strings.Index(s, " m="); i > 0 {
+		s = s[:i]
+	}
+	for _, layout := range storedTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,249 @@
+package sqlite
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/project"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// webhookSecretBytes is the entropy of generated webhook secrets.
+const webhookSecretBytes = 32
+
+const webhookColumns = `id, project_id, url, secret, event_types, active, created_at`
+
+const deliveryColumns = `id, webhook_id, event_type, payload, status, attempts, next_attempt_at,
+	last_attempt_at, response_status, last_error, created_at, delivered_at`
+
+// CreateWebhook registers a webhook. A random secret is generated when none
+// is provided; the caller sees it in wh.Secret.
+func (s *Store) CreateWebhook(ctx context.Context, wh *types.Webhook) error {
+	if err := wh.Validate(); err != nil {
+		return fmt.Errorf("validate webhook: %w", err)
+	}
+	if wh.ID == "" {
+		wh.ID = project.GenerateProjectID("wh", wh.ProjectID+wh.URL)
+	}
+	if wh.Secret == "" {
+		buf := make([]byte, webhookSecretBytes)
+		if _, err := rand.Read(buf); err != nil {
+			return fmt.Errorf("generate webhook secret: %w", err)
+		}
+		wh.Secret = hex.EncodeToString(buf)
+	}
+	wh.CreatedAt = time.Now()
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO webhooks (id, project_id, url, secret, event_types, active, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, wh.ID, wh.ProjectID, wh.URL, wh.Secret, strings.Join(wh.EventTypes, ","), wh.Active, wh.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("create webhook: %w", err)
+	}
+	return nil
+}
+
+// GetWebhook retrieves a webhook by ID, including its secret.
+func (s *Store) GetWebhook(ctx context.Context, id string) (*types.Webhook, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE id = ?`, id)
+	wh, err := scanWebhook(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("webhook not found: %s", id)
+		}
+		return nil, fmt.Errorf("get webhook: %w", err)
+	}
+	return wh, nil
+}
+
+// ListWebhooks returns a project's webhooks, oldest first.
+func (s *Store) ListWebhooks(ctx context.Context, projectID string) ([]*types.Webhook, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+webhookColumns+` FROM webhooks WHERE project_id = ? ORDER BY created_at, id`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := []*types.Webhook{}
+	for rows.Next() {
+		wh, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan webhook: %w", err)
+		}
+		webhooks = append(webhooks, wh)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+// DeleteWebhook removes a webhook and its delivery log.
+func (s *Store) DeleteWebhook(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete webhook: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("webhook not found: %s", id)
+	}
+	return nil
+}
+
+// CreateWebhookDelivery queues a delivery. Status defaults to pending and
+// NextAttemptAt to now.
+func (s *Store) CreateWebhookDelivery(ctx context.Context, d *types.WebhookDelivery) error {
+	now := time.Now().UTC()
+	d.CreatedAt = now
+	if d.Status == "" {
+		d.Status = types.DeliveryPending
+	}
+	if d.NextAttemptAt.IsZero() {
+		d.NextAttemptAt = now
+	}
+
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO webhook_deliveries (webhook_id, event_type, payload, status, attempts, next_attempt_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, d.WebhookID, string(d.EventType), d.Payload, d.Status, d.Attempts, d.NextAttemptAt.UTC(), now)
+	if err != nil {
+		return fmt.Errorf("create webhook delivery: %w", err)
+	}
+	d.ID, err = result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("create webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// GetWebhookDelivery retrieves a single delivery by ID.
+func (s *Store) GetWebhookDelivery(ctx context.Context, id int64) (*types.WebhookDelivery, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+deliveryColumns+` FROM webhook_deliveries WHERE id = ?`, id)
+	d, err := scanDelivery(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("webhook delivery not found: %d", id)
+		}
+		return nil, fmt.Errorf("get webhook delivery: %w", err)
+	}
+	return d, nil
+}
+
+// ListWebhookDeliveries returns a webhook's delivery log, newest first.
+func (s *Store) ListWebhookDeliveries(
+	ctx context.Context, webhookID string, limit int,
+) ([]*types.WebhookDelivery, error) {
+	return s.queryDeliveries(ctx,
+		`SELECT `+deliveryColumns+` FROM webhook_deliveries WHERE webhook_id = ? ORDER BY id DESC LIMIT ?`,
+		webhookID, limit)
+}
+
+// ListDueWebhookDeliveries returns pending deliveries whose next attempt is
+// at or before now, oldest first.
+func (s *Store) ListDueWebhookDeliveries(
+	ctx context.Context, now time.Time, limit int,
+) ([]*types.WebhookDelivery, error) {
+	return s.queryDeliveries(ctx, `
+		SELECT `+deliveryColumns+` FROM webhook_deliveries
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY next_attempt_at, id
+		LIMIT ?
+	`, types.DeliveryPending, now.UTC(), limit)
+}
+
+// UpdateWebhookDelivery records the outcome of a delivery attempt.
+func (s *Store) UpdateWebhookDelivery(ctx context.Context, d *types.WebhookDelivery) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = ?, next_attempt_at = ?, last_attempt_at = ?,
+		    response_status = ?, last_error = ?, delivered_at = ?
+		WHERE id = ?
+	`, d.Status, d.Attempts, d.NextAttemptAt.UTC(), utcNullTime(d.LastAttemptAt),
+		toNullInt64(d.ResponseStatus), toNullString(d.LastError), utcNullTime(d.DeliveredAt), d.ID)
+	if err != nil {
+		return fmt.Errorf("update webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) queryDeliveries(ctx context.Context, query string, args ...any) ([]*types.WebhookDelivery, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []*types.WebhookDelivery{}
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanWebhook(row rowScanner) (*types.Webhook, error) {
+	var (
+		wh         types.Webhook
+		eventTypes string
+	)
+	if err := row.Scan(&wh.ID, &wh.ProjectID, &wh.URL, &wh.Secret, &eventTypes, &wh.Active, &wh.CreatedAt); err != nil {
+		return nil, err
+	}
+	if eventTypes != "" {
+		wh.EventTypes = strings.Split(eventTypes, ",")
+	}
+	return &wh, nil
+}
+
+func scanDelivery(row rowScanner) (*types.WebhookDelivery, error) {
+	var (
+		d              types.WebhookDelivery
+		eventType      string
+		lastAttemptAt  sql.NullTime
+		responseStatus sql.NullInt64
+		lastError      sql.NullString
+		deliveredAt    sql.NullTime
+	)
+	err := row.Scan(&d.ID, &d.WebhookID, &eventType, &d.Payload, &d.Status, &d.Attempts, &d.NextAttemptAt,
+		&lastAttemptAt, &responseStatus, &lastError, &d.CreatedAt, &deliveredAt)
+	if err != nil {
+		return nil, err
+	}
+	d.EventType = types.StreamEventType(eventType)
+	d.LastAttemptAt = fromNullTime(lastAttemptAt)
+	d.DeliveredAt = fromNullTime(deliveredAt)
+	d.LastError = lastError.String
+	if responseStatus.Valid {
+		status := int(responseStatus.Int64)
+		d.ResponseStatus = &status
+	}
+	return &d, nil
+}
+
+// utcNullTime converts an optional time to a UTC sql.NullTime so stored
+// timestamps compare correctly as text.
+func utcNullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
@@ -0,0 +1,126 @@
+package sqlite_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+func TestWebhookCRUD(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+	proj := setupTestProject(t, store)
+
+	wh := &types.Webhook{
+		ProjectID:  proj.ID,
+		URL:        "https://example.com/hook",
+		EventTypes: []string{"issue.*", "plan.status_changed"},
+		Active:     true,
+	}
+	if err := store.CreateWebhook(ctx, wh); err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+	if wh.ID == "" || len(wh.Secret) != 64 {
+		t.Fatalf("expected generated ID and 32-byte hex secret, got id=%q secret=%q", wh.ID, wh.Secret)
+	}
+
+	got, err := store.GetWebhook(ctx, wh.ID)
+	if err != nil {
+		t.Fatalf("GetWebhook: %v", err)
+	}
+	if got.Secret != wh.Secret || len(got.EventTypes) != 2 || got.EventTypes[1] != "plan.status_changed" || !got.Active {
+		t.Errorf("GetWebhook = %+v", got)
+	}
+
+	hooks, err := store.ListWebhooks(ctx, proj.ID)
+	if err != nil || len(hooks) != 1 {
+		t.Fatalf("ListWebhooks = %d, %v; want 1", len(hooks), err)
+	}
+
+	if err := store.CreateWebhook(ctx, &types.Webhook{ProjectID: proj.ID, URL: "ftp://nope"}); err == nil {
+		t.Error("expected validation error for non-http URL")
+	}
+
+	if err := store.DeleteWebhook(ctx, wh.ID); err != nil {
+		t.Fatalf("DeleteWebhook: %v", err)
+	}
+	if _, err := store.GetWebhook(ctx, wh.ID); err == nil {
+		t.Error("expected error getting deleted webhook")
+	}
+	if err := store.DeleteWebhook(ctx, wh.ID); err == nil {
+		t.Error("expected error deleting missing webhook")
+	}
+}
+
+func TestWebhookDeliveryQueue(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+	proj := setupTestProject(t, store)
+
+	wh := &types.Webhook{ProjectID: proj.ID, URL: "http://localhost/hook", Active: true}
+	if err := store.CreateWebhook(ctx, wh); err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+
+	now := time.Now()
+	due := &types.WebhookDelivery{WebhookID: wh.ID, EventType: types.StreamIssueCreated, Payload: `{}`}
+	later := &types.WebhookDelivery{
+		WebhookID: wh.ID, EventType: types.StreamIssueClosed, Payload: `{}`, NextAttemptAt: now.Add(time.Hour),
+	}
+	for _, d := range []*types.WebhookDelivery{due, later} {
+		if err := store.CreateWebhookDelivery(ctx, d); err != nil {
+			t.Fatalf("CreateWebhookDelivery: %v", err)
+		}
+	}
+
+	list, err := store.ListDueWebhookDeliveries(ctx, now.Add(time.Second), 10)
+	if err != nil {
+		t.Fatalf("ListDueWebhookDeliveries: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != due.ID || list[0].Status != types.DeliveryPending {
+		t.Fatalf("due deliveries = %+v, want only %d", list, due.ID)
+	}
+
+	// Record a successful attempt; it must leave the queue.
+	status := http.StatusNoContent
+	attemptedAt := now.Add(time.Second)
+	due.Status = types.DeliverySucceeded
+	due.Attempts = 1
+	due.LastAttemptAt = &attemptedAt
+	due.DeliveredAt = &attemptedAt
+	due.ResponseStatus = &status
+	if err := store.UpdateWebhookDelivery(ctx, due); err != nil {
+		t.Fatalf("UpdateWebhookDelivery: %v", err)
+	}
+
+	list, err = store.ListDueWebhookDeliveries(ctx, now.Add(2*time.Hour), 10)
+	if err != nil || len(list) != 1 || list[0].ID != later.ID {
+		t.Fatalf("due deliveries after update = %+v, %v; want only %d", list, err, later.ID)
+	}
+
+	got, err := store.GetWebhookDelivery(ctx, due.ID)
+	if err != nil {
+		t.Fatalf("GetWebhookDelivery: %v", err)
+	}
+	if got.Status != types.DeliverySucceeded || got.ResponseStatus == nil || *got.ResponseStatus != status ||
+		got.DeliveredAt == nil || got.Attempts != 1 {
+		t.Errorf("GetWebhookDelivery = %+v", got)
+	}
+
+	// The log is newest first, and deleting the webhook removes it.
+	log, err := store.ListWebhookDeliveries(ctx, wh.ID, 10)
+	if err != nil || len(log) != 2 || log[0].ID != later.ID {
+		t.Fatalf("ListWebhookDeliveries = %+v, %v", log, err)
+	}
+	if err := store.DeleteWebhook(ctx, wh.ID); err != nil {
+		t.Fatalf("DeleteWebhook: %v", err)
+	}
+	if _, err := store.GetWebhookDelivery(ctx, due.ID); err == nil {
+		t.Error("expected deliveries to be deleted with their webhook")
+	}
+}
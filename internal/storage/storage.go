@@ -3,6 +3,7 @@ package storage
 
 import (
 	"context"
+	"time"
 
 	"github.com/sentiolabs/arc/internal/types"
 )
@@ -17,6 +18,8 @@ type Storage interface {
 	UpdateProject(ctx context.Context, project *types.Project) error
 	DeleteProject(ctx context.Context, id string) error
 	MergeProjects(ctx context.Context, targetID string, sourceIDs []string, actor string) (*types.MergeResult, error)
+	ExportProject(ctx context.Context, projectID string) (*types.ProjectArchive, error)
+	ImportProject(ctx context.Context, archive *types.ProjectArchive) error
 
 	// Project config (per-project key/value settings)
 	GetProjectConfig(ctx context.Context, projectID string) (map[string]string, error)
@@ -34,14 +37,36 @@ type Storage interface {
 
 	// Issues
 	CreateIssue(ctx context.Context, issue *types.Issue, actor string) error
+	// GetIssue resolves an ID an issue had before MoveIssue to the issue, as
+	// does GetIssueDetails. A merged duplicate is returned as itself.
 	GetIssue(ctx context.Context, id string) (*types.Issue, error)
 	GetIssueByExternalRef(ctx context.Context, externalRef string) (*types.Issue, error)
 	ListIssues(ctx context.Context, filter types.IssueFilter) ([]*types.Issue, error)
-	UpdateIssue(ctx context.Context, id string, updates map[string]any, actor string) error
+	// UpdateIssue applies all updates atomically and increments the issue's
+	// version. A non-zero ifVersion must match the current version, or a
+	// *types.VersionConflictError is returned and nothing is written.
+	UpdateIssue(ctx context.Context, id string, updates map[string]any, ifVersion int64, actor string) error
 	CloseIssue(ctx context.Context, id string, reason string, cascade bool, actor string) error
 	ReopenIssue(ctx context.Context, id string, actor string) error
 	DeleteIssue(ctx context.Context, id string) error
 	GetIssueDetails(ctx context.Context, id string) (*types.IssueDetails, error)
+	// MergeIssue folds a duplicate into its canonical issue in the same
+	// project: comments, labels and dependencies move across, dependents are
+	// rewired, and the duplicate is closed with a duplicates dependency on
+	// the canonical issue. Merged events are recorded on both.
+	MergeIssue(ctx context.Context, duplicateID, canonicalID, actor string) (*types.IssueMergeResult, error)
+	// MoveIssue moves an issue and its descendants to another project,
+	// reissuing their IDs under the project's prefix. Dependencies follow
+	// the new IDs, and each old ID is kept as an alias of the issue. Custom
+	// field values the project does not accept are dropped and reported.
+	MoveIssue(ctx context.Context, issueID, projectID, actor string) (*types.IssueMoveResult, error)
+	// ResolveIssueAlias returns the current ID of the issue once known as
+	// id: the issue it was moved to with MoveIssue or merged into with
+	// MergeIssue, or id itself.
+	ResolveIssueAlias(ctx context.Context, id string) (string, error)
+	// SearchIssues ranks issues by how well their title, description,
+	// comments, labels and linked plans match a full-text query.
+	SearchIssues(ctx context.Context, filter types.SearchFilter) ([]*types.SearchHit, error)
 
 	// Ready Work & Blocking
 	GetReadyWork(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error)
@@ -49,6 +74,8 @@ type Storage interface {
 	IsBlocked(ctx context.Context, issueID string) (bool, []string, error)
 
 	// Dependencies
+	// AddDependency rejects a blocks or parent-child dependency that would
+	// close a cycle with a *types.DependencyCycleError.
 	AddDependency(ctx context.Context, dep *types.Dependency, actor string) error
 	RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error
 	GetDependencies(ctx context.Context, issueID string) ([]*types.Dependency, error)
@@ -65,6 +92,10 @@ type Storage interface {
 	GetIssueLabels(ctx context.Context, issueID string) ([]string, error)
 	GetLabelsForIssues(ctx context.Context, issueIDs []string) (map[string][]string, error)
 
+	// Custom fields (definitions live in project config under field.<key>;
+	// values are set through CreateIssue and UpdateIssue)
+	GetFieldsForIssues(ctx context.Context, issueIDs []string) (map[string]map[string]string, error)
+
 	// Comments
 	AddComment(ctx context.Context, issueID, author, text string) (*types.Comment, error)
 	GetComments(ctx context.Context, issueID string) ([]*types.Comment, error)
@@ -76,6 +107,15 @@ type Storage interface {
 	GetPlan(ctx context.Context, id string) (*types.Plan, error)
 	UpdatePlanStatus(ctx context.Context, id string, status string) error
 	DeletePlan(ctx context.Context, id string) error
+	// SetPlanText stores the copy of a plan's file content that search
+	// indexes for the issues linked to it.
+	SetPlanText(ctx context.Context, id, text string) error
+
+	// Plan Links (an issue can link any number of plans and a plan any
+	// number of issues)
+	LinkIssuePlan(ctx context.Context, issueID, planID string) error
+	UnlinkIssuePlan(ctx context.Context, issueID, planID string) error
+	ListIssuePlans(ctx context.Context, issueID string) ([]*types.Plan, error)
 
 	// Plan Comments
 	CreatePlanComment(ctx context.Context, comment *types.PlanComment) error
@@ -95,8 +135,43 @@ type Storage interface {
 	ListAIAgents(ctx context.Context, sessionID string) ([]*types.AIAgent, error)
 	GetAgentSummariesForSessions(ctx context.Context, sessionIDs []string) (map[string]*types.AgentSummary, error)
 
+	// Webhooks
+	CreateWebhook(ctx context.Context, wh *types.Webhook) error
+	GetWebhook(ctx context.Context, id string) (*types.Webhook, error)
+	ListWebhooks(ctx context.Context, projectID string) ([]*types.Webhook, error)
+	DeleteWebhook(ctx context.Context, id string) error
+	CreateWebhookDelivery(ctx context.Context, d *types.WebhookDelivery) error
+	GetWebhookDelivery(ctx context.Context, id int64) (*types.WebhookDelivery, error)
+	ListWebhookDeliveries(ctx context.Context, webhookID string, limit int) ([]*types.WebhookDelivery, error)
+	ListDueWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]*types.WebhookDelivery, error)
+	UpdateWebhookDelivery(ctx context.Context, d *types.WebhookDelivery) error
+
+	// Issue templates
+	SaveIssueTemplate(ctx context.Context, t *types.IssueTemplate) error
+	GetIssueTemplate(ctx context.Context, projectID, name string) (*types.IssueTemplate, error)
+	ListIssueTemplates(ctx context.Context, projectID string) ([]*types.IssueTemplate, error)
+	ListDueIssueTemplates(ctx context.Context, now time.Time) ([]*types.IssueTemplate, error)
+	RecordIssueTemplateRun(ctx context.Context, projectID, name string, ranAt time.Time, next *time.Time) error
+	DeleteIssueTemplate(ctx context.Context, projectID, name string) error
+
+	// Project members
+	ListProjectMembers(ctx context.Context, projectID string) ([]*types.ProjectMember, error)
+	GetProjectMember(ctx context.Context, projectID, actor string) (*types.ProjectMember, error)
+	SetProjectMember(ctx context.Context, m *types.ProjectMember) error
+	RemoveProjectMember(ctx context.Context, projectID, actor string) error
+
+	// API tokens
+	CreateAPIToken(ctx context.Context, tok *types.APIToken, tokenHash string) error
+	GetAPIToken(ctx context.Context, id string) (*types.APIToken, error)
+	GetAPITokenByHash(ctx context.Context, tokenHash string) (*types.APIToken, error)
+	ListAPITokens(ctx context.Context) ([]*types.APIToken, error)
+	RevokeAPIToken(ctx context.Context, id string) error
+	TouchAPIToken(ctx context.Context, id string, at time.Time) error
+
 	// Events (audit trail)
 	GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error)
+	GetRecentEvents(ctx context.Context, projectID string, limit int) ([]*types.Event, error)
+	AddEvent(ctx context.Context, event *types.Event) error
 
 	// Statistics
 	GetStatistics(ctx context.Context, projectID string) (*types.Statistics, error)
@@ -105,3 +180,16 @@ type Storage interface {
 	Close() error
 	Path() string
 }
+
+// Publisher receives live change notifications. Backends call Publish after
+// each successful mutation; implementations must not block.
+type Publisher interface {
+	Publish(ev types.StreamEvent)
+}
+
+// Notifier is implemented by backends that can publish change
+// notifications. SetPublisher must be called before the store is shared
+// between goroutines.
+type Notifier interface {
+	SetPublisher(p Publisher)
+}
@@ -0,0 +1,1500 @@
+// Package storagetest provides a conformance suite that every storage
+// backend must pass. Backends wire it up from their own tests:
+//
+//	func TestConformance(t *testing.T) {
+//		storagetest.RunConformance(t, func(t *testing.T) storage.Storage {
+//			return newTestStore(t)
+//		})
+//	}
+//
+// The suite encodes the behavior callers rely on rather than any one
+// backend's implementation details.
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/history"
+	"github.com/sentiolabs/arc/internal/storage"
+	"github.com/sentiolabs/arc/internal/types"
+	"github.com/sentiolabs/arc/internal/undo"
+)
+
+// Factory returns a fresh, empty store. It is called once per subtest and
+// is responsible for registering any cleanup with t.
+type Factory func(t *testing.T) storage.Storage
+
+const actor = "conformance"
+
+// RunConformance runs the shared storage suite against stores built by
+// newStore.
+func RunConformance(t *testing.T, newStore Factory) {
+	t.Helper()
+
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s storage.Storage)
+	}{
+		{"Projects", testProjects},
+		{"ChildIDs", testChildIDs},
+		{"CloseWithOpenChildren", testCloseWithOpenChildren},
+		{"CascadeClose", testCascadeClose},
+		{"ReopenIssue", testReopenIssue},
+		{"ReadyWorkBlocking", testReadyWorkBlocking},
+		{"DependencyCycles", testDependencyCycles},
+		{"ReadyWorkSortPolicies", testReadyWorkSortPolicies},
+		{"WorkFilters", testWorkFilters},
+		{"MergeProjects", testMergeProjects},
+		{"MergeIssue", testMergeIssue},
+		{"MoveIssue", testMoveIssue},
+		{"MoveIssueFields", testMoveIssueFields},
+		{"LabelFilters", testLabelFilters},
+		{"IssueQueries", testIssueQueries},
+		{"UpdateIssue", testUpdateIssue},
+		{"IssueVersions", testIssueVersions},
+		{"IssueHistory", testIssueHistory},
+		{"Undo", testUndo},
+		{"Comments", testComments},
+		{"Search", testSearch},
+		{"PlanSearch", testPlanSearch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newStore(t))
+		})
+	}
+}
+
+func testProjects(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	proj := newProject(t, s, "Alpha", "alpha")
+	if proj.ID == "" {
+		t.Fatal("CreateProject did not assign an ID")
+	}
+
+	got, err := s.GetProject(ctx, proj.ID)
+	if err != nil {
+		t.Fatalf("GetProject failed: %v", err)
+	}
+	if got.Name != "Alpha" || got.Prefix != "alpha" {
+		t.Errorf("GetProject = %q/%q, want Alpha/alpha", got.Name, got.Prefix)
+	}
+	if _, err := s.GetProjectByName(ctx, "Alpha"); err != nil {
+		t.Errorf("GetProjectByName failed: %v", err)
+	}
+	if err := s.CreateProject(ctx, &types.Project{Name: "Alpha", Prefix: "dup"}); err == nil {
+		t.Error("expected error creating project with duplicate name")
+	}
+
+	issue := newIssue(t, s, proj.ID, "Doomed", 2)
+	if err := s.DeleteProject(ctx, proj.ID); err != nil {
+		t.Fatalf("DeleteProject failed: %v", err)
+	}
+	if _, err := s.GetProject(ctx, proj.ID); err == nil {
+		t.Error("expected error getting deleted project")
+	}
+	if _, err := s.GetIssue(ctx, issue.ID); err == nil {
+		t.Error("expected project's issues to be deleted with it")
+	}
+	if _, err := s.GetProject(ctx, "proj-missing"); err == nil {
+		t.Error("expected error getting unknown project")
+	}
+}
+
+func testChildIDs(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	proj := newProject(t, s, "Children", "kids")
+
+	parent := newIssue(t, s, proj.ID, "Parent", 2)
+	child1 := newChild(t, s, parent, "Child one")
+	child2 := newChild(t, s, parent, "Child two")
+	grandchild := newChild(t, s, child1, "Grandchild")
+
+	for _, c := range []struct{ got, want string }{
+		{child1.ID, parent.ID + ".1"},
+		{child2.ID, parent.ID + ".2"},
+		{grandchild.ID, parent.ID + ".1.1"},
+	} {
+		if c.got != c.want {
+			t.Errorf("child ID = %s, want %s", c.got, c.want)
+		}
+	}
+
+	deps, err := s.GetDependencies(ctx, child1.ID)
+	if err != nil {
+		t.Fatalf("GetDependencies failed: %v", err)
+	}
+	if len(deps) != 1 || deps[0].DependsOnID != parent.ID || deps[0].Type != types.DepParentChild {
+		t.Errorf("child dependencies = %+v, want one parent-child on %s", deps, parent.ID)
+	}
+
+	children, err := s.ListIssues(ctx, types.IssueFilter{ProjectID: proj.ID, ParentID: parent.ID})
+	if err != nil {
+		t.Fatalf("ListIssues failed: %v", err)
+	}
+	if got := issueIDs(children); !sameSet(got, []string{child1.ID, child2.ID}) {
+		t.Errorf("children of %s = %v, want %s and %s", parent.ID, got, child1.ID, child2.ID)
+	}
+
+	orphan := &types.Issue{ProjectID: proj.ID, Title: "Orphan", ParentID: "kids.missing"}
+	if err := s.CreateIssue(ctx, orphan, actor); err == nil {
+		t.Error("expected error creating child of unknown parent")
+	}
+}
+
+func testCloseWithOpenChildren(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	proj := newProject(t, s, "Close", "close")
+
+	parent := newIssue(t, s, proj.ID, "Parent", 2)
+	child := newChild(t, s, parent, "Child")
+
+	err := s.CloseIssue(ctx, parent.ID, "done", false, actor)
+	var openErr *types.OpenChildrenError
+	if !errors.As(err, &openErr) {
+		t.Fatalf("CloseIssue error = %v, want *types.OpenChildrenError", err)
+	}
+	if openErr.IssueID != parent.ID {
+		t.Errorf("OpenChildrenError.IssueID = %s, want %s", openErr.IssueID, parent.ID)
+	}
+	if len(openErr.Children) != 1 || openErr.Children[0].ID != child.ID {
+		t.Errorf("OpenChildrenError.Children = %+v, want [%s]", openErr.Children, child.ID)
+	}
+	if got := getIssue(t, s, parent.ID); got.Status != types.StatusOpen {
+		t.Errorf("parent status = %s, want open", got.Status)
+	}
+
+	if err := s.CloseIssue(ctx, child.ID, "done", false, actor); err != nil {
+		t.Fatalf("CloseIssue(child) failed: %v", err)
+	}
+	if err := s.CloseIssue(ctx, parent.ID, "done", false, actor); err != nil {
+		t.Fatalf("CloseIssue(parent) after closing child failed: %v", err)
+	}
+}
+
+func testCascadeClose(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	proj := newProject(t, s, "Cascade", "casc")
+
+	root := newIssue(t, s, proj.ID, "Root", 2)
+	mid := newChild(t, s, root, "Middle")
+	leaf := newChild(t, s, mid, "Leaf")
+	done := newChild(t, s, root, "Already done")
+	if err := s.CloseIssue(ctx, done.ID, "earlier", false, actor); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+
+	if err := s.CloseIssue(ctx, root.ID, "all done", true, actor); err != nil {
+		t.Fatalf("cascade CloseIssue failed: %v", err)
+	}
+
+	cascaded := fmt.Sprintf("all done (cascade closed by %s)", root.ID)
+	for _, c := range []struct{ id, reason string }{
+		{root.ID, "all done"},
+		{mid.ID, cascaded},
+		{leaf.ID, cascaded},
+		{done.ID, "earlier"},
+	} {
+		got := getIssue(t, s, c.id)
+		if got.Status != types.StatusClosed {
+			t.Errorf("%s status = %s, want closed", c.id, got.Status)
+		}
+		if got.CloseReason != c.reason {
+			t.Errorf("%s close reason = %q, want %q", c.id, got.CloseReason, c.reason)
+		}
+	}
+
+	leafClosed, midClosed := getIssue(t, s, leaf.ID).ClosedAt, getIssue(t, s, mid.ID).ClosedAt
+	if leafClosed == nil || midClosed == nil || leafClosed.After(*midClosed) {
+		t.Errorf("leaf closed at %v after its parent at %v, want leaves first", leafClosed, midClosed)
+	}
+}
+
+func testReopenIssue(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	proj := newProject(t, s, "Reopen", "reo")
+
+	issue := newIssue(t, s, proj.ID, "Flaky", 2)
+	if err := s.CloseIssue(ctx, issue.ID, "fixed", false, actor); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	if err := s.ReopenIssue(ctx, issue.ID, actor); err != nil {
+		t.Fatalf("ReopenIssue failed: %v", err)
+	}
+
+	got := getIssue(t, s, issue.ID)
+	if got.Status != types.StatusOpen || got.ClosedAt != nil || got.CloseReason != "" {
+		t.Errorf("reopened issue = %s/%v/%q, want open with no close data", got.Status, got.ClosedAt, got.CloseReason)
+	}
+}
+
+func testReadyWorkBlocking(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	proj := newProject(t, s, "Ready", "ready")
+
+	blocker := newIssue(t, s, proj.ID, "Blocker", 2)
+	blocked := newIssue(t, s, proj.ID, "Blocked", 1)
+	parent := newIssue(t, s, proj.ID, "Parent", 2)
+	child := newChild(t, s, parent, "Child")
+
+	dep := &types.Dependency{IssueID: blocked.ID, DependsOnID: blocker.ID, Type: types.DepBlocks}
+	if err := s.AddDependency(ctx, dep, actor); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+
+	ready := readyIDs(t, s, types.WorkFilter{ProjectID: proj.ID})
+	if slices.Contains(ready, blocked.ID) {
+		t.Errorf("ready work %v includes blocked issue %s", ready, blocked.ID)
+	}
+	if !slices.Contains(ready, child.ID) {
+		t.Errorf("ready work %v excludes %s; parent-child must not block", ready, child.ID)
+	}
+
+	isBlocked, blockers, err := s.IsBlocked(ctx, blocked.ID)
+	if err != nil {
+		t.Fatalf("IsBlocked failed: %v", err)
+	}
+	if !isBlocked || !slices.Equal(blockers, []string{blocker.ID}) {
+		t.Errorf("IsBlocked = %v %v, want true [%s]", isBlocked, blockers, blocker.ID)
+	}
+
+	blockedList, err := s.GetBlockedIssues(ctx, types.WorkFilter{ProjectID: proj.ID})
+	if err != nil {
+		t.Fatalf("GetBlockedIssues failed: %v", err)
+	}
+	if len(blockedList) != 1 || blockedList[0].ID != blocked.ID || blockedList[0].BlockedByCount != 1 {
+		t.Errorf("GetBlockedIssues = %+v, want only %s blocked by one issue", blockedList, blocked.ID)
+	}
+
+	if err := s.CloseIssue(ctx, blocker.ID, "done", false, actor); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	if ready := readyIDs(t, s, types.WorkFilter{ProjectID: proj.ID}); !slices.Contains(ready, blocked.ID) {
+		t.Errorf("ready work %v excludes %s after its blocker closed", ready, blocked.ID)
+	}
+}
+
+func testDependencyCycles(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	proj := newProject(t, s, "Cycles", "cyc")
+	a := newIssue(t, s, proj.ID, "A", 2)
+	b := newIssue(t, s, proj.ID, "B", 2)
+	c := newIssue(t, s, proj.ID, "C", 2)
+
+	for _, d := range []*types.Dependency{
+		{IssueID: b.ID, DependsOnID: a.ID, Type: types.DepBlocks},
+		{IssueID: c.ID, DependsOnID: b.ID, Type: types.DepParentChild},
+	} {
+		if err := s.AddDependency(ctx, d, actor); err != nil {
+			t.Fatalf("AddDependency(%s -> %s) failed: %v", d.IssueID, d.DependsOnID, err)
+		}
+	}
+
+	err := s.AddDependency(ctx, &types.Dependency{IssueID: a.ID, DependsOnID: c.ID, Type: types.DepBlocks}, actor)
+	var cycleErr *types.DependencyCycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("closing the loop error = %v, want *types.DependencyCycleError", err)
+	}
+	if want := []string{a.ID, c.ID, b.ID, a.ID}; !slices.Equal(cycleErr.Path, want) {
+		t.Errorf("cycle path = %v, want %v", cycleErr.Path, want)
+	}
+	if deps, _ := s.GetDependencies(ctx, a.ID); len(deps) != 0 {
+		t.Errorf("rejected dependency was stored: %+v", deps)
+	}
+
+	// Non-blocking links may loop.
+	if err := s.AddDependency(ctx, &types.Dependency{IssueID: a.ID, DependsOnID: c.ID, Type: types.DepRelated}, actor); err != nil {
+		t.Errorf("related dependency closing a loop failed: %v", err)
+	}
+	// Turning that link into a blocker is still caught.
+	err = s.AddDependency(ctx, &types.Dependency{IssueID: a.ID, DependsOnID: c.ID, Type: types.DepBlocks}, actor)
+	if !errors.As(err, &cycleErr) {
+		t.Errorf("upgrading a related link to blocks error = %v, want *types.DependencyCycleError", err)
+	}
+
+	// Concurrent adds of both halves of a loop never both succeed.
+	for range 10 {
+		x := newIssue(t, s, proj.ID, "X", 2)
+		y := newIssue(t, s, proj.ID, "Y", 2)
+		var wg sync.WaitGroup
+		errs := make([]error, 2)
+		for i, d := range []*types.Dependency{
+			{IssueID: x.ID, DependsOnID: y.ID, Type: types.DepBlocks},
+			{IssueID: y.ID, DependsOnID: x.ID, Type: types.DepBlocks},
+		} {
+			wg.Go(func() { errs[i] = s.AddDependency(ctx, d, actor) })
+		}
+		wg.Wait()
+		if errs[0] == nil && errs[1] == nil {
+			t.Fatalf("concurrent adds of %s -> %s and back both succeeded", x.ID, y.ID)
+		}
+	}
+}
+
+func testReadyWorkSortPolicies(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	daysAgo := func(n int) time.Time { return now.Add(-time.Duration(n) * 24 * time.Hour) }
+
+	// Import keeps timestamps, which lets the suite place issues on either
+	// side of the hybrid policy's recency window.
+	seed := func(id string, priority int, created, updated time.Time) *types.Issue {
+		return &types.Issue{
+			ID: id, Title: id, Status: types.StatusOpen, Priority: priority,
+			IssueType: types.TypeTask, CreatedAt: created, UpdatedAt: updated,
+		}
+	}
+	archive := &types.ProjectArchive{
+		Project: &types.Project{ID: "proj-sort", Name: "Sorting", Prefix: "sort"},
+		Issues: []*types.Issue{
+			seed("sort.recentlow", 2, daysAgo(30), now),
+			seed("sort.recenthigh", 0, daysAgo(20), now),
+			seed("sort.oldnewer", 0, daysAgo(5), daysAgo(5)),
+			seed("sort.oldolder", 3, daysAgo(10), daysAgo(10)),
+		},
+	}
+	if err := s.ImportProject(ctx, archive); err != nil {
+		t.Fatalf("ImportProject failed: %v", err)
+	}
+
+	hybrid := []string{"sort.recenthigh", "sort.recentlow", "sort.oldolder", "sort.oldnewer"}
+	for _, c := range []struct {
+		policy types.SortPolicy
+		want   []string
+	}{
+		{"", hybrid},
+		{types.SortPolicyHybrid, hybrid},
+		{types.SortPolicyPriority, []string{"sort.recenthigh", "sort.oldnewer", "sort.recentlow", "sort.oldolder"}},
+		{types.SortPolicyOldest, []string{"sort.recentlow", "sort.recenthigh", "sort.oldolder", "sort.oldnewer"}},
+	} {
+		got := readyIDs(t, s, types.WorkFilter{ProjectID: "proj-sort", SortPolicy: c.policy})
+		if !slices.Equal(got, c.want) {
+			t.Errorf("ready work with policy %q = %v, want %v", c.policy, got, c.want)
+		}
+
+		// Offsets and cursors page through the same order.
+		paged := readyIDs(t, s, types.WorkFilter{ProjectID: "proj-sort", SortPolicy: c.policy, Offset: 1, Limit: 2})
+		if !slices.Equal(paged, c.want[1:3]) {
+			t.Errorf("ready work with policy %q, offset 1, limit 2 = %v, want %v", c.policy, paged, c.want[1:3])
+		}
+		resumed := readyIDs(t, s, types.WorkFilter{ProjectID: "proj-sort", SortPolicy: c.policy, After: c.want[1]})
+		if !slices.Equal(resumed, c.want[2:]) {
+			t.Errorf("ready work with policy %q after %s = %v, want %v", c.policy, c.want[1], resumed, c.want[2:])
+		}
+	}
+
+	limited := readyIDs(t, s, types.WorkFilter{ProjectID: "proj-sort", SortPolicy: types.SortPolicyOldest, Limit: 2})
+	if !slices.Equal(limited, []string{"sort.recentlow", "sort.recenthigh"}) {
+		t.Errorf("ready work with limit 2 = %v", limited)
+	}
+}
+
+func testWorkFilters(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	proj := newProject(t, s, "Work", "wf")
+
+	// Urgent issues sort ahead of the backend work, so the filter has to
+	// run before the limit for a full page to come back.
+	for _, title := range []string{"Urgent one", "Urgent two", "Urgent three"} {
+		newIssue(t, s, proj.ID, title, 1)
+	}
+	epic := newIssue(t, s, proj.ID, "Epic", 1)
+	api := newChild(t, s, epic, "API")
+	ui := newChild(t, s, epic, "UI")
+	docs := newChild(t, s, epic, "Docs")
+	for _, id := range []string{api.ID, ui.ID, docs.ID} {
+		if err := s.AddLabelToIssue(ctx, id, "backend", actor); err != nil {
+			t.Fatalf("AddLabelToIssue failed: %v", err)
+		}
+	}
+	if err := s.AddLabelToIssue(ctx, ui.ID, "teammate:frontend", actor); err != nil {
+		t.Fatalf("AddLabelToIssue failed: %v", err)
+	}
+	dep := &types.Dependency{IssueID: docs.ID, DependsOnID: api.ID, Type: types.DepBlocks}
+	if err := s.AddDependency(ctx, dep, actor); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+
+	two := 2
+	for _, c := range []struct {
+		name   string
+		filter types.WorkFilter
+		want   []string
+	}{
+		{"priority", types.WorkFilter{Priority: &two, Limit: 2}, []string{api.ID, ui.ID}},
+		{"labels", types.WorkFilter{Labels: []string{"backend"}, Limit: 2}, []string{api.ID, ui.ID}},
+		{"role label", types.WorkFilter{Labels: []string{"teammate:frontend"}}, []string{ui.ID}},
+		{"parent", types.WorkFilter{ParentID: epic.ID}, []string{api.ID, ui.ID}},
+		{"parent with offset", types.WorkFilter{ParentID: epic.ID, Offset: 1}, []string{ui.ID}},
+		{"unknown parent", types.WorkFilter{ParentID: "wf.missing"}, nil},
+	} {
+		c.filter.ProjectID = proj.ID
+		c.filter.SortPolicy = types.SortPolicyPriority
+		if got := readyIDs(t, s, c.filter); !sameSet(got, c.want) {
+			t.Errorf("ready work by %s = %v, want %v", c.name, got, c.want)
+		}
+	}
+
+	blocked, err := s.GetBlockedIssues(ctx, types.WorkFilter{ProjectID: proj.ID, ParentID: epic.ID, Labels: []string{"backend"}})
+	if err != nil {
+		t.Fatalf("GetBlockedIssues failed: %v", err)
+	}
+	if len(blocked) != 1 || blocked[0].ID != docs.ID || !slices.Equal(blocked[0].BlockedBy, []string{api.ID}) {
+		t.Errorf("blocked children of %s = %+v, want %s blocked by %s", epic.ID, blocked, docs.ID, api.ID)
+	}
+	for _, filter := range []types.WorkFilter{
+		{ProjectID: proj.ID, Priority: new(int)},
+		{ProjectID: proj.ID, Offset: 1},
+		{ProjectID: proj.ID, After: docs.ID},
+	} {
+		if blocked, err = s.GetBlockedIssues(ctx, filter); err != nil || len(blocked) != 0 {
+			t.Errorf("GetBlockedIssues(%+v) = %+v, %v; want none", filter, blocked, err)
+		}
+	}
+}
+
+func testMergeProjects(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	target := newProject(t, s, "Target", "tgt")
+	src1 := newProject(t, s, "Source one", "src1")
+	src2 := newProject(t, s, "Source two", "src2")
+	newIssue(t, s, target.ID, "Already here", 2)
+	moved := newIssue(t, s, src1.ID, "Moved one", 2)
+	newIssue(t, s, src1.ID, "Moved two", 2)
+	newIssue(t, s, src2.ID, "Moved three", 2)
+
+	if _, err := s.MergeProjects(ctx, target.ID, []string{target.ID}, actor); err == nil {
+		t.Error("expected error merging a project into itself")
+	}
+	if _, err := s.MergeProjects(ctx, "proj-missing", []string{src1.ID}, actor); err == nil {
+		t.Error("expected error merging into unknown target")
+	}
+	if _, err := s.MergeProjects(ctx, target.ID, []string{"proj-missing"}, actor); err == nil {
+		t.Error("expected error merging unknown source")
+	}
+
+	res, err := s.MergeProjects(ctx, target.ID, []string{src1.ID, src2.ID}, actor)
+	if err != nil {
+		t.Fatalf("MergeProjects failed: %v", err)
+	}
+	if res.IssuesMoved != 3 {
+		t.Errorf("IssuesMoved = %d, want 3", res.IssuesMoved)
+	}
+	if !slices.Equal(res.SourcesDeleted, []string{src1.ID, src2.ID}) {
+		t.Errorf("SourcesDeleted = %v, want [%s %s]", res.SourcesDeleted, src1.ID, src2.ID)
+	}
+	if res.TargetProject == nil || res.TargetProject.ID != target.ID {
+		t.Errorf("TargetProject = %+v, want %s", res.TargetProject, target.ID)
+	}
+
+	for _, id := range []string{src1.ID, src2.ID} {
+		if _, err := s.GetProject(ctx, id); err == nil {
+			t.Errorf("source project %s still exists after merge", id)
+		}
+	}
+	issues, err := s.ListIssues(ctx, types.IssueFilter{ProjectID: target.ID})
+	if err != nil {
+		t.Fatalf("ListIssues failed: %v", err)
+	}
+	if len(issues) != 4 {
+		t.Errorf("target has %d issues after merge, want 4", len(issues))
+	}
+
+	events, err := s.GetEvents(ctx, moved.ID, 10)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	if len(events) == 0 || events[0].EventType != types.EventMerged ||
+		events[0].NewValue == nil || *events[0].NewValue != "merged into "+target.ID {
+		t.Errorf("latest event on moved issue = %+v, want merged into %s", events, target.ID)
+	}
+}
+
+func testMergeIssue(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	proj := newProject(t, s, "Merge", "mrg")
+	other := newProject(t, s, "Other", "oth")
+	canonical := newIssue(t, s, proj.ID, "Login crashes on Safari", 2)
+	dup := newIssue(t, s, proj.ID, "Safari login crash", 2)
+	api := newIssue(t, s, proj.ID, "Fix session API", 2)
+	ui := newIssue(t, s, proj.ID, "Update login UI", 2)
+	elsewhere := newIssue(t, s, other.ID, "Safari crash", 2)
+
+	for _, d := range []*types.Dependency{
+		{IssueID: dup.ID, DependsOnID: api.ID, Type: types.DepBlocks},
+		{IssueID: ui.ID, DependsOnID: dup.ID, Type: types.DepBlocks},
+	} {
+		if err := s.AddDependency(ctx, d, actor); err != nil {
+			t.Fatalf("AddDependency(%s -> %s) failed: %v", d.IssueID, d.DependsOnID, err)
+		}
+	}
+	for _, label := range []string{"bug", "safari"} {
+		if err := s.AddLabelToIssue(ctx, dup.ID, label, actor); err != nil {
+			t.Fatalf("AddLabelToIssue failed: %v", err)
+		}
+	}
+	if err := s.AddLabelToIssue(ctx, canonical.ID, "bug", actor); err != nil {
+		t.Fatalf("AddLabelToIssue failed: %v", err)
+	}
+	if _, err := s.AddComment(ctx, dup.ID, actor, "Also seen on iOS"); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+
+	if _, err := s.MergeIssue(ctx, dup.ID, dup.ID, actor); err == nil {
+		t.Error("expected error merging an issue into itself")
+	}
+	if _, err := s.MergeIssue(ctx, dup.ID, elsewhere.ID, actor); err == nil {
+		t.Error("expected error merging across projects")
+	}
+	if _, err := s.MergeIssue(ctx, dup.ID, "mrg-missing", actor); err == nil {
+		t.Error("expected error merging into an unknown issue")
+	}
+
+	res, err := s.MergeIssue(ctx, dup.ID, canonical.ID, actor)
+	if err != nil {
+		t.Fatalf("MergeIssue failed: %v", err)
+	}
+	if res.CommentsMoved != 1 || res.LabelsMoved != 1 || res.DependenciesMoved != 2 {
+		t.Errorf("moved %d comments, %d labels, %d dependencies; want 1, 1, 2",
+			res.CommentsMoved, res.LabelsMoved, res.DependenciesMoved)
+	}
+	if res.Duplicate.Status != types.StatusClosed || res.Duplicate.CloseReason != types.CloseReasonDuplicate {
+		t.Errorf("duplicate = %s (%q), want closed as %q",
+			res.Duplicate.Status, res.Duplicate.CloseReason, types.CloseReasonDuplicate)
+	}
+
+	if got := getIssue(t, s, dup.ID); got.ID != dup.ID || got.Status != types.StatusClosed {
+		t.Errorf("GetIssue(%s) = %s (%s), want the closed duplicate itself", dup.ID, got.ID, got.Status)
+	}
+	if current, err := s.ResolveIssueAlias(ctx, dup.ID); err != nil || current != canonical.ID {
+		t.Errorf("ResolveIssueAlias(%s) = %s, %v; want the canonical issue %s", dup.ID, current, err, canonical.ID)
+	}
+	comments, err := s.GetComments(ctx, canonical.ID)
+	if err != nil {
+		t.Fatalf("GetComments failed: %v", err)
+	}
+	if len(comments) != 1 {
+		t.Errorf("canonical issue has %d comments, want 1", len(comments))
+	}
+	labels, err := s.GetIssueLabels(ctx, canonical.ID)
+	if err != nil {
+		t.Fatalf("GetIssueLabels failed: %v", err)
+	}
+	slices.Sort(labels)
+	if !slices.Equal(labels, []string{"bug", "safari"}) {
+		t.Errorf("canonical labels = %v, want [bug safari]", labels)
+	}
+
+	deps, err := s.GetDependencies(ctx, canonical.ID)
+	if err != nil {
+		t.Fatalf("GetDependencies failed: %v", err)
+	}
+	if len(deps) != 1 || deps[0].DependsOnID != api.ID {
+		t.Errorf("canonical dependencies = %+v, want only %s", deps, api.ID)
+	}
+	dependents, err := s.GetDependents(ctx, canonical.ID)
+	if err != nil {
+		t.Fatalf("GetDependents failed: %v", err)
+	}
+	var dependentIDs []string
+	for _, d := range dependents {
+		dependentIDs = append(dependentIDs, d.IssueID+":"+string(d.Type))
+	}
+	slices.Sort(dependentIDs)
+	want := []string{dup.ID + ":" + string(types.DepDuplicates), ui.ID + ":" + string(types.DepBlocks)}
+	slices.Sort(want)
+	if !slices.Equal(dependentIDs, want) {
+		t.Errorf("canonical dependents = %v, want %v", dependentIDs, want)
+	}
+
+	for id, value := range map[string]string{
+		dup.ID:       "merged into " + canonical.ID,
+		canonical.ID: "merged from " + dup.ID,
+	} {
+		events, err := s.GetEvents(ctx, id, 10)
+		if err != nil {
+			t.Fatalf("GetEvents failed: %v", err)
+		}
+		if len(events) == 0 || events[0].EventType != types.EventMerged ||
+			events[0].NewValue == nil || *events[0].NewValue != value {
+			t.Errorf("latest event on %s = %+v, want %s", id, events, value)
+		}
+	}
+
+	// The label the canonical issue gained is in its audit trail.
+	events, err := s.GetEvents(ctx, canonical.ID, 10)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	var added []string
+	for _, e := range events {
+		if e.EventType == types.EventLabelAdded && e.NewValue != nil {
+			added = append(added, *e.NewValue)
+		}
+	}
+	slices.Sort(added)
+	if !slices.Equal(added, []string{"bug", "safari"}) {
+		t.Errorf("label_added events on canonical issue = %v, want [bug safari]", added)
+	}
+
+	if _, err := s.MergeIssue(ctx, dup.ID, api.ID, actor); err == nil {
+		t.Error("expected error merging an already merged issue")
+	}
+}
+
+func testMoveIssue(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	src := newProject(t, s, "Source", "src")
+	dst := newProject(t, s, "Destination", "dst")
+	parent := newIssue(t, s, src.ID, "Roadmap", 2)
+	epic := newChild(t, s, parent, "Checkout epic")
+	task := newChild(t, s, epic, "Card form")
+	sub := newChild(t, s, task, "Validate card numbers")
+	blocker := newIssue(t, s, src.ID, "Payments API", 2)
+	if err := s.AddDependency(ctx, &types.Dependency{IssueID: task.ID, DependsOnID: blocker.ID, Type: types.DepBlocks}, actor); err != nil {
+		t.Fatalf("AddDependency failed: %v", err)
+	}
+	if err := s.AddLabelToIssue(ctx, task.ID, "frontend", actor); err != nil {
+		t.Fatalf("AddLabelToIssue failed: %v", err)
+	}
+	if _, err := s.AddComment(ctx, task.ID, actor, "Use the new design"); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+
+	if _, err := s.MoveIssue(ctx, epic.ID, src.ID, actor); err == nil {
+		t.Error("expected error moving an issue into its own project")
+	}
+	if _, err := s.MoveIssue(ctx, epic.ID, "proj-missing", actor); err == nil {
+		t.Error("expected error moving into an unknown project")
+	}
+
+	res, err := s.MoveIssue(ctx, epic.ID, dst.ID, actor)
+	if err != nil {
+		t.Fatalf("MoveIssue failed: %v", err)
+	}
+	if len(res.IDMap) != 3 {
+		t.Fatalf("IDMap = %v, want the epic, its child and grandchild", res.IDMap)
+	}
+	newEpic, newTask, newSub := res.IDMap[epic.ID], res.IDMap[task.ID], res.IDMap[sub.ID]
+	if !strings.HasPrefix(newEpic, "dst.") || strings.Count(newEpic, ".") != 1 {
+		t.Errorf("epic moved to %s, want a top-level dst ID", newEpic)
+	}
+	if newTask != newEpic+".1" || newSub != newTask+".1" {
+		t.Errorf("children moved to %s and %s, want %s.1 and %s.1", newTask, newSub, newEpic, newTask)
+	}
+	if res.Issue.ID != newEpic || res.Issue.ProjectID != dst.ID {
+		t.Errorf("moved issue = %s in %s, want %s in %s", res.Issue.ID, res.Issue.ProjectID, newEpic, dst.ID)
+	}
+
+	// Old IDs resolve to the moved issues.
+	for oldID, newID := range res.IDMap {
+		if got := getIssue(t, s, oldID); got.ID != newID || got.ProjectID != dst.ID {
+			t.Errorf("GetIssue(%s) = %s in %s, want %s in %s", oldID, got.ID, got.ProjectID, newID, dst.ID)
+		}
+		if current, err := s.ResolveIssueAlias(ctx, oldID); err != nil || current != newID {
+			t.Errorf("ResolveIssueAlias(%s) = %s, %v; want %s", oldID, current, err, newID)
+		}
+	}
+	if current, err := s.ResolveIssueAlias(ctx, blocker.ID); err != nil || current != blocker.ID {
+		t.Errorf("ResolveIssueAlias(%s) = %s, %v; want it unchanged", blocker.ID, current, err)
+	}
+
+	// Dependencies, labels and comments follow the new IDs; the epic leaves
+	// its parent behind.
+	deps, err := s.GetDependencies(ctx, newTask)
+	if err != nil {
+		t.Fatalf("GetDependencies failed: %v", err)
+	}
+	var depIDs []string
+	for _, d := range deps {
+		depIDs = append(depIDs, d.DependsOnID)
+	}
+	slices.Sort(depIDs)
+	want := []string{blocker.ID, newEpic}
+	slices.Sort(want)
+	if !slices.Equal(depIDs, want) {
+		t.Errorf("dependencies of %s = %v, want %v", newTask, depIDs, want)
+	}
+	if deps, err := s.GetDependencies(ctx, newEpic); err != nil || len(deps) != 0 {
+		t.Errorf("dependencies of moved epic = %+v, %v; want none", deps, err)
+	}
+	if labels, err := s.GetIssueLabels(ctx, newTask); err != nil || !slices.Equal(labels, []string{"frontend"}) {
+		t.Errorf("labels of %s = %v, %v; want [frontend]", newTask, labels, err)
+	}
+	if comments, err := s.GetComments(ctx, newTask); err != nil || len(comments) != 1 {
+		t.Errorf("comments of %s = %d, %v; want 1", newTask, len(comments), err)
+	}
+	events, err := s.GetEvents(ctx, newTask, 10)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	if len(events) == 0 || events[0].EventType != types.EventMoved {
+		t.Fatalf("latest event on %s = %+v, want moved", newTask, events)
+	}
+	if id, projectID := history.ParseMoveValue(events[0].OldValue); id != task.ID || projectID != task.ProjectID {
+		t.Errorf("moved event on %s came from %s in %s, want %s in %s", newTask, id, projectID, task.ID, task.ProjectID)
+	}
+	if events[len(events)-1].EventType != types.EventCreated {
+		t.Errorf("earliest event on %s = %s, want the history kept", newTask, events[len(events)-1].EventType)
+	}
+
+	// New children continue after the moved ones.
+	next := newChild(t, s, getIssue(t, s, newEpic), "Receipts")
+	if next.ID != newEpic+".2" {
+		t.Errorf("next child ID = %s, want %s.2", next.ID, newEpic)
+	}
+
+	// Every earlier ID follows a second move.
+	again, err := s.MoveIssue(ctx, newTask, src.ID, actor)
+	if err != nil {
+		t.Fatalf("second MoveIssue failed: %v", err)
+	}
+	for _, id := range []string{task.ID, newTask} {
+		if got := getIssue(t, s, id); got.ID != again.Issue.ID {
+			t.Errorf("GetIssue(%s) = %s, want %s", id, got.ID, again.Issue.ID)
+		}
+	}
+
+	// Moving back restores a top-level ID, which stops being an alias.
+	moved, err := s.MoveIssue(ctx, blocker.ID, dst.ID, actor)
+	if err != nil {
+		t.Fatalf("MoveIssue failed: %v", err)
+	}
+	back, err := s.MoveIssue(ctx, moved.Issue.ID, src.ID, actor)
+	if err != nil {
+		t.Fatalf("MoveIssue back failed: %v", err)
+	}
+	if back.Issue.ID != blocker.ID {
+		t.Errorf("moved back to %s, want %s", back.Issue.ID, blocker.ID)
+	}
+	if current, err := s.ResolveIssueAlias(ctx, blocker.ID); err != nil || current != blocker.ID {
+		t.Errorf("ResolveIssueAlias(%s) = %s, %v; want it unchanged", blocker.ID, current, err)
+	}
+	if got := getIssue(t, s, moved.Issue.ID); got.ID != blocker.ID {
+		t.Errorf("GetIssue(%s) = %s, want %s", moved.Issue.ID, got.ID, blocker.ID)
+	}
+}
+
+func testLabelFilters(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	proj := newProject(t, s, "Labels", "lbl")
+
+	both := newIssue(t, s, proj.ID, "Both", 2)
+	onlyA := newIssue(t, s, proj.ID, "Only A", 2)
+	onlyB := newIssue(t, s, proj.ID, "Only B", 2)
+	newIssue(t, s, proj.ID, "Neither", 2)
+	for _, l := range []struct{ id, label string }{
+		{both.ID, "backend"}, {both.ID, "urgent"}, {onlyA.ID, "backend"}, {onlyB.ID, "urgent"},
+	} {
+		if err := s.AddLabelToIssue(ctx, l.id, l.label, actor); err != nil {
+			t.Fatalf("AddLabelToIssue failed: %v", err)
+		}
+	}
+
+	for _, c := range []struct {
+		labels []string
+		want   []string
+	}{
+		{[]string{"backend"}, []string{both.ID, onlyA.ID}},
+		{[]string{"backend", "urgent"}, []string{both.ID}},
+		{[]string{"backend", "missing"}, nil},
+	} {
+		issues, err := s.ListIssues(ctx, types.IssueFilter{ProjectID: proj.ID, Labels: c.labels})
+		if err != nil {
+			t.Fatalf("ListIssues failed: %v", err)
+		}
+		if got := issueIDs(issues); !sameSet(got, c.want) {
+			t.Errorf("ListIssues labels %v = %v, want %v", c.labels, got, c.want)
+		}
+
+		ready := readyIDs(t, s, types.WorkFilter{ProjectID: proj.ID, Labels: c.labels})
+		if !sameSet(ready, c.want) {
+			t.Errorf("GetReadyWork labels %v = %v, want %v", c.labels, ready, c.want)
+		}
+	}
+}
+
+func testIssueQueries(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	proj := newProject(t, s, "Queries", "qry")
+
+	login := newIssue(t, s, proj.ID, "Fix login redirect", 1)
+	crash := newIssue(t, s, proj.ID, "Crash on save", 2)
+	docs := newIssue(t, s, proj.ID, "Write docs", 3)
+	wontfix := newIssue(t, s, proj.ID, "Old login idea", 2)
+	if err := s.UpdateIssue(ctx, crash.ID, map[string]any{"priority": 0}, 0, actor); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if err := s.AddLabelToIssue(ctx, wontfix.ID, "wontfix", actor); err != nil {
+		t.Fatalf("AddLabelToIssue failed: %v", err)
+	}
+	if err := s.CloseIssue(ctx, docs.ID, "done", false, actor); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+
+	one, hourAgo, inHour := 1, time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	for _, c := range []struct {
+		name   string
+		filter types.IssueFilter
+		want   []string
+	}{
+		{"exclude status", types.IssueFilter{ExcludeStatuses: []types.Status{types.StatusClosed}},
+			[]string{login.ID, crash.ID, wontfix.ID}},
+		{"exclude label", types.IssueFilter{ExcludeLabels: []string{"wontfix"}},
+			[]string{login.ID, crash.ID, docs.ID}},
+		{"exclude priority", types.IssueFilter{ExcludePriorities: []int{1, 3}}, []string{crash.ID, wontfix.ID}},
+		{"max priority", types.IssueFilter{MaxPriority: &one}, []string{login.ID, crash.ID}},
+		{"min priority", types.IssueFilter{MinPriority: &one}, []string{login.ID, docs.ID, wontfix.ID}},
+		{"text", types.IssueFilter{Text: []string{"LOGIN"}}, []string{login.ID, wontfix.ID}},
+		{"all text", types.IssueFilter{Text: []string{"login", "fix"}}, []string{login.ID}},
+		{"created after", types.IssueFilter{CreatedAfter: &hourAgo, MaxPriority: &one}, []string{login.ID, crash.ID}},
+		{"created before", types.IssueFilter{CreatedBefore: &hourAgo}, nil},
+		{"updated before", types.IssueFilter{UpdatedBefore: &inHour, Text: []string{"docs"}}, []string{docs.ID}},
+		{"or groups", types.IssueFilter{
+			ExcludeStatuses: []types.Status{types.StatusClosed},
+			Or: []types.IssueFilter{
+				{Priorities: []int{0}},
+				{Text: []string{"login"}, ExcludeLabels: []string{"wontfix"}},
+			},
+		}, []string{crash.ID, login.ID}},
+	} {
+		c.filter.ProjectID = proj.ID
+		issues, err := s.ListIssues(ctx, c.filter)
+		if err != nil {
+			t.Fatalf("ListIssues(%s) failed: %v", c.name, err)
+		}
+		if got := issueIDs(issues); !sameSet(got, c.want) {
+			t.Errorf("ListIssues by %s = %v, want %v", c.name, got, c.want)
+		}
+	}
+
+	issues, err := s.ListIssues(ctx, types.IssueFilter{
+		ProjectID: proj.ID,
+		Sort:      []types.IssueSort{{Field: types.SortByStatus}, {Field: types.SortByTitle, Desc: true}},
+	})
+	if err != nil {
+		t.Fatalf("ListIssues failed: %v", err)
+	}
+	if got, want := issueIDs(issues), []string{docs.ID, wontfix.ID, login.ID, crash.ID}; !slices.Equal(got, want) {
+		t.Errorf("ListIssues by status, then title descending = %v, want %v", got, want)
+	}
+}
+
+func testUpdateIssue(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	proj := newProject(t, s, "Update", "upd")
+	issue := newIssue(t, s, proj.ID, "Before", 2)
+
+	updates := map[string]any{"title": "After", "priority": 1, "status": string(types.StatusInProgress)}
+	if err := s.UpdateIssue(ctx, issue.ID, updates, 0, actor); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	got := getIssue(t, s, issue.ID)
+	if got.Title != "After" || got.Priority != 1 || got.Status != types.StatusInProgress {
+		t.Errorf("updated issue = %q/P%d/%s, want After/P1/in_progress", got.Title, got.Priority, got.Status)
+	}
+
+	if err := s.UpdateIssue(ctx, issue.ID, map[string]any{"bogus": "x"}, 0, actor); err == nil {
+		t.Error("expected error updating unknown field")
+	}
+}
+
+func testIssueVersions(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	proj := newProject(t, s, "Versions", "ver")
+
+	issue := newIssue(t, s, proj.ID, "Contended", 2)
+	if issue.Version != 1 || getIssue(t, s, issue.ID).Version != 1 {
+		t.Fatalf("new issue version = %d, want 1", issue.Version)
+	}
+
+	if err := s.UpdateIssue(ctx, issue.ID, map[string]any{"title": "First"}, 1, actor); err != nil {
+		t.Fatalf("UpdateIssue at current version failed: %v", err)
+	}
+	if got := getIssue(t, s, issue.ID).Version; got != 2 {
+		t.Errorf("version after update = %d, want 2", got)
+	}
+
+	err := s.UpdateIssue(ctx, issue.ID, map[string]any{"title": "Stale", "priority": 0}, 1, actor)
+	var conflictErr *types.VersionConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("stale UpdateIssue error = %v, want *types.VersionConflictError", err)
+	}
+	if conflictErr.IssueID != issue.ID || conflictErr.Expected != 1 || conflictErr.Current != 2 {
+		t.Errorf("VersionConflictError = %+v, want %s expected 1 current 2", conflictErr, issue.ID)
+	}
+	if got := getIssue(t, s, issue.ID); got.Title != "First" || got.Priority != 2 || got.Version != 2 {
+		t.Errorf("stale update wrote %q/P%d/v%d, want First/P2/v2", got.Title, got.Priority, got.Version)
+	}
+
+	if err := s.UpdateIssue(ctx, issue.ID, map[string]any{"priority": 1}, 0, actor); err != nil {
+		t.Fatalf("unconditional UpdateIssue failed: %v", err)
+	}
+	if err := s.CloseIssue(ctx, issue.ID, "done", false, actor); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	if err := s.ReopenIssue(ctx, issue.ID, actor); err != nil {
+		t.Fatalf("ReopenIssue failed: %v", err)
+	}
+	if got := getIssue(t, s, issue.ID).Version; got != 5 {
+		t.Errorf("version after update, close and reopen = %d, want 5", got)
+	}
+}
+
+func testIssueHistory(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	proj := newProject(t, s, "History", "his")
+	issue := &types.Issue{ProjectID: proj.ID, Title: "Draft", Description: "one", Priority: 2}
+	if err := s.CreateIssue(ctx, issue, actor); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	// checkpoint returns a moment strictly between two mutations, clear of
+	// any timestamp rounding in the backend.
+	checkpoint := func() time.Time {
+		time.Sleep(2 * time.Millisecond)
+		at := time.Now()
+		time.Sleep(2 * time.Millisecond)
+		return at
+	}
+
+	created := checkpoint()
+	updates := map[string]any{
+		"title": "Final", "description": "one\ntwo", "priority": 1, "rank": 3, "status": "in_progress",
+	}
+	if err := s.UpdateIssue(ctx, issue.ID, updates, 0, actor); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	updated := checkpoint()
+	if err := s.CloseIssue(ctx, issue.ID, "done", false, actor); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	closed := checkpoint()
+	if err := s.ReopenIssue(ctx, issue.ID, actor); err != nil {
+		t.Fatalf("ReopenIssue failed: %v", err)
+	}
+
+	events, err := s.GetEvents(ctx, issue.ID, 100)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	history.Sort(events)
+
+	var fields []string
+	for _, e := range events {
+		if e.EventType != types.EventUpdated {
+			continue
+		}
+		field, before, ok := history.ParseValue(e.OldValue)
+		_, after, _ := history.ParseValue(e.NewValue)
+		if !ok {
+			t.Fatalf("updated event has no old value: %+v", e)
+		}
+		fields = append(fields, field+":"+before+">"+after)
+	}
+	want := []string{"title:Draft>Final", "description:one>one\ntwo", "priority:2>1", "rank:0>3"}
+	if !slices.Equal(fields, want) {
+		t.Errorf("updated events = %q, want %q", fields, want)
+	}
+
+	current := getIssue(t, s, issue.ID)
+	tests := []struct {
+		name   string
+		at     time.Time
+		title  string
+		status types.Status
+		rank   int
+		reason string
+	}{
+		{"created", created, "Draft", types.StatusOpen, 0, ""},
+		{"updated", updated, "Final", types.StatusInProgress, 3, ""},
+		{"closed", closed, "Final", types.StatusClosed, 3, "done"},
+		{"now", time.Now(), "Final", types.StatusOpen, 3, ""},
+	}
+	for _, tt := range tests {
+		got, err := history.At(current, events, tt.at)
+		if err != nil {
+			t.Fatalf("At(%s) failed: %v", tt.name, err)
+		}
+		if got.Title != tt.title || got.Status != tt.status || got.Rank != tt.rank || got.CloseReason != tt.reason {
+			t.Errorf("At(%s) = %q/%s/rank %d/%q, want %q/%s/rank %d/%q", tt.name,
+				got.Title, got.Status, got.Rank, got.CloseReason, tt.title, tt.status, tt.rank, tt.reason)
+		}
+		if (got.ClosedAt != nil) != (tt.status == types.StatusClosed) {
+			t.Errorf("At(%s).ClosedAt = %v with status %s", tt.name, got.ClosedAt, got.Status)
+		}
+	}
+
+	if _, err := history.At(current, events, current.CreatedAt.Add(-time.Hour)); !errors.Is(err, history.ErrNotCreated) {
+		t.Errorf("At before creation error = %v, want ErrNotCreated", err)
+	}
+}
+
+func testUndo(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	proj := newProject(t, s, "Undo", "und")
+	a := newIssue(t, s, proj.ID, "First", 2)
+	b := newIssue(t, s, proj.ID, "Second", 2)
+
+	mutations := []func() error{
+		func() error { return s.AddLabelToIssue(ctx, a.ID, "bug", actor) },
+		func() error {
+			return s.AddDependency(ctx, &types.Dependency{IssueID: b.ID, DependsOnID: a.ID, Type: types.DepRelated}, actor)
+		},
+		func() error {
+			return s.UpdateIssue(ctx, a.ID, map[string]any{"title": "Renamed", "priority": 0}, 0, actor)
+		},
+		func() error { return s.CloseIssue(ctx, a.ID, "done", false, actor) },
+		func() error { return s.RemoveDependency(ctx, b.ID, a.ID, actor) },
+	}
+	for i, mutate := range mutations {
+		if err := mutate(); err != nil {
+			t.Fatalf("mutation %d failed: %v", i, err)
+		}
+	}
+
+	steps, err := undo.Plan(ctx, s, proj.ID, "", 2)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	var kinds []types.EventType
+	for _, step := range steps {
+		kinds = append(kinds, step.Event.EventType)
+	}
+	if want := []types.EventType{types.EventDependencyRemoved, types.EventClosed}; !slices.Equal(kinds, want) {
+		t.Fatalf("planned %v, want %v", kinds, want)
+	}
+	if err := undo.Apply(ctx, s, steps, "undoer"); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if got := getIssue(t, s, a.ID); got.Status != types.StatusOpen {
+		t.Errorf("status after undoing close = %s, want open", got.Status)
+	}
+	deps, err := s.GetDependencies(ctx, b.ID)
+	if err != nil {
+		t.Fatalf("GetDependencies failed: %v", err)
+	}
+	if len(deps) != 1 || deps[0].Type != types.DepRelated {
+		t.Errorf("dependencies after undoing removal = %+v, want the related dependency back", deps)
+	}
+
+	// The reversals themselves are passed over, so undo keeps moving back.
+	steps, err = undo.Plan(ctx, s, proj.ID, "", 10)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if len(steps) != 4 {
+		t.Fatalf("planned %d steps after undo, want the 4 earlier changes", len(steps))
+	}
+	if err := undo.Apply(ctx, s, steps, "undoer"); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	got := getIssue(t, s, a.ID)
+	if got.Title != "First" || got.Priority != 2 {
+		t.Errorf("issue after undoing update = %q P%d, want %q P2", got.Title, got.Priority, "First")
+	}
+	if labels, _ := s.GetIssueLabels(ctx, a.ID); len(labels) != 0 {
+		t.Errorf("labels after undo = %v, want none", labels)
+	}
+	if deps, _ := s.GetDependencies(ctx, b.ID); len(deps) != 0 {
+		t.Errorf("dependencies after undo = %+v, want none", deps)
+	}
+	if steps, _ := undo.Plan(ctx, s, proj.ID, "", 10); len(steps) != 0 {
+		t.Errorf("planned %d steps with nothing left to undo", len(steps))
+	}
+
+	events, err := s.GetEvents(ctx, a.ID, 100)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	if events[0].EventType != types.EventUndone || events[0].Actor != "undoer" {
+		t.Errorf("latest event = %s by %s, want undone by undoer", events[0].EventType, events[0].Actor)
+	}
+
+	// A change overwritten by someone else is skipped rather than clobbered.
+	if err := s.UpdateIssue(ctx, b.ID, map[string]any{"title": "Mine"}, 0, "alice"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if err := s.UpdateIssue(ctx, b.ID, map[string]any{"title": "Theirs"}, 0, "bob"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	steps, err = undo.Plan(ctx, s, proj.ID, "alice", 1)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if len(steps) != 1 || steps[0].Skipped == "" {
+		t.Fatalf("steps = %+v, want alice's change skipped", steps)
+	}
+	if err := undo.Apply(ctx, s, steps, "alice"); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if got := getIssue(t, s, b.ID); got.Title != "Theirs" {
+		t.Errorf("title = %q, want bob's change kept", got.Title)
+	}
+}
+
+func testComments(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	proj := newProject(t, s, "Comments", "cmt")
+	issue := newIssue(t, s, proj.ID, "Discussed", 2)
+
+	first, err := s.AddComment(ctx, issue.ID, "alice", "first")
+	if err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	if _, err := s.AddComment(ctx, issue.ID, "bob", "second"); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	if err := s.UpdateComment(ctx, first.ID, "first, edited"); err != nil {
+		t.Fatalf("UpdateComment failed: %v", err)
+	}
+
+	comments, err := s.GetComments(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetComments failed: %v", err)
+	}
+	if len(comments) != 2 || comments[0].Text != "first, edited" || comments[1].Author != "bob" {
+		t.Errorf("GetComments = %+v, want edited first comment then bob's", comments)
+	}
+	if _, err := s.AddComment(ctx, "cmt.missing", "alice", "orphan"); err == nil {
+		t.Error("expected error commenting on unknown issue")
+	}
+}
+
+func testSearch(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	a := newProject(t, s, "Search A", "sra")
+	b := newProject(t, s, "Search B", "srb")
+
+	inTitle := newIssue(t, s, a.ID, "Flaky login test", 2)
+	inComment := newIssue(t, s, a.ID, "Checkout retries", 2)
+	comment, err := s.AddComment(ctx, inComment.ID, "alice", "The payment stub is flaky under load")
+	if err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	labeled := newIssue(t, s, b.ID, "Nightly build", 2)
+	if err := s.AddLabelToIssue(ctx, labeled.ID, "flaky", actor); err != nil {
+		t.Fatalf("AddLabelToIssue failed: %v", err)
+	}
+
+	search := func(filter types.SearchFilter) []*types.SearchHit {
+		t.Helper()
+		hits, err := s.SearchIssues(ctx, filter)
+		if err != nil {
+			t.Fatalf("SearchIssues(%+v) failed: %v", filter, err)
+		}
+		return hits
+	}
+	field := func(hits []*types.SearchHit, id string) types.SearchField {
+		for _, hit := range hits {
+			if hit.Issue.ID == id {
+				return hit.Field
+			}
+		}
+		return ""
+	}
+
+	hits := search(types.SearchFilter{Query: "flaky"})
+	if len(hits) != 3 || hits[0].Issue.ID != inTitle.ID {
+		t.Fatalf("search across projects = %d hits, want 3 with %s first", len(hits), inTitle.ID)
+	}
+	for id, want := range map[string]types.SearchField{
+		inTitle.ID:   types.SearchFieldTitle,
+		inComment.ID: types.SearchFieldComments,
+		labeled.ID:   types.SearchFieldLabels,
+	} {
+		if got := field(hits, id); got != want {
+			t.Errorf("hit %s matched %q, want %q", id, got, want)
+		}
+	}
+	for _, hit := range hits {
+		if !strings.Contains(strings.ToLower(hit.Snippet), types.HighlightStart+"flaky"+types.HighlightEnd) {
+			t.Errorf("snippet %q of %s does not highlight the match", hit.Snippet, hit.Issue.ID)
+		}
+	}
+
+	hits = search(types.SearchFilter{Query: "flaky", ProjectIDs: []string{b.ID}})
+	if len(hits) != 1 || hits[0].Issue.ID != labeled.ID {
+		t.Errorf("search in %s = %d hits, want only %s", b.ID, len(hits), labeled.ID)
+	}
+	if hits := search(types.SearchFilter{Query: "flaky", Limit: 1, Offset: 1}); len(hits) != 1 {
+		t.Errorf("paged search = %d hits, want 1", len(hits))
+	}
+
+	// Every term must match, in any field.
+	hits = search(types.SearchFilter{Query: "checkout payment"})
+	if len(hits) != 1 || hits[0].Issue.ID != inComment.ID || hits[0].Field != types.SearchFieldTitle {
+		t.Errorf("search for checkout payment = %+v, want %s matched on its title", hits, inComment.ID)
+	}
+
+	// Comment edits and label changes keep the index current.
+	if err := s.UpdateComment(ctx, comment.ID, "The payment stub times out"); err != nil {
+		t.Fatalf("UpdateComment failed: %v", err)
+	}
+	if err := s.RemoveLabelFromIssue(ctx, labeled.ID, "flaky", actor); err != nil {
+		t.Fatalf("RemoveLabelFromIssue failed: %v", err)
+	}
+	hits = search(types.SearchFilter{Query: "flaky"})
+	if len(hits) != 1 || hits[0].Issue.ID != inTitle.ID {
+		t.Errorf("search after edits = %d hits, want only %s", len(hits), inTitle.ID)
+	}
+	if hits := search(types.SearchFilter{Query: "   "}); len(hits) != 0 {
+		t.Errorf("blank search = %d hits, want none", len(hits))
+	}
+}
+
+func testMoveIssueFields(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	src := newProject(t, s, "Field Source", "fsrc")
+	dst := newProject(t, s, "Field Destination", "fdst")
+	for project, defs := range map[string]map[string]string{
+		src.ID: {"component": "enum:ui,api", "estimate": "number", "severity": "enum:low,high"},
+		dst.ID: {"component": "enum:ui,web", "estimate": "number"},
+	} {
+		for key, spec := range defs {
+			if err := s.SetProjectConfig(ctx, project, types.FieldConfigPrefix+key, spec); err != nil {
+				t.Fatalf("SetProjectConfig failed: %v", err)
+			}
+		}
+	}
+
+	root := newIssue(t, s, src.ID, "Checkout", 2)
+	child := newChild(t, s, root, "Card form")
+	for id, fields := range map[string]map[string]string{
+		root.ID:  {"component": "api", "estimate": "3", "severity": "high"},
+		child.ID: {"component": "ui"},
+	} {
+		if err := s.UpdateIssue(ctx, id, map[string]any{"fields": fields}, 0, actor); err != nil {
+			t.Fatalf("UpdateIssue(%s) failed: %v", id, err)
+		}
+	}
+
+	res, err := s.MoveIssue(ctx, root.ID, dst.ID, actor)
+	if err != nil {
+		t.Fatalf("MoveIssue failed: %v", err)
+	}
+	newRoot, movedChild := res.IDMap[root.ID], res.IDMap[child.ID]
+
+	// The destination has no severity field and no api component.
+	want := map[string][]string{newRoot: {"component", "severity"}}
+	if !reflect.DeepEqual(res.DroppedFields, want) {
+		t.Errorf("DroppedFields = %v, want %v", res.DroppedFields, want)
+	}
+	fields, err := s.GetFieldsForIssues(ctx, []string{newRoot, movedChild})
+	if err != nil {
+		t.Fatalf("GetFieldsForIssues failed: %v", err)
+	}
+	if got := fields[newRoot]; !reflect.DeepEqual(got, map[string]string{"estimate": "3"}) {
+		t.Errorf("moved root fields = %v, want only estimate=3", got)
+	}
+	if got := fields[movedChild]; !reflect.DeepEqual(got, map[string]string{"component": "ui"}) {
+		t.Errorf("moved child fields = %v, want component=ui", got)
+	}
+
+	events, err := s.GetEvents(ctx, newRoot, 0)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	var cleared []string
+	for _, e := range events {
+		if e.EventType == types.EventFieldChanged && e.NewValue == nil && e.OldValue != nil {
+			cleared = append(cleared, *e.OldValue)
+		}
+	}
+	slices.Sort(cleared)
+	if want := []string{"component=api", "severity=high"}; !slices.Equal(cleared, want) {
+		t.Errorf("field_changed events clearing %v, want %v", cleared, want)
+	}
+}
+
+func testPlanSearch(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	a := newProject(t, s, "Plan Search A", "psa")
+	b := newProject(t, s, "Plan Search B", "psb")
+	issue := newIssue(t, s, a.ID, "Auth rework", 2)
+
+	plan := &types.Plan{ID: "plan.search", FilePath: "/tmp/plan-search.md", Status: types.PlanStatusDraft}
+	if err := s.CreatePlan(ctx, plan); err != nil {
+		t.Fatalf("CreatePlan failed: %v", err)
+	}
+	if err := s.SetPlanText(ctx, plan.ID, "Rotate the signing keys weekly"); err != nil {
+		t.Fatalf("SetPlanText failed: %v", err)
+	}
+
+	search := func(query string) []*types.SearchHit {
+		t.Helper()
+		hits, err := s.SearchIssues(ctx, types.SearchFilter{Query: query})
+		if err != nil {
+			t.Fatalf("SearchIssues(%q) failed: %v", query, err)
+		}
+		return hits
+	}
+	linked := func(issueID string) []*types.Plan {
+		t.Helper()
+		plans, err := s.ListIssuePlans(ctx, issueID)
+		if err != nil {
+			t.Fatalf("ListIssuePlans failed: %v", err)
+		}
+		return plans
+	}
+
+	if hits := search("signing"); len(hits) != 0 {
+		t.Errorf("search before linking = %d hits, want none", len(hits))
+	}
+
+	// Linking twice is a no-op.
+	for range 2 {
+		if err := s.LinkIssuePlan(ctx, issue.ID, plan.ID); err != nil {
+			t.Fatalf("LinkIssuePlan failed: %v", err)
+		}
+	}
+	if plans := linked(issue.ID); len(plans) != 1 || plans[0].ID != plan.ID {
+		t.Errorf("ListIssuePlans = %+v, want only %s", plans, plan.ID)
+	}
+	if err := s.LinkIssuePlan(ctx, issue.ID, "plan.missing"); err == nil {
+		t.Error("expected error linking unknown plan")
+	}
+	if err := s.LinkIssuePlan(ctx, "psa-missing", plan.ID); err == nil {
+		t.Error("expected error linking unknown issue")
+	}
+	hits := search("signing")
+	if len(hits) != 1 || hits[0].Issue.ID != issue.ID || hits[0].Field != types.SearchFieldPlans {
+		t.Fatalf("search for linked plan = %+v, want %s matched on its plans", hits, issue.ID)
+	}
+	if !strings.Contains(hits[0].Snippet, types.HighlightStart+"signing"+types.HighlightEnd) {
+		t.Errorf("snippet %q does not highlight the match", hits[0].Snippet)
+	}
+
+	// New plan text replaces the old in the index.
+	if err := s.SetPlanText(ctx, plan.ID, "Rotate the session cookies"); err != nil {
+		t.Fatalf("SetPlanText failed: %v", err)
+	}
+	if hits := search("signing"); len(hits) != 0 {
+		t.Errorf("search for replaced plan text = %d hits, want none", len(hits))
+	}
+	if hits := search("cookies"); len(hits) != 1 {
+		t.Errorf("search for new plan text = %d hits, want 1", len(hits))
+	}
+
+	// Links follow an issue to its new ID.
+	moved, err := s.MoveIssue(ctx, issue.ID, b.ID, actor)
+	if err != nil {
+		t.Fatalf("MoveIssue failed: %v", err)
+	}
+	movedID := moved.Issue.ID
+	if plans := linked(movedID); len(plans) != 1 {
+		t.Errorf("ListIssuePlans after move = %d plans, want 1", len(plans))
+	}
+	if hits := search("cookies"); len(hits) != 1 || hits[0].Issue.ID != movedID {
+		t.Errorf("search after move = %+v, want %s", hits, movedID)
+	}
+
+	if err := s.UnlinkIssuePlan(ctx, movedID, plan.ID); err != nil {
+		t.Fatalf("UnlinkIssuePlan failed: %v", err)
+	}
+	if plans := linked(movedID); len(plans) != 0 {
+		t.Errorf("ListIssuePlans after unlink = %d plans, want none", len(plans))
+	}
+	if hits := search("cookies"); len(hits) != 0 {
+		t.Errorf("search after unlink = %d hits, want none", len(hits))
+	}
+
+	// Deleting a plan drops its links.
+	if err := s.LinkIssuePlan(ctx, movedID, plan.ID); err != nil {
+		t.Fatalf("LinkIssuePlan failed: %v", err)
+	}
+	if err := s.DeletePlan(ctx, plan.ID); err != nil {
+		t.Fatalf("DeletePlan failed: %v", err)
+	}
+	if plans := linked(movedID); len(plans) != 0 {
+		t.Errorf("ListIssuePlans after plan delete = %d plans, want none", len(plans))
+	}
+	if hits := search("cookies"); len(hits) != 0 {
+		t.Errorf("search after plan delete = %d hits, want none", len(hits))
+	}
+}
+
+func newProject(t *testing.T, s storage.Storage, name, prefix string) *types.Project {
+	t.Helper()
+	p := &types.Project{Name: name, Prefix: prefix}
+	if err := s.CreateProject(context.Background(), p); err != nil {
+		t.Fatalf("CreateProject(%s) failed: %v", name, err)
+	}
+	return p
+}
+
+func newIssue(t *testing.T, s storage.Storage, projectID, title string, priority int) *types.Issue {
+	t.Helper()
+	issue := &types.Issue{
+		ProjectID: projectID,
+		Title:     title,
+		Status:    types.StatusOpen,
+		Priority:  priority,
+		IssueType: types.TypeTask,
+	}
+	if err := s.CreateIssue(context.Background(), issue, actor); err != nil {
+		t.Fatalf("CreateIssue(%s) failed: %v", title, err)
+	}
+	return issue
+}
+
+func newChild(t *testing.T, s storage.Storage, parent *types.Issue, title string) *types.Issue {
+	t.Helper()
+	issue := &types.Issue{
+		ProjectID: parent.ProjectID,
+		ParentID:  parent.ID,
+		Title:     title,
+		Status:    types.StatusOpen,
+		Priority:  2,
+		IssueType: types.TypeTask,
+	}
+	if err := s.CreateIssue(context.Background(), issue, actor); err != nil {
+		t.Fatalf("CreateIssue(%s) failed: %v", title, err)
+	}
+	return issue
+}
+
+func getIssue(t *testing.T, s storage.Storage, id string) *types.Issue {
+	t.Helper()
+	issue, err := s.GetIssue(context.Background(), id)
+	if err != nil {
+		t.Fatalf("GetIssue(%s) failed: %v", id, err)
+	}
+	return issue
+}
+
+func readyIDs(t *testing.T, s storage.Storage, filter types.WorkFilter) []string {
+	t.Helper()
+	issues, err := s.GetReadyWork(context.Background(), filter)
+	if err != nil {
+		t.Fatalf("GetReadyWork failed: %v", err)
+	}
+	return issueIDs(issues)
+}
+
+func issueIDs(issues []*types.Issue) []string {
+	ids := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		ids = append(ids, issue.ID)
+	}
+	return ids
+}
+
+func sameSet(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
@@ -4,6 +4,10 @@ package types
 import (
 	"errors"
 	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -72,12 +76,21 @@ type Issue struct {
 	CloseReason string     `json:"close_reason,omitempty"`
 
 	// External Integration
-	ExternalRef string `json:"external_ref,omitempty"` // e.g., "gh-9", "jira-ABC"
+	ExternalRef string `json:"external_ref,omitempty"` // e.g., "owner/repo#9", "bd-a1b2", "jira-ABC"
+
+	// Optimistic Concurrency
+	Version int64 `json:"version,omitempty"` // Incremented by every update, close and reopen; served as the ETag
 
 	// Relational Data (populated for detail views)
 	Labels       []string      `json:"labels,omitempty"`
 	Dependencies []*Dependency `json:"dependencies,omitempty"`
 	Comments     []*Comment    `json:"comments,omitempty"`
+
+	// Custom field values, keyed by field (populated for list and detail views)
+	Fields map[string]string `json:"fields,omitempty"`
+
+	// Open issues that look like duplicates (populated when the issue is created)
+	PossibleDuplicates []*DuplicateMatch `json:"possible_duplicates,omitempty"`
 }
 
 // Validate checks if the issue has valid field values.
@@ -123,6 +136,10 @@ func (i *Issue) SetDefaults() {
 	}
 }
 
+// CloseReasonDuplicate is the close reason given to an issue merged into
+// another with MergeIssue.
+const CloseReasonDuplicate = "duplicate"
+
 // Status represents the current state of an issue.
 type Status string
 
@@ -219,12 +236,13 @@ const (
 	DepParentChild    DependencyType = "parent-child"
 	DepRelated        DependencyType = "related"
 	DepDiscoveredFrom DependencyType = "discovered-from"
+	DepDuplicates     DependencyType = "duplicates" // The issue was merged into the one it depends on
 )
 
 // IsValid checks if the dependency type value is valid.
 func (d DependencyType) IsValid() bool {
 	switch d {
-	case DepBlocks, DepParentChild, DepRelated, DepDiscoveredFrom:
+	case DepBlocks, DepParentChild, DepRelated, DepDiscoveredFrom, DepDuplicates:
 		return true
 	}
 	return false
@@ -237,7 +255,7 @@ func (d DependencyType) AffectsReadyWork() bool {
 
 // AllDependencyTypes returns all valid dependency type values.
 func AllDependencyTypes() []DependencyType {
-	return []DependencyType{DepBlocks, DepParentChild, DepRelated, DepDiscoveredFrom}
+	return []DependencyType{DepBlocks, DepParentChild, DepRelated, DepDiscoveredFrom, DepDuplicates}
 }
 
 // Label represents a global tag that can be applied to issues.
@@ -283,6 +301,15 @@ type Event struct {
 }
 
 // EventType categorizes audit trail events.
+//
+// Events that change an issue carry the value before and after: updated
+// and field_changed events hold one field each as "field=value",
+// status_changed and closed events hold the previous status in OldValue,
+// and reopened events hold the previous close reason. A moved event holds
+// the issue's previous and new ID, each as "id@project", in OldValue and
+// NewValue. An undone
+// event holds the ID of the event it reversed in OldValue and the
+// comma-separated IDs of the events recorded by the reversal in NewValue.
 type EventType string
 
 const (
@@ -297,21 +324,200 @@ const (
 	EventLabelAdded        EventType = "label_added"
 	EventLabelRemoved      EventType = "label_removed"
 	EventMerged            EventType = "merged"
+	EventMoved             EventType = "moved"
+	EventFieldChanged      EventType = "field_changed"
+	EventUndone            EventType = "undone"
 )
 
+// StreamEvent is a live change notification published after a successful
+// mutation and delivered to event stream subscribers. Unlike Event, it is
+// not persisted; Seq is a per-server sequence number that resets on restart.
+type StreamEvent struct {
+	Seq       uint64          `json:"seq"`
+	Type      StreamEventType `json:"type"`
+	ProjectID string          `json:"project_id,omitempty"`
+	IssueID   string          `json:"issue_id,omitempty"`
+	PlanID    string          `json:"plan_id,omitempty"`
+	SessionID string          `json:"session_id,omitempty"`
+	Actor     string          `json:"actor,omitempty"`
+	Data      map[string]any  `json:"data,omitempty"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// StreamEventType identifies the kind of change carried by a StreamEvent.
+// Values are "<resource>.<action>" so subscribers can filter by prefix.
+type StreamEventType string
+
+const (
+	StreamIssueCreated       StreamEventType = "issue.created"
+	StreamIssueUpdated       StreamEventType = "issue.updated"
+	StreamIssueStatusChanged StreamEventType = "issue.status_changed"
+	StreamIssueClosed        StreamEventType = "issue.closed"
+	StreamIssueReopened      StreamEventType = "issue.reopened"
+	StreamIssueDeleted       StreamEventType = "issue.deleted"
+	StreamIssueMerged        StreamEventType = "issue.merged"
+	StreamDependencyAdded    StreamEventType = "dependency.added"
+	StreamDependencyRemoved  StreamEventType = "dependency.removed"
+	StreamLabelAdded         StreamEventType = "label.added"
+	StreamLabelRemoved       StreamEventType = "label.removed"
+	StreamCommentAdded       StreamEventType = "comment.added"
+	StreamCommentUpdated     StreamEventType = "comment.updated"
+	StreamCommentDeleted     StreamEventType = "comment.deleted"
+	StreamPlanCreated        StreamEventType = "plan.created"
+	StreamPlanStatusChanged  StreamEventType = "plan.status_changed"
+	StreamPlanDeleted        StreamEventType = "plan.deleted"
+	StreamPlanCommentAdded   StreamEventType = "plan_comment.added"
+	StreamPlanCommentUpdated StreamEventType = "plan_comment.updated"
+	StreamPlanCommentDeleted StreamEventType = "plan_comment.deleted"
+	StreamAISessionCreated   StreamEventType = "ai_session.created"
+	StreamAIAgentRegistered  StreamEventType = "ai_agent.registered"
+)
+
+// StreamTypeForEvent maps an audit trail event type to the stream event
+// type published alongside it.
+func StreamTypeForEvent(et EventType) StreamEventType {
+	switch et {
+	case EventCreated:
+		return StreamIssueCreated
+	case EventStatusChanged:
+		return StreamIssueStatusChanged
+	case EventCommented:
+		return StreamCommentAdded
+	case EventClosed:
+		return StreamIssueClosed
+	case EventReopened:
+		return StreamIssueReopened
+	case EventDependencyAdded:
+		return StreamDependencyAdded
+	case EventDependencyRemoved:
+		return StreamDependencyRemoved
+	case EventLabelAdded:
+		return StreamLabelAdded
+	case EventLabelRemoved:
+		return StreamLabelRemoved
+	case EventMerged:
+		return StreamIssueMerged
+	default:
+		return StreamIssueUpdated
+	}
+}
+
 // IssueFilter is used to filter issue queries.
 type IssueFilter struct {
-	ProjectID   string      // Required: filter by project
-	Statuses    []Status    // Filter by statuses (multi-select, empty means all)
-	Priorities  []int       // Filter by priorities (multi-select, empty means all)
-	IssueTypes  []IssueType // Filter by issue types (multi-select, empty means all)
-	AISessionID *string     // Filter by AI session ID
-	Labels      []string    // AND semantics: issue must have ALL these labels
-	ParentID    string      // Filter by parent issue (via parent-child dependency)
-	Query       string      // Full-text search in title/description
-	IDs         []string    // Filter by specific issue IDs
-	Limit       int         // Maximum results to return
-	Offset      int         // Pagination offset
+	ProjectID   string            // Required: filter by project
+	Statuses    []Status          // Filter by statuses (multi-select, empty means all)
+	Priorities  []int             // Filter by priorities (multi-select, empty means all)
+	IssueTypes  []IssueType       // Filter by issue types (multi-select, empty means all)
+	AISessionID *string           // Filter by AI session ID
+	Labels      []string          // AND semantics: issue must have ALL these labels
+	ParentID    string            // Filter by parent issue (via parent-child dependency)
+	Query       string            // Full-text search in title/description
+	IDs         []string          // Filter by specific issue IDs
+	Fields      map[string]string // Custom field key -> exact value (AND semantics)
+
+	ExcludeStatuses   []Status    // Leave out these statuses
+	ExcludePriorities []int       // Leave out these priorities
+	ExcludeIssueTypes []IssueType // Leave out these issue types
+	ExcludeLabels     []string    // Leave out issues carrying ANY of these labels
+	MinPriority       *int        // Priority at least (numerically) this
+	MaxPriority       *int        // Priority at most (numerically) this
+	CreatedAfter      *time.Time  // Created at or after
+	CreatedBefore     *time.Time  // Created before
+	UpdatedAfter      *time.Time  // Updated at or after
+	UpdatedBefore     *time.Time  // Updated before
+	Text              []string    // Each a case-insensitive substring of title or description
+
+	// Or holds alternative groups of conditions: an issue must also match
+	// at least one of them. Only the condition fields above are read from
+	// a group.
+	Or []IssueFilter
+
+	Sort   []IssueSort // Result order; default is priority, then most recently updated
+	Limit  int         // Maximum results to return
+	Offset int         // Pagination offset
+}
+
+// IssueSortField names a column issue lists can be sorted by.
+type IssueSortField string
+
+// Sortable issue columns.
+const (
+	SortByPriority IssueSortField = "priority"
+	SortByCreated  IssueSortField = "created"
+	SortByUpdated  IssueSortField = "updated"
+	SortByRank     IssueSortField = "rank"
+	SortByTitle    IssueSortField = "title"
+	SortByStatus   IssueSortField = "status"
+	SortByType     IssueSortField = "type"
+	SortByID       IssueSortField = "id"
+)
+
+// IsValid checks if the sort field is one issues can be sorted by.
+func (f IssueSortField) IsValid() bool {
+	switch f {
+	case SortByPriority, SortByCreated, SortByUpdated, SortByRank,
+		SortByTitle, SortByStatus, SortByType, SortByID:
+		return true
+	}
+	return false
+}
+
+// IssueSort is one key of an issue list's order.
+type IssueSort struct {
+	Field IssueSortField
+	Desc  bool
+}
+
+// SearchFilter is used to run a ranked full-text search across issues.
+type SearchFilter struct {
+	Query      string
+	ProjectIDs []string // empty searches every project
+	Limit      int
+	Offset     int
+}
+
+// SearchField names the part of an issue a search hit matched.
+type SearchField string
+
+// Searchable fields, in the order a hit's field is chosen when several match.
+const (
+	SearchFieldTitle       SearchField = "title"
+	SearchFieldDescription SearchField = "description"
+	SearchFieldComments    SearchField = "comments"
+	SearchFieldLabels      SearchField = "labels"
+	SearchFieldPlans       SearchField = "plans"
+)
+
+// Markers wrapped around the matched terms of a search snippet.
+const (
+	HighlightStart = "**"
+	HighlightEnd   = "**"
+)
+
+// SearchHit is one result of a full-text search. Snippet is a short excerpt
+// of Field with the matched terms wrapped in HighlightStart and
+// HighlightEnd. Hits are ordered best first; Score is only comparable
+// within one result set.
+type SearchHit struct {
+	Issue   *Issue      `json:"issue"`
+	Field   SearchField `json:"field"`
+	Snippet string      `json:"snippet"`
+	Score   float64     `json:"score"`
+}
+
+// DuplicateMatch is an open issue that looks like a duplicate of another.
+// Score runs from 0 to 1, where 1 means the wording matches as closely as
+// the other issue matches itself.
+type DuplicateMatch struct {
+	Issue *Issue  `json:"issue"`
+	Score float64 `json:"score"`
+}
+
+// DuplicateCluster is a group of open issues that look like duplicates of
+// one another. Score is the highest similarity between two of its issues.
+type DuplicateCluster struct {
+	Issues []*Issue `json:"issues"`
+	Score  float64  `json:"score"`
 }
 
 // WorkFilter is used to filter ready work queries.
@@ -321,8 +527,11 @@ type WorkFilter struct {
 	IssueType  *IssueType // Filter by issue type
 	Priority   *int       // Filter by priority
 	Labels     []string   // AND semantics
+	ParentID   string     // Filter to children of this issue (e.g. an epic's)
 	SortPolicy SortPolicy // Sort policy: hybrid (default), priority, oldest
+	After      string     // Cursor: only issues sorted after this issue ID
 	Limit      int        // Maximum results
+	Offset     int        // Results to skip
 }
 
 // Statistics provides aggregate metrics for a project.
@@ -345,6 +554,27 @@ type MergeResult struct {
 	SourcesDeleted []string `json:"sources_deleted"`
 }
 
+// IssueMoveResult contains the outcome of moving an issue and its
+// descendants to another project.
+type IssueMoveResult struct {
+	Issue         *Issue            `json:"issue"` // the moved issue under its new ID
+	TargetProject *Project          `json:"target_project"`
+	IDMap         map[string]string `json:"id_map"` // old issue ID -> new issue ID
+	// DroppedFields maps new issue IDs to the keys of custom fields that
+	// were removed because the target project does not accept them.
+	DroppedFields map[string][]string `json:"dropped_fields,omitempty"`
+}
+
+// IssueMergeResult contains the outcome of merging a duplicate issue into
+// its canonical issue.
+type IssueMergeResult struct {
+	Canonical         *Issue `json:"canonical"`
+	Duplicate         *Issue `json:"duplicate"`
+	CommentsMoved     int    `json:"comments_moved"`
+	LabelsMoved       int    `json:"labels_moved"`
+	DependenciesMoved int    `json:"dependencies_moved"`
+}
+
 // Workspace represents a directory path associated with a project.
 // Multiple workspaces can be linked to a single project to support multi-directory projects.
 // Previously named WorkspacePath; renamed because this IS the workspace (a directory where work happens).
@@ -383,6 +613,120 @@ func (e *OpenChildrenError) Error() string {
 	return fmt.Sprintf("cannot close issue %s: %d open child issue(s)", e.IssueID, len(e.Children))
 }
 
+// DuplicateIssueError is returned when creating an issue that was asked to
+// refuse likely duplicates and one was found.
+type DuplicateIssueError struct {
+	Title   string            // The title of the issue that was not created
+	Matches []*DuplicateMatch // The open issues it looks like, best match first
+}
+
+// Error implements the error interface.
+func (e *DuplicateIssueError) Error() string {
+	if len(e.Matches) == 0 {
+		return fmt.Sprintf("issue %q looks like a duplicate", e.Title)
+	}
+	best := e.Matches[0]
+	return fmt.Sprintf("issue %q looks like a duplicate of %s (%.0f%% similar)",
+		e.Title, best.Issue.ID, best.Score*100)
+}
+
+// VersionConflictError is returned when a conditional update names a
+// version the issue has already moved past.
+type VersionConflictError struct {
+	IssueID  string // The issue that was not updated
+	Expected int64  // The version the caller based its update on
+	Current  int64  // The issue's version at the time of the update
+}
+
+// Error implements the error interface.
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("issue %s was modified concurrently: expected version %d, current version is %d",
+		e.IssueID, e.Expected, e.Current)
+}
+
+// DependencyCycleError is returned when adding a blocking dependency would
+// close a loop, leaving every issue on it blocked for good.
+type DependencyCycleError struct {
+	Path []string // Issue IDs around the cycle, starting and ending with the same issue
+}
+
+// Error implements the error interface.
+func (e *DependencyCycleError) Error() string {
+	return "dependency would create a cycle: " + strings.Join(e.Path, " -> ")
+}
+
+// DependencyProblemKind categorizes a dependency graph audit finding.
+type DependencyProblemKind string
+
+const (
+	// ProblemCycle is a loop of blocking dependencies.
+	ProblemCycle DependencyProblemKind = "cycle"
+	// ProblemDangling is a dependency on an issue that no longer exists.
+	ProblemDangling DependencyProblemKind = "dangling"
+	// ProblemCrossProject is a dependency on an issue in another project.
+	ProblemCrossProject DependencyProblemKind = "cross_project"
+	// ProblemParentMismatch is a child whose parent-child dependency
+	// disagrees with its hierarchical ID.
+	ProblemParentMismatch DependencyProblemKind = "parent_mismatch"
+)
+
+// DependencyProblem is one finding of a dependency graph audit.
+type DependencyProblem struct {
+	Kind       DependencyProblemKind `json:"kind"`
+	Cycle      []string              `json:"cycle,omitempty"`      // For cycles: issue IDs around the loop
+	Dependency *Dependency           `json:"dependency,omitempty"` // The offending edge, when there is one
+	Detail     string                `json:"detail"`
+}
+
+// DependencyReport is the result of auditing a project's dependency graph.
+type DependencyReport struct {
+	ProjectID    string               `json:"project_id"`
+	Issues       int                  `json:"issues"`       // Issues checked
+	Dependencies int                  `json:"dependencies"` // Dependencies checked
+	Problems     []*DependencyProblem `json:"problems"`
+}
+
+// Graph is a set of issues and the dependencies between them, for export
+// and visualization.
+type Graph struct {
+	ProjectID string       `json:"project_id"`
+	RootID    string       `json:"root_id,omitempty"` // The issue the graph was grown from, if any
+	Nodes     []*GraphNode `json:"nodes"`
+	Edges     []*GraphEdge `json:"edges"`
+}
+
+// GraphNode is an issue in a Graph.
+type GraphNode struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Status    Status    `json:"status"`
+	IssueType IssueType `json:"issue_type"`
+	Priority  int       `json:"priority"`
+	Depth     int       `json:"depth"` // Dependency hops from the root; 0 without one
+}
+
+// GraphEdge is a dependency in a Graph: From depends on To.
+type GraphEdge struct {
+	From string         `json:"from"`
+	To   string         `json:"to"`
+	Type DependencyType `json:"type"`
+}
+
+// CriticalPath is the longest chain of open issues gating an issue's
+// completion, in the order they have to be finished.
+type CriticalPath struct {
+	RootID     string    `json:"root_id"`
+	Path       []*Issue  `json:"path"`                 // Ends with the root; empty if it is closed
+	Bottleneck *Unblocks `json:"bottleneck,omitempty"` // The issue gating the root that the most work waits on
+}
+
+// Unblocks is the open work waiting on an issue.
+type Unblocks struct {
+	IssueID string   `json:"issue_id"`
+	Ready   []*Issue `json:"ready"`   // Issues that become ready as soon as it closes
+	Waiting []*Issue `json:"waiting"` // Every issue that transitively waits on it, Ready included
+}
+
 // BlockedIssue extends Issue with blocking information.
 type BlockedIssue struct {
 	Issue
@@ -399,6 +743,24 @@ type IssueDetails struct {
 	Comments     []*Comment    `json:"comments,omitempty"`
 }
 
+// IssueHistory is an issue as it was at a point in time, with the audit
+// trail that led up to it, oldest first.
+type IssueHistory struct {
+	Issue  *Issue    `json:"issue"`
+	At     time.Time `json:"at"`
+	Events []*Event  `json:"events"`
+}
+
+// UndoStep is the reversal of one audit trail event. Action describes the
+// inverse operation; Skipped explains why a step cannot be applied.
+type UndoStep struct {
+	Event   *Event `json:"event"`
+	Action  string `json:"action"`
+	Skipped string `json:"skipped,omitempty"`
+	Applied bool   `json:"applied"`
+	Error   string `json:"error,omitempty"`
+}
+
 // Plan status constants.
 const (
 	PlanStatusDraft            = "draft"
@@ -489,3 +851,360 @@ type ProjectResolution struct {
 	ProjectName string `json:"project_name"`
 	PathID      string `json:"path_id"`
 }
+
+// Webhook delivery statuses.
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// Webhook is a per-project subscription that receives a signed HTTP POST
+// for every matching change event.
+type Webhook struct {
+	ID        string `json:"id"`
+	ProjectID string `json:"project_id"`
+	URL       string `json:"url"`
+	// Secret keys the HMAC-SHA256 payload signature. It is only returned
+	// when the webhook is created.
+	Secret string `json:"secret,omitempty"`
+	// EventTypes lists accepted stream event types; entries ending in ".*"
+	// match by prefix. Empty means every event.
+	EventTypes []string  `json:"event_types,omitempty"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Validate checks that the webhook has a usable target URL.
+func (w *Webhook) Validate() error {
+	if w.ProjectID == "" {
+		return errors.New("project_id is required")
+	}
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url must be an absolute http(s) URL")
+	}
+	return nil
+}
+
+// WebhookDelivery is one queued or attempted POST of an event to a webhook.
+// Deliveries double as the delivery log.
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	WebhookID      string          `json:"webhook_id"`
+	EventType      StreamEventType `json:"event_type"`
+	Payload        string          `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastAttemptAt  *time.Time      `json:"last_attempt_at,omitempty"`
+	ResponseStatus *int            `json:"response_status,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
+
+// APIToken is a bearer credential that authenticates API requests as Actor.
+// Only a hash of the token is stored; the plaintext Token is returned once,
+// when the token is created.
+type APIToken struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Actor string `json:"actor"`
+	// Prefix is the start of the token, shown to help identify it.
+	Prefix     string     `json:"prefix"`
+	Token      string     `json:"token,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// ProjectRole is a member's level of access to a project. Each role
+// includes the permissions of the roles before it.
+type ProjectRole string
+
+const (
+	RoleViewer      ProjectRole = "viewer"
+	RoleContributor ProjectRole = "contributor"
+	RoleMaintainer  ProjectRole = "maintainer"
+	RoleAdmin       ProjectRole = "admin"
+)
+
+// IsValid checks if the role value is valid.
+func (r ProjectRole) IsValid() bool {
+	return r.rank() > 0
+}
+
+// Includes reports whether r grants at least the access of other.
+func (r ProjectRole) Includes(other ProjectRole) bool {
+	return r.rank() >= other.rank() && r.IsValid()
+}
+
+func (r ProjectRole) rank() int {
+	switch r {
+	case RoleViewer:
+		return 1
+	case RoleContributor:
+		return 2
+	case RoleMaintainer:
+		return 3
+	case RoleAdmin:
+		return 4
+	}
+	return 0
+}
+
+// AllProjectRoles returns all valid roles, from least to most privileged.
+func AllProjectRoles() []ProjectRole {
+	return []ProjectRole{RoleViewer, RoleContributor, RoleMaintainer, RoleAdmin}
+}
+
+// ProjectMember grants an actor a role on a project. A project without
+// members is open to every caller; once it has members, only they can
+// access it.
+type ProjectMember struct {
+	ProjectID string      `json:"project_id"`
+	Actor     string      `json:"actor"`
+	Role      ProjectRole `json:"role"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// ProjectArchive is a complete copy of one project, used to move it between
+// servers. Issues carry their label names in Labels and their custom field
+// values in Fields; Labels holds the global definitions of those labels,
+// and Config the field definitions. Plans are those whose file lives under one
+// of the project's workspaces; plan file contents are not included.
+type ProjectArchive struct {
+	Project      *Project          `json:"project"`
+	Config       map[string]string `json:"config,omitempty"`
+	Workspaces   []*Workspace      `json:"workspaces,omitempty"`
+	Labels       []*Label          `json:"labels,omitempty"`
+	Issues       []*Issue          `json:"issues,omitempty"`
+	Dependencies []*Dependency     `json:"dependencies,omitempty"`
+	Comments     []*Comment        `json:"comments,omitempty"`
+	Events       []*Event          `json:"events,omitempty"`
+	Plans        []*Plan           `json:"plans,omitempty"`
+	PlanComments []*PlanComment    `json:"plan_comments,omitempty"`
+}
+
+// ImportResult summarizes a project import.
+type ImportResult struct {
+	Project           *Project `json:"project"`
+	Issues            int      `json:"issues"`
+	Dependencies      int      `json:"dependencies"`
+	Comments          int      `json:"comments"`
+	Events            int      `json:"events"`
+	Plans             int      `json:"plans"`
+	Workspaces        int      `json:"workspaces"`
+	SkippedWorkspaces int      `json:"skipped_workspaces,omitempty"`
+	// IDMap maps original issue IDs to their new IDs when they were rewritten.
+	IDMap map[string]string `json:"id_map,omitempty"`
+}
+
+// BeadsImportResult summarizes an import of beads issues into a project.
+type BeadsImportResult struct {
+	Created      int `json:"created"`
+	Skipped      int `json:"skipped"` // already imported on an earlier run
+	Ignored      int `json:"ignored"` // deleted (tombstoned) in beads
+	Dependencies int `json:"dependencies"`
+	Comments     int `json:"comments"`
+	// IDMap maps beads IDs to the arc issues they were imported as.
+	IDMap    map[string]string `json:"id_map,omitempty"`
+	Warnings []string          `json:"warnings,omitempty"`
+}
+
+// SyncReport summarizes a sync run with an external issue tracker. In a
+// dry run, Actions lists what would have been done and nothing is written.
+type SyncReport struct {
+	Repo      string          `json:"repo"`
+	DryRun    bool            `json:"dry_run"`
+	Pulled    int             `json:"pulled"`
+	Pushed    int             `json:"pushed"`
+	Actions   []*SyncAction   `json:"actions"`
+	Conflicts []*SyncConflict `json:"conflicts,omitempty"`
+	Warnings  []string        `json:"warnings,omitempty"`
+	Cursor    time.Time       `json:"cursor"`
+}
+
+// SyncAction is one change made (or planned) by a sync run.
+type SyncAction struct {
+	Direction   string `json:"direction"` // "pull" or "push"
+	Kind        string `json:"kind"`      // "create", "update", "close", "reopen", "comment", "label"
+	IssueID     string `json:"issue_id,omitempty"`
+	ExternalRef string `json:"external_ref"`
+	Detail      string `json:"detail,omitempty"`
+}
+
+// SyncConflict is an issue changed on both sides since the last sync. Its
+// fields are left untouched on both sides until the conflict is resolved
+// by hand; comments still sync.
+type SyncConflict struct {
+	IssueID         string    `json:"issue_id"`
+	ExternalRef     string    `json:"external_ref"`
+	LocalUpdatedAt  time.Time `json:"local_updated_at"`
+	RemoteUpdatedAt time.Time `json:"remote_updated_at"`
+}
+
+// IssueTemplate is a stored issue template. Content is the template's YAML
+// source; a template with a Recurrence is instantiated by the server each
+// time NextRunAt comes due.
+type IssueTemplate struct {
+	ProjectID  string     `json:"project_id"`
+	Name       string     `json:"name"`
+	Content    string     `json:"content"`
+	Recurrence string     `json:"recurrence,omitempty"` // cron expression
+	NextRunAt  *time.Time `json:"next_run_at,omitempty"`
+	LastRunAt  *time.Time `json:"last_run_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TemplateInstance lists the issues created from one instantiation of a
+// template. RootID is the top of the created tree.
+type TemplateInstance struct {
+	Template string   `json:"template"`
+	RootID   string   `json:"root_id"`
+	IssueIDs []string `json:"issue_ids"`
+}
+
+// FieldConfigPrefix prefixes the project config keys that define custom
+// issue fields. The key "field.estimate" with value "number" defines a
+// numeric field named estimate.
+const FieldConfigPrefix = "field."
+
+// FieldType is the value type of a custom issue field.
+type FieldType string
+
+// Custom field types. An enum definition lists its options after a colon,
+// e.g. "enum:ui,api,storage".
+const (
+	FieldString FieldType = "string"
+	FieldNumber FieldType = "number"
+	FieldEnum   FieldType = "enum"
+	FieldDate   FieldType = "date" // YYYY-MM-DD
+	FieldUser   FieldType = "user"
+)
+
+// ErrInvalidField reports a custom field that is undefined or a value that
+// does not fit its definition.
+var ErrInvalidField = errors.New("invalid custom field")
+
+// FieldDef is a custom issue field defined for a project.
+type FieldDef struct {
+	Key     string    `json:"key"`
+	Type    FieldType `json:"type"`
+	Options []string  `json:"options,omitempty"` // enum only
+}
+
+// ParseFieldDef parses the definition of field key from its config value.
+func ParseFieldDef(key, spec string) (*FieldDef, error) {
+	if !validFieldKey(key) {
+		return nil, fmt.Errorf("invalid field name %q (use lowercase letters, digits, - and _)", key)
+	}
+	typ, opts, hasOpts := strings.Cut(strings.TrimSpace(spec), ":")
+	def := &FieldDef{Key: key, Type: FieldType(typ)}
+	switch def.Type {
+	case FieldString, FieldNumber, FieldDate, FieldUser:
+		if hasOpts {
+			return nil, fmt.Errorf("field %s: only enum fields take options", key)
+		}
+	case FieldEnum:
+		for opt := range strings.SplitSeq(opts, ",") {
+			if opt = strings.TrimSpace(opt); opt != "" && !slices.Contains(def.Options, opt) {
+				def.Options = append(def.Options, opt)
+			}
+		}
+		if len(def.Options) == 0 {
+			return nil, fmt.Errorf("field %s: enum needs options, e.g. enum:low,high", key)
+		}
+	default:
+		return nil, fmt.Errorf("field %s: unknown type %q (want string, number, enum, date or user)", key, typ)
+	}
+	return def, nil
+}
+
+// Spec returns the config value that defines d.
+func (d *FieldDef) Spec() string {
+	if d.Type == FieldEnum {
+		return string(FieldEnum) + ":" + strings.Join(d.Options, ",")
+	}
+	return string(d.Type)
+}
+
+// Normalize checks value against the field's type and returns it in
+// canonical form, so equal values compare equal when filtering.
+func (d *FieldDef) Normalize(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	switch d.Type {
+	case FieldNumber:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s must be a number (got %q)", ErrInvalidField, d.Key, value)
+		}
+		return strconv.FormatFloat(n, 'f', -1, 64), nil
+	case FieldEnum:
+		if !slices.Contains(d.Options, value) {
+			return "", fmt.Errorf("%w: %s must be one of %s (got %q)",
+				ErrInvalidField, d.Key, strings.Join(d.Options, ", "), value)
+		}
+	case FieldDate:
+		if _, err := time.Parse(time.DateOnly, value); err != nil {
+			return "", fmt.Errorf("%w: %s must be a YYYY-MM-DD date (got %q)", ErrInvalidField, d.Key, value)
+		}
+	case FieldUser:
+		if strings.ContainsFunc(value, func(r rune) bool { return r == ' ' || r == '\t' || r == '\n' }) {
+			return "", fmt.Errorf("%w: %s must be a single user name (got %q)", ErrInvalidField, d.Key, value)
+		}
+	}
+	if value == "" {
+		return "", fmt.Errorf("%w: %s must not be empty", ErrInvalidField, d.Key)
+	}
+	return value, nil
+}
+
+// FieldDefs extracts the custom field definitions from a project's config.
+// Malformed definitions are skipped; they are rejected when written.
+func FieldDefs(config map[string]string) map[string]*FieldDef {
+	defs := make(map[string]*FieldDef)
+	for k, v := range config {
+		key, ok := strings.CutPrefix(k, FieldConfigPrefix)
+		if !ok {
+			continue
+		}
+		if def, err := ParseFieldDef(key, v); err == nil {
+			defs[key] = def
+		}
+	}
+	return defs
+}
+
+// RejectedFields returns, sorted, the keys of field values that defs do not
+// accept: keys they do not define and values their definitions reject. An
+// issue moving to another project loses these fields.
+func RejectedFields(defs map[string]*FieldDef, fields map[string]string) []string {
+	var rejected []string
+	for key, value := range fields {
+		def, ok := defs[key]
+		if !ok {
+			rejected = append(rejected, key)
+			continue
+		}
+		if _, err := def.Normalize(value); err != nil {
+			rejected = append(rejected, key)
+		}
+	}
+	slices.Sort(rejected)
+	return rejected
+}
+
+func validFieldKey(key string) bool {
+	if key == "" || len(key) > 64 || key[0] < 'a' || key[0] > 'z' {
+		return false
+	}
+	for _, c := range key {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' && c != '_' {
+			return false
+		}
+	}
+	return true
+}
@@ -90,6 +90,7 @@ func TestDependencyTypeIsValid(t *testing.T) {
 		{"parent-child", DepParentChild, true},
 		{"related", DepRelated, true},
 		{"discovered-from", DepDiscoveredFrom, true},
+		{"duplicates", DepDuplicates, true},
 		{"empty", DependencyType(""), false},
 		{"invalid", DependencyType("after"), false},
 	}
@@ -113,6 +114,7 @@ func TestDependencyTypeAffectsReadyWork(t *testing.T) {
 		{"parent-child affects ready work", DepParentChild, true},
 		{"related does not affect ready work", DepRelated, false},
 		{"discovered-from does not affect ready work", DepDiscoveredFrom, false},
+		{"duplicates does not affect ready work", DepDuplicates, false},
 	}
 
 	for _, tt := range tests {
@@ -402,7 +404,7 @@ func TestAllIssueTypes(t *testing.T) {
 
 func TestAllDependencyTypes(t *testing.T) {
 	types := AllDependencyTypes()
-	expected := []DependencyType{DepBlocks, DepParentChild, DepRelated, DepDiscoveredFrom}
+	expected := []DependencyType{DepBlocks, DepParentChild, DepRelated, DepDiscoveredFrom, DepDuplicates}
 
 	if len(types) != len(expected) {
 		t.Errorf("AllDependencyTypes() returned %d items, want %d", len(types), len(expected))
@@ -843,3 +845,102 @@ func TestAIAgentOptionalFields(t *testing.T) {
 		t.Errorf("AIAgent.ToolUseCount should be nil by default, got %v", agent.ToolUseCount)
 	}
 }
+
+func TestProjectRoleIncludes(t *testing.T) {
+	tests := []struct {
+		role  ProjectRole
+		other ProjectRole
+		want  bool
+	}{
+		{RoleAdmin, RoleViewer, true},
+		{RoleMaintainer, RoleMaintainer, true},
+		{RoleContributor, RoleMaintainer, false},
+		{RoleViewer, RoleContributor, false},
+		{ProjectRole("owner"), RoleViewer, false},
+		{ProjectRole(""), ProjectRole(""), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s includes %s", tt.role, tt.other), func(t *testing.T) {
+			if got := tt.role.Includes(tt.other); got != tt.want {
+				t.Errorf("ProjectRole.Includes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFieldDef(t *testing.T) {
+	tests := []struct {
+		key, spec string
+		want      string // canonical spec, or "" for an error
+	}{
+		{"estimate", "number", "number"},
+		{"component", "enum: ui, api,ui,,storage", "enum:ui,api,storage"},
+		{"due", " date ", "date"},
+		{"customer", "string", "string"},
+		{"owner", "user", "user"},
+		{"component", "enum", ""},
+		{"component", "enum:", ""},
+		{"estimate", "number:1,2", ""},
+		{"estimate", "float", ""},
+		{"Estimate", "number", ""},
+		{"1st", "string", ""},
+		{"", "string", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.spec, func(t *testing.T) {
+			def, err := ParseFieldDef(tt.key, tt.spec)
+			if tt.want == "" {
+				if err == nil {
+					t.Errorf("ParseFieldDef() = %+v, want error", def)
+				}
+				return
+			}
+			if err != nil || def.Spec() != tt.want {
+				t.Errorf("ParseFieldDef() = %+v, %v, want spec %q", def, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldDefNormalize(t *testing.T) {
+	defs := FieldDefs(map[string]string{
+		"field.estimate":  "number",
+		"field.component": "enum:ui,api",
+		"field.due":       "date",
+		"field.owner":     "user",
+		"field.customer":  "string",
+		"field.broken":    "float",
+		"plans.dir":       "/tmp",
+	})
+	if len(defs) != 5 {
+		t.Fatalf("FieldDefs() = %v, want 5 definitions", defs)
+	}
+
+	tests := []struct {
+		key, value, want string
+		wantErr          bool
+	}{
+		{"estimate", "3.50", "3.5", false},
+		{"estimate", " 8 ", "8", false},
+		{"estimate", "lots", "", true},
+		{"component", "ui", "ui", false},
+		{"component", "db", "", true},
+		{"due", "2026-05-01", "2026-05-01", false},
+		{"due", "May 1", "", true},
+		{"owner", "alice", "alice", false},
+		{"owner", "alice bob", "", true},
+		{"customer", "Acme Corp", "Acme Corp", false},
+		{"customer", "  ", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s=%s", tt.key, tt.value), func(t *testing.T) {
+			got, err := defs[tt.key].Normalize(tt.value)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("Normalize(%q) = %q, %v", tt.value, got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,433 @@
+// Package undo reverses recent issue mutations using the audit trail.
+//
+// Each reversible event maps to an inverse operation performed through the
+// regular storage methods, so the reversal is recorded like any other
+// change. An undone event then links the original event to the events the
+// reversal recorded, and all of them are passed over when choosing what to
+// undo next.
+package undo
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sentiolabs/arc/internal/history"
+	"github.com/sentiolabs/arc/internal/storage"
+	"github.com/sentiolabs/arc/internal/types"
+)
+
+// ScanLimit is how many of a project's latest events Plan searches.
+const ScanLimit = 500
+
+// producedLimit bounds the events read back after applying one step; a
+// reversal records at most a handful.
+const producedLimit = 20
+
+// dependencyValue matches the values of dependency_added and
+// dependency_removed events.
+var dependencyValue = regexp.MustCompile(`^(\S+) (?:no longer )?depends on (\S+)(?: \(([^)]+)\))?$`)
+
+// Plan returns the steps that undo the last n reversible events in a
+// project, newest first. When actor is set only that actor's events are
+// considered. Steps whose issue has changed since the event, or has been
+// merged into another, are marked skipped; the rest are checked as if the
+// steps before them had been applied.
+func Plan(ctx context.Context, store storage.Storage, projectID, actor string, n int) ([]*types.UndoStep, error) {
+	events, err := store.GetRecentEvents(ctx, projectID, ScanLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	done := undoneEvents(events)
+	p := newPlanner(store)
+	steps := []*types.UndoStep{}
+	for _, e := range events {
+		if len(steps) == n {
+			break
+		}
+		if done[e.ID] || !Reversible(e.EventType) || (actor != "" && e.Actor != actor) {
+			continue
+		}
+		step, err := p.step(ctx, e)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// PlanEvents returns the steps that undo exactly the given events of a
+// project, newest first, checked the same way as Plan. Events that have
+// been undone since, cannot be undone, or are no longer among the project's
+// latest events are returned as skipped steps, so a confirmed preview
+// never grows into changes the user did not see.
+func PlanEvents(ctx context.Context, store storage.Storage, projectID string, ids []int64) ([]*types.UndoStep, error) {
+	events, err := store.GetRecentEvents(ctx, projectID, ScanLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	done := undoneEvents(events)
+	p := newPlanner(store)
+	steps := []*types.UndoStep{}
+	for _, e := range events {
+		if !wanted[e.ID] {
+			continue
+		}
+		delete(wanted, e.ID)
+
+		var step *types.UndoStep
+		switch {
+		case done[e.ID]:
+			step = &types.UndoStep{Event: e, Action: describe(e), Skipped: "already undone"}
+		case !Reversible(e.EventType):
+			step = &types.UndoStep{Event: e, Skipped: fmt.Sprintf("%s events cannot be undone", e.EventType)}
+		default:
+			if step, err = p.step(ctx, e); err != nil {
+				return nil, err
+			}
+		}
+		steps = append(steps, step)
+	}
+
+	missing := slices.Sorted(maps.Keys(wanted))
+	for _, id := range slices.Backward(missing) {
+		steps = append(steps, &types.UndoStep{
+			Event:   &types.Event{ID: id},
+			Skipped: "not among the project's recent changes",
+		})
+	}
+	return steps, nil
+}
+
+// undoneEvents returns the IDs of the undone events among events and of
+// the events they link to, which are passed over when choosing what to
+// undo.
+func undoneEvents(events []*types.Event) map[int64]bool {
+	done := make(map[int64]bool)
+	for _, e := range events {
+		if e.EventType == types.EventUndone {
+			done[e.ID] = true
+			for _, id := range linkedIDs(e) {
+				done[id] = true
+			}
+		}
+	}
+	return done
+}
+
+// planner checks events against the state of their issues, updating the
+// state as if each planned step had been applied.
+type planner struct {
+	store  storage.Storage
+	states map[string]*types.Issue
+	merged map[string]string
+}
+
+// newPlanner returns a planner that reads issue state from store.
+func newPlanner(store storage.Storage) *planner {
+	return &planner{store: store, states: make(map[string]*types.Issue), merged: make(map[string]string)}
+}
+
+// step returns the step that undoes e, skipped when it conflicts with the
+// issue's state.
+func (p *planner) step(ctx context.Context, e *types.Event) (*types.UndoStep, error) {
+	state, ok := p.states[e.IssueID]
+	if !ok {
+		current, err := p.store.ResolveIssueAlias(ctx, e.IssueID)
+		if err != nil {
+			return nil, err
+		}
+		if current != e.IssueID {
+			p.merged[e.IssueID] = fmt.Sprintf("issue %s has been merged into %s", e.IssueID, current)
+		} else if state, err = issueState(ctx, p.store, e.IssueID); err != nil {
+			return nil, err
+		}
+		p.states[e.IssueID] = state
+	}
+
+	step := &types.UndoStep{Event: e, Action: describe(e)}
+	if reason := p.merged[e.IssueID]; reason != "" {
+		step.Skipped = reason
+	} else if reason := conflict(state, e); reason != "" {
+		step.Skipped = reason
+	} else {
+		history.Revert(state, e, nil)
+	}
+	return step, nil
+}
+
+// Apply performs the steps that are not skipped, in order, as actor. Each
+// applied step is followed by an undone event on its issue. Apply stops at
+// the first step that fails, recording the failure on the step.
+func Apply(ctx context.Context, store storage.Storage, steps []*types.UndoStep, actor string) error {
+	for _, step := range steps {
+		if step.Skipped != "" {
+			continue
+		}
+		e := step.Event
+
+		var watermark int64
+		if latest, err := store.GetEvents(ctx, e.IssueID, 1); err == nil && len(latest) > 0 {
+			watermark = latest[0].ID
+		}
+
+		if err := invert(ctx, store, e, actor); err != nil {
+			step.Error = err.Error()
+			return fmt.Errorf("undo event %d: %w", e.ID, err)
+		}
+
+		var produced []string
+		if recent, err := store.GetEvents(ctx, e.IssueID, producedLimit); err == nil {
+			for _, r := range slices.Backward(recent) {
+				if r.ID > watermark && r.EventType != types.EventUndone {
+					produced = append(produced, strconv.FormatInt(r.ID, 10))
+				}
+			}
+		}
+		undone := strconv.FormatInt(e.ID, 10)
+		record := &types.Event{IssueID: e.IssueID, EventType: types.EventUndone, Actor: actor, OldValue: &undone}
+		if len(produced) > 0 {
+			joined := strings.Join(produced, ",")
+			record.NewValue = &joined
+		}
+		if err := store.AddEvent(ctx, record); err != nil {
+			step.Error = err.Error()
+			return fmt.Errorf("record undo of event %d: %w", e.ID, err)
+		}
+		step.Applied = true
+	}
+	return nil
+}
+
+// Reversible reports whether events of a type can be undone.
+func Reversible(et types.EventType) bool {
+	switch et {
+	case types.EventUpdated, types.EventFieldChanged, types.EventStatusChanged,
+		types.EventClosed, types.EventReopened,
+		types.EventLabelAdded, types.EventLabelRemoved,
+		types.EventDependencyAdded, types.EventDependencyRemoved:
+		return true
+	}
+	return false
+}
+
+// linkedIDs returns the IDs an undone event refers to: the event it
+// reversed and the events the reversal recorded.
+func linkedIDs(e *types.Event) []int64 {
+	var ids []int64
+	if e.OldValue != nil {
+		if id, err := strconv.ParseInt(*e.OldValue, 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	if e.NewValue != nil {
+		for _, s := range strings.Split(*e.NewValue, ",") {
+			if id, err := strconv.ParseInt(s, 10, 64); err == nil {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// issueState reads the parts of an issue that undo steps are checked
+// against.
+func issueState(ctx context.Context, store storage.Storage, id string) (*types.Issue, error) {
+	issue, err := store.GetIssue(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if issue.Labels, err = store.GetIssueLabels(ctx, id); err != nil {
+		return nil, err
+	}
+	fields, err := store.GetFieldsForIssues(ctx, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	issue.Fields = make(map[string]string, len(fields[id]))
+	for k, v := range fields[id] {
+		issue.Fields[k] = v
+	}
+	return issue, nil
+}
+
+// conflict explains why e cannot be undone on an issue in state, or
+// returns "" when it can.
+//
+//nolint:gocognit,cyclop // one case per event type
+func conflict(state *types.Issue, e *types.Event) string {
+	const noOldValue = "recorded without its previous value"
+	switch e.EventType {
+	case types.EventUpdated:
+		field, _, ok := history.ParseValue(e.OldValue)
+		if !ok {
+			return noOldValue
+		}
+		if _, after, _ := history.ParseValue(e.NewValue); history.FieldValue(state, field) != after {
+			return field + " has changed since"
+		}
+	case types.EventFieldChanged:
+		key, _, hadOld := history.ParseValue(e.OldValue)
+		newKey, after, hasNew := history.ParseValue(e.NewValue)
+		if !hadOld {
+			key = newKey
+		}
+		if current, has := state.Fields[key]; has != hasNew || current != after {
+			return "field " + key + " has changed since"
+		}
+	case types.EventStatusChanged:
+		if e.OldValue == nil {
+			return noOldValue
+		}
+		if e.NewValue == nil || string(state.Status) != *e.NewValue {
+			return "status has changed since"
+		}
+	case types.EventClosed:
+		if state.Status != types.StatusClosed {
+			return "issue is no longer closed"
+		}
+	case types.EventReopened:
+		if state.Status == types.StatusClosed {
+			return "issue has been closed again"
+		}
+	case types.EventLabelAdded:
+		if e.NewValue == nil || !slices.Contains(state.Labels, *e.NewValue) {
+			return "label is no longer on the issue"
+		}
+	case types.EventLabelRemoved:
+		if e.OldValue == nil || slices.Contains(state.Labels, *e.OldValue) {
+			return "label is back on the issue"
+		}
+	case types.EventDependencyAdded, types.EventDependencyRemoved:
+		if _, _, _, ok := parseDependency(e); !ok {
+			return "unrecognized dependency"
+		}
+	}
+	return ""
+}
+
+// describe returns a human-readable summary of the operation that undoes e.
+func describe(e *types.Event) string {
+	id := e.IssueID
+	switch e.EventType {
+	case types.EventUpdated:
+		field, before, _ := history.ParseValue(e.OldValue)
+		return fmt.Sprintf("set %s of %s back to %q", field, id, before)
+	case types.EventFieldChanged:
+		if key, before, ok := history.ParseValue(e.OldValue); ok {
+			return fmt.Sprintf("set field %s of %s back to %q", key, id, before)
+		}
+		key, _, _ := history.ParseValue(e.NewValue)
+		return fmt.Sprintf("clear field %s of %s", key, id)
+	case types.EventStatusChanged:
+		return fmt.Sprintf("set status of %s back to %s", id, deref(e.OldValue))
+	case types.EventClosed:
+		if status := deref(e.OldValue); status != "" && status != string(types.StatusOpen) {
+			return fmt.Sprintf("reopen %s as %s", id, status)
+		}
+		return "reopen " + id
+	case types.EventReopened:
+		if reason := deref(e.OldValue); reason != "" {
+			return fmt.Sprintf("close %s again (%s)", id, reason)
+		}
+		return fmt.Sprintf("close %s again", id)
+	case types.EventLabelAdded:
+		return fmt.Sprintf("remove label %s from %s", deref(e.NewValue), id)
+	case types.EventLabelRemoved:
+		return fmt.Sprintf("add label %s back to %s", deref(e.OldValue), id)
+	case types.EventDependencyAdded:
+		issueID, dependsOnID, _, _ := parseDependency(e)
+		return fmt.Sprintf("remove dependency %s -> %s", issueID, dependsOnID)
+	case types.EventDependencyRemoved:
+		issueID, dependsOnID, depType, _ := parseDependency(e)
+		return fmt.Sprintf("restore dependency %s -> %s (%s)", issueID, dependsOnID, depType)
+	}
+	return ""
+}
+
+// invert performs the operation that undoes e.
+func invert(ctx context.Context, store storage.Storage, e *types.Event, actor string) error {
+	id := e.IssueID
+	switch e.EventType {
+	case types.EventUpdated:
+		field, before, _ := history.ParseValue(e.OldValue)
+		var value any = before
+		if field == "priority" || field == "rank" {
+			n, err := strconv.Atoi(before)
+			if err != nil {
+				return fmt.Errorf("invalid %s %q", field, before)
+			}
+			value = n
+		}
+		return store.UpdateIssue(ctx, id, map[string]any{field: value}, 0, actor)
+	case types.EventFieldChanged:
+		key, before, ok := history.ParseValue(e.OldValue)
+		if !ok {
+			key, _, _ = history.ParseValue(e.NewValue)
+		}
+		return store.UpdateIssue(ctx, id, map[string]any{"fields": map[string]string{key: before}}, 0, actor)
+	case types.EventStatusChanged:
+		return store.UpdateIssue(ctx, id, map[string]any{"status": deref(e.OldValue)}, 0, actor)
+	case types.EventClosed:
+		if err := store.ReopenIssue(ctx, id, actor); err != nil {
+			return err
+		}
+		if status := deref(e.OldValue); status != "" && status != string(types.StatusOpen) {
+			return store.UpdateIssue(ctx, id, map[string]any{"status": status}, 0, actor)
+		}
+		return nil
+	case types.EventReopened:
+		return store.CloseIssue(ctx, id, deref(e.OldValue), false, actor)
+	case types.EventLabelAdded:
+		return store.RemoveLabelFromIssue(ctx, id, deref(e.NewValue), actor)
+	case types.EventLabelRemoved:
+		return store.AddLabelToIssue(ctx, id, deref(e.OldValue), actor)
+	case types.EventDependencyAdded:
+		issueID, dependsOnID, _, _ := parseDependency(e)
+		return store.RemoveDependency(ctx, issueID, dependsOnID, actor)
+	case types.EventDependencyRemoved:
+		issueID, dependsOnID, depType, _ := parseDependency(e)
+		return store.AddDependency(ctx, &types.Dependency{IssueID: issueID, DependsOnID: dependsOnID, Type: depType}, actor)
+	}
+	return fmt.Errorf("%s events cannot be undone", e.EventType)
+}
+
+// parseDependency reads the dependency named by a dependency event. Events
+// recorded before removals carried the type default to blocks.
+func parseDependency(e *types.Event) (issueID, dependsOnID string, depType types.DependencyType, ok bool) {
+	v := e.NewValue
+	if e.EventType == types.EventDependencyRemoved {
+		v = e.OldValue
+	}
+	if v == nil {
+		return "", "", "", false
+	}
+	m := dependencyValue.FindStringSubmatch(*v)
+	if m == nil {
+		return "", "", "", false
+	}
+	depType = types.DependencyType(m[3])
+	if depType == "" {
+		depType = types.DepBlocks
+	}
+	return m[1], m[2], depType, true
+}
+
+// deref returns the value of s, or "" when it is nil.
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
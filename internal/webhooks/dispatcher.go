@@ -0,0 +1,383 @@
+// Package webhooks delivers change events to per-project HTTP subscribers.
+//
+// The Dispatcher listens on the in-process event bus, turns each event that
+// matches a project's webhooks into a JSON payload (the event plus a
+// snapshot of the affected issue or plan), and queues one delivery per
+// webhook in storage. Queued deliveries survive restarts and are POSTed with
+// an HMAC-SHA256 signature, retrying failures with exponential backoff until
+// they succeed or run out of attempts.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/events"
+	"github.com/sentiolabs/arc/internal/storage"
+	"github.com/sentiolabs/arc/internal/types"
+	"github.com/sentiolabs/arc/internal/version"
+)
+
+// Request headers sent with every delivery.
+const (
+	SignatureHeader = "X-Arc-Signature-256"
+	EventHeader     = "X-Arc-Event"
+	DeliveryHeader  = "X-Arc-Delivery"
+)
+
+// PingEvent is the event type of deliveries sent by Dispatcher.Test.
+const PingEvent types.StreamEventType = "webhook.ping"
+
+// Dispatcher defaults.
+const (
+	DefaultMaxAttempts  = 8
+	DefaultBaseBackoff  = 10 * time.Second
+	DefaultMaxBackoff   = time.Hour
+	DefaultPollInterval = 5 * time.Second
+	DefaultTimeout      = 10 * time.Second
+
+	// deliveryBatch caps how many due deliveries one pass attempts.
+	deliveryBatch = 50
+	// subscriptionBuffer absorbs bursts while events are being queued.
+	subscriptionBuffer = 256
+	// maxResponseDrain bounds how much of a response body is read and discarded.
+	maxResponseDrain = 64 << 10
+)
+
+// Payload is the JSON body POSTed to webhook subscribers.
+type Payload struct {
+	Type      types.StreamEventType `json:"type"`
+	ProjectID string                `json:"project_id"`
+	IssueID   string                `json:"issue_id,omitempty"`
+	PlanID    string                `json:"plan_id,omitempty"`
+	SessionID string                `json:"session_id,omitempty"`
+	Actor     string                `json:"actor,omitempty"`
+	// Data carries the event details, e.g. old_value and new_value.
+	Data      map[string]any `json:"data,omitempty"`
+	Timestamp time.Time      `json:"timestamp"`
+	// Issue is a snapshot of the affected issue after the change.
+	Issue *types.Issue `json:"issue,omitempty"`
+	// Plan is a snapshot of the affected plan after the change.
+	Plan *types.Plan `json:"plan,omitempty"`
+}
+
+// Options configures a Dispatcher. Zero values select the defaults above.
+type Options struct {
+	Client       *http.Client
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	PollInterval time.Duration
+	// Now returns the current time; tests override it to control backoff.
+	Now func() time.Time
+}
+
+// Dispatcher queues and delivers webhook events.
+type Dispatcher struct {
+	store        storage.Storage
+	client       *http.Client
+	maxAttempts  int
+	baseBackoff  time.Duration
+	maxBackoff   time.Duration
+	pollInterval time.Duration
+	now          func() time.Time
+
+	// deliverMu serializes delivery passes so a delivery is never sent twice
+	// concurrently.
+	deliverMu sync.Mutex
+	wake      chan struct{}
+}
+
+// New creates a Dispatcher backed by store.
+func New(store storage.Storage, opts Options) *Dispatcher {
+	d := &Dispatcher{
+		store:        store,
+		client:       opts.Client,
+		maxAttempts:  opts.MaxAttempts,
+		baseBackoff:  opts.BaseBackoff,
+		maxBackoff:   opts.MaxBackoff,
+		pollInterval: opts.PollInterval,
+		now:          opts.Now,
+		wake:         make(chan struct{}, 1),
+	}
+	if d.client == nil {
+		d.client = &http.Client{Timeout: DefaultTimeout}
+	}
+	if d.maxAttempts <= 0 {
+		d.maxAttempts = DefaultMaxAttempts
+	}
+	if d.baseBackoff <= 0 {
+		d.baseBackoff = DefaultBaseBackoff
+	}
+	if d.maxBackoff <= 0 {
+		d.maxBackoff = DefaultMaxBackoff
+	}
+	if d.pollInterval <= 0 {
+		d.pollInterval = DefaultPollInterval
+	}
+	if d.now == nil {
+		d.now = time.Now
+	}
+	return d
+}
+
+// Run queues deliveries for events published on bus and delivers them until
+// ctx is cancelled. Deliveries left pending by a previous run are picked up
+// on the first pass.
+func (d *Dispatcher) Run(ctx context.Context, bus *events.Bus) {
+	go d.deliverLoop(ctx)
+
+	sub := bus.Subscribe(events.Filter{}, subscriptionBuffer)
+	defer func() { sub.Close() }()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-sub.C:
+			if !ok {
+				log.Printf("webhooks: fell behind the event bus; some events were not queued")
+				sub = bus.Subscribe(events.Filter{}, subscriptionBuffer)
+				continue
+			}
+			if err := d.Enqueue(ctx, ev); err != nil {
+				log.Printf("webhooks: queue %s: %v", ev.Type, err)
+			}
+		}
+	}
+}
+
+func (d *Dispatcher) deliverLoop(ctx context.Context) {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := d.DeliverDue(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("webhooks: deliver: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// Enqueue queues one delivery of ev for every active webhook in the event's
+// project whose event filter matches. Plan events are attributed to the
+// project whose workspace contains the plan file; events that cannot be
+// attributed to a project are ignored.
+func (d *Dispatcher) Enqueue(ctx context.Context, ev types.StreamEvent) error {
+	payload := Payload{
+		Type:      ev.Type,
+		ProjectID: ev.ProjectID,
+		IssueID:   ev.IssueID,
+		PlanID:    ev.PlanID,
+		SessionID: ev.SessionID,
+		Actor:     ev.Actor,
+		Data:      ev.Data,
+		Timestamp: ev.Timestamp,
+	}
+	if ev.PlanID != "" {
+		if plan, err := d.store.GetPlan(ctx, ev.PlanID); err == nil {
+			payload.Plan = plan
+			if payload.ProjectID == "" {
+				if ws, err := d.store.ResolveProjectByPath(ctx, plan.FilePath); err == nil {
+					payload.ProjectID = ws.ProjectID
+				}
+			}
+		}
+	}
+	if payload.ProjectID == "" {
+		return nil
+	}
+
+	hooks, err := d.matchingWebhooks(ctx, payload.ProjectID, ev)
+	if err != nil || len(hooks) == 0 {
+		return err
+	}
+
+	if ev.IssueID != "" {
+		// A missing issue (e.g. issue.deleted) simply leaves the snapshot out.
+		if issue, err := d.store.GetIssue(ctx, ev.IssueID); err == nil {
+			payload.Issue = issue
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode payload: %w", err)
+	}
+
+	now := d.now()
+	for _, wh := range hooks {
+		delivery := &types.WebhookDelivery{
+			WebhookID: wh.ID, EventType: ev.Type, Payload: string(body), NextAttemptAt: now,
+		}
+		if err := d.store.CreateWebhookDelivery(ctx, delivery); err != nil {
+			return err
+		}
+	}
+	d.notify()
+	return nil
+}
+
+func (d *Dispatcher) matchingWebhooks(
+	ctx context.Context, projectID string, ev types.StreamEvent,
+) ([]*types.Webhook, error) {
+	hooks, err := d.store.ListWebhooks(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	var matched []*types.Webhook
+	for _, wh := range hooks {
+		if wh.Active && (events.Filter{Types: wh.EventTypes}).Match(ev) {
+			matched = append(matched, wh)
+		}
+	}
+	return matched, nil
+}
+
+// notify wakes the delivery loop without blocking.
+func (d *Dispatcher) notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// DeliverDue attempts every pending delivery whose next attempt is due and
+// returns how many were attempted.
+func (d *Dispatcher) DeliverDue(ctx context.Context) (int, error) {
+	d.deliverMu.Lock()
+	defer d.deliverMu.Unlock()
+
+	due, err := d.store.ListDueWebhookDeliveries(ctx, d.now(), deliveryBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	hooks := make(map[string]*types.Webhook)
+	for i, delivery := range due {
+		wh, ok := hooks[delivery.WebhookID]
+		if !ok {
+			if wh, err = d.store.GetWebhook(ctx, delivery.WebhookID); err != nil {
+				return i, err
+			}
+			hooks[delivery.WebhookID] = wh
+		}
+		if err := d.attempt(ctx, wh, delivery, d.maxAttempts); err != nil {
+			return i, err
+		}
+	}
+	return len(due), nil
+}
+
+// Test sends a webhook.ping delivery immediately and returns its outcome.
+// Test deliveries are logged like any other but are not retried.
+func (d *Dispatcher) Test(ctx context.Context, webhookID string) (*types.WebhookDelivery, error) {
+	wh, err := d.store.GetWebhook(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(Payload{
+		Type:      PingEvent,
+		ProjectID: wh.ProjectID,
+		Data:      map[string]any{"webhook_id": wh.ID},
+		Timestamp: d.now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encode payload: %w", err)
+	}
+
+	d.deliverMu.Lock()
+	defer d.deliverMu.Unlock()
+
+	delivery := &types.WebhookDelivery{
+		WebhookID: wh.ID, EventType: PingEvent, Payload: string(body), NextAttemptAt: d.now(),
+	}
+	if err := d.store.CreateWebhookDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+	if err := d.attempt(ctx, wh, delivery, 1); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// attempt POSTs one delivery and records the outcome. Failures are
+// rescheduled with backoff until maxAttempts is reached, then marked failed.
+func (d *Dispatcher) attempt(
+	ctx context.Context, wh *types.Webhook, delivery *types.WebhookDelivery, maxAttempts int,
+) error {
+	status, sendErr := d.send(ctx, wh, delivery)
+
+	now := d.now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.ResponseStatus = nil
+	if status != 0 {
+		delivery.ResponseStatus = &status
+	}
+
+	if sendErr == nil {
+		delivery.Status = types.DeliverySucceeded
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+	} else {
+		delivery.LastError = sendErr.Error()
+		if !wh.Active || delivery.Attempts >= maxAttempts {
+			delivery.Status = types.DeliveryFailed
+		} else {
+			delivery.NextAttemptAt = now.Add(d.backoff(delivery.Attempts))
+		}
+	}
+	return d.store.UpdateWebhookDelivery(ctx, delivery)
+}
+
+// send performs the HTTP POST and returns the response status (0 if no
+// response was received) and an error for anything other than a 2xx.
+func (d *Dispatcher) send(ctx context.Context, wh *types.Webhook, delivery *types.WebhookDelivery) (int, error) {
+	if !wh.Active {
+		return 0, fmt.Errorf("webhook %s is inactive", wh.ID)
+	}
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wh.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "arc-webhooks/"+version.Short())
+	req.Header.Set(EventHeader, string(delivery.EventType))
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(SignatureHeader, Sign(wh.Secret, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseDrain))
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff returns the delay before the next attempt after the given number
+// of failed attempts: BaseBackoff doubled per attempt, capped at MaxBackoff.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.baseBackoff
+	for i := 1; i < attempts && delay < d.maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, d.maxBackoff)
+}
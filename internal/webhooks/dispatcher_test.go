@@ -0,0 +1,291 @@
+package webhooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sentiolabs/arc/internal/events"
+	"github.com/sentiolabs/arc/internal/storage/sqlite"
+	"github.com/sentiolabs/arc/internal/types"
+	"github.com/sentiolabs/arc/internal/webhooks"
+)
+
+const testSecret = "s3cret"
+
+// receiver is an httptest server that records webhook requests and answers
+// with the next queued status code (200 once the queue is empty).
+type receiver struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	requests []receivedRequest
+}
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func newReceiver(t *testing.T, statuses ...int) *receiver {
+	t.Helper()
+	r := &receiver{statuses: statuses}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		r.requests = append(r.requests, receivedRequest{header: req.Header.Clone(), body: body})
+		status := http.StatusOK
+		if len(r.statuses) > 0 {
+			status, r.statuses = r.statuses[0], r.statuses[1:]
+		}
+		r.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *receiver) received() []receivedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]receivedRequest(nil), r.requests...)
+}
+
+// fakeClock is a manually advanced clock.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func setup(t *testing.T) (*sqlite.Store, *types.Project) {
+	t.Helper()
+	store, err := sqlite.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	proj := &types.Project{Name: "Webhooks", Prefix: "wh"}
+	if err := store.CreateProject(context.Background(), proj); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+	return store, proj
+}
+
+func createWebhook(t *testing.T, store *sqlite.Store, projectID, url string, eventTypes ...string) *types.Webhook {
+	t.Helper()
+	wh := &types.Webhook{ProjectID: projectID, URL: url, Secret: testSecret, EventTypes: eventTypes, Active: true}
+	if err := store.CreateWebhook(context.Background(), wh); err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+	return wh
+}
+
+func TestEnqueueAndDeliverSignedPayload(t *testing.T) {
+	store, proj := setup(t)
+	ctx := context.Background()
+	recv := newReceiver(t)
+	wh := createWebhook(t, store, proj.ID, recv.URL, "issue.status_changed")
+
+	issue := &types.Issue{ProjectID: proj.ID, Title: "Ship it", Priority: 2}
+	if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+
+	d := webhooks.New(store, webhooks.Options{})
+	// Not subscribed: issue.created must not be queued.
+	if err := d.Enqueue(ctx, types.StreamEvent{Type: types.StreamIssueCreated, ProjectID: proj.ID, IssueID: issue.ID}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	ev := types.StreamEvent{
+		Type:      types.StreamIssueStatusChanged,
+		ProjectID: proj.ID,
+		IssueID:   issue.ID,
+		Actor:     "alice",
+		Data:      map[string]any{"old_value": "open", "new_value": "in_progress"},
+		Timestamp: time.Now(),
+	}
+	if err := d.Enqueue(ctx, ev); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	n, err := d.DeliverDue(ctx)
+	if err != nil || n != 1 {
+		t.Fatalf("DeliverDue = %d, %v; want 1 delivery", n, err)
+	}
+
+	reqs := recv.received()
+	if len(reqs) != 1 {
+		t.Fatalf("receiver got %d requests, want 1", len(reqs))
+	}
+	req := reqs[0]
+	if !webhooks.Verify(testSecret, req.body, req.header.Get(webhooks.SignatureHeader)) {
+		t.Errorf("signature %q does not verify", req.header.Get(webhooks.SignatureHeader))
+	}
+	if got := req.header.Get(webhooks.EventHeader); got != string(types.StreamIssueStatusChanged) {
+		t.Errorf("%s = %q", webhooks.EventHeader, got)
+	}
+
+	var payload webhooks.Payload
+	if err := json.Unmarshal(req.body, &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload.Issue == nil || payload.Issue.Title != "Ship it" {
+		t.Errorf("payload issue snapshot = %+v", payload.Issue)
+	}
+	if payload.Actor != "alice" || payload.Data["new_value"] != "in_progress" {
+		t.Errorf("payload = %+v", payload)
+	}
+
+	log, err := store.ListWebhookDeliveries(ctx, wh.ID, 10)
+	if err != nil {
+		t.Fatalf("ListWebhookDeliveries: %v", err)
+	}
+	if len(log) != 1 || log[0].Status != types.DeliverySucceeded || log[0].DeliveredAt == nil {
+		t.Fatalf("delivery log = %+v", log)
+	}
+}
+
+func TestDeliveryRetriesWithBackoff(t *testing.T) {
+	store, proj := setup(t)
+	ctx := context.Background()
+	recv := newReceiver(t, http.StatusInternalServerError, http.StatusBadGateway)
+	wh := createWebhook(t, store, proj.ID, recv.URL)
+
+	clock := &fakeClock{now: time.Now()}
+	d := webhooks.New(store, webhooks.Options{BaseBackoff: time.Minute, Now: clock.Now})
+	if err := d.Enqueue(ctx, types.StreamEvent{Type: types.StreamIssueUpdated, ProjectID: proj.ID}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	steps := []struct {
+		advance   time.Duration
+		attempted int
+		status    string
+	}{
+		{0, 1, types.DeliveryPending},                // 500, retry in 1m
+		{30 * time.Second, 0, types.DeliveryPending}, // not due yet
+		{30 * time.Second, 1, types.DeliveryPending}, // 502, retry in 2m
+		{time.Minute, 0, types.DeliveryPending},      // not due yet
+		{time.Minute, 1, types.DeliverySucceeded},    // 200
+	}
+	for i, step := range steps {
+		clock.Advance(step.advance)
+		n, err := d.DeliverDue(ctx)
+		if err != nil {
+			t.Fatalf("step %d: DeliverDue: %v", i, err)
+		}
+		if n != step.attempted {
+			t.Errorf("step %d: attempted %d, want %d", i, n, step.attempted)
+		}
+		log, err := store.ListWebhookDeliveries(ctx, wh.ID, 1)
+		if err != nil {
+			t.Fatalf("ListWebhookDeliveries: %v", err)
+		}
+		if log[0].Status != step.status {
+			t.Errorf("step %d: status %q, want %q", i, log[0].Status, step.status)
+		}
+	}
+
+	log, _ := store.ListWebhookDeliveries(ctx, wh.ID, 1)
+	if log[0].Attempts != 3 || *log[0].ResponseStatus != http.StatusOK || log[0].LastError != "" {
+		t.Errorf("final delivery = %+v", log[0])
+	}
+}
+
+func TestDeliveryGivesUpAfterMaxAttempts(t *testing.T) {
+	store, proj := setup(t)
+	ctx := context.Background()
+	recv := newReceiver(t, http.StatusInternalServerError, http.StatusInternalServerError)
+	wh := createWebhook(t, store, proj.ID, recv.URL)
+
+	clock := &fakeClock{now: time.Now()}
+	d := webhooks.New(store, webhooks.Options{MaxAttempts: 2, BaseBackoff: time.Second, Now: clock.Now})
+	if err := d.Enqueue(ctx, types.StreamEvent{Type: types.StreamIssueUpdated, ProjectID: proj.ID}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	for range 3 {
+		if _, err := d.DeliverDue(ctx); err != nil {
+			t.Fatalf("DeliverDue: %v", err)
+		}
+		clock.Advance(time.Hour)
+	}
+
+	log, _ := store.ListWebhookDeliveries(ctx, wh.ID, 1)
+	if log[0].Status != types.DeliveryFailed || log[0].Attempts != 2 {
+		t.Errorf("delivery = %+v, want failed after 2 attempts", log[0])
+	}
+	if got := len(recv.received()); got != 2 {
+		t.Errorf("receiver got %d requests, want 2", got)
+	}
+}
+
+func TestTestSendsPing(t *testing.T) {
+	store, proj := setup(t)
+	recv := newReceiver(t, http.StatusNotFound)
+	wh := createWebhook(t, store, proj.ID, recv.URL)
+
+	d := webhooks.New(store, webhooks.Options{})
+	delivery, err := d.Test(context.Background(), wh.ID)
+	if err != nil {
+		t.Fatalf("Test: %v", err)
+	}
+	// Pings are not retried.
+	if delivery.Status != types.DeliveryFailed || *delivery.ResponseStatus != http.StatusNotFound {
+		t.Errorf("delivery = %+v", delivery)
+	}
+	reqs := recv.received()
+	if len(reqs) != 1 || reqs[0].header.Get(webhooks.EventHeader) != string(webhooks.PingEvent) {
+		t.Errorf("receiver requests = %+v", reqs)
+	}
+}
+
+func TestRunDeliversBusEvents(t *testing.T) {
+	store, proj := setup(t)
+	recv := newReceiver(t)
+	createWebhook(t, store, proj.ID, recv.URL, "issue.*")
+
+	bus := events.NewBus()
+	store.SetPublisher(bus)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go webhooks.New(store, webhooks.Options{}).Run(ctx, bus)
+
+	// Wait for the dispatcher to subscribe before publishing.
+	deadline := time.Now().Add(5 * time.Second)
+	for bus.Subscribers() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	issue := &types.Issue{ProjectID: proj.ID, Title: "From the bus", Priority: 2}
+	if err := store.CreateIssue(context.Background(), issue, "bob"); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+
+	for len(recv.received()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	reqs := recv.received()
+	if len(reqs) != 1 || reqs[0].header.Get(webhooks.EventHeader) != string(types.StreamIssueCreated) {
+		t.Fatalf("receiver requests = %d, want one issue.created", len(reqs))
+	}
+}
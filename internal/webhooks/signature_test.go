@@ -0,0 +1,41 @@
+package webhooks_test
+
+import (
+	"testing"
+
+	"github.com/sentiolabs/arc/internal/webhooks"
+)
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"type":"issue.created"}`)
+	sig := webhooks.Sign("key", body)
+
+	// Known HMAC-SHA256("key", body) so receivers in other languages can
+	// check their implementation against it.
+	const want = "sha256=cd060fd28659c517b17c1abb096d2cdbf0270f203bacea1c9e61bb78f41c2fc4"
+	if sig != want {
+		t.Fatalf("Sign = %q, want %q", sig, want)
+	}
+
+	tests := []struct {
+		name   string
+		secret string
+		body   []byte
+		sig    string
+		ok     bool
+	}{
+		{"valid", "key", body, sig, true},
+		{"wrong secret", "other", body, sig, false},
+		{"tampered body", "key", []byte(`{"type":"issue.deleted"}`), sig, false},
+		{"missing prefix", "key", body, sig[len("sha256="):], false},
+		{"not hex", "key", body, "sha256=zz", false},
+		{"empty", "key", body, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := webhooks.Verify(tt.secret, tt.body, tt.sig); got != tt.ok {
+				t.Errorf("Verify = %v, want %v", got, tt.ok)
+			}
+		})
+	}
+}